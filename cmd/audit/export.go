@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/tools"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	userID := fs.String("user", "", "Export events performed by or about this user id")
+	groupID := fs.String("group", "", "Export events of this group id")
+	from := fs.String("from", "", "Inclusive window start (RFC3339); defaults to the retention horizon")
+	to := fs.String("to", "", "Exclusive window end (RFC3339); defaults to now")
+	format := fs.String("format", audit.FormatNDJSON, "Output format: ndjson or csv")
+	redact := fs.Bool("redact", true, "Withhold personal data of anyone but -user")
+	out := fs.String("out", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tools.LoadEnv()
+	opts, err := exportOptions(*userID, *groupID, *from, *to, *format, *redact, retentionFromEnv())
+	if err != nil {
+		return err
+	}
+
+	pool, err := pgxpool.New(ctx, tools.GetEnv("DB_URL"))
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	buf := bufio.NewWriter(w)
+
+	n, err := audit.Export(ctx, pool, buf, opts)
+	if err != nil {
+		return fmt.Errorf("after %d events: %w", n, err)
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d events\n", n)
+	return nil
+}
+
+// exportOptions applies the same defaults and guards as the HTTP endpoint.
+func exportOptions(userID, groupID, from, to, format string, redact bool, retention time.Duration) (audit.ExportOptions, error) {
+	opts := audit.ExportOptions{
+		SubjectID: userID,
+		GroupID:   groupID,
+		Format:    format,
+		Redact:    redact,
+		To:        time.Now().UTC(),
+	}
+	if format != audit.FormatNDJSON && format != audit.FormatCSV {
+		return opts, fmt.Errorf("unknown format %q (use ndjson or csv)", format)
+	}
+	if to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return opts, fmt.Errorf("-to: %w", err)
+		}
+		opts.To = t
+	}
+	opts.From = opts.To.Add(-retention)
+	if from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return opts, fmt.Errorf("-from: %w", err)
+		}
+		opts.From = t
+	} else if userID == "" && groupID == "" {
+		return opts, errors.New("one of -user, -group or -from is required")
+	}
+	if !opts.From.Before(opts.To) {
+		return opts, errors.New("-from must be before -to")
+	}
+	return opts, nil
+}
+
+func retentionFromEnv() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("AUDIT_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = audit.DefaultRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
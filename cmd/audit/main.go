@@ -0,0 +1,51 @@
+// Command audit runs operator tasks against the audit trail.
+//
+//	audit export -user <id> [-format ndjson|csv] [-from RFC3339] [-to RFC3339] [-out file]
+//	audit export -group <id> ...
+//	audit export -from 2026-01-01T00:00:00Z -to 2026-02-01T00:00:00Z ...
+//	audit verify
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{name: "export", summary: "stream events for a user, a group or a time window as NDJSON or CSV", run: runExport},
+	{name: "verify", summary: "walk the hash chain and report the first broken link", run: runVerify},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(context.Background(), os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "audit %s: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for _, c := range commands {
+		names = append(names, fmt.Sprintf("  %-8s %s", c.name, c.summary))
+	}
+	fmt.Fprintf(os.Stderr, "usage: audit <command> [flags]\n\ncommands:\n%s\n", strings.Join(names, "\n"))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/tools"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func runVerify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tools.LoadEnv()
+	pool, err := pgxpool.New(ctx, tools.GetEnv("DB_URL"))
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	report, err := audit.VerifyChain(ctx, pool)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if report.FirstBreak != nil {
+		return errors.New("hash chain is broken")
+	}
+	return nil
+}
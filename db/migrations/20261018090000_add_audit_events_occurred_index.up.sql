@@ -0,0 +1,4 @@
+-- Unfiltered admin listing walks (occurred_at, id) newest first; the keyset
+-- cursor compares the same pair.
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred
+    ON audit_events (occurred_at DESC, id DESC);
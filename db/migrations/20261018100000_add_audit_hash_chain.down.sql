@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS audit_chain_heads;
+DROP INDEX IF EXISTS idx_audit_events_chain;
+ALTER TABLE audit_events
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS chain_seq;
@@ -0,0 +1,28 @@
+-- Tamper-evident hash chain. Every event stores the hash of its predecessor in
+-- the same monthly partition (prev_hash) and its own hash over prev_hash plus
+-- its contents, numbered by chain_seq. Rows written before this migration keep
+-- NULL chain columns and are outside the chain.
+ALTER TABLE audit_events
+    ADD COLUMN IF NOT EXISTS chain_seq BIGINT,
+    ADD COLUMN IF NOT EXISTS prev_hash BYTEA,
+    ADD COLUMN IF NOT EXISTS hash      BYTEA;
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_chain
+    ON audit_events (chain_seq);
+
+-- One row per partition holding its chain head. Recording locks the row FOR
+-- UPDATE, which serialises appends per partition. The first event of a month
+-- seals the previous month (sealed_seq/sealed_hash) and chains to that head
+-- (genesis_hash), carrying the chain across partition rollover. Rows outlive
+-- their partition (dropped_at) so the next partition's genesis stays checkable.
+CREATE TABLE IF NOT EXISTS audit_chain_heads (
+    partition_name TEXT PRIMARY KEY,
+    genesis_hash   BYTEA,
+    chained_since  TIMESTAMP WITH TIME ZONE,
+    head_seq       BIGINT NOT NULL DEFAULT 0,
+    head_hash      BYTEA,
+    sealed_seq     BIGINT,
+    sealed_hash    BYTEA,
+    dropped_at     TIMESTAMP WITH TIME ZONE,
+    updated_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_videos_mux_asset_id;
+DROP INDEX IF EXISTS idx_videos_mux_upload_id;
+
+ALTER TABLE videos
+    DROP COLUMN IF EXISTS mux_error;
@@ -0,0 +1,10 @@
+-- Mux webhooks report asset failures; keep the reason next to the failed
+-- status so the client can show it instead of a spinner that never resolves.
+ALTER TABLE videos
+    ADD COLUMN IF NOT EXISTS mux_error TEXT;
+
+-- Webhook events are keyed by the Mux upload or asset id, not our own id.
+CREATE INDEX IF NOT EXISTS idx_videos_mux_upload_id
+    ON videos (mux_upload_id) WHERE mux_upload_id <> '';
+CREATE INDEX IF NOT EXISTS idx_videos_mux_asset_id
+    ON videos (mux_asset_id) WHERE mux_asset_id <> '';
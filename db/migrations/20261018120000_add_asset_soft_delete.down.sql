@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS moderation_reports_target_video_id_idx;
+DROP INDEX IF EXISTS idx_notifications_asset_id;
+DROP INDEX IF EXISTS idx_videos_deleted_at;
+DROP INDEX IF EXISTS idx_assets_deleted_at;
+
+ALTER TABLE videos
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE assets
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,22 @@
+-- Deleting an asset or a single video hides it immediately and keeps the row
+-- for a grace window; the purge job then removes the Mux asset, the rows
+-- (reviews cascade), deep-linking notifications and moderation evidence.
+ALTER TABLE assets
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+
+ALTER TABLE videos
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_assets_deleted_at
+    ON assets (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_videos_deleted_at
+    ON videos (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Notifications deep-link to assets through their JSON payload.
+CREATE INDEX IF NOT EXISTS idx_notifications_asset_id
+    ON notifications ((payload->>'asset_id'));
+
+CREATE INDEX IF NOT EXISTS moderation_reports_target_video_id_idx
+    ON moderation_reports (target_video_id);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_assets_search;
+DROP INDEX IF EXISTS idx_assets_owner_created;
+DROP INDEX IF EXISTS idx_assets_group_review_activity;
+DROP INDEX IF EXISTS idx_assets_group_updated;
+DROP INDEX IF EXISTS idx_assets_group_created;
+
+DROP TRIGGER IF EXISTS videos_refresh_asset ON videos;
+DROP FUNCTION IF EXISTS videos_refresh_asset();
+DROP TRIGGER IF EXISTS video_reviews_refresh_asset ON video_reviews;
+DROP FUNCTION IF EXISTS video_reviews_refresh_asset();
+DROP FUNCTION IF EXISTS refresh_asset_last_review_at(UUID);
+
+ALTER TABLE assets DROP COLUMN IF EXISTS last_review_at;
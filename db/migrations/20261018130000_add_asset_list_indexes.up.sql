@@ -0,0 +1,71 @@
+-- Keeps the time of the newest review on a live video on the asset itself, so
+-- the video list can filter by review state and sort by review activity from
+-- an index instead of aggregating reviews per row.
+ALTER TABLE assets ADD COLUMN IF NOT EXISTS last_review_at TIMESTAMP WITH TIME ZONE;
+
+CREATE FUNCTION refresh_asset_last_review_at(target UUID) RETURNS void AS $$
+BEGIN
+    UPDATE assets a
+    SET last_review_at = (
+        SELECT MAX(COALESCE(r.updated_at, r.created_at))
+        FROM videos v
+        JOIN video_reviews r ON r.video_id = v.id
+        WHERE v.asset_id = a.id AND v.deleted_at IS NULL
+    )
+    WHERE a.id = target;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION video_reviews_refresh_asset() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_asset_last_review_at((SELECT asset_id FROM videos WHERE id = OLD.video_id));
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM refresh_asset_last_review_at((SELECT asset_id FROM videos WHERE id = NEW.video_id));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER video_reviews_refresh_asset
+    AFTER INSERT OR UPDATE OR DELETE ON video_reviews
+    FOR EACH ROW EXECUTE FUNCTION video_reviews_refresh_asset();
+
+-- Deleting a single video takes its reviews out of the asset's activity.
+CREATE FUNCTION videos_refresh_asset() RETURNS trigger AS $$
+BEGIN
+    PERFORM refresh_asset_last_review_at(NEW.asset_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER videos_refresh_asset
+    AFTER UPDATE OF deleted_at ON videos
+    FOR EACH ROW
+    WHEN (OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+    EXECUTE FUNCTION videos_refresh_asset();
+
+UPDATE assets a
+SET last_review_at = (
+    SELECT MAX(COALESCE(r.updated_at, r.created_at))
+    FROM videos v
+    JOIN video_reviews r ON r.video_id = v.id
+    WHERE v.asset_id = a.id AND v.deleted_at IS NULL
+);
+
+-- Keyset pagination: experts page through their groups, students through
+-- their own uploads. Each sort order has its own index.
+CREATE INDEX IF NOT EXISTS idx_assets_group_created
+    ON assets (group_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_assets_group_updated
+    ON assets (group_id, updated_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_assets_group_review_activity
+    ON assets (group_id, (COALESCE(last_review_at, created_at)) DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_assets_owner_created
+    ON assets (owner_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- Full-text search over title and description. The 'simple' configuration
+-- because titles are written in any of the supported languages.
+CREATE INDEX IF NOT EXISTS idx_assets_search
+    ON assets USING GIN (to_tsvector('simple', name || ' ' || description)) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE video_reviews DROP CONSTRAINT IF EXISTS video_reviews_annotation_needs_timestamp;
+
+ALTER TABLE video_reviews
+    DROP COLUMN IF EXISTS annotation_version,
+    DROP COLUMN IF EXISTS annotation;
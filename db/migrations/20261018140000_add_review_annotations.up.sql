@@ -0,0 +1,11 @@
+-- Optional vector layer (paths, arrows, circles, angles) drawn over the frame
+-- for a time range around the review's timestamp. The handler validates the
+-- shape; annotation_version increments on every change so concurrent editors
+-- cannot silently overwrite each other.
+ALTER TABLE video_reviews
+    ADD COLUMN IF NOT EXISTS annotation JSONB,
+    ADD COLUMN IF NOT EXISTS annotation_version INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE video_reviews
+    ADD CONSTRAINT video_reviews_annotation_needs_timestamp
+    CHECK (annotation IS NULL OR timestamp_seconds IS NOT NULL);
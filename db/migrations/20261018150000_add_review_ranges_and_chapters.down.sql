@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS video_chapters;
+
+ALTER TABLE video_reviews
+    DROP CONSTRAINT IF EXISTS video_reviews_range_valid;
+
+ALTER TABLE video_reviews
+    DROP COLUMN IF EXISTS end_seconds;
@@ -0,0 +1,24 @@
+-- A timestamped review may cover a range: timestamp_seconds is the start and
+-- end_seconds the end, e.g. a whole rep at 0:42–0:55.
+ALTER TABLE video_reviews
+    ADD COLUMN IF NOT EXISTS end_seconds INTEGER;
+
+ALTER TABLE video_reviews
+    ADD CONSTRAINT video_reviews_range_valid
+    CHECK (end_seconds IS NULL OR (timestamp_seconds IS NOT NULL AND end_seconds > timestamp_seconds));
+
+-- Named sections experts use to segment a video. end_seconds is optional: an
+-- open chapter runs until the next one starts.
+CREATE TABLE video_chapters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    start_seconds INTEGER NOT NULL CHECK (start_seconds >= 0),
+    end_seconds INTEGER,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT video_chapters_range_valid CHECK (end_seconds IS NULL OR end_seconds > start_seconds)
+);
+
+CREATE INDEX idx_video_chapters_video_start ON video_chapters(video_id, start_seconds);
@@ -0,0 +1,4 @@
+ALTER TABLE video_reviews
+    DROP COLUMN IF EXISTS snippet_id;
+
+DROP TABLE IF EXISTS review_snippets;
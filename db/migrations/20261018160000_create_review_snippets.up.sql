@@ -0,0 +1,22 @@
+-- Reusable review comments. A snippet without group_id is private to its
+-- owner; with group_id every member of that group can use it.
+CREATE TABLE review_snippets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id TEXT NOT NULL,
+    group_id UUID REFERENCES groups(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_review_snippets_owner ON review_snippets(owner_id);
+CREATE INDEX idx_review_snippets_group ON review_snippets(group_id) WHERE group_id IS NOT NULL;
+CREATE INDEX idx_review_snippets_tags ON review_snippets USING GIN (tags);
+
+-- Which snippet a review was written from; kept for usage statistics only.
+ALTER TABLE video_reviews
+    ADD COLUMN IF NOT EXISTS snippet_id UUID REFERENCES review_snippets(id) ON DELETE SET NULL;
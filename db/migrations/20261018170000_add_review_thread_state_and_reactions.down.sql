@@ -0,0 +1,33 @@
+DELETE FROM notifications WHERE type IN ('review_thread_updated', 'review_reaction_added');
+
+ALTER TYPE notification_type RENAME TO notification_type_old;
+CREATE TYPE notification_type AS ENUM (
+    'group_invitation_received',
+    'group_member_joined',
+    'video_reviewed',
+    'video_uploaded',
+    'coaching_booking_created',
+    'coaching_booking_cancelled'
+);
+ALTER TABLE notifications
+    ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+DROP TYPE notification_type_old;
+
+DROP TRIGGER IF EXISTS videos_refresh_threads ON videos;
+DROP FUNCTION IF EXISTS videos_refresh_threads();
+DROP TRIGGER IF EXISTS video_reviews_refresh_threads ON video_reviews;
+DROP FUNCTION IF EXISTS video_reviews_refresh_threads();
+DROP FUNCTION IF EXISTS refresh_asset_review_threads(UUID);
+
+ALTER TABLE assets
+    DROP COLUMN IF EXISTS open_review_threads,
+    DROP COLUMN IF EXISTS review_threads;
+
+DROP TABLE IF EXISTS review_reactions;
+
+ALTER TABLE video_reviews
+    DROP COLUMN IF EXISTS thread_state_changed_at,
+    DROP COLUMN IF EXISTS thread_state_changed_by,
+    DROP COLUMN IF EXISTS thread_state;
+
+DROP TYPE IF EXISTS review_thread_state;
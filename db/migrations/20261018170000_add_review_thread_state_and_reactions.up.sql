@@ -0,0 +1,92 @@
+-- Top-level reviews are threads the student can acknowledge and the expert
+-- can resolve. Replies inherit nothing; their thread_state stays 'open'.
+CREATE TYPE review_thread_state AS ENUM ('open', 'acknowledged', 'resolved');
+
+ALTER TABLE video_reviews
+    ADD COLUMN IF NOT EXISTS thread_state review_thread_state NOT NULL DEFAULT 'open',
+    ADD COLUMN IF NOT EXISTS thread_state_changed_by TEXT,
+    ADD COLUMN IF NOT EXISTS thread_state_changed_at TIMESTAMP WITH TIME ZONE;
+
+-- Videos already marked reviewed predate thread state; their threads count as
+-- resolved so they stay under Reviewed.
+UPDATE video_reviews r
+SET thread_state = 'resolved', thread_state_changed_at = NOW()
+FROM videos v
+JOIN assets a ON a.id = v.asset_id
+WHERE r.video_id = v.id AND r.parent_id IS NULL AND a.status = 'completed';
+
+CREATE TABLE review_reactions (
+    review_id UUID NOT NULL REFERENCES video_reviews(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    reaction TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id, reaction)
+);
+
+-- Thread counts on the asset feed the progress summary and the review_state
+-- filter of the video list, the same way last_review_at does.
+ALTER TABLE assets
+    ADD COLUMN IF NOT EXISTS review_threads INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS open_review_threads INTEGER NOT NULL DEFAULT 0;
+
+CREATE FUNCTION refresh_asset_review_threads(target UUID) RETURNS void AS $$
+BEGIN
+    UPDATE assets a
+    SET review_threads = t.total,
+        open_review_threads = t.open
+    FROM (
+        SELECT COUNT(r.id)::int AS total,
+               (COUNT(r.id) FILTER (WHERE r.thread_state = 'open'))::int AS open
+        FROM videos v
+        JOIN video_reviews r ON r.video_id = v.id AND r.parent_id IS NULL
+        WHERE v.asset_id = target AND v.deleted_at IS NULL
+    ) t
+    WHERE a.id = target;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION video_reviews_refresh_threads() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_asset_review_threads((SELECT asset_id FROM videos WHERE id = OLD.video_id));
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM refresh_asset_review_threads((SELECT asset_id FROM videos WHERE id = NEW.video_id));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER video_reviews_refresh_threads
+    AFTER INSERT OR DELETE OR UPDATE OF thread_state, parent_id, video_id ON video_reviews
+    FOR EACH ROW EXECUTE FUNCTION video_reviews_refresh_threads();
+
+CREATE FUNCTION videos_refresh_threads() RETURNS trigger AS $$
+BEGIN
+    PERFORM refresh_asset_review_threads(NEW.asset_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER videos_refresh_threads
+    AFTER UPDATE OF deleted_at ON videos
+    FOR EACH ROW
+    WHEN (OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+    EXECUTE FUNCTION videos_refresh_threads();
+
+UPDATE assets a
+SET review_threads = t.total,
+    open_review_threads = t.open
+FROM (
+    SELECT v.asset_id,
+           COUNT(r.id)::int AS total,
+           (COUNT(r.id) FILTER (WHERE r.thread_state = 'open'))::int AS open
+    FROM videos v
+    JOIN video_reviews r ON r.video_id = v.id AND r.parent_id IS NULL
+    WHERE v.deleted_at IS NULL
+    GROUP BY v.asset_id
+) t
+WHERE a.id = t.asset_id;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'review_thread_updated';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'review_reaction_added';
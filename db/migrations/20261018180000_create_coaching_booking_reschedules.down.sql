@@ -0,0 +1,23 @@
+DELETE FROM notifications WHERE type IN (
+    'coaching_booking_reschedule_proposed',
+    'coaching_booking_reschedule_declined',
+    'coaching_booking_rescheduled'
+);
+
+ALTER TYPE notification_type RENAME TO notification_type_old;
+CREATE TYPE notification_type AS ENUM (
+    'group_invitation_received',
+    'group_member_joined',
+    'video_reviewed',
+    'video_uploaded',
+    'coaching_booking_created',
+    'coaching_booking_cancelled',
+    'review_thread_updated',
+    'review_reaction_added'
+);
+ALTER TABLE notifications
+    ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+DROP TYPE notification_type_old;
+
+DROP TABLE IF EXISTS coaching_booking_reschedules;
+DROP TYPE IF EXISTS coaching_reschedule_status;
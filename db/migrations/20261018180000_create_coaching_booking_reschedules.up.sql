@@ -0,0 +1,24 @@
+CREATE TYPE coaching_reschedule_status AS ENUM ('pending', 'accepted', 'declined', 'withdrawn');
+
+-- A proposal to move a booking to a new start time. Either participant may
+-- propose; only the other one may accept. A counter-proposal withdraws the
+-- pending one, so a booking has at most one pending proposal at a time.
+CREATE TABLE coaching_booking_reschedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    proposed_by TEXT NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    previous_scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    status coaching_reschedule_status NOT NULL DEFAULT 'pending',
+    responded_by TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_coaching_booking_reschedules_pending
+    ON coaching_booking_reschedules(booking_id) WHERE status = 'pending';
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_booking_reschedule_proposed';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_booking_reschedule_declined';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_booking_rescheduled';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_coaching_bookings_series;
+ALTER TABLE coaching_bookings DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS coaching_booking_series;
@@ -0,0 +1,27 @@
+-- A weekly or biweekly run of bookings between the same expert and student.
+-- Each occurrence is an ordinary coaching_bookings row pointing back here, so
+-- it can be rescheduled or cancelled on its own.
+CREATE TABLE coaching_booking_series (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id TEXT NOT NULL,
+    student_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    session_type_id UUID NOT NULL REFERENCES coaching_session_types(id),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    timezone TEXT NOT NULL,
+    interval_weeks INTEGER NOT NULL CHECK (interval_weeks IN (1, 2)),
+    occurrence_count INTEGER CHECK (occurrence_count > 0),
+    until_date DATE,
+    duration_minutes INTEGER NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((occurrence_count IS NULL) <> (until_date IS NULL))
+);
+
+CREATE INDEX idx_coaching_booking_series_group ON coaching_booking_series(group_id);
+
+ALTER TABLE coaching_bookings
+    ADD COLUMN series_id UUID REFERENCES coaching_booking_series(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_coaching_bookings_series ON coaching_bookings(series_id, scheduled_at)
+    WHERE series_id IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS coaching_calendar_feeds;
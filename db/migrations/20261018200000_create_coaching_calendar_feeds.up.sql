@@ -0,0 +1,8 @@
+-- Secret per-user iCalendar feed of coaching sessions. Only the SHA-256 of the
+-- token is stored; rotating replaces it and deleting the row revokes the feed.
+CREATE TABLE coaching_calendar_feeds (
+    user_id TEXT PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_accessed_at TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS coaching_external_busy_blocks;
+DROP TABLE IF EXISTS coaching_external_calendars;
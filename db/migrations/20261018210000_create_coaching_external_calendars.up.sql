@@ -0,0 +1,28 @@
+-- External calendars an expert subscribes to (or uploads once) so their busy
+-- time is kept out of bookable slots. source_url is NULL for uploaded files,
+-- which are never refetched.
+CREATE TABLE coaching_external_calendars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id TEXT NOT NULL,
+    label TEXT NOT NULL,
+    source_url TEXT,
+    last_attempted_at TIMESTAMP WITH TIME ZONE,
+    last_synced_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coaching_external_calendars_expert ON coaching_external_calendars(expert_id);
+CREATE INDEX idx_coaching_external_calendars_due ON coaching_external_calendars(last_attempted_at NULLS FIRST)
+    WHERE source_url IS NOT NULL;
+
+-- Cached busy intervals from the last successful fetch. Replaced wholesale on
+-- every sync.
+CREATE TABLE coaching_external_busy_blocks (
+    calendar_id UUID NOT NULL REFERENCES coaching_external_calendars(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_coaching_external_busy_blocks_calendar ON coaching_external_busy_blocks(calendar_id, starts_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS coaching_absences;
+DROP TABLE IF EXISTS coaching_availability_overrides;
@@ -0,0 +1,28 @@
+-- Extra bookable hours on a specific date, on top of the weekly availability
+-- of a group. Blocked slots, absences and bookings still apply to them.
+CREATE TABLE coaching_availability_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    override_date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_availability_override_time_order CHECK (start_time < end_time)
+);
+
+CREATE INDEX idx_coaching_availability_overrides_expert_group ON coaching_availability_overrides(expert_id, group_id, override_date);
+
+-- Days an expert is away (vacation mode). starts_on and ends_on are inclusive
+-- dates in the expert's timezone; no slots are offered in any group.
+CREATE TABLE coaching_absences (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id TEXT NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_absence_date_order CHECK (starts_on <= ends_on)
+);
+
+CREATE INDEX idx_coaching_absences_expert ON coaching_absences(expert_id, ends_on);
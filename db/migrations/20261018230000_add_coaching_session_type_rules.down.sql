@@ -0,0 +1,11 @@
+ALTER TABLE coaching_bookings
+    DROP COLUMN IF EXISTS buffer_after_minutes,
+    DROP COLUMN IF EXISTS buffer_before_minutes;
+
+ALTER TABLE coaching_session_types
+    DROP COLUMN IF EXISTS cancellation_notice_minutes,
+    DROP COLUMN IF EXISTS min_booking_notice_minutes,
+    DROP COLUMN IF EXISTS booking_horizon_days,
+    DROP COLUMN IF EXISTS max_sessions_per_day,
+    DROP COLUMN IF EXISTS buffer_after_minutes,
+    DROP COLUMN IF EXISTS buffer_before_minutes;
@@ -0,0 +1,18 @@
+-- Booking rules per session type. NULL notices fall back to the server-wide
+-- MIN_BOOKING_NOTICE and CANCELLATION_NOTICE; a NULL cap or horizon means no
+-- limit.
+ALTER TABLE coaching_session_types
+    ADD COLUMN buffer_before_minutes INTEGER NOT NULL DEFAULT 0
+        CHECK (buffer_before_minutes >= 0 AND buffer_before_minutes <= 120),
+    ADD COLUMN buffer_after_minutes INTEGER NOT NULL DEFAULT 0
+        CHECK (buffer_after_minutes >= 0 AND buffer_after_minutes <= 120),
+    ADD COLUMN max_sessions_per_day INTEGER CHECK (max_sessions_per_day > 0),
+    ADD COLUMN booking_horizon_days INTEGER CHECK (booking_horizon_days > 0),
+    ADD COLUMN min_booking_notice_minutes INTEGER CHECK (min_booking_notice_minutes >= 0),
+    ADD COLUMN cancellation_notice_minutes INTEGER CHECK (cancellation_notice_minutes >= 0);
+
+-- Bookings keep the buffers they were booked with, like duration_minutes, so
+-- editing a session type does not change the time around existing sessions.
+ALTER TABLE coaching_bookings
+    ADD COLUMN buffer_before_minutes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN buffer_after_minutes INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,24 @@
+DELETE FROM notifications WHERE type = 'coaching_waitlist_slot_offered';
+
+ALTER TYPE notification_type RENAME TO notification_type_old;
+CREATE TYPE notification_type AS ENUM (
+    'group_invitation_received',
+    'group_member_joined',
+    'video_reviewed',
+    'video_uploaded',
+    'coaching_booking_created',
+    'coaching_booking_cancelled',
+    'review_thread_updated',
+    'review_reaction_added',
+    'coaching_booking_reschedule_proposed',
+    'coaching_booking_reschedule_declined',
+    'coaching_booking_rescheduled'
+);
+ALTER TABLE notifications
+    ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+DROP TYPE notification_type_old;
+
+DROP TABLE IF EXISTS coaching_waitlist_holds;
+DROP TYPE IF EXISTS coaching_waitlist_hold_status;
+DROP TABLE IF EXISTS coaching_waitlist_windows;
+DROP TABLE IF EXISTS coaching_waitlist_entries;
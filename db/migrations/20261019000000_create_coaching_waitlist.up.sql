@@ -0,0 +1,53 @@
+-- A student waiting for a slot with an expert. preferred_weekdays (0=Sun … 6=Sat)
+-- and the windows in coaching_waitlist_windows are in the student's timezone;
+-- none of either accepts any time. booked_at is set once an offered slot was
+-- confirmed as a booking, which takes the entry off the waitlist.
+CREATE TABLE coaching_waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id TEXT NOT NULL,
+    expert_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    session_type_id UUID NOT NULL REFERENCES coaching_session_types(id) ON DELETE CASCADE,
+    preferred_weekdays SMALLINT[] NOT NULL DEFAULT '{}',
+    booked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_waitlist_weekdays CHECK (preferred_weekdays <@ ARRAY[0, 1, 2, 3, 4, 5, 6]::SMALLINT[])
+);
+
+CREATE UNIQUE INDEX idx_coaching_waitlist_entries_waiting
+    ON coaching_waitlist_entries(student_id, session_type_id) WHERE booked_at IS NULL;
+CREATE INDEX idx_coaching_waitlist_entries_expert
+    ON coaching_waitlist_entries(expert_id, created_at) WHERE booked_at IS NULL;
+
+CREATE TABLE coaching_waitlist_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL REFERENCES coaching_waitlist_entries(id) ON DELETE CASCADE,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    CONSTRAINT chk_waitlist_window_time_order CHECK (start_time < end_time)
+);
+
+CREATE INDEX idx_coaching_waitlist_windows_entry ON coaching_waitlist_windows(entry_id);
+
+CREATE TYPE coaching_waitlist_hold_status AS ENUM ('offered', 'confirmed', 'declined', 'expired');
+
+-- A freed slot held for one waitlisted student until expires_at. While it is
+-- offered and unexpired nobody else can book the time.
+CREATE TABLE coaching_waitlist_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL REFERENCES coaching_waitlist_entries(id) ON DELETE CASCADE,
+    expert_id TEXT NOT NULL,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status coaching_waitlist_hold_status NOT NULL DEFAULT 'offered',
+    booking_id UUID REFERENCES coaching_bookings(id) ON DELETE SET NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coaching_waitlist_holds_entry ON coaching_waitlist_holds(entry_id, created_at);
+CREATE INDEX idx_coaching_waitlist_holds_offered
+    ON coaching_waitlist_holds(expert_id, scheduled_at) WHERE status = 'offered';
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_waitlist_slot_offered';
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS coaching_payments;
+
+DROP INDEX IF EXISTS idx_coaching_bookings_refund_due;
+DROP INDEX IF EXISTS idx_coaching_bookings_payment_pending;
+ALTER TABLE coaching_bookings
+    DROP COLUMN IF EXISTS payment_expires_at,
+    DROP COLUMN IF EXISTS payment_status,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS price_cents;
+
+DROP TYPE IF EXISTS coaching_payment_status;
+
+ALTER TABLE coaching_session_types
+    DROP CONSTRAINT IF EXISTS chk_session_type_price_currency,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS price_cents;
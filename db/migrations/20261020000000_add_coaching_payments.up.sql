@@ -0,0 +1,39 @@
+-- Paid session types. price_cents is in the currency's minor unit (ISO 4217
+-- code in currency); 0 means the session is free and needs no checkout.
+ALTER TABLE coaching_session_types
+    ADD COLUMN price_cents INTEGER NOT NULL DEFAULT 0 CHECK (price_cents >= 0),
+    ADD COLUMN currency TEXT CHECK (currency ~ '^[A-Z]{3}$'),
+    ADD CONSTRAINT chk_session_type_price_currency CHECK (price_cents = 0 OR currency IS NOT NULL);
+
+CREATE TYPE coaching_payment_status AS ENUM ('pending', 'paid', 'expired', 'refunded');
+
+-- Bookings keep the price they were booked at, like duration_minutes.
+-- payment_status is NULL for free sessions. A pending booking holds its slot
+-- until payment_expires_at, then it is cancelled as expired. A cancelled
+-- booking that is still paid is owed a refund.
+ALTER TABLE coaching_bookings
+    ADD COLUMN price_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN currency TEXT,
+    ADD COLUMN payment_status coaching_payment_status,
+    ADD COLUMN payment_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_coaching_bookings_payment_pending
+    ON coaching_bookings(payment_expires_at) WHERE payment_status = 'pending' AND is_cancelled = false;
+CREATE INDEX idx_coaching_bookings_refund_due
+    ON coaching_bookings(updated_at) WHERE payment_status = 'paid' AND is_cancelled = true;
+
+-- The payment provider's side of a paid booking: its checkout session, the
+-- captured payment and the refund.
+CREATE TABLE coaching_payments (
+    booking_id UUID PRIMARY KEY REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    checkout_session_id TEXT NOT NULL,
+    amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+    currency TEXT NOT NULL,
+    provider_payment_id TEXT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    refund_id TEXT,
+    refunded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, checkout_session_id)
+);
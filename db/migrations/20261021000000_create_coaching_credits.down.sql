@@ -0,0 +1,9 @@
+ALTER TABLE coaching_bookings DROP COLUMN IF EXISTS credit_grant_id;
+
+DROP TABLE IF EXISTS coaching_credit_ledger;
+DROP TYPE IF EXISTS coaching_credit_entry_kind;
+DROP TABLE IF EXISTS coaching_credit_grants;
+
+ALTER TABLE coaching_session_types
+    DROP CONSTRAINT IF EXISTS chk_session_type_credit_unpriced,
+    DROP COLUMN IF EXISTS requires_credit;
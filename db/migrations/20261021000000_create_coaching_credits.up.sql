@@ -0,0 +1,55 @@
+-- Credit-only session types can only be booked with a prepaid credit. They are
+-- never priced: the credits were paid for outside the app.
+ALTER TABLE coaching_session_types
+    ADD COLUMN requires_credit BOOLEAN NOT NULL DEFAULT false,
+    ADD CONSTRAINT chk_session_type_credit_unpriced CHECK (NOT requires_credit OR price_cents = 0);
+
+-- Credits an expert granted a student in a group, e.g. a "10 sessions"
+-- package. remaining drops as bookings consume them and grows again when such
+-- a booking is cancelled; whatever is left at expires_at lapses.
+CREATE TABLE coaching_credit_grants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id TEXT NOT NULL,
+    expert_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    remaining INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    note TEXT,
+    granted_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_credit_grant_remaining CHECK (remaining >= 0 AND remaining <= quantity)
+);
+
+CREATE INDEX idx_coaching_credit_grants_balance
+    ON coaching_credit_grants(student_id, expert_id, group_id) WHERE remaining > 0;
+CREATE INDEX idx_coaching_credit_grants_expiry
+    ON coaching_credit_grants(expires_at) WHERE remaining > 0 AND expires_at IS NOT NULL;
+
+CREATE TYPE coaching_credit_entry_kind AS ENUM ('grant', 'consume', 'restore', 'expire');
+
+-- Append-only ledger of every credit movement. The remaining credits of a
+-- grant always equal the sum of its deltas. actor_id is NULL for movements made
+-- by the system (expiry).
+CREATE TABLE coaching_credit_ledger (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    grant_id UUID NOT NULL REFERENCES coaching_credit_grants(id) ON DELETE CASCADE,
+    student_id TEXT NOT NULL,
+    expert_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    booking_id UUID REFERENCES coaching_bookings(id) ON DELETE SET NULL,
+    kind coaching_credit_entry_kind NOT NULL,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    actor_id TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coaching_credit_ledger_student
+    ON coaching_credit_ledger(group_id, student_id, created_at DESC);
+CREATE INDEX idx_coaching_credit_ledger_expert
+    ON coaching_credit_ledger(group_id, expert_id, created_at DESC);
+
+-- The grant a booking consumed; its credit goes back there on cancellation.
+ALTER TABLE coaching_bookings
+    ADD COLUMN credit_grant_id UUID REFERENCES coaching_credit_grants(id) ON DELETE SET NULL;
@@ -0,0 +1,29 @@
+DROP TABLE IF EXISTS asset_viewers;
+
+-- Keep one row per role so the old primary key fits again.
+DELETE FROM coaching_booking_presence presence
+USING coaching_bookings booking
+WHERE booking.id = presence.booking_id
+  AND presence.participant_id <> CASE presence.participant_role
+        WHEN 'expert' THEN booking.expert_id
+        ELSE booking.student_id
+    END;
+
+ALTER TABLE coaching_booking_presence
+    DROP CONSTRAINT coaching_booking_presence_pkey,
+    ADD PRIMARY KEY (booking_id, participant_role),
+    DROP COLUMN participant_id;
+
+DROP INDEX IF EXISTS idx_coaching_bookings_class_student;
+DROP INDEX IF EXISTS idx_coaching_bookings_class_seat;
+
+ALTER TABLE coaching_bookings
+    DROP CONSTRAINT IF EXISTS chk_booking_class_seat,
+    DROP COLUMN IF EXISTS class_seat,
+    DROP COLUMN IF EXISTS class_id;
+
+DROP TABLE IF EXISTS coaching_classes;
+
+ALTER TABLE coaching_session_types
+    DROP CONSTRAINT IF EXISTS chk_session_type_capacity,
+    DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,60 @@
+-- Session types with a capacity above 1 are group classes: several students
+-- book the same start and join one call.
+ALTER TABLE coaching_session_types
+    ADD COLUMN capacity INTEGER NOT NULL DEFAULT 1,
+    ADD CONSTRAINT chk_session_type_capacity CHECK (capacity BETWEEN 1 AND 50);
+
+-- One class per session type and start. Every attendee keeps their own
+-- booking (payment, credit, cancellation); the class ties them to one call.
+CREATE TABLE coaching_classes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expert_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    session_type_id UUID NOT NULL REFERENCES coaching_session_types(id) ON DELETE CASCADE,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 1),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (session_type_id, scheduled_at)
+);
+
+-- class_seat numbers the bookings of a class in booking order and is never
+-- reused, so every attendee keeps a distinct Agora UID. Seat 1 hosts the
+-- class: the call's channel, presence and recordings hang off that booking,
+-- even after its attendee cancels.
+ALTER TABLE coaching_bookings
+    ADD COLUMN class_id UUID REFERENCES coaching_classes(id) ON DELETE SET NULL,
+    ADD COLUMN class_seat INTEGER,
+    ADD CONSTRAINT chk_booking_class_seat CHECK (class_id IS NULL OR class_seat IS NOT NULL);
+
+CREATE UNIQUE INDEX idx_coaching_bookings_class_seat
+    ON coaching_bookings(class_id, class_seat) WHERE class_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_coaching_bookings_class_student
+    ON coaching_bookings(class_id, student_id) WHERE class_id IS NOT NULL AND is_cancelled = false;
+
+-- Presence is tracked per participant: a class call has many students.
+ALTER TABLE coaching_booking_presence ADD COLUMN participant_id TEXT;
+
+UPDATE coaching_booking_presence presence
+SET participant_id = CASE presence.participant_role
+        WHEN 'expert' THEN booking.expert_id
+        ELSE booking.student_id
+    END
+FROM coaching_bookings booking
+WHERE booking.id = presence.booking_id;
+
+ALTER TABLE coaching_booking_presence
+    ALTER COLUMN participant_id SET NOT NULL,
+    DROP CONSTRAINT coaching_booking_presence_pkey,
+    ADD PRIMARY KEY (booking_id, participant_id);
+
+-- Users besides the owner who may watch an asset, e.g. every attendee of a
+-- recorded class.
+CREATE TABLE asset_viewers (
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (asset_id, user_id)
+);
+
+CREATE INDEX idx_asset_viewers_user ON asset_viewers(user_id);
@@ -0,0 +1,27 @@
+DELETE FROM notifications WHERE type IN ('coaching_session_notes_shared', 'coaching_homework_assigned');
+
+ALTER TYPE notification_type RENAME TO notification_type_old;
+CREATE TYPE notification_type AS ENUM (
+    'group_invitation_received',
+    'group_member_joined',
+    'video_reviewed',
+    'video_uploaded',
+    'coaching_booking_created',
+    'coaching_booking_cancelled',
+    'review_thread_updated',
+    'review_reaction_added',
+    'coaching_booking_reschedule_proposed',
+    'coaching_booking_reschedule_declined',
+    'coaching_booking_rescheduled',
+    'coaching_waitlist_slot_offered'
+);
+ALTER TABLE notifications
+    ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+DROP TYPE notification_type_old;
+
+DELETE FROM coaching_booking_reminders WHERE homework_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_coaching_booking_reminders_homework_id;
+ALTER TABLE coaching_booking_reminders DROP COLUMN IF EXISTS homework_id;
+
+DROP TABLE IF EXISTS coaching_homework;
+DROP TABLE IF EXISTS coaching_session_notes;
@@ -0,0 +1,42 @@
+-- The expert's write-up of a session that took place: one set of notes per
+-- booking, shown to the student as the session summary.
+CREATE TABLE coaching_session_notes (
+    booking_id UUID PRIMARY KEY REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    author_id TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    went_well TEXT NOT NULL DEFAULT '',
+    to_improve TEXT NOT NULL DEFAULT '',
+    next_focus TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Homework the expert assigns after a session, tracked until the student
+-- completes it. The session's recording is reached through the booking.
+CREATE TABLE coaching_homework (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    student_id TEXT NOT NULL,
+    expert_id TEXT NOT NULL,
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    due_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coaching_homework_booking ON coaching_homework(booking_id);
+CREATE INDEX idx_coaching_homework_student ON coaching_homework(group_id, student_id, completed_at);
+CREATE INDEX idx_coaching_homework_expert ON coaching_homework(group_id, expert_id, completed_at);
+
+-- Due-date reminders for homework ride on the session reminder pipeline.
+ALTER TABLE coaching_booking_reminders
+    ADD COLUMN homework_id UUID REFERENCES coaching_homework(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_coaching_booking_reminders_homework_id
+    ON coaching_booking_reminders(homework_id) WHERE homework_id IS NOT NULL;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_session_notes_shared';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_homework_assigned';
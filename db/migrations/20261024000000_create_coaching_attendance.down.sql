@@ -0,0 +1,34 @@
+DELETE FROM notifications WHERE type = 'coaching_booking_no_show';
+
+ALTER TYPE notification_type RENAME TO notification_type_old;
+CREATE TYPE notification_type AS ENUM (
+    'group_invitation_received',
+    'group_member_joined',
+    'video_reviewed',
+    'video_uploaded',
+    'coaching_booking_created',
+    'coaching_booking_cancelled',
+    'review_thread_updated',
+    'review_reaction_added',
+    'coaching_booking_reschedule_proposed',
+    'coaching_booking_reschedule_declined',
+    'coaching_booking_rescheduled',
+    'coaching_waitlist_slot_offered',
+    'coaching_session_notes_shared',
+    'coaching_homework_assigned'
+);
+ALTER TABLE notifications
+    ALTER COLUMN type TYPE notification_type USING type::text::notification_type;
+DROP TYPE notification_type_old;
+
+ALTER TABLE coaching_session_types
+    DROP COLUMN IF EXISTS notify_owner_on_no_show,
+    DROP COLUMN IF EXISTS no_show_credit_policy;
+
+DROP INDEX IF EXISTS idx_coaching_bookings_attendance_pending;
+ALTER TABLE coaching_bookings
+    DROP COLUMN IF EXISTS attendance_recorded_at,
+    DROP COLUMN IF EXISTS attendance_outcome;
+DROP TYPE IF EXISTS coaching_attendance_outcome;
+
+DROP TABLE IF EXISTS coaching_booking_attendance;
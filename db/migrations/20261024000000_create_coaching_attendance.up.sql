@@ -0,0 +1,38 @@
+-- Time each participant spent in a booking's call, accumulated from presence
+-- heartbeats. Presence rows come and go with the connection; these stay so the
+-- outcome can be decided once the session is over. Class attendees are
+-- recorded on the booking that hosts the class call.
+CREATE TABLE coaching_booking_attendance (
+    booking_id UUID NOT NULL REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    participant_id TEXT NOT NULL,
+    participant_role TEXT NOT NULL CHECK (participant_role IN ('student', 'expert')),
+    attended_seconds INTEGER NOT NULL DEFAULT 0,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (booking_id, participant_id)
+);
+
+CREATE TYPE coaching_attendance_outcome AS ENUM ('attended', 'student_no_show', 'expert_no_show', 'partial');
+
+-- attendance_recorded_at is set once the outcome has been decided; the
+-- outcome stays NULL for sessions that ended before attendance was tracked.
+ALTER TABLE coaching_bookings
+    ADD COLUMN attendance_outcome coaching_attendance_outcome,
+    ADD COLUMN attendance_recorded_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE coaching_bookings
+SET attendance_recorded_at = NOW()
+WHERE scheduled_at + (duration_minutes * interval '1 minute') <= NOW();
+
+CREATE INDEX idx_coaching_bookings_attendance_pending
+    ON coaching_bookings(scheduled_at) WHERE attendance_recorded_at IS NULL AND is_cancelled = false;
+
+-- No-show policy: whether a student who does not show keeps their credit, and
+-- whether the group owner hears about no-shows. An expert no-show always
+-- gives the credit back.
+ALTER TABLE coaching_session_types
+    ADD COLUMN no_show_credit_policy TEXT NOT NULL DEFAULT 'forfeit'
+        CHECK (no_show_credit_policy IN ('forfeit', 'restore')),
+    ADD COLUMN notify_owner_on_no_show BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'coaching_booking_no_show';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS coaching_booking_messages_notify_created ON coaching_booking_messages;
+DROP FUNCTION IF EXISTS notify_coaching_booking_message_created();
+DROP TABLE IF EXISTS coaching_booking_messages;
@@ -0,0 +1,30 @@
+-- Chat of a booking's call. Like presence, messages are kept on the call's
+-- host booking, so everyone in a class shares one conversation. links holds
+-- the URLs found in body when it was written.
+CREATE TABLE coaching_booking_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    author_id TEXT NOT NULL,
+    author_role TEXT NOT NULL CHECK (author_role IN ('student', 'expert')),
+    body TEXT NOT NULL,
+    links TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coaching_booking_messages_booking ON coaching_booking_messages(booking_id, created_at);
+
+-- Every insert emits a NOTIFY so the API instance holding a participant's
+-- chat stream can deliver the message, whichever instance accepted it.
+CREATE FUNCTION notify_coaching_booking_message_created() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify(
+        'coaching_booking_messages',
+        json_build_object('id', NEW.id, 'booking_id', NEW.booking_id)::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER coaching_booking_messages_notify_created
+    AFTER INSERT ON coaching_booking_messages
+    FOR EACH ROW EXECUTE FUNCTION notify_coaching_booking_message_created();
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS coaching_recording_stitches;
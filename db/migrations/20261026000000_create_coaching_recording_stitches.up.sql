@@ -0,0 +1,24 @@
+-- A booking whose recording came in several parts gets one stitched video
+-- once all parts are imported. The part videos stay until the stitched one is
+-- ready and remain the recording if stitching gives up. Statuses follow the
+-- part imports: importing while the parts are joined and uploaded, processing
+-- while Mux encodes the result.
+CREATE TABLE coaching_recording_stitches (
+    booking_id UUID PRIMARY KEY REFERENCES coaching_bookings(id) ON DELETE CASCADE,
+    status coaching_recording_import_status NOT NULL DEFAULT 'pending',
+    part_count INTEGER NOT NULL CHECK (part_count > 1),
+    object_name TEXT,
+    mux_asset_id TEXT,
+    mux_playback_id TEXT,
+    video_id UUID REFERENCES videos(id) ON DELETE SET NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    stitched_at TIMESTAMP WITH TIME ZONE,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_coaching_recording_stitches_pending
+    ON coaching_recording_stitches (status, last_attempt_at)
+    WHERE status IN ('pending', 'importing', 'processing', 'failed');
@@ -0,0 +1,13 @@
+DELETE FROM audit_chain_heads WHERE shard <> 0;
+ALTER TABLE audit_chain_heads
+    DROP CONSTRAINT IF EXISTS audit_chain_heads_pkey,
+    ADD PRIMARY KEY (partition_name);
+ALTER TABLE audit_chain_heads
+    DROP COLUMN IF EXISTS shard;
+
+DROP INDEX IF EXISTS idx_audit_events_chain;
+CREATE INDEX IF NOT EXISTS idx_audit_events_chain
+    ON audit_events (chain_seq);
+
+ALTER TABLE audit_events
+    DROP COLUMN IF EXISTS chain_shard;
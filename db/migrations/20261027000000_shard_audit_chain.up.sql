@@ -0,0 +1,15 @@
+-- Splits the hash chain into shards so unrelated writers no longer queue on one
+-- head row per partition. Existing chains continue as shard 0; heads of other
+-- shards are created on their first event.
+ALTER TABLE audit_events
+    ADD COLUMN IF NOT EXISTS chain_shard INTEGER NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idx_audit_events_chain;
+CREATE INDEX IF NOT EXISTS idx_audit_events_chain
+    ON audit_events (chain_shard, chain_seq);
+
+ALTER TABLE audit_chain_heads
+    ADD COLUMN IF NOT EXISTS shard INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE audit_chain_heads
+    DROP CONSTRAINT IF EXISTS audit_chain_heads_pkey,
+    ADD PRIMARY KEY (partition_name, shard);
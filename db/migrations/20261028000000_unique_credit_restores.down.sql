@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_coaching_credit_ledger_restore;
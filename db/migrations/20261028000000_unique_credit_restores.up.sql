@@ -0,0 +1,3 @@
+-- A booking's credit goes back to its grant at most once.
+CREATE UNIQUE INDEX idx_coaching_credit_ledger_restore
+    ON coaching_credit_ledger(booking_id) WHERE kind = 'restore';
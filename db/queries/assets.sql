@@ -21,6 +21,8 @@ UPDATE assets SET status = $2, updated_at = NOW() WHERE id = $1;
 UPDATE videos SET mux_asset_id = $2, status = 'ready', updated_at = NOW() WHERE id = $1;
 
 -- name: ListVisibleAssets :many
+-- Newest upload first. Without a status filter, assets still uploading are
+-- hidden. A NULL page_limit returns every match.
 SELECT
     a.id,
     a.name,
@@ -29,15 +31,18 @@ SELECT
     a.created_at,
     a.updated_at,
     a.owner_id,
+    COALESCE(a.last_review_at, a.created_at)::timestamptz as last_activity_at,
     COALESCE(v.playback_id, '') as playback_id,
     COALESCE(v.mux_upload_id, '') as mux_upload_id,
     COALESCE(v.mux_asset_id, '') as mux_asset_id,
-    COALESCE(rv.review_count, 0)::bigint as review_count
+    COALESCE(rv.review_count, 0)::bigint as review_count,
+    a.review_threads,
+    a.open_review_threads
 FROM assets a
 LEFT JOIN LATERAL (
     SELECT playback_id, mux_upload_id, mux_asset_id
     FROM videos
-    WHERE asset_id = a.id
+    WHERE asset_id = a.id AND deleted_at IS NULL
     ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
     LIMIT 1
 ) v ON true
@@ -46,10 +51,14 @@ LEFT JOIN LATERAL (
     FROM videos review_videos
     LEFT JOIN video_reviews r ON r.video_id = review_videos.id
     WHERE review_videos.asset_id = a.id
+      AND review_videos.deleted_at IS NULL
 ) rv ON true
-WHERE a.status != 'waiting_upload'
+WHERE a.deleted_at IS NULL
   AND (
-    (sqlc.arg(is_student)::boolean AND a.owner_id = sqlc.arg(user_id))
+    (sqlc.arg(is_student)::boolean AND (
+      a.owner_id = sqlc.arg(user_id)
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = sqlc.arg(user_id))
+    ))
     OR (
       NOT sqlc.arg(is_student)::boolean
       AND EXISTS (
@@ -60,10 +69,183 @@ WHERE a.status != 'waiting_upload'
       )
     )
   )
-ORDER BY a.created_at DESC;
+  AND (
+    (sqlc.arg(status)::text = '' AND a.status != 'waiting_upload')
+    OR a.status::text = sqlc.arg(status)
+  )
+  AND (sqlc.narg(group_id)::uuid IS NULL OR a.group_id = sqlc.narg(group_id))
+  AND (sqlc.arg(owner_id)::text = '' OR a.owner_id = sqlc.arg(owner_id))
+  AND (
+    sqlc.arg(review_state)::text = ''
+    OR (sqlc.arg(review_state) = 'to_review' AND a.status = 'pending' AND a.last_review_at IS NULL)
+    OR (sqlc.arg(review_state) = 'in_review' AND (
+      (a.status = 'pending' AND a.last_review_at IS NOT NULL)
+      OR (a.status = 'completed' AND a.open_review_threads > 0)
+    ))
+    OR (sqlc.arg(review_state) = 'reviewed' AND a.status = 'completed' AND a.open_review_threads = 0)
+  )
+  AND (
+    sqlc.arg(search_query)::text = ''
+    OR to_tsvector('simple', a.name || ' ' || a.description) @@ to_tsquery('simple', sqlc.arg(search_query))
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (a.created_at, a.id) < (sqlc.arg(cursor_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.narg(page_limit)::int;
+
+-- name: ListVisibleAssetsByUpdated :many
+-- Same filters as ListVisibleAssets, most recently changed first.
+SELECT
+    a.id,
+    a.name,
+    a.description,
+    a.status,
+    a.created_at,
+    a.updated_at,
+    a.owner_id,
+    COALESCE(a.last_review_at, a.created_at)::timestamptz as last_activity_at,
+    COALESCE(v.playback_id, '') as playback_id,
+    COALESCE(v.mux_upload_id, '') as mux_upload_id,
+    COALESCE(v.mux_asset_id, '') as mux_asset_id,
+    COALESCE(rv.review_count, 0)::bigint as review_count,
+    a.review_threads,
+    a.open_review_threads
+FROM assets a
+LEFT JOIN LATERAL (
+    SELECT playback_id, mux_upload_id, mux_asset_id
+    FROM videos
+    WHERE asset_id = a.id AND deleted_at IS NULL
+    ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
+    LIMIT 1
+) v ON true
+LEFT JOIN LATERAL (
+    SELECT COUNT(r.id) as review_count
+    FROM videos review_videos
+    LEFT JOIN video_reviews r ON r.video_id = review_videos.id
+    WHERE review_videos.asset_id = a.id
+      AND review_videos.deleted_at IS NULL
+) rv ON true
+WHERE a.deleted_at IS NULL
+  AND (
+    (sqlc.arg(is_student)::boolean AND (
+      a.owner_id = sqlc.arg(user_id)
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = sqlc.arg(user_id))
+    ))
+    OR (
+      NOT sqlc.arg(is_student)::boolean
+      AND EXISTS (
+        SELECT 1
+        FROM user_groups ug
+        WHERE ug.user_id = sqlc.arg(user_id)
+          AND ug.group_id = a.group_id
+      )
+    )
+  )
+  AND (
+    (sqlc.arg(status)::text = '' AND a.status != 'waiting_upload')
+    OR a.status::text = sqlc.arg(status)
+  )
+  AND (sqlc.narg(group_id)::uuid IS NULL OR a.group_id = sqlc.narg(group_id))
+  AND (sqlc.arg(owner_id)::text = '' OR a.owner_id = sqlc.arg(owner_id))
+  AND (
+    sqlc.arg(review_state)::text = ''
+    OR (sqlc.arg(review_state) = 'to_review' AND a.status = 'pending' AND a.last_review_at IS NULL)
+    OR (sqlc.arg(review_state) = 'in_review' AND (
+      (a.status = 'pending' AND a.last_review_at IS NOT NULL)
+      OR (a.status = 'completed' AND a.open_review_threads > 0)
+    ))
+    OR (sqlc.arg(review_state) = 'reviewed' AND a.status = 'completed' AND a.open_review_threads = 0)
+  )
+  AND (
+    sqlc.arg(search_query)::text = ''
+    OR to_tsvector('simple', a.name || ' ' || a.description) @@ to_tsquery('simple', sqlc.arg(search_query))
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (a.updated_at, a.id) < (sqlc.arg(cursor_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY a.updated_at DESC, a.id DESC
+LIMIT sqlc.narg(page_limit)::int;
+
+-- name: ListVisibleAssetsByReviewActivity :many
+-- Same filters as ListVisibleAssets, most recent review activity first.
+-- Assets nobody has reviewed yet rank by their upload time.
+SELECT
+    a.id,
+    a.name,
+    a.description,
+    a.status,
+    a.created_at,
+    a.updated_at,
+    a.owner_id,
+    COALESCE(a.last_review_at, a.created_at)::timestamptz as last_activity_at,
+    COALESCE(v.playback_id, '') as playback_id,
+    COALESCE(v.mux_upload_id, '') as mux_upload_id,
+    COALESCE(v.mux_asset_id, '') as mux_asset_id,
+    COALESCE(rv.review_count, 0)::bigint as review_count,
+    a.review_threads,
+    a.open_review_threads
+FROM assets a
+LEFT JOIN LATERAL (
+    SELECT playback_id, mux_upload_id, mux_asset_id
+    FROM videos
+    WHERE asset_id = a.id AND deleted_at IS NULL
+    ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
+    LIMIT 1
+) v ON true
+LEFT JOIN LATERAL (
+    SELECT COUNT(r.id) as review_count
+    FROM videos review_videos
+    LEFT JOIN video_reviews r ON r.video_id = review_videos.id
+    WHERE review_videos.asset_id = a.id
+      AND review_videos.deleted_at IS NULL
+) rv ON true
+WHERE a.deleted_at IS NULL
+  AND (
+    (sqlc.arg(is_student)::boolean AND (
+      a.owner_id = sqlc.arg(user_id)
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = sqlc.arg(user_id))
+    ))
+    OR (
+      NOT sqlc.arg(is_student)::boolean
+      AND EXISTS (
+        SELECT 1
+        FROM user_groups ug
+        WHERE ug.user_id = sqlc.arg(user_id)
+          AND ug.group_id = a.group_id
+      )
+    )
+  )
+  AND (
+    (sqlc.arg(status)::text = '' AND a.status != 'waiting_upload')
+    OR a.status::text = sqlc.arg(status)
+  )
+  AND (sqlc.narg(group_id)::uuid IS NULL OR a.group_id = sqlc.narg(group_id))
+  AND (sqlc.arg(owner_id)::text = '' OR a.owner_id = sqlc.arg(owner_id))
+  AND (
+    sqlc.arg(review_state)::text = ''
+    OR (sqlc.arg(review_state) = 'to_review' AND a.status = 'pending' AND a.last_review_at IS NULL)
+    OR (sqlc.arg(review_state) = 'in_review' AND (
+      (a.status = 'pending' AND a.last_review_at IS NOT NULL)
+      OR (a.status = 'completed' AND a.open_review_threads > 0)
+    ))
+    OR (sqlc.arg(review_state) = 'reviewed' AND a.status = 'completed' AND a.open_review_threads = 0)
+  )
+  AND (
+    sqlc.arg(search_query)::text = ''
+    OR to_tsvector('simple', a.name || ' ' || a.description) @@ to_tsquery('simple', sqlc.arg(search_query))
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (COALESCE(a.last_review_at, a.created_at), a.id) < (sqlc.arg(cursor_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY COALESCE(a.last_review_at, a.created_at) DESC, a.id DESC
+LIMIT sqlc.narg(page_limit)::int;
 
 -- name: GetAsset :one
-SELECT a.id, a.name, a.description, a.status, a.created_at, a.updated_at, a.owner_id, a.group_id, COALESCE(v.playback_id, '') as playback_id, COALESCE(v.mux_upload_id, '') as mux_upload_id, COALESCE(v.mux_asset_id, '') as mux_asset_id, g.name as group_name, g.avatar as group_avatar FROM assets a LEFT JOIN LATERAL (SELECT playback_id, mux_upload_id, mux_asset_id FROM videos WHERE asset_id = a.id ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC LIMIT 1) v ON true LEFT JOIN groups g ON g.id = a.group_id WHERE a.id = $1;
+SELECT a.id, a.name, a.description, a.status, a.created_at, a.updated_at, a.owner_id, a.group_id, COALESCE(v.playback_id, '') as playback_id, COALESCE(v.mux_upload_id, '') as mux_upload_id, COALESCE(v.mux_asset_id, '') as mux_asset_id, g.name as group_name, g.avatar as group_avatar FROM assets a LEFT JOIN LATERAL (SELECT playback_id, mux_upload_id, mux_asset_id FROM videos WHERE asset_id = a.id AND deleted_at IS NULL ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC LIMIT 1) v ON true LEFT JOIN groups g ON g.id = a.group_id WHERE a.id = $1 AND a.deleted_at IS NULL;
 
 -- name: GetVisibleAsset :one
 SELECT
@@ -75,6 +257,8 @@ SELECT
     a.updated_at,
     a.owner_id,
     a.group_id,
+    a.review_threads,
+    a.open_review_threads,
     COALESCE(v.playback_id, '') as playback_id,
     COALESCE(v.mux_upload_id, '') as mux_upload_id,
     COALESCE(v.mux_asset_id, '') as mux_asset_id,
@@ -88,15 +272,19 @@ FROM assets a
 LEFT JOIN LATERAL (
     SELECT playback_id, mux_upload_id, mux_asset_id
     FROM videos
-    WHERE asset_id = a.id
+    WHERE asset_id = a.id AND deleted_at IS NULL
     ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
     LIMIT 1
 ) v ON true
 LEFT JOIN groups g ON g.id = a.group_id
 LEFT JOIN user_preferences up ON up.user_id = a.owner_id
 WHERE a.id = sqlc.arg(asset_id)
+  AND a.deleted_at IS NULL
   AND (
-    (sqlc.arg(is_student)::boolean AND a.owner_id = sqlc.arg(user_id))
+    (sqlc.arg(is_student)::boolean AND (
+      a.owner_id = sqlc.arg(user_id)
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = sqlc.arg(user_id))
+    ))
     OR (
       NOT sqlc.arg(is_student)::boolean
       AND EXISTS (
@@ -109,10 +297,10 @@ WHERE a.id = sqlc.arg(asset_id)
   );
 
 -- name: GetAssetVideos :many
-SELECT v.id, v.mux_upload_id, v.mux_asset_id, v.playback_id, v.status, v.duration_seconds, v.created_at, COUNT(r.id) as review_count
+SELECT v.id, v.mux_upload_id, v.mux_asset_id, v.playback_id, v.status, v.duration_seconds, v.mux_error, v.created_at, COUNT(r.id) as review_count
 FROM videos v
 LEFT JOIN video_reviews r ON v.id = r.video_id
-WHERE v.asset_id = $1
+WHERE v.asset_id = $1 AND v.deleted_at IS NULL
 GROUP BY v.id
 ORDER BY v.sort_order ASC NULLS LAST, v.created_at ASC, v.id ASC;
 
@@ -135,6 +323,15 @@ SELECT EXISTS (
                     OR recording_import.status IN ('pending', 'importing', 'processing', 'failed')
                 )
           )
+          OR EXISTS (
+              SELECT 1
+              FROM coaching_recording_stitches stitch
+              WHERE stitch.booking_id = booking.id
+                AND (
+                    stitch.status IN ('pending', 'importing', 'processing')
+                    OR (stitch.status = 'failed' AND stitch.attempts < 3)
+                )
+          )
       )
 );
 
@@ -156,6 +353,7 @@ SELECT id, mux_asset_id, mux_upload_id
 FROM videos
 WHERE status = 'ready'
   AND duration_seconds IS NULL
+  AND deleted_at IS NULL
   AND (mux_asset_id <> '' OR mux_upload_id <> '')
 ORDER BY created_at
 LIMIT $1;
@@ -164,3 +362,104 @@ LIMIT $1;
 UPDATE videos
 SET duration_seconds = $2, updated_at = NOW()
 WHERE id = $1 AND duration_seconds IS NULL;
+
+-- name: AttachVideoMuxAsset :execrows
+-- Records the asset Mux created for a direct upload. A later or repeated
+-- delivery never overwrites an asset id that is already set.
+UPDATE videos
+SET mux_asset_id = sqlc.arg(mux_asset_id)::text, updated_at = NOW()
+WHERE mux_upload_id = sqlc.arg(mux_upload_id)::text
+  AND sqlc.arg(mux_upload_id)::text <> ''
+  AND (mux_asset_id IS NULL OR mux_asset_id = '');
+
+-- name: MarkVideoReadyFromMux :many
+-- Matches by asset id, or by upload id when video.asset.ready overtakes
+-- video.upload.asset_created. An existing duration or playback id is kept.
+-- Deleted videos are left alone so a late delivery cannot revive them.
+UPDATE videos
+SET mux_asset_id = sqlc.arg(mux_asset_id)::text,
+    playback_id = COALESCE(NULLIF(sqlc.arg(playback_id)::text, ''), playback_id),
+    duration_seconds = COALESCE(duration_seconds, sqlc.narg(duration_seconds)),
+    status = 'ready',
+    mux_error = NULL,
+    updated_at = NOW()
+WHERE (mux_asset_id = sqlc.arg(mux_asset_id)::text
+   OR (sqlc.arg(mux_upload_id)::text <> '' AND mux_upload_id = sqlc.arg(mux_upload_id)::text))
+  AND deleted_at IS NULL
+RETURNING asset_id;
+
+-- name: MarkVideoFailedFromMux :many
+-- A ready video stays ready: a stale errored delivery must not hide a
+-- playable video, and deleted videos are left alone.
+UPDATE videos
+SET mux_asset_id = sqlc.arg(mux_asset_id)::text,
+    status = 'failed',
+    mux_error = sqlc.arg(mux_error)::text,
+    updated_at = NOW()
+WHERE (mux_asset_id = sqlc.arg(mux_asset_id)::text
+   OR (sqlc.arg(mux_upload_id)::text <> '' AND mux_upload_id = sqlc.arg(mux_upload_id)::text))
+  AND status <> 'ready'
+  AND deleted_at IS NULL
+RETURNING asset_id;
+
+-- name: PromoteUploadedAsset :execrows
+-- Moves an asset out of waiting_upload once no video is still waiting on Mux
+-- and at least one is playable, so uploads finish even if the client never
+-- calls complete.
+UPDATE assets a
+SET status = 'pending', updated_at = NOW()
+WHERE a.id = $1
+  AND a.status = 'waiting_upload'
+  AND a.deleted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM videos v
+      WHERE v.asset_id = a.id AND v.deleted_at IS NULL AND v.status = 'waiting_upload'
+  )
+  AND EXISTS (
+      SELECT 1 FROM videos v
+      WHERE v.asset_id = a.id AND v.deleted_at IS NULL AND v.status = 'ready'
+  );
+
+-- name: SoftDeleteAsset :one
+-- Hides the asset and all its videos at once. The purge job removes it for
+-- good once the grace window has passed.
+UPDATE assets
+SET deleted_at = NOW(), deleted_by = sqlc.arg(deleted_by), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteVideo :one
+UPDATE videos
+SET deleted_at = NOW(), deleted_by = sqlc.arg(deleted_by), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND asset_id = sqlc.arg(asset_id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListAssetsDueForPurge :many
+SELECT id
+FROM assets
+WHERE deleted_at < sqlc.arg(deleted_before)
+ORDER BY deleted_at
+LIMIT sqlc.arg(batch_limit);
+
+-- name: ListVideosDueForPurge :many
+-- Videos deleted on their own. Videos of a deleted asset go with the asset.
+SELECT v.id, v.mux_asset_id, v.mux_upload_id
+FROM videos v
+JOIN assets a ON a.id = v.asset_id
+WHERE v.deleted_at < sqlc.arg(deleted_before)
+  AND a.deleted_at IS NULL
+ORDER BY v.deleted_at
+LIMIT sqlc.arg(batch_limit);
+
+-- name: ListAssetMuxIdentifiers :many
+-- Every video of the asset, deleted or not, so no Mux asset is left behind.
+SELECT id, mux_asset_id, mux_upload_id
+FROM videos
+WHERE asset_id = $1;
+
+-- name: PurgeAsset :execrows
+-- Reviews cascade with the videos.
+DELETE FROM assets WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeVideo :execrows
+DELETE FROM videos WHERE id = $1 AND deleted_at IS NOT NULL;
@@ -1,10 +1,126 @@
 -- name: CreateAuditEvent :exec
 INSERT INTO audit_events (
+    id, occurred_at,
     actor_id, actor_type, actor_label, action,
     resource_type, resource_id, group_id,
-    old_values, new_values, metadata
+    old_values, new_values, metadata,
+    chain_shard, chain_seq, prev_hash, hash
 ) VALUES (
-    $1, $2, $3, $4,
-    $5, $6, $7,
-    $8, $9, $10
+    $1, $2,
+    $3, $4, $5, $6,
+    $7, $8, $9,
+    $10, $11, $12,
+    $13, $14, $15, $16
 );
+
+-- name: ExportAuditEvents :many
+-- Chronological keyset page for compliance exports. subject_id matches events
+-- the user performed as well as events about them: their profile, their
+-- memberships, bookings they are party to and reviews they authored.
+SELECT *
+FROM audit_events
+WHERE occurred_at >= sqlc.arg(occurred_from)
+  AND occurred_at < sqlc.arg(occurred_to)
+  AND (sqlc.arg(group_id)::TEXT = '' OR group_id = sqlc.arg(group_id))
+  AND (
+    sqlc.arg(subject_id)::TEXT = ''
+    OR actor_id = sqlc.arg(subject_id)
+    OR (resource_type = 'profile' AND resource_id = sqlc.arg(subject_id))
+    OR sqlc.arg(subject_id) IN (
+      new_values->>'user_id', old_values->>'user_id',
+      new_values->>'student_id', old_values->>'student_id',
+      new_values->>'expert_id', old_values->>'expert_id',
+      new_values->>'author_id', old_values->>'author_id'
+    )
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::BOOLEAN
+    OR (occurred_at, id) > (sqlc.arg(cursor_occurred_at)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+  )
+ORDER BY occurred_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListAuditEvents :many
+-- Keyset-paginated newest first. The occurred_at window is always bounded so
+-- the planner prunes monthly partitions outside it.
+SELECT *
+FROM audit_events
+WHERE occurred_at >= sqlc.arg(occurred_from)
+  AND occurred_at < sqlc.arg(occurred_to)
+  AND (sqlc.arg(actor_id)::TEXT = '' OR actor_id = sqlc.arg(actor_id))
+  AND (sqlc.arg(group_id)::TEXT = '' OR group_id = sqlc.arg(group_id))
+  AND (sqlc.arg(resource_type)::TEXT = '' OR resource_type = sqlc.arg(resource_type))
+  AND (sqlc.arg(resource_id)::TEXT = '' OR resource_id = sqlc.arg(resource_id))
+  AND (sqlc.arg(action)::TEXT = '' OR action = sqlc.arg(action))
+  AND (
+    NOT sqlc.arg(has_cursor)::BOOLEAN
+    OR (occurred_at, id) < (sqlc.arg(cursor_occurred_at)::TIMESTAMPTZ, sqlc.arg(cursor_id)::UUID)
+  )
+ORDER BY occurred_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: EnsureAuditChainHead :exec
+-- Creates the shard's head for the partition NOW() falls into on its first
+-- event; a concurrent first event waits on the insert instead of failing.
+INSERT INTO audit_chain_heads (partition_name, shard)
+VALUES ('audit_events_' || to_char(NOW() AT TIME ZONE 'UTC', 'YYYY_MM'), sqlc.arg(shard))
+ON CONFLICT DO NOTHING;
+
+-- name: LockAuditChainHead :one
+-- Locks one shard's chain head of the partition NOW() falls into. NOW() is the
+-- transaction start, the same value occurred_at defaults to, so the event and
+-- the head always agree on the partition.
+SELECT partition_name, genesis_hash, head_seq, head_hash, NOW()::TIMESTAMPTZ AS now
+FROM audit_chain_heads
+WHERE partition_name = 'audit_events_' || to_char(NOW() AT TIME ZONE 'UTC', 'YYYY_MM')
+  AND shard = sqlc.arg(shard)
+FOR UPDATE;
+
+-- name: SealAuditChainHead :one
+-- Freezes the head a following partition chains to. Late events may still
+-- extend the sealed partition; the seal pins the link, not the tail.
+UPDATE audit_chain_heads
+SET sealed_seq = head_seq,
+    sealed_hash = head_hash,
+    updated_at = NOW()
+WHERE partition_name = $1
+  AND shard = $2
+  AND sealed_seq IS NULL
+RETURNING sealed_hash;
+
+-- name: AdvanceAuditChainHead :exec
+UPDATE audit_chain_heads
+SET head_seq = sqlc.arg(head_seq),
+    head_hash = sqlc.arg(head_hash),
+    genesis_hash = COALESCE(genesis_hash, sqlc.arg(genesis_hash)),
+    chained_since = COALESCE(chained_since, sqlc.arg(chained_since)),
+    updated_at = NOW()
+WHERE partition_name = sqlc.arg(partition_name)
+  AND shard = sqlc.arg(shard);
+
+-- name: ListAuditChainHeads :many
+SELECT *
+FROM audit_chain_heads
+ORDER BY partition_name, shard;
+
+-- name: ListAuditChainEvents :many
+-- One shard's chain of a partition in chain_seq order, keyset-paginated on
+-- chain_seq.
+SELECT *
+FROM audit_events
+WHERE occurred_at >= sqlc.arg(occurred_from)
+  AND occurred_at < sqlc.arg(occurred_to)
+  AND chain_shard = sqlc.arg(chain_shard)
+  AND chain_seq > sqlc.arg(after_seq)
+ORDER BY chain_seq
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountUnchainedAuditEvents :one
+-- Rows without a chain position written after the partition's chain began can
+-- only have been inserted around the recorder.
+SELECT COUNT(*)
+FROM audit_events
+WHERE occurred_at >= sqlc.arg(occurred_from)
+  AND occurred_at < sqlc.arg(occurred_to)
+  AND occurred_at >= sqlc.arg(chained_since)
+  AND chain_seq IS NULL;
@@ -6,8 +6,14 @@ SELECT timezone FROM user_preferences WHERE user_id = $1;
 -- === Session Types ===
 
 -- name: CreateSessionType :one
-INSERT INTO coaching_session_types (expert_id, group_id, name, description, duration_minutes)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO coaching_session_types (
+    expert_id, group_id, name, description, duration_minutes,
+    buffer_before_minutes, buffer_after_minutes, max_sessions_per_day,
+    booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes,
+    price_cents, currency, requires_credit, capacity,
+    no_show_credit_policy, notify_owner_on_no_show
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 RETURNING *;
 
 -- name: ListSessionTypesByExpertGroup :many
@@ -25,7 +31,11 @@ SELECT * FROM coaching_session_types WHERE id = $1 AND group_id = $2;
 
 -- name: UpdateSessionType :one
 UPDATE coaching_session_types
-SET name = $2, description = $3, duration_minutes = $4, updated_at = NOW()
+SET name = $2, description = $3, duration_minutes = $4,
+    buffer_before_minutes = $7, buffer_after_minutes = $8, max_sessions_per_day = $9,
+    booking_horizon_days = $10, min_booking_notice_minutes = $11, cancellation_notice_minutes = $12,
+    price_cents = $13, currency = $14, requires_credit = $15, capacity = $16,
+    no_show_credit_policy = $17, notify_owner_on_no_show = $18, updated_at = NOW()
 WHERE id = $1 AND expert_id = $5 AND group_id = $6
 RETURNING *;
 
@@ -84,6 +94,38 @@ ORDER BY blocked_date, start_time;
 -- name: DeleteBlockedSlot :execrows
 DELETE FROM coaching_blocked_slots WHERE id = $1 AND expert_id = $2;
 
+-- === Availability Overrides ===
+
+-- name: CreateAvailabilityOverride :one
+INSERT INTO coaching_availability_overrides (expert_id, group_id, override_date, start_time, end_time)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListAvailabilityOverrides :many
+SELECT * FROM coaching_availability_overrides
+WHERE expert_id = $1 AND group_id = $2
+  AND override_date >= @from_date AND override_date <= @to_date
+ORDER BY override_date, start_time;
+
+-- name: DeleteAvailabilityOverride :execrows
+DELETE FROM coaching_availability_overrides WHERE id = $1 AND expert_id = $2;
+
+-- === Absences ===
+
+-- name: CreateAbsence :one
+INSERT INTO coaching_absences (expert_id, starts_on, ends_on, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListAbsences :many
+-- Absences overlapping the inclusive date range.
+SELECT * FROM coaching_absences
+WHERE expert_id = $1 AND ends_on >= @from_date AND starts_on <= @to_date
+ORDER BY starts_on;
+
+-- name: DeleteAbsence :execrows
+DELETE FROM coaching_absences WHERE id = $1 AND expert_id = $2;
+
 -- === Bookings ===
 
 -- name: ListBookingsByExpertInRange :many
@@ -95,8 +137,12 @@ WHERE expert_id = $1
 ORDER BY scheduled_at;
 
 -- name: CreateBooking :one
-INSERT INTO coaching_bookings (expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, notes)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO coaching_bookings (
+    expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, notes, series_id,
+    buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at,
+    credit_grant_id, class_id, class_seat
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 RETURNING *;
 
 -- name: GetBooking :one
@@ -201,9 +247,9 @@ ORDER BY recording.updated_at
 LIMIT sqlc.arg(limit_count);
 
 -- name: UpsertBookingPresence :one
-INSERT INTO coaching_booking_presence (booking_id, participant_role, connection_id, last_seen_at)
-VALUES ($1, $2, $3, NOW())
-ON CONFLICT (booking_id, participant_role) DO UPDATE SET
+INSERT INTO coaching_booking_presence (booking_id, participant_id, participant_role, connection_id, last_seen_at)
+VALUES ($1, $2, $3, $4, NOW())
+ON CONFLICT (booking_id, participant_id) DO UPDATE SET
     connection_id = EXCLUDED.connection_id,
     last_seen_at = NOW()
 RETURNING *;
@@ -211,18 +257,54 @@ RETURNING *;
 -- name: RefreshBookingPresence :one
 UPDATE coaching_booking_presence
 SET last_seen_at = NOW()
-WHERE booking_id = $1 AND participant_role = $2 AND connection_id = $3
+WHERE booking_id = $1 AND participant_id = $2 AND connection_id = $3
 RETURNING *;
 
 -- name: RemoveBookingPresence :execrows
 DELETE FROM coaching_booking_presence
-WHERE booking_id = $1 AND participant_role = $2 AND connection_id = $3;
+WHERE booking_id = $1 AND participant_id = $2 AND connection_id = $3;
 
 -- name: CountFreshBookingParticipants :one
 SELECT COUNT(*) FROM coaching_booking_presence
 WHERE booking_id = $1
   AND last_seen_at >= NOW() - (sqlc.arg(fresh_seconds)::int * interval '1 second');
 
+-- name: RecordBookingAttendance :exec
+-- Adds the time since the participant's previous heartbeat to their attendance,
+-- capped at max_gap_seconds so a dropped connection is not counted as time in
+-- the call.
+INSERT INTO coaching_booking_attendance (booking_id, participant_id, participant_role)
+VALUES (sqlc.arg(booking_id), sqlc.arg(participant_id), sqlc.arg(participant_role))
+ON CONFLICT (booking_id, participant_id) DO UPDATE SET
+    attended_seconds = coaching_booking_attendance.attended_seconds
+        + LEAST(EXTRACT(EPOCH FROM NOW() - coaching_booking_attendance.last_seen_at), sqlc.arg(max_gap_seconds)::int)::int,
+    last_seen_at = NOW();
+
+-- name: ListBookingAttendance :many
+SELECT * FROM coaching_booking_attendance
+WHERE booking_id = $1
+ORDER BY first_seen_at;
+
+-- name: ListBookingsAwaitingAttendance :many
+-- Sessions that ended more than end_grace_seconds ago without an attendance
+-- outcome. Bookings still awaiting payment never took place.
+SELECT * FROM coaching_bookings
+WHERE attendance_recorded_at IS NULL
+  AND is_cancelled = false
+  AND payment_status IS DISTINCT FROM 'pending'
+  AND scheduled_at + (duration_minutes * interval '1 minute')
+      + (sqlc.arg(end_grace_seconds)::int * interval '1 second') <= NOW()
+ORDER BY scheduled_at
+LIMIT sqlc.arg(limit_count);
+
+-- name: SetBookingAttendance :one
+-- Leaves updated_at alone: the outcome is not a change to the session itself,
+-- and updated_at drives calendar feed sequence numbers.
+UPDATE coaching_bookings
+SET attendance_outcome = $2, attendance_recorded_at = NOW()
+WHERE id = $1 AND attendance_recorded_at IS NULL
+RETURNING *;
+
 -- name: ExchangeRecordingRendererCapability :one
 SELECT
     recording.id AS recording_id,
@@ -231,7 +313,8 @@ SELECT
     booking.student_id,
     booking.expert_id,
     booking.scheduled_at,
-    booking.duration_minutes
+    booking.duration_minutes,
+    booking.class_id
 FROM coaching_booking_recordings recording
 JOIN coaching_bookings booking ON booking.id = recording.booking_id
 WHERE recording.renderer_token_hash = $1
@@ -323,75 +406,285 @@ SET recording_asset_id = COALESCE(recording_asset_id, $2), updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- === Stitched recordings ===
+
+-- name: EnqueueRecordingStitches :execrows
+-- Queues a stitch for every recent booking whose recording has more than one
+-- imported part video once nothing is in flight: no part recording, no import
+-- running or due a retry, no part stopped in the last 15 minutes without files,
+-- and a known duration for every part video.
+INSERT INTO coaching_recording_stitches (booking_id, part_count)
+SELECT recording.booking_id, COUNT(*)
+FROM coaching_booking_recordings recording
+JOIN coaching_bookings booking ON booking.id = recording.booking_id
+JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
+JOIN videos video ON video.id = recording_import.video_id AND video.deleted_at IS NULL
+WHERE recording_import.status = 'ready'
+  AND booking.recording_asset_id IS NOT NULL
+  AND booking.scheduled_at >= NOW() - interval '7 days'
+  AND booking.scheduled_at + (booking.duration_minutes * interval '1 minute')
+      + (sqlc.arg(end_grace_seconds)::int * interval '1 second') <= NOW()
+  AND NOT EXISTS (
+      SELECT 1
+      FROM coaching_booking_recordings other
+      LEFT JOIN coaching_recording_imports other_import ON other_import.recording_id = other.id
+      LEFT JOIN videos other_video ON other_video.id = other_import.video_id AND other_video.deleted_at IS NULL
+      WHERE other.booking_id = recording.booking_id
+        AND (
+            other.status IN ('starting', 'started', 'stopping')
+            OR (other.status = 'stopped' AND other_import.id IS NULL AND other.stopped_at > NOW() - interval '15 minutes')
+            OR other_import.status IN ('pending', 'importing', 'processing')
+            OR (other_import.status = 'failed' AND other_import.attempts < 5)
+            OR (other_video.id IS NOT NULL AND other_video.duration_seconds IS NULL)
+        )
+  )
+GROUP BY recording.booking_id
+HAVING COUNT(*) > 1
+ON CONFLICT (booking_id) DO NOTHING;
+
+-- name: ClaimPendingRecordingStitches :many
+-- Claims stitches to work on. Polling Mux does not use up an attempt; a stitch
+-- that stopped halfway is claimed again once it has gone quiet for 30 minutes,
+-- even past its last attempt, so it can be given up on.
+WITH candidates AS (
+    SELECT stitch.booking_id
+    FROM coaching_recording_stitches stitch
+    WHERE stitch.status IN ('pending', 'importing', 'processing', 'failed')
+      AND (stitch.attempts < 3 OR stitch.status = 'importing')
+      AND (
+          stitch.last_attempt_at IS NULL
+          OR (stitch.status = 'importing' AND stitch.last_attempt_at <= NOW() - interval '30 minutes')
+          OR (stitch.status <> 'importing' AND stitch.last_attempt_at <= NOW() - interval '1 minute')
+      )
+    ORDER BY stitch.created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT $1
+), claimed AS (
+    UPDATE coaching_recording_stitches stitch
+    SET status = (CASE WHEN stitch.mux_asset_id IS NULL THEN 'importing' ELSE 'processing' END)::coaching_recording_import_status,
+        attempts = stitch.attempts + (CASE WHEN stitch.mux_asset_id IS NULL THEN 1 ELSE 0 END),
+        last_attempt_at = NOW(), updated_at = NOW()
+    FROM candidates
+    WHERE stitch.booking_id = candidates.booking_id
+    RETURNING stitch.*
+)
+SELECT claimed.*, booking.recording_asset_id, booking.expert_id
+FROM claimed
+JOIN coaching_bookings booking ON booking.id = claimed.booking_id
+ORDER BY claimed.created_at;
+
+-- name: ListRecordingStitchInputs :many
+-- The part videos a stitch joins, in recording order.
+SELECT recording.part_number, recording_import.file_index, recording_import.gcs_object_name,
+       video.id AS video_id, video.duration_seconds
+FROM coaching_recording_imports recording_import
+JOIN coaching_booking_recordings recording ON recording.id = recording_import.recording_id
+JOIN videos video ON video.id = recording_import.video_id AND video.deleted_at IS NULL
+WHERE recording.booking_id = $1 AND recording_import.status = 'ready'
+ORDER BY recording.part_number, recording_import.file_index;
+
+-- name: MarkRecordingStitchMuxCreated :one
+UPDATE coaching_recording_stitches
+SET status = 'processing', object_name = $2, mux_asset_id = $3, mux_playback_id = $4,
+    error = NULL, updated_at = NOW()
+WHERE booking_id = $1
+RETURNING *;
+
+-- name: MarkRecordingStitchReady :one
+UPDATE coaching_recording_stitches
+SET status = 'ready', video_id = $2, stitched_at = NOW(), error = NULL, updated_at = NOW()
+WHERE booking_id = $1
+RETURNING *;
+
+-- name: MarkRecordingStitchFailed :exec
+-- Keeps the stitched file, if there is one, so a retry only imports it again.
+-- mux_asset_id is set when the stitch's Mux asset could not be deleted, so a
+-- retry checks on it again instead of losing track of it.
+UPDATE coaching_recording_stitches
+SET status = 'failed', object_name = COALESCE(sqlc.narg(object_name), object_name),
+    mux_asset_id = sqlc.narg(mux_asset_id), mux_playback_id = NULL, error = sqlc.arg(error), updated_at = NOW()
+WHERE booking_id = sqlc.arg(booking_id);
+
+-- name: GetRecordingStitch :one
+SELECT * FROM coaching_recording_stitches WHERE booking_id = $1;
+
+-- name: ListBookingRecordingImports :many
+-- Every part of a booking's recording with the import of each of its files;
+-- a part without files yet has a single row with no import.
+SELECT recording.id AS recording_id, recording.part_number, recording.status AS recording_status,
+       recording.error AS recording_error, recording.started_at, recording.stopped_at,
+       recording_import.file_index, recording_import.status AS import_status,
+       recording_import.attempts AS import_attempts, recording_import.error AS import_error,
+       recording_import.video_id
+FROM coaching_booking_recordings recording
+LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
+WHERE recording.booking_id = $1
+ORDER BY recording.part_number, recording_import.file_index;
+
 -- name: ListMyBookings :many
 SELECT cb.*, cst.name AS session_type_name,
        COALESCE(latest.recording_status, '')::varchar AS recording_status,
-       latest.video_id AS recording_video_id
+       latest.video_id AS recording_video_id,
+       pending_reschedule.id AS reschedule_id,
+       pending_reschedule.proposed_by AS reschedule_proposed_by,
+       pending_reschedule.scheduled_at AS reschedule_scheduled_at
 FROM coaching_bookings cb
 JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 LEFT JOIN LATERAL (
     SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
-           recording_import.video_id
+           COALESCE(stitch.video_id, recording_import.video_id) AS video_id
     FROM coaching_booking_recordings recording
     LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
-    WHERE recording.booking_id = cb.id
+    LEFT JOIN coaching_recording_stitches stitch
+        ON stitch.booking_id = recording.booking_id AND stitch.status = 'ready'
+    WHERE recording.booking_id = COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = cb.class_id AND host.class_seat = 1), cb.id
+    )
     ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
     LIMIT 1
 ) latest ON true
+LEFT JOIN coaching_booking_reschedules pending_reschedule
+    ON pending_reschedule.booking_id = cb.id AND pending_reschedule.status = 'pending'
 WHERE (cb.expert_id = $1 OR cb.student_id = $1) AND cb.group_id = $2
 ORDER BY cb.scheduled_at DESC;
 
 -- name: ListGroupBookings :many
 SELECT cb.*, cst.name AS session_type_name,
        COALESCE(latest.recording_status, '')::varchar AS recording_status,
-       latest.video_id AS recording_video_id
+       latest.video_id AS recording_video_id,
+       pending_reschedule.id AS reschedule_id,
+       pending_reschedule.proposed_by AS reschedule_proposed_by,
+       pending_reschedule.scheduled_at AS reschedule_scheduled_at
 FROM coaching_bookings cb
 JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 LEFT JOIN LATERAL (
     SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
-           recording_import.video_id
+           COALESCE(stitch.video_id, recording_import.video_id) AS video_id
     FROM coaching_booking_recordings recording
     LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
-    WHERE recording.booking_id = cb.id
+    LEFT JOIN coaching_recording_stitches stitch
+        ON stitch.booking_id = recording.booking_id AND stitch.status = 'ready'
+    WHERE recording.booking_id = COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = cb.class_id AND host.class_seat = 1), cb.id
+    )
     ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
     LIMIT 1
 ) latest ON true
+LEFT JOIN coaching_booking_reschedules pending_reschedule
+    ON pending_reschedule.booking_id = cb.id AND pending_reschedule.status = 'pending'
 WHERE cb.group_id = $1
 ORDER BY cb.scheduled_at;
 
 -- name: ListAllMyBookings :many
 SELECT cb.*, cst.name AS session_type_name,
        COALESCE(latest.recording_status, '')::varchar AS recording_status,
-       latest.video_id AS recording_video_id
+       latest.video_id AS recording_video_id,
+       pending_reschedule.id AS reschedule_id,
+       pending_reschedule.proposed_by AS reschedule_proposed_by,
+       pending_reschedule.scheduled_at AS reschedule_scheduled_at
 FROM coaching_bookings cb
 JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 LEFT JOIN LATERAL (
     SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
-           recording_import.video_id
+           COALESCE(stitch.video_id, recording_import.video_id) AS video_id
     FROM coaching_booking_recordings recording
     LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
-    WHERE recording.booking_id = cb.id
+    LEFT JOIN coaching_recording_stitches stitch
+        ON stitch.booking_id = recording.booking_id AND stitch.status = 'ready'
+    WHERE recording.booking_id = COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = cb.class_id AND host.class_seat = 1), cb.id
+    )
     ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
     LIMIT 1
 ) latest ON true
+LEFT JOIN coaching_booking_reschedules pending_reschedule
+    ON pending_reschedule.booking_id = cb.id AND pending_reschedule.status = 'pending'
 WHERE cb.expert_id = $1 OR cb.student_id = $1
 ORDER BY cb.scheduled_at ASC;
 
 -- name: CancelBooking :one
+-- No row when the booking is already cancelled, so a repeated cancellation
+-- cannot return its credit twice.
 UPDATE coaching_bookings
 SET is_cancelled = true,
     cancellation_reason = $2,
     cancelled_by = $3,
     updated_at = NOW()
-WHERE id = $1 AND (expert_id = $4 OR student_id = $4)
+WHERE id = $1 AND (expert_id = $4 OR student_id = $4) AND is_cancelled = false
 RETURNING *;
 
 -- name: CountConflictingBookings :one
+-- $4 excludes the booking being rescheduled; pass NULL when creating a booking.
 SELECT COUNT(*) FROM coaching_bookings
 WHERE expert_id = $1
   AND is_cancelled = false
   AND scheduled_at < $3
-  AND scheduled_at + (duration_minutes * interval '1 minute') > $2;
+  AND scheduled_at + (duration_minutes * interval '1 minute') > $2
+  AND id IS DISTINCT FROM $4;
+
+-- name: CountBookingsWithinBuffers :one
+-- Active bookings closer to the interval than the larger of the two buffers
+-- between them. @exclude_id excludes the booking being rescheduled.
+SELECT COUNT(*) FROM coaching_bookings
+WHERE expert_id = @expert_id
+  AND is_cancelled = false
+  AND scheduled_at - make_interval(mins => GREATEST(buffer_before_minutes, @buffer_after_minutes::int)) < @ends_at
+  AND scheduled_at + make_interval(mins => duration_minutes + GREATEST(buffer_after_minutes, @buffer_before_minutes::int)) > @starts_at
+  AND id IS DISTINCT FROM @exclude_id;
+
+-- name: CountBookingsStartingInRange :one
+-- Active sessions of the expert starting in [from_at, to_at), for daily caps.
+-- The bookings of a class count as one session.
+SELECT COUNT(DISTINCT COALESCE(class_id, id)) FROM coaching_bookings
+WHERE expert_id = @expert_id
+  AND is_cancelled = false
+  AND scheduled_at >= @from_at
+  AND scheduled_at < @to_at
+  AND id IS DISTINCT FROM @exclude_id;
+
+-- name: RescheduleBooking :one
+UPDATE coaching_bookings
+SET scheduled_at = $2, updated_at = NOW()
+WHERE id = $1 AND is_cancelled = false
+RETURNING *;
+
+-- === Booking Series ===
+
+-- name: CreateBookingSeries :one
+INSERT INTO coaching_booking_series (
+    expert_id, student_id, group_id, session_type_id, starts_at, timezone,
+    interval_weeks, occurrence_count, until_date, duration_minutes, notes
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: ListActiveSeriesBookingsFrom :many
+SELECT * FROM coaching_bookings
+WHERE series_id = $1 AND scheduled_at >= $2 AND is_cancelled = false
+ORDER BY scheduled_at
+FOR UPDATE;
+
+-- === Booking Reschedules ===
+
+-- name: CreateBookingReschedule :one
+INSERT INTO coaching_booking_reschedules (booking_id, proposed_by, scheduled_at, previous_scheduled_at, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetPendingBookingReschedule :one
+SELECT * FROM coaching_booking_reschedules
+WHERE booking_id = $1 AND status = 'pending';
+
+-- name: WithdrawPendingBookingReschedule :execrows
+UPDATE coaching_booking_reschedules
+SET status = 'withdrawn', responded_by = $2, responded_at = NOW()
+WHERE booking_id = $1 AND status = 'pending';
+
+-- name: RespondToBookingReschedule :one
+UPDATE coaching_booking_reschedules
+SET status = $2, responded_by = $3, responded_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
 
 -- === Booking Reminders ===
 
@@ -400,10 +693,14 @@ INSERT INTO coaching_booking_reminders (booking_id, remind_at)
 VALUES ($1, $2);
 
 -- name: ListPendingReminders :many
+-- Session reminders, and homework due-date reminders where homework_id is set.
 SELECT r.id, r.booking_id, r.remind_at,
-       b.expert_id, b.student_id, b.group_id, b.scheduled_at, b.duration_minutes, b.is_cancelled
+       b.expert_id, b.student_id, b.group_id, b.scheduled_at, b.duration_minutes, b.is_cancelled,
+       r.homework_id, hw.title AS homework_title, hw.due_at AS homework_due_at,
+       hw.completed_at AS homework_completed_at
 FROM coaching_booking_reminders r
 JOIN coaching_bookings b ON b.id = r.booking_id
+LEFT JOIN coaching_homework hw ON hw.id = r.homework_id
 WHERE r.sent_at IS NULL
   AND r.remind_at <= NOW()
 ORDER BY r.remind_at
@@ -411,3 +708,537 @@ LIMIT 100;
 
 -- name: MarkReminderSent :exec
 UPDATE coaching_booking_reminders SET sent_at = NOW() WHERE id = $1;
+
+-- name: DeleteUnsentBookingReminders :exec
+-- Homework reminders are kept: they follow the due date, not the session.
+DELETE FROM coaching_booking_reminders
+WHERE booking_id = $1 AND sent_at IS NULL AND homework_id IS NULL;
+
+-- name: CreateHomeworkReminder :exec
+INSERT INTO coaching_booking_reminders (booking_id, homework_id, remind_at)
+VALUES ($1, $2, $3);
+
+-- name: DeleteUnsentHomeworkReminders :exec
+DELETE FROM coaching_booking_reminders WHERE homework_id = $1 AND sent_at IS NULL;
+
+-- === Calendar Feeds ===
+
+-- name: UpsertCalendarFeed :one
+-- Rotating replaces the token hash, so the previous feed URL stops working.
+INSERT INTO coaching_calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET token_hash = EXCLUDED.token_hash, created_at = NOW(), last_accessed_at = NULL
+RETURNING *;
+
+-- name: GetCalendarFeed :one
+SELECT * FROM coaching_calendar_feeds WHERE user_id = $1;
+
+-- name: TouchCalendarFeedByTokenHash :one
+UPDATE coaching_calendar_feeds SET last_accessed_at = NOW()
+WHERE token_hash = $1
+RETURNING *;
+
+-- name: DeleteCalendarFeed :execrows
+DELETE FROM coaching_calendar_feeds WHERE user_id = $1;
+
+-- name: ListCalendarFeedBookings :many
+-- Cancelled bookings stay in the window so subscribed calendars drop them.
+-- The expert sees a class once, through its host booking: it is cancelled only
+-- when every seat is, and a change to any seat updates it.
+SELECT DISTINCT ON (cb.scheduled_at, e.entry_id)
+       cb.id, cb.class_id, cb.expert_id, cb.student_id, cb.group_id, cb.scheduled_at, cb.duration_minutes,
+       bool_and(cb.is_cancelled) OVER entry AS is_cancelled,
+       cb.created_at, max(cb.updated_at) OVER entry AS updated_at,
+       cst.name AS session_type_name, g.name AS group_name
+FROM coaching_bookings cb
+JOIN coaching_session_types cst ON cst.id = cb.session_type_id
+JOIN groups g ON g.id = cb.group_id
+CROSS JOIN LATERAL (
+    SELECT CASE WHEN cb.expert_id = @user_id THEN COALESCE(cb.class_id, cb.id) ELSE cb.id END AS entry_id
+) e
+WHERE (cb.expert_id = @user_id OR cb.student_id = @user_id)
+  AND cb.scheduled_at >= @from_at
+  AND cb.scheduled_at < @to_at
+WINDOW entry AS (PARTITION BY e.entry_id)
+ORDER BY cb.scheduled_at, e.entry_id, cb.class_seat NULLS FIRST;
+
+-- === External Calendars ===
+
+-- name: CreateExternalCalendar :one
+INSERT INTO coaching_external_calendars (expert_id, label, source_url)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListExternalCalendarsByExpert :many
+SELECT * FROM coaching_external_calendars
+WHERE expert_id = $1
+ORDER BY created_at;
+
+-- name: GetExternalCalendar :one
+SELECT * FROM coaching_external_calendars
+WHERE id = $1 AND expert_id = $2;
+
+-- name: DeleteExternalCalendar :execrows
+DELETE FROM coaching_external_calendars
+WHERE id = $1 AND expert_id = $2;
+
+-- name: ClaimDueExternalCalendars :many
+-- Subscribed calendars not attempted within the refresh interval. Claiming
+-- stamps last_attempted_at so overlapping runs skip them.
+WITH due AS (
+    SELECT id FROM coaching_external_calendars
+    WHERE source_url IS NOT NULL
+      AND (last_attempted_at IS NULL OR last_attempted_at <= NOW() - make_interval(secs => @refresh_seconds::int))
+    ORDER BY last_attempted_at NULLS FIRST
+    FOR UPDATE SKIP LOCKED
+    LIMIT @limit_count
+)
+UPDATE coaching_external_calendars cal
+SET last_attempted_at = NOW()
+FROM due
+WHERE cal.id = due.id
+RETURNING cal.*;
+
+-- name: MarkExternalCalendarSynced :exec
+UPDATE coaching_external_calendars
+SET last_attempted_at = NOW(), last_synced_at = NOW(), last_error = NULL
+WHERE id = $1;
+
+-- name: MarkExternalCalendarFailed :exec
+-- Keeps the busy blocks from the last successful fetch.
+UPDATE coaching_external_calendars
+SET last_attempted_at = NOW(), last_error = $2
+WHERE id = $1;
+
+-- name: DeleteExternalBusyBlocks :exec
+DELETE FROM coaching_external_busy_blocks WHERE calendar_id = $1;
+
+-- name: InsertExternalBusyBlocks :exec
+INSERT INTO coaching_external_busy_blocks (calendar_id, starts_at, ends_at)
+SELECT @calendar_id::uuid, unnest(@starts_at::timestamptz[]), unnest(@ends_at::timestamptz[]);
+
+-- name: ListExternalBusyBlocks :many
+SELECT b.starts_at, b.ends_at
+FROM coaching_external_busy_blocks b
+JOIN coaching_external_calendars cal ON cal.id = b.calendar_id
+WHERE cal.expert_id = @expert_id
+  AND b.starts_at < @to_at
+  AND b.ends_at > @from_at
+ORDER BY b.starts_at;
+
+-- === Waitlist ===
+
+-- name: CreateWaitlistEntry :one
+INSERT INTO coaching_waitlist_entries (student_id, expert_id, group_id, session_type_id, preferred_weekdays)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: InsertWaitlistWindows :exec
+INSERT INTO coaching_waitlist_windows (entry_id, start_time, end_time)
+SELECT @entry_id::uuid, unnest(@start_times::time[]), unnest(@end_times::time[]);
+
+-- name: ListWaitlistWindows :many
+SELECT * FROM coaching_waitlist_windows
+WHERE entry_id = ANY(@entry_ids::uuid[])
+ORDER BY entry_id, start_time;
+
+-- name: GetWaitlistEntry :one
+SELECT * FROM coaching_waitlist_entries
+WHERE id = $1 AND student_id = $2 AND booked_at IS NULL;
+
+-- name: ListMyWaitlistEntries :many
+-- The student's waiting entries in the group with the slot currently held
+-- for them, if any.
+SELECT e.id, e.student_id, e.expert_id, e.group_id, e.session_type_id,
+       e.preferred_weekdays, e.created_at,
+       st.name AS session_type_name,
+       hold.id AS hold_id,
+       hold.scheduled_at AS hold_scheduled_at,
+       hold.duration_minutes AS hold_duration_minutes,
+       hold.expires_at AS hold_expires_at
+FROM coaching_waitlist_entries e
+JOIN coaching_session_types st ON st.id = e.session_type_id
+LEFT JOIN coaching_waitlist_holds hold
+    ON hold.entry_id = e.id AND hold.status = 'offered' AND hold.expires_at > NOW()
+WHERE e.student_id = @student_id AND e.group_id = @group_id AND e.booked_at IS NULL
+ORDER BY e.created_at;
+
+-- name: DeleteWaitlistEntry :execrows
+DELETE FROM coaching_waitlist_entries
+WHERE id = $1 AND student_id = $2 AND booked_at IS NULL;
+
+-- name: ListWaitingEntriesByExpert :many
+-- Entries waiting for the expert without an unexpired hold, oldest first,
+-- for session types that can still be booked.
+SELECT e.* FROM coaching_waitlist_entries e
+JOIN coaching_session_types st ON st.id = e.session_type_id AND st.is_active = true
+WHERE e.expert_id = $1
+  AND e.booked_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM coaching_waitlist_holds h
+      WHERE h.entry_id = e.id AND h.status = 'offered' AND h.expires_at > NOW()
+  )
+ORDER BY e.created_at
+LIMIT $2;
+
+-- name: MarkWaitlistEntryBooked :exec
+UPDATE coaching_waitlist_entries SET booked_at = NOW() WHERE id = $1;
+
+-- name: ReopenWaitlistEntryForBooking :exec
+-- Puts the entry a confirmed hold booked back on the waitlist, in its old
+-- place, when that booking's checkout could not be started. An entry the
+-- student has since replaced stays booked.
+UPDATE coaching_waitlist_entries e
+SET booked_at = NULL
+FROM coaching_waitlist_holds h
+WHERE h.booking_id = $1
+  AND h.entry_id = e.id
+  AND e.booked_at IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM coaching_waitlist_entries w
+      WHERE w.student_id = e.student_id
+        AND w.session_type_id = e.session_type_id
+        AND w.booked_at IS NULL
+  );
+
+-- name: CreateWaitlistHold :one
+INSERT INTO coaching_waitlist_holds (entry_id, expert_id, scheduled_at, duration_minutes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetOfferedWaitlistHold :one
+SELECT * FROM coaching_waitlist_holds
+WHERE entry_id = $1 AND status = 'offered'
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: CountConflictingWaitlistHolds :one
+-- Unexpired holds of the expert overlapping [starts_at, ends_at).
+-- @exclude_id excludes the hold being confirmed.
+SELECT COUNT(*) FROM coaching_waitlist_holds
+WHERE expert_id = @expert_id
+  AND status = 'offered'
+  AND expires_at > NOW()
+  AND scheduled_at < @ends_at
+  AND scheduled_at + (duration_minutes * interval '1 minute') > @starts_at
+  AND id IS DISTINCT FROM @exclude_id;
+
+-- name: ListWaitlistHoldsByExpertInRange :many
+-- Unexpired holds of the expert overlapping [from_at, to_at).
+SELECT * FROM coaching_waitlist_holds
+WHERE expert_id = @expert_id
+  AND status = 'offered'
+  AND expires_at > NOW()
+  AND scheduled_at < @to_at
+  AND scheduled_at + (duration_minutes * interval '1 minute') > @from_at
+ORDER BY scheduled_at;
+
+-- name: RespondToWaitlistHold :one
+UPDATE coaching_waitlist_holds
+SET status = @status, booking_id = @booking_id, responded_at = NOW()
+WHERE id = @id AND status = 'offered'
+RETURNING *;
+
+-- name: ExpireWaitlistHolds :many
+-- Closes offered holds past expires_at so their slots can go to the next
+-- student in line.
+UPDATE coaching_waitlist_holds
+SET status = 'expired', responded_at = NOW()
+WHERE status = 'offered' AND expires_at <= NOW()
+RETURNING *;
+
+-- === Payments ===
+
+-- name: CreateCoachingPayment :one
+INSERT INTO coaching_payments (booking_id, provider, checkout_session_id, amount_cents, currency)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetCoachingPayment :one
+SELECT * FROM coaching_payments WHERE booking_id = $1;
+
+-- name: GetCoachingPaymentByCheckout :one
+SELECT * FROM coaching_payments WHERE provider = $1 AND checkout_session_id = $2;
+
+-- name: GetBookingForPaymentUpdate :one
+SELECT * FROM coaching_bookings WHERE id = $1 FOR UPDATE;
+
+-- name: MarkCoachingPaymentPaid :exec
+UPDATE coaching_payments
+SET provider_payment_id = $2, paid_at = COALESCE(paid_at, NOW())
+WHERE booking_id = $1;
+
+-- name: MarkBookingPaid :one
+-- Pending and expired bookings become paid; a cancelled one is then owed a
+-- refund. No row when the payment was already applied.
+UPDATE coaching_bookings
+SET payment_status = 'paid', updated_at = NOW()
+WHERE id = $1 AND payment_status IN ('pending', 'expired')
+RETURNING *;
+
+-- name: ListUnpaidExpiredBookings :many
+-- Active bookings whose payment hold ran out, locked for expiry.
+SELECT * FROM coaching_bookings
+WHERE payment_status = 'pending'
+  AND is_cancelled = false
+  AND payment_expires_at <= NOW()
+ORDER BY payment_expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: ExpireBookingPayment :one
+-- Cancels an active booking still waiting for its payment.
+UPDATE coaching_bookings
+SET is_cancelled = true,
+    cancellation_reason = 'Payment not completed',
+    payment_status = 'expired',
+    updated_at = NOW()
+WHERE id = $1 AND payment_status = 'pending' AND is_cancelled = false
+RETURNING *;
+
+-- name: ListPaymentsAwaitingRefund :many
+-- Payments of cancelled bookings that are still paid, oldest first.
+SELECT p.* FROM coaching_payments p
+JOIN coaching_bookings b ON b.id = p.booking_id
+WHERE b.payment_status = 'paid' AND b.is_cancelled = true
+ORDER BY b.updated_at
+LIMIT $1;
+
+-- name: MarkCoachingPaymentRefunded :exec
+UPDATE coaching_payments
+SET refund_id = $2, refunded_at = NOW()
+WHERE booking_id = $1;
+
+-- name: MarkBookingRefunded :one
+UPDATE coaching_bookings
+SET payment_status = 'refunded', updated_at = NOW()
+WHERE id = $1 AND payment_status = 'paid' AND is_cancelled = true
+RETURNING *;
+
+-- === Credits ===
+
+-- name: CreateCreditGrant :one
+INSERT INTO coaching_credit_grants (student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by)
+VALUES ($1, $2, $3, $4, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ConsumeCredit :one
+-- Takes one credit from the student's grant that expires first. No row when
+-- the student has no usable credit with this expert.
+UPDATE coaching_credit_grants
+SET remaining = remaining - 1, updated_at = NOW()
+WHERE id = (
+    SELECT g.id FROM coaching_credit_grants g
+    WHERE g.student_id = $1
+      AND g.expert_id = $2
+      AND g.group_id = $3
+      AND g.remaining > 0
+      AND (g.expires_at IS NULL OR g.expires_at > NOW())
+    ORDER BY g.expires_at NULLS LAST, g.created_at
+    LIMIT 1
+    FOR UPDATE
+  )
+  AND remaining > 0
+RETURNING *;
+
+-- name: RestoreCredit :one
+-- No row when the grant was never short of this credit or the booking's
+-- credit was already restored.
+UPDATE coaching_credit_grants
+SET remaining = remaining + 1, updated_at = NOW()
+WHERE id = $1 AND remaining < quantity
+  AND NOT EXISTS (
+      SELECT 1 FROM coaching_credit_ledger
+      WHERE booking_id = $2 AND kind = 'restore'
+  )
+RETURNING *;
+
+-- name: ListCreditGrants :many
+-- Grants the user holds as student or issued as expert, optionally narrowed
+-- to one student.
+SELECT * FROM coaching_credit_grants
+WHERE group_id = sqlc.arg(group_id)
+  AND (student_id = sqlc.arg(user_id) OR expert_id = sqlc.arg(user_id))
+  AND (sqlc.arg(student_id)::text = '' OR student_id = sqlc.arg(student_id))
+ORDER BY created_at DESC;
+
+-- name: ListExpiredCreditGrants :many
+-- Grants past their expiry that still hold credits, locked for expiry.
+SELECT * FROM coaching_credit_grants
+WHERE remaining > 0 AND expires_at <= NOW()
+ORDER BY expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: ExpireCreditGrant :one
+UPDATE coaching_credit_grants
+SET remaining = 0, updated_at = NOW()
+WHERE id = $1 AND remaining > 0
+RETURNING *;
+
+-- name: CreateCreditLedgerEntry :one
+INSERT INTO coaching_credit_ledger (grant_id, student_id, expert_id, group_id, booking_id, kind, delta, actor_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListCreditLedger :many
+-- Ledger movements visible to the user as student or expert, newest first.
+SELECT * FROM coaching_credit_ledger
+WHERE group_id = sqlc.arg(group_id)
+  AND (student_id = sqlc.arg(user_id) OR expert_id = sqlc.arg(user_id))
+  AND (sqlc.arg(student_id)::text = '' OR student_id = sqlc.arg(student_id))
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_limit);
+
+-- === Classes ===
+
+-- name: GetClassForSlot :one
+SELECT * FROM coaching_classes
+WHERE session_type_id = $1 AND scheduled_at = $2
+FOR UPDATE;
+
+-- name: CreateClass :one
+INSERT INTO coaching_classes (expert_id, group_id, session_type_id, scheduled_at, duration_minutes, capacity)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetClassSeats :one
+-- Seats taken by active bookings, whether the student holds one of them, and
+-- the last seat number ever handed out in the class.
+SELECT COUNT(*) FILTER (WHERE is_cancelled = false) AS taken,
+       (COUNT(*) FILTER (WHERE is_cancelled = false AND student_id = sqlc.arg(student_id)) > 0)::boolean AS joined,
+       COALESCE(MAX(class_seat), 0)::int AS last_seat
+FROM coaching_bookings
+WHERE class_id = sqlc.arg(class_id);
+
+-- name: ListOpenClasses :many
+-- Classes of the session type starting in the range that hold at least one
+-- active booking and still have a seat left, with the seats taken.
+SELECT c.*, COUNT(cb.id) AS taken
+FROM coaching_classes c
+JOIN coaching_bookings cb ON cb.class_id = c.id AND cb.is_cancelled = false
+WHERE c.session_type_id = sqlc.arg(session_type_id)
+  AND c.scheduled_at >= sqlc.arg(from_time)
+  AND c.scheduled_at < sqlc.arg(to_time)
+GROUP BY c.id
+HAVING COUNT(cb.id) < c.capacity
+ORDER BY c.scheduled_at;
+
+-- name: GetClassHostBooking :one
+SELECT * FROM coaching_bookings WHERE class_id = $1 AND class_seat = 1;
+
+-- name: ListClassAttendees :many
+SELECT * FROM coaching_bookings
+WHERE class_id = $1 AND is_cancelled = false
+ORDER BY class_seat;
+
+-- name: AssignClassRecordingAsset :exec
+-- Gives every active attendee of the class the recording asset on their own
+-- booking and lets them watch it.
+WITH assigned AS (
+    UPDATE coaching_bookings
+    SET recording_asset_id = COALESCE(recording_asset_id, sqlc.arg(asset_id)), updated_at = NOW()
+    WHERE class_id = sqlc.arg(class_id) AND is_cancelled = false
+    RETURNING student_id
+)
+INSERT INTO asset_viewers (asset_id, user_id)
+SELECT sqlc.arg(asset_id), student_id FROM assigned
+ON CONFLICT DO NOTHING;
+
+-- === Session Notes & Homework ===
+
+-- name: GetSessionNotes :one
+SELECT * FROM coaching_session_notes WHERE booking_id = $1;
+
+-- name: UpsertSessionNotes :one
+INSERT INTO coaching_session_notes (booking_id, author_id, summary, went_well, to_improve, next_focus)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (booking_id) DO UPDATE SET
+    author_id = EXCLUDED.author_id,
+    summary = EXCLUDED.summary,
+    went_well = EXCLUDED.went_well,
+    to_improve = EXCLUDED.to_improve,
+    next_focus = EXCLUDED.next_focus,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: CreateHomework :one
+INSERT INTO coaching_homework (booking_id, student_id, expert_id, group_id, title, description, due_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetHomework :one
+SELECT * FROM coaching_homework WHERE id = $1 AND group_id = $2;
+
+-- name: CountBookingHomework :one
+SELECT COUNT(*) FROM coaching_homework WHERE booking_id = $1;
+
+-- name: UpdateHomework :one
+UPDATE coaching_homework
+SET title = $2, description = $3, due_at = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetHomeworkCompleted :one
+-- Marks the homework done, or open again when completed is false.
+UPDATE coaching_homework
+SET completed_at = CASE WHEN sqlc.arg(completed)::boolean THEN COALESCE(completed_at, NOW()) END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteHomework :exec
+DELETE FROM coaching_homework WHERE id = $1;
+
+-- name: ListBookingHomework :many
+SELECT * FROM coaching_homework
+WHERE booking_id = $1
+ORDER BY created_at;
+
+-- name: ListHomework :many
+-- Homework the user assigned or was assigned in the group, open items first
+-- by due date, with the session it came from and that session's recording.
+SELECT hw.*, b.scheduled_at AS session_scheduled_at, b.recording_asset_id
+FROM coaching_homework hw
+JOIN coaching_bookings b ON b.id = hw.booking_id
+WHERE hw.group_id = sqlc.arg(group_id)
+  AND (hw.student_id = sqlc.arg(user_id) OR hw.expert_id = sqlc.arg(user_id))
+  AND (NOT sqlc.arg(open_only)::boolean OR hw.completed_at IS NULL)
+ORDER BY hw.completed_at IS NOT NULL, hw.due_at NULLS LAST, hw.created_at
+LIMIT sqlc.arg(page_limit);
+
+-- === Booking Chat ===
+
+-- name: GetBookingForMessageUpdate :one
+-- Locks the call's host booking so posts to its chat are counted one at a time.
+SELECT * FROM coaching_bookings WHERE id = $1 FOR UPDATE;
+
+-- name: CreateBookingMessage :one
+INSERT INTO coaching_booking_messages (booking_id, author_id, author_role, body, links)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetBookingMessage :one
+SELECT * FROM coaching_booking_messages WHERE id = $1;
+
+-- name: CountBookingMessages :one
+SELECT COUNT(*) FROM coaching_booking_messages WHERE booking_id = $1;
+
+-- name: ListBookingMessages :many
+SELECT * FROM coaching_booking_messages
+WHERE booking_id = $1
+ORDER BY created_at, id;
+
+-- name: ListRecordingAssetMessages :many
+-- Chat of the call the asset recorded. Messages live on the call's host
+-- booking, which for a class is its first seat.
+SELECT m.* FROM coaching_booking_messages m
+WHERE m.booking_id IN (
+    SELECT COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = b.class_id AND host.class_seat = 1), b.id
+    )
+    FROM coaching_bookings b
+    WHERE b.recording_asset_id = $1
+)
+ORDER BY m.created_at, m.id;
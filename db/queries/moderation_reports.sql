@@ -92,3 +92,15 @@ SET
   discord_error = $2,
   updated_at = NOW()
 WHERE id = $1;
+
+-- name: ClearAssetModerationTargets :exec
+-- Reported review text goes with the purged reviews; the report itself stays
+-- as moderation history.
+UPDATE moderation_reports
+SET target_review_content = '', updated_at = NOW()
+WHERE target_video_id IN (SELECT id FROM videos WHERE asset_id = $1);
+
+-- name: ClearVideoModerationTargets :exec
+UPDATE moderation_reports
+SET target_review_content = '', updated_at = NOW()
+WHERE target_video_id = $1;
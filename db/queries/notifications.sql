@@ -34,3 +34,15 @@ WHERE recipient_id = @recipient_id
   AND type = 'group_invitation_received'
   AND payload->>'code' = @code
   AND read_at IS NULL;
+
+-- name: DeleteAssetNotifications :exec
+-- Drops notifications that deep-link to a purged asset.
+DELETE FROM notifications
+WHERE type IN ('video_reviewed', 'video_uploaded', 'review_thread_updated', 'review_reaction_added')
+  AND payload->>'asset_id' = sqlc.arg(asset_id)::text;
+
+-- name: DeleteVideoNotifications :exec
+-- Drops notifications that deep-link to a purged video of a surviving asset.
+DELETE FROM notifications
+WHERE type IN ('review_thread_updated', 'review_reaction_added')
+  AND payload->>'video_id' = sqlc.arg(video_id)::text;
@@ -20,9 +20,10 @@ SELECT
     COALESCE(SUM(v.duration_seconds), 0)::double precision AS duration_seconds
 FROM assets a
 JOIN groups g ON g.id = a.group_id
-LEFT JOIN videos v ON v.asset_id = a.id
+LEFT JOIN videos v ON v.asset_id = a.id AND v.deleted_at IS NULL
 WHERE g.owner_id = @expert_id
   AND a.status != 'waiting_upload'
+  AND a.deleted_at IS NULL
 GROUP BY a.id, a.name, a.created_at, a.group_id, g.name, a.owner_id, g.owner_id
 ORDER BY a.created_at DESC;
 
@@ -39,14 +40,16 @@ SELECT
     COALESCE(SUM(v.duration_seconds), 0)::double precision AS duration_seconds
 FROM assets a
 JOIN groups g ON g.id = a.group_id
-LEFT JOIN videos v ON v.asset_id = a.id
+LEFT JOIN videos v ON v.asset_id = a.id AND v.deleted_at IS NULL
 WHERE a.owner_id = @student_id
   AND a.status != 'waiting_upload'
+  AND a.deleted_at IS NULL
 GROUP BY a.id, a.name, a.created_at, a.group_id, g.name, a.owner_id, g.owner_id
 ORDER BY a.created_at DESC;
 
 -- name: ReportSessionEventsForExpert :many
--- Past, non-cancelled sessions the expert ran. Title is the session type name.
+-- Past, non-cancelled sessions the expert ran. Title is the session type name;
+-- revenue_cents is the price of paid sessions.
 SELECT
     cb.id AS booking_id,
     COALESCE(cst.name, '') AS title,
@@ -55,17 +58,22 @@ SELECT
     g.name AS group_name,
     cb.student_id,
     cb.expert_id,
-    cb.duration_minutes
+    cb.duration_minutes,
+    CASE WHEN cb.payment_status = 'paid' THEN cb.price_cents ELSE 0 END AS revenue_cents,
+    COALESCE(cb.currency, '') AS currency,
+    cb.attendance_outcome
 FROM coaching_bookings cb
 JOIN groups g ON g.id = cb.group_id
 LEFT JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 WHERE cb.expert_id = @expert_id
   AND cb.is_cancelled = false
+  AND cb.payment_status IS DISTINCT FROM 'pending'
   AND cb.scheduled_at < NOW()
 ORDER BY cb.scheduled_at DESC;
 
 -- name: ReportSessionEventsForStudent :many
--- Past, non-cancelled sessions the student attended. Title is the session type name.
+-- Past, non-cancelled sessions the student attended. Title is the session type
+-- name; revenue_cents is the price of paid sessions.
 SELECT
     cb.id AS booking_id,
     COALESCE(cst.name, '') AS title,
@@ -74,11 +82,15 @@ SELECT
     g.name AS group_name,
     cb.student_id,
     cb.expert_id,
-    cb.duration_minutes
+    cb.duration_minutes,
+    CASE WHEN cb.payment_status = 'paid' THEN cb.price_cents ELSE 0 END AS revenue_cents,
+    COALESCE(cb.currency, '') AS currency,
+    cb.attendance_outcome
 FROM coaching_bookings cb
 JOIN groups g ON g.id = cb.group_id
 LEFT JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 WHERE cb.student_id = @student_id
   AND cb.is_cancelled = false
+  AND cb.payment_status IS DISTINCT FROM 'pending'
   AND cb.scheduled_at < NOW()
 ORDER BY cb.scheduled_at DESC;
@@ -0,0 +1,22 @@
+-- name: AddReviewReaction :execrows
+-- Adding a reaction twice is a no-op; zero rows means it was already there.
+INSERT INTO review_reactions (review_id, user_id, reaction)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveReviewReaction :execrows
+DELETE FROM review_reactions
+WHERE review_id = $1 AND user_id = $2 AND reaction = $3;
+
+-- name: ListVideoReviewReactions :many
+-- Reaction counts per review of a video, flagging the ones user_id added.
+SELECT
+    rr.review_id,
+    rr.reaction,
+    COUNT(*)::int AS count,
+    BOOL_OR(rr.user_id = sqlc.arg(user_id)) AS reacted
+FROM review_reactions rr
+INNER JOIN video_reviews r ON r.id = rr.review_id
+WHERE r.video_id = sqlc.arg(video_id)
+GROUP BY rr.review_id, rr.reaction
+ORDER BY rr.review_id, MIN(rr.created_at), rr.reaction;
@@ -0,0 +1,53 @@
+-- name: ListVisibleSnippets :many
+-- Snippets the user owns plus those shared with any of their groups, most
+-- used first. tag and search narrow the list when set; search matches
+-- literally, ignoring case, so % and _ are not wildcards.
+SELECT s.*
+FROM review_snippets s
+WHERE (s.owner_id = sqlc.arg(user_id)
+       OR s.group_id IN (SELECT ug.group_id FROM user_groups ug WHERE ug.user_id = sqlc.arg(user_id)))
+  AND (sqlc.narg(group_id)::uuid IS NULL OR s.group_id = sqlc.narg(group_id))
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag) = ANY(s.tags))
+  AND (sqlc.narg(search)::text IS NULL
+       OR strpos(lower(s.title), lower(sqlc.narg(search))) > 0
+       OR strpos(lower(s.content), lower(sqlc.narg(search))) > 0)
+ORDER BY s.usage_count DESC, s.updated_at DESC, s.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetVisibleSnippet :one
+SELECT s.*
+FROM review_snippets s
+WHERE s.id = sqlc.arg(id)
+  AND (s.owner_id = sqlc.arg(user_id)
+       OR s.group_id IN (SELECT ug.group_id FROM user_groups ug WHERE ug.user_id = sqlc.arg(user_id)));
+
+-- name: CreateSnippet :one
+INSERT INTO review_snippets (
+    owner_id,
+    group_id,
+    title,
+    content,
+    tags
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: UpdateSnippet :one
+UPDATE review_snippets
+SET group_id = $3,
+    title = $4,
+    content = $5,
+    tags = $6,
+    updated_at = NOW()
+WHERE id = $1 AND owner_id = $2
+RETURNING *;
+
+-- name: DeleteSnippet :execrows
+DELETE FROM review_snippets
+WHERE id = $1 AND owner_id = $2;
+
+-- name: RecordSnippetUse :exec
+UPDATE review_snippets
+SET usage_count = usage_count + 1,
+    last_used_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,43 @@
+-- name: ListVideoChapters :many
+SELECT *
+FROM video_chapters
+WHERE video_id = $1
+ORDER BY start_seconds, created_at;
+
+-- name: GetVideoChapter :one
+SELECT *
+FROM video_chapters
+WHERE id = $1 AND video_id = $2;
+
+-- name: CreateVideoChapter :one
+INSERT INTO video_chapters (
+    video_id,
+    title,
+    start_seconds,
+    end_seconds,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: UpdateVideoChapter :one
+UPDATE video_chapters
+SET title = $3,
+    start_seconds = $4,
+    end_seconds = $5,
+    updated_at = NOW()
+WHERE id = $1 AND video_id = $2
+RETURNING *;
+
+-- name: DeleteVideoChapter :execrows
+DELETE FROM video_chapters
+WHERE id = $1 AND video_id = $2;
+
+-- name: MoveVideoChapters :execrows
+-- Moves the chapters of one video to another, like MoveVideoReviews.
+UPDATE video_chapters
+SET video_id = sqlc.arg(to_video_id),
+    start_seconds = start_seconds + sqlc.arg(offset_seconds)::int,
+    end_seconds = end_seconds + sqlc.arg(offset_seconds)::int,
+    updated_at = NOW()
+WHERE video_id = sqlc.arg(from_video_id);
@@ -4,9 +4,12 @@ INSERT INTO video_reviews (
     content,
     timestamp_seconds,
     parent_id,
-    author_id
+    author_id,
+    annotation,
+    end_seconds,
+    snippet_id
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: ListVideoReviews :many
@@ -15,8 +18,12 @@ SELECT
     r.video_id,
     r.content,
     r.timestamp_seconds,
+    r.end_seconds,
     r.parent_id,
     r.author_id,
+    r.annotation,
+    r.annotation_version,
+    r.thread_state,
     r.created_at,
     r.updated_at,
     up.first_name  AS author_first_name,
@@ -32,9 +39,25 @@ DELETE FROM video_reviews
 WHERE id = $1 AND video_id = $2;
 
 -- name: UpdateVideoReview :one
+-- set_annotation replaces the annotation (NULL removes it) and bumps its
+-- version. A non-NULL expected_annotation_version that no longer matches
+-- updates nothing, so a concurrent edit surfaces as no rows.
 UPDATE video_reviews
-SET content = $2, updated_at = NOW()
-WHERE id = $1 AND video_id = $3
+SET content = sqlc.arg(content),
+    annotation = CASE WHEN sqlc.arg(set_annotation)::boolean THEN sqlc.narg(annotation)::jsonb ELSE annotation END,
+    annotation_version = CASE WHEN sqlc.arg(set_annotation)::boolean THEN annotation_version + 1 ELSE annotation_version END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND video_id = sqlc.arg(video_id)
+  AND (sqlc.narg(expected_annotation_version)::int IS NULL OR annotation_version = sqlc.narg(expected_annotation_version))
+RETURNING *;
+
+-- name: SetReviewThreadState :one
+-- Only top-level reviews carry a thread state; a reply updates nothing.
+UPDATE video_reviews
+SET thread_state = sqlc.arg(thread_state),
+    thread_state_changed_by = sqlc.arg(changed_by),
+    thread_state_changed_at = NOW()
+WHERE id = sqlc.arg(id) AND video_id = sqlc.arg(video_id) AND parent_id IS NULL
 RETURNING *;
 
 -- name: GetAssetStatusByVideoID :one
@@ -56,8 +79,13 @@ SELECT EXISTS (
     FROM videos v
     INNER JOIN assets a ON a.id = v.asset_id
     WHERE v.id = sqlc.arg(video_id)
+      AND v.deleted_at IS NULL
+      AND a.deleted_at IS NULL
       AND (
-        (sqlc.arg(is_student)::boolean AND a.owner_id = sqlc.arg(user_id))
+        (sqlc.arg(is_student)::boolean AND (
+          a.owner_id = sqlc.arg(user_id)
+          OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = sqlc.arg(user_id))
+        ))
         OR (
           NOT sqlc.arg(is_student)::boolean
           AND EXISTS (
@@ -74,7 +102,7 @@ SELECT EXISTS (
 SELECT COUNT(*) as count
 FROM videos v
 LEFT JOIN video_reviews r ON v.id = r.video_id
-WHERE v.asset_id = $1
+WHERE v.asset_id = $1 AND v.deleted_at IS NULL
 GROUP BY v.asset_id
 HAVING COUNT(r.id) = 0
    OR COUNT(DISTINCT v.id) > COUNT(DISTINCT r.video_id);
@@ -84,12 +112,35 @@ SELECT EXISTS (
     SELECT 1
     FROM videos v
     LEFT JOIN video_reviews r ON v.id = r.video_id
-    WHERE v.asset_id = $1
+    WHERE v.asset_id = $1 AND v.deleted_at IS NULL
     GROUP BY v.id
     HAVING COUNT(r.id) = 0
 ) as has_unreviewed;
 
 -- name: GetVideoReview :one
-SELECT id, video_id, parent_id, author_id
+SELECT *
 FROM video_reviews
 WHERE id = $1;
+
+-- name: GetVideoDuration :one
+SELECT duration_seconds
+FROM videos
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: MoveVideoReviews :execrows
+-- Moves the reviews of one video to another that plays the same footage
+-- offset_seconds later, as when recording parts are stitched into one video.
+-- Annotation ranges move along, and their version changes so an editor still
+-- holding the old times cannot write them back.
+UPDATE video_reviews
+SET video_id = sqlc.arg(to_video_id),
+    timestamp_seconds = timestamp_seconds + sqlc.arg(offset_seconds)::int,
+    end_seconds = end_seconds + sqlc.arg(offset_seconds)::int,
+    annotation = CASE WHEN annotation IS NULL THEN NULL ELSE jsonb_set(
+        jsonb_set(annotation, '{start_seconds}',
+            to_jsonb((annotation->>'start_seconds')::numeric + sqlc.arg(offset_seconds)::int)),
+        '{end_seconds}',
+        to_jsonb((annotation->>'end_seconds')::numeric + sqlc.arg(offset_seconds)::int)) END,
+    annotation_version = annotation_version + CASE WHEN annotation IS NULL THEN 0 ELSE 1 END,
+    updated_at = NOW()
+WHERE video_id = sqlc.arg(from_video_id);
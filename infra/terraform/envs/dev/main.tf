@@ -176,6 +176,13 @@ resource "google_storage_bucket_iam_member" "api_recording_storage_viewer" {
   member = "serviceAccount:${local.cloud_run_runtime_service_account}"
 }
 
+# Stitched recordings are written back to the bucket by the API.
+resource "google_storage_bucket_iam_member" "api_recording_storage_creator" {
+  bucket = module.agora_recording_storage.bucket_name
+  role   = "roles/storage.objectCreator"
+  member = "serviceAccount:${local.cloud_run_runtime_service_account}"
+}
+
 resource "google_service_account_iam_member" "api_recording_signed_url_token_creator" {
   service_account_id = "projects/${var.project_id}/serviceAccounts/${local.cloud_run_runtime_service_account}"
   role               = "roles/iam.serviceAccountTokenCreator"
@@ -271,6 +278,91 @@ resource "google_cloud_scheduler_job" "coaching_recordings_cleanup" {
   }
 }
 
+resource "google_cloud_scheduler_job" "coaching_external_calendars_sync" {
+  name             = "coaching-external-calendars-sync"
+  region           = var.region
+  schedule         = "*/15 * * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "300s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/coaching/external-calendars/sync"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
+resource "google_cloud_scheduler_job" "coaching_waitlist_process" {
+  name             = "coaching-waitlist-process"
+  region           = var.region
+  schedule         = "*/5 * * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "120s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/coaching/waitlist/process"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
+resource "google_cloud_scheduler_job" "coaching_payments_process" {
+  name             = "coaching-payments-process"
+  region           = var.region
+  schedule         = "*/5 * * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "120s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/coaching/payments/process"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
+resource "google_cloud_scheduler_job" "coaching_credits_expire" {
+  name             = "coaching-credits-expire"
+  region           = var.region
+  schedule         = "0 * * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "60s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/coaching/credits/expire"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
+resource "google_cloud_scheduler_job" "coaching_attendance_process" {
+  name             = "coaching-attendance-process"
+  region           = var.region
+  schedule         = "*/5 * * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "120s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/coaching/attendance/process"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
 resource "google_cloud_scheduler_job" "audit_maintenance" {
   name             = "audit-maintenance"
   region           = var.region
@@ -288,6 +380,23 @@ resource "google_cloud_scheduler_job" "audit_maintenance" {
   }
 }
 
+resource "google_cloud_scheduler_job" "audit_verify" {
+  name             = "audit-verify"
+  region           = var.region
+  schedule         = "30 3 * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "300s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/audit/verify"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
 resource "google_cloud_scheduler_job" "inbound_email_reconcile" {
   name             = "inbound-email-reconcile"
   region           = var.region
@@ -305,6 +414,23 @@ resource "google_cloud_scheduler_job" "inbound_email_reconcile" {
   }
 }
 
+resource "google_cloud_scheduler_job" "assets_purge" {
+  name             = "assets-purge"
+  region           = var.region
+  schedule         = "15 * * * *"
+  time_zone        = "UTC"
+  attempt_deadline = "120s"
+  depends_on       = [module.github_wif]
+
+  http_target {
+    uri         = "${module.cloud_run_dev.service_url}/internal/assets/purge"
+    http_method = "POST"
+    headers = {
+      "Authorization" = "Bearer ${var.scheduler_secret}"
+    }
+  }
+}
+
 output "dashboard_domain" {
   value = local.dashboard_domain
 }
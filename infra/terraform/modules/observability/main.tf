@@ -624,7 +624,7 @@ resource "google_monitoring_alert_policy" "critical_application_log" {
         "resource.type=\"cloud_run_revision\"",
         "resource.labels.service_name=\"${var.api_service_name}\"",
         "severity>=ERROR",
-        "jsonPayload.message=(\"inbound_email_webhook_persist_failed\" OR \"recording_import_process_failed\" OR \"recording_import_cleanup_process_failed\" OR \"audit_ensure_partitions_failed\" OR \"api_listen_failed\")",
+        "jsonPayload.message=(\"inbound_email_webhook_persist_failed\" OR \"recording_import_process_failed\" OR \"recording_import_cleanup_process_failed\" OR \"recording_stitch_process_failed\" OR \"recording_stitch_cleanup_process_failed\" OR \"audit_ensure_partitions_failed\" OR \"api_listen_failed\")",
       ])
     }
   }
@@ -10,10 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/logger"
 	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
 	"github.com/OZIOisgood/zeta/internal/tools"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -30,13 +32,14 @@ type SessionRefresher interface {
 
 type Handler struct {
 	q         db.Querier
+	tx        audit.Runner
 	workos    auth.UserManagement
 	refresher SessionRefresher
 	logger    *slog.Logger
 }
 
-func NewHandler(q db.Querier, workos auth.UserManagement, refresher SessionRefresher, logger *slog.Logger) *Handler {
-	return &Handler{q: q, workos: workos, refresher: refresher, logger: logger}
+func NewHandler(q db.Querier, tx audit.Runner, workos auth.UserManagement, refresher SessionRefresher, logger *slog.Logger) *Handler {
+	return &Handler{q: q, tx: tx, workos: workos, refresher: refresher, logger: logger}
 }
 
 // upgradeToExpert changes the user's default-org membership role to expert.
@@ -178,22 +181,47 @@ func (h *Handler) tryGroupRedeem(ctx context.Context, w http.ResponseWriter, r *
 		if inv.Status != db.InvitationStatusPending {
 			return false // already used / not joinable → caller emits the neutral error
 		}
-		if err := h.q.AddUserToGroup(ctx, db.AddUserToGroupParams{UserID: user.ID, GroupID: inv.GroupID}); err != nil {
-			log.ErrorContext(ctx, "access_redeem_add_to_group_failed",
-				slog.String("component", "access"), slog.Any("err", err))
-			http.Error(w, "Failed to join group", http.StatusInternalServerError)
-			return true
-		}
-		// Email-specific invitations are single-use — mark consumed (non-fatal on error,
-		// matching AcceptInvitation which logs and continues).
-		if inv.Email.Valid && strings.TrimSpace(inv.Email.String) != "" {
-			if err := h.q.UpdateGroupInvitationStatus(ctx, db.UpdateGroupInvitationStatusParams{
+		// Email-specific invitations are single-use — consumed in the same
+		// transaction as the membership, matching AcceptInvitation.
+		err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+			if err := tx.AddUserToGroup(ctx, db.AddUserToGroupParams{UserID: user.ID, GroupID: inv.GroupID}); err != nil {
+				return err
+			}
+			groupID := pgutil.UUIDToString(inv.GroupID)
+			if err := tx.Record(ctx, audit.Event{
+				Action:       audit.ActionGroupMembershipAdded,
+				ResourceType: audit.ResourceGroupMembership,
+				ResourceID:   user.ID,
+				GroupID:      groupID,
+				NewValues:    audit.GroupMembershipSnapshotOf(user.ID, inv.GroupID, "access_code"),
+			}); err != nil {
+				return err
+			}
+			if !invitationHasEmail(inv) {
+				return nil
+			}
+			if err := tx.UpdateGroupInvitationStatus(ctx, db.UpdateGroupInvitationStatusParams{
 				ID:     inv.ID,
 				Status: db.InvitationStatusAccepted,
 			}); err != nil {
-				log.ErrorContext(ctx, "access_redeem_invitation_status_update_failed",
-					slog.String("component", "access"), slog.Any("err", err))
+				return err
 			}
+			accepted := inv
+			accepted.Status = db.InvitationStatusAccepted
+			return tx.Record(ctx, audit.Event{
+				Action:       audit.ActionGroupInviteAccepted,
+				ResourceType: audit.ResourceGroupInvite,
+				ResourceID:   pgutil.UUIDToString(inv.ID),
+				GroupID:      groupID,
+				OldValues:    audit.GroupInviteSnapshotOf(inv),
+				NewValues:    audit.GroupInviteSnapshotOf(accepted),
+			})
+		})
+		if err != nil {
+			log.ErrorContext(ctx, "access_redeem_add_to_group_failed",
+				slog.String("component", "access"), slog.Any("err", err))
+			http.Error(w, "Failed to join group", http.StatusInternalServerError)
+			return true
 		}
 	}
 
@@ -7,8 +7,10 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	authmocks "github.com/OZIOisgood/zeta/internal/auth/mocks"
 	"github.com/OZIOisgood/zeta/internal/db"
@@ -48,7 +50,7 @@ func TestRedeemExpertCodeUpgradesRole(t *testing.T) {
 	workos.EXPECT().UpdateOrganizationMembership(gomock.Any(), "om_1", usermanagement.UpdateOrganizationMembershipOpts{RoleSlug: permissions.RoleExpert}).Return(usermanagement.OrganizationMembership{}, nil)
 	q.EXPECT().ActivateUserAccess(gomock.Any(), gomock.Any()).Return(db.UserAccess{Status: db.AccessStatusActive}, nil)
 
-	h := NewHandler(q, workos, ref, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), workos, ref, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_new", permissions.RoleStudent, "EXPERT01"))
 
@@ -78,7 +80,7 @@ func TestRedeemGroupCodeActivatesStudent(t *testing.T) {
 	q.EXPECT().GetGroup(gomock.Any(), gomock.Any()).Return(db.Group{Name: "Training group"}, nil)
 	q.EXPECT().ActivateUserAccess(gomock.Any(), gomock.Any()).Return(db.UserAccess{Status: db.AccessStatusActive}, nil)
 
-	h := NewHandler(q, workos, &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), workos, &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_s", permissions.RoleStudent, "GRP123"))
 
@@ -111,7 +113,7 @@ func TestRedeemNormalizesCodeInput(t *testing.T) {
 	q.EXPECT().GetGroup(gomock.Any(), gomock.Any()).Return(db.Group{Name: "Training group"}, nil)
 	q.EXPECT().ActivateUserAccess(gomock.Any(), gomock.Any()).Return(db.UserAccess{Status: db.AccessStatusActive}, nil)
 
-	h := NewHandler(q, workos, &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), workos, &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_n", permissions.RoleStudent, " grp-123 "))
 
@@ -131,7 +133,7 @@ func TestRedeemConsumedEmailInviteIsRejected(t *testing.T) {
 	q.EXPECT().CheckUserGroup(gomock.Any(), gomock.Any()).Return(false, nil)
 	// No AddUserToGroup / ActivateUserAccess — a replayed single-use email invite must be rejected.
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_e", permissions.RoleStudent, "EMA11C0DE"))
 
@@ -154,13 +156,17 @@ func TestRedeemPendingEmailInviteMarksAccepted(t *testing.T) {
 	q.EXPECT().GetGroup(gomock.Any(), gomock.Any()).Return(db.Group{Name: "Training group"}, nil)
 	q.EXPECT().ActivateUserAccess(gomock.Any(), gomock.Any()).Return(db.UserAccess{Status: db.AccessStatusActive}, nil)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_p", permissions.RoleStudent, "EMA11PEND"))
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("status = %d, want 200; body=%s", rec.Code, rec.Body.String())
 	}
+	if got := strings.Join(runner.Actions(), ","); got != "group_membership.added,group_invite.accepted" {
+		t.Errorf("audit actions = %q", got)
+	}
 }
 
 func TestRedeemEmailInviteRejectsDifferentRecipient(t *testing.T) {
@@ -171,7 +177,7 @@ func TestRedeemEmailInviteRejectsDifferentRecipient(t *testing.T) {
 	q.EXPECT().GetGroupInvitationByCode(gomock.Any(), "EMA11PEND").Return(
 		db.GroupInvitation{Email: pgtype.Text{String: "invited@example.com", Valid: true}, Status: db.InvitationStatusPending}, nil)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_wrong", permissions.RoleStudent, "EMA11PEND"))
 
@@ -188,7 +194,7 @@ func TestRedeemInvalidCodeReturnsNeutralError(t *testing.T) {
 	q.EXPECT().ConsumeSignupCode(gomock.Any(), gomock.Any()).Return(db.SignupCode{}, pgx.ErrNoRows)
 	q.EXPECT().GetGroupInvitationByCode(gomock.Any(), "N0PE").Return(db.GroupInvitation{}, pgx.ErrNoRows)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_x", permissions.RoleStudent, "N0PE"))
 
@@ -204,7 +210,7 @@ func TestRedeemRejectsEmptyCode(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_empty", permissions.RoleStudent, "   "))
 
@@ -218,7 +224,7 @@ func TestRedeemAlreadyActiveIsNoOp(t *testing.T) {
 	q := dbmocks.NewMockQuerier(ctrl)
 	q.EXPECT().GetUserAccess(gomock.Any(), "user_a").Return(db.UserAccess{Status: db.AccessStatusActive}, nil)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("user_a", permissions.RoleExpert, "EXPERT01"))
 
@@ -250,7 +256,7 @@ func TestRedeemActiveStudentExpertCodeUpgradesRole(t *testing.T) {
 	q.EXPECT().ActivateUserAccess(gomock.Any(), gomock.Any()).Return(
 		db.UserAccess{UserID: "student_active", Status: db.AccessStatusActive}, nil)
 
-	h := NewHandler(q, workos, ref, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), workos, ref, slog.Default())
 	rec := httptest.NewRecorder()
 	h.Redeem(rec, redeemRequestFor("student_active", permissions.RoleStudent, "EXPERT01"))
 
@@ -279,7 +285,7 @@ func TestListCodesReturnsPersonalAllowance(t *testing.T) {
 		Return(db.SignupCode{Status: db.SignupCodeStatusAvailable}, nil)
 	q.EXPECT().ListSignupCodesByOwner(gomock.Any(), "exp_1").Return(complete, nil)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	req := httptest.NewRequest(http.MethodGet, "/access/codes", nil).
 		WithContext(context.WithValue(context.Background(), auth.UserKey, &auth.UserContext{ID: "exp_1", Permissions: []string{permissions.AccessInviteCodesRead}}))
 	rec := httptest.NewRecorder()
@@ -307,7 +313,7 @@ func TestPreviewGroupInvitationForWaitlistedRecipient(t *testing.T) {
 	q.EXPECT().GetGroup(gomock.Any(), groupID).Return(db.Group{ID: groupID, Name: "Morning training", Avatar: "avatar"}, nil)
 	q.EXPECT().CheckUserGroup(gomock.Any(), db.CheckUserGroupParams{UserID: "wait_1", GroupID: groupID}).Return(false, nil)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	router := chi.NewRouter()
 	router.Get("/access/group-invitations/{code}", h.PreviewGroupInvitation)
 	req := httptest.NewRequest(http.MethodGet, "/access/group-invitations/GR0UP123", nil).
@@ -327,7 +333,7 @@ func TestRequireActiveAccessBlocksWaitlisted(t *testing.T) {
 	q := dbmocks.NewMockQuerier(ctrl)
 	q.EXPECT().GetUserAccess(gomock.Any(), "wl_1").Return(db.UserAccess{Status: db.AccessStatusWaitlisted}, nil)
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
 	req := httptest.NewRequest(http.MethodGet, "/groups", nil).
 		WithContext(context.WithValue(context.Background(), auth.UserKey, &auth.UserContext{ID: "wl_1", Role: permissions.RoleStudent}))
@@ -345,7 +351,7 @@ func TestRequireActiveAccessAllowsActiveAndAdmin(t *testing.T) {
 	q.EXPECT().GetUserAccess(gomock.Any(), "act_1").Return(db.UserAccess{Status: db.AccessStatusActive}, nil)
 	// Admin path makes no DB call (no EXPECT for adm_1).
 
-	h := NewHandler(q, authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), authmocks.NewMockUserManagement(ctrl), &fakeRefresher{}, slog.Default())
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
 
 	for _, tc := range []struct{ id, role string }{{"act_1", permissions.RoleStudent}, {"adm_1", permissions.RoleAdmin}} {
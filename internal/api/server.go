@@ -31,6 +31,7 @@ import (
 	"github.com/OZIOisgood/zeta/internal/push"
 	"github.com/OZIOisgood/zeta/internal/reports"
 	"github.com/OZIOisgood/zeta/internal/reviews"
+	"github.com/OZIOisgood/zeta/internal/snippets"
 	"github.com/OZIOisgood/zeta/internal/users"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -84,20 +85,26 @@ func (s *Server) routes(ctx context.Context) {
 	notifications.SetNotifier(push.NewSender(queries, s.Logger))
 
 	auditRetention := time.Duration(parseIntOrDefault(os.Getenv("AUDIT_RETENTION_DAYS"), audit.DefaultRetentionDays)) * 24 * time.Hour
-	auditHandler := audit.NewHandler(s.Pool, s.Logger, auditRetention)
+	auditHandler := audit.NewHandler(s.Pool, queries, s.Logger, auditRetention)
+	// Mutating handlers write through auditRunner so each change and its audit
+	// event commit in one transaction.
+	auditRunner := audit.NewRunner(s.Pool, audit.NewRecorder())
 
 	// Initialize Handlers
 	workosClient := auth.NewWorkOSClient()
 	authHandler := auth.NewHandler(s.Logger, queries, workosClient)
-	accessHandler := access.NewHandler(queries, workosClient, authHandler, s.Logger)
+	authHandler.SetProfileWriter(audit.NewProfileWriter(auditRunner))
+	accessHandler := access.NewHandler(queries, auditRunner, workosClient, authHandler, s.Logger)
 	emailService := email.NewService(s.Logger)
 	llmService := llm.NewService(s.Logger)
 	muxClient := assets.NewMuxClient()
-	assetsHandler := assets.NewHandler(queries, muxClient, emailService, workosClient, s.Logger)
-	groupsHandler := groups.NewHandler(queries, s.Logger)
-	invitationsHandler := invitations.NewHandler(queries, emailService, workosClient, s.Logger, frontendBaseURL())
-	reviewsHandler := reviews.NewHandler(queries, s.Logger, llmService)
-	usersHandler := users.NewHandler(s.Logger, queries, emailService, workosClient)
+	assetsHandler := assets.NewHandler(queries, auditRunner, muxClient, emailService, workosClient, s.Logger)
+	muxWebhookHandler := assets.NewWebhookHandler(queries, os.Getenv("MUX_WEBHOOK_SIGNING_SECRET"), s.Logger)
+	groupsHandler := groups.NewHandler(queries, auditRunner, s.Logger)
+	invitationsHandler := invitations.NewHandler(queries, auditRunner, emailService, workosClient, s.Logger, frontendBaseURL())
+	reviewsHandler := reviews.NewHandler(queries, auditRunner, s.Logger, llmService)
+	snippetsHandler := snippets.NewHandler(queries, s.Logger)
+	usersHandler := users.NewHandler(s.Logger, queries, auditRunner, emailService, workosClient)
 	reportsHandler := reports.NewHandler(queries, s.Logger)
 	devicesHandler := devices.NewHandler(queries, s.Logger)
 	var discordPoster discord.Poster
@@ -158,41 +165,83 @@ func (s *Server) routes(ctx context.Context) {
 	// listener (started below) delivers events to connected SSE clients.
 	notificationsHub := notifications.NewHub()
 	notificationsHandler := notifications.NewHandler(queries, notificationsHub, s.Logger)
-	go notifications.NewListener(s.Pool, queries, notificationsHub, s.Logger).Run(ctx)
+	go notifications.NewNotificationListener(s.Pool, queries, notificationsHub, s.Logger).Run(ctx)
+	// Booking chat streams are fed the same way, from their own channel.
+	chatHub := notifications.NewHub()
+	go coaching.NewChatListener(s.Pool, queries, chatHub, s.Logger).Run(ctx)
 	recordingEnabled := parseBool(os.Getenv("AGORA_CLOUD_RECORDING_ENABLED"))
 	var recordingClient coaching.RecordingClient
 	var recordingStore coaching.RecordingObjectStore
+	var recordingStitcher coaching.RecordingStitcher
 	if recordingEnabled {
-		recordingClient = coaching.NewAgoraCloudRecordingClient(s.Logger, coaching.AgoraCloudRecordingConfig{
-			AppID:              os.Getenv("AGORA_APP_ID"),
-			CustomerID:         os.Getenv("AGORA_REST_CUSTOMER_ID"),
-			CustomerSecret:     os.Getenv("AGORA_REST_CUSTOMER_SECRET"),
-			BaseURL:            os.Getenv("AGORA_CLOUD_RECORDING_BASE_URL"),
-			Mode:               envOrDefault(os.Getenv("AGORA_RECORDING_MODE"), "web"),
-			StorageVendor:      parseIntOrDefault(os.Getenv("AGORA_RECORDING_STORAGE_VENDOR"), 1),
-			StorageRegion:      parseIntOrDefault(os.Getenv("AGORA_RECORDING_STORAGE_REGION"), 0),
-			StorageBucket:      os.Getenv("AGORA_RECORDING_STORAGE_BUCKET"),
-			StorageAccessKey:   os.Getenv("AGORA_RECORDING_STORAGE_ACCESS_KEY"),
-			StorageSecretKey:   os.Getenv("AGORA_RECORDING_STORAGE_SECRET_KEY"),
-			FileNamePrefix:     splitCSV(os.Getenv("AGORA_RECORDING_FILE_PREFIX")),
-			MaxIdleTime:        parseIntOrDefault(os.Getenv("AGORA_RECORDING_MAX_IDLE_TIME"), 30),
-			TranscodingWidth:   parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_WIDTH"), 640),
-			TranscodingHeight:  parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_HEIGHT"), 360),
-			TranscodingBitrate: parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_BITRATE"), 1500),
-			TranscodingFPS:     parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_FPS"), 30),
-		})
-		if bucket := os.Getenv("AGORA_RECORDING_STORAGE_BUCKET"); bucket != "" {
-			store, err := coaching.NewGCSRecordingObjectStore(
-				context.Background(),
-				bucket,
-				os.Getenv("GCS_SIGNING_SERVICE_ACCOUNT"),
-			)
+		recordingConfig := coaching.RecordingConfig{
+			Provider: os.Getenv("RECORDING_PROVIDER"),
+			Store:    os.Getenv("RECORDING_STORE"),
+			Agora: coaching.AgoraCloudRecordingConfig{
+				AppID:              os.Getenv("AGORA_APP_ID"),
+				CustomerID:         os.Getenv("AGORA_REST_CUSTOMER_ID"),
+				CustomerSecret:     os.Getenv("AGORA_REST_CUSTOMER_SECRET"),
+				BaseURL:            os.Getenv("AGORA_CLOUD_RECORDING_BASE_URL"),
+				Mode:               envOrDefault(os.Getenv("AGORA_RECORDING_MODE"), "web"),
+				StorageVendor:      parseIntOrDefault(os.Getenv("AGORA_RECORDING_STORAGE_VENDOR"), 1),
+				StorageRegion:      parseIntOrDefault(os.Getenv("AGORA_RECORDING_STORAGE_REGION"), 0),
+				StorageBucket:      os.Getenv("AGORA_RECORDING_STORAGE_BUCKET"),
+				StorageAccessKey:   os.Getenv("AGORA_RECORDING_STORAGE_ACCESS_KEY"),
+				StorageSecretKey:   os.Getenv("AGORA_RECORDING_STORAGE_SECRET_KEY"),
+				FileNamePrefix:     splitCSV(os.Getenv("AGORA_RECORDING_FILE_PREFIX")),
+				MaxIdleTime:        parseIntOrDefault(os.Getenv("AGORA_RECORDING_MAX_IDLE_TIME"), 30),
+				TranscodingWidth:   parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_WIDTH"), 640),
+				TranscodingHeight:  parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_HEIGHT"), 360),
+				TranscodingBitrate: parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_BITRATE"), 1500),
+				TranscodingFPS:     parseIntOrDefault(os.Getenv("AGORA_RECORDING_TRANSCODING_FPS"), 30),
+			},
+			GCS: coaching.GCSRecordingStoreConfig{
+				Bucket:                os.Getenv("AGORA_RECORDING_STORAGE_BUCKET"),
+				SigningServiceAccount: os.Getenv("GCS_SIGNING_SERVICE_ACCOUNT"),
+			},
+			S3: coaching.S3RecordingStoreConfig{
+				Endpoint:        os.Getenv("RECORDING_S3_ENDPOINT"),
+				Region:          os.Getenv("RECORDING_S3_REGION"),
+				Bucket:          os.Getenv("AGORA_RECORDING_STORAGE_BUCKET"),
+				AccessKeyID:     os.Getenv("AGORA_RECORDING_STORAGE_ACCESS_KEY"),
+				SecretAccessKey: os.Getenv("AGORA_RECORDING_STORAGE_SECRET_KEY"),
+				PathStyle:       parseBool(os.Getenv("RECORDING_S3_PATH_STYLE")),
+			},
+			Local: coaching.LocalRecordingStoreConfig{
+				Dir:           os.Getenv("RECORDING_LOCAL_DIR"),
+				BaseURL:       apiPublicBaseURL(),
+				SigningSecret: os.Getenv("RECORDING_URL_SIGNING_SECRET"),
+			},
+		}
+		if path := os.Getenv("RECORDING_FAKE_SAMPLE_FILE"); path != "" {
+			sample, err := os.ReadFile(path)
 			if err != nil {
-				s.Logger.Error("recording_store_init_failed", slog.Any("err", err))
-			} else {
-				recordingStore = store
+				s.Logger.Error("recording_fake_sample_read_failed", slog.Any("err", err))
 			}
+			recordingConfig.FakeSample = sample
+		}
+		client, store, err := coaching.NewRecordingProvider(context.Background(), s.Logger, recordingConfig)
+		if err != nil {
+			s.Logger.Error("recording_provider_init_failed", slog.Any("err", err))
+		} else {
+			recordingClient, recordingStore = client, store
 		}
+		if parseBool(os.Getenv("RECORDING_STITCH_PARTS")) {
+			recordingStitcher = coaching.NewFFmpegRecordingStitcher(os.Getenv("FFMPEG_PATH"))
+		}
+	}
+	var paymentProvider coaching.PaymentProvider
+	switch os.Getenv("PAYMENT_PROVIDER") {
+	case "stripe":
+		paymentProvider = coaching.NewStripePaymentProvider(coaching.StripeConfig{
+			SecretKey:            os.Getenv("STRIPE_SECRET_KEY"),
+			WebhookSigningSecret: os.Getenv("PAYMENT_WEBHOOK_SIGNING_SECRET"),
+		})
+	case "fake":
+		paymentProvider = coaching.NewFakePaymentProvider(os.Getenv("PAYMENT_WEBHOOK_SIGNING_SECRET"))
+	case "":
+	default:
+		s.Logger.Error("unknown_payment_provider", slog.String("provider", os.Getenv("PAYMENT_PROVIDER")))
 	}
 	coachingHandler := coaching.NewHandler(queries, s.Pool, emailService, workosClient, s.Logger, coaching.HandlerConfig{
 		AgoraAppID:           os.Getenv("AGORA_APP_ID"),
@@ -201,13 +250,19 @@ func (s *Server) routes(ctx context.Context) {
 		RecordingClient:      recordingClient,
 		RecordingStore:       recordingStore,
 		RecordingMux:         muxClient,
+		RecordingStitcher:    recordingStitcher,
 		RecordingEmptyGrace:  parseDurationOrDefault(os.Getenv("AGORA_RECORDING_EMPTY_GRACE"), 60*time.Second),
 		RecordingPresenceTTL: parseDurationOrDefault(os.Getenv("AGORA_RECORDING_PRESENCE_TTL"), 30*time.Second),
 		RecordingEndGrace:    parseDurationOrDefault(os.Getenv("AGORA_RECORDING_END_GRACE"), 15*time.Minute),
 		AppBaseURL:           frontendBaseURL(),
+		APIBaseURL:           apiPublicBaseURL(),
 		MinBookingNotice:     parseDurationOrDefault(os.Getenv("MIN_BOOKING_NOTICE"), 2*time.Hour),
 		CancellationNotice:   parseDurationOrDefault(os.Getenv("CANCELLATION_NOTICE"), 1*time.Hour),
 		ConnectWindow:        parseDurationOrDefault(os.Getenv("CONNECT_WINDOW"), 15*time.Minute),
+		WaitlistHoldTTL:      parseDurationOrDefault(os.Getenv("WAITLIST_HOLD_TTL"), 2*time.Hour),
+		PaymentProvider:      paymentProvider,
+		ChatHub:              chatHub,
+		PaymentHoldTTL:       parseDurationOrDefault(os.Getenv("PAYMENT_HOLD_TTL"), 35*time.Minute),
 		MinSessionDuration:   int32(parseIntOrDefault(os.Getenv("MIN_SESSION_DURATION_MINUTES"), 15)),
 		SessionDurationStep:  int32(parseIntOrDefault(os.Getenv("SESSION_DURATION_STEP_MINUTES"), 5)),
 	})
@@ -222,8 +277,12 @@ func (s *Server) routes(ctx context.Context) {
 		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 	})
 	s.Router.Post("/webhooks/resend", inboundEmailHandler.Webhook)
+	s.Router.Post("/webhooks/mux", muxWebhookHandler.MuxWebhook)
+	s.Router.Post("/webhooks/payments", coachingHandler.PaymentWebhook)
 	s.Router.Post("/public/coaching/recording-renderer/exchange", coachingHandler.ExchangeRecordingRendererCapability)
 	s.Router.Post("/public/coaching/recording-renderer/ready", coachingHandler.MarkRecordingRendererReady)
+	s.Router.Get("/public/coaching/recording-files/*", coachingHandler.ServeRecordingFile)
+	s.Router.Get("/public/coaching/calendar/{token}.ics", coachingHandler.ServeCalendarFeed)
 	s.Router.Route("/contact", contactHandler.RegisterRoutes)
 
 	// Auth Routes
@@ -268,6 +327,7 @@ func (s *Server) routes(ctx context.Context) {
 			r.Route("/assets/videos", reviewsHandler.RegisterRoutes)
 			r.Route("/reviews", func(r chi.Router) {
 				r.Post("/enhance", reviewsHandler.EnhanceText)
+				r.Route("/snippets", snippetsHandler.RegisterRoutes)
 			})
 			r.Route("/groups", func(r chi.Router) {
 				r.Get("/", groupsHandler.ListGroups)
@@ -291,6 +351,7 @@ func (s *Server) routes(ctx context.Context) {
 			r.Route("/feedback", feedbackHandler.RegisterRoutes)
 			r.Route("/moderation", moderationHandler.RegisterRoutes)
 			r.Route("/admin/emails", inboundEmailHandler.RegisterAdminRoutes)
+			r.Route("/admin/audit", auditHandler.RegisterAdminRoutes)
 			reportsHandler.RegisterRoutes(r)
 			coachingHandler.RegisterRoutes(r)
 			devicesHandler.RegisterRoutes(r)
@@ -303,8 +364,15 @@ func (s *Server) routes(ctx context.Context) {
 		r.Post("/internal/coaching/reminders", coachingHandler.ProcessReminders)
 		r.Post("/internal/coaching/recordings/cleanup", coachingHandler.CleanupFinishedRecordings)
 		r.Post("/internal/coaching/recordings/process", coachingHandler.ProcessRecordingImports)
+		r.Post("/internal/coaching/external-calendars/sync", coachingHandler.SyncExternalCalendars)
+		r.Post("/internal/coaching/waitlist/process", coachingHandler.ProcessWaitlistHolds)
+		r.Post("/internal/coaching/payments/process", coachingHandler.ProcessPayments)
+		r.Post("/internal/coaching/credits/expire", coachingHandler.ExpireCredits)
+		r.Post("/internal/coaching/attendance/process", coachingHandler.ProcessAttendance)
 		r.Post("/internal/assets/durations/backfill", assetsHandler.BackfillVideoDurations)
+		r.Post("/internal/assets/purge", assetsHandler.PurgeDeletedAssets)
 		r.Post("/internal/audit/maintenance", auditHandler.RunMaintenance)
+		r.Post("/internal/audit/verify", auditHandler.RunVerify)
 		r.Post("/internal/inbound-email/reconcile", inboundEmailHandler.Reconcile)
 	})
 }
@@ -385,6 +453,14 @@ func splitCSV(s string) []string {
 	return values
 }
 
+// apiPublicBaseURL is the origin calendar apps use to reach this API.
+func apiPublicBaseURL() string {
+	if v := os.Getenv("API_PUBLIC_URL"); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return "http://localhost:8080"
+}
+
 func frontendBaseURL() string {
 	if v := os.Getenv("FRONTEND_URL"); v != "" {
 		return strings.TrimRight(v, "/")
@@ -0,0 +1,375 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+)
+
+const (
+	// deletionGrace is how long a deleted asset or video stays recoverable by
+	// support before the purge job removes it and its Mux asset for good.
+	deletionGrace  = 7 * 24 * time.Hour
+	purgeBatchSize = 50
+)
+
+// canDelete reports whether user may delete the visible asset: its owner, or
+// any non-student who can see it (the group's experts and admins).
+func canDelete(user *auth.UserContext, asset db.GetVisibleAssetRow) bool {
+	return asset.OwnerID == user.ID || !isStudent(user)
+}
+
+// loadDeletableAsset resolves the {id} route param to an asset the caller may
+// delete, writing the error response itself when it returns ok=false.
+func (h *Handler) loadDeletableAsset(w http.ResponseWriter, r *http.Request, user *auth.UserContext) (db.GetVisibleAssetRow, bool) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	idStr := chi.URLParam(r, "id")
+
+	var assetID pgtype.UUID
+	if err := assetID.Scan(idStr); err != nil {
+		http.Error(w, "Invalid video ID", http.StatusBadRequest)
+		return db.GetVisibleAssetRow{}, false
+	}
+	asset, err := h.q.GetVisibleAsset(ctx, db.GetVisibleAssetParams{
+		AssetID:   assetID,
+		UserID:    user.ID,
+		IsStudent: isStudent(user),
+	})
+	if err != nil {
+		log.WarnContext(ctx, "asset_delete_visibility_denied",
+			slog.String("component", "assets"),
+			slog.String("asset_id", idStr),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Video not found", http.StatusNotFound)
+		return db.GetVisibleAssetRow{}, false
+	}
+	if !canDelete(user, asset) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return db.GetVisibleAssetRow{}, false
+	}
+
+	recordingStillOpen, err := h.q.IsRecordingAssetStillOpen(ctx, assetID)
+	if err != nil {
+		log.ErrorContext(ctx, "asset_delete_recording_state_failed",
+			slog.String("component", "assets"), slog.String("asset_id", idStr), slog.Any("err", err))
+		http.Error(w, "Failed to check recording state", http.StatusInternalServerError)
+		return db.GetVisibleAssetRow{}, false
+	}
+	if recordingStillOpen {
+		http.Error(w, "Cannot delete a video while recording parts are still being prepared", http.StatusConflict)
+		return db.GetVisibleAssetRow{}, false
+	}
+	return asset, true
+}
+
+// DeleteAsset handles DELETE /assets/{id}. The asset disappears immediately;
+// its Mux assets, reviews and notifications are purged after deletionGrace.
+func (h *Handler) DeleteAsset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	asset, ok := h.loadDeletableAsset(w, r, user)
+	if !ok {
+		return
+	}
+	assetIDStr := pgutil.UUIDToString(asset.ID)
+	groupIDStr := pgutil.UUIDToString(asset.GroupID)
+
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		videos, err := tx.GetAssetVideos(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		deleted, err := tx.SoftDeleteAsset(ctx, db.SoftDeleteAssetParams{
+			DeletedBy: pgtype.Text{String: user.ID, Valid: true},
+			ID:        asset.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionAssetDeleted,
+			ResourceType: audit.ResourceAsset,
+			ResourceID:   assetIDStr,
+			GroupID:      groupIDStr,
+			OldValues:    audit.AssetSnapshotOf(deleted, len(videos)),
+		})
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Deleted concurrently; the outcome is the one the caller asked for.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "asset_delete_failed",
+			slog.String("component", "assets"),
+			slog.String("asset_id", assetIDStr),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete video", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "asset_deleted",
+		slog.String("component", "assets"),
+		slog.String("asset_id", assetIDStr),
+		slog.String("user_id", user.ID),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var errLastVideo = errors.New("video is the asset's last part")
+
+// DeleteVideo handles DELETE /assets/{id}/videos/{videoID}, removing a single
+// part of a multi-part asset. The last remaining part cannot be deleted on its
+// own; delete the asset instead.
+func (h *Handler) DeleteVideo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	videoIDStr := chi.URLParam(r, "videoID")
+	var videoID pgtype.UUID
+	if err := videoID.Scan(videoIDStr); err != nil {
+		http.Error(w, "Invalid video part ID", http.StatusBadRequest)
+		return
+	}
+
+	asset, ok := h.loadDeletableAsset(w, r, user)
+	if !ok {
+		return
+	}
+	assetIDStr := pgutil.UUIDToString(asset.ID)
+
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		videos, err := tx.GetAssetVideos(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, v := range videos {
+			found = found || v.ID == videoID
+		}
+		if !found {
+			return pgx.ErrNoRows
+		}
+		if len(videos) == 1 {
+			return errLastVideo
+		}
+
+		deleted, err := tx.SoftDeleteVideo(ctx, db.SoftDeleteVideoParams{
+			DeletedBy: pgtype.Text{String: user.ID, Valid: true},
+			ID:        videoID,
+			AssetID:   asset.ID,
+		})
+		if err != nil {
+			return err
+		}
+		// The deleted part may have been the one the upload was waiting on.
+		if _, err := tx.PromoteUploadedAsset(ctx, asset.ID); err != nil {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionVideoDeleted,
+			ResourceType: audit.ResourceVideo,
+			ResourceID:   videoIDStr,
+			GroupID:      pgutil.UUIDToString(asset.GroupID),
+			OldValues:    audit.VideoSnapshotOf(deleted, asset.OwnerID),
+		})
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "Video part not found", http.StatusNotFound)
+		return
+	case errors.Is(err, errLastVideo):
+		http.Error(w, "Cannot delete the only part of a video; delete the video instead", http.StatusConflict)
+		return
+	case err != nil:
+		log.ErrorContext(ctx, "video_delete_failed",
+			slog.String("component", "assets"),
+			slog.String("asset_id", assetIDStr),
+			slog.String("video_id", videoIDStr),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete video part", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "video_deleted",
+		slog.String("component", "assets"),
+		slog.String("asset_id", assetIDStr),
+		slog.String("video_id", videoIDStr),
+		slog.String("user_id", user.ID),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeDeletedAssets permanently removes assets and videos whose grace window
+// has passed: their Mux assets first, then the rows, reviews, notifications
+// that link to them and reported review content. An item whose Mux cleanup
+// fails is left for the next run. Internal endpoint, protected by the
+// scheduler secret.
+func (h *Handler) PurgeDeletedAssets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-deletionGrace), Valid: true}
+
+	assetIDs, err := h.q.ListAssetsDueForPurge(ctx, db.ListAssetsDueForPurgeParams{
+		DeletedBefore: cutoff,
+		BatchLimit:    purgeBatchSize,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "asset_purge_list_failed",
+			slog.String("component", "assets"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list deleted videos", http.StatusInternalServerError)
+		return
+	}
+	videos, err := h.q.ListVideosDueForPurge(ctx, db.ListVideosDueForPurgeParams{
+		DeletedBefore: cutoff,
+		BatchLimit:    purgeBatchSize,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "video_purge_list_failed",
+			slog.String("component", "assets"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list deleted video parts", http.StatusInternalServerError)
+		return
+	}
+
+	assetsPurged, videosPurged, failed := 0, 0, 0
+	for _, id := range assetIDs {
+		if err := h.purgeAsset(ctx, id); err != nil {
+			log.ErrorContext(ctx, "asset_purge_failed",
+				slog.String("component", "assets"),
+				slog.String("asset_id", pgutil.UUIDToString(id)),
+				slog.Any("err", err),
+			)
+			failed++
+			continue
+		}
+		assetsPurged++
+	}
+	for _, v := range videos {
+		if err := h.purgeVideo(ctx, v); err != nil {
+			log.ErrorContext(ctx, "video_purge_failed",
+				slog.String("component", "assets"),
+				slog.String("video_id", pgutil.UUIDToString(v.ID)),
+				slog.Any("err", err),
+			)
+			failed++
+			continue
+		}
+		videosPurged++
+	}
+
+	log.InfoContext(ctx, "asset_purge_completed",
+		slog.String("component", "assets"),
+		slog.Int("assets_purged", assetsPurged),
+		slog.Int("videos_purged", videosPurged),
+		slog.Int("failed", failed),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"assets_purged": assetsPurged,
+		"videos_purged": videosPurged,
+		"failed":        failed,
+		"has_more":      len(assetIDs) >= purgeBatchSize || len(videos) >= purgeBatchSize,
+	})
+}
+
+func (h *Handler) purgeAsset(ctx context.Context, id pgtype.UUID) error {
+	parts, err := h.q.ListAssetMuxIdentifiers(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if err := h.deleteMuxAsset(p.MuxAssetID.String, p.MuxUploadID.String); err != nil {
+			return fmt.Errorf("delete mux asset for video %s: %w", pgutil.UUIDToString(p.ID), err)
+		}
+	}
+	return h.tx.InTx(ctx, func(tx audit.Tx) error {
+		if err := tx.ClearAssetModerationTargets(ctx, id); err != nil {
+			return err
+		}
+		if err := tx.DeleteAssetNotifications(ctx, pgutil.UUIDToString(id)); err != nil {
+			return err
+		}
+		_, err := tx.PurgeAsset(ctx, id)
+		return err
+	})
+}
+
+func (h *Handler) purgeVideo(ctx context.Context, v db.ListVideosDueForPurgeRow) error {
+	if err := h.deleteMuxAsset(v.MuxAssetID.String, v.MuxUploadID.String); err != nil {
+		return fmt.Errorf("delete mux asset: %w", err)
+	}
+	return h.tx.InTx(ctx, func(tx audit.Tx) error {
+		if err := tx.ClearVideoModerationTargets(ctx, v.ID); err != nil {
+			return err
+		}
+		if err := tx.DeleteVideoNotifications(ctx, pgutil.UUIDToString(v.ID)); err != nil {
+			return err
+		}
+		_, err := tx.PurgeVideo(ctx, v.ID)
+		return err
+	})
+}
+
+// deleteMuxAsset removes a video's Mux asset. Direct uploads that never got
+// as far as asset_created in our database are resolved through the upload.
+// An asset Mux no longer knows about counts as deleted.
+func (h *Handler) deleteMuxAsset(muxAssetID, muxUploadID string) error {
+	if muxAssetID == "" && muxUploadID != "" {
+		upload, err := h.mux.GetDirectUpload(muxUploadID)
+		if isMuxNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		muxAssetID = upload.Data.AssetId
+	}
+	if muxAssetID == "" {
+		return nil
+	}
+	if err := h.mux.DeleteAsset(muxAssetID); err != nil && !isMuxNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func isMuxNotFound(err error) bool {
+	var nf muxgo.NotFoundError
+	return errors.As(err, &nf)
+}
@@ -0,0 +1,94 @@
+//go:build integration
+
+package assets_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// A soft-deleted video drops out of every read immediately; the purge query
+// only picks it up once the grace window has passed, and the row goes with
+// its reviews.
+func TestIntegration_SoftDeletedVideoHiddenThenPurged(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Match", GroupID: group.ID, OwnerID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	var parts []db.Video
+	for _, upload := range []string{"upload-1", "upload-2"} {
+		v, err := q.CreateVideo(ctx, db.CreateVideoParams{
+			AssetID:     asset.ID,
+			MuxUploadID: pgtype.Text{String: upload, Valid: true},
+			Status:      db.VideoStatusWaitingUpload,
+		})
+		if err != nil {
+			t.Fatalf("CreateVideo: %v", err)
+		}
+		parts = append(parts, v)
+	}
+
+	deleted, err := q.SoftDeleteVideo(ctx, db.SoftDeleteVideoParams{
+		DeletedBy: pgtype.Text{String: "user-1", Valid: true},
+		ID:        parts[1].ID,
+		AssetID:   asset.ID,
+	})
+	if err != nil || !deleted.DeletedAt.Valid {
+		t.Fatalf("SoftDeleteVideo = %+v, %v", deleted, err)
+	}
+	if _, err := q.SoftDeleteVideo(ctx, db.SoftDeleteVideoParams{ID: parts[1].ID, AssetID: asset.ID}); err == nil {
+		t.Error("second soft delete succeeded")
+	}
+
+	videos, err := q.GetAssetVideos(ctx, asset.ID)
+	if err != nil || len(videos) != 1 || videos[0].ID != parts[0].ID {
+		t.Fatalf("GetAssetVideos = %+v, %v", videos, err)
+	}
+
+	due, err := q.ListVideosDueForPurge(ctx, db.ListVideosDueForPurgeParams{
+		DeletedBefore: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		BatchLimit:    10,
+	})
+	if err != nil || len(due) != 0 {
+		t.Fatalf("purged inside the grace window: %+v, %v", due, err)
+	}
+	due, err = q.ListVideosDueForPurge(ctx, db.ListVideosDueForPurgeParams{
+		DeletedBefore: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+		BatchLimit:    10,
+	})
+	if err != nil || len(due) != 1 || due[0].ID != parts[1].ID {
+		t.Fatalf("ListVideosDueForPurge = %+v, %v", due, err)
+	}
+	if n, err := q.PurgeVideo(ctx, parts[0].ID); err != nil || n != 0 {
+		t.Errorf("PurgeVideo removed a live video: %d, %v", n, err)
+	}
+	if n, err := q.PurgeVideo(ctx, parts[1].ID); err != nil || n != 1 {
+		t.Errorf("PurgeVideo = %d, %v", n, err)
+	}
+
+	if _, err := q.SoftDeleteAsset(ctx, db.SoftDeleteAssetParams{
+		DeletedBy: pgtype.Text{String: "user-1", Valid: true},
+		ID:        asset.ID,
+	}); err != nil {
+		t.Fatalf("SoftDeleteAsset: %v", err)
+	}
+	if _, err := q.GetAsset(ctx, asset.ID); err == nil {
+		t.Error("GetAsset returned a deleted asset")
+	}
+	if n, err := q.PurgeAsset(ctx, asset.ID); err != nil || n != 1 {
+		t.Errorf("PurgeAsset = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,244 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/assets/mocks"
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+	"go.uber.org/mock/gomock"
+)
+
+func deleteTestUUID(b byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+}
+
+func deleteRequest(user *auth.UserContext, params map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodDelete, "/assets", nil)
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(assetTestUserCtx(ctx, user))
+}
+
+func expectDeletableAsset(q *dbmocks.MockQuerier, asset db.GetVisibleAssetRow) {
+	q.EXPECT().GetVisibleAsset(gomock.Any(), gomock.Any()).Return(asset, nil)
+	q.EXPECT().IsRecordingAssetStillOpen(gomock.Any(), asset.ID).Return(false, nil)
+}
+
+func TestDeleteAsset_OwnerSoftDeletesAndRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, nil, nil, nil, slog.Default())
+
+	assetID, groupID := deleteTestUUID(1), deleteTestUUID(2)
+	student := &auth.UserContext{ID: "student-1", Role: permissions.RoleStudent}
+	expectDeletableAsset(q, db.GetVisibleAssetRow{ID: assetID, GroupID: groupID, OwnerID: "student-1"})
+	q.EXPECT().GetAssetVideos(gomock.Any(), assetID).Return([]db.GetAssetVideosRow{{ID: deleteTestUUID(3)}}, nil)
+	q.EXPECT().SoftDeleteAsset(gomock.Any(), db.SoftDeleteAssetParams{
+		DeletedBy: pgtype.Text{String: "student-1", Valid: true},
+		ID:        assetID,
+	}).Return(db.Asset{ID: assetID, Name: "Serve", OwnerID: "student-1", GroupID: groupID}, nil)
+
+	rec := httptest.NewRecorder()
+	h.DeleteAsset(rec, deleteRequest(student, map[string]string{"id": "01000000-0000-0000-0000-000000000000"}))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+	events := runner.Events()
+	if len(events) != 1 || events[0].Action != audit.ActionAssetDeleted || events[0].GroupID != "02000000-0000-0000-0000-000000000000" {
+		t.Fatalf("events = %+v", events)
+	}
+	if snap := events[0].OldValues.(audit.AssetSnapshot); snap.VideoCount != 1 || snap.StudentID != "student-1" {
+		t.Errorf("snapshot = %+v", snap)
+	}
+}
+
+func TestDeleteAsset_OtherStudentForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, nil, nil, nil, slog.Default())
+
+	q.EXPECT().GetVisibleAsset(gomock.Any(), gomock.Any()).Return(db.GetVisibleAssetRow{ID: deleteTestUUID(1), OwnerID: "student-1"}, nil)
+
+	rec := httptest.NewRecorder()
+	h.DeleteAsset(rec, deleteRequest(&auth.UserContext{ID: "student-2", Role: permissions.RoleStudent},
+		map[string]string{"id": "01000000-0000-0000-0000-000000000000"}))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if len(runner.Events()) != 0 {
+		t.Error("audit event recorded for a rejected delete")
+	}
+}
+
+func TestDeleteAsset_OpenRecordingConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), nil, nil, nil, slog.Default())
+
+	assetID := deleteTestUUID(1)
+	q.EXPECT().GetVisibleAsset(gomock.Any(), gomock.Any()).Return(db.GetVisibleAssetRow{ID: assetID, OwnerID: "student-1"}, nil)
+	q.EXPECT().IsRecordingAssetStillOpen(gomock.Any(), assetID).Return(true, nil)
+
+	rec := httptest.NewRecorder()
+	h.DeleteAsset(rec, deleteRequest(&auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert},
+		map[string]string{"id": "01000000-0000-0000-0000-000000000000"}))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestDeleteVideo_ExpertDeletesPart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, nil, nil, nil, slog.Default())
+
+	assetID, videoID := deleteTestUUID(1), deleteTestUUID(3)
+	expectDeletableAsset(q, db.GetVisibleAssetRow{ID: assetID, GroupID: deleteTestUUID(2), OwnerID: "student-1"})
+	q.EXPECT().GetAssetVideos(gomock.Any(), assetID).Return([]db.GetAssetVideosRow{{ID: videoID}, {ID: deleteTestUUID(4)}}, nil)
+	q.EXPECT().SoftDeleteVideo(gomock.Any(), db.SoftDeleteVideoParams{
+		DeletedBy: pgtype.Text{String: "expert-1", Valid: true},
+		ID:        videoID,
+		AssetID:   assetID,
+	}).Return(db.Video{ID: videoID, AssetID: assetID, Status: db.VideoStatusReady}, nil)
+	q.EXPECT().PromoteUploadedAsset(gomock.Any(), assetID).Return(int64(0), nil)
+
+	rec := httptest.NewRecorder()
+	h.DeleteVideo(rec, deleteRequest(&auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}, map[string]string{
+		"id":      "01000000-0000-0000-0000-000000000000",
+		"videoID": "03000000-0000-0000-0000-000000000000",
+	}))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+	if got := runner.Actions(); !slices.Equal(got, []string{audit.ActionVideoDeleted}) {
+		t.Errorf("actions = %v", got)
+	}
+}
+
+func TestDeleteVideo_RejectsLastAndForeignParts(t *testing.T) {
+	cases := []struct {
+		name    string
+		videoID string
+		want    int
+	}{
+		{"last part", "03000000-0000-0000-0000-000000000000", http.StatusConflict},
+		{"part of another asset", "09000000-0000-0000-0000-000000000000", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			runner := audittest.NewRunner(q)
+			h := NewHandler(q, runner, nil, nil, nil, slog.Default())
+
+			assetID := deleteTestUUID(1)
+			expectDeletableAsset(q, db.GetVisibleAssetRow{ID: assetID, OwnerID: "student-1"})
+			q.EXPECT().GetAssetVideos(gomock.Any(), assetID).Return([]db.GetAssetVideosRow{{ID: deleteTestUUID(3)}}, nil)
+
+			rec := httptest.NewRecorder()
+			h.DeleteVideo(rec, deleteRequest(&auth.UserContext{ID: "student-1", Role: permissions.RoleStudent}, map[string]string{
+				"id":      "01000000-0000-0000-0000-000000000000",
+				"videoID": tc.videoID,
+			}))
+
+			if rec.Code != tc.want {
+				t.Fatalf("got %d, want %d", rec.Code, tc.want)
+			}
+			if len(runner.Events()) != 0 {
+				t.Error("audit event recorded for a rejected delete")
+			}
+		})
+	}
+}
+
+func TestPurgeDeletedAssets_RemovesMuxAssetsThenRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	mux := mocks.NewMockMuxClient(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), mux, nil, nil, slog.Default())
+
+	assetID, partID := deleteTestUUID(1), deleteTestUUID(5)
+	q.EXPECT().ListAssetsDueForPurge(gomock.Any(), gomock.Any()).Return([]pgtype.UUID{assetID}, nil)
+	q.EXPECT().ListVideosDueForPurge(gomock.Any(), gomock.Any()).Return([]db.ListVideosDueForPurgeRow{
+		{ID: partID, MuxAssetID: pgtype.Text{String: "mux-gone", Valid: true}},
+	}, nil)
+
+	q.EXPECT().ListAssetMuxIdentifiers(gomock.Any(), assetID).Return([]db.ListAssetMuxIdentifiersRow{
+		{ID: deleteTestUUID(3), MuxAssetID: pgtype.Text{String: "mux-1", Valid: true}},
+		{ID: deleteTestUUID(4), MuxUploadID: pgtype.Text{String: "upload-2", Valid: true}},
+	}, nil)
+	gomock.InOrder(
+		mux.EXPECT().DeleteAsset("mux-1").Return(nil),
+		mux.EXPECT().GetDirectUpload("upload-2").Return(muxgo.UploadResponse{Data: muxgo.Upload{AssetId: "mux-2"}}, nil),
+		mux.EXPECT().DeleteAsset("mux-2").Return(nil),
+		q.EXPECT().ClearAssetModerationTargets(gomock.Any(), assetID).Return(nil),
+		q.EXPECT().DeleteAssetNotifications(gomock.Any(), "01000000-0000-0000-0000-000000000000").Return(nil),
+		q.EXPECT().PurgeAsset(gomock.Any(), assetID).Return(int64(1), nil),
+	)
+
+	// Mux already forgot this one; the purge still goes ahead.
+	mux.EXPECT().DeleteAsset("mux-gone").Return(muxgo.NotFoundError{})
+	q.EXPECT().ClearVideoModerationTargets(gomock.Any(), partID).Return(nil)
+	q.EXPECT().DeleteVideoNotifications(gomock.Any(), "05000000-0000-0000-0000-000000000000").Return(nil)
+	q.EXPECT().PurgeVideo(gomock.Any(), partID).Return(int64(1), nil)
+
+	rec := httptest.NewRecorder()
+	h.PurgeDeletedAssets(rec, httptest.NewRequest(http.MethodPost, "/internal/assets/purge", nil))
+
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["assets_purged"] != float64(1) || resp["videos_purged"] != float64(1) || resp["failed"] != float64(0) {
+		t.Errorf("resp = %v", resp)
+	}
+}
+
+func TestPurgeDeletedAssets_MuxFailureKeepsRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	mux := mocks.NewMockMuxClient(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), mux, nil, nil, slog.Default())
+
+	q.EXPECT().ListAssetsDueForPurge(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListVideosDueForPurge(gomock.Any(), gomock.Any()).Return([]db.ListVideosDueForPurgeRow{
+		{ID: deleteTestUUID(5), MuxAssetID: pgtype.Text{String: "mux-1", Valid: true}},
+	}, nil)
+	mux.EXPECT().DeleteAsset("mux-1").Return(errors.New("mux unavailable"))
+
+	rec := httptest.NewRecorder()
+	h.PurgeDeletedAssets(rec, httptest.NewRequest(http.MethodPost, "/internal/assets/purge", nil))
+
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["videos_purged"] != float64(0) || resp["failed"] != float64(1) {
+		t.Errorf("resp = %v", resp)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/email"
@@ -25,15 +27,17 @@ import (
 
 type Handler struct {
 	q      db.Querier
+	tx     audit.Runner
 	mux    MuxClient
 	email  email.Sender
 	workos auth.UserManagement
 	logger *slog.Logger
 }
 
-func NewHandler(q db.Querier, mux MuxClient, email email.Sender, workos auth.UserManagement, logger *slog.Logger) *Handler {
+func NewHandler(q db.Querier, tx audit.Runner, mux MuxClient, email email.Sender, workos auth.UserManagement, logger *slog.Logger) *Handler {
 	return &Handler{
 		q:      q,
+		tx:     tx,
 		mux:    mux,
 		email:  email,
 		workos: workos,
@@ -63,6 +67,8 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Post("/", h.CreateAsset)
 	r.Get("/", h.ListAssets)
 	r.Get("/{id}", h.GetAsset)
+	r.Delete("/{id}", h.DeleteAsset)
+	r.Delete("/{id}/videos/{videoID}", h.DeleteVideo)
 	r.Post("/{id}/complete", h.CompleteUpload)
 	r.Post("/{id}/finalize", h.FinalizeAsset)
 }
@@ -145,26 +151,55 @@ type StudentInfo struct {
 }
 
 type AssetItem struct {
-	ID          string       `json:"id"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	OwnerID     string       `json:"owner_id"`
-	Status      string       `json:"status"`
-	Thumbnail   string       `json:"thumbnail,omitempty"`
-	PlaybackID  string       `json:"playback_id,omitempty"`
-	ReviewCount int64        `json:"review_count"`
-	Videos      []VideoItem  `json:"videos,omitempty"`
-	Group       *GroupInfo   `json:"group,omitempty"`
-	Student     *StudentInfo `json:"student,omitempty"`
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	OwnerID        string         `json:"owner_id"`
+	Status         string         `json:"status"`
+	Thumbnail      string         `json:"thumbnail,omitempty"`
+	PlaybackID     string         `json:"playback_id,omitempty"`
+	ReviewCount    int64          `json:"review_count"`
+	ReviewProgress ReviewProgress `json:"review_progress"`
+	Videos         []VideoItem    `json:"videos,omitempty"`
+	Group          *GroupInfo     `json:"group,omitempty"`
+	Student        *StudentInfo   `json:"student,omitempty"`
+	ChatMessages   []ChatMessage  `json:"chat_messages,omitempty"`
+}
+
+// ReviewProgress counts the top-level review threads of an asset. A thread
+// is addressed once the student acknowledged it or the expert resolved it.
+type ReviewProgress struct {
+	Threads   int32 `json:"threads"`
+	Addressed int32 `json:"addressed"`
+}
+
+func reviewProgress(threads, open int32) ReviewProgress {
+	return ReviewProgress{Threads: threads, Addressed: threads - open}
 }
 
 type VideoItem struct {
 	ID          string `json:"id"`
 	PlaybackID  string `json:"playback_id"`
 	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
 	ReviewCount int64  `json:"review_count"`
 }
 
+// ChatMessage is a message from the chat of the coaching call an asset
+// recorded, shown to reviewers next to the video.
+type ChatMessage struct {
+	ID         string    `json:"id"`
+	AuthorID   string    `json:"author_id"`
+	AuthorRole string    `json:"author_role"`
+	Body       string    `json:"body"`
+	Links      []string  `json:"links"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ListAssets handles GET /assets. Filters (q, group_id, owner_id, status,
+// review_state) and sort apply to every request; pagination starts once the
+// client passes limit or cursor, with the next page linked from the Link
+// header. The body stays a plain array so existing clients keep working.
 func (h *Handler) ListAssets(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx, h.logger)
@@ -174,10 +209,13 @@ func (h *Handler) ListAssets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	assets, err := h.q.ListVisibleAssets(ctx, db.ListVisibleAssetsParams{
-		UserID:    userInfo.ID,
-		IsStudent: isStudent(userInfo),
-	})
+	query, err := parseAssetListQuery(r.URL.Query(), userInfo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	assets, err := h.listVisibleAssets(ctx, query)
 	if err != nil {
 		log.ErrorContext(ctx, "asset_list_failed",
 			slog.String("component", "assets"),
@@ -187,6 +225,10 @@ func (h *Handler) ListAssets(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to list videos", http.StatusInternalServerError)
 		return
 	}
+	if query.paged && int32(len(assets)) > query.pageSize {
+		assets = assets[:query.pageSize]
+		w.Header().Set("Link", nextPageLink(r, query, assets[len(assets)-1]))
+	}
 
 	resp := make([]AssetItem, len(assets))
 	for i, a := range assets {
@@ -225,14 +267,15 @@ func (h *Handler) ListAssets(w http.ResponseWriter, r *http.Request) {
 		}
 
 		resp[i] = AssetItem{
-			ID:          pgutil.UUIDToString(a.ID),
-			Title:       a.Name,
-			Description: a.Description,
-			OwnerID:     a.OwnerID,
-			Status:      string(a.Status),
-			Thumbnail:   thumb,
-			PlaybackID:  playbackID,
-			ReviewCount: a.ReviewCount,
+			ID:             pgutil.UUIDToString(a.ID),
+			Title:          a.Name,
+			Description:    a.Description,
+			OwnerID:        a.OwnerID,
+			Status:         string(a.Status),
+			Thumbnail:      thumb,
+			PlaybackID:     playbackID,
+			ReviewCount:    a.ReviewCount,
+			ReviewProgress: reviewProgress(a.ReviewThreads, a.OpenReviewThreads),
 		}
 	}
 
@@ -332,6 +375,7 @@ func (h *Handler) GetAsset(w http.ResponseWriter, r *http.Request) {
 			ID:          pgutil.UUIDToString(v.ID),
 			PlaybackID:  playbackID,
 			Status:      string(v.Status),
+			Error:       v.MuxError.String,
 			ReviewCount: v.ReviewCount,
 		})
 	}
@@ -381,16 +425,41 @@ func (h *Handler) GetAsset(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := AssetItem{
-		ID:          pgutil.UUIDToString(asset.ID),
-		Title:       asset.Name,
-		Description: asset.Description,
-		OwnerID:     asset.OwnerID,
-		Status:      string(asset.Status),
-		Thumbnail:   thumb,
-		PlaybackID:  currentPlaybackID,
-		Videos:      videos,
-		Group:       group,
-		Student:     student,
+		ID:             pgutil.UUIDToString(asset.ID),
+		Title:          asset.Name,
+		Description:    asset.Description,
+		OwnerID:        asset.OwnerID,
+		Status:         string(asset.Status),
+		Thumbnail:      thumb,
+		PlaybackID:     currentPlaybackID,
+		ReviewProgress: reviewProgress(asset.ReviewThreads, asset.OpenReviewThreads),
+		Videos:         videos,
+		Group:          group,
+		Student:        student,
+	}
+
+	// Recordings of coaching calls carry the call's chat.
+	messages, err := h.q.ListRecordingAssetMessages(ctx, uuid)
+	if err != nil {
+		log.ErrorContext(ctx, "asset_chat_messages_get_failed",
+			slog.String("component", "assets"),
+			slog.String("asset_id", idStr),
+			slog.Any("err", err),
+		)
+	}
+	for _, m := range messages {
+		links := m.Links
+		if links == nil {
+			links = []string{}
+		}
+		resp.ChatMessages = append(resp.ChatMessages, ChatMessage{
+			ID:         pgutil.UUIDToString(m.ID),
+			AuthorID:   m.AuthorID,
+			AuthorRole: m.AuthorRole,
+			Body:       m.Body,
+			Links:      links,
+			CreatedAt:  m.CreatedAt.Time,
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -415,10 +484,8 @@ func (h *Handler) fetchPlaybackIDFromMux(ctx context.Context, uploadID string) (
 			return "", 0, err
 		}
 
-		for _, pid := range asset.Data.PlaybackIds {
-			if pid.Policy == muxgo.PUBLIC {
-				return pid.Id, asset.Data.Duration, nil
-			}
+		if pid := publicPlaybackID(asset.Data); pid != "" {
+			return pid, asset.Data.Duration, nil
 		}
 	}
 
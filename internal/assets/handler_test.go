@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/OZIOisgood/zeta/internal/assets/mocks"
@@ -39,7 +40,7 @@ func assetTestUUID() pgtype.UUID {
 func TestListAssets_StudentUsesOwnerVisibilityScope(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "student-1", Role: permissions.RoleStudent}
 	assetID := assetTestUUID()
@@ -82,7 +83,7 @@ func TestListAssets_StudentUsesOwnerVisibilityScope(t *testing.T) {
 func TestListAssets_ExpertUsesGroupMembershipVisibilityScope(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}
 	q.EXPECT().ListVisibleAssets(gomock.Any(), db.ListVisibleAssetsParams{
@@ -104,7 +105,7 @@ func TestListAssets_ExpertUsesGroupMembershipVisibilityScope(t *testing.T) {
 func TestListAssets_AdminUsesGroupMembershipVisibilityScope(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "admin-1", Role: permissions.RoleAdmin}
 	q.EXPECT().ListVisibleAssets(gomock.Any(), db.ListVisibleAssetsParams{
@@ -126,7 +127,7 @@ func TestListAssets_AdminUsesGroupMembershipVisibilityScope(t *testing.T) {
 func TestGetAsset_NotVisibleReturnsNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "student-1", Role: permissions.RoleStudent}
 	assetID := assetTestUUID()
@@ -152,7 +153,7 @@ func TestGetAsset_NotVisibleReturnsNotFound(t *testing.T) {
 func TestGetAsset_IncludesStudentAndGroupIdentity(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}
 	assetID := assetTestUUID()
@@ -177,6 +178,12 @@ func TestGetAsset_IncludesStudentAndGroupIdentity(t *testing.T) {
 		StudentAvatar:      pgtype.Text{String: "student-avatar", Valid: true},
 	}, nil)
 	q.EXPECT().GetAssetVideos(gomock.Any(), assetID).Return([]db.GetAssetVideosRow{}, nil)
+	q.EXPECT().ListRecordingAssetMessages(gomock.Any(), assetID).Return([]db.CoachingBookingMessage{{
+		ID: assetID, AuthorID: "expert-1", AuthorRole: "expert",
+		Body: "Drill: https://example.com/jab", Links: []string{"https://example.com/jab"},
+	}, {
+		ID: assetID, AuthorID: "student-1", AuthorRole: "student", Body: "Thanks!",
+	}}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/assets/"+assetIDStr, nil)
 	req = assetWithChiURLParam(req, "id", assetIDStr)
@@ -188,6 +195,9 @@ func TestGetAsset_IncludesStudentAndGroupIdentity(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Fatalf("got %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
 	}
+	if !strings.Contains(rec.Body.String(), `"body":"Thanks!","links":[]`) {
+		t.Fatalf("body = %s, want a message without links to list none", rec.Body.String())
+	}
 	var resp AssetItem
 	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
 		t.Fatalf("decode: %v", err)
@@ -198,12 +208,15 @@ func TestGetAsset_IncludesStudentAndGroupIdentity(t *testing.T) {
 	if resp.Group == nil || resp.Group.Name != "Arena Academy" || resp.Group.Avatar != "group-avatar" {
 		t.Fatalf("group = %+v, want group identity", resp.Group)
 	}
+	if len(resp.ChatMessages) != 2 || resp.ChatMessages[0].AuthorRole != "expert" || len(resp.ChatMessages[0].Links) != 1 {
+		t.Fatalf("chat messages = %+v, want the recorded call's chat", resp.ChatMessages)
+	}
 }
 
 func TestFinalizeAsset_NotVisibleReturnsNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{
 		ID:          "expert-1",
@@ -233,7 +246,7 @@ func TestFinalizeAsset_NotVisibleReturnsNotFound(t *testing.T) {
 func TestCompleteUpload_OwnerUpdatesStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "student-1", Role: permissions.RoleStudent}
 	assetID := assetTestUUID()
@@ -263,7 +276,7 @@ func TestCompleteUpload_OwnerUpdatesStatus(t *testing.T) {
 func TestCompleteUpload_NonOwnerReturnsForbidden(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}
 	assetID := assetTestUUID()
@@ -289,7 +302,7 @@ func TestCompleteUpload_NonOwnerReturnsForbidden(t *testing.T) {
 func TestCompleteUpload_NotVisibleReturnsNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	user := &auth.UserContext{ID: "student-2", Role: permissions.RoleStudent}
 	assetID := assetTestUUID()
@@ -315,7 +328,7 @@ func TestCompleteUpload_NotVisibleReturnsNotFound(t *testing.T) {
 func TestCompleteUpload_UnauthenticatedReturnsUnauthorized(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 
 	assetIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
 	req := httptest.NewRequest(http.MethodPost, "/assets/"+assetIDStr+"/complete", nil)
@@ -332,7 +345,7 @@ func TestCompleteUpload_UnauthenticatedReturnsUnauthorized(t *testing.T) {
 func TestBackfillVideoDurations_RejectsWithoutSecret(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, nil, slog.Default())
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
 	protected := auth.RequireSchedulerSecret("scheduler-secret", slog.Default())(http.HandlerFunc(h.BackfillVideoDurations))
 
 	req := httptest.NewRequest(http.MethodPost, "/internal/assets/durations/backfill", nil)
@@ -350,7 +363,7 @@ func TestBackfillVideoDurations_UpdatesMissingDurations(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	mux := mocks.NewMockMuxClient(ctrl)
-	h := NewHandler(q, mux, nil, nil, slog.Default())
+	h := NewHandler(q, nil, mux, nil, nil, slog.Default())
 
 	videoID := assetTestUUID()
 	q.EXPECT().ListVideosMissingDuration(gomock.Any(), int32(100)).Return([]db.ListVideosMissingDurationRow{
@@ -390,7 +403,7 @@ func TestBackfillVideoDurations_ResolvesViaUploadID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	mux := mocks.NewMockMuxClient(ctrl)
-	h := NewHandler(q, mux, nil, nil, slog.Default())
+	h := NewHandler(q, nil, mux, nil, nil, slog.Default())
 
 	videoID := assetTestUUID()
 	q.EXPECT().ListVideosMissingDuration(gomock.Any(), int32(100)).Return([]db.ListVideosMissingDurationRow{
@@ -0,0 +1,208 @@
+package assets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultAssetPageSize = 50
+	maxAssetPageSize     = 100
+	maxSearchTerms       = 8
+)
+
+// Sort orders accepted by GET /assets?sort=. Every order is newest first.
+const (
+	sortCreatedAt    = "created_at"
+	sortUpdatedAt    = "updated_at"
+	sortLastReviewAt = "last_review_at"
+)
+
+var (
+	assetStatuses = map[string]bool{
+		string(db.AssetStatusWaitingUpload): true,
+		string(db.AssetStatusPending):       true,
+		string(db.AssetStatusCompleted):     true,
+	}
+	reviewStates = map[string]bool{"to_review": true, "in_review": true, "reviewed": true}
+	assetSorts   = map[string]bool{sortCreatedAt: true, sortUpdatedAt: true, sortLastReviewAt: true}
+)
+
+// assetListQuery is a parsed GET /assets request.
+type assetListQuery struct {
+	params db.ListVisibleAssetsParams
+	sort   string
+	// paged is set once the client asks for a limit or a cursor. Clients that
+	// predate pagination still get every asset in one response.
+	paged    bool
+	pageSize int32
+}
+
+func parseAssetListQuery(values url.Values, user *auth.UserContext) (assetListQuery, error) {
+	q := assetListQuery{
+		params: db.ListVisibleAssetsParams{
+			UserID:    user.ID,
+			IsStudent: isStudent(user),
+			OwnerID:   strings.TrimSpace(values.Get("owner_id")),
+		},
+		sort: sortCreatedAt,
+	}
+
+	if v := strings.TrimSpace(values.Get("sort")); v != "" {
+		if !assetSorts[v] {
+			return q, errors.New("Invalid sort")
+		}
+		q.sort = v
+	}
+	if v := strings.TrimSpace(values.Get("status")); v != "" {
+		if !assetStatuses[v] {
+			return q, errors.New("Invalid status")
+		}
+		q.params.Status = v
+	}
+	if v := strings.TrimSpace(values.Get("review_state")); v != "" {
+		if !reviewStates[v] {
+			return q, errors.New("Invalid review_state")
+		}
+		q.params.ReviewState = v
+	}
+	if v := strings.TrimSpace(values.Get("group_id")); v != "" {
+		if err := q.params.GroupID.Scan(v); err != nil {
+			return q, errors.New("Invalid group_id")
+		}
+	}
+	q.params.SearchQuery = searchQuery(values.Get("q"))
+
+	if values.Has("limit") || values.Has("cursor") {
+		q.paged = true
+		q.pageSize = assetPageSize(values.Get("limit"))
+		// Fetch one extra row to learn whether another page exists.
+		q.params.PageLimit = pgtype.Int4{Int32: q.pageSize + 1, Valid: true}
+	}
+	if v := values.Get("cursor"); v != "" {
+		at, id, err := decodeAssetCursor(v, q.sort)
+		if err != nil {
+			return q, errors.New("Invalid cursor")
+		}
+		q.params.HasCursor = true
+		q.params.CursorAt = pgtype.Timestamptz{Time: at, Valid: true}
+		q.params.CursorID = id
+	}
+	return q, nil
+}
+
+func assetPageSize(value string) int32 {
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parsed < 1 {
+		return defaultAssetPageSize
+	}
+	return int32(min(parsed, maxAssetPageSize))
+}
+
+// searchQuery turns free text into a prefix-matching tsquery, so "forehand
+// dri" finds "Forehand drills". Only letters and digits survive, which keeps
+// the result valid to_tsquery syntax whatever the user typed.
+func searchQuery(raw string) string {
+	terms := strings.FieldsFunc(raw, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	for i, t := range terms {
+		terms[i] = t + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// listVisibleAssets runs the query for the requested sort order. The three
+// queries share their filters and columns, so their rows convert directly.
+func (h *Handler) listVisibleAssets(ctx context.Context, q assetListQuery) ([]db.ListVisibleAssetsRow, error) {
+	switch q.sort {
+	case sortUpdatedAt:
+		rows, err := h.q.ListVisibleAssetsByUpdated(ctx, db.ListVisibleAssetsByUpdatedParams(q.params))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]db.ListVisibleAssetsRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVisibleAssetsRow(row)
+		}
+		return out, nil
+	case sortLastReviewAt:
+		rows, err := h.q.ListVisibleAssetsByReviewActivity(ctx, db.ListVisibleAssetsByReviewActivityParams(q.params))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]db.ListVisibleAssetsRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVisibleAssetsRow(row)
+		}
+		return out, nil
+	default:
+		return h.q.ListVisibleAssets(ctx, q.params)
+	}
+}
+
+// sortKey returns the value row is ordered by under sort.
+func sortKey(row db.ListVisibleAssetsRow, sort string) time.Time {
+	switch sort {
+	case sortUpdatedAt:
+		return row.UpdatedAt.Time
+	case sortLastReviewAt:
+		return row.LastActivityAt.Time
+	default:
+		return row.CreatedAt.Time
+	}
+}
+
+// nextPageLink builds the RFC 8288 Link header pointing at the page after
+// last, keeping every filter of the current request.
+func nextPageLink(r *http.Request, q assetListQuery, last db.ListVisibleAssetsRow) string {
+	next := *r.URL
+	values := next.Query()
+	values.Set("cursor", encodeAssetCursor(q.sort, sortKey(last, q.sort), last.ID))
+	values.Set("limit", strconv.Itoa(int(q.pageSize)))
+	next.RawQuery = values.Encode()
+	return fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI())
+}
+
+// encodeAssetCursor renders an opaque keyset cursor. The sort order is part of
+// it, so a cursor cannot be replayed against a different ordering.
+func encodeAssetCursor(sort string, at time.Time, id pgtype.UUID) string {
+	raw := sort + "|" + at.UTC().Format(time.RFC3339Nano) + "|" + pgutil.UUIDToString(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeAssetCursor(cursor, sort string) (time.Time, pgtype.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, pgtype.UUID{}, err
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || parts[0] != sort {
+		return time.Time{}, pgtype.UUID{}, errors.New("malformed cursor")
+	}
+	at, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return time.Time{}, pgtype.UUID{}, err
+	}
+	var id pgtype.UUID
+	if err := id.Scan(parts[2]); err != nil {
+		return time.Time{}, pgtype.UUID{}, err
+	}
+	return at, id, nil
+}
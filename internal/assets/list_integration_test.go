@@ -0,0 +1,95 @@
+//go:build integration
+
+package assets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_ListVisibleAssetsFiltersAndPages(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if err := q.AddUserToGroup(ctx, db.AddUserToGroupParams{UserID: "expert-1", GroupID: group.ID}); err != nil {
+		t.Fatalf("AddUserToGroup: %v", err)
+	}
+
+	create := func(name string, status db.AssetStatus) (db.Asset, db.Video) {
+		t.Helper()
+		a, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: name, Description: "Training", GroupID: group.ID, OwnerID: "student-1"})
+		if err != nil {
+			t.Fatalf("CreateAsset: %v", err)
+		}
+		if err := q.UpdateAssetStatus(ctx, db.UpdateAssetStatusParams{ID: a.ID, Status: status}); err != nil {
+			t.Fatalf("UpdateAssetStatus: %v", err)
+		}
+		v, err := q.CreateVideo(ctx, db.CreateVideoParams{AssetID: a.ID, Status: db.VideoStatusReady})
+		if err != nil {
+			t.Fatalf("CreateVideo: %v", err)
+		}
+		return a, v
+	}
+	serve, _ := create("Serve practice", db.AssetStatusPending)
+	forehand, forehandVideo := create("Forehand drills", db.AssetStatusPending)
+	create("Uploading", db.AssetStatusWaitingUpload)
+
+	// The trigger moves the reviewed asset into in_review.
+	if _, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{VideoID: forehandVideo.ID, Content: "Elbow up"}); err != nil {
+		t.Fatalf("CreateVideoReview: %v", err)
+	}
+
+	list := func(p db.ListVisibleAssetsParams) []db.ListVisibleAssetsRow {
+		t.Helper()
+		p.UserID = "expert-1"
+		rows, err := q.ListVisibleAssets(ctx, p)
+		if err != nil {
+			t.Fatalf("ListVisibleAssets: %v", err)
+		}
+		return rows
+	}
+
+	if rows := list(db.ListVisibleAssetsParams{}); len(rows) != 2 {
+		t.Errorf("default list = %d rows, want the two uploaded assets", len(rows))
+	}
+	if rows := list(db.ListVisibleAssetsParams{SearchQuery: "fore:*"}); len(rows) != 1 || rows[0].ID != forehand.ID {
+		t.Errorf("search = %+v", rows)
+	}
+	if rows := list(db.ListVisibleAssetsParams{ReviewState: "to_review"}); len(rows) != 1 || rows[0].ID != serve.ID {
+		t.Errorf("to_review = %+v", rows)
+	}
+	if rows := list(db.ListVisibleAssetsParams{ReviewState: "in_review"}); len(rows) != 1 || rows[0].ID != forehand.ID {
+		t.Errorf("in_review = %+v", rows)
+	}
+	if rows := list(db.ListVisibleAssetsParams{Status: "waiting_upload"}); len(rows) != 1 {
+		t.Errorf("waiting_upload = %d rows", len(rows))
+	}
+
+	first := list(db.ListVisibleAssetsParams{PageLimit: pgtype.Int4{Int32: 1, Valid: true}})
+	if len(first) != 1 || first[0].ID != forehand.ID {
+		t.Fatalf("first page = %+v", first)
+	}
+	second := list(db.ListVisibleAssetsParams{
+		PageLimit: pgtype.Int4{Int32: 1, Valid: true},
+		HasCursor: true,
+		CursorAt:  first[0].CreatedAt,
+		CursorID:  first[0].ID,
+	})
+	if len(second) != 1 || second[0].ID != serve.ID {
+		t.Errorf("second page = %+v", second)
+	}
+
+	byActivity, err := q.ListVisibleAssetsByReviewActivity(ctx, db.ListVisibleAssetsByReviewActivityParams{UserID: "expert-1"})
+	if err != nil || len(byActivity) != 2 || byActivity[0].ID != forehand.ID {
+		t.Errorf("by review activity = %+v, %v", byActivity, err)
+	}
+}
@@ -0,0 +1,138 @@
+package assets
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchQuery(t *testing.T) {
+	cases := map[string]string{
+		"":                      "",
+		"  ":                    "",
+		"Forehand dri":          "Forehand:* & dri:*",
+		"serve' | !(x) <-> y:*": "serve:* & x:* & y:*",
+		"Aufschlag-Übung 2":     "Aufschlag:* & Übung:* & 2:*",
+	}
+	for in, want := range cases {
+		if got := searchQuery(in); got != want {
+			t.Errorf("searchQuery(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseAssetListQuery_RejectsUnknownValues(t *testing.T) {
+	user := &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}
+	otherSort := encodeAssetCursor(sortUpdatedAt, time.Now(), assetTestUUID())
+	for _, raw := range []string{
+		"sort=title",
+		"status=archived",
+		"review_state=done",
+		"group_id=not-a-uuid",
+		"cursor=bm90LWEtY3Vyc29y",
+		"cursor=" + otherSort,
+	} {
+		values, _ := url.ParseQuery(raw)
+		if _, err := parseAssetListQuery(values, user); err == nil {
+			t.Errorf("%s: accepted", raw)
+		}
+	}
+}
+
+func TestListAssets_UnpagedRequestReturnsEverything(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
+
+	user := &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}
+	q.EXPECT().ListVisibleAssets(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, arg db.ListVisibleAssetsParams) ([]db.ListVisibleAssetsRow, error) {
+			if arg.PageLimit.Valid || arg.HasCursor {
+				t.Errorf("unpaged request sent %+v", arg)
+			}
+			if arg.Status != "pending" || arg.SearchQuery != "serve:*" {
+				t.Errorf("filters = %+v", arg)
+			}
+			return nil, nil
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/assets?status=pending&q=serve", nil)
+	req = req.WithContext(assetTestUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+	h.ListAssets(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Link") != "" {
+		t.Fatalf("got %d link %q", rec.Code, rec.Header().Get("Link"))
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != "[]" {
+		t.Errorf("body = %s, want []", body)
+	}
+}
+
+func TestListAssets_PagesWithLinkHeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, nil, slog.Default())
+
+	user := &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert}
+	reviewedAt := time.Date(2026, 10, 17, 8, 30, 0, 123456000, time.UTC)
+	rows := []db.ListVisibleAssetsByReviewActivityRow{
+		{ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Status: db.AssetStatusPending, PlaybackID: "a"},
+		{ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, Status: db.AssetStatusPending, PlaybackID: "b",
+			LastActivityAt: pgtype.Timestamptz{Time: reviewedAt, Valid: true}, ReviewThreads: 9, OpenReviewThreads: 2},
+		{ID: pgtype.UUID{Bytes: [16]byte{3}, Valid: true}, Status: db.AssetStatusPending, PlaybackID: "c"},
+	}
+	q.EXPECT().ListVisibleAssetsByReviewActivity(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, arg db.ListVisibleAssetsByReviewActivityParams) ([]db.ListVisibleAssetsByReviewActivityRow, error) {
+			if arg.PageLimit.Int32 != 3 {
+				t.Errorf("page limit = %d, want limit+1", arg.PageLimit.Int32)
+			}
+			return rows, nil
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/assets?sort=last_review_at&review_state=in_review&limit=2", nil)
+	req = req.WithContext(assetTestUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+	h.ListAssets(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+	var items []AssetItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(items) != 2 || items[1].ReviewProgress != (ReviewProgress{Threads: 9, Addressed: 7}) {
+		t.Errorf("review progress = %+v", items)
+	}
+	link := rec.Header().Get("Link")
+	target, ok := strings.CutPrefix(link, "<")
+	target, ok2 := strings.CutSuffix(target, `>; rel="next"`)
+	if !ok || !ok2 {
+		t.Fatalf("Link = %q", link)
+	}
+	next, err := url.Parse(target)
+	if err != nil {
+		t.Fatalf("parse next: %v", err)
+	}
+	values := next.Query()
+	if values.Get("review_state") != "in_review" || values.Get("limit") != "2" {
+		t.Errorf("next page dropped filters: %s", target)
+	}
+	at, id, err := decodeAssetCursor(values.Get("cursor"), sortLastReviewAt)
+	if err != nil || !at.Equal(reviewedAt) || id != rows[1].ID {
+		t.Errorf("cursor = %v %v %v, want second row", at, id, err)
+	}
+}
@@ -70,6 +70,20 @@ func (mr *MockMuxClientMockRecorder) CreateDirectUpload(req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDirectUpload", reflect.TypeOf((*MockMuxClient)(nil).CreateDirectUpload), req)
 }
 
+// DeleteAsset mocks base method.
+func (m *MockMuxClient) DeleteAsset(assetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAsset", assetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAsset indicates an expected call of DeleteAsset.
+func (mr *MockMuxClientMockRecorder) DeleteAsset(assetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsset", reflect.TypeOf((*MockMuxClient)(nil).DeleteAsset), assetID)
+}
+
 // GetAsset mocks base method.
 func (m *MockMuxClient) GetAsset(assetID string) (muxgo.AssetResponse, error) {
 	m.ctrl.T.Helper()
@@ -13,6 +13,7 @@ type MuxClient interface {
 	CreateAsset(req muxgo.CreateAssetRequest) (muxgo.AssetResponse, error)
 	GetDirectUpload(uploadID string) (muxgo.UploadResponse, error)
 	GetAsset(assetID string) (muxgo.AssetResponse, error)
+	DeleteAsset(assetID string) error
 }
 
 // muxClient wraps the real Mux SDK client.
@@ -45,3 +46,7 @@ func (m *muxClient) GetDirectUpload(uploadID string) (muxgo.UploadResponse, erro
 func (m *muxClient) GetAsset(assetID string) (muxgo.AssetResponse, error) {
 	return m.client.AssetsApi.GetAsset(assetID)
 }
+
+func (m *muxClient) DeleteAsset(assetID string) error {
+	return m.client.AssetsApi.DeleteAsset(assetID)
+}
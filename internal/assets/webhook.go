@@ -0,0 +1,239 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/OZIOisgood/zeta/internal/webhooksig"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+)
+
+const (
+	maxWebhookBodySize = 1 << 20
+	// muxSignatureTolerance bounds how old a signed delivery may be, so a
+	// captured request cannot be replayed indefinitely.
+	muxSignatureTolerance = 5 * time.Minute
+	maxMuxErrorLength     = 500
+)
+
+const (
+	muxEventUploadAssetCreated = "video.upload.asset_created"
+	muxEventAssetReady         = "video.asset.ready"
+	muxEventAssetErrored       = "video.asset.errored"
+)
+
+// WebhookHandler receives Mux webhooks so uploads finish server-side, without
+// relying on the client to poll GET /assets/{id} or call complete.
+type WebhookHandler struct {
+	q      db.Querier
+	secret string
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewWebhookHandler(q db.Querier, secret string, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		q:      q,
+		secret: strings.TrimSpace(secret),
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+type muxWebhookEvent struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// MuxWebhook handles POST /webhooks/mux. Every transition it applies is
+// idempotent, so Mux retries and out-of-order deliveries converge on the same
+// state. A 5xx asks Mux to redeliver.
+func (h *WebhookHandler) MuxWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	if h.secret == "" {
+		http.Error(w, "Mux webhooks are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodySize)
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := webhooksig.Verify("Mux-Signature", r.Header.Get("Mux-Signature"), payload, h.secret, muxSignatureTolerance, h.now()); err != nil {
+		log.WarnContext(ctx, "mux_webhook_verification_failed",
+			slog.String("component", "assets"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	var event muxWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+		return
+	}
+
+	var status string
+	switch event.Type {
+	case muxEventUploadAssetCreated:
+		var upload muxgo.Upload
+		if err = json.Unmarshal(event.Data, &upload); err == nil {
+			status, err = h.handleUploadAssetCreated(ctx, upload)
+		}
+	case muxEventAssetReady:
+		var asset muxgo.Asset
+		if err = json.Unmarshal(event.Data, &asset); err == nil {
+			status, err = h.handleAssetReady(ctx, asset)
+		}
+	case muxEventAssetErrored:
+		var asset muxgo.Asset
+		if err = json.Unmarshal(event.Data, &asset); err == nil {
+			status, err = h.handleAssetErrored(ctx, asset)
+		}
+	default:
+		status = "ignored"
+	}
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, errMuxEventIncomplete) {
+			http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+			return
+		}
+		log.ErrorContext(ctx, "mux_webhook_apply_failed",
+			slog.String("component", "assets"),
+			slog.String("event_id", event.ID),
+			slog.String("event_type", event.Type),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to apply webhook", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "mux_webhook_handled",
+		slog.String("component", "assets"),
+		slog.String("event_id", event.ID),
+		slog.String("event_type", event.Type),
+		slog.String("status", status),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
+
+var errMuxEventIncomplete = errors.New("mux event is missing its identifiers")
+
+// handleUploadAssetCreated records the asset behind a direct upload. The
+// video stays waiting_upload until video.asset.ready.
+func (h *WebhookHandler) handleUploadAssetCreated(ctx context.Context, upload muxgo.Upload) (string, error) {
+	if upload.Id == "" || upload.AssetId == "" {
+		return "", errMuxEventIncomplete
+	}
+	n, err := h.q.AttachVideoMuxAsset(ctx, db.AttachVideoMuxAssetParams{
+		MuxAssetID:  upload.AssetId,
+		MuxUploadID: upload.Id,
+	})
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "ignored", nil
+	}
+	return "accepted", nil
+}
+
+func (h *WebhookHandler) handleAssetReady(ctx context.Context, asset muxgo.Asset) (string, error) {
+	if asset.Id == "" {
+		return "", errMuxEventIncomplete
+	}
+	duration := pgtype.Float8{}
+	if asset.Duration > 0 {
+		duration = pgtype.Float8{Float64: asset.Duration, Valid: true}
+	}
+	assetIDs, err := h.q.MarkVideoReadyFromMux(ctx, db.MarkVideoReadyFromMuxParams{
+		MuxAssetID:      asset.Id,
+		PlaybackID:      publicPlaybackID(asset),
+		DurationSeconds: duration,
+		MuxUploadID:     asset.UploadId,
+	})
+	if err != nil {
+		return "", err
+	}
+	return h.promoteAssets(ctx, assetIDs)
+}
+
+func (h *WebhookHandler) handleAssetErrored(ctx context.Context, asset muxgo.Asset) (string, error) {
+	if asset.Id == "" {
+		return "", errMuxEventIncomplete
+	}
+	assetIDs, err := h.q.MarkVideoFailedFromMux(ctx, db.MarkVideoFailedFromMuxParams{
+		MuxAssetID:  asset.Id,
+		MuxError:    muxErrorMessage(asset.Errors),
+		MuxUploadID: asset.UploadId,
+	})
+	if err != nil {
+		return "", err
+	}
+	// A failed part can be the last one the asset was waiting on.
+	return h.promoteAssets(ctx, assetIDs)
+}
+
+func (h *WebhookHandler) promoteAssets(ctx context.Context, assetIDs []pgtype.UUID) (string, error) {
+	if len(assetIDs) == 0 {
+		return "ignored", nil
+	}
+	for _, id := range assetIDs {
+		n, err := h.q.PromoteUploadedAsset(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("promote asset %s: %w", pgutil.UUIDToString(id), err)
+		}
+		if n > 0 {
+			logger.From(ctx, h.logger).InfoContext(ctx, "mux_webhook_asset_promoted",
+				slog.String("component", "assets"),
+				slog.String("asset_id", pgutil.UUIDToString(id)),
+			)
+		}
+	}
+	return "accepted", nil
+}
+
+// publicPlaybackID returns the asset's public playback ID, or "" if it has none.
+func publicPlaybackID(asset muxgo.Asset) string {
+	for _, pid := range asset.PlaybackIds {
+		if pid.Policy == muxgo.PUBLIC {
+			return pid.Id
+		}
+	}
+	return ""
+}
+
+func muxErrorMessage(e muxgo.AssetErrors) string {
+	msg := strings.Join(e.Messages, "; ")
+	switch {
+	case e.Type != "" && msg != "":
+		msg = e.Type + ": " + msg
+	case e.Type != "":
+		msg = e.Type
+	case msg == "":
+		msg = "unknown error"
+	}
+	if len(msg) > maxMuxErrorLength {
+		msg = msg[:maxMuxErrorLength]
+	}
+	return msg
+}
@@ -0,0 +1,145 @@
+//go:build integration
+
+package assets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Mux may deliver video.asset.ready before video.upload.asset_created and
+// retries freely; the transitions must converge regardless of order.
+func TestIntegration_MuxWebhookTransitionsConverge(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Upload", GroupID: group.ID, OwnerID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	first, err := q.CreateVideo(ctx, db.CreateVideoParams{
+		AssetID:     asset.ID,
+		MuxUploadID: pgtype.Text{String: "upload-1", Valid: true},
+		Status:      db.VideoStatusWaitingUpload,
+	})
+	if err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+	if _, err := q.CreateVideo(ctx, db.CreateVideoParams{
+		AssetID:     asset.ID,
+		MuxUploadID: pgtype.Text{String: "upload-2", Valid: true},
+		Status:      db.VideoStatusWaitingUpload,
+	}); err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+
+	// ready overtakes asset_created and is matched by upload id.
+	ids, err := q.MarkVideoReadyFromMux(ctx, db.MarkVideoReadyFromMuxParams{
+		MuxAssetID:      "mux-asset-1",
+		PlaybackID:      "pid-1",
+		DurationSeconds: pgtype.Float8{Float64: 30, Valid: true},
+		MuxUploadID:     "upload-1",
+	})
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("MarkVideoReadyFromMux = %v, %v", ids, err)
+	}
+	if n, _ := q.AttachVideoMuxAsset(ctx, db.AttachVideoMuxAssetParams{MuxAssetID: "mux-asset-other", MuxUploadID: "upload-1"}); n != 0 {
+		t.Errorf("late asset_created overwrote the asset id")
+	}
+	if n, _ := q.PromoteUploadedAsset(ctx, asset.ID); n != 0 {
+		t.Error("asset promoted while a part is still uploading")
+	}
+
+	// The second part fails; a stale errored delivery for the ready part is a no-op.
+	if ids, err := q.MarkVideoFailedFromMux(ctx, db.MarkVideoFailedFromMuxParams{MuxAssetID: "mux-asset-1", MuxError: "late"}); err != nil || len(ids) != 0 {
+		t.Errorf("errored delivery touched a ready video: %v, %v", ids, err)
+	}
+	if n, _ := q.AttachVideoMuxAsset(ctx, db.AttachVideoMuxAssetParams{MuxAssetID: "mux-asset-2", MuxUploadID: "upload-2"}); n != 1 {
+		t.Error("asset_created did not attach the asset id")
+	}
+	if _, err := q.MarkVideoFailedFromMux(ctx, db.MarkVideoFailedFromMuxParams{MuxAssetID: "mux-asset-2", MuxError: "invalid_input"}); err != nil {
+		t.Fatalf("MarkVideoFailedFromMux: %v", err)
+	}
+	for range 2 {
+		if n, err := q.PromoteUploadedAsset(ctx, asset.ID); err != nil {
+			t.Fatalf("PromoteUploadedAsset: %v", err)
+		} else if n > 1 {
+			t.Errorf("promoted %d rows", n)
+		}
+	}
+
+	// A redelivered ready keeps the stored duration.
+	if _, err := q.MarkVideoReadyFromMux(ctx, db.MarkVideoReadyFromMuxParams{
+		MuxAssetID:      "mux-asset-1",
+		DurationSeconds: pgtype.Float8{Float64: 99, Valid: true},
+	}); err != nil {
+		t.Fatalf("MarkVideoReadyFromMux redelivery: %v", err)
+	}
+
+	videos, err := q.GetAssetVideos(ctx, asset.ID)
+	if err != nil {
+		t.Fatalf("GetAssetVideos: %v", err)
+	}
+	for _, v := range videos {
+		switch v.ID {
+		case first.ID:
+			if v.Status != db.VideoStatusReady || v.PlaybackID.String != "pid-1" || v.DurationSeconds.Float64 != 30 || v.MuxAssetID.String != "mux-asset-1" {
+				t.Errorf("ready video = %+v", v)
+			}
+		default:
+			if v.Status != db.VideoStatusFailed || v.MuxError.String != "invalid_input" {
+				t.Errorf("failed video = %+v", v)
+			}
+		}
+	}
+	got, err := q.GetAsset(ctx, asset.ID)
+	if err != nil {
+		t.Fatalf("GetAsset: %v", err)
+	}
+	if got.Status != db.AssetStatusPending {
+		t.Errorf("asset status = %s, want pending", got.Status)
+	}
+}
+
+// Deliveries that arrive after a video was deleted must not touch it.
+func TestIntegration_MuxWebhookSkipsDeletedVideos(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Upload", GroupID: group.ID, OwnerID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	video, err := q.CreateVideo(ctx, db.CreateVideoParams{
+		AssetID:     asset.ID,
+		MuxUploadID: pgtype.Text{String: "upload-1", Valid: true},
+		Status:      db.VideoStatusWaitingUpload,
+	})
+	if err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+	if _, err := q.SoftDeleteVideo(ctx, db.SoftDeleteVideoParams{ID: video.ID, AssetID: asset.ID, DeletedBy: pgtype.Text{String: "user-1", Valid: true}}); err != nil {
+		t.Fatalf("SoftDeleteVideo: %v", err)
+	}
+
+	if ids, err := q.MarkVideoReadyFromMux(ctx, db.MarkVideoReadyFromMuxParams{MuxAssetID: "mux-asset-1", MuxUploadID: "upload-1"}); err != nil || len(ids) != 0 {
+		t.Errorf("ready delivery touched a deleted video: %v, %v", ids, err)
+	}
+	if ids, err := q.MarkVideoFailedFromMux(ctx, db.MarkVideoFailedFromMuxParams{MuxAssetID: "mux-asset-1", MuxError: "late", MuxUploadID: "upload-1"}); err != nil || len(ids) != 0 {
+		t.Errorf("errored delivery touched a deleted video: %v, %v", ids, err)
+	}
+}
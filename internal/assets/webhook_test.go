@@ -0,0 +1,216 @@
+package assets
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/webhooksig"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const testMuxSecret = "mux-webhook-secret"
+
+var webhookNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func signMux(t *testing.T, secret string, ts time.Time, body []byte) string {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", ts.Unix(), body)
+	return fmt.Sprintf("t=%d,v1=%s", ts.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func newTestWebhookHandler(q db.Querier) *WebhookHandler {
+	h := NewWebhookHandler(q, testMuxSecret, slog.Default())
+	h.now = func() time.Time { return webhookNow }
+	return h
+}
+
+func serveMuxWebhook(t *testing.T, h *WebhookHandler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/mux", strings.NewReader(body))
+	req.Header.Set("Mux-Signature", signMux(t, testMuxSecret, webhookNow, []byte(body)))
+	rec := httptest.NewRecorder()
+	h.MuxWebhook(rec, req)
+	return rec
+}
+
+func webhookStatus(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v (%s)", err, rec.Body.String())
+	}
+	return resp["status"]
+}
+
+func TestVerifyMuxSignature(t *testing.T) {
+	body := []byte(`{"type":"video.asset.ready"}`)
+	valid := signMux(t, testMuxSecret, webhookNow, body)
+	other := signMux(t, "old-secret", webhookNow, body)
+	cases := []struct {
+		name    string
+		header  string
+		body    []byte
+		wantErr bool
+	}{
+		{"valid", valid, body, false},
+		{"rotated secret alongside old", other + "," + strings.SplitN(valid, ",", 2)[1], body, false},
+		{"wrong secret", other, body, true},
+		{"tampered body", valid, []byte(`{"type":"video.asset.errored"}`), true},
+		{"stale timestamp", signMux(t, testMuxSecret, webhookNow.Add(-10*time.Minute), body), body, true},
+		{"missing header", "", body, true},
+		{"no v1", fmt.Sprintf("t=%d", webhookNow.Unix()), body, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := webhooksig.Verify("Mux-Signature", tc.header, tc.body, testMuxSecret, muxSignatureTolerance, webhookNow)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMuxWebhook_NotConfiguredReturnsUnavailable(t *testing.T) {
+	h := NewWebhookHandler(nil, " ", slog.Default())
+	rec := httptest.NewRecorder()
+	h.MuxWebhook(rec, httptest.NewRequest(http.MethodPost, "/webhooks/mux", strings.NewReader("{}")))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestMuxWebhook_BadSignatureTouchesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	body := `{"type":"video.asset.ready","data":{"id":"mux-asset-1"}}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/mux", bytes.NewBufferString(body))
+	req.Header.Set("Mux-Signature", signMux(t, "wrong", webhookNow, []byte(body)))
+	rec := httptest.NewRecorder()
+	h.MuxWebhook(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMuxWebhook_UploadAssetCreatedAttachesAsset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	q.EXPECT().AttachVideoMuxAsset(gomock.Any(), db.AttachVideoMuxAssetParams{
+		MuxAssetID:  "mux-asset-1",
+		MuxUploadID: "upload-1",
+	}).Return(int64(1), nil)
+
+	rec := serveMuxWebhook(t, h, `{"id":"evt-1","type":"video.upload.asset_created","data":{"id":"upload-1","asset_id":"mux-asset-1","status":"asset_created"}}`)
+
+	if rec.Code != http.StatusOK || webhookStatus(t, rec) != "accepted" {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMuxWebhook_AssetReadyPersistsAndPromotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	assetID := assetTestUUID()
+	q.EXPECT().MarkVideoReadyFromMux(gomock.Any(), db.MarkVideoReadyFromMuxParams{
+		MuxAssetID:      "mux-asset-1",
+		PlaybackID:      "public-pid",
+		DurationSeconds: pgtype.Float8{Float64: 61.5, Valid: true},
+		MuxUploadID:     "upload-1",
+	}).Return([]pgtype.UUID{assetID}, nil)
+	q.EXPECT().PromoteUploadedAsset(gomock.Any(), assetID).Return(int64(1), nil)
+
+	rec := serveMuxWebhook(t, h, `{"id":"evt-2","type":"video.asset.ready","data":{
+		"id":"mux-asset-1","upload_id":"upload-1","status":"ready","duration":61.5,
+		"playback_ids":[{"id":"signed-pid","policy":"signed"},{"id":"public-pid","policy":"public"}]}}`)
+
+	if rec.Code != http.StatusOK || webhookStatus(t, rec) != "accepted" {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMuxWebhook_AssetErroredRecordsReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	assetID := assetTestUUID()
+	q.EXPECT().MarkVideoFailedFromMux(gomock.Any(), db.MarkVideoFailedFromMuxParams{
+		MuxAssetID:  "mux-asset-1",
+		MuxError:    "invalid_input: File is not a valid video",
+		MuxUploadID: "upload-1",
+	}).Return([]pgtype.UUID{assetID}, nil)
+	q.EXPECT().PromoteUploadedAsset(gomock.Any(), assetID).Return(int64(0), nil)
+
+	rec := serveMuxWebhook(t, h, `{"type":"video.asset.errored","data":{"id":"mux-asset-1","upload_id":"upload-1",
+		"errors":{"type":"invalid_input","messages":["File is not a valid video"]}}}`)
+
+	if rec.Code != http.StatusOK || webhookStatus(t, rec) != "accepted" {
+		t.Fatalf("got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+// Redelivery after the video is already ready, or an event for an asset this
+// service never created, matches no row and is acknowledged without retry.
+func TestMuxWebhook_UnmatchedEventsAreIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	q.EXPECT().MarkVideoFailedFromMux(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	for _, body := range []string{
+		`{"type":"video.asset.errored","data":{"id":"mux-asset-1"}}`,
+		`{"type":"video.asset.created","data":{"id":"mux-asset-1"}}`,
+	} {
+		rec := serveMuxWebhook(t, h, body)
+		if rec.Code != http.StatusOK || webhookStatus(t, rec) != "ignored" {
+			t.Fatalf("%s: got %d %s", body, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestMuxWebhook_MissingIdentifiersRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	rec := serveMuxWebhook(t, h, `{"type":"video.upload.asset_created","data":{"id":"upload-1"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMuxWebhook_StoreFailureAsksForRedelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := newTestWebhookHandler(q)
+
+	q.EXPECT().MarkVideoReadyFromMux(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+	rec := serveMuxWebhook(t, h, `{"type":"video.asset.ready","data":{"id":"mux-asset-1"}}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
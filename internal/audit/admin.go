@@ -0,0 +1,309 @@
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultListLimit  = 50
+	maxListLimit      = 100
+	defaultListWindow = 30 * 24 * time.Hour
+)
+
+type eventListResponse struct {
+	Items      []eventView `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type eventView struct {
+	ID           string          `json:"id"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	ActorType    string          `json:"actor_type"`
+	ActorID      string          `json:"actor_id,omitempty"`
+	ActorLabel   string          `json:"actor_label,omitempty"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id,omitempty"`
+	GroupID      string          `json:"group_id,omitempty"`
+	OldValues    json.RawMessage `json:"old_values,omitempty"`
+	NewValues    json.RawMessage `json:"new_values,omitempty"`
+	Metadata     json.RawMessage `json:"metadata,omitempty"`
+}
+
+// RegisterAdminRoutes mounts the audit trail API under /admin/audit.
+func (h *Handler) RegisterAdminRoutes(r chi.Router) {
+	r.Get("/events", h.ListEvents)
+	r.Get("/export", h.ExportEvents)
+}
+
+// ListEvents returns audit events newest first. The time window defaults to the
+// last 30 days and is always bounded, so only the partitions it overlaps are
+// scanned. Pagination is keyset-based: next_cursor encodes the last row's
+// (occurred_at, id) and stays stable while new events are appended.
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !permissions.HasPermission(user.Permissions, permissions.AuditEventsRead) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	to := time.Now().UTC()
+	if v := query.Get("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid to (use RFC3339)", http.StatusBadRequest)
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultListWindow)
+	if v := query.Get("from"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid from (use RFC3339)", http.StatusBadRequest)
+			return
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	params := db.ListAuditEventsParams{
+		OccurredFrom: pgtype.Timestamptz{Time: from, Valid: true},
+		OccurredTo:   pgtype.Timestamptz{Time: to, Valid: true},
+		ActorID:      strings.TrimSpace(query.Get("actor_id")),
+		GroupID:      strings.TrimSpace(query.Get("group_id")),
+		ResourceType: strings.TrimSpace(query.Get("resource_type")),
+		ResourceID:   strings.TrimSpace(query.Get("resource_id")),
+		Action:       strings.TrimSpace(query.Get("action")),
+		PageLimit:    listLimit(query.Get("limit")),
+	}
+	if v := query.Get("cursor"); v != "" {
+		at, id, err := decodeCursor(v)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		params.HasCursor = true
+		params.CursorOccurredAt = pgtype.Timestamptz{Time: at, Valid: true}
+		params.CursorID = id
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	pageSize := params.PageLimit
+	params.PageLimit++
+	rows, err := h.q.ListAuditEvents(ctx, params)
+	if err != nil {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "audit_events_list_failed",
+			slog.String("component", "audit"),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list audit events", http.StatusInternalServerError)
+		return
+	}
+
+	resp := eventListResponse{Items: make([]eventView, 0, len(rows))}
+	if int32(len(rows)) > pageSize {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		resp.NextCursor = encodeCursor(last.OccurredAt.Time, last.ID)
+	}
+	for _, row := range rows {
+		resp.Items = append(resp.Items, toEventView(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toEventView(e db.AuditEvent) eventView {
+	return eventView{
+		ID:           pgutil.UUIDToString(e.ID),
+		OccurredAt:   e.OccurredAt.Time.UTC(),
+		ActorType:    e.ActorType,
+		ActorID:      e.ActorID.String,
+		ActorLabel:   e.ActorLabel.String,
+		Action:       e.Action,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID.String,
+		GroupID:      e.GroupID.String,
+		OldValues:    rawJSON(e.OldValues),
+		NewValues:    rawJSON(e.NewValues),
+		Metadata:     rawJSON(e.Metadata),
+	}
+}
+
+func rawJSON(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.RawMessage(b)
+}
+
+func listLimit(value string) int32 {
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parsed < 1 || parsed > maxListLimit {
+		return defaultListLimit
+	}
+	return int32(parsed)
+}
+
+// encodeCursor renders an opaque keyset cursor. Nanosecond precision matters:
+// Postgres stores microseconds, and a truncated timestamp would skip or repeat
+// rows sharing the boundary.
+func encodeCursor(at time.Time, id pgtype.UUID) string {
+	raw := at.UTC().Format(time.RFC3339Nano) + "|" + pgutil.UUIDToString(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, pgtype.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, pgtype.UUID{}, err
+	}
+	at, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, pgtype.UUID{}, errors.New("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, pgtype.UUID{}, err
+	}
+	var id pgtype.UUID
+	if err := id.Scan(idStr); err != nil {
+		return time.Time{}, pgtype.UUID{}, err
+	}
+	return t, id, nil
+}
+
+// ExportEvents streams the events of a user, a group or a time window as NDJSON
+// or CSV for compliance requests. Personal data of anyone but user_id is
+// redacted unless redact=false. The window defaults to the full retention
+// period, so one of user_id, group_id or from is required to keep an export
+// from dumping the whole trail by accident.
+func (h *Handler) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !permissions.HasPermission(user.Permissions, permissions.AuditEventsExport) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := ExportOptions{
+		SubjectID: strings.TrimSpace(query.Get("user_id")),
+		GroupID:   strings.TrimSpace(query.Get("group_id")),
+		Format:    FormatNDJSON,
+		Redact:    query.Get("redact") != "false",
+		To:        time.Now().UTC(),
+	}
+	if v := query.Get("format"); v != "" {
+		opts.Format = v
+	}
+	contentType, ok := exportContentTypes[opts.Format]
+	if !ok {
+		http.Error(w, "Invalid format (use ndjson or csv)", http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid to (use RFC3339)", http.StatusBadRequest)
+			return
+		}
+		opts.To = parsed
+	}
+	opts.From = opts.To.Add(-h.retention)
+	if v := query.Get("from"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid from (use RFC3339)", http.StatusBadRequest)
+			return
+		}
+		opts.From = parsed
+	} else if opts.SubjectID == "" && opts.GroupID == "" {
+		http.Error(w, "user_id, group_id or from is required", http.StatusBadRequest)
+		return
+	}
+	if !opts.From.Before(opts.To) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	log := logger.From(ctx, h.logger).With(
+		slog.String("component", "audit"),
+		slog.String("user_id", user.ID),
+		slog.String("subject_id", opts.SubjectID),
+		slog.String("group_id", opts.GroupID),
+		slog.Bool("redact", opts.Redact),
+	)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-export-%s.%s"`,
+		opts.To.UTC().Format("20060102T150405Z"), opts.Format))
+	sw := &startedWriter{ResponseWriter: w}
+	n, err := Export(ctx, h.pool, sw, opts)
+	if err != nil {
+		log.ErrorContext(ctx, "audit_export_failed", slog.Int("events", n), slog.Any("err", err))
+		if !sw.started {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, "Failed to export audit events", http.StatusInternalServerError)
+			return
+		}
+		// Headers are gone; abort the connection so the client sees a broken
+		// transfer instead of a truncated file that looks complete.
+		panic(http.ErrAbortHandler)
+	}
+	log.InfoContext(ctx, "audit_export_completed", slog.Int("events", n))
+}
+
+var exportContentTypes = map[string]string{
+	FormatNDJSON: "application/x-ndjson",
+	FormatCSV:    "text/csv; charset=utf-8",
+}
+
+// startedWriter notes whether any body bytes reached the client.
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (s *startedWriter) Write(b []byte) (int, error) {
+	s.started = true
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *startedWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,208 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func auditAdminRequest(target string, perms ...string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return req.WithContext(context.WithValue(req.Context(), auth.UserKey, &auth.UserContext{
+		ID: "admin-1", Role: permissions.RoleAdmin, Permissions: perms,
+	}))
+}
+
+func auditEventRow(b byte, at time.Time) db.AuditEvent {
+	id := pgtype.UUID{Valid: true}
+	for i := range id.Bytes {
+		id.Bytes[i] = b
+	}
+	return db.AuditEvent{
+		ID:           id,
+		OccurredAt:   pgtype.Timestamptz{Time: at, Valid: true},
+		ActorType:    "user",
+		ActorID:      pgtype.Text{String: "user-1", Valid: true},
+		Action:       ActionGroupCreated,
+		ResourceType: ResourceGroup,
+		NewValues:    []byte(`{"_v":1,"name":"Academy"}`),
+	}
+}
+
+func TestListEvents_RequiresPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := NewHandler(nil, dbmocks.NewMockQuerier(ctrl), slog.Default(), 0)
+
+	rec := httptest.NewRecorder()
+	h.ListEvents(rec, auditAdminRequest("/admin/audit/events"))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestListEvents_PassesFiltersAndPaginates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(nil, q, slog.Default(), 0)
+
+	at := time.Date(2026, 9, 1, 12, 0, 0, 123456000, time.UTC)
+	var got db.ListAuditEventsParams
+	q.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+			got = arg
+			return []db.AuditEvent{auditEventRow(1, at), auditEventRow(2, at), auditEventRow(3, at)}, nil
+		})
+
+	rec := httptest.NewRecorder()
+	h.ListEvents(rec, auditAdminRequest(
+		"/admin/audit/events?group_id=g-1&actor_id=user-1&resource_type=group&action=group.created&from=2026-08-01T00:00:00Z&to=2026-10-01T00:00:00Z&limit=2",
+		permissions.AuditEventsRead,
+	))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	if got.GroupID != "g-1" || got.ActorID != "user-1" || got.ResourceType != "group" || got.Action != "group.created" {
+		t.Errorf("filters = %+v", got)
+	}
+	if got.PageLimit != 3 {
+		t.Errorf("PageLimit = %d, want limit+1 = 3", got.PageLimit)
+	}
+	if got.HasCursor {
+		t.Error("first page must not carry a cursor")
+	}
+	if !got.OccurredFrom.Time.Equal(time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)) ||
+		!got.OccurredTo.Time.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("window = %v..%v", got.OccurredFrom.Time, got.OccurredTo.Time)
+	}
+
+	var resp struct {
+		Items []struct {
+			ID        string          `json:"id"`
+			NewValues json.RawMessage `json:"new_values"`
+		} `json:"items"`
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Items) != 2 {
+		t.Fatalf("items = %d, want 2", len(resp.Items))
+	}
+	if string(resp.Items[0].NewValues) != `{"_v":1,"name":"Academy"}` {
+		t.Errorf("new_values = %s", resp.Items[0].NewValues)
+	}
+	cursorAt, cursorID, err := decodeCursor(resp.NextCursor)
+	if err != nil {
+		t.Fatalf("decode next_cursor: %v", err)
+	}
+	if !cursorAt.Equal(at) || cursorID != auditEventRow(2, at).ID {
+		t.Errorf("cursor = (%v, %v), want last returned row", cursorAt, cursorID)
+	}
+}
+
+func TestListEvents_LastPageHasNoCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(nil, q, slog.Default(), 0)
+
+	at := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	cursor := encodeCursor(at, auditEventRow(9, at).ID)
+	q.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+			if !arg.HasCursor || !arg.CursorOccurredAt.Time.Equal(at) || arg.CursorID != auditEventRow(9, at).ID {
+				t.Errorf("cursor params = %+v", arg)
+			}
+			if arg.PageLimit != defaultListLimit+1 {
+				t.Errorf("PageLimit = %d, want default+1", arg.PageLimit)
+			}
+			return []db.AuditEvent{auditEventRow(1, at)}, nil
+		})
+
+	rec := httptest.NewRecorder()
+	h.ListEvents(rec, auditAdminRequest("/admin/audit/events?cursor="+cursor, permissions.AuditEventsRead))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := resp["next_cursor"]; ok {
+		t.Errorf("last page carries next_cursor: %v", resp["next_cursor"])
+	}
+}
+
+func TestListEvents_RejectsBadInput(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+	}{
+		{"malformed cursor", "?cursor=not-a-cursor"},
+		{"bad from", "?from=yesterday"},
+		{"inverted window", "?from=2026-10-01T00:00:00Z&to=2026-09-01T00:00:00Z"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := NewHandler(nil, dbmocks.NewMockQuerier(ctrl), slog.Default(), 0)
+
+			rec := httptest.NewRecorder()
+			h.ListEvents(rec, auditAdminRequest("/admin/audit/events"+tc.query, permissions.AuditEventsRead))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestExportEvents_RequiresExportPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := NewHandler(nil, dbmocks.NewMockQuerier(ctrl), slog.Default(), 0)
+
+	rec := httptest.NewRecorder()
+	h.ExportEvents(rec, auditAdminRequest("/admin/audit/export?user_id=u-1", permissions.AuditEventsRead))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestExportEvents_RejectsBadInput(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+	}{
+		{"unscoped", ""},
+		{"unknown format", "?user_id=u-1&format=xml"},
+		{"bad to", "?group_id=g-1&to=tomorrow"},
+		{"inverted window", "?from=2026-10-01T00:00:00Z&to=2026-09-01T00:00:00Z"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := NewHandler(nil, dbmocks.NewMockQuerier(ctrl), slog.Default(), 0)
+
+			rec := httptest.NewRecorder()
+			h.ExportEvents(rec, auditAdminRequest("/admin/audit/export"+tc.query, permissions.AuditEventsExport))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
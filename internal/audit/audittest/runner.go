@@ -0,0 +1,70 @@
+// Package audittest provides an in-memory audit.Runner for handler unit tests.
+package audittest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/db"
+)
+
+// Runner hands fn the wrapped Querier (typically a gomock) and captures the
+// events it records instead of writing them. Events of a transaction whose fn
+// fails are discarded, mirroring a rollback.
+type Runner struct {
+	Q db.Querier
+	// RecordErr, when set, is returned by every Record call so tests can assert
+	// that an audit failure aborts the mutation.
+	RecordErr error
+
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+// NewRunner wraps q.
+func NewRunner(q db.Querier) *Runner {
+	return &Runner{Q: q}
+}
+
+func (r *Runner) InTx(ctx context.Context, fn func(tx audit.Tx) error) error {
+	tx := &tx{Querier: r.Q, recordErr: r.RecordErr}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.events = append(r.events, tx.events...)
+	r.mu.Unlock()
+	return nil
+}
+
+// Events returns the events of all committed transactions, in order.
+func (r *Runner) Events() []audit.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]audit.Event(nil), r.events...)
+}
+
+// Actions returns the Action of every committed event, in order.
+func (r *Runner) Actions() []string {
+	events := r.Events()
+	actions := make([]string, len(events))
+	for i, e := range events {
+		actions[i] = e.Action
+	}
+	return actions
+}
+
+type tx struct {
+	db.Querier
+	recordErr error
+	events    []audit.Event
+}
+
+func (t *tx) Record(_ context.Context, e audit.Event) error {
+	if t.recordErr != nil {
+		return t.recordErr
+	}
+	t.events = append(t.events, e)
+	return nil
+}
@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// chainVersion prefixes every hashed record so the encoding can evolve without
+// old links verifying under new rules.
+const chainVersion = "audit-chain-v1"
+
+// chainShards is how many independent chains each partition is split into.
+// Events pick a shard by actor, so one transaction — which acts for a single
+// actor — only ever locks one head and shards cannot deadlock each other.
+// Changing the count is safe: every (partition, shard) chain stands alone.
+const chainShards = 16
+
+// zeroHash is the genesis of a shard without a sealed predecessor: the first
+// chained partition, or one following a month the shard recorded nothing in.
+var zeroHash = make([]byte, sha256.Size)
+
+// chainShardOf maps an actor to its shard; the system actor has one of its own.
+func chainShardOf(actorID pgtype.Text) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(actorID.String)) //nolint:errcheck
+	return int32(h.Sum32() % chainShards)
+}
+
+// appendToChain locks its shard's chain head in the current partition, links p
+// to it and advances the head. The lock is held until the caller's transaction
+// ends, so recorders sharing a shard append one at a time and a rolled-back
+// mutation leaves no gap in the chain. Heads are created on a shard's first
+// event of the month.
+func appendToChain(ctx context.Context, q db.Querier, p *db.CreateAuditEventParams) error {
+	p.ChainShard = chainShardOf(p.ActorID)
+	if err := q.EnsureAuditChainHead(ctx, p.ChainShard); err != nil {
+		return err
+	}
+	head, err := q.LockAuditChainHead(ctx, p.ChainShard)
+	if err != nil {
+		return err
+	}
+
+	prev := head.HeadHash
+	if head.HeadSeq == 0 {
+		// First event of the month: seal the shard's previous partition and
+		// chain to its head so the link survives the rollover.
+		if prev, err = sealPrevious(ctx, q, head.PartitionName, p.ChainShard); err != nil {
+			return err
+		}
+	}
+
+	p.OccurredAt = head.Now
+	p.ChainSeq = pgtype.Int8{Int64: head.HeadSeq + 1, Valid: true}
+	p.PrevHash = prev
+	p.Hash, err = chainHash(prev, chainRecordOf(*p))
+	if err != nil {
+		return err
+	}
+	if err := q.CreateAuditEvent(ctx, *p); err != nil {
+		return err
+	}
+	return q.AdvanceAuditChainHead(ctx, db.AdvanceAuditChainHeadParams{
+		HeadSeq:       p.ChainSeq.Int64,
+		HeadHash:      p.Hash,
+		GenesisHash:   prev,
+		ChainedSince:  head.Now,
+		PartitionName: head.PartitionName,
+		Shard:         p.ChainShard,
+	})
+}
+
+func sealPrevious(ctx context.Context, q db.Querier, partition string, shard int32) ([]byte, error) {
+	start, ok := partitionStart(partition)
+	if !ok {
+		return nil, fmt.Errorf("unmanaged audit partition %q", partition)
+	}
+	sealed, err := q.SealAuditChainHead(ctx, db.SealAuditChainHeadParams{
+		PartitionName: partitionName(start.AddDate(0, -1, 0)),
+		Shard:         shard,
+	})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && sealed == nil) {
+		return zeroHash, nil
+	}
+	return sealed, err
+}
+
+// chainRecord is the hashed content of one event. Values are normalised so a
+// row read back from Postgres hashes exactly like the one that was written.
+type chainRecord struct {
+	seq          int64
+	id           pgtype.UUID
+	occurredAt   time.Time
+	actorID      pgtype.Text
+	actorType    string
+	actorLabel   pgtype.Text
+	action       string
+	resourceType string
+	resourceID   pgtype.Text
+	groupID      pgtype.Text
+	oldValues    []byte
+	newValues    []byte
+	metadata     []byte
+}
+
+func chainRecordOf(p db.CreateAuditEventParams) chainRecord {
+	return chainRecord{
+		seq: p.ChainSeq.Int64, id: p.ID, occurredAt: p.OccurredAt.Time,
+		actorID: p.ActorID, actorType: p.ActorType, actorLabel: p.ActorLabel,
+		action: p.Action, resourceType: p.ResourceType, resourceID: p.ResourceID, groupID: p.GroupID,
+		oldValues: p.OldValues, newValues: p.NewValues, metadata: p.Metadata,
+	}
+}
+
+func chainRecordOfEvent(e db.AuditEvent) chainRecord {
+	return chainRecord{
+		seq: e.ChainSeq.Int64, id: e.ID, occurredAt: e.OccurredAt.Time,
+		actorID: e.ActorID, actorType: e.ActorType, actorLabel: e.ActorLabel,
+		action: e.Action, resourceType: e.ResourceType, resourceID: e.ResourceID, groupID: e.GroupID,
+		oldValues: e.OldValues, newValues: e.NewValues, metadata: e.Metadata,
+	}
+}
+
+// chainHash computes SHA-256 over prev and a length-prefixed encoding of r.
+// JSON payloads are canonicalised (sorted keys, no insignificant whitespace)
+// because JSONB does not preserve the bytes that were inserted.
+func chainHash(prev []byte, r chainRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	field := func(b []byte, present bool) {
+		if !present {
+			binary.Write(&buf, binary.BigEndian, int32(-1)) //nolint:errcheck
+			return
+		}
+		binary.Write(&buf, binary.BigEndian, int32(len(b))) //nolint:errcheck
+		buf.Write(b)
+	}
+	text := func(t pgtype.Text) { field([]byte(t.String), t.Valid) }
+	jsonField := func(raw []byte) error {
+		c, err := canonicalJSON(raw)
+		if err != nil {
+			return err
+		}
+		field(c, c != nil)
+		return nil
+	}
+
+	field([]byte(chainVersion), true)
+	field(prev, true)
+	binary.Write(&buf, binary.BigEndian, r.seq) //nolint:errcheck
+	field(r.id.Bytes[:], r.id.Valid)
+	field([]byte(r.occurredAt.UTC().Format(time.RFC3339Nano)), true)
+	text(r.actorID)
+	field([]byte(r.actorType), true)
+	text(r.actorLabel)
+	field([]byte(r.action), true)
+	field([]byte(r.resourceType), true)
+	text(r.resourceID)
+	text(r.groupID)
+	for _, raw := range [][]byte{r.oldValues, r.newValues, r.metadata} {
+		if err := jsonField(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return sum[:], nil
+}
+
+// canonicalJSON re-encodes raw with sorted object keys. Numbers keep their
+// literal text so no precision is lost in the round trip.
+func canonicalJSON(raw []byte) ([]byte, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
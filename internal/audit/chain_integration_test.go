@@ -0,0 +1,63 @@
+//go:build integration
+
+package audit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+)
+
+func TestIntegration_HashChain_VerifiesAndDetectsTampering(t *testing.T) {
+	pool := testdb.New(t)
+	ctx := userCtx(context.Background())
+	if err := audit.EnsurePartitions(ctx, pool); err != nil {
+		t.Fatalf("EnsurePartitions: %v", err)
+	}
+
+	rec := audit.NewRecorder()
+	for _, action := range []string{audit.ActionGroupCreated, audit.ActionGroupUpdated, audit.ActionGroupDeleted} {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		if err := rec.Record(ctx, tx, audit.Event{
+			Action:       action,
+			ResourceType: audit.ResourceGroup,
+			ResourceID:   "55555555-5555-5555-5555-555555555555",
+			NewValues:    audit.GroupSnapshot{V: 1, Name: "Team <A>", OwnerID: "user_123"},
+		}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+	}
+
+	report, err := audit.VerifyChain(ctx, pool)
+	if err != nil {
+		t.Fatalf("VerifyChain: %v", err)
+	}
+	if report.FirstBreak != nil || report.Events != 3 {
+		t.Fatalf("report = %+v, want 3 intact events", report)
+	}
+
+	// An operator with table rights can lift the append-only trigger; the chain
+	// must still expose the rewrite.
+	if _, err := pool.Exec(ctx, `ALTER TABLE audit_events DISABLE TRIGGER audit_events_block_mutation`); err != nil {
+		t.Fatalf("disable trigger: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE audit_events SET action = 'group.updated' WHERE chain_seq = 3`); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+
+	report, err = audit.VerifyChain(ctx, pool)
+	if err != nil {
+		t.Fatalf("VerifyChain: %v", err)
+	}
+	if report.FirstBreak == nil || report.FirstBreak.Seq != 3 {
+		t.Fatalf("first_break = %+v, want seq 3", report.FirstBreak)
+	}
+}
@@ -0,0 +1,328 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+var chainNow = time.Date(2026, 10, 18, 9, 30, 0, 123456000, time.UTC)
+
+func TestChainHash_StableAcrossJSONBRoundTrip(t *testing.T) {
+	r := chainRecord{
+		seq:          1,
+		id:           pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+		occurredAt:   chainNow,
+		actorType:    "user",
+		actorID:      pgtype.Text{String: "user-1", Valid: true},
+		action:       ActionGroupCreated,
+		resourceType: ResourceGroup,
+		newValues:    []byte(`{"_v":1,"name":"Academy","description":"<b>","owner_id":"user-1"}`),
+	}
+	written, err := chainHash(zeroHash, r)
+	if err != nil {
+		t.Fatalf("chainHash: %v", err)
+	}
+
+	// JSONB hands back sorted keys with its own spacing.
+	r.newValues = []byte(`{"_v": 1, "name": "Academy", "owner_id": "user-1", "description": "<b>"}`)
+	read, err := chainHash(zeroHash, r)
+	if err != nil {
+		t.Fatalf("chainHash: %v", err)
+	}
+	if !bytes.Equal(written, read) {
+		t.Error("hash changed across the JSONB round trip")
+	}
+
+	r.actorID = pgtype.Text{}
+	if nullActor, _ := chainHash(zeroHash, r); bytes.Equal(nullActor, read) {
+		t.Error("NULL actor_id hashes like a present one")
+	}
+}
+
+func TestAppendToChain_ChainsToHead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	headHash := bytes.Repeat([]byte{7}, 32)
+	q.EXPECT().EnsureAuditChainHead(gomock.Any(), chainShardOf(pgtype.Text{})).Return(nil)
+	q.EXPECT().LockAuditChainHead(gomock.Any(), chainShardOf(pgtype.Text{})).Return(db.LockAuditChainHeadRow{
+		PartitionName: "audit_events_2026_10",
+		HeadSeq:       4,
+		HeadHash:      headHash,
+		Now:           pgtype.Timestamptz{Time: chainNow, Valid: true},
+	}, nil)
+	var inserted db.CreateAuditEventParams
+	q.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) error {
+			inserted = arg
+			return nil
+		})
+	q.EXPECT().AdvanceAuditChainHead(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.AdvanceAuditChainHeadParams) error {
+			if arg.HeadSeq != 5 || !bytes.Equal(arg.HeadHash, inserted.Hash) || arg.PartitionName != "audit_events_2026_10" || arg.Shard != inserted.ChainShard {
+				t.Errorf("advance = %+v", arg)
+			}
+			return nil
+		})
+
+	p := &db.CreateAuditEventParams{ActorType: "system", Action: ActionBookingCreated, ResourceType: ResourceBooking}
+	if err := appendToChain(context.Background(), q, p); err != nil {
+		t.Fatalf("appendToChain: %v", err)
+	}
+	if inserted.ChainSeq.Int64 != 5 || !bytes.Equal(inserted.PrevHash, headHash) || !inserted.OccurredAt.Time.Equal(chainNow) {
+		t.Errorf("inserted = %+v", inserted)
+	}
+	want, _ := chainHash(headHash, chainRecordOf(inserted))
+	if !bytes.Equal(inserted.Hash, want) {
+		t.Error("stored hash does not cover the inserted row")
+	}
+}
+
+func TestAppendToChain_FirstEventOfMonthSealsPrevious(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	sealed := bytes.Repeat([]byte{9}, 32)
+	shard := chainShardOf(pgtype.Text{})
+	q.EXPECT().EnsureAuditChainHead(gomock.Any(), shard).Return(nil)
+	q.EXPECT().LockAuditChainHead(gomock.Any(), shard).Return(db.LockAuditChainHeadRow{
+		PartitionName: "audit_events_2026_01",
+		Now:           pgtype.Timestamptz{Time: chainNow, Valid: true},
+	}, nil)
+	q.EXPECT().SealAuditChainHead(gomock.Any(), db.SealAuditChainHeadParams{PartitionName: "audit_events_2025_12", Shard: shard}).Return(sealed, nil)
+	q.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) error {
+			if arg.ChainSeq.Int64 != 1 || !bytes.Equal(arg.PrevHash, sealed) {
+				t.Errorf("first event = seq %d prev %x", arg.ChainSeq.Int64, arg.PrevHash)
+			}
+			return nil
+		})
+	q.EXPECT().AdvanceAuditChainHead(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.AdvanceAuditChainHeadParams) error {
+			if !bytes.Equal(arg.GenesisHash, sealed) {
+				t.Errorf("genesis = %x, want sealed head", arg.GenesisHash)
+			}
+			return nil
+		})
+
+	if err := appendToChain(context.Background(), q, &db.CreateAuditEventParams{ActorType: "system"}); err != nil {
+		t.Fatalf("appendToChain: %v", err)
+	}
+}
+
+func TestAppendToChain_CreatesHeadBeforeLockingActorShard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	actor := pgtype.Text{String: "user-1", Valid: true}
+	shard := chainShardOf(actor)
+	gomock.InOrder(
+		q.EXPECT().EnsureAuditChainHead(gomock.Any(), shard).Return(nil),
+		q.EXPECT().LockAuditChainHead(gomock.Any(), shard).Return(db.LockAuditChainHeadRow{
+			PartitionName: "audit_events_2026_10",
+			HeadSeq:       1,
+			HeadHash:      zeroHash,
+			Now:           pgtype.Timestamptz{Time: chainNow, Valid: true},
+		}, nil),
+		q.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.CreateAuditEventParams) error {
+				if arg.ChainShard != shard {
+					t.Errorf("chain_shard = %d, want %d", arg.ChainShard, shard)
+				}
+				return nil
+			}),
+		q.EXPECT().AdvanceAuditChainHead(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	if err := appendToChain(context.Background(), q, &db.CreateAuditEventParams{ActorID: actor, ActorType: "user"}); err != nil {
+		t.Fatalf("appendToChain: %v", err)
+	}
+}
+
+func TestChainShardOf_StablePerActor(t *testing.T) {
+	a := chainShardOf(pgtype.Text{String: "user-1", Valid: true})
+	if a != chainShardOf(pgtype.Text{String: "user-1", Valid: true}) {
+		t.Error("shard changed between calls for the same actor")
+	}
+	seen := map[int32]bool{}
+	for i := range 64 {
+		s := chainShardOf(pgtype.Text{String: fmt.Sprintf("user-%d", i), Valid: true})
+		if s < 0 || s >= chainShards {
+			t.Fatalf("shard %d out of range", s)
+		}
+		seen[s] = true
+	}
+	if len(seen) < 2 {
+		t.Error("all actors landed on one shard")
+	}
+}
+
+// buildChain returns n correctly chained events for partition October 2026
+// and the head row describing them.
+func buildChain(t *testing.T, n int, genesis []byte) ([]db.AuditEvent, db.AuditChainHead) {
+	t.Helper()
+	prev := genesis
+	events := make([]db.AuditEvent, n)
+	for i := range events {
+		e := db.AuditEvent{
+			ID:           pgtype.UUID{Bytes: [16]byte{byte(i + 1)}, Valid: true},
+			OccurredAt:   pgtype.Timestamptz{Time: chainNow.Add(time.Duration(i) * time.Minute), Valid: true},
+			ActorType:    "system",
+			Action:       ActionGroupUpdated,
+			ResourceType: ResourceGroup,
+			NewValues:    []byte(`{"_v": 1, "name": "Academy"}`),
+			ChainSeq:     pgtype.Int8{Int64: int64(i + 1), Valid: true},
+			PrevHash:     prev,
+		}
+		h, err := chainHash(prev, chainRecordOfEvent(e))
+		if err != nil {
+			t.Fatalf("chainHash: %v", err)
+		}
+		e.Hash = h
+		events[i] = e
+		prev = h
+	}
+	return events, db.AuditChainHead{
+		PartitionName: "audit_events_2026_10",
+		GenesisHash:   genesis,
+		ChainedSince:  events[0].OccurredAt,
+		HeadSeq:       int64(n),
+		HeadHash:      prev,
+	}
+}
+
+func expectVerify(q *dbmocks.MockQuerier, heads []db.AuditChainHead, events []db.AuditEvent) {
+	q.EXPECT().ListAuditChainHeads(gomock.Any()).Return(heads, nil)
+	q.EXPECT().ListAuditChainEvents(gomock.Any(), gomock.Any()).Return(events, nil).AnyTimes()
+	q.EXPECT().CountUnchainedAuditEvents(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+}
+
+func TestVerifyChain_Intact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	sealed := bytes.Repeat([]byte{3}, 32)
+	events, head := buildChain(t, 3, sealed)
+	prev := db.AuditChainHead{
+		PartitionName: "audit_events_2026_09",
+		SealedSeq:     pgtype.Int8{Int64: 8, Valid: true},
+		SealedHash:    sealed,
+		HeadSeq:       8,
+		DroppedAt:     pgtype.Timestamptz{Time: chainNow, Valid: true},
+	}
+	expectVerify(q, []db.AuditChainHead{prev, head}, events)
+
+	report, err := verifyChain(context.Background(), q)
+	if err != nil {
+		t.Fatalf("verifyChain: %v", err)
+	}
+	if report.FirstBreak != nil {
+		t.Fatalf("unexpected break: %+v", report.FirstBreak)
+	}
+	if report.Events != 3 || len(report.Partitions) != 2 || !report.Partitions[0].Dropped {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestVerifyChain_ReportsFirstBrokenLink(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(events []db.AuditEvent, head *db.AuditChainHead) []db.AuditEvent
+		seq    int64
+		reason string
+	}{
+		{"altered contents", func(e []db.AuditEvent, _ *db.AuditChainHead) []db.AuditEvent {
+			e[1].Action = "group.deleted"
+			return e
+		}, 2, "contents"},
+		{"deleted event", func(e []db.AuditEvent, _ *db.AuditChainHead) []db.AuditEvent {
+			return append(e[:1], e[2:]...)
+		}, 2, "expected chain_seq 2"},
+		{"truncated tail", func(e []db.AuditEvent, _ *db.AuditChainHead) []db.AuditEvent {
+			return e[:2]
+		}, 2, "head records 3"},
+		{"rewritten genesis", func(e []db.AuditEvent, h *db.AuditChainHead) []db.AuditEvent {
+			h.GenesisHash = bytes.Repeat([]byte{1}, 32)
+			return e
+		}, 0, "genesis"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+
+			events, head := buildChain(t, 3, zeroHash)
+			events = tc.mutate(events, &head)
+			expectVerify(q, []db.AuditChainHead{head}, events)
+
+			report, err := verifyChain(context.Background(), q)
+			if err != nil {
+				t.Fatalf("verifyChain: %v", err)
+			}
+			brk := report.FirstBreak
+			if brk == nil {
+				t.Fatal("break not detected")
+			}
+			if brk.Seq != tc.seq || !strings.Contains(brk.Reason, tc.reason) || brk.Partition != "audit_events_2026_10" {
+				t.Errorf("break = %+v, want seq %d reason ~%q", brk, tc.seq, tc.reason)
+			}
+		})
+	}
+}
+
+func TestVerifyChain_FlagsRowsOutsideChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	events, head := buildChain(t, 2, zeroHash)
+	q.EXPECT().ListAuditChainHeads(gomock.Any()).Return([]db.AuditChainHead{head}, nil)
+	q.EXPECT().ListAuditChainEvents(gomock.Any(), gomock.Any()).Return(events, nil)
+	q.EXPECT().CountUnchainedAuditEvents(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+	report, err := verifyChain(context.Background(), q)
+	if err != nil {
+		t.Fatalf("verifyChain: %v", err)
+	}
+	if report.FirstBreak == nil || !strings.Contains(report.FirstBreak.Reason, "outside the chain") {
+		t.Errorf("break = %+v", report.FirstBreak)
+	}
+}
+
+func TestVerifyChain_WalksEachShardAndCountsUnchainedOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	events, head := buildChain(t, 2, zeroHash)
+	other := head
+	other.Shard = 3
+	other.HeadSeq = 3
+	q.EXPECT().ListAuditChainHeads(gomock.Any()).Return([]db.AuditChainHead{head, other}, nil)
+	q.EXPECT().ListAuditChainEvents(gomock.Any(), gomock.Any()).Return(events, nil).Times(2)
+
+	report, err := verifyChain(context.Background(), q)
+	if err != nil {
+		t.Fatalf("verifyChain: %v", err)
+	}
+	if brk := report.FirstBreak; brk == nil || brk.Shard != 3 || !strings.Contains(brk.Reason, "head records 3") {
+		t.Errorf("break = %+v, want shard 3 truncated", brk)
+	}
+
+	ctrl = gomock.NewController(t)
+	q = dbmocks.NewMockQuerier(ctrl)
+	other.HeadSeq = 2
+	q.EXPECT().ListAuditChainHeads(gomock.Any()).Return([]db.AuditChainHead{head, other}, nil)
+	q.EXPECT().ListAuditChainEvents(gomock.Any(), gomock.Any()).Return(events, nil).Times(2)
+	q.EXPECT().CountUnchainedAuditEvents(gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(1)
+	if report, err := verifyChain(context.Background(), q); err != nil || report.FirstBreak != nil || report.Events != 4 {
+		t.Errorf("report = %+v, err = %v", report, err)
+	}
+}
@@ -6,8 +6,10 @@ package audit
 const (
 	ResourceBooking         = "booking"
 	ResourceCoachingSession = "coaching_session"
+	ResourceCoachingCredit  = "coaching_credit"
 	ResourceRecording       = "recording"
 	ResourceReview          = "review"
+	ResourceChapter         = "chapter"
 	ResourceGroup           = "group"
 	ResourceGroupMembership = "group_membership"
 	ResourceGroupInvite     = "group_invite"
@@ -19,12 +21,20 @@ const (
 // Actions — stable verbs. These names are part of the trail's contract; never
 // rename an existing one (downstream queries and exports depend on them).
 const (
-	ActionBookingCreated     = "booking.created"
-	ActionBookingCancelled   = "booking.cancelled"
-	ActionBookingRescheduled = "booking.rescheduled"
+	ActionBookingCreated            = "booking.created"
+	ActionBookingCancelled          = "booking.cancelled"
+	ActionBookingRescheduled        = "booking.rescheduled"
+	ActionBookingPaid               = "booking.paid"
+	ActionBookingRefunded           = "booking.refunded"
+	ActionBookingAttendanceRecorded = "booking.attendance_recorded"
 
 	ActionCoachingSessionConducted = "coaching_session.conducted"
 
+	ActionCoachingCreditGranted  = "coaching_credit.granted"
+	ActionCoachingCreditConsumed = "coaching_credit.consumed"
+	ActionCoachingCreditRestored = "coaching_credit.restored"
+	ActionCoachingCreditExpired  = "coaching_credit.expired"
+
 	ActionRecordingCreated = "recording.created"
 	ActionRecordingDeleted = "recording.deleted"
 
@@ -32,6 +42,12 @@ const (
 	ActionReviewUpdated = "review.updated"
 	ActionReviewDeleted = "review.deleted"
 
+	ActionReviewThreadStateChanged = "review.thread_state_changed"
+
+	ActionChapterCreated = "chapter.created"
+	ActionChapterUpdated = "chapter.updated"
+	ActionChapterDeleted = "chapter.deleted"
+
 	ActionGroupCreated = "group.created"
 	ActionGroupUpdated = "group.updated"
 	ActionGroupDeleted = "group.deleted"
@@ -43,6 +59,7 @@ const (
 	ActionGroupInviteCreated  = "group_invite.created"
 	ActionGroupInviteAccepted = "group_invite.accepted"
 	ActionGroupInviteRevoked  = "group_invite.revoked"
+	ActionGroupInviteDeclined = "group_invite.declined"
 
 	ActionAssetDeleted = "asset.deleted"
 	ActionVideoDeleted = "video.deleted"
@@ -0,0 +1,224 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Export formats.
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+// exportPageSize bounds how many rows are held in memory at once.
+const exportPageSize = 500
+
+// ExportOptions selects the events to export. At least one of SubjectID,
+// GroupID or an explicit window must narrow the export; the caller enforces
+// that. With Redact set, personal data of anyone but SubjectID is withheld.
+type ExportOptions struct {
+	SubjectID string
+	GroupID   string
+	From      time.Time
+	To        time.Time
+	Format    string
+	Redact    bool
+}
+
+// TxBeginner starts a transaction with options. *pgxpool.Pool satisfies it.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+}
+
+// Export streams the selected events to w, oldest first, and returns how many
+// were written. All pages are read in one REPEATABLE READ, READ ONLY
+// transaction: the snapshot keeps pages consistent, and the ACCESS SHARE lock
+// taken on every scanned partition is held until the export ends, so a
+// concurrent DropExpiredPartitions cannot remove rows mid-stream — it skips
+// the partition instead and retries on its next run.
+func Export(ctx context.Context, b TxBeginner, w io.Writer, opts ExportOptions) (int, error) {
+	tx, err := b.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	return exportEvents(ctx, db.New(tx), w, opts)
+}
+
+func exportEvents(ctx context.Context, q db.Querier, w io.Writer, opts ExportOptions) (int, error) {
+	out, err := newEventWriter(w, opts.Format)
+	if err != nil {
+		return 0, err
+	}
+	labels := &labelResolver{q: q, cache: map[string]string{}}
+
+	params := db.ExportAuditEventsParams{
+		OccurredFrom: pgtype.Timestamptz{Time: opts.From, Valid: true},
+		OccurredTo:   pgtype.Timestamptz{Time: opts.To, Valid: true},
+		GroupID:      opts.GroupID,
+		SubjectID:    opts.SubjectID,
+		PageLimit:    exportPageSize,
+	}
+	written := 0
+	for {
+		rows, err := q.ExportAuditEvents(ctx, params)
+		if err != nil {
+			return written, err
+		}
+		for _, row := range rows {
+			view, err := exportView(ctx, row, opts, labels)
+			if err != nil {
+				return written, fmt.Errorf("event %s: %w", view.ID, err)
+			}
+			if err := out.Write(view); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if err := out.Flush(); err != nil {
+			return written, err
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		if len(rows) < exportPageSize {
+			return written, nil
+		}
+		last := rows[len(rows)-1]
+		params.HasCursor = true
+		params.CursorOccurredAt = last.OccurredAt
+		params.CursorID = last.ID
+	}
+}
+
+func exportView(ctx context.Context, e db.AuditEvent, opts ExportOptions, labels *labelResolver) (eventView, error) {
+	view := toEventView(e)
+	if opts.Redact && (opts.SubjectID == "" || view.ActorID != opts.SubjectID) {
+		view.ActorLabel = ""
+	} else if view.ActorType == "user" && view.ActorLabel == "" {
+		label, err := labels.resolve(ctx, view.ActorID)
+		if err != nil {
+			return view, err
+		}
+		view.ActorLabel = label
+	}
+	if !opts.Redact {
+		return view, nil
+	}
+
+	var err error
+	if view.OldValues, err = redactSnapshot(e.ResourceType, view.ResourceID, e.OldValues, opts.SubjectID); err != nil {
+		return view, err
+	}
+	if view.NewValues, err = redactSnapshot(e.ResourceType, view.ResourceID, e.NewValues, opts.SubjectID); err != nil {
+		return view, err
+	}
+	if view.Metadata, err = redactMetadata(e.Metadata, view.ActorID, opts.SubjectID); err != nil {
+		return view, err
+	}
+	return view, nil
+}
+
+// labelResolver fills in actor labels for events recorded without one, using
+// the actor's current profile name.
+type labelResolver struct {
+	q     db.Querier
+	cache map[string]string
+}
+
+func (r *labelResolver) resolve(ctx context.Context, userID string) (string, error) {
+	if userID == "" {
+		return "", nil
+	}
+	if label, ok := r.cache[userID]; ok {
+		return label, nil
+	}
+	prefs, err := r.q.GetUserPreferences(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+	label := strings.TrimSpace(prefs.DisplayName)
+	if label == "" {
+		label = strings.TrimSpace(prefs.FirstName + " " + prefs.LastName)
+	}
+	r.cache[userID] = label
+	return label, nil
+}
+
+type eventWriter interface {
+	Write(v eventView) error
+	Flush() error
+}
+
+func newEventWriter(w io.Writer, format string) (eventWriter, error) {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(v eventView) error { return n.enc.Encode(v) }
+func (n *ndjsonWriter) Flush() error            { return nil }
+
+var csvHeader = []string{
+	"id", "occurred_at", "actor_type", "actor_id", "actor_label", "action",
+	"resource_type", "resource_id", "group_id", "old_values", "new_values", "metadata",
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(v eventView) error {
+	return c.w.Write([]string{
+		v.ID,
+		v.OccurredAt.Format(time.RFC3339Nano),
+		v.ActorType,
+		v.ActorID,
+		csvSafe(v.ActorLabel),
+		v.Action,
+		v.ResourceType,
+		v.ResourceID,
+		v.GroupID,
+		string(v.OldValues),
+		string(v.NewValues),
+		string(v.Metadata),
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvSafe neutralises user-controlled text that a spreadsheet would otherwise
+// evaluate as a formula.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,212 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func exportRow(b byte, resourceType, resourceID string, actorID string, newValues string) db.AuditEvent {
+	row := auditEventRow(b, time.Date(2026, 9, 1, 12, 0, int(b), 0, time.UTC))
+	row.ResourceType = resourceType
+	row.ResourceID = pgtype.Text{String: resourceID, Valid: resourceID != ""}
+	row.ActorID = pgtype.Text{String: actorID, Valid: true}
+	row.ActorLabel = pgtype.Text{}
+	row.NewValues = []byte(newValues)
+	return row
+}
+
+func decodeNDJSON(t *testing.T, b []byte) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("decode line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestExportEvents_RedactsOthersPersonalData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	own := exportRow(1, ResourceReview, "r-1", "student-1", `{"_v":1,"video_id":"v","author_id":"student-1","content":"my words"}`)
+	own.Metadata = []byte(`{"ip":"10.0.0.1","request_id":"req-1"}`)
+	other := exportRow(2, ResourceReview, "r-2", "expert-1", `{"_v":1,"video_id":"v","author_id":"expert-1","content":"their words"}`)
+	other.Metadata = []byte(`{"ip":"10.0.0.2","user_agent":"x","request_id":"req-2"}`)
+	future := exportRow(3, ResourceProfile, "student-1", "student-1", `{"_v":99,"first_name":"Ann"}`)
+
+	q.EXPECT().ExportAuditEvents(gomock.Any(), gomock.Any()).Return([]db.AuditEvent{own, other, future}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "student-1").Return(db.UserPreference{DisplayName: "Ann"}, nil)
+
+	var buf bytes.Buffer
+	n, err := exportEvents(context.Background(), q, &buf, ExportOptions{SubjectID: "student-1", Format: FormatNDJSON, Redact: true})
+	if err != nil {
+		t.Fatalf("exportEvents: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("n = %d, want 3", n)
+	}
+	lines := decodeNDJSON(t, buf.Bytes())
+
+	if lines[0]["actor_label"] != "Ann" {
+		t.Errorf("subject's label = %v, want resolved Ann", lines[0]["actor_label"])
+	}
+	if got := lines[0]["new_values"].(map[string]any)["content"]; got != "my words" {
+		t.Errorf("subject's own content = %v", got)
+	}
+	if got := lines[0]["metadata"].(map[string]any)["ip"]; got != "10.0.0.1" {
+		t.Errorf("subject's own ip = %v", got)
+	}
+
+	if _, ok := lines[1]["actor_label"]; ok {
+		t.Errorf("third-party label leaked: %v", lines[1]["actor_label"])
+	}
+	if got := lines[1]["new_values"].(map[string]any)["content"]; got != redactedValue {
+		t.Errorf("third-party content = %v, want redacted", got)
+	}
+	meta := lines[1]["metadata"].(map[string]any)
+	if _, ok := meta["ip"]; ok || meta["request_id"] != "req-2" {
+		t.Errorf("third-party metadata = %v", meta)
+	}
+
+	if nv := lines[2]["new_values"].(map[string]any); nv["_redacted"] != true || nv["first_name"] != nil {
+		t.Errorf("unknown snapshot version not withheld: %v", nv)
+	}
+}
+
+func TestExportEvents_PagesWithKeysetCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	page := make([]db.AuditEvent, exportPageSize)
+	for i := range page {
+		page[i] = exportRow(byte(i%50), ResourceGroup, "g-1", "", `{"_v":1,"name":"Academy"}`)
+		page[i].ActorType = "system"
+	}
+	last := page[len(page)-1]
+	gomock.InOrder(
+		q.EXPECT().ExportAuditEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.ExportAuditEventsParams) ([]db.AuditEvent, error) {
+				if arg.HasCursor || arg.GroupID != "g-1" || arg.PageLimit != exportPageSize {
+					t.Errorf("first page params = %+v", arg)
+				}
+				return page, nil
+			}),
+		q.EXPECT().ExportAuditEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg db.ExportAuditEventsParams) ([]db.AuditEvent, error) {
+				if !arg.HasCursor || arg.CursorID != last.ID || arg.CursorOccurredAt != last.OccurredAt {
+					t.Errorf("second page params = %+v", arg)
+				}
+				return page[:1], nil
+			}),
+	)
+
+	var buf bytes.Buffer
+	n, err := exportEvents(context.Background(), q, &buf, ExportOptions{GroupID: "g-1", Format: FormatNDJSON})
+	if err != nil {
+		t.Fatalf("exportEvents: %v", err)
+	}
+	if n != exportPageSize+1 {
+		t.Errorf("n = %d, want %d", n, exportPageSize+1)
+	}
+}
+
+func TestExportEvents_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	row := exportRow(1, ResourceGroup, "g-1", "user-1", `{"_v":1,"name":"Academy, Inc."}`)
+	row.ActorLabel = pgtype.Text{String: "=cmd()", Valid: true}
+	q.EXPECT().ExportAuditEvents(gomock.Any(), gomock.Any()).Return([]db.AuditEvent{row}, nil)
+
+	var buf bytes.Buffer
+	if _, err := exportEvents(context.Background(), q, &buf, ExportOptions{GroupID: "g-1", Format: FormatCSV}); err != nil {
+		t.Fatalf("exportEvents: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 2 || strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("records = %v", records)
+	}
+	if records[1][4] != "'=cmd()" {
+		t.Errorf("actor_label = %q, want formula neutralised", records[1][4])
+	}
+	if records[1][10] != `{"_v":1,"name":"Academy, Inc."}` {
+		t.Errorf("new_values = %q", records[1][10])
+	}
+}
+
+func TestExportEvents_UnknownActorKeepsEmptyLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+
+	row := exportRow(1, ResourceGroup, "g-1", "gone-user", `{"_v":1,"name":"Academy"}`)
+	q.EXPECT().ExportAuditEvents(gomock.Any(), gomock.Any()).Return([]db.AuditEvent{row, row}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "gone-user").Return(db.UserPreference{}, pgx.ErrNoRows).Times(1)
+
+	var buf bytes.Buffer
+	if _, err := exportEvents(context.Background(), q, &buf, ExportOptions{GroupID: "g-1", Format: FormatNDJSON}); err != nil {
+		t.Fatalf("exportEvents: %v", err)
+	}
+	for _, line := range decodeNDJSON(t, buf.Bytes()) {
+		if _, ok := line["actor_label"]; ok {
+			t.Errorf("actor_label = %v, want omitted", line["actor_label"])
+		}
+	}
+}
+
+// Every snapshot version the code writes must have a redaction rule, or its
+// contents are withheld from every redacted export.
+func TestPIIRulesCoverCurrentSnapshots(t *testing.T) {
+	current := map[string]int{
+		ResourceBooking:         BookingSnapshotOf(db.CoachingBooking{}).V,
+		ResourceCoachingCredit:  CreditGrantSnapshotOf(db.CoachingCreditGrant{}, pgtype.UUID{}).V,
+		ResourceReview:          ReviewSnapshotOf(db.VideoReview{}).V,
+		ResourceChapter:         ChapterSnapshotOf(db.VideoChapter{}).V,
+		ResourceGroup:           GroupSnapshotOf(db.Group{}).V,
+		ResourceGroupMembership: GroupMembershipSnapshotOf("", pgtype.UUID{}, "").V,
+		ResourceGroupInvite:     GroupInviteSnapshotOf(db.GroupInvitation{}).V,
+		ResourceProfile:         ProfileSnapshotOf(db.UserPreference{}).V,
+		ResourceAsset:           AssetSnapshotOf(db.Asset{}, 0).V,
+		ResourceVideo:           VideoSnapshotOf(db.Video{}, "").V,
+	}
+	for resourceType, v := range current {
+		if _, ok := piiRules[resourceType][v]; !ok {
+			t.Errorf("no piiRules entry for %s v%d", resourceType, v)
+		}
+	}
+}
+
+func TestRedactSnapshot_ProfileOwnedByResource(t *testing.T) {
+	raw := []byte(`{"_v":1,"first_name":"Ann","language":"en"}`)
+
+	got, err := redactSnapshot(ResourceProfile, "user-1", raw, "user-1")
+	if err != nil || string(got) != string(raw) {
+		t.Errorf("own profile = %s, %v", got, err)
+	}
+	got, err = redactSnapshot(ResourceProfile, "user-2", raw, "user-1")
+	if err != nil {
+		t.Fatalf("redactSnapshot: %v", err)
+	}
+	var m map[string]any
+	json.Unmarshal(got, &m)
+	if m["first_name"] != redactedValue || m["language"] != "en" {
+		t.Errorf("other profile = %s", got)
+	}
+}
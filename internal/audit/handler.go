@@ -1,10 +1,12 @@
 package audit
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -12,20 +14,22 @@ import (
 // DefaultRetentionDays is the fallback retention when AUDIT_RETENTION_DAYS is unset.
 const DefaultRetentionDays = 1095 // 3 years
 
-// Handler exposes the scheduler-triggered maintenance endpoint.
+// Handler exposes the scheduler-triggered maintenance and verification
+// endpoints and the admin audit trail API.
 type Handler struct {
 	pool      *pgxpool.Pool
+	q         db.Querier
 	logger    *slog.Logger
 	retention time.Duration
 }
 
-// NewHandler constructs the audit maintenance handler. retention <= 0 falls
-// back to DefaultRetentionDays.
-func NewHandler(pool *pgxpool.Pool, logger *slog.Logger, retention time.Duration) *Handler {
+// NewHandler constructs the audit handler. retention <= 0 falls back to
+// DefaultRetentionDays.
+func NewHandler(pool *pgxpool.Pool, q db.Querier, logger *slog.Logger, retention time.Duration) *Handler {
 	if retention <= 0 {
 		retention = time.Duration(DefaultRetentionDays) * 24 * time.Hour
 	}
-	return &Handler{pool: pool, logger: logger, retention: retention}
+	return &Handler{pool: pool, q: q, logger: logger, retention: retention}
 }
 
 // RunMaintenance ensures upcoming partitions exist and drops expired ones.
@@ -39,12 +43,56 @@ func (h *Handler) RunMaintenance(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "maintenance failed", http.StatusInternalServerError)
 		return
 	}
-	if err := DropExpiredPartitions(ctx, h.pool, h.retention); err != nil {
+	skipped, err := DropExpiredPartitions(ctx, h.pool, h.retention)
+	if err != nil {
 		log.ErrorContext(ctx, "audit_drop_expired_failed", slog.String("component", "audit"), slog.Any("err", err))
 		http.Error(w, "maintenance failed", http.StatusInternalServerError)
 		return
 	}
+	if len(skipped) > 0 {
+		log.WarnContext(ctx, "audit_drop_expired_skipped_busy",
+			slog.String("component", "audit"),
+			slog.Any("partitions", skipped),
+		)
+	}
 
 	log.InfoContext(ctx, "audit_maintenance_ran", slog.String("component", "audit"))
 	w.WriteHeader(http.StatusOK)
 }
+
+// RunVerify walks the hash chain of every partition and reports the first
+// broken link. Protected by the scheduler secret. Responds 409 with the report
+// when the chain is broken so the scheduler job fails loudly.
+func (h *Handler) RunVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	report, err := VerifyChain(ctx, h.pool)
+	if err != nil {
+		log.ErrorContext(ctx, "audit_chain_verify_failed", slog.String("component", "audit"), slog.Any("err", err))
+		http.Error(w, "verification failed", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if report.FirstBreak != nil {
+		status = http.StatusConflict
+		log.ErrorContext(ctx, "audit_chain_broken",
+			slog.String("component", "audit"),
+			slog.String("partition", report.FirstBreak.Partition),
+			slog.Int64("seq", report.FirstBreak.Seq),
+			slog.String("event_id", report.FirstBreak.EventID),
+			slog.String("reason", report.FirstBreak.Reason),
+		)
+	} else {
+		log.InfoContext(ctx, "audit_chain_verified",
+			slog.String("component", "audit"),
+			slog.Int64("events", report.Events),
+			slog.Any("heads", report.Partitions),
+		)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -2,11 +2,13 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,7 +16,8 @@ import (
 const monthsAhead = 3
 
 // EnsurePartitions creates the monthly partitions for the previous month through
-// monthsAhead future months. Idempotent: existing partitions are left untouched.
+// monthsAhead future months. Idempotent: existing partitions are left
+// untouched. Hash-chain heads are created by the first event of each shard.
 func EnsurePartitions(ctx context.Context, pool *pgxpool.Pool) error {
 	now := time.Now().UTC()
 	base := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
@@ -33,14 +36,22 @@ func EnsurePartitions(ctx context.Context, pool *pgxpool.Pool) error {
 	return nil
 }
 
+// dropLockTimeout bounds how long a partition drop waits for its locks. DROP
+// needs ACCESS EXCLUSIVE on audit_events itself, and while it queues every
+// audited INSERT queues behind it — so an export holding the table must make
+// the drop give up quickly rather than stall business writes.
+const dropLockTimeout = "2s"
+
 // DropExpiredPartitions drops partitions whose entire range is older than
 // retention. Uses DROP TABLE (DDL), which the append-only trigger does not block.
-func DropExpiredPartitions(ctx context.Context, pool *pgxpool.Pool, retention time.Duration) error {
+// Partitions still being read (e.g. by a running Export) are skipped and
+// returned; the next daily run retries them.
+func DropExpiredPartitions(ctx context.Context, pool *pgxpool.Pool, retention time.Duration) (skipped []string, err error) {
 	cutoff := time.Now().UTC().Add(-retention)
 	rows, err := pool.Query(ctx,
 		`SELECT inhrelid::regclass::text FROM pg_inherits WHERE inhparent = 'audit_events'::regclass`)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -48,12 +59,12 @@ func DropExpiredPartitions(ctx context.Context, pool *pgxpool.Pool, retention ti
 	for rows.Next() {
 		var name string
 		if err := rows.Scan(&name); err != nil {
-			return err
+			return nil, err
 		}
 		names = append(names, name)
 	}
 	if err := rows.Err(); err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, name := range names {
@@ -68,12 +79,45 @@ func DropExpiredPartitions(ctx context.Context, pool *pgxpool.Pool, retention ti
 			if i := strings.LastIndexByte(name, '.'); i >= 0 {
 				ident = pgx.Identifier{name[:i], name[i+1:]}
 			}
-			if _, err := pool.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, ident.Sanitize())); err != nil {
-				return fmt.Errorf("drop partition %s: %w", name, err)
+			if err := dropPartition(ctx, pool, ident, bareName(name)); err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "55P03" { // lock_not_available
+					skipped = append(skipped, name)
+					continue
+				}
+				return skipped, fmt.Errorf("drop partition %s: %w", name, err)
 			}
 		}
 	}
-	return nil
+	return skipped, nil
+}
+
+// dropPartition drops one partition and, in the same transaction, turns its
+// chain heads into tombstones. Each head is sealed first if no later partition
+// has done so yet, so the following partition's genesis stays verifiable after
+// the rows it chained to are gone.
+func dropPartition(ctx context.Context, pool *pgxpool.Pool, ident pgx.Identifier, name string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	if _, err := tx.Exec(ctx, `SET LOCAL lock_timeout = '`+dropLockTimeout+`'`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, ident.Sanitize())); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE audit_chain_heads
+		SET sealed_hash = CASE WHEN sealed_seq IS NULL THEN head_hash ELSE sealed_hash END,
+		    sealed_seq = COALESCE(sealed_seq, head_seq),
+		    dropped_at = NOW(),
+		    updated_at = NOW()
+		WHERE partition_name = $1`, name); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func partitionName(monthStart time.Time) string {
@@ -81,16 +125,23 @@ func partitionName(monthStart time.Time) string {
 }
 
 // partitionUpperBound parses the exclusive upper bound from a managed partition
-// name like "audit_events_2026_06" -> 2026-07-01. regclass::text returns a
+// name like "audit_events_2026_06" -> 2026-07-01.
+func partitionUpperBound(name string) (time.Time, bool) {
+	start, ok := partitionStart(name)
+	if !ok {
+		return time.Time{}, false
+	}
+	return start.AddDate(0, 1, 0), true
+}
+
+// partitionStart parses the inclusive lower bound from a managed partition name
+// like "audit_events_2026_06" -> 2026-06-01. regclass::text returns a
 // schema-qualified name ("public.audit_events_2026_06") when the table's schema
 // is not on the search_path, so any qualifier is stripped before matching —
 // otherwise expired partitions would silently never be dropped.
-func partitionUpperBound(name string) (time.Time, bool) {
-	bare := name
-	if i := strings.LastIndexByte(bare, '.'); i >= 0 {
-		bare = bare[i+1:]
-	}
+func partitionStart(name string) (time.Time, bool) {
 	const prefix = "audit_events_"
+	bare := bareName(name)
 	if len(bare) <= len(prefix) || bare[:len(prefix)] != prefix {
 		return time.Time{}, false
 	}
@@ -98,5 +149,12 @@ func partitionUpperBound(name string) (time.Time, bool) {
 	if err != nil {
 		return time.Time{}, false
 	}
-	return start.AddDate(0, 1, 0), true
+	return start, true
+}
+
+func bareName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
 )
 
 func TestIntegration_EnsurePartitions_CreatesRollingWindow(t *testing.T) {
@@ -52,9 +53,13 @@ func TestIntegration_DropExpiredPartitions_RemovesOld(t *testing.T) {
 		t.Fatalf("create old partition: %v", err)
 	}
 
-	if err := audit.DropExpiredPartitions(ctx, pool, audit.DefaultRetentionDays*24*time.Hour); err != nil {
+	skipped, err := audit.DropExpiredPartitions(ctx, pool, audit.DefaultRetentionDays*24*time.Hour)
+	if err != nil {
 		t.Fatalf("DropExpiredPartitions: %v", err)
 	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want none", skipped)
+	}
 
 	var exists bool
 	if err := pool.QueryRow(ctx,
@@ -65,3 +70,44 @@ func TestIntegration_DropExpiredPartitions_RemovesOld(t *testing.T) {
 		t.Errorf("expired partition %s still exists", name)
 	}
 }
+
+func TestIntegration_DropExpiredPartitions_SkipsPartitionUnderExport(t *testing.T) {
+	pool := testdb.New(t)
+	ctx := context.Background()
+	if err := audit.EnsurePartitions(ctx, pool); err != nil {
+		t.Fatalf("EnsurePartitions: %v", err)
+	}
+
+	old := time.Now().AddDate(0, -40, 0)
+	start := time.Date(old.Year(), old.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	name := "audit_events_" + start.Format("2006_01")
+	if _, err := pool.Exec(ctx, fmt.Sprintf(
+		`CREATE TABLE %s PARTITION OF audit_events FOR VALUES FROM ('%s') TO ('%s')`,
+		name, start.Format("2006-01-02"), end.Format("2006-01-02"))); err != nil {
+		t.Fatalf("create old partition: %v", err)
+	}
+
+	// An export's snapshot transaction scanning the old month holds its lock.
+	reader, err := pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		t.Fatalf("begin reader: %v", err)
+	}
+	defer reader.Rollback(ctx) //nolint:errcheck
+	if _, err := reader.Exec(ctx, `SELECT count(*) FROM audit_events WHERE occurred_at >= $1 AND occurred_at < $2`, start, end); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+
+	skipped, err := audit.DropExpiredPartitions(ctx, pool, audit.DefaultRetentionDays*24*time.Hour)
+	if err != nil {
+		t.Fatalf("DropExpiredPartitions: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != name {
+		t.Fatalf("skipped = %v, want [%s]", skipped, name)
+	}
+
+	reader.Rollback(ctx) //nolint:errcheck
+	if skipped, err := audit.DropExpiredPartitions(ctx, pool, audit.DefaultRetentionDays*24*time.Hour); err != nil || len(skipped) != 0 {
+		t.Fatalf("retry: skipped=%v err=%v", skipped, err)
+	}
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// ProfileWriter is the audited auth.ProfileWriter: it runs the profile writes
+// in one transaction and records profile.updated with before/after snapshots.
+// Names are recorded only as changed flags.
+type ProfileWriter struct {
+	r Runner
+}
+
+// NewProfileWriter returns a ProfileWriter running on r.
+func NewProfileWriter(r Runner) *ProfileWriter {
+	return &ProfileWriter{r: r}
+}
+
+// WriteProfile implements auth.ProfileWriter.
+func (w *ProfileWriter) WriteProfile(ctx context.Context, userID string, fn func(q db.Querier) (db.UserPreference, error)) (db.UserPreference, error) {
+	var prefs db.UserPreference
+	err := w.r.InTx(ctx, func(tx Tx) error {
+		before, err := tx.GetUserPreferences(ctx, userID)
+		hadBefore := err == nil
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if !hadBefore {
+			before = db.UserPreference{}
+		}
+		prefs, err = fn(tx)
+		if err != nil {
+			return err
+		}
+		e := Event{
+			Action:       ActionProfileUpdated,
+			ResourceType: ResourceProfile,
+			ResourceID:   userID,
+			NewValues:    ProfileChangeSnapshotOf(before, prefs),
+		}
+		if hadBefore {
+			e.OldValues = ProfileSnapshotOf(before)
+		}
+		return tx.Record(ctx, e)
+	})
+	return prefs, err
+}
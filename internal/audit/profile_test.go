@@ -0,0 +1,88 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProfileWriter_RecordsBeforeAndAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	w := audit.NewProfileWriter(runner)
+
+	q.EXPECT().GetUserPreferences(gomock.Any(), "user-1").Return(db.UserPreference{FirstName: "Olga", Avatar: "data"}, nil)
+
+	got, err := w.WriteProfile(context.Background(), "user-1", func(db.Querier) (db.UserPreference, error) {
+		return db.UserPreference{FirstName: "Nina"}, nil
+	})
+	if err != nil {
+		t.Fatalf("WriteProfile: %v", err)
+	}
+	if got.FirstName != "Nina" {
+		t.Errorf("returned prefs = %+v", got)
+	}
+	events := runner.Events()
+	if len(events) != 1 || events[0].Action != audit.ActionProfileUpdated || events[0].ResourceID != "user-1" {
+		t.Fatalf("events = %+v", events)
+	}
+	old := events[0].OldValues.(audit.ProfileSnapshot)
+	if !old.HasAvatar || old.FirstNameChanged {
+		t.Errorf("old snapshot = %+v", old)
+	}
+	next := events[0].NewValues.(audit.ProfileSnapshot)
+	if !next.FirstNameChanged || next.LastNameChanged || next.DisplayNameChanged {
+		t.Errorf("new snapshot = %+v", next)
+	}
+	if raw, _ := json.Marshal(events[0]); strings.Contains(string(raw), "Olga") || strings.Contains(string(raw), "Nina") {
+		t.Errorf("names leaked into the event: %s", raw)
+	}
+}
+
+func TestProfileWriter_FirstWriteHasNoOldValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	w := audit.NewProfileWriter(runner)
+
+	q.EXPECT().GetUserPreferences(gomock.Any(), "user-1").Return(db.UserPreference{}, pgx.ErrNoRows)
+
+	if _, err := w.WriteProfile(context.Background(), "user-1", func(db.Querier) (db.UserPreference, error) {
+		return db.UserPreference{FirstName: "New"}, nil
+	}); err != nil {
+		t.Fatalf("WriteProfile: %v", err)
+	}
+	if events := runner.Events(); len(events) != 1 || events[0].OldValues != nil {
+		t.Fatalf("events = %+v", events)
+	}
+}
+
+func TestProfileWriter_FailedWriteRecordsNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	w := audit.NewProfileWriter(runner)
+
+	q.EXPECT().GetUserPreferences(gomock.Any(), "user-1").Return(db.UserPreference{}, nil)
+
+	boom := errors.New("boom")
+	_, err := w.WriteProfile(context.Background(), "user-1", func(db.Querier) (db.UserPreference, error) {
+		return db.UserPreference{}, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if len(runner.Events()) != 0 {
+		t.Errorf("events recorded for failed write: %v", runner.Actions())
+	}
+}
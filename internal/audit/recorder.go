@@ -7,6 +7,7 @@ import (
 
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -21,7 +22,9 @@ func NewRecorder() *Recorder { return &Recorder{} }
 // deliberately pgx.Tx — NOT db.DBTX — so the atomicity contract is enforced at
 // compile time: the audit row commits or rolls back together with the caller's
 // mutation, never independently. The actor is resolved from the context; absent
-// a user it is recorded as the system actor.
+// a user it is recorded as the system actor. The event is appended to the
+// actor's shard of the partition's hash chain, which locks that shard's head
+// until tx ends.
 func (r *Recorder) Record(ctx context.Context, tx pgx.Tx, e Event) error {
 	q := db.New(tx)
 
@@ -66,7 +69,8 @@ func (r *Recorder) Record(ctx context.Context, tx pgx.Tx, e Event) error {
 		return err
 	}
 
-	return q.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+	return appendToChain(ctx, q, &db.CreateAuditEventParams{
+		ID:           pgtype.UUID{Bytes: uuid.New(), Valid: true},
 		ActorID:      actorID,
 		ActorType:    actorType,
 		ActorLabel:   actorLabel,
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"encoding/json"
+)
+
+// piiRule names the personal data in one snapshot schema version: the
+// top-level keys holding it and the key identifying whose data it is. An empty
+// owner means the event's resource_id is the data subject (profiles).
+type piiRule struct {
+	owner  string
+	fields []string
+}
+
+// piiRules is keyed by resource type and snapshot `_v`. Every snapshot version
+// in snapshot.go MUST have an entry here — a version missing from this table
+// fails closed and the whole snapshot is withheld from redacted exports.
+var piiRules = map[string]map[int]piiRule{
+	ResourceBooking:         {1: {owner: "cancelled_by", fields: []string{"cancellation_reason"}}},
+	ResourceCoachingCredit:  {1: {}},
+	ResourceReview:          {1: {owner: "author_id", fields: []string{"content"}}},
+	ResourceChapter:         {1: {}},
+	ResourceGroup:           {1: {}},
+	ResourceGroupMembership: {1: {}},
+	ResourceGroupInvite:     {1: {}},
+	ResourceProfile:         {1: {fields: []string{"first_name", "last_name", "display_name", "timezone"}}, 2: {fields: []string{"timezone"}}},
+	ResourceAsset:           {1: {owner: "student_id", fields: []string{"title", "description"}}},
+	ResourceVideo:           {1: {}},
+}
+
+// redactedValue replaces a withheld personal-data field.
+const redactedValue = "[redacted]"
+
+// redactSnapshot strips personal data from a stored snapshot unless it belongs
+// to subject. An empty subject redacts everyone's data.
+func redactSnapshot(resourceType, resourceID string, raw []byte, subject string) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var snap map[string]any
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return nil, err
+	}
+	version, _ := snap["_v"].(float64)
+	rule, ok := piiRules[resourceType][int(version)]
+	if !ok {
+		return json.Marshal(map[string]any{"_v": snap["_v"], "_redacted": true})
+	}
+	if len(rule.fields) == 0 {
+		return raw, nil
+	}
+
+	owner := resourceID
+	if rule.owner != "" {
+		owner, _ = snap[rule.owner].(string)
+	}
+	if subject != "" && owner == subject {
+		return raw, nil
+	}
+	for _, f := range rule.fields {
+		if v, present := snap[f]; present && v != "" {
+			snap[f] = redactedValue
+		}
+	}
+	return json.Marshal(snap)
+}
+
+// redactMetadata drops the client fingerprint (IP, user agent) unless the
+// actor is the export subject. The request id is kept for correlation.
+func redactMetadata(raw []byte, actorID, subject string) (json.RawMessage, error) {
+	if len(raw) == 0 || (subject != "" && actorID == subject) {
+		return rawJSON(raw), nil
+	}
+	var meta map[string]any
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, err
+	}
+	delete(meta, "ip")
+	delete(meta, "user_agent")
+	if len(meta) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(meta)
+}
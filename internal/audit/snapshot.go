@@ -0,0 +1,318 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Snapshot DTOs for Event.OldValues/NewValues. Each carries its own `_v`
+// schema version; additive fields keep the version, renamed/removed fields or
+// changed semantics bump it. Changelog:
+//
+//	booking          v1 — initial; series_id added; payment_status added;
+//	                      class_id added; attendance_outcome added
+//	coaching_credit  v1 — initial (grant note deliberately omitted: free text)
+//	review           v1 — initial; annotation_version/annotation_shapes added
+//	                      (the shapes themselves are too large for the trail);
+//	                      end_seconds added
+//	chapter          v1 — initial
+//	group            v1 — initial (avatar deliberately omitted: base64 blob)
+//	group_membership v1 — initial
+//	group_invite     v1 — initial (invitee email deliberately omitted)
+//	profile          v1 — initial (avatar reduced to has_avatar)
+//	                 v2 — names replaced by *_changed flags on the new
+//	                      values (names can never be erased from the trail)
+//	asset            v1 — initial
+//	video            v1 — initial
+
+// BookingSnapshot is the audited shape of a coaching booking.
+type BookingSnapshot struct {
+	V                  int    `json:"_v"`
+	ExpertID           string `json:"expert_id"`
+	StudentID          string `json:"student_id"`
+	SessionTypeID      string `json:"session_type_id"`
+	ScheduledAt        string `json:"scheduled_at"`
+	DurationMin        int32  `json:"duration_min"`
+	IsCancelled        bool   `json:"is_cancelled"`
+	CancelledBy        string `json:"cancelled_by,omitempty"`
+	CancellationReason string `json:"cancellation_reason,omitempty"`
+	SeriesID           string `json:"series_id,omitempty"`
+	PaymentStatus      string `json:"payment_status,omitempty"`
+	ClassID            string `json:"class_id,omitempty"`
+	AttendanceOutcome  string `json:"attendance_outcome,omitempty"`
+}
+
+// BookingSnapshotOf curates b for the trail.
+func BookingSnapshotOf(b db.CoachingBooking) BookingSnapshot {
+	return BookingSnapshot{
+		V:                  1,
+		ExpertID:           b.ExpertID,
+		StudentID:          b.StudentID,
+		SessionTypeID:      pgutil.UUIDToString(b.SessionTypeID),
+		ScheduledAt:        formatTime(b.ScheduledAt),
+		DurationMin:        b.DurationMinutes,
+		IsCancelled:        b.IsCancelled,
+		CancelledBy:        b.CancelledBy.String,
+		CancellationReason: b.CancellationReason.String,
+		SeriesID:           pgutil.UUIDToString(b.SeriesID),
+		PaymentStatus:      string(b.PaymentStatus.CoachingPaymentStatus),
+		ClassID:            pgutil.UUIDToString(b.ClassID),
+		AttendanceOutcome:  string(b.AttendanceOutcome.CoachingAttendanceOutcome),
+	}
+}
+
+// CreditGrantSnapshot is the audited shape of a credit grant. BookingID names
+// the booking a credit was consumed by or restored from.
+type CreditGrantSnapshot struct {
+	V         int    `json:"_v"`
+	StudentID string `json:"student_id"`
+	ExpertID  string `json:"expert_id"`
+	Quantity  int32  `json:"quantity"`
+	Remaining int32  `json:"remaining"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	GrantedBy string `json:"granted_by"`
+	BookingID string `json:"booking_id,omitempty"`
+}
+
+// CreditGrantSnapshotOf curates g for the trail; bookingID may be invalid.
+func CreditGrantSnapshotOf(g db.CoachingCreditGrant, bookingID pgtype.UUID) CreditGrantSnapshot {
+	return CreditGrantSnapshot{
+		V:         1,
+		StudentID: g.StudentID,
+		ExpertID:  g.ExpertID,
+		Quantity:  g.Quantity,
+		Remaining: g.Remaining,
+		ExpiresAt: formatTime(g.ExpiresAt),
+		GrantedBy: g.GrantedBy,
+		BookingID: pgutil.UUIDToString(bookingID),
+	}
+}
+
+// ReviewSnapshot is the audited shape of a video review comment.
+type ReviewSnapshot struct {
+	V                 int    `json:"_v"`
+	VideoID           string `json:"video_id"`
+	ParentID          string `json:"parent_id,omitempty"`
+	AuthorID          string `json:"author_id,omitempty"`
+	TimestampSeconds  *int32 `json:"timestamp_seconds,omitempty"`
+	EndSeconds        *int32 `json:"end_seconds,omitempty"`
+	Content           string `json:"content"`
+	AnnotationVersion int32  `json:"annotation_version,omitempty"`
+	AnnotationShapes  int    `json:"annotation_shapes,omitempty"`
+	// ThreadState is only set on top-level reviews.
+	ThreadState string `json:"thread_state,omitempty"`
+}
+
+// ReviewSnapshotOf curates r for the trail.
+func ReviewSnapshotOf(r db.VideoReview) ReviewSnapshot {
+	s := ReviewSnapshot{
+		V:        1,
+		VideoID:  pgutil.UUIDToString(r.VideoID),
+		ParentID: pgutil.UUIDToString(r.ParentID),
+		AuthorID: r.AuthorID.String,
+		Content:  r.Content,
+		// Only the version and shape count: the layer itself can be large.
+		AnnotationVersion: r.AnnotationVersion,
+	}
+	if r.TimestampSeconds.Valid {
+		ts := r.TimestampSeconds.Int32
+		s.TimestampSeconds = &ts
+	}
+	if r.EndSeconds.Valid {
+		end := r.EndSeconds.Int32
+		s.EndSeconds = &end
+	}
+	if !r.ParentID.Valid {
+		s.ThreadState = string(r.ThreadState)
+	}
+	if len(r.Annotation) > 0 {
+		var layer struct {
+			Shapes []json.RawMessage `json:"shapes"`
+		}
+		if json.Unmarshal(r.Annotation, &layer) == nil {
+			s.AnnotationShapes = len(layer.Shapes)
+		}
+	}
+	return s
+}
+
+// ChapterSnapshot is the audited shape of a named video chapter.
+type ChapterSnapshot struct {
+	V            int    `json:"_v"`
+	VideoID      string `json:"video_id"`
+	Title        string `json:"title"`
+	StartSeconds int32  `json:"start_seconds"`
+	EndSeconds   *int32 `json:"end_seconds,omitempty"`
+	CreatedBy    string `json:"created_by"`
+}
+
+// ChapterSnapshotOf curates c for the trail.
+func ChapterSnapshotOf(c db.VideoChapter) ChapterSnapshot {
+	s := ChapterSnapshot{
+		V:            1,
+		VideoID:      pgutil.UUIDToString(c.VideoID),
+		Title:        c.Title,
+		StartSeconds: c.StartSeconds,
+		CreatedBy:    c.CreatedBy,
+	}
+	if c.EndSeconds.Valid {
+		end := c.EndSeconds.Int32
+		s.EndSeconds = &end
+	}
+	return s
+}
+
+// GroupSnapshot is the audited shape of a group.
+type GroupSnapshot struct {
+	V           int    `json:"_v"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	OwnerID     string `json:"owner_id"`
+}
+
+// GroupSnapshotOf curates g for the trail.
+func GroupSnapshotOf(g db.Group) GroupSnapshot {
+	return GroupSnapshot{
+		V:           1,
+		Name:        g.Name,
+		Description: g.Description,
+		OwnerID:     g.OwnerID,
+	}
+}
+
+// GroupMembershipSnapshot is the audited shape of a user_groups row.
+type GroupMembershipSnapshot struct {
+	V       int    `json:"_v"`
+	UserID  string `json:"user_id"`
+	GroupID string `json:"group_id"`
+	Via     string `json:"via,omitempty"` // "owner" | "invitation" | "access_code"
+}
+
+// GroupMembershipSnapshotOf curates a membership for the trail.
+func GroupMembershipSnapshotOf(userID string, groupID pgtype.UUID, via string) GroupMembershipSnapshot {
+	return GroupMembershipSnapshot{
+		V:       1,
+		UserID:  userID,
+		GroupID: pgutil.UUIDToString(groupID),
+		Via:     via,
+	}
+}
+
+// GroupInviteSnapshot is the audited shape of a group invitation.
+type GroupInviteSnapshot struct {
+	V         int    `json:"_v"`
+	InviterID string `json:"inviter_id"`
+	Delivery  string `json:"delivery"` // "email" | "link"
+	Status    string `json:"status"`
+}
+
+// GroupInviteSnapshotOf curates inv for the trail.
+func GroupInviteSnapshotOf(inv db.GroupInvitation) GroupInviteSnapshot {
+	delivery := "link"
+	if inv.Email.Valid && inv.Email.String != "" {
+		delivery = "email"
+	}
+	return GroupInviteSnapshot{
+		V:         1,
+		InviterID: inv.InviterID,
+		Delivery:  delivery,
+		Status:    string(inv.Status),
+	}
+}
+
+// ProfileSnapshot is the audited shape of a user's profile preferences. Names
+// are personal data the trail can never erase, so only whether each one
+// changed is recorded, and only on the new values.
+type ProfileSnapshot struct {
+	V                  int    `json:"_v"`
+	Language           string `json:"language"`
+	Timezone           string `json:"timezone"`
+	HasAvatar          bool   `json:"has_avatar"`
+	EmailNotifications bool   `json:"email_notifications"`
+	PushNotifications  bool   `json:"push_notifications"`
+	FirstNameChanged   bool   `json:"first_name_changed,omitempty"`
+	LastNameChanged    bool   `json:"last_name_changed,omitempty"`
+	DisplayNameChanged bool   `json:"display_name_changed,omitempty"`
+}
+
+// ProfileSnapshotOf curates p for the trail.
+func ProfileSnapshotOf(p db.UserPreference) ProfileSnapshot {
+	return ProfileSnapshot{
+		V:                  2,
+		Language:           string(p.Language),
+		Timezone:           p.Timezone,
+		HasAvatar:          p.Avatar != "",
+		EmailNotifications: p.EmailNotificationsEnabled,
+		PushNotifications:  p.PushNotificationsEnabled,
+	}
+}
+
+// ProfileChangeSnapshotOf curates after for the trail, flagging the names that
+// differ from before (the zero value on a first write).
+func ProfileChangeSnapshotOf(before, after db.UserPreference) ProfileSnapshot {
+	s := ProfileSnapshotOf(after)
+	s.FirstNameChanged = before.FirstName != after.FirstName
+	s.LastNameChanged = before.LastName != after.LastName
+	s.DisplayNameChanged = before.DisplayName != after.DisplayName
+	return s
+}
+
+// AssetSnapshot is the audited shape of an uploaded asset. StudentID is the
+// uploader, named as in reports so subject exports find it.
+type AssetSnapshot struct {
+	V           int    `json:"_v"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	StudentID   string `json:"student_id"`
+	Status      string `json:"status"`
+	VideoCount  int    `json:"video_count"`
+}
+
+// AssetSnapshotOf curates a for the trail.
+func AssetSnapshotOf(a db.Asset, videoCount int) AssetSnapshot {
+	return AssetSnapshot{
+		V:           1,
+		Title:       a.Name,
+		Description: a.Description,
+		StudentID:   a.OwnerID,
+		Status:      string(a.Status),
+		VideoCount:  videoCount,
+	}
+}
+
+// VideoSnapshot is the audited shape of one video part of an asset.
+type VideoSnapshot struct {
+	V               int      `json:"_v"`
+	AssetID         string   `json:"asset_id"`
+	StudentID       string   `json:"student_id"`
+	MuxAssetID      string   `json:"mux_asset_id,omitempty"`
+	Status          string   `json:"status"`
+	DurationSeconds *float64 `json:"duration_seconds,omitempty"`
+}
+
+// VideoSnapshotOf curates v, uploaded by studentID, for the trail.
+func VideoSnapshotOf(v db.Video, studentID string) VideoSnapshot {
+	s := VideoSnapshot{
+		V:          1,
+		AssetID:    pgutil.UUIDToString(v.AssetID),
+		StudentID:  studentID,
+		MuxAssetID: v.MuxAssetID.String,
+		Status:     string(v.Status),
+	}
+	if v.DurationSeconds.Valid {
+		d := v.DurationSeconds.Float64
+		s.DurationSeconds = &d
+	}
+	return s
+}
+
+func formatTime(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
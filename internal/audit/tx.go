@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// Tx is the unit of work an audited handler mutates through: the sqlc queries
+// bound to one transaction plus Record, which appends an audit event to that
+// same transaction.
+type Tx interface {
+	db.Querier
+	Record(ctx context.Context, e Event) error
+}
+
+// Runner executes fn inside a transaction. The transaction commits only when fn
+// returns nil, so a mutation and its audit events land together or not at all.
+type Runner interface {
+	InTx(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// Beginner starts a transaction. *pgxpool.Pool satisfies it.
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// NewRunner returns a Runner that opens transactions on b and records events
+// through rec.
+func NewRunner(b Beginner, rec *Recorder) Runner {
+	return &pgxRunner{b: b, rec: rec}
+}
+
+type pgxRunner struct {
+	b   Beginner
+	rec *Recorder
+}
+
+func (r *pgxRunner) InTx(ctx context.Context, fn func(tx Tx) error) error {
+	tx, err := r.b.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	if err := fn(&pgxTx{Queries: db.New(tx), tx: tx, rec: r.rec}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+type pgxTx struct {
+	*db.Queries
+	tx  pgx.Tx
+	rec *Recorder
+}
+
+func (t *pgxTx) Record(ctx context.Context, e Event) error {
+	return t.rec.Record(ctx, t.tx, e)
+}
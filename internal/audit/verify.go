@@ -0,0 +1,206 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// verifyPageSize bounds how many chained rows are held in memory at once.
+const verifyPageSize = 1000
+
+// ChainBreak locates the first link that fails verification.
+type ChainBreak struct {
+	Partition string `json:"partition"`
+	Shard     int32  `json:"shard"`
+	Seq       int64  `json:"seq,omitempty"`
+	EventID   string `json:"event_id,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// ChainHeadReport is one shard's chain head in a partition. Copying these out of the
+// database (logs, tickets) is what makes a wholesale rewrite detectable.
+type ChainHeadReport struct {
+	Partition string `json:"partition"`
+	Shard     int32  `json:"shard"`
+	Seq       int64  `json:"seq"`
+	Hash      string `json:"hash,omitempty"`
+	Dropped   bool   `json:"dropped,omitempty"`
+}
+
+// VerifyReport is the outcome of one walk over every chain.
+type VerifyReport struct {
+	Partitions []ChainHeadReport `json:"partitions"`
+	Events     int64             `json:"events"`
+	FirstBreak *ChainBreak       `json:"first_break,omitempty"`
+}
+
+// VerifyChain walks every shard's chain in one REPEATABLE READ, READ ONLY
+// snapshot, so appends racing the walk cannot look like a broken tail.
+func VerifyChain(ctx context.Context, b TxBeginner) (VerifyReport, error) {
+	tx, err := b.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	return verifyChain(ctx, db.New(tx))
+}
+
+// chainKey names one shard's chain in one partition.
+type chainKey struct {
+	partition string
+	shard     int32
+}
+
+func verifyChain(ctx context.Context, q db.Querier) (VerifyReport, error) {
+	var report VerifyReport
+	heads, err := q.ListAuditChainHeads(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	byKey := make(map[chainKey]db.AuditChainHead, len(heads))
+	for _, h := range heads {
+		byKey[chainKey{h.PartitionName, h.Shard}] = h
+	}
+	// chainedSince is the earliest chained event per live partition; any
+	// unchained row after it was slipped in beside the chains.
+	var partitions []string
+	chainedSince := map[string]pgtype.Timestamptz{}
+	for _, h := range heads {
+		start, ok := partitionStart(h.PartitionName)
+		if !ok {
+			continue
+		}
+		report.Partitions = append(report.Partitions, ChainHeadReport{
+			Partition: h.PartitionName,
+			Shard:     h.Shard,
+			Seq:       h.HeadSeq,
+			Hash:      hex.EncodeToString(h.HeadHash),
+			Dropped:   h.DroppedAt.Valid,
+		})
+		if h.DroppedAt.Valid {
+			continue
+		}
+
+		prev, hasPrev := byKey[chainKey{partitionName(start.AddDate(0, -1, 0)), h.Shard}]
+		brk, n, err := verifyShard(ctx, q, h, start, prev, hasPrev)
+		report.Events += n
+		if err != nil || brk != nil {
+			report.FirstBreak = brk
+			return report, err
+		}
+
+		if since, seen := chainedSince[h.PartitionName]; !seen {
+			partitions = append(partitions, h.PartitionName)
+			chainedSince[h.PartitionName] = h.ChainedSince
+		} else if h.ChainedSince.Valid && (!since.Valid || h.ChainedSince.Time.Before(since.Time)) {
+			chainedSince[h.PartitionName] = h.ChainedSince
+		}
+	}
+
+	for _, name := range partitions {
+		since := chainedSince[name]
+		if !since.Valid {
+			continue
+		}
+		start, _ := partitionStart(name)
+		n, err := q.CountUnchainedAuditEvents(ctx, db.CountUnchainedAuditEventsParams{
+			OccurredFrom: pgtype.Timestamptz{Time: start, Valid: true},
+			OccurredTo:   pgtype.Timestamptz{Time: start.AddDate(0, 1, 0), Valid: true},
+			ChainedSince: since,
+		})
+		if err != nil {
+			return report, err
+		}
+		if n > 0 {
+			report.FirstBreak = &ChainBreak{Partition: name, Reason: fmt.Sprintf("%d events were inserted outside the chain", n)}
+			return report, nil
+		}
+	}
+	return report, nil
+}
+
+// verifyShard checks one shard's chain in a partition: its genesis against the
+// predecessor's seal, every link and hash in chain_seq order, and the recorded
+// seal and head.
+func verifyShard(ctx context.Context, q db.Querier, h db.AuditChainHead, start time.Time, prev db.AuditChainHead, hasPrev bool) (*ChainBreak, int64, error) {
+	brk := func(seq int64, id pgtype.UUID, format string, args ...any) *ChainBreak {
+		return &ChainBreak{Partition: h.PartitionName, Shard: h.Shard, Seq: seq, EventID: pgutil.UUIDToString(id), Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if h.HeadSeq > 0 {
+		want := zeroHash
+		if hasPrev {
+			if !prev.SealedSeq.Valid {
+				return brk(0, pgtype.UUID{}, "predecessor %s was never sealed", prev.PartitionName), 0, nil
+			}
+			if prev.SealedHash != nil {
+				want = prev.SealedHash
+			}
+		}
+		if !bytes.Equal(h.GenesisHash, want) {
+			return brk(0, pgtype.UUID{}, "genesis does not match the sealed head of the previous partition"), 0, nil
+		}
+	}
+
+	window := db.ListAuditChainEventsParams{
+		OccurredFrom: pgtype.Timestamptz{Time: start, Valid: true},
+		OccurredTo:   pgtype.Timestamptz{Time: start.AddDate(0, 1, 0), Valid: true},
+		ChainShard:   h.Shard,
+		AfterSeq:     pgtype.Int8{Valid: true},
+		PageLimit:    verifyPageSize,
+	}
+	expectPrev := h.GenesisHash
+	var seq int64
+	var lastID pgtype.UUID
+	for {
+		rows, err := q.ListAuditChainEvents(ctx, window)
+		if err != nil {
+			return nil, seq, err
+		}
+		for _, e := range rows {
+			if e.ChainSeq.Int64 != seq+1 {
+				return brk(seq+1, e.ID, "expected chain_seq %d, found %d", seq+1, e.ChainSeq.Int64), seq, nil
+			}
+			seq++
+			if !bytes.Equal(e.PrevHash, expectPrev) {
+				return brk(seq, e.ID, "prev_hash does not match the preceding event"), seq, nil
+			}
+			sum, err := chainHash(e.PrevHash, chainRecordOfEvent(e))
+			if err != nil {
+				return brk(seq, e.ID, "unhashable contents: %v", err), seq, nil
+			}
+			if !bytes.Equal(sum, e.Hash) {
+				return brk(seq, e.ID, "contents do not match the stored hash"), seq, nil
+			}
+			if h.SealedSeq.Valid && seq == h.SealedSeq.Int64 && !bytes.Equal(e.Hash, h.SealedHash) {
+				return brk(seq, e.ID, "event differs from the sealed head the next partition chains to"), seq, nil
+			}
+			expectPrev = e.Hash
+			lastID = e.ID
+		}
+		if len(rows) < verifyPageSize {
+			break
+		}
+		window.AfterSeq = pgtype.Int8{Int64: seq, Valid: true}
+	}
+
+	if seq != h.HeadSeq {
+		return brk(seq, lastID, "chain ends at %d but the head records %d", seq, h.HeadSeq), seq, nil
+	}
+	if seq > 0 && !bytes.Equal(expectPrev, h.HeadHash) {
+		return brk(seq, lastID, "last event does not match the recorded head"), seq, nil
+	}
+	if h.SealedSeq.Valid && h.SealedSeq.Int64 > seq {
+		return brk(seq, lastID, "sealed at %d beyond the end of the chain", h.SealedSeq.Int64), seq, nil
+	}
+	return nil, seq, nil
+}
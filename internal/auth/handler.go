@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -184,11 +185,44 @@ func frontendRedirectURL(returnTo string) string {
 }
 
 type Handler struct {
-	logger *slog.Logger
-	q      db.Querier
-	workos UserManagement
+	logger   *slog.Logger
+	q        db.Querier
+	workos   UserManagement
+	profiles ProfileWriter
 }
 
+// ProfileWriter runs the preference writes of UpdateMe as one unit of work and
+// returns the resulting row. The audited implementation lives in
+// internal/audit, which imports this package for the actor and therefore
+// cannot be imported back; SetProfileWriter injects it at startup.
+type ProfileWriter interface {
+	WriteProfile(ctx context.Context, userID string, fn func(q db.Querier) (db.UserPreference, error)) (db.UserPreference, error)
+}
+
+// SetProfileWriter routes UpdateMe through w. Without one, UpdateMe writes
+// directly through the handler's Querier.
+func (h *Handler) SetProfileWriter(w ProfileWriter) {
+	h.profiles = w
+}
+
+func (h *Handler) writeProfile(ctx context.Context, userID string, fn func(q db.Querier) (db.UserPreference, error)) (db.UserPreference, error) {
+	if h.profiles == nil {
+		return fn(h.q)
+	}
+	return h.profiles.WriteProfile(ctx, userID, fn)
+}
+
+// profileWriteError tags a failed UpdateMe step with its log event and the
+// message returned to the client.
+type profileWriteError struct {
+	event   string
+	message string
+	err     error
+}
+
+func (e *profileWriteError) Error() string { return e.err.Error() }
+func (e *profileWriteError) Unwrap() error { return e.err }
+
 func NewHandler(logger *slog.Logger, q db.Querier, workos UserManagement) *Handler {
 	usermanagement.SetAPIKey(os.Getenv("WORKOS_API_KEY"))
 	return &Handler{
@@ -857,69 +891,53 @@ func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 		// Sent but empty → deliberate reset to the derived name; keep the default.
 	}
 
-	prefs, err := h.q.UpdateUserProfilePreferences(ctx, db.UpdateUserProfilePreferencesParams{
-		UserID:      user.ID,
-		Language:    db.LanguageCode(req.Language),
-		Timezone:    req.Timezone,
-		FirstName:   req.FirstName,
-		LastName:    req.LastName,
-		DisplayName: displayName,
+	prefs, err := h.writeProfile(ctx, user.ID, func(q db.Querier) (db.UserPreference, error) {
+		prefs, err := q.UpdateUserProfilePreferences(ctx, db.UpdateUserProfilePreferencesParams{
+			UserID:      user.ID,
+			Language:    db.LanguageCode(req.Language),
+			Timezone:    req.Timezone,
+			FirstName:   req.FirstName,
+			LastName:    req.LastName,
+			DisplayName: displayName,
+		})
+		if err != nil {
+			return prefs, &profileWriteError{"auth_update_profile_preferences_failed", "Failed to update profile settings", err}
+		}
+		if req.EmailPreferences != nil {
+			if prefs, err = q.UpdateUserEmailPreferences(ctx, preferences.ToUpdateParams(user.ID, *req.EmailPreferences)); err != nil {
+				return prefs, &profileWriteError{"auth_update_email_preferences_failed", "Failed to update email preferences", err}
+			}
+		}
+		if req.PushPreferences != nil {
+			if prefs, err = q.UpdateUserPushPreferences(ctx, preferences.ToUpdatePushParams(user.ID, *req.PushPreferences)); err != nil {
+				return prefs, &profileWriteError{"auth_update_push_preferences_failed", "Failed to update push preferences", err}
+			}
+		}
+		if req.Avatar != nil {
+			if prefs, err = q.UpdateUserAvatar(ctx, db.UpdateUserAvatarParams{
+				UserID: user.ID,
+				Avatar: *req.Avatar,
+			}); err != nil {
+				return prefs, &profileWriteError{"auth_update_avatar_failed", "Failed to update avatar", err}
+			}
+		}
+		return prefs, nil
 	})
 	if err != nil {
-		h.logger.ErrorContext(ctx, "auth_update_profile_preferences_failed",
+		event, message := "auth_update_profile_failed", "Failed to update user settings"
+		var werr *profileWriteError
+		if errors.As(err, &werr) {
+			event, message = werr.event, werr.message
+		}
+		h.logger.ErrorContext(ctx, event,
 			slog.String("component", "auth"),
 			slog.String("user_id", user.ID),
 			slog.Any("err", err),
 		)
-		http.Error(w, "Failed to update profile settings", http.StatusInternalServerError)
+		http.Error(w, message, http.StatusInternalServerError)
 		return
 	}
 
-	if req.EmailPreferences != nil {
-		updated, err := h.q.UpdateUserEmailPreferences(ctx, preferences.ToUpdateParams(user.ID, *req.EmailPreferences))
-		if err != nil {
-			h.logger.ErrorContext(ctx, "auth_update_email_preferences_failed",
-				slog.String("component", "auth"),
-				slog.String("user_id", user.ID),
-				slog.Any("err", err),
-			)
-			http.Error(w, "Failed to update email preferences", http.StatusInternalServerError)
-			return
-		}
-		prefs = updated
-	}
-
-	if req.PushPreferences != nil {
-		updated, err := h.q.UpdateUserPushPreferences(ctx, preferences.ToUpdatePushParams(user.ID, *req.PushPreferences))
-		if err != nil {
-			h.logger.ErrorContext(ctx, "auth_update_push_preferences_failed",
-				slog.String("component", "auth"),
-				slog.String("user_id", user.ID),
-				slog.Any("err", err),
-			)
-			http.Error(w, "Failed to update push preferences", http.StatusInternalServerError)
-			return
-		}
-		prefs = updated
-	}
-
-	if req.Avatar != nil {
-		updated, err := h.q.UpdateUserAvatar(ctx, db.UpdateUserAvatarParams{
-			UserID: user.ID,
-			Avatar: *req.Avatar,
-		})
-		if err != nil {
-			h.logger.ErrorContext(ctx, "auth_update_avatar_failed",
-				slog.String("component", "auth"),
-				slog.String("user_id", user.ID),
-				slog.Any("err", err),
-			)
-			http.Error(w, "Failed to update avatar", http.StatusInternalServerError)
-			return
-		}
-		prefs = updated
-	}
-
 	// Try to update WorkOS user
 	go func() {
 		// Update WorkOS user
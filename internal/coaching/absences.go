@@ -0,0 +1,442 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxAbsenceDays bounds a single absence; longer breaks take several.
+const maxAbsenceDays = 366
+
+type absenceResponse struct {
+	ID        string    `json:"id"`
+	ExpertID  string    `json:"expert_id"`
+	StartsOn  string    `json:"starts_on"`
+	EndsOn    string    `json:"ends_on"`
+	Reason    *string   `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toAbsenceResponse(a db.CoachingAbsence) absenceResponse {
+	resp := absenceResponse{
+		ID:        uuidToString(a.ID),
+		ExpertID:  a.ExpertID,
+		StartsOn:  a.StartsOn.Time.Format("2006-01-02"),
+		EndsOn:    a.EndsOn.Time.Format("2006-01-02"),
+		CreatedAt: a.CreatedAt.Time,
+	}
+	if a.Reason.Valid {
+		resp.Reason = &a.Reason.String
+	}
+	return resp
+}
+
+type createAbsenceRequest struct {
+	StartsOn string  `json:"starts_on"`
+	EndsOn   string  `json:"ends_on"`
+	Reason   *string `json:"reason,omitempty"`
+	// CancelBookings cancels the expert's sessions inside the absence, except
+	// those starting within the cancellation notice.
+	CancelBookings bool `json:"cancel_bookings"`
+}
+
+type createAbsenceResponse struct {
+	Absence           absenceResponse   `json:"absence"`
+	CancelledBookings []bookingResponse `json:"cancelled_bookings"`
+	// KeptBookings are sessions inside the absence that still take place.
+	KeptBookings []bookingResponse `json:"kept_bookings"`
+}
+
+func (h *Handler) ListAbsences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	var fromDate, toDate pgtype.Date
+	_ = fromDate.Scan(now.Format("2006-01-02"))
+	_ = toDate.Scan(now.AddDate(0, AbsenceRangeMonths, 0).Format("2006-01-02"))
+
+	absences, err := h.q.ListAbsences(ctx, db.ListAbsencesParams{
+		ExpertID: user.ID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_absences_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list absences", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]absenceResponse, len(absences))
+	for i, a := range absences {
+		resp[i] = toAbsenceResponse(a)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListAbsenceConflicts returns the caller's upcoming sessions inside a
+// prospective absence so the UI can warn before it is saved.
+func (h *Handler) ListAbsenceConflicts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	loc, err := userLocation(ctx, h.q, user.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "get_timezone_failed",
+			slog.String("component", "coaching"),
+			slog.String("expert_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get expert timezone", http.StatusInternalServerError)
+		return
+	}
+
+	startsOn, endsOn, errMsg := parseAbsenceRange(r.URL.Query().Get("starts_on"), r.URL.Query().Get("ends_on"), loc)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	conflicts, err := absenceConflicts(ctx, h.q, user.ID, absenceInterval(startsOn, endsOn, loc))
+	if err != nil {
+		log.ErrorContext(ctx, "list_absence_conflicts_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list conflicting bookings", http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := h.bookingResponses(ctx, conflicts)
+	if err != nil {
+		log.ErrorContext(ctx, "resolve_booking_users_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to resolve booking users", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) CreateAbsence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := userLocation(ctx, h.q, user.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "get_timezone_failed",
+			slog.String("component", "coaching"),
+			slog.String("expert_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get expert timezone", http.StatusInternalServerError)
+		return
+	}
+
+	startsOn, endsOn, errMsg := parseAbsenceRange(req.StartsOn, req.EndsOn, loc)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var reason pgtype.Text
+	if req.Reason != nil {
+		reason = pgtype.Text{String: *req.Reason, Valid: true}
+	}
+	arg := db.CreateAbsenceParams{
+		ExpertID: user.ID,
+		StartsOn: startsOn,
+		EndsOn:   endsOn,
+		Reason:   reason,
+	}
+	iv := absenceInterval(startsOn, endsOn, loc)
+
+	var absence db.CoachingAbsence
+	var cancelled, kept []db.CoachingBooking
+	if req.CancelBookings {
+		absence, cancelled, kept, err = h.createAbsenceCancellingBookings(ctx, arg, iv)
+	} else {
+		absence, err = h.q.CreateAbsence(ctx, arg)
+		if err == nil {
+			kept, err = absenceConflicts(ctx, h.q, user.ID, iv)
+		}
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "create_absence_failed",
+			slog.String("component", "coaching"),
+			slog.String("expert_id", user.ID),
+			slog.Bool("cancel_bookings", req.CancelBookings),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create absence", http.StatusInternalServerError)
+		return
+	}
+
+	for i, b := range cancelled {
+		cancelled[i] = h.refundBooking(ctx, b)
+		h.sendCancellationEmail(ctx, b, user.ID)
+		h.recordBookingCancelledNotification(b, user.ID)
+	}
+	if len(cancelled) > 0 || len(kept) > 0 {
+		log.InfoContext(ctx, "absence_bookings_handled",
+			slog.String("component", "coaching"),
+			slog.String("absence_id", uuidToString(absence.ID)),
+			slog.Int("cancelled", len(cancelled)),
+			slog.Int("kept", len(kept)),
+		)
+	}
+
+	resp := createAbsenceResponse{Absence: toAbsenceResponse(absence)}
+	if resp.CancelledBookings, err = h.bookingResponses(ctx, cancelled); err == nil {
+		resp.KeptBookings, err = h.bookingResponses(ctx, kept)
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "resolve_booking_users_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to resolve booking users", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// createAbsenceCancellingBookings stores the absence and cancels the sessions
+// inside it in one transaction. Sessions starting within their session type's
+// cancellation notice cannot be cancelled and are returned as kept.
+func (h *Handler) createAbsenceCancellingBookings(ctx context.Context, arg db.CreateAbsenceParams, iv busyInterval) (absence db.CoachingAbsence, cancelled, kept []db.CoachingBooking, err error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingAbsence{}, nil, nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	absence, err = qtx.CreateAbsence(ctx, arg)
+	if err != nil {
+		return db.CoachingAbsence{}, nil, nil, err
+	}
+	conflicts, err := absenceConflicts(ctx, qtx, arg.ExpertID, iv)
+	if err != nil {
+		return db.CoachingAbsence{}, nil, nil, err
+	}
+	for _, b := range conflicts {
+		rules, err := h.bookingRulesFor(ctx, qtx, b)
+		if err != nil {
+			return db.CoachingAbsence{}, nil, nil, err
+		}
+		if rules.checkCancellation(time.Now(), b.ScheduledAt.Time) != "" {
+			kept = append(kept, b)
+			continue
+		}
+		updated, err := h.cancelBookingInTx(ctx, tx, b, db.CancelBookingParams{
+			ID:                 b.ID,
+			CancellationReason: arg.Reason,
+			CancelledBy:        pgtype.Text{String: arg.ExpertID, Valid: true},
+			ExpertID:           arg.ExpertID,
+		})
+		if errors.Is(err, errBookingAlreadyCancelled) {
+			// A participant cancelled it in the meantime.
+			continue
+		}
+		if err != nil {
+			return db.CoachingAbsence{}, nil, nil, err
+		}
+		cancelled = append(cancelled, updated)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingAbsence{}, nil, nil, err
+	}
+	return absence, cancelled, kept, nil
+}
+
+func (h *Handler) DeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	absenceID, err := parseUUID(chi.URLParam(r, "absenceID"))
+	if err != nil {
+		http.Error(w, "Invalid absence ID", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.q.DeleteAbsence(ctx, db.DeleteAbsenceParams{
+		ID:       absenceID,
+		ExpertID: user.ID,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "delete_absence_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete absence", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Absence not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseAbsenceRange parses inclusive YYYY-MM-DD bounds. The absence may not
+// have ended already in the expert's timezone. Returns an error string
+// suitable for http.Error.
+func parseAbsenceRange(startStr, endStr string, loc *time.Location) (start, end pgtype.Date, errMsg string) {
+	s, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, "Invalid starts_on (use YYYY-MM-DD)"
+	}
+	e, err := time.Parse("2006-01-02", endStr)
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, "Invalid ends_on (use YYYY-MM-DD)"
+	}
+	if e.Before(s) {
+		return pgtype.Date{}, pgtype.Date{}, "ends_on must not be before starts_on"
+	}
+	if days := int(e.Sub(s).Hours()/24) + 1; days > maxAbsenceDays {
+		return pgtype.Date{}, pgtype.Date{}, fmt.Sprintf("An absence can span at most %d days", maxAbsenceDays)
+	}
+	today, _ := time.Parse("2006-01-02", time.Now().In(loc).Format("2006-01-02"))
+	if e.Before(today) {
+		return pgtype.Date{}, pgtype.Date{}, "ends_on is in the past"
+	}
+	return pgtype.Date{Time: s, Valid: true}, pgtype.Date{Time: e, Valid: true}, ""
+}
+
+// absenceInterval covers the whole local days from startsOn through endsOn.
+func absenceInterval(startsOn, endsOn pgtype.Date, loc *time.Location) busyInterval {
+	s, e := startsOn.Time, endsOn.Time
+	return busyInterval{
+		Start: time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc).UTC(),
+		End:   time.Date(e.Year(), e.Month(), e.Day()+1, 0, 0, 0, 0, loc).UTC(),
+	}
+}
+
+// absenceConflicts returns the expert's active sessions overlapping iv that
+// have not started yet.
+func absenceConflicts(ctx context.Context, q db.Querier, expertID string, iv busyInterval) ([]db.CoachingBooking, error) {
+	// Sessions starting shortly before the absence can run into it.
+	from := iv.Start.Add(-time.Duration(MaxSessionDuration) * time.Minute)
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+	bookings, err := q.ListBookingsByExpertInRange(ctx, db.ListBookingsByExpertInRangeParams{
+		ExpertID:      expertID,
+		ScheduledAt:   pgtype.Timestamptz{Time: from, Valid: true},
+		ScheduledAt_2: pgtype.Timestamptz{Time: iv.End, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []db.CoachingBooking
+	for _, b := range bookings {
+		if overlapsBooking(iv.Start, iv.End, []db.CoachingBooking{b}) {
+			conflicts = append(conflicts, b)
+		}
+	}
+	return conflicts, nil
+}
+
+// listExpertBusy loads the time in [from, to) the expert cannot take
+// sessions in any group: absences, as whole days in loc, cached busy time
+// from external calendars, and slots held for waitlisted students.
+func listExpertBusy(ctx context.Context, q db.Querier, expertID string, loc *time.Location, from, to time.Time) ([]busyInterval, error) {
+	busy, err := listExternalBusy(ctx, q, expertID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromDate, toDate pgtype.Date
+	_ = fromDate.Scan(from.In(loc).Format("2006-01-02"))
+	_ = toDate.Scan(to.In(loc).Format("2006-01-02"))
+	absences, err := q.ListAbsences(ctx, db.ListAbsencesParams{
+		ExpertID: expertID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range absences {
+		busy = append(busy, absenceInterval(a.StartsOn, a.EndsOn, loc))
+	}
+
+	holds, err := q.ListWaitlistHoldsByExpertInRange(ctx, db.ListWaitlistHoldsByExpertInRangeParams{
+		ExpertID: expertID,
+		ToAt:     pgtype.Timestamptz{Time: to, Valid: true},
+		FromAt:   pgtype.Timestamptz{Time: from, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		start := hold.ScheduledAt.Time
+		busy = append(busy, busyInterval{Start: start, End: start.Add(time.Duration(hold.DurationMinutes) * time.Minute)})
+	}
+	return busy, nil
+}
+
+// bookingResponses renders bookings with participant names; it returns an
+// empty slice rather than nil so the JSON is always an array.
+func (h *Handler) bookingResponses(ctx context.Context, bookings []db.CoachingBooking) ([]bookingResponse, error) {
+	resp := make([]bookingResponse, 0, len(bookings))
+	if len(bookings) == 0 {
+		return resp, nil
+	}
+	pairs := make([][2]string, len(bookings))
+	for i, b := range bookings {
+		pairs[i] = [2]string{b.ExpertID, b.StudentID}
+	}
+	users, err := h.resolveUsers(ctx, collectUserIDs(pairs))
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bookings {
+		resp = append(resp, toBookingResponse(b, users, ""))
+	}
+	return resp, nil
+}
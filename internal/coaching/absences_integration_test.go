@@ -0,0 +1,125 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func pgDate(y int, m time.Month, d int) pgtype.Date {
+	return pgtype.Date{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func TestIntegration_ListAbsencesOverlappingRange(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	if _, err := q.CreateAbsence(ctx, db.CreateAbsenceParams{
+		ExpertID: "expert-1", StartsOn: pgDate(2030, 7, 10), EndsOn: pgDate(2030, 7, 1),
+	}); err == nil {
+		t.Fatal("absence ending before it starts was accepted")
+	}
+
+	summer, err := q.CreateAbsence(ctx, db.CreateAbsenceParams{
+		ExpertID: "expert-1", StartsOn: pgDate(2030, 7, 1), EndsOn: pgDate(2030, 7, 14),
+		Reason: pgtype.Text{String: "Vacation", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateAbsence: %v", err)
+	}
+	if _, err := q.CreateAbsence(ctx, db.CreateAbsenceParams{
+		ExpertID: "expert-2", StartsOn: pgDate(2030, 7, 1), EndsOn: pgDate(2030, 7, 14),
+	}); err != nil {
+		t.Fatalf("CreateAbsence other expert: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to pgtype.Date
+		want     int
+	}{
+		{"range inside", pgDate(2030, 7, 5), pgDate(2030, 7, 6), 1},
+		{"touches last day", pgDate(2030, 7, 14), pgDate(2030, 7, 20), 1},
+		{"touches first day", pgDate(2030, 6, 20), pgDate(2030, 7, 1), 1},
+		{"after", pgDate(2030, 7, 15), pgDate(2030, 7, 20), 0},
+	}
+	for _, tt := range tests {
+		got, err := q.ListAbsences(ctx, db.ListAbsencesParams{ExpertID: "expert-1", FromDate: tt.from, ToDate: tt.to})
+		if err != nil {
+			t.Fatalf("%s: ListAbsences: %v", tt.name, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d absences; want %d", tt.name, len(got), tt.want)
+		}
+	}
+
+	if n, err := q.DeleteAbsence(ctx, db.DeleteAbsenceParams{ID: summer.ID, ExpertID: "expert-2"}); err != nil || n != 0 {
+		t.Fatalf("DeleteAbsence by other expert = %d, %v; want 0", n, err)
+	}
+	if n, err := q.DeleteAbsence(ctx, db.DeleteAbsenceParams{ID: summer.ID, ExpertID: "expert-1"}); err != nil || n != 1 {
+		t.Fatalf("DeleteAbsence = %d, %v; want 1", n, err)
+	}
+}
+
+func TestIntegration_AvailabilityOverridesByGroupAndDate(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	other, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Other", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+
+	at := func(h int) pgtype.Time {
+		return pgtype.Time{Microseconds: int64(h) * int64(time.Hour/time.Microsecond), Valid: true}
+	}
+	create := func(groupID pgtype.UUID, d pgtype.Date, start, end int) error {
+		_, err := q.CreateAvailabilityOverride(ctx, db.CreateAvailabilityOverrideParams{
+			ExpertID: "expert-1", GroupID: groupID, OverrideDate: d, StartTime: at(start), EndTime: at(end),
+		})
+		return err
+	}
+	if err := create(group.ID, pgDate(2030, 7, 6), 14, 10); err == nil {
+		t.Fatal("override ending before it starts was accepted")
+	}
+	for _, c := range []struct {
+		groupID    pgtype.UUID
+		d          pgtype.Date
+		start, end int
+	}{
+		{group.ID, pgDate(2030, 7, 6), 14, 16},
+		{group.ID, pgDate(2030, 7, 6), 9, 11},
+		{group.ID, pgDate(2030, 8, 1), 9, 11},
+		{other.ID, pgDate(2030, 7, 6), 9, 11},
+	} {
+		if err := create(c.groupID, c.d, c.start, c.end); err != nil {
+			t.Fatalf("CreateAvailabilityOverride: %v", err)
+		}
+	}
+
+	got, err := q.ListAvailabilityOverrides(ctx, db.ListAvailabilityOverridesParams{
+		ExpertID: "expert-1", GroupID: group.ID, FromDate: pgDate(2030, 7, 1), ToDate: pgDate(2030, 7, 31),
+	})
+	if err != nil {
+		t.Fatalf("ListAvailabilityOverrides: %v", err)
+	}
+	if len(got) != 2 || got[0].StartTime != at(9) || got[1].StartTime != at(14) {
+		t.Fatalf("ListAvailabilityOverrides = %+v; want the two July windows of the group in order", got)
+	}
+
+	if n, err := q.DeleteAvailabilityOverride(ctx, db.DeleteAvailabilityOverrideParams{ID: got[0].ID, ExpertID: "expert-1"}); err != nil || n != 1 {
+		t.Fatalf("DeleteAvailabilityOverride = %d, %v; want 1", n, err)
+	}
+}
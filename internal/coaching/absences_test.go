@@ -0,0 +1,130 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseAbsenceRange(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		wantErr bool
+	}{
+		{"single day", today, today, false},
+		{"two weeks", "2099-07-01", "2099-07-14", false},
+		{"invalid start", "07/01/2099", "2099-07-14", true},
+		{"end before start", "2099-07-14", "2099-07-01", true},
+		{"too long", "2099-01-01", "2100-01-02", true},
+		{"already over", "2000-01-01", "2000-01-10", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, errMsg := parseAbsenceRange(tt.start, tt.end, time.UTC)
+			if (errMsg != "") != tt.wantErr {
+				t.Fatalf("parseAbsenceRange(%q, %q) = %q; want error %v", tt.start, tt.end, errMsg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAbsenceIntervalCoversLocalDays(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	start := pgtype.Date{Time: time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	end := pgtype.Date{Time: time.Date(2030, 7, 3, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	iv := absenceInterval(start, end, berlin)
+	if want := time.Date(2030, 6, 30, 22, 0, 0, 0, time.UTC); !iv.Start.Equal(want) {
+		t.Errorf("start = %v; want %v", iv.Start, want)
+	}
+	if want := time.Date(2030, 7, 3, 22, 0, 0, 0, time.UTC); !iv.End.Equal(want) {
+		t.Errorf("end = %v; want %v", iv.End, want)
+	}
+}
+
+func TestCreateAbsenceWarnsAboutBookingsInside(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t) // 2030-01-07 10:00 UTC
+
+	q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("UTC", nil)
+	q.EXPECT().CreateAbsence(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.CreateAbsenceParams) (db.CoachingAbsence, error) {
+			if arg.ExpertID != "expert-1" || arg.StartsOn.Time.Format("2006-01-02") != "2030-01-07" ||
+				arg.EndsOn.Time.Format("2006-01-02") != "2030-01-11" || arg.Reason.String != "Vacation" {
+				t.Errorf("unexpected absence %+v", arg)
+			}
+			return db.CoachingAbsence{
+				ExpertID: arg.ExpertID, StartsOn: arg.StartsOn, EndsOn: arg.EndsOn, Reason: arg.Reason,
+				CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			}, nil
+		},
+	)
+	// The range reaches back far enough for sessions running into the first day.
+	q.EXPECT().ListBookingsByExpertInRange(gomock.Any(), db.ListBookingsByExpertInRangeParams{
+		ExpertID:      "expert-1",
+		ScheduledAt:   pgtype.Timestamptz{Time: time.Date(2030, 1, 6, 22, 0, 0, 0, time.UTC), Valid: true},
+		ScheduledAt_2: pgtype.Timestamptz{Time: time.Date(2030, 1, 12, 0, 0, 0, 0, time.UTC), Valid: true},
+	}).Return([]db.CoachingBooking{
+		{ExpertID: "expert-1", ScheduledAt: pgtype.Timestamptz{Time: time.Date(2030, 1, 6, 23, 0, 0, 0, time.UTC), Valid: true}, DurationMinutes: 60},
+		b,
+	}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "expert-1").Return(db.UserPreference{UserID: "expert-1", FirstName: "Alex", LastName: "Coach"}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "student-1").Return(db.UserPreference{UserID: "student-1", FirstName: "Sam", LastName: "Student"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/groups/g/coaching/absences",
+		strings.NewReader(`{"starts_on":"2030-01-07","ends_on":"2030-01-11","reason":"Vacation"}`))
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, &auth.UserContext{ID: "expert-1"}))
+	rec := httptest.NewRecorder()
+	h.CreateAbsence(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var resp createAbsenceResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Absence.StartsOn != "2030-01-07" || resp.Absence.EndsOn != "2030-01-11" {
+		t.Errorf("absence = %+v", resp.Absence)
+	}
+	// The session ending at midnight before the absence is not a conflict.
+	if len(resp.CancelledBookings) != 0 || len(resp.KeptBookings) != 1 || resp.KeptBookings[0].ID != rescheduleTestBookingID {
+		t.Fatalf("cancelled %+v, kept %+v; want only the session inside kept", resp.CancelledBookings, resp.KeptBookings)
+	}
+}
+
+func TestListAbsenceConflictsRejectsInvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+
+	q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("UTC", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/groups/g/coaching/absences/conflicts?starts_on=2099-07-14&ends_on=2099-07-01", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, &auth.UserContext{ID: "expert-1"}))
+	rec := httptest.NewRecorder()
+	h.ListAbsenceConflicts(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d; want 400", rec.Code)
+	}
+}
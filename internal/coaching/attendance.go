@@ -0,0 +1,221 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// AttendanceMinSeconds is how long a participant must have been in the
+	// call to count as having shown up at all.
+	AttendanceMinSeconds = int32(60)
+	// AttendanceFullSharePercent is the share of the session both sides must
+	// have been present for to count as attended rather than partial.
+	AttendanceFullSharePercent = int32(50)
+	AttendanceBatchSize        = int32(50)
+
+	// No-show credit policies of a session type.
+	NoShowCreditForfeit = "forfeit"
+	NoShowCreditRestore = "restore"
+)
+
+// attendanceOutcome judges a session from the seconds each side spent in the
+// call. An absent expert outweighs an absent student: the student could not
+// have had the session either way.
+func attendanceOutcome(durationMinutes, studentSeconds, expertSeconds int32) db.CoachingAttendanceOutcome {
+	if expertSeconds < AttendanceMinSeconds {
+		return db.CoachingAttendanceOutcomeExpertNoShow
+	}
+	if studentSeconds < AttendanceMinSeconds {
+		return db.CoachingAttendanceOutcomeStudentNoShow
+	}
+	full := durationMinutes * 60 * AttendanceFullSharePercent / 100
+	if studentSeconds >= full && expertSeconds >= full {
+		return db.CoachingAttendanceOutcomeAttended
+	}
+	return db.CoachingAttendanceOutcomePartial
+}
+
+// recordAttendance counts a presence heartbeat of caller towards their time
+// in the call hosted by host. Only heartbeats within the scheduled session
+// count; being early or staying on afterwards does not.
+func (h *Handler) recordAttendance(ctx context.Context, host db.CoachingBooking, caller callParticipant) {
+	now := time.Now()
+	if now.Before(host.ScheduledAt.Time) || now.After(bookingEnd(host)) {
+		return
+	}
+	if err := h.q.RecordBookingAttendance(ctx, db.RecordBookingAttendanceParams{
+		BookingID:       host.ID,
+		ParticipantID:   caller.UserID,
+		ParticipantRole: caller.Role,
+		MaxGapSeconds:   int32(h.recordingPresenceTTL / time.Second),
+	}); err != nil {
+		logger.From(ctx, h.logger).WarnContext(ctx, "coaching_attendance_record_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(host.ID)),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// ProcessAttendance decides the attendance outcome of sessions that have
+// ended, applying the session type's no-show policy. Called by the
+// scheduler.
+func (h *Handler) ProcessAttendance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	due, err := h.q.ListBookingsAwaitingAttendance(ctx, db.ListBookingsAwaitingAttendanceParams{
+		EndGraceSeconds: int32(h.recordingEndGrace / time.Second),
+		LimitCount:      AttendanceBatchSize,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_bookings_awaiting_attendance_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list bookings awaiting attendance", http.StatusInternalServerError)
+		return
+	}
+
+	recorded, noShows, failed := 0, 0, 0
+	for _, b := range due {
+		outcome, err := h.settleAttendance(ctx, b)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			failed++
+			log.WarnContext(ctx, "settle_attendance_failed",
+				slog.String("component", "coaching"),
+				slog.String("booking_id", uuidToString(b.ID)),
+				slog.Any("err", err),
+			)
+			continue
+		}
+		recorded++
+		if isNoShow(outcome) {
+			noShows++
+		}
+	}
+
+	log.InfoContext(ctx, "attendance_processed",
+		slog.String("component", "coaching"),
+		slog.Int("recorded", recorded),
+		slog.Int("no_shows", noShows),
+		slog.Int("failed", failed),
+	)
+
+	writeJSON(w, http.StatusOK, map[string]int{"recorded": recorded, "no_shows": noShows, "failed": failed})
+}
+
+// settleAttendance records the outcome of b, gives back its credit when the
+// no-show policy says so and records booking.attendance_recorded, all in one
+// transaction. It returns pgx.ErrNoRows when the outcome was already settled.
+func (h *Handler) settleAttendance(ctx context.Context, b db.CoachingBooking) (db.CoachingAttendanceOutcome, error) {
+	call, err := resolveBookingCall(ctx, h.q, b)
+	if err != nil {
+		return "", err
+	}
+	attendance, err := h.q.ListBookingAttendance(ctx, call.Host.ID)
+	if err != nil {
+		return "", err
+	}
+	var studentSeconds, expertSeconds int32
+	for _, a := range attendance {
+		switch a.ParticipantID {
+		case b.StudentID:
+			studentSeconds = a.AttendedSeconds
+		case b.ExpertID:
+			expertSeconds = a.AttendedSeconds
+		}
+	}
+	outcome := attendanceOutcome(b.DurationMinutes, studentSeconds, expertSeconds)
+
+	st, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{ID: b.SessionTypeID, GroupID: b.GroupID})
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	updated, err := db.New(tx).SetBookingAttendance(ctx, db.SetBookingAttendanceParams{
+		ID:                b.ID,
+		AttendanceOutcome: db.NullCoachingAttendanceOutcome{CoachingAttendanceOutcome: outcome, Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	if restoresCredit(outcome, st.NoShowCreditPolicy) {
+		if err := h.restoreCreditInTx(ctx, tx, updated, ""); err != nil {
+			return "", err
+		}
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingAttendanceRecorded, updated, &b)); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	if isNoShow(outcome) && st.NotifyOwnerOnNoShow {
+		h.notifyOwnerOfNoShow(ctx, updated, outcome)
+	}
+	return outcome, nil
+}
+
+// restoresCredit reports whether a session with the given outcome gives its
+// credit back. A student who had no expert to meet always gets it back.
+func restoresCredit(outcome db.CoachingAttendanceOutcome, policy string) bool {
+	switch outcome {
+	case db.CoachingAttendanceOutcomeExpertNoShow:
+		return true
+	case db.CoachingAttendanceOutcomeStudentNoShow:
+		return policy == NoShowCreditRestore
+	}
+	return false
+}
+
+func isNoShow(outcome db.CoachingAttendanceOutcome) bool {
+	return outcome == db.CoachingAttendanceOutcomeStudentNoShow || outcome == db.CoachingAttendanceOutcomeExpertNoShow
+}
+
+// notifyOwnerOfNoShow tells the group owner that a session was missed. An
+// owner who is the expert that missed it is not told about it.
+func (h *Handler) notifyOwnerOfNoShow(ctx context.Context, b db.CoachingBooking, outcome db.CoachingAttendanceOutcome) {
+	group, err := h.q.GetGroup(ctx, b.GroupID)
+	if err != nil {
+		logger.From(ctx, h.logger).WarnContext(ctx, "no_show_fetch_group_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		return
+	}
+	if outcome == db.CoachingAttendanceOutcomeExpertNoShow && group.OwnerID == b.ExpertID {
+		return
+	}
+
+	notifications.Record(ctx, h.q, h.logger, group.OwnerID, notifications.TypeCoachingBookingNoShow, notifications.CoachingBookingNoShowPayload{
+		BookingID:   uuidToString(b.ID),
+		GroupID:     uuidToString(b.GroupID),
+		GroupName:   group.Name,
+		Outcome:     string(outcome),
+		StudentName: h.resolveParticipant(ctx, b.StudentID).name,
+		ExpertName:  h.resolveParticipant(ctx, b.ExpertID).name,
+		ScheduledAt: b.ScheduledAt.Time.UTC().Format(time.RFC3339),
+	})
+}
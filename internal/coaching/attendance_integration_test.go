@@ -0,0 +1,109 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CoachingAttendance(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private lesson", DurationMinutes: 60, Capacity: 1,
+		NoShowCreditPolicy: "restore", NotifyOwnerOnNoShow: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	if sessionType.NoShowCreditPolicy != "restore" || !sessionType.NotifyOwnerOnNoShow {
+		t.Fatalf("session type no-show policy = %q/%v, want restore/true", sessionType.NoShowCreditPolicy, sessionType.NotifyOwnerOnNoShow)
+	}
+
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	now := time.Now().UTC().Truncate(time.Second)
+	book := func(studentID string, scheduledAt time.Time) db.CoachingBooking {
+		b, err := q.CreateBooking(ctx, db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: studentID, GroupID: group.ID, SessionTypeID: sessionType.ID,
+			ScheduledAt: ts(scheduledAt), DurationMinutes: 60,
+		})
+		if err != nil {
+			t.Fatalf("CreateBooking(%s): %v", studentID, err)
+		}
+		return b
+	}
+	ended := book("student-1", now.Add(-2*time.Hour))
+	running := book("student-2", now.Add(-30*time.Minute))
+
+	// Heartbeats add the time since the previous one, but never more than the
+	// allowed gap.
+	record := func() {
+		if err := q.RecordBookingAttendance(ctx, db.RecordBookingAttendanceParams{
+			BookingID: ended.ID, ParticipantID: "student-1", ParticipantRole: "student", MaxGapSeconds: 30,
+		}); err != nil {
+			t.Fatalf("RecordBookingAttendance: %v", err)
+		}
+	}
+	record()
+	if _, err := pool.Exec(ctx, `UPDATE coaching_booking_attendance SET last_seen_at = NOW() - interval '10 seconds'`); err != nil {
+		t.Fatalf("rewind last_seen_at: %v", err)
+	}
+	record()
+	if _, err := pool.Exec(ctx, `UPDATE coaching_booking_attendance SET last_seen_at = NOW() - interval '10 minutes'`); err != nil {
+		t.Fatalf("rewind last_seen_at: %v", err)
+	}
+	record()
+	attendance, err := q.ListBookingAttendance(ctx, ended.ID)
+	if err != nil || len(attendance) != 1 {
+		t.Fatalf("ListBookingAttendance = %d, %v; want one row", len(attendance), err)
+	}
+	if got := attendance[0].AttendedSeconds; got != 40 {
+		t.Fatalf("attended_seconds = %d, want 10 + a 30 second cap", got)
+	}
+
+	awaiting := func(graceSeconds int32) []db.CoachingBooking {
+		rows, err := q.ListBookingsAwaitingAttendance(ctx, db.ListBookingsAwaitingAttendanceParams{EndGraceSeconds: graceSeconds, LimitCount: 10})
+		if err != nil {
+			t.Fatalf("ListBookingsAwaitingAttendance: %v", err)
+		}
+		return rows
+	}
+	if due := awaiting(15 * 60); len(due) != 1 || due[0].ID != ended.ID {
+		t.Fatalf("awaiting attendance = %v, want only the ended session", due)
+	}
+	if due := awaiting(2 * 60 * 60); len(due) != 0 {
+		t.Fatalf("awaiting attendance within the grace period = %d, want none", len(due))
+	}
+
+	outcome := db.NullCoachingAttendanceOutcome{CoachingAttendanceOutcome: db.CoachingAttendanceOutcomeExpertNoShow, Valid: true}
+	settled, err := q.SetBookingAttendance(ctx, db.SetBookingAttendanceParams{ID: ended.ID, AttendanceOutcome: outcome})
+	if err != nil {
+		t.Fatalf("SetBookingAttendance: %v", err)
+	}
+	if settled.AttendanceOutcome != outcome || !settled.AttendanceRecordedAt.Valid || settled.UpdatedAt != ended.UpdatedAt {
+		t.Fatalf("settled booking = %+v, want the outcome without touching updated_at", settled)
+	}
+	if _, err := q.SetBookingAttendance(ctx, db.SetBookingAttendanceParams{ID: ended.ID, AttendanceOutcome: outcome}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("second SetBookingAttendance = %v, want ErrNoRows", err)
+	}
+	if due := awaiting(15 * 60); len(due) != 0 {
+		t.Fatalf("awaiting attendance after settling = %d, want none", len(due))
+	}
+	if got, err := q.GetBooking(ctx, db.GetBookingParams{ID: running.ID, ExpertID: "expert-1"}); err != nil || got.AttendanceOutcome.Valid {
+		t.Fatalf("running session = %+v, %v; want no outcome yet", got.AttendanceOutcome, err)
+	}
+}
@@ -0,0 +1,84 @@
+package coaching
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAttendanceOutcome(t *testing.T) {
+	tests := []struct {
+		name            string
+		student, expert int32
+		want            db.CoachingAttendanceOutcome
+	}{
+		{"both stayed", 3000, 3600, db.CoachingAttendanceOutcomeAttended},
+		{"both for exactly half", 1800, 1800, db.CoachingAttendanceOutcomeAttended},
+		{"student left early", 600, 3600, db.CoachingAttendanceOutcomePartial},
+		{"student never came", 0, 3600, db.CoachingAttendanceOutcomeStudentNoShow},
+		{"student only looked in", AttendanceMinSeconds - 1, 3600, db.CoachingAttendanceOutcomeStudentNoShow},
+		{"expert never came", 3600, 0, db.CoachingAttendanceOutcomeExpertNoShow},
+		{"nobody came", 0, 0, db.CoachingAttendanceOutcomeExpertNoShow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attendanceOutcome(60, tt.student, tt.expert); got != tt.want {
+				t.Fatalf("attendanceOutcome(60, %d, %d) = %q, want %q", tt.student, tt.expert, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoresCredit(t *testing.T) {
+	tests := []struct {
+		outcome db.CoachingAttendanceOutcome
+		policy  string
+		want    bool
+	}{
+		{db.CoachingAttendanceOutcomeExpertNoShow, NoShowCreditForfeit, true},
+		{db.CoachingAttendanceOutcomeStudentNoShow, NoShowCreditForfeit, false},
+		{db.CoachingAttendanceOutcomeStudentNoShow, NoShowCreditRestore, true},
+		{db.CoachingAttendanceOutcomePartial, NoShowCreditRestore, false},
+		{db.CoachingAttendanceOutcomeAttended, NoShowCreditRestore, false},
+	}
+	for _, tt := range tests {
+		if got := restoresCredit(tt.outcome, tt.policy); got != tt.want {
+			t.Errorf("restoresCredit(%s, %s) = %v, want %v", tt.outcome, tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestRecordAttendanceOnlyDuringSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{RecordingPresenceTTL: 30 * time.Second})
+	caller := callParticipant{UserID: "student-1", Role: "student"}
+
+	upcoming := rescheduleBooking(t)
+	upcoming.ScheduledAt = pgtype.Timestamptz{Time: time.Now().Add(10 * time.Minute), Valid: true}
+	h.recordAttendance(context.Background(), upcoming, caller)
+
+	running := rescheduleBooking(t)
+	running.ScheduledAt = pgtype.Timestamptz{Time: time.Now().Add(-10 * time.Minute), Valid: true}
+	q.EXPECT().RecordBookingAttendance(gomock.Any(), db.RecordBookingAttendanceParams{
+		BookingID: running.ID, ParticipantID: "student-1", ParticipantRole: "student", MaxGapSeconds: 30,
+	}).Return(nil)
+	h.recordAttendance(context.Background(), running, caller)
+}
+
+func TestNotifyOwnerOfNoShowSkipsOwnerWhoMissedIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := pastBooking(t)
+
+	// The expert owns the group; nobody else is told and no names are looked up.
+	q.EXPECT().GetGroup(gomock.Any(), b.GroupID).Return(db.Group{Name: "Academy", OwnerID: b.ExpertID}, nil)
+	h.notifyOwnerOfNoShow(context.Background(), b, db.CoachingAttendanceOutcomeExpertNoShow)
+}
@@ -197,6 +197,8 @@ func (h *Handler) CreateAvailability(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to create availability", http.StatusInternalServerError)
 		return
 	}
+	now := time.Now()
+	h.fillFromWaitlist(user.ID, now, now.AddDate(0, 0, SlotLookaheadDays))
 
 	if err := h.mergeAvailabilityForDay(ctx, user.ID, groupID, req.DayOfWeek); err != nil {
 		log.ErrorContext(ctx, "merge_availability_failed",
@@ -278,6 +280,8 @@ func (h *Handler) UpdateAvailability(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to update availability", http.StatusInternalServerError)
 		return
 	}
+	now := time.Now()
+	h.fillFromWaitlist(user.ID, now, now.AddDate(0, 0, SlotLookaheadDays))
 
 	if err := h.mergeAvailabilityForDay(ctx, user.ID, groupID, req.DayOfWeek); err != nil {
 		log.ErrorContext(ctx, "merge_availability_failed",
@@ -0,0 +1,176 @@
+package coaching
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// availabilityOverrideResponse is a window of extra bookable hours on one
+// date, offered on top of the weekly availability.
+type availabilityOverrideResponse struct {
+	ID        string    `json:"id"`
+	ExpertID  string    `json:"expert_id"`
+	GroupID   string    `json:"group_id"`
+	Date      string    `json:"date"`
+	StartTime string    `json:"start_time"`
+	EndTime   string    `json:"end_time"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type availabilityOverrideRequest struct {
+	Date      string `json:"date"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+func toAvailabilityOverrideResponse(o db.CoachingAvailabilityOverride) availabilityOverrideResponse {
+	return availabilityOverrideResponse{
+		ID:        uuidToString(o.ID),
+		ExpertID:  o.ExpertID,
+		GroupID:   uuidToString(o.GroupID),
+		Date:      o.OverrideDate.Time.Format("2006-01-02"),
+		StartTime: pgTimeToString(o.StartTime),
+		EndTime:   pgTimeToString(o.EndTime),
+		CreatedAt: o.CreatedAt.Time,
+	}
+}
+
+func (h *Handler) ListAvailabilityOverrides(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	var fromDate, toDate pgtype.Date
+	_ = fromDate.Scan(now.Format("2006-01-02"))
+	_ = toDate.Scan(now.AddDate(0, BlockedSlotRangeMonths, 0).Format("2006-01-02"))
+
+	overrides, err := h.q.ListAvailabilityOverrides(ctx, db.ListAvailabilityOverridesParams{
+		ExpertID: user.ID,
+		GroupID:  groupID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_availability_overrides_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list availability overrides", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]availabilityOverrideResponse, len(overrides))
+	for i, o := range overrides {
+		resp[i] = toAvailabilityOverrideResponse(o)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) CreateAvailabilityOverride(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req availabilityOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var date pgtype.Date
+	if err := date.Scan(req.Date); err != nil {
+		http.Error(w, "Invalid date (use YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+
+	startTime, endTime, errMsg := parseTimeRange(req.StartTime, req.EndTime)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	override, err := h.q.CreateAvailabilityOverride(ctx, db.CreateAvailabilityOverrideParams{
+		ExpertID:     user.ID,
+		GroupID:      groupID,
+		OverrideDate: date,
+		StartTime:    startTime,
+		EndTime:      endTime,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "create_availability_override_failed",
+			slog.String("component", "coaching"),
+			slog.String("expert_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create availability override", http.StatusInternalServerError)
+		return
+	}
+	// The date is local to the expert, so cover a day on either side.
+	h.fillFromWaitlist(user.ID, date.Time.AddDate(0, 0, -1), date.Time.AddDate(0, 0, 2))
+
+	writeJSON(w, http.StatusCreated, toAvailabilityOverrideResponse(override))
+}
+
+func (h *Handler) DeleteAvailabilityOverride(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	overrideID, err := parseUUID(chi.URLParam(r, "overrideID"))
+	if err != nil {
+		http.Error(w, "Invalid override ID", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.q.DeleteAvailabilityOverride(ctx, db.DeleteAvailabilityOverrideParams{
+		ID:       overrideID,
+		ExpertID: user.ID,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "delete_availability_override_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete availability override", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Availability override not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
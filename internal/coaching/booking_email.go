@@ -148,6 +148,72 @@ func (h *Handler) resolveParticipant(ctx context.Context, userID string) booking
 	}
 }
 
+// bookingInvitation builds the .ics attachment that lets mail clients add,
+// move or remove the session in the recipient's calendar. The expert is the
+// organizer and the student the attendee; the UID matches the calendar feed
+// so both sources update the same entry.
+func (h *Handler) bookingInvitation(method string, b db.CoachingBooking, sessionTypeName, groupName string, expert, student bookingParticipant) email.Attachment {
+	event := h.bookingICSEvent(bookingICSInput{
+		ID: b.ID, GroupID: b.GroupID,
+		ScheduledAt: b.ScheduledAt.Time, DurationMinutes: b.DurationMinutes,
+		CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, Cancelled: method == icsMethodCancel,
+		SessionName: sessionTypeName, GroupName: groupName,
+	}, time.Now())
+	if expert.email != "" {
+		event.Organizer = &icsParty{Name: expert.name, Email: expert.email}
+	}
+	if student.email != "" {
+		event.Attendees = []icsParty{{Name: student.name, Email: student.email}}
+	}
+	return invitationAttachment(method, event)
+}
+
+// expertInvitations returns the .ics the expert gets with an email about
+// booking b. A class is one entry in the expert's calendar, keyed on the
+// class: its first attendee adds it and its last cancellation removes it, so
+// the seats in between come without an invitation.
+func (h *Handler) expertInvitations(ctx context.Context, method string, b db.CoachingBooking, sessionTypeName, groupName string, expert, student bookingParticipant) []email.Attachment {
+	if !b.ClassID.Valid {
+		return []email.Attachment{h.bookingInvitation(method, b, sessionTypeName, groupName, expert, student)}
+	}
+	attendees, err := h.q.ListClassAttendees(ctx, b.ClassID)
+	if err != nil {
+		logger.From(ctx, h.logger).WarnContext(ctx, "class_invitation_list_attendees_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		return nil
+	}
+	opened := method == icsMethodRequest && len(attendees) == 1 && attendees[0].ID == b.ID
+	closed := method == icsMethodCancel && len(attendees) == 0
+	if !opened && !closed {
+		return nil
+	}
+	event := h.bookingICSEvent(bookingICSInput{
+		ID: b.ID, ClassID: b.ClassID, GroupID: b.GroupID,
+		ScheduledAt: b.ScheduledAt.Time, DurationMinutes: b.DurationMinutes,
+		CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, Cancelled: closed,
+		SessionName: sessionTypeName, GroupName: groupName,
+	}, time.Now())
+	if expert.email != "" {
+		event.Organizer = &icsParty{Name: expert.name, Email: expert.email}
+	}
+	return []email.Attachment{invitationAttachment(method, event)}
+}
+
+func invitationAttachment(method string, event icsEvent) email.Attachment {
+	filename := "invite.ics"
+	if method == icsMethodCancel {
+		filename = "cancel.ics"
+	}
+	return email.Attachment{
+		Filename:    filename,
+		ContentType: "text/calendar; method=" + method + "; charset=UTF-8",
+		Content:     renderICS(method, "", "", []icsEvent{event}),
+	}
+}
+
 func (h *Handler) sendBookingCreatedEmail(ctx context.Context, b db.CoachingBooking, sessionTypeName string) {
 	log := logger.From(ctx, h.logger)
 
@@ -165,7 +231,7 @@ func (h *Handler) sendBookingCreatedEmail(ctx context.Context, b db.CoachingBook
 	expert := h.resolveParticipant(ctx, b.ExpertID)
 	student := h.resolveParticipant(ctx, b.StudentID)
 
-	buildMessage := func(localization recipientLocalization, partnerName string) email.Message {
+	buildMessage := func(localization recipientLocalization, partnerName string, attachments []email.Attachment) email.Message {
 		loc := localization.localizer
 		note := ""
 		if b.Notes.Valid && b.Notes.String != "" {
@@ -184,17 +250,21 @@ func (h *Handler) sendBookingCreatedEmail(ctx context.Context, b db.CoachingBook
 				}),
 				Note: note,
 			},
+			Attachments: attachments,
 		}
 	}
 
 	type emailTarget struct {
-		userID  string
-		addr    string
-		partner string
+		userID      string
+		addr        string
+		partner     string
+		attachments []email.Attachment
 	}
 	targets := []emailTarget{
-		{userID: b.StudentID, addr: student.email, partner: expert.name},
-		{userID: b.ExpertID, addr: expert.email, partner: student.name},
+		{userID: b.StudentID, addr: student.email, partner: expert.name,
+			attachments: []email.Attachment{h.bookingInvitation(icsMethodRequest, b, sessionTypeName, groupName, expert, student)}},
+		{userID: b.ExpertID, addr: expert.email, partner: student.name,
+			attachments: h.expertInvitations(ctx, icsMethodRequest, b, sessionTypeName, groupName, expert, student)},
 	}
 
 	sentCount := 0
@@ -213,7 +283,7 @@ func (h *Handler) sendBookingCreatedEmail(ctx context.Context, b db.CoachingBook
 		localization := h.resolveRecipientLocalization(ctx, t.userID)
 		loc := localization.localizer
 		subject := i18n.T(loc, "email.booking_confirmed.subject")
-		if err := h.emailService.SendTemplate([]string{t.addr}, subject, email.TemplateNotification, buildMessage(localization, t.partner)); err != nil {
+		if err := h.emailService.SendTemplate([]string{t.addr}, subject, email.TemplateNotification, buildMessage(localization, t.partner, t.attachments)); err != nil {
 			log.ErrorContext(ctx, "booking_created_email_failed",
 				slog.String("component", "coaching"),
 				slog.String("user_id", t.userID),
@@ -313,6 +383,11 @@ func (h *Handler) sendCancellationEmail(ctx context.Context, b db.CoachingBookin
 			Note: note,
 		},
 	}
+	if otherID == b.ExpertID {
+		message.Attachments = h.expertInvitations(ctx, icsMethodCancel, b, sessionTypeName, groupName, expert, student)
+	} else {
+		message.Attachments = []email.Attachment{h.bookingInvitation(icsMethodCancel, b, sessionTypeName, groupName, expert, student)}
+	}
 
 	if err := h.emailService.SendTemplate([]string{otherEmail}, subject, email.TemplateNotification, message); err != nil {
 		log.ErrorContext(ctx, "booking_cancellation_email_failed",
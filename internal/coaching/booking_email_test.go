@@ -3,7 +3,9 @@ package coaching
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +20,32 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+// invitationMessage matches an email.Message with the given copy and a single
+// .ics attachment for the given iTIP method.
+type invitationMessage struct {
+	copy   email.Copy
+	method string
+}
+
+func (m invitationMessage) Matches(x any) bool {
+	msg, ok := x.(email.Message)
+	if !ok || msg.Copy != m.copy || msg.Action != nil || len(msg.Attachments) != 1 {
+		return false
+	}
+	a := msg.Attachments[0]
+	body := string(a.Content)
+	return strings.Contains(a.ContentType, "method="+m.method) &&
+		strings.Contains(body, "METHOD:"+m.method+"\r\n") &&
+		strings.Contains(body, "UID:coaching-booking-11111111-1111-1111-1111-111111111111@strido.net\r\n") &&
+		strings.Contains(body, "ORGANIZER;CN=\"Alex Coach\":mailto:expert@example.com\r\n") &&
+		strings.Contains(body, "DTSTART:20260804T164500Z\r\n") &&
+		strings.Contains(body, "DTEND:20260804T173000Z\r\n")
+}
+
+func (m invitationMessage) String() string {
+	return fmt.Sprintf("message with copy %+v and a %s invitation", m.copy, m.method)
+}
+
 func TestSendBookingCreatedEmailSkipsDisabledRecipient(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
@@ -134,7 +162,7 @@ func TestSendBookingCreatedEmailLocalizesEachRecipient(t *testing.T) {
 		[]string{"student@example.com"},
 		"Live-Coaching-Sitzung bestätigt",
 		email.TemplateNotification,
-		email.Message{Copy: email.Copy{
+		invitationMessage{method: icsMethodRequest, copy: email.Copy{
 			Preheader: "Deine Live-Coaching-Sitzung wurde bestätigt.",
 			Title:     "Live-Coaching-Sitzung bestätigt",
 			Intro:     "Deine Sitzung **„Reitstunde“** mit **Alex Coach** für **„Training“** ist für **Dienstag, 4. August 2026 um 18:45 (Europe/Berlin, UTC+02:00)** gebucht und dauert 45 Minuten.",
@@ -144,7 +172,7 @@ func TestSendBookingCreatedEmailLocalizesEachRecipient(t *testing.T) {
 		[]string{"expert@example.com"},
 		"Live Coaching Session Confirmed",
 		email.TemplateNotification,
-		email.Message{Copy: email.Copy{
+		invitationMessage{method: icsMethodRequest, copy: email.Copy{
 			Preheader: "Your live coaching session has been confirmed.",
 			Title:     "Live coaching session confirmed",
 			Intro:     "Your **“Reitstunde”** session with **Bea Rider** for **“Training”** is booked for **Wednesday, 5 August 2026 at 02:45 (Australia/Melbourne, UTC+10:00)** and lasts 45 minutes.",
@@ -214,7 +242,7 @@ func TestSendCancellationEmailUsesRemainingRecipientsTimezone(t *testing.T) {
 		[]string{"student@example.com"},
 		"Live-Coaching-Sitzung abgesagt",
 		email.TemplateNotification,
-		email.Message{Copy: email.Copy{
+		invitationMessage{method: icsMethodCancel, copy: email.Copy{
 			Preheader: "Eine Coaching-Sitzung wurde abgesagt.",
 			Title:     "Live-Coaching-Sitzung abgesagt",
 			Intro:     "Die Sitzung **„Reitstunde“** für **„Training“** am **Dienstag, 4. August 2026 um 18:45 (Europe/Berlin, UTC+02:00)** wurde von **Alex Coach** abgesagt.",
@@ -312,3 +340,49 @@ func TestWriteBookingCancelledNotificationTargetsOtherParty(t *testing.T) {
 		})
 	}
 }
+
+func TestExpertInvitationsAddAClassOnce(t *testing.T) {
+	b := rescheduleBooking(t)
+	classID := pgtype.UUID{Bytes: [16]byte{0xc1}, Valid: true}
+	other := db.CoachingBooking{ID: pgtype.UUID{Bytes: [16]byte{0x02}, Valid: true}}
+	expert := bookingParticipant{name: "Alex Coach", email: "expert@example.com"}
+	student := bookingParticipant{name: "Sam Student", email: "student@example.com"}
+
+	tests := []struct {
+		name      string
+		classID   pgtype.UUID
+		method    string
+		attendees []db.CoachingBooking
+		wantUID   string
+	}{
+		{"one-to-one", pgtype.UUID{}, icsMethodRequest, nil, bookingEventUID(b.ID)},
+		{"class opens", classID, icsMethodRequest, []db.CoachingBooking{b}, classEventUID(classID)},
+		{"later seat", classID, icsMethodRequest, []db.CoachingBooking{other, b}, ""},
+		{"seat cancelled", classID, icsMethodCancel, []db.CoachingBooking{other}, ""},
+		{"last seat cancelled", classID, icsMethodCancel, nil, classEventUID(classID)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+			booking := b
+			booking.ClassID = tt.classID
+			if tt.classID.Valid {
+				q.EXPECT().ListClassAttendees(gomock.Any(), tt.classID).Return(tt.attendees, nil)
+			}
+
+			got := h.expertInvitations(t.Context(), tt.method, booking, "Group Class", "Training", expert, student)
+			if tt.wantUID == "" {
+				if len(got) != 0 {
+					t.Fatalf("got %d invitations, want none", len(got))
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(string(got[0].Content), "UID:"+tt.wantUID+"\r\n") ||
+				!strings.Contains(string(got[0].Content), "METHOD:"+tt.method+"\r\n") {
+				t.Fatalf("invitations = %+v, want one %s for %s", got, tt.method, tt.wantUID)
+			}
+		})
+	}
+}
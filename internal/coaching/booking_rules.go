@@ -0,0 +1,166 @@
+package coaching
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// bookingRules are the booking constraints of a session type, with the
+// server-wide notices filled in where the type sets none. Zero MaxPerDay and
+// Horizon mean no limit.
+type bookingRules struct {
+	DurationMinutes    int32
+	BufferBefore       time.Duration
+	BufferAfter        time.Duration
+	MaxPerDay          int
+	Horizon            time.Duration
+	MinNotice          time.Duration
+	CancellationNotice time.Duration
+}
+
+func (h *Handler) sessionTypeRules(st db.CoachingSessionType) bookingRules {
+	rules := bookingRules{
+		DurationMinutes:    st.DurationMinutes,
+		BufferBefore:       time.Duration(st.BufferBeforeMinutes) * time.Minute,
+		BufferAfter:        time.Duration(st.BufferAfterMinutes) * time.Minute,
+		MinNotice:          h.minBookingNotice,
+		CancellationNotice: h.cancellationNotice,
+	}
+	if st.MaxSessionsPerDay.Valid {
+		rules.MaxPerDay = int(st.MaxSessionsPerDay.Int32)
+	}
+	if st.BookingHorizonDays.Valid {
+		rules.Horizon = time.Duration(st.BookingHorizonDays.Int32) * 24 * time.Hour
+	}
+	if st.MinBookingNoticeMinutes.Valid {
+		rules.MinNotice = time.Duration(st.MinBookingNoticeMinutes.Int32) * time.Minute
+	}
+	if st.CancellationNoticeMinutes.Valid {
+		rules.CancellationNotice = time.Duration(st.CancellationNoticeMinutes.Int32) * time.Minute
+	}
+	return rules
+}
+
+// bookingRulesFor returns the rules of the booking's session type, keeping the
+// duration and buffers the booking was made with.
+func (h *Handler) bookingRulesFor(ctx context.Context, q db.Querier, b db.CoachingBooking) (bookingRules, error) {
+	st, err := q.GetSessionType(ctx, db.GetSessionTypeParams{ID: b.SessionTypeID, GroupID: b.GroupID})
+	if err != nil {
+		return bookingRules{}, err
+	}
+	rules := h.sessionTypeRules(st)
+	rules.DurationMinutes = b.DurationMinutes
+	rules.BufferBefore = time.Duration(b.BufferBeforeMinutes) * time.Minute
+	rules.BufferAfter = time.Duration(b.BufferAfterMinutes) * time.Minute
+	return rules, nil
+}
+
+// loadBookingRules fetches the rules of the booking's session type and writes
+// the error response when that fails.
+func (h *Handler) loadBookingRules(ctx context.Context, w http.ResponseWriter, b db.CoachingBooking) (bookingRules, bool) {
+	rules, err := h.bookingRulesFor(ctx, h.q, b)
+	if err != nil {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "get_booking_rules_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get session type", http.StatusInternalServerError)
+		return bookingRules{}, false
+	}
+	return rules, true
+}
+
+// checkStart returns the message of the notice or horizon rule a session
+// starting at start would break, or "" when it breaks neither.
+func (r bookingRules) checkStart(now, start time.Time) string {
+	if start.Sub(now) < r.MinNotice {
+		return "Sessions must be booked at least " + r.MinNotice.String() + " in advance (min_booking_notice_minutes)"
+	}
+	if r.Horizon > 0 && start.Sub(now) > r.Horizon {
+		return fmt.Sprintf("Sessions can be booked at most %d days in advance (booking_horizon_days)", int(r.Horizon/(24*time.Hour)))
+	}
+	return ""
+}
+
+// checkCancellation returns the message of the cancellation notice rule when a
+// session starting at start can no longer be cancelled, or "".
+func (r bookingRules) checkCancellation(now, start time.Time) string {
+	if start.Sub(now) < r.CancellationNotice {
+		return "Cancellations must be made at least " + r.CancellationNotice.String() + " before the session (cancellation_notice_minutes)"
+	}
+	return ""
+}
+
+// checkBookingRulesInTx checks the buffer and daily-cap rules against the
+// expert's other active bookings and keeps the session off slots held for
+// waitlisted students. Run it inside the booking transaction after the overlap
+// check; exclude (if valid) is the booking being moved or the hold being
+// confirmed. It returns the message of the violated rule, or "" when the
+// session fits.
+func checkBookingRulesInTx(ctx context.Context, q db.Querier, rules bookingRules, expertID string, start time.Time, exclude pgtype.UUID) (string, error) {
+	end := start.Add(time.Duration(rules.DurationMinutes) * time.Minute)
+	held, err := q.CountConflictingWaitlistHolds(ctx, db.CountConflictingWaitlistHoldsParams{
+		ExpertID:  expertID,
+		EndsAt:    pgtype.Timestamptz{Time: end, Valid: true},
+		StartsAt:  pgtype.Timestamptz{Time: start, Valid: true},
+		ExcludeID: exclude,
+	})
+	if err != nil {
+		return "", err
+	}
+	if held > 0 {
+		return "Time slot is held for a waitlisted student", nil
+	}
+
+	nearby, err := q.CountBookingsWithinBuffers(ctx, db.CountBookingsWithinBuffersParams{
+		ExpertID:            expertID,
+		BufferAfterMinutes:  int32(rules.BufferAfter / time.Minute),
+		EndsAt:              pgtype.Timestamptz{Time: end, Valid: true},
+		BufferBeforeMinutes: int32(rules.BufferBefore / time.Minute),
+		StartsAt:            pgtype.Timestamptz{Time: start, Valid: true},
+		ExcludeID:           exclude,
+	})
+	if err != nil {
+		return "", err
+	}
+	if nearby > 0 {
+		return "Time slot is too close to another of the expert's sessions (buffer_before_minutes, buffer_after_minutes)", nil
+	}
+
+	if rules.MaxPerDay == 0 {
+		return "", nil
+	}
+	loc, err := userLocation(ctx, q, expertID)
+	if err != nil {
+		return "", err
+	}
+	local := start.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	n, err := q.CountBookingsStartingInRange(ctx, db.CountBookingsStartingInRangeParams{
+		ExpertID:  expertID,
+		FromAt:    pgtype.Timestamptz{Time: dayStart, Valid: true},
+		ToAt:      pgtype.Timestamptz{Time: dayStart.AddDate(0, 0, 1), Valid: true},
+		ExcludeID: exclude,
+	})
+	if err != nil {
+		return "", err
+	}
+	if n >= int64(rules.MaxPerDay) {
+		return fmt.Sprintf("The expert is fully booked on that day: at most %d sessions (max_sessions_per_day)", rules.MaxPerDay), nil
+	}
+	return "", nil
+}
+
+// bookingRuleError carries a rule violation found inside a booking
+// transaction out to the handler.
+type bookingRuleError struct{ msg string }
+
+func (e *bookingRuleError) Error() string { return e.msg }
@@ -0,0 +1,83 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_BookingRuleCounts(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if _, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Broken", DurationMinutes: 60, BufferAfterMinutes: 121,
+	}); err == nil {
+		t.Fatal("buffer over two hours was accepted")
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60,
+		BufferAfterMinutes: 15, MaxSessionsPerDay: pgtype.Int4{Int32: 2, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	ts := func(h, m int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Date(2030, 7, 1, h, m, 0, 0, time.UTC), Valid: true}
+	}
+	// 10:00-11:00 with 15 minutes of buffer after.
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: ts(10, 0), DurationMinutes: 60, BufferAfterMinutes: 15,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		start, end    pgtype.Timestamptz
+		before, after int32
+		exclude       pgtype.UUID
+		want          int64
+	}{
+		{"inside existing buffer", ts(11, 10), ts(12, 10), 0, 0, pgtype.UUID{}, 1},
+		{"after existing buffer", ts(11, 15), ts(12, 15), 0, 0, pgtype.UUID{}, 0},
+		{"own buffer before reaches back", ts(11, 15), ts(12, 15), 30, 0, pgtype.UUID{}, 1},
+		{"own buffer after reaches forward", ts(8, 30), ts(9, 30), 0, 45, pgtype.UUID{}, 1},
+		{"excluding the booking", ts(11, 10), ts(12, 10), 0, 0, booking.ID, 0},
+	}
+	for _, tt := range tests {
+		n, err := q.CountBookingsWithinBuffers(ctx, db.CountBookingsWithinBuffersParams{
+			ExpertID: "expert-1", BufferAfterMinutes: tt.after, EndsAt: tt.end,
+			BufferBeforeMinutes: tt.before, StartsAt: tt.start, ExcludeID: tt.exclude,
+		})
+		if err != nil {
+			t.Fatalf("%s: CountBookingsWithinBuffers: %v", tt.name, err)
+		}
+		if n != tt.want {
+			t.Errorf("%s: got %d; want %d", tt.name, n, tt.want)
+		}
+	}
+
+	day := db.CountBookingsStartingInRangeParams{ExpertID: "expert-1", FromAt: ts(0, 0), ToAt: ts(23, 59)}
+	if n, err := q.CountBookingsStartingInRange(ctx, day); err != nil || n != 1 {
+		t.Fatalf("CountBookingsStartingInRange = %d, %v; want 1", n, err)
+	}
+	day.ExcludeID = booking.ID
+	if n, err := q.CountBookingsStartingInRange(ctx, day); err != nil || n != 0 {
+		t.Fatalf("CountBookingsStartingInRange excluding the booking = %d, %v; want 0", n, err)
+	}
+}
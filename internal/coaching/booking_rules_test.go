@@ -0,0 +1,86 @@
+package coaching
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionTypeRulesFallBackToServerDefaults(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil, slog.Default(), HandlerConfig{
+		MinBookingNotice:   2 * time.Hour,
+		CancellationNotice: time.Hour,
+	})
+
+	rules := h.sessionTypeRules(db.CoachingSessionType{DurationMinutes: 45, BufferAfterMinutes: 15})
+	if rules.MinNotice != 2*time.Hour || rules.CancellationNotice != time.Hour {
+		t.Errorf("notices = %v, %v; want the server defaults", rules.MinNotice, rules.CancellationNotice)
+	}
+	if rules.MaxPerDay != 0 || rules.Horizon != 0 || rules.BufferAfter != 15*time.Minute {
+		t.Errorf("rules = %+v; want no cap or horizon and a 15m buffer after", rules)
+	}
+
+	rules = h.sessionTypeRules(db.CoachingSessionType{
+		DurationMinutes:           45,
+		MaxSessionsPerDay:         pgtype.Int4{Int32: 3, Valid: true},
+		BookingHorizonDays:        pgtype.Int4{Int32: 14, Valid: true},
+		MinBookingNoticeMinutes:   pgtype.Int4{Int32: 0, Valid: true},
+		CancellationNoticeMinutes: pgtype.Int4{Int32: 24 * 60, Valid: true},
+	})
+	if rules.MinNotice != 0 || rules.CancellationNotice != 24*time.Hour ||
+		rules.MaxPerDay != 3 || rules.Horizon != 14*24*time.Hour {
+		t.Errorf("rules = %+v; want the session type's own rules", rules)
+	}
+}
+
+func TestBookingRulesCheckStart(t *testing.T) {
+	now := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+	rules := bookingRules{MinNotice: 2 * time.Hour, Horizon: 7 * 24 * time.Hour}
+	tests := []struct {
+		name  string
+		start time.Time
+		want  string
+	}{
+		{"inside notice", now.Add(time.Hour), "min_booking_notice_minutes"},
+		{"at notice", now.Add(2 * time.Hour), ""},
+		{"at horizon", now.AddDate(0, 0, 7), ""},
+		{"beyond horizon", now.AddDate(0, 0, 7).Add(time.Minute), "booking_horizon_days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules.checkStart(now, tt.start)
+			if (got == "") != (tt.want == "") || !strings.Contains(got, tt.want) {
+				t.Fatalf("checkStart() = %q; want it to name %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelBookingUsesSessionTypeNotice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{CancellationNotice: time.Hour})
+	b := rescheduleBooking(t)
+	b.ScheduledAt = pgtype.Timestamptz{Time: time.Now().Add(12 * time.Hour), Valid: true}
+
+	q.EXPECT().GetBooking(gomock.Any(), db.GetBookingParams{ID: b.ID, ExpertID: "student-1"}).Return(b, nil)
+	q.EXPECT().GetSessionType(gomock.Any(), db.GetSessionTypeParams{ID: b.SessionTypeID, GroupID: b.GroupID}).
+		Return(db.CoachingSessionType{
+			DurationMinutes:           60,
+			CancellationNoticeMinutes: pgtype.Int4{Int32: 24 * 60, Valid: true},
+		}, nil)
+
+	rec := httptest.NewRecorder()
+	h.CancelBooking(rec, rescheduleRequest("", "student-1"))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "cancellation_notice_minutes") {
+		t.Fatalf("status = %d, body %q; want 400 naming cancellation_notice_minutes", rec.Code, rec.Body.String())
+	}
+}
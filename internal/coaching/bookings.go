@@ -1,12 +1,14 @@
 package coaching
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/logger"
@@ -19,22 +21,31 @@ import (
 // --- DTO ---
 
 type bookingResponse struct {
-	ID                 string                    `json:"id"`
-	ExpertID           string                    `json:"expert_id"`
-	ExpertName         string                    `json:"expert_name"`
-	StudentID          string                    `json:"student_id"`
-	StudentName        string                    `json:"student_name"`
-	GroupID            string                    `json:"group_id"`
-	SessionTypeID      string                    `json:"session_type_id"`
-	SessionTypeName    string                    `json:"session_type_name,omitempty"`
-	ScheduledAt        time.Time                 `json:"scheduled_at"`
-	DurationMinutes    int32                     `json:"duration_minutes"`
-	Status             string                    `json:"status"` // "pending" | "done" | "cancelled"
-	CancellationReason *string                   `json:"cancellation_reason,omitempty"`
-	CancelledBy        *string                   `json:"cancelled_by,omitempty"`
-	Notes              *string                   `json:"notes,omitempty"`
-	Recording          *bookingRecordingResponse `json:"recording,omitempty"`
-	CreatedAt          time.Time                 `json:"created_at"`
+	ID                 string                     `json:"id"`
+	ExpertID           string                     `json:"expert_id"`
+	ExpertName         string                     `json:"expert_name"`
+	StudentID          string                     `json:"student_id"`
+	StudentName        string                     `json:"student_name"`
+	GroupID            string                     `json:"group_id"`
+	SessionTypeID      string                     `json:"session_type_id"`
+	SessionTypeName    string                     `json:"session_type_name,omitempty"`
+	ScheduledAt        time.Time                  `json:"scheduled_at"`
+	DurationMinutes    int32                      `json:"duration_minutes"`
+	Status             string                     `json:"status"` // "pending" | "pending_payment" | "done" | "cancelled"
+	CancellationReason *string                    `json:"cancellation_reason,omitempty"`
+	CancelledBy        *string                    `json:"cancelled_by,omitempty"`
+	Notes              *string                    `json:"notes,omitempty"`
+	SeriesID           string                     `json:"series_id,omitempty"`
+	ClassID            string                     `json:"class_id,omitempty"`
+	Recording          *bookingRecordingResponse  `json:"recording,omitempty"`
+	PendingReschedule  *pendingRescheduleResponse `json:"pending_reschedule,omitempty"`
+	PriceCents         int32                      `json:"price_cents,omitempty"`
+	Currency           string                     `json:"currency,omitempty"`
+	PaymentStatus      string                     `json:"payment_status,omitempty"` // "pending" | "paid" | "expired" | "refunded"
+	PaymentExpiresAt   *time.Time                 `json:"payment_expires_at,omitempty"`
+	CheckoutURL        string                     `json:"checkout_url,omitempty"`       // only on creation of a paid booking
+	AttendanceOutcome  string                     `json:"attendance_outcome,omitempty"` // "attended" | "student_no_show" | "expert_no_show" | "partial"
+	CreatedAt          time.Time                  `json:"created_at"`
 }
 
 type bookingRecordingResponse struct {
@@ -55,6 +66,25 @@ func bookingStatus(scheduledAt time.Time, isCancelled bool) string {
 	return "pending"
 }
 
+// applyBookingPayment adds the price and payment state of a paid booking. A
+// booking still waiting for its payment reports "pending_payment".
+func applyBookingPayment(resp *bookingResponse, priceCents int32, currency pgtype.Text, status db.NullCoachingPaymentStatus, expiresAt pgtype.Timestamptz) {
+	if !status.Valid {
+		return
+	}
+	resp.PriceCents = priceCents
+	resp.Currency = currency.String
+	resp.PaymentStatus = string(status.CoachingPaymentStatus)
+	if status.CoachingPaymentStatus == db.CoachingPaymentStatusPending {
+		if resp.Status == "pending" {
+			resp.Status = "pending_payment"
+		}
+		if expiresAt.Valid {
+			resp.PaymentExpiresAt = &expiresAt.Time
+		}
+	}
+}
+
 // --- Mappers ---
 
 // buildBookingResponse fills a bookingResponse from common scalar fields.
@@ -105,7 +135,7 @@ func buildBookingResponse(
 }
 
 func toBookingResponse(b db.CoachingBooking, users map[string]userInfo, sessionTypeName string) bookingResponse {
-	return buildBookingResponse(
+	resp := buildBookingResponse(
 		b.ID, b.ExpertID, b.StudentID,
 		b.GroupID, b.SessionTypeID, sessionTypeName,
 		b.ScheduledAt, b.DurationMinutes, b.IsCancelled,
@@ -113,10 +143,15 @@ func toBookingResponse(b db.CoachingBooking, users map[string]userInfo, sessionT
 		b.CreatedAt, users,
 		"", pgtype.UUID{}, pgtype.UUID{},
 	)
+	resp.SeriesID = uuidToString(b.SeriesID)
+	resp.ClassID = uuidToString(b.ClassID)
+	resp.AttendanceOutcome = string(b.AttendanceOutcome.CoachingAttendanceOutcome)
+	applyBookingPayment(&resp, b.PriceCents, b.Currency, b.PaymentStatus, b.PaymentExpiresAt)
+	return resp
 }
 
 func toBookingResponseFromRow(b db.ListMyBookingsRow, users map[string]userInfo) bookingResponse {
-	return buildBookingResponse(
+	resp := buildBookingResponse(
 		b.ID, b.ExpertID, b.StudentID,
 		b.GroupID, b.SessionTypeID, b.SessionTypeName,
 		b.ScheduledAt, b.DurationMinutes, b.IsCancelled,
@@ -124,10 +159,16 @@ func toBookingResponseFromRow(b db.ListMyBookingsRow, users map[string]userInfo)
 		b.CreatedAt, users,
 		b.RecordingStatus, b.RecordingAssetID, b.RecordingVideoID,
 	)
+	resp.SeriesID = uuidToString(b.SeriesID)
+	resp.ClassID = uuidToString(b.ClassID)
+	resp.AttendanceOutcome = string(b.AttendanceOutcome.CoachingAttendanceOutcome)
+	resp.PendingReschedule = buildPendingReschedule(b.RescheduleID, b.RescheduleProposedBy, b.RescheduleScheduledAt)
+	applyBookingPayment(&resp, b.PriceCents, b.Currency, b.PaymentStatus, b.PaymentExpiresAt)
+	return resp
 }
 
 func toBookingResponseFromAllRow(b db.ListAllMyBookingsRow, users map[string]userInfo) bookingResponse {
-	return buildBookingResponse(
+	resp := buildBookingResponse(
 		b.ID, b.ExpertID, b.StudentID,
 		b.GroupID, b.SessionTypeID, b.SessionTypeName,
 		b.ScheduledAt, b.DurationMinutes, b.IsCancelled,
@@ -135,10 +176,16 @@ func toBookingResponseFromAllRow(b db.ListAllMyBookingsRow, users map[string]use
 		b.CreatedAt, users,
 		b.RecordingStatus, b.RecordingAssetID, b.RecordingVideoID,
 	)
+	resp.SeriesID = uuidToString(b.SeriesID)
+	resp.ClassID = uuidToString(b.ClassID)
+	resp.AttendanceOutcome = string(b.AttendanceOutcome.CoachingAttendanceOutcome)
+	resp.PendingReschedule = buildPendingReschedule(b.RescheduleID, b.RescheduleProposedBy, b.RescheduleScheduledAt)
+	applyBookingPayment(&resp, b.PriceCents, b.Currency, b.PaymentStatus, b.PaymentExpiresAt)
+	return resp
 }
 
 func toBookingResponseFromGroupRow(b db.ListGroupBookingsRow, users map[string]userInfo) bookingResponse {
-	return buildBookingResponse(
+	resp := buildBookingResponse(
 		b.ID, b.ExpertID, b.StudentID,
 		b.GroupID, b.SessionTypeID, b.SessionTypeName,
 		b.ScheduledAt, b.DurationMinutes, b.IsCancelled,
@@ -146,6 +193,12 @@ func toBookingResponseFromGroupRow(b db.ListGroupBookingsRow, users map[string]u
 		b.CreatedAt, users,
 		b.RecordingStatus, b.RecordingAssetID, b.RecordingVideoID,
 	)
+	resp.SeriesID = uuidToString(b.SeriesID)
+	resp.ClassID = uuidToString(b.ClassID)
+	resp.AttendanceOutcome = string(b.AttendanceOutcome.CoachingAttendanceOutcome)
+	resp.PendingReschedule = buildPendingReschedule(b.RescheduleID, b.RescheduleProposedBy, b.RescheduleScheduledAt)
+	applyBookingPayment(&resp, b.PriceCents, b.Currency, b.PaymentStatus, b.PaymentExpiresAt)
+	return resp
 }
 
 func buildBookingRecordingResponse(status string, assetID, videoID pgtype.UUID) *bookingRecordingResponse {
@@ -226,8 +279,19 @@ func (h *Handler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if time.Until(scheduledAt) < h.minBookingNotice {
-		http.Error(w, "Sessions must be booked at least "+h.minBookingNotice.String()+" in advance", http.StatusBadRequest)
+	rules := h.sessionTypeRules(sessionType)
+	if msg := rules.checkStart(time.Now(), scheduledAt); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if sessionType.PriceCents > 0 && h.paymentProvider == nil {
+		http.Error(w, "Payments are not configured", http.StatusServiceUnavailable)
+		return
+	}
+	// A class belongs to the expert of its session type.
+	if sessionType.Capacity > 1 && sessionType.ExpertID != req.ExpertID {
+		http.Error(w, "Session type not found", http.StatusNotFound)
 		return
 	}
 
@@ -253,43 +317,127 @@ func (h *Handler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 
 		qtx := db.New(tx)
 
-		conflicts, err := qtx.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
-			ExpertID:      req.ExpertID,
-			ScheduledAt:   slotStart,
-			ScheduledAt_2: slotEnd,
-		})
+		arg := db.CreateBookingParams{
+			ExpertID:            req.ExpertID,
+			StudentID:           user.ID,
+			GroupID:             groupID,
+			SessionTypeID:       sessionTypeID,
+			ScheduledAt:         slotStart,
+			DurationMinutes:     sessionType.DurationMinutes,
+			Notes:               notes,
+			BufferBeforeMinutes: sessionType.BufferBeforeMinutes,
+			BufferAfterMinutes:  sessionType.BufferAfterMinutes,
+		}
+
+		joining := false
+		if sessionType.Capacity > 1 {
+			joining, err = joinClassInTx(ctx, qtx, sessionType, &arg)
+			if err != nil {
+				_ = tx.Rollback(ctx)
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
+					continue
+				}
+				switch {
+				case errors.Is(err, errClassFull):
+					http.Error(w, "Class is full", http.StatusConflict)
+				case errors.Is(err, errAlreadyInClass):
+					http.Error(w, "You are already booked into this class", http.StatusConflict)
+				default:
+					log.ErrorContext(ctx, "join_class_failed", slog.String("component", "coaching"), slog.Any("err", err))
+					http.Error(w, "Failed to create booking", http.StatusInternalServerError)
+				}
+				return
+			}
+		}
+
+		// Joining a class takes a seat in a slot its attendees already hold.
+		if !joining {
+			conflicts, err := qtx.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
+				ExpertID:      req.ExpertID,
+				ScheduledAt:   slotStart,
+				ScheduledAt_2: slotEnd,
+			})
+			if err != nil {
+				_ = tx.Rollback(ctx)
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
+					continue
+				}
+				log.ErrorContext(ctx, "count_conflicts_failed", slog.String("component", "coaching"), slog.Any("err", err))
+				http.Error(w, "Failed to check conflicts", http.StatusInternalServerError)
+				return
+			}
+			if conflicts > 0 {
+				_ = tx.Rollback(ctx)
+				http.Error(w, "Time slot is no longer available", http.StatusConflict)
+				return
+			}
+
+			ruleMsg, err := checkBookingRulesInTx(ctx, qtx, rules, req.ExpertID, scheduledAt, pgtype.UUID{})
+			if err != nil {
+				_ = tx.Rollback(ctx)
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
+					continue
+				}
+				log.ErrorContext(ctx, "check_booking_rules_failed", slog.String("component", "coaching"), slog.Any("err", err))
+				http.Error(w, "Failed to check conflicts", http.StatusInternalServerError)
+				return
+			}
+			if ruleMsg != "" {
+				_ = tx.Rollback(ctx)
+				http.Error(w, ruleMsg, http.StatusConflict)
+				return
+			}
+		}
+
+		h.setBookingPrice(&arg, sessionType)
+		grant, err := reserveCreditInTx(ctx, qtx, &arg, sessionType)
 		if err != nil {
 			_ = tx.Rollback(ctx)
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
 				continue
 			}
-			log.ErrorContext(ctx, "count_conflicts_failed", slog.String("component", "coaching"), slog.Any("err", err))
-			http.Error(w, "Failed to check conflicts", http.StatusInternalServerError)
+			if errors.Is(err, errNoCredit) {
+				http.Error(w, "No session credit available", http.StatusConflict)
+				return
+			}
+			log.ErrorContext(ctx, "consume_credit_failed", slog.String("component", "coaching"), slog.Any("err", err))
+			http.Error(w, "Failed to create booking", http.StatusInternalServerError)
 			return
 		}
-		if conflicts > 0 {
+		booking, err = qtx.CreateBooking(ctx, arg)
+		if err != nil {
 			_ = tx.Rollback(ctx)
-			http.Error(w, "Time slot is no longer available", http.StatusConflict)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
+				continue
+			}
+			log.ErrorContext(ctx, "create_booking_failed", slog.String("component", "coaching"), slog.Any("err", err))
+			http.Error(w, "Failed to create booking", http.StatusInternalServerError)
 			return
 		}
 
-		booking, err = qtx.CreateBooking(ctx, db.CreateBookingParams{
-			ExpertID:        req.ExpertID,
-			StudentID:       user.ID,
-			GroupID:         groupID,
-			SessionTypeID:   sessionTypeID,
-			ScheduledAt:     slotStart,
-			DurationMinutes: sessionType.DurationMinutes,
-			Notes:           notes,
-		})
-		if err != nil {
+		if err = h.recordCreditConsumedInTx(ctx, tx, grant, booking); err != nil {
 			_ = tx.Rollback(ctx)
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
 				continue
 			}
-			log.ErrorContext(ctx, "create_booking_failed", slog.String("component", "coaching"), slog.Any("err", err))
+			log.ErrorContext(ctx, "record_credit_consumed_failed", slog.String("component", "coaching"), slog.Any("err", err))
+			http.Error(w, "Failed to create booking", http.StatusInternalServerError)
+			return
+		}
+
+		if err = h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingCreated, booking, nil)); err != nil {
+			_ = tx.Rollback(ctx)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "40001" && attempt < maxRetries-1 {
+				continue
+			}
+			log.ErrorContext(ctx, "audit_booking_created_failed", slog.String("component", "coaching"), slog.Any("err", err))
 			http.Error(w, "Failed to create booking", http.StatusInternalServerError)
 			return
 		}
@@ -306,9 +454,16 @@ func (h *Handler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		break
 	}
 
-	h.sendBookingCreatedEmail(ctx, booking, sessionType.Name)
-	h.recordBookingCreatedNotification(booking, sessionType.Name)
-	h.scheduleReminders(ctx, booking)
+	checkoutURL, err := h.completeNewBooking(ctx, booking, sessionType.Name, user.Email)
+	if err != nil {
+		log.ErrorContext(ctx, "start_checkout_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(booking.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to start checkout", http.StatusBadGateway)
+		return
+	}
 
 	users, err := h.resolveUsers(ctx, []string{booking.ExpertID, booking.StudentID})
 	if err != nil {
@@ -319,7 +474,9 @@ func (h *Handler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to resolve booking users", http.StatusInternalServerError)
 		return
 	}
-	writeJSON(w, http.StatusCreated, toBookingResponse(booking, users, sessionType.Name))
+	resp := toBookingResponse(booking, users, sessionType.Name)
+	resp.CheckoutURL = checkoutURL
+	writeJSON(w, http.StatusCreated, resp)
 }
 
 func (h *Handler) ListMyBookings(w http.ResponseWriter, r *http.Request) {
@@ -474,8 +631,16 @@ func (h *Handler) ListGroupSessions(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// Cancellation scopes. A series booking can be cancelled on its own or
+// together with every later occurrence of its series.
+const (
+	cancelScopeSingle    = "single"
+	cancelScopeFollowing = "following"
+)
+
 type cancelBookingRequest struct {
 	CancellationReason *string `json:"cancellation_reason,omitempty"`
+	Scope              string  `json:"scope,omitempty"` // "single" (default) | "following"
 }
 
 func (h *Handler) CancelBooking(w http.ResponseWriter, r *http.Request) {
@@ -501,6 +666,15 @@ func (h *Handler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	switch req.Scope {
+	case "":
+		req.Scope = cancelScopeSingle
+	case cancelScopeSingle, cancelScopeFollowing:
+	default:
+		http.Error(w, "scope must be single or following", http.StatusBadRequest)
+		return
+	}
+
 	// GetBooking is scoped to expert_id OR student_id — ensures caller is a participant.
 	existing, err := h.q.GetBooking(ctx, db.GetBookingParams{
 		ID:       bookingID,
@@ -520,8 +694,17 @@ func (h *Handler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if time.Until(existing.ScheduledAt.Time) < h.cancellationNotice {
-		http.Error(w, "Cancellations must be made at least "+h.cancellationNotice.String()+" before the session", http.StatusBadRequest)
+	if req.Scope == cancelScopeFollowing && !existing.SeriesID.Valid {
+		http.Error(w, "Booking is not part of a series", http.StatusBadRequest)
+		return
+	}
+
+	rules, ok := h.loadBookingRules(ctx, w, existing)
+	if !ok {
+		return
+	}
+	if msg := rules.checkCancellation(time.Now(), existing.ScheduledAt.Time); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
@@ -530,23 +713,54 @@ func (h *Handler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 		cancelReason = pgtype.Text{String: *req.CancellationReason, Valid: true}
 	}
 
-	updated, err := h.q.CancelBooking(ctx, db.CancelBookingParams{
+	arg := db.CancelBookingParams{
 		ID:                 bookingID,
 		CancellationReason: cancelReason,
 		CancelledBy:        pgtype.Text{String: user.ID, Valid: true},
 		ExpertID:           user.ID,
-	})
+	}
+	var updated db.CoachingBooking
+	cancelledCount := 1
+	var freedUntil time.Time
+	if req.Scope == cancelScopeFollowing {
+		var cancelled []db.CoachingBooking
+		cancelled, err = h.cancelSeriesFromAudited(ctx, existing, arg)
+		if err == nil {
+			// cancelled is ordered by time, so the first entry is this booking.
+			updated, cancelledCount = cancelled[0], len(cancelled)
+			last := cancelled[len(cancelled)-1]
+			freedUntil = last.ScheduledAt.Time.Add(time.Duration(last.DurationMinutes) * time.Minute)
+		}
+	} else {
+		updated, err = h.cancelBookingAudited(ctx, existing, arg)
+		freedUntil = updated.ScheduledAt.Time.Add(time.Duration(updated.DurationMinutes) * time.Minute)
+	}
+	if errors.Is(err, errBookingAlreadyCancelled) {
+		http.Error(w, "Booking is already cancelled", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		log.ErrorContext(ctx, "cancel_booking_failed",
 			slog.String("component", "coaching"),
+			slog.String("scope", req.Scope),
 			slog.Any("err", err),
 		)
 		http.Error(w, "Failed to cancel booking", http.StatusInternalServerError)
 		return
 	}
+	// Series are never paid, so only a single booking can be owed a refund.
+	if cancelledCount == 1 {
+		updated = h.refundBooking(ctx, updated)
+	}
 
-	h.sendCancellationEmail(ctx, updated, user.ID)
-	h.recordBookingCancelledNotification(updated, user.ID)
+	if cancelledCount > 1 {
+		h.sendSeriesCancellationEmail(ctx, updated, user.ID, cancelledCount)
+		h.recordSeriesCancelledNotification(updated, user.ID, cancelledCount)
+	} else {
+		h.sendCancellationEmail(ctx, updated, user.ID)
+		h.recordBookingCancelledNotification(updated, user.ID)
+	}
+	h.fillFromWaitlist(updated.ExpertID, updated.ScheduledAt.Time, freedUntil)
 
 	users, err := h.resolveUsers(ctx, []string{updated.ExpertID, updated.StudentID})
 	if err != nil {
@@ -559,3 +773,104 @@ func (h *Handler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 	}
 	writeJSON(w, http.StatusOK, toBookingResponse(updated, users, ""))
 }
+
+// cancelBookingAudited cancels the booking, withdraws its pending reschedule
+// proposal and records booking.cancelled in one transaction.
+func (h *Handler) cancelBookingAudited(ctx context.Context, existing db.CoachingBooking, arg db.CancelBookingParams) (db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	updated, err := h.cancelBookingInTx(ctx, tx, existing, arg)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return updated, nil
+}
+
+// cancelSeriesFromAudited cancels existing and every later active occurrence
+// of its series in one transaction, each with its own booking.cancelled
+// event. The result is ordered by time and starts with existing.
+func (h *Handler) cancelSeriesFromAudited(ctx context.Context, existing db.CoachingBooking, arg db.CancelBookingParams) ([]db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	occurrences, err := db.New(tx).ListActiveSeriesBookingsFrom(ctx, db.ListActiveSeriesBookingsFromParams{
+		SeriesID:    existing.SeriesID,
+		ScheduledAt: existing.ScheduledAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) == 0 || occurrences[0].ID != existing.ID {
+		// existing was cancelled or moved earlier since it was loaded.
+		return nil, pgx.ErrNoRows
+	}
+	cancelled := make([]db.CoachingBooking, 0, len(occurrences))
+	for _, o := range occurrences {
+		oArg := arg
+		oArg.ID = o.ID
+		updated, err := h.cancelBookingInTx(ctx, tx, o, oArg)
+		if err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, updated)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// errBookingAlreadyCancelled is returned when a booking was cancelled since it
+// was loaded.
+var errBookingAlreadyCancelled = errors.New("booking is already cancelled")
+
+// cancelBookingInTx cancels one booking inside tx, withdrawing its pending
+// reschedule proposal, returning its session credit and recording
+// booking.cancelled.
+func (h *Handler) cancelBookingInTx(ctx context.Context, tx pgx.Tx, existing db.CoachingBooking, arg db.CancelBookingParams) (db.CoachingBooking, error) {
+	qtx := db.New(tx)
+	updated, err := qtx.CancelBooking(ctx, arg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.CoachingBooking{}, errBookingAlreadyCancelled
+	}
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	// A cancelled booking cannot move, so close any proposal still waiting.
+	if _, err := qtx.WithdrawPendingBookingReschedule(ctx, db.WithdrawPendingBookingRescheduleParams{
+		BookingID:   updated.ID,
+		RespondedBy: arg.CancelledBy,
+	}); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.restoreCreditInTx(ctx, tx, updated, arg.CancelledBy.String); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingCancelled, updated, &existing)); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return updated, nil
+}
+
+// bookingEvent builds a booking event; old is nil when there is no prior state.
+func bookingEvent(action string, b db.CoachingBooking, old *db.CoachingBooking) audit.Event {
+	e := audit.Event{
+		Action:       action,
+		ResourceType: audit.ResourceBooking,
+		ResourceID:   uuidToString(b.ID),
+		GroupID:      uuidToString(b.GroupID),
+		NewValues:    audit.BookingSnapshotOf(b),
+	}
+	if old != nil {
+		e.OldValues = audit.BookingSnapshotOf(*old)
+	}
+	return e
+}
@@ -0,0 +1,293 @@
+package coaching
+
+import (
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The feed covers recent history so just-finished sessions do not vanish
+// from subscribed calendars, and far enough ahead to include weekly series.
+const (
+	calendarFeedPast   = 30 * 24 * time.Hour
+	calendarFeedFuture = 180 * 24 * time.Hour
+)
+
+type calendarFeedResponse struct {
+	Active         bool       `json:"active"`
+	URL            string     `json:"url,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
+
+func toCalendarFeedResponse(feed db.CoachingCalendarFeed) calendarFeedResponse {
+	resp := calendarFeedResponse{Active: true}
+	if feed.CreatedAt.Valid {
+		t := feed.CreatedAt.Time
+		resp.CreatedAt = &t
+	}
+	if feed.LastAccessedAt.Valid {
+		t := feed.LastAccessedAt.Time
+		resp.LastAccessedAt = &t
+	}
+	return resp
+}
+
+// GetCalendarFeed reports whether the caller has an active feed. The URL is
+// only revealed when it is created or rotated.
+func (h *Handler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	feed, err := h.q.GetCalendarFeed(ctx, user.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusOK, calendarFeedResponse{Active: false})
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "calendar_feed_get_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get calendar feed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, toCalendarFeedResponse(feed))
+}
+
+// RotateCalendarFeed issues a new secret feed URL for the caller. Any
+// previously issued URL stops working immediately.
+func (h *Handler) RotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token, tokenHash, err := newHashedSecret()
+	if err != nil {
+		log.ErrorContext(ctx, "calendar_feed_token_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create calendar feed", http.StatusInternalServerError)
+		return
+	}
+	feed, err := h.q.UpsertCalendarFeed(ctx, db.UpsertCalendarFeedParams{UserID: user.ID, TokenHash: tokenHash})
+	if err != nil {
+		log.ErrorContext(ctx, "calendar_feed_rotate_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "calendar_feed_rotated",
+		slog.String("component", "coaching"),
+		slog.String("user_id", user.ID),
+	)
+	resp := toCalendarFeedResponse(feed)
+	resp.URL = h.calendarFeedURL(token)
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// RevokeCalendarFeed deletes the caller's feed so its URL returns 404.
+func (h *Handler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	n, err := h.q.DeleteCalendarFeed(ctx, user.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "calendar_feed_revoke_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to revoke calendar feed", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Calendar feed not found", http.StatusNotFound)
+		return
+	}
+
+	log.InfoContext(ctx, "calendar_feed_revoked",
+		slog.String("component", "coaching"),
+		slog.String("user_id", user.ID),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeCalendarFeed is the public, token-authenticated iCalendar feed. The
+// token in the path is the only credential, so unknown and revoked tokens are
+// indistinguishable.
+func (h *Handler) ServeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	token := strings.TrimSuffix(chi.URLParam(r, "token"), ".ics")
+	if token == "" {
+		http.Error(w, "Calendar feed not found", http.StatusNotFound)
+		return
+	}
+	digest := sha256.Sum256([]byte(token))
+	feed, err := h.q.TouchCalendarFeedByTokenHash(ctx, digest[:])
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Calendar feed not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "calendar_feed_lookup_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to load calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	bookings, err := h.q.ListCalendarFeedBookings(ctx, db.ListCalendarFeedBookingsParams{
+		UserID: feed.UserID,
+		FromAt: pgtype.Timestamptz{Time: now.Add(-calendarFeedPast), Valid: true},
+		ToAt:   pgtype.Timestamptz{Time: now.Add(calendarFeedFuture), Valid: true},
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "calendar_feed_list_bookings_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", feed.UserID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to load calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	partnerIDs := make([]string, 0, len(bookings))
+	for _, b := range bookings {
+		if !b.ClassID.Valid || feed.UserID != b.ExpertID {
+			partnerIDs = append(partnerIDs, otherUserID(b.ExpertID, b.StudentID, feed.UserID))
+		}
+	}
+	users, err := h.resolveUsers(ctx, partnerIDs)
+	if err != nil {
+		// Names are cosmetic; serve the feed without them rather than failing
+		// the calendar client's poll.
+		log.WarnContext(ctx, "calendar_feed_resolve_users_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", feed.UserID),
+			slog.Any("err", err),
+		)
+	}
+
+	timezone := "UTC"
+	if tz, err := h.q.GetUserTimezone(ctx, feed.UserID); err == nil && tz != "" {
+		timezone = tz
+	}
+
+	events := make([]icsEvent, len(bookings))
+	for i, b := range bookings {
+		in := bookingICSInput{
+			ID: b.ID, GroupID: b.GroupID,
+			ScheduledAt: b.ScheduledAt.Time, DurationMinutes: b.DurationMinutes,
+			CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, Cancelled: b.IsCancelled,
+			SessionName: b.SessionTypeName, GroupName: b.GroupName,
+		}
+		if b.ClassID.Valid && feed.UserID == b.ExpertID {
+			// The row stands for the whole class, not one attendee.
+			in.ClassID = b.ClassID
+		} else {
+			partner := users[otherUserID(b.ExpertID, b.StudentID, feed.UserID)]
+			in.PartnerName = strings.TrimSpace(partner.FirstName + " " + partner.LastName)
+		}
+		events[i] = h.bookingICSEvent(in, now)
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(renderICS(icsMethodPublish, "Coaching sessions", timezone, events))
+}
+
+// bookingICSInput is the subset of a booking the iCalendar renderers need, so
+// the feed rows and db.CoachingBooking can share one mapping.
+type bookingICSInput struct {
+	ID              pgtype.UUID
+	ClassID         pgtype.UUID // set when the event is the expert's entry for a whole class
+	GroupID         pgtype.UUID
+	ScheduledAt     time.Time
+	DurationMinutes int32
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	Cancelled       bool
+	SessionName     string
+	GroupName       string
+	PartnerName     string
+}
+
+func (h *Handler) bookingICSEvent(in bookingICSInput, stamp time.Time) icsEvent {
+	summary := in.SessionName
+	if summary == "" {
+		summary = "Coaching session"
+	}
+	if in.PartnerName != "" {
+		summary += " · " + in.PartnerName
+	}
+	joinURL := h.bookingCallURL(in.GroupID, in.ID)
+	var description []string
+	if in.GroupName != "" {
+		description = append(description, in.GroupName)
+	}
+	if joinURL != "" {
+		description = append(description, "Join: "+joinURL)
+	}
+	uid := bookingEventUID(in.ID)
+	if in.ClassID.Valid {
+		uid = classEventUID(in.ClassID)
+	}
+	return icsEvent{
+		UID:         uid,
+		Sequence:    bookingEventSequence(in.CreatedAt, in.UpdatedAt),
+		Start:       in.ScheduledAt,
+		End:         in.ScheduledAt.Add(time.Duration(in.DurationMinutes) * time.Minute),
+		Stamp:       stamp,
+		Summary:     summary,
+		Description: strings.Join(description, "\n"),
+		URL:         joinURL,
+		Cancelled:   in.Cancelled,
+	}
+}
+
+func (h *Handler) calendarFeedURL(token string) string {
+	return strings.TrimRight(h.apiBaseURL, "/") + "/public/coaching/calendar/" + token + ".ics"
+}
+
+func otherUserID(expertID, studentID, userID string) string {
+	if userID == expertID {
+		return studentID
+	}
+	return expertID
+}
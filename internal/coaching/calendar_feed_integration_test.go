@@ -0,0 +1,168 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CalendarFeedRotateAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	first, err := q.UpsertCalendarFeed(ctx, db.UpsertCalendarFeedParams{UserID: "student-1", TokenHash: []byte("hash-1")})
+	if err != nil {
+		t.Fatalf("UpsertCalendarFeed: %v", err)
+	}
+	if _, err := q.TouchCalendarFeedByTokenHash(ctx, first.TokenHash); err != nil {
+		t.Fatalf("TouchCalendarFeedByTokenHash: %v", err)
+	}
+
+	// Rotating replaces the hash; the old token no longer resolves.
+	if _, err := q.UpsertCalendarFeed(ctx, db.UpsertCalendarFeedParams{UserID: "student-1", TokenHash: []byte("hash-2")}); err != nil {
+		t.Fatalf("UpsertCalendarFeed rotate: %v", err)
+	}
+	if _, err := q.TouchCalendarFeedByTokenHash(ctx, []byte("hash-1")); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("old token err = %v; want ErrNoRows", err)
+	}
+	rotated, err := q.GetCalendarFeed(ctx, "student-1")
+	if err != nil {
+		t.Fatalf("GetCalendarFeed: %v", err)
+	}
+	if rotated.LastAccessedAt.Valid {
+		t.Fatal("rotation kept last_accessed_at of the previous token")
+	}
+
+	if n, err := q.DeleteCalendarFeed(ctx, "student-1"); err != nil || n != 1 {
+		t.Fatalf("DeleteCalendarFeed = %d, %v; want 1", n, err)
+	}
+	if _, err := q.TouchCalendarFeedByTokenHash(ctx, []byte("hash-2")); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("revoked token err = %v; want ErrNoRows", err)
+	}
+}
+
+func TestIntegration_ListCalendarFeedBookings(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Hour)
+	book := func(studentID string, at time.Time) db.CoachingBooking {
+		t.Helper()
+		b, err := q.CreateBooking(ctx, db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: studentID, GroupID: group.ID, SessionTypeID: sessionType.ID,
+			ScheduledAt: pgtype.Timestamptz{Time: at, Valid: true}, DurationMinutes: 60,
+		})
+		if err != nil {
+			t.Fatalf("CreateBooking: %v", err)
+		}
+		return b
+	}
+	upcoming := book("student-1", now.Add(48*time.Hour))
+	cancelled := book("student-1", now.Add(72*time.Hour))
+	book("student-1", now.Add(-90*24*time.Hour)) // outside the window
+	book("student-2", now.Add(24*time.Hour))     // someone else's session
+	if _, err := q.CancelBooking(ctx, db.CancelBookingParams{
+		ID: cancelled.ID, CancelledBy: pgtype.Text{String: "student-1", Valid: true}, ExpertID: "student-1",
+	}); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+
+	rows, err := q.ListCalendarFeedBookings(ctx, db.ListCalendarFeedBookingsParams{
+		UserID: "student-1",
+		FromAt: pgtype.Timestamptz{Time: now.Add(-30 * 24 * time.Hour), Valid: true},
+		ToAt:   pgtype.Timestamptz{Time: now.Add(180 * 24 * time.Hour), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("ListCalendarFeedBookings: %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != upcoming.ID || rows[1].ID != cancelled.ID || !rows[1].IsCancelled {
+		t.Fatalf("ListCalendarFeedBookings = %+v; want the upcoming and the cancelled booking", rows)
+	}
+	if rows[0].SessionTypeName != "Private" || rows[0].GroupName != "Academy" {
+		t.Fatalf("labels = %q/%q", rows[0].SessionTypeName, rows[0].GroupName)
+	}
+}
+
+func TestIntegration_ListCalendarFeedBookingsCollapsesClassesForTheExpert(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Footwork class", DurationMinutes: 60, Capacity: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	start := pgtype.Timestamptz{Time: time.Now().Truncate(time.Hour).Add(48 * time.Hour), Valid: true}
+	class, err := q.CreateClass(ctx, db.CreateClassParams{
+		ExpertID: "expert-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: start, DurationMinutes: 60, Capacity: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateClass: %v", err)
+	}
+	var seats []db.CoachingBooking
+	for seat, studentID := range []string{"student-1", "student-2"} {
+		b, err := q.CreateBooking(ctx, db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: studentID, GroupID: group.ID, SessionTypeID: sessionType.ID,
+			ScheduledAt: start, DurationMinutes: 60,
+			ClassID: class.ID, ClassSeat: pgtype.Int4{Int32: int32(seat + 1), Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("CreateBooking seat %d: %v", seat+1, err)
+		}
+		seats = append(seats, b)
+	}
+	list := func(userID string) []db.ListCalendarFeedBookingsRow {
+		t.Helper()
+		rows, err := q.ListCalendarFeedBookings(ctx, db.ListCalendarFeedBookingsParams{
+			UserID: userID,
+			FromAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			ToAt:   pgtype.Timestamptz{Time: time.Now().Add(7 * 24 * time.Hour), Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("ListCalendarFeedBookings: %v", err)
+		}
+		return rows
+	}
+
+	// The host leaving does not cancel the class the other attendee still has.
+	if _, err := q.CancelBooking(ctx, db.CancelBookingParams{
+		ID: seats[0].ID, CancelledBy: pgtype.Text{String: "student-1", Valid: true}, ExpertID: "student-1",
+	}); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+	rows := list("expert-1")
+	if len(rows) != 1 || rows[0].ID != seats[0].ID || rows[0].ClassID != class.ID || rows[0].IsCancelled {
+		t.Fatalf("expert feed = %+v; want the class once, through its host booking, still on", rows)
+	}
+	if rows := list("student-2"); len(rows) != 1 || rows[0].ID != seats[1].ID {
+		t.Fatalf("student feed = %+v; want their own seat", rows)
+	}
+}
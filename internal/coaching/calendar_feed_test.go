@@ -0,0 +1,195 @@
+package coaching
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+// serveCalendarFeed routes through chi so the "{token}.ics" pattern is
+// exercised the same way as in the API server.
+func serveCalendarFeed(h *Handler, path string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Get("/public/coaching/calendar/{token}.ics", h.ServeCalendarFeed)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestServeCalendarFeedUnknownTokenIsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+
+	digest := sha256.Sum256([]byte("revoked"))
+	q.EXPECT().TouchCalendarFeedByTokenHash(gomock.Any(), digest[:]).Return(db.CoachingCalendarFeed{}, pgx.ErrNoRows)
+
+	rec := serveCalendarFeed(h, "/public/coaching/calendar/revoked.ics")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d; want 404", rec.Code)
+	}
+}
+
+func TestServeCalendarFeedRendersBookingsForOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{AppBaseURL: "https://app.example.com"})
+	b := rescheduleBooking(t)
+
+	digest := sha256.Sum256([]byte("secret-token"))
+	q.EXPECT().TouchCalendarFeedByTokenHash(gomock.Any(), digest[:]).Return(
+		db.CoachingCalendarFeed{UserID: "student-1"}, nil,
+	)
+	q.EXPECT().ListCalendarFeedBookings(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.ListCalendarFeedBookingsParams) ([]db.ListCalendarFeedBookingsRow, error) {
+			if arg.UserID != "student-1" || !arg.FromAt.Time.Before(time.Now()) || !arg.ToAt.Time.After(time.Now()) {
+				t.Errorf("unexpected feed window %+v", arg)
+			}
+			return []db.ListCalendarFeedBookingsRow{
+				{
+					ID: b.ID, ExpertID: "expert-1", StudentID: "student-1", GroupID: b.GroupID,
+					ScheduledAt: b.ScheduledAt, DurationMinutes: 60,
+					SessionTypeName: "Private Session", GroupName: "Training",
+				},
+				{
+					ID: b.ID, ExpertID: "expert-1", StudentID: "student-1", GroupID: b.GroupID,
+					ScheduledAt: b.ScheduledAt, DurationMinutes: 60, IsCancelled: true,
+					SessionTypeName: "Private Session", GroupName: "Training",
+				},
+			}, nil
+		},
+	)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "expert-1").Return(
+		db.UserPreference{UserID: "expert-1", FirstName: "Alex", LastName: "Coach"}, nil,
+	)
+	q.EXPECT().GetUserTimezone(gomock.Any(), "student-1").Return("Europe/Berlin", nil)
+
+	rec := serveCalendarFeed(h, "/public/coaching/calendar/secret-token.ics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	// Unfold continuation lines before matching whole properties.
+	body := strings.ReplaceAll(rec.Body.String(), "\r\n ", "")
+	for _, want := range []string{
+		"METHOD:PUBLISH\r\n",
+		"X-WR-TIMEZONE:Europe/Berlin\r\n",
+		"SUMMARY:Private Session · Alex Coach\r\n",
+		"DTSTART:20300107T100000Z\r\n",
+		"URL:https://app.example.com/sessions/" + rescheduleTestBookingID + "/" + rescheduleTestBookingID + "/call\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"STATUS:CANCELLED\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed missing %q:\n%s", want, body)
+		}
+	}
+	if n := strings.Count(body, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("VEVENT count = %d; want 2", n)
+	}
+}
+
+func TestServeCalendarFeedShowsTheExpertEachClassOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t)
+	classID := pgtype.UUID{Bytes: [16]byte{0xc1}, Valid: true}
+
+	digest := sha256.Sum256([]byte("expert-token"))
+	q.EXPECT().TouchCalendarFeedByTokenHash(gomock.Any(), digest[:]).Return(
+		db.CoachingCalendarFeed{UserID: "expert-1"}, nil,
+	)
+	q.EXPECT().ListCalendarFeedBookings(gomock.Any(), gomock.Any()).Return([]db.ListCalendarFeedBookingsRow{{
+		ID: b.ID, ClassID: classID, ExpertID: "expert-1", StudentID: "student-1", GroupID: b.GroupID,
+		ScheduledAt: b.ScheduledAt, DurationMinutes: 60, SessionTypeName: "Group Class",
+	}}, nil)
+	q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("", nil)
+
+	rec := serveCalendarFeed(h, "/public/coaching/calendar/expert-token.ics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	body := strings.ReplaceAll(rec.Body.String(), "\r\n ", "")
+	for _, want := range []string{
+		"UID:" + classEventUID(classID) + "\r\n",
+		"SUMMARY:Group Class\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRotateCalendarFeedReturnsURLMatchingStoredHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{APIBaseURL: "https://api.example.com/"})
+
+	var stored []byte
+	q.EXPECT().UpsertCalendarFeed(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.UpsertCalendarFeedParams) (db.CoachingCalendarFeed, error) {
+			stored = arg.TokenHash
+			return db.CoachingCalendarFeed{
+				UserID: arg.UserID, TokenHash: arg.TokenHash,
+				CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			}, nil
+		},
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/coaching/calendar-feed", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, &auth.UserContext{ID: "student-1"}))
+	rec := httptest.NewRecorder()
+	h.RotateCalendarFeed(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var resp calendarFeedResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	const prefix = "https://api.example.com/public/coaching/calendar/"
+	if !resp.Active || !strings.HasPrefix(resp.URL, prefix) || !strings.HasSuffix(resp.URL, ".ics") {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	token := strings.TrimSuffix(strings.TrimPrefix(resp.URL, prefix), ".ics")
+	digest := sha256.Sum256([]byte(token))
+	if !bytes.Equal(stored, digest[:]) {
+		t.Fatal("stored hash does not match the token in the returned URL")
+	}
+}
+
+func TestRevokeCalendarFeedWithoutFeedIsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+
+	q.EXPECT().DeleteCalendarFeed(gomock.Any(), "student-1").Return(int64(0), nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/coaching/calendar-feed", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, &auth.UserContext{ID: "student-1"}))
+	rec := httptest.NewRecorder()
+	h.RevokeCalendarFeed(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d; want 404", rec.Code)
+	}
+}
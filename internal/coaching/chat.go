@@ -0,0 +1,325 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Limits on the chat of a call.
+const (
+	MaxBookingMessageLength = 2000
+	MaxBookingMessages      = 500
+	MaxMessageLinks         = 10
+	chatHeartbeatInterval   = 25 * time.Second
+)
+
+// linkPattern finds the web links pasted into a chat message.
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// messageLinks returns the distinct links in body in the order they appear,
+// without the punctuation that usually closes a sentence around them.
+func messageLinks(body string) []string {
+	links := []string{}
+	for _, link := range linkPattern.FindAllString(body, -1) {
+		link = strings.TrimRight(link, ".,;:!?)]}'")
+		if len(link) <= len("https://") || slices.Contains(links, link) {
+			continue
+		}
+		links = append(links, link)
+		if len(links) == MaxMessageLinks {
+			break
+		}
+	}
+	return links
+}
+
+// --- DTO ---
+
+type bookingMessageResponse struct {
+	ID         string    `json:"id"`
+	BookingID  string    `json:"booking_id"`
+	AuthorID   string    `json:"author_id"`
+	AuthorRole string    `json:"author_role"`
+	Body       string    `json:"body"`
+	Links      []string  `json:"links"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func toBookingMessageResponse(m db.CoachingBookingMessage) bookingMessageResponse {
+	links := m.Links
+	if links == nil {
+		links = []string{}
+	}
+	return bookingMessageResponse{
+		ID:         uuidToString(m.ID),
+		BookingID:  uuidToString(m.BookingID),
+		AuthorID:   m.AuthorID,
+		AuthorRole: m.AuthorRole,
+		Body:       m.Body,
+		Links:      links,
+		CreatedAt:  m.CreatedAt.Time,
+	}
+}
+
+type bookingMessageRequest struct {
+	Body string `json:"body"`
+}
+
+// validate trims the body in place and returns a client-facing message, or ""
+// when the message is acceptable.
+func (req *bookingMessageRequest) validate() string {
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || utf8.RuneCountInString(req.Body) > MaxBookingMessageLength {
+		return "body must be between 1 and 2000 characters"
+	}
+	return ""
+}
+
+// --- Handlers ---
+
+// ListBookingMessages handles GET /groups/{groupID}/coaching/bookings/{bookingID}/messages.
+// Every participant of the call reads its whole chat, before, during and after
+// the session. The seats of a class share the chat of the class.
+func (h *Handler) ListBookingMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, call, _, ok := h.loadChatCall(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+
+	messages, err := h.q.ListBookingMessages(ctx, call.Host.ID)
+	if err != nil {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "list_booking_messages_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(call.Host.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list messages", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]bookingMessageResponse, 0, len(messages))
+	for _, m := range messages {
+		resp = append(resp, toBookingMessageResponse(m))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// PostBookingMessage handles POST /groups/{groupID}/coaching/bookings/{bookingID}/messages.
+// Links in the body are kept alongside it so they can be listed on their own.
+// Connected participants receive the message through the chat stream.
+func (h *Handler) PostBookingMessage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	b, call, caller, ok := h.loadChatCall(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+	if b.IsCancelled {
+		http.Error(w, "Booking is cancelled", http.StatusConflict)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 16*1024)
+	var req bookingMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	m, err := h.createBookingMessage(ctx, db.CreateBookingMessageParams{
+		BookingID:  call.Host.ID,
+		AuthorID:   user.ID,
+		AuthorRole: caller.Role,
+		Body:       req.Body,
+		Links:      messageLinks(req.Body),
+	})
+	if errors.Is(err, errChatFull) {
+		http.Error(w, "This session's chat is full", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "create_booking_message_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(call.Host.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to send message", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toBookingMessageResponse(m))
+}
+
+// errChatFull is returned when a call's chat already holds MaxBookingMessages.
+var errChatFull = errors.New("chat is full")
+
+// createBookingMessage adds a message to the chat of a call. The host booking
+// stays locked while its messages are counted, so concurrent posts cannot
+// take the chat past MaxBookingMessages.
+func (h *Handler) createBookingMessage(ctx context.Context, arg db.CreateBookingMessageParams) (db.CoachingBookingMessage, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingBookingMessage{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	if _, err := qtx.GetBookingForMessageUpdate(ctx, arg.BookingID); err != nil {
+		return db.CoachingBookingMessage{}, err
+	}
+	count, err := qtx.CountBookingMessages(ctx, arg.BookingID)
+	if err != nil {
+		return db.CoachingBookingMessage{}, err
+	}
+	if count >= MaxBookingMessages {
+		return db.CoachingBookingMessage{}, errChatFull
+	}
+	m, err := qtx.CreateBookingMessage(ctx, arg)
+	if err != nil {
+		return db.CoachingBookingMessage{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBookingMessage{}, err
+	}
+	return m, nil
+}
+
+// StreamBookingMessages handles GET /groups/{groupID}/coaching/bookings/{bookingID}/messages/stream.
+// It opens a Server-Sent Events connection that pushes each new message of
+// the call's chat; clients load the history through ListBookingMessages.
+func (h *Handler) StreamBookingMessages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, call, _, ok := h.loadChatCall(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	key := uuidToString(call.Host.ID)
+	ch := h.chatHub.Subscribe(key)
+	defer h.chatHub.Unsubscribe(key, ch)
+
+	heartbeat := time.NewTicker(chatHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", msg)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// loadChatCall resolves the booking in the URL to the call whose chat it
+// shares and the caller's place in it. Writes the error response and returns
+// false when the caller is not a participant.
+func (h *Handler) loadChatCall(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) (db.CoachingBooking, bookingCall, callParticipant, bool) {
+	log := logger.From(ctx, h.logger)
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+	}
+	bookingID, err := parseUUID(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+	}
+
+	// GetBooking is scoped to expert_id OR student_id — ensures caller is a participant.
+	b, err := h.q.GetBooking(ctx, db.GetBookingParams{ID: bookingID, ExpertID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Booking not found", http.StatusNotFound)
+			return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+		}
+		log.ErrorContext(ctx, "get_booking_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(bookingID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch booking", http.StatusInternalServerError)
+		return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+	}
+	if b.GroupID != groupID {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+	}
+
+	call, err := resolveBookingCall(ctx, h.q, b)
+	if err != nil {
+		log.ErrorContext(ctx, "coaching_call_resolve_failed", slog.String("component", "coaching"), slog.Any("err", err))
+		http.Error(w, "Failed to fetch booking", http.StatusInternalServerError)
+		return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+	}
+	caller, ok := call.participant(userID)
+	if !ok {
+		if !b.IsCancelled {
+			http.Error(w, "Booking not found", http.StatusNotFound)
+			return db.CoachingBooking{}, bookingCall{}, callParticipant{}, false
+		}
+		// A student who gave up their seat in a class keeps reading what
+		// was said in it.
+		caller = callParticipant{UserID: userID, Role: "student"}
+	}
+	return b, call, caller, true
+}
@@ -0,0 +1,169 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/coaching"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CoachingBookingChat(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private lesson", DurationMinutes: 60, Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}, DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	post := func(authorID, role, body string, links []string) db.CoachingBookingMessage {
+		m, err := q.CreateBookingMessage(ctx, db.CreateBookingMessageParams{
+			BookingID: booking.ID, AuthorID: authorID, AuthorRole: role, Body: body, Links: links,
+		})
+		if err != nil {
+			t.Fatalf("CreateBookingMessage(%q): %v", body, err)
+		}
+		return m
+	}
+	first := post("expert-1", "expert", "Drill X: https://example.com/x", []string{"https://example.com/x"})
+	second := post("student-1", "student", "Thanks!", []string{})
+
+	if got, err := q.GetBookingMessage(ctx, first.ID); err != nil || !slices.Equal(got.Links, first.Links) {
+		t.Fatalf("GetBookingMessage = %+v, %v; want the links kept", got, err)
+	}
+	if len(second.Links) != 0 {
+		t.Fatalf("message without links = %#v, want none", second.Links)
+	}
+	if n, err := q.CountBookingMessages(ctx, booking.ID); err != nil || n != 2 {
+		t.Fatalf("CountBookingMessages = %d, %v; want 2", n, err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO coaching_booking_messages (booking_id, author_id, author_role, body) VALUES ($1, 'x', 'admin', 'hi')`, booking.ID); err == nil {
+		t.Fatal("message with an unknown author role was accepted")
+	}
+
+	messages, err := q.ListBookingMessages(ctx, booking.ID)
+	if err != nil || len(messages) != 2 || messages[0].ID != first.ID || messages[1].ID != second.ID {
+		t.Fatalf("ListBookingMessages = %v, %v; want both in order", messages, err)
+	}
+
+	// The recording carries the chat of the call it recorded.
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Live coaching recording", GroupID: group.ID, OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	if recorded, err := q.ListRecordingAssetMessages(ctx, asset.ID); err != nil || len(recorded) != 0 {
+		t.Fatalf("ListRecordingAssetMessages before assignment = %d, %v; want none", len(recorded), err)
+	}
+	if _, err := q.AssignBookingRecordingAsset(ctx, db.AssignBookingRecordingAssetParams{ID: booking.ID, RecordingAssetID: asset.ID}); err != nil {
+		t.Fatalf("AssignBookingRecordingAsset: %v", err)
+	}
+	recorded, err := q.ListRecordingAssetMessages(ctx, asset.ID)
+	if err != nil || len(recorded) != 2 || recorded[0].ID != first.ID {
+		t.Fatalf("ListRecordingAssetMessages = %v, %v; want the call's chat", recorded, err)
+	}
+}
+
+func TestIntegration_PostBookingMessageCapsTheChat(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private lesson", DurationMinutes: 60, Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}, DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	h := coaching.NewHandler(q, pool, nil, nil, slog.Default(), coaching.HandlerConfig{})
+	post := func(body string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("groupID", group.ID.String())
+		rctx.URLParams.Add("bookingID", booking.ID.String())
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		reqCtx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		req = req.WithContext(context.WithValue(reqCtx, auth.UserKey, &auth.UserContext{ID: "student-1"}))
+		rec := httptest.NewRecorder()
+		h.PostBookingMessage(rec, req)
+		return rec
+	}
+
+	rec := post(`{"body":" Drill X: https://example.com/x "}`)
+	var sent struct {
+		AuthorRole string   `json:"author_role"`
+		Links      []string `json:"links"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &sent); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("PostBookingMessage = %d %s", rec.Code, rec.Body.String())
+	}
+	if sent.AuthorRole != "student" || !slices.Equal(sent.Links, []string{"https://example.com/x"}) {
+		t.Fatalf("message = %+v, want the student's message with its link", sent)
+	}
+
+	// One seat is left in the chat; concurrent posts must not overfill it.
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO coaching_booking_messages (booking_id, author_id, author_role, body)
+		SELECT $1, 'expert-1', 'expert', 'filler' FROM generate_series(1, $2::int)`,
+		booking.ID, coaching.MaxBookingMessages-2); err != nil {
+		t.Fatalf("fill chat: %v", err)
+	}
+	var mu sync.Mutex
+	codes := map[int]int{}
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code := post(`{"body":"one more"}`).Code
+			mu.Lock()
+			codes[code]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if codes[http.StatusCreated] != 1 || codes[http.StatusConflict] != 4 {
+		t.Fatalf("concurrent posts = %v, want one accepted and the rest refused", codes)
+	}
+	if n, err := q.CountBookingMessages(ctx, booking.ID); err != nil || n != coaching.MaxBookingMessages {
+		t.Fatalf("CountBookingMessages = %d, %v; want the cap", n, err)
+	}
+}
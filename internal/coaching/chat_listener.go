@@ -0,0 +1,63 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const chatNotifyChannel = "coaching_booking_messages"
+
+// NewChatListener returns the listener that publishes new booking chat
+// messages (emitted by the DB trigger) through the hub, keyed by the call's
+// host booking, to the chat streams open on this instance. Run it once per API
+// instance, sharing the hub with the Handler.
+func NewChatListener(pool *pgxpool.Pool, q db.Querier, hub *notifications.Hub, logger *slog.Logger) *notifications.Listener {
+	d := &chatDispatcher{q: q, hub: hub, logger: logger}
+	return notifications.NewListener(pool, chatNotifyChannel, d.dispatch, logger)
+}
+
+type chatDispatcher struct {
+	q      db.Querier
+	hub    *notifications.Hub
+	logger *slog.Logger
+}
+
+type chatNotifyPayload struct {
+	ID string `json:"id"`
+}
+
+func (d *chatDispatcher) dispatch(ctx context.Context, raw string) {
+	var p chatNotifyPayload
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		d.logger.WarnContext(ctx, "chat_notify_decode_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	id, err := parseUUID(p.ID)
+	if err != nil {
+		return
+	}
+	m, err := d.q.GetBookingMessage(ctx, id)
+	if err != nil {
+		d.logger.WarnContext(ctx, "chat_notify_fetch_failed",
+			slog.String("component", "coaching"),
+			slog.String("message_id", p.ID),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	msg, err := json.Marshal(toBookingMessageResponse(m))
+	if err != nil {
+		return
+	}
+	d.hub.Publish(uuidToString(m.BookingID), msg)
+}
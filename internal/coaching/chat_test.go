@@ -0,0 +1,88 @@
+package coaching
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMessageLinks(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"Try drill X tomorrow", []string{}},
+		{"See https://example.com/jab.", []string{"https://example.com/jab"}},
+		{"(http://a.example/x?y=1) and https://b.example, then http://a.example/x?y=1!",
+			[]string{"http://a.example/x?y=1", "https://b.example"}},
+		{"just https:// here", []string{}},
+	}
+	for _, tt := range tests {
+		if got := messageLinks(tt.body); !slices.Equal(got, tt.want) {
+			t.Errorf("messageLinks(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+
+	var many []string
+	for i := range MaxMessageLinks + 5 {
+		many = append(many, fmt.Sprintf("https://example.com/%d", i))
+	}
+	if got := messageLinks(strings.Join(many, " ")); len(got) != MaxMessageLinks {
+		t.Fatalf("messageLinks kept %d links, want %d", len(got), MaxMessageLinks)
+	}
+}
+
+func TestBookingMessageRequestValidate(t *testing.T) {
+	ok := bookingMessageRequest{Body: "  Drill X  "}
+	if msg := ok.validate(); msg != "" || ok.Body != "Drill X" {
+		t.Fatalf("validate() = %q with body %q, want trimmed body accepted", msg, ok.Body)
+	}
+	for _, body := range []string{" ", strings.Repeat("é", MaxBookingMessageLength+1)} {
+		req := bookingMessageRequest{Body: body}
+		if msg := req.validate(); msg == "" {
+			t.Errorf("validate() accepted a body of %d characters", len([]rune(body)))
+		}
+	}
+	long := bookingMessageRequest{Body: strings.Repeat("é", MaxBookingMessageLength)}
+	if msg := long.validate(); msg != "" {
+		t.Fatalf("validate() = %q, want characters counted rather than bytes", msg)
+	}
+}
+
+func TestListBookingMessagesOfCancelledBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := pastBooking(t)
+	b.IsCancelled = true
+
+	// The history stays readable; only new messages are refused.
+	q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(b, nil).Times(2)
+	q.EXPECT().ListBookingMessages(gomock.Any(), b.ID).Return([]db.CoachingBookingMessage{{ID: b.ID, BookingID: b.ID, Body: "See you"}}, nil)
+
+	params := map[string]string{"bookingID": rescheduleTestBookingID}
+	rec := httptest.NewRecorder()
+	h.ListBookingMessages(rec, homeworkTestRequest(http.MethodGet, "", "student-1", params))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp []bookingMessageResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || len(resp) != 1 || resp[0].Links == nil {
+		t.Fatalf("list = %+v, %v; want one message with an empty link list", resp, err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.PostBookingMessage(rec, homeworkTestRequest(http.MethodPost, `{"body":"hi"}`, "student-1", params))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("post status = %d, want 409", rec.Code)
+	}
+}
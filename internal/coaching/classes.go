@@ -0,0 +1,173 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// classSeatUIDBase offsets the Agora UIDs of class attendees past the fixed
+// UIDs of one-to-one calls and the recording clients.
+const classSeatUIDBase = uint32(100)
+
+// errClassFull is returned when every seat of a class is taken.
+var errClassFull = errors.New("class is full")
+
+// errAlreadyInClass is returned when the student already holds a seat in the class.
+var errAlreadyInClass = errors.New("already booked into the class")
+
+// callParticipant is someone allowed into a booking's call.
+type callParticipant struct {
+	UserID string
+	Role   string
+	UID    uint32
+}
+
+// bookingCall is the Agora call a booking joins. A one-to-one booking has a
+// call of its own; the bookings of a class share the call of the class's host
+// booking, its first seat.
+type bookingCall struct {
+	Host         db.CoachingBooking
+	Participants []callParticipant
+}
+
+func (c bookingCall) channel() string {
+	return "coaching_" + uuidToString(c.Host.ID)
+}
+
+// participant returns the caller's place in the call. An admin who booked a
+// session with themselves joins as the expert.
+func (c bookingCall) participant(userID string) (callParticipant, bool) {
+	var found callParticipant
+	ok := false
+	for _, p := range c.Participants {
+		if p.UserID != userID {
+			continue
+		}
+		if p.Role == "expert" {
+			return p, true
+		}
+		found, ok = p, true
+	}
+	return found, ok
+}
+
+// videoUIDs are the Agora UIDs whose video a mixed recording of the call keeps.
+func (c bookingCall) videoUIDs() []string {
+	uids := make([]string, 0, len(c.Participants))
+	for _, p := range c.Participants {
+		uids = append(uids, strconv.FormatUint(uint64(p.UID), 10))
+	}
+	return uids
+}
+
+// resolveBookingCall loads the call of booking b.
+func resolveBookingCall(ctx context.Context, q db.Querier, b db.CoachingBooking) (bookingCall, error) {
+	if !b.ClassID.Valid {
+		return bookingCall{Host: b, Participants: oneToOneParticipants(b.StudentID, b.ExpertID)}, nil
+	}
+	host, err := q.GetClassHostBooking(ctx, b.ClassID)
+	if err != nil {
+		return bookingCall{}, err
+	}
+	participants, err := classParticipants(ctx, q, b.ClassID, b.ExpertID)
+	if err != nil {
+		return bookingCall{}, err
+	}
+	return bookingCall{Host: host, Participants: participants}, nil
+}
+
+func oneToOneParticipants(studentID, expertID string) []callParticipant {
+	return []callParticipant{
+		{UserID: studentID, Role: "student", UID: studentParticipantUIDNum},
+		{UserID: expertID, Role: "expert", UID: expertParticipantUIDNum},
+	}
+}
+
+// classParticipants lists the active attendees of a class in seat order,
+// followed by its expert.
+func classParticipants(ctx context.Context, q db.Querier, classID pgtype.UUID, expertID string) ([]callParticipant, error) {
+	attendees, err := q.ListClassAttendees(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	participants := make([]callParticipant, 0, len(attendees)+1)
+	for _, a := range attendees {
+		participants = append(participants, callParticipant{
+			UserID: a.StudentID, Role: "student", UID: classSeatUIDBase + uint32(a.ClassSeat.Int32),
+		})
+	}
+	return append(participants, callParticipant{UserID: expertID, Role: "expert", UID: expertParticipantUIDNum}), nil
+}
+
+// joinClassInTx seats a booking of a group session type in the class at its
+// start, opening the class when there is none yet, and sets the class and seat
+// on arg. joining reports that the class already holds the slot through its
+// other attendees, so the booking skips the overlap and rule checks its first
+// attendee passed. A class whose attendees all cancelled holds nothing: its
+// next booking is checked like the first. Run it inside the booking
+// transaction before those checks.
+func joinClassInTx(ctx context.Context, q db.Querier, st db.CoachingSessionType, arg *db.CreateBookingParams) (joining bool, err error) {
+	class, err := q.GetClassForSlot(ctx, db.GetClassForSlotParams{
+		SessionTypeID: st.ID,
+		ScheduledAt:   arg.ScheduledAt,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		class, err = q.CreateClass(ctx, db.CreateClassParams{
+			ExpertID:        arg.ExpertID,
+			GroupID:         arg.GroupID,
+			SessionTypeID:   st.ID,
+			ScheduledAt:     arg.ScheduledAt,
+			DurationMinutes: arg.DurationMinutes,
+			Capacity:        st.Capacity,
+		})
+	}
+	if err != nil {
+		return false, err
+	}
+	seats, err := q.GetClassSeats(ctx, db.GetClassSeatsParams{StudentID: arg.StudentID, ClassID: class.ID})
+	if err != nil {
+		return false, err
+	}
+	if seats.Joined {
+		return false, errAlreadyInClass
+	}
+	// A class keeps the capacity it opened with.
+	if seats.Taken >= int64(class.Capacity) {
+		return false, errClassFull
+	}
+	arg.ClassID = class.ID
+	arg.ClassSeat = pgtype.Int4{Int32: seats.LastSeat + 1, Valid: true}
+	return seats.Taken > 0, nil
+}
+
+// mergeClassSlots adds the open classes to the fresh slots of a group session
+// type, which offer a whole class of capacity seats, in start order. A class
+// already holds its time, so only the notice and horizon rules apply to it.
+func mergeClassSlots(slots []SlotResponse, classes []db.ListOpenClassesRow, capacity int32, now time.Time, rules bookingRules) []SlotResponse {
+	for i := range slots {
+		slots[i].SeatsLeft = capacity
+	}
+	minNotice := now.Add(rules.MinNotice)
+	for _, c := range classes {
+		start := c.ScheduledAt.Time
+		if start.Before(minNotice) || rules.Horizon > 0 && start.Sub(now) > rules.Horizon {
+			continue
+		}
+		slots = append(slots, SlotResponse{
+			ExpertID:  c.ExpertID,
+			StartsAt:  start,
+			EndsAt:    start.Add(time.Duration(c.DurationMinutes) * time.Minute),
+			Duration:  c.DurationMinutes,
+			SeatsLeft: c.Capacity - int32(c.Taken),
+		})
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].StartsAt.Before(slots[j].StartsAt) })
+	return slots
+}
@@ -0,0 +1,144 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CoachingClasses(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if _, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Too big", DurationMinutes: 60, Capacity: 51,
+	}); err == nil {
+		t.Fatal("session type above the class capacity limit was accepted")
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Footwork class", DurationMinutes: 60, Capacity: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	start := time.Date(2030, 7, 1, 10, 0, 0, 0, time.UTC)
+	class, err := q.CreateClass(ctx, db.CreateClassParams{
+		ExpertID: "expert-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: ts(start), DurationMinutes: 60, Capacity: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateClass: %v", err)
+	}
+	if _, err := q.CreateClass(ctx, db.CreateClassParams{
+		ExpertID: "expert-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: ts(start), DurationMinutes: 60, Capacity: 3,
+	}); err == nil {
+		t.Fatal("second class in the same slot was accepted")
+	}
+	if got, err := q.GetClassForSlot(ctx, db.GetClassForSlotParams{
+		SessionTypeID: sessionType.ID, ScheduledAt: ts(start),
+	}); err != nil || got.ID != class.ID {
+		t.Fatalf("GetClassForSlot = %v, %v; want the class", got.ID, err)
+	}
+
+	book := func(studentID string, seat int32) (db.CoachingBooking, error) {
+		return q.CreateBooking(ctx, db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: studentID, GroupID: group.ID, SessionTypeID: sessionType.ID,
+			ScheduledAt: ts(start), DurationMinutes: 60,
+			ClassID: class.ID, ClassSeat: pgtype.Int4{Int32: seat, Valid: true},
+		})
+	}
+	host, err := book("student-1", 1)
+	if err != nil {
+		t.Fatalf("CreateBooking seat 1: %v", err)
+	}
+	second, err := book("student-2", 2)
+	if err != nil {
+		t.Fatalf("CreateBooking seat 2: %v", err)
+	}
+	if _, err := book("student-1", 3); err == nil {
+		t.Fatal("student took a second seat in the class")
+	}
+	if _, err := book("student-3", 2); err == nil {
+		t.Fatal("seat was handed out twice")
+	}
+	if _, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-3", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: ts(start), DurationMinutes: 60, ClassID: class.ID,
+	}); err == nil {
+		t.Fatal("class booking without a seat was accepted")
+	}
+
+	seats, err := q.GetClassSeats(ctx, db.GetClassSeatsParams{StudentID: "student-2", ClassID: class.ID})
+	if err != nil || seats.Taken != 2 || !seats.Joined || seats.LastSeat != 2 {
+		t.Fatalf("GetClassSeats = %+v, %v; want 2 taken by student-2 up to seat 2", seats, err)
+	}
+	if got, err := q.GetClassHostBooking(ctx, class.ID); err != nil || got.ID != host.ID {
+		t.Fatalf("GetClassHostBooking = %v, %v; want seat 1", got.ID, err)
+	}
+	open, err := q.ListOpenClasses(ctx, db.ListOpenClassesParams{
+		SessionTypeID: sessionType.ID, FromTime: ts(start.Add(-time.Hour)), ToTime: ts(start.Add(time.Hour)),
+	})
+	if err != nil || len(open) != 1 || open[0].Taken != 2 {
+		t.Fatalf("ListOpenClasses = %+v, %v; want the class with 2 seats taken", open, err)
+	}
+	if n, err := q.CountBookingsStartingInRange(ctx, db.CountBookingsStartingInRangeParams{
+		ExpertID: "expert-1", FromAt: ts(start), ToAt: ts(start.Add(time.Hour)),
+	}); err != nil || n != 1 {
+		t.Fatalf("CountBookingsStartingInRange = %d, %v; want the class once", n, err)
+	}
+
+	// Every attendee's presence counts on the host booking.
+	for i, p := range []struct{ userID, role string }{{"student-1", "student"}, {"student-2", "student"}, {"expert-1", "expert"}} {
+		if _, err := q.UpsertBookingPresence(ctx, db.UpsertBookingPresenceParams{
+			BookingID: host.ID, ParticipantID: p.userID, ParticipantRole: p.role,
+			ConnectionID: pgtype.UUID{Bytes: [16]byte{byte(i + 1)}, Valid: true},
+		}); err != nil {
+			t.Fatalf("UpsertBookingPresence(%s): %v", p.userID, err)
+		}
+	}
+	if n, err := q.CountFreshBookingParticipants(ctx, db.CountFreshBookingParticipantsParams{
+		BookingID: host.ID, FreshSeconds: 60,
+	}); err != nil || n != 3 {
+		t.Fatalf("CountFreshBookingParticipants = %d, %v; want 3", n, err)
+	}
+
+	// The recording belongs to the expert and is shared with the attendees.
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Live coaching recording", GroupID: group.ID, OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	if _, err := q.AssignBookingRecordingAsset(ctx, db.AssignBookingRecordingAssetParams{ID: host.ID, RecordingAssetID: asset.ID}); err != nil {
+		t.Fatalf("AssignBookingRecordingAsset: %v", err)
+	}
+	if err := q.AssignClassRecordingAsset(ctx, db.AssignClassRecordingAssetParams{AssetID: asset.ID, ClassID: class.ID}); err != nil {
+		t.Fatalf("AssignClassRecordingAsset: %v", err)
+	}
+	if got, err := q.GetBooking(ctx, db.GetBookingParams{ID: second.ID, ExpertID: "student-2"}); err != nil || got.RecordingAssetID != asset.ID {
+		t.Fatalf("second attendee recording = %v, %v; want the class asset", got.RecordingAssetID, err)
+	}
+	for _, tt := range []struct {
+		userID  string
+		visible bool
+	}{{"student-1", true}, {"student-2", true}, {"student-3", false}} {
+		_, err := q.GetVisibleAsset(ctx, db.GetVisibleAssetParams{AssetID: asset.ID, IsStudent: true, UserID: tt.userID})
+		if tt.visible && err != nil || !tt.visible && !errors.Is(err, pgx.ErrNoRows) {
+			t.Fatalf("GetVisibleAsset(%s) error = %v, want visible=%v", tt.userID, err, tt.visible)
+		}
+	}
+}
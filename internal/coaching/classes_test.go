@@ -0,0 +1,149 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJoinClassInTx(t *testing.T) {
+	st := db.CoachingSessionType{ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Capacity: 3}
+	class := db.CoachingClass{ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, Capacity: 2}
+	newArg := func() db.CreateBookingParams {
+		return db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: "student-1", DurationMinutes: 60,
+			ScheduledAt: pgtype.Timestamptz{Time: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC), Valid: true},
+		}
+	}
+
+	t.Run("opens the class for its first attendee", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		q := dbmocks.NewMockQuerier(ctrl)
+		arg := newArg()
+		q.EXPECT().GetClassForSlot(gomock.Any(), gomock.Any()).Return(db.CoachingClass{}, pgx.ErrNoRows)
+		q.EXPECT().CreateClass(gomock.Any(), db.CreateClassParams{
+			ExpertID: "expert-1", SessionTypeID: st.ID, ScheduledAt: arg.ScheduledAt, DurationMinutes: 60, Capacity: 3,
+		}).Return(db.CoachingClass{ID: class.ID, Capacity: 3}, nil)
+		q.EXPECT().GetClassSeats(gomock.Any(), db.GetClassSeatsParams{StudentID: "student-1", ClassID: class.ID}).
+			Return(db.GetClassSeatsRow{}, nil)
+
+		joining, err := joinClassInTx(context.Background(), q, st, &arg)
+		if err != nil || joining {
+			t.Fatalf("joinClassInTx = %v, %v; want a new class", joining, err)
+		}
+		if arg.ClassID != class.ID || arg.ClassSeat.Int32 != 1 {
+			t.Fatalf("seat = %v/%d, want seat 1 of the new class", arg.ClassID, arg.ClassSeat.Int32)
+		}
+	})
+
+	t.Run("takes the seat after the last one handed out", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		q := dbmocks.NewMockQuerier(ctrl)
+		arg := newArg()
+		q.EXPECT().GetClassForSlot(gomock.Any(), gomock.Any()).Return(class, nil)
+		q.EXPECT().GetClassSeats(gomock.Any(), gomock.Any()).Return(db.GetClassSeatsRow{Taken: 1, LastSeat: 2}, nil)
+
+		joining, err := joinClassInTx(context.Background(), q, st, &arg)
+		if err != nil || !joining {
+			t.Fatalf("joinClassInTx = %v, %v; want joining", joining, err)
+		}
+		if arg.ClassSeat.Int32 != 3 {
+			t.Fatalf("seat = %d, want 3", arg.ClassSeat.Int32)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		seats   db.GetClassSeatsRow
+		wantErr error
+	}{
+		// The class opened with two seats; the session type's capacity of
+		// three since does not apply to it.
+		{"full", db.GetClassSeatsRow{Taken: 2, LastSeat: 2}, errClassFull},
+		{"already in", db.GetClassSeatsRow{Taken: 1, Joined: true, LastSeat: 1}, errAlreadyInClass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			arg := newArg()
+			q.EXPECT().GetClassForSlot(gomock.Any(), gomock.Any()).Return(class, nil)
+			q.EXPECT().GetClassSeats(gomock.Any(), gomock.Any()).Return(tt.seats, nil)
+
+			if _, err := joinClassInTx(context.Background(), q, st, &arg); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("joinClassInTx error = %v, want %v", err, tt.wantErr)
+			}
+			if arg.ClassID.Valid {
+				t.Fatal("a refused booking was seated")
+			}
+		})
+	}
+}
+
+func TestResolveBookingCallForClass(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	classID := pgtype.UUID{Bytes: [16]byte{2}, Valid: true}
+	host := db.CoachingBooking{ID: pgtype.UUID{Bytes: [16]byte{3}, Valid: true}, ClassID: classID}
+	mine := db.CoachingBooking{
+		ID: pgtype.UUID{Bytes: [16]byte{4}, Valid: true}, ExpertID: "expert-1", StudentID: "student-2", ClassID: classID,
+	}
+
+	q.EXPECT().GetClassHostBooking(gomock.Any(), classID).Return(host, nil)
+	q.EXPECT().ListClassAttendees(gomock.Any(), classID).Return([]db.CoachingBooking{
+		{StudentID: "student-1", ClassSeat: pgtype.Int4{Int32: 2, Valid: true}},
+		{StudentID: "student-2", ClassSeat: pgtype.Int4{Int32: 3, Valid: true}},
+	}, nil)
+
+	call, err := resolveBookingCall(context.Background(), q, mine)
+	if err != nil {
+		t.Fatalf("resolveBookingCall: %v", err)
+	}
+	if call.channel() != "coaching_"+uuidToString(host.ID) {
+		t.Fatalf("channel = %q, want the host booking's", call.channel())
+	}
+	if got, ok := call.participant("student-2"); !ok || got.UID != 103 || got.Role != "student" {
+		t.Fatalf("student-2 = %+v, %v; want uid 103 as student", got, ok)
+	}
+	if got, ok := call.participant("expert-1"); !ok || got.UID != expertParticipantUIDNum {
+		t.Fatalf("expert = %+v, %v; want uid %d", got, ok, expertParticipantUIDNum)
+	}
+	if got := call.videoUIDs(); !slices.Equal(got, []string{"102", "103", "2"}) {
+		t.Fatalf("videoUIDs = %v", got)
+	}
+}
+
+func TestMergeClassSlots(t *testing.T) {
+	now := time.Date(2030, 1, 7, 8, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return time.Date(2030, 1, 7, h, 0, 0, 0, time.UTC) }
+	class := func(h int, taken int64) db.ListOpenClassesRow {
+		return db.ListOpenClassesRow{
+			ExpertID: "expert-1", ScheduledAt: pgtype.Timestamptz{Time: at(h), Valid: true},
+			DurationMinutes: 60, Capacity: 4, Taken: taken,
+		}
+	}
+	fresh := []SlotResponse{
+		{ExpertID: "expert-1", StartsAt: at(9), EndsAt: at(10), Duration: 60},
+		{ExpertID: "expert-1", StartsAt: at(12), EndsAt: at(13), Duration: 60},
+	}
+
+	got := mergeClassSlots(fresh, []db.ListOpenClassesRow{class(8, 1), class(11, 3)}, 5, now, bookingRules{MinNotice: 30 * time.Minute})
+
+	// The 08:00 class is inside the booking notice.
+	want := []SlotResponse{
+		{ExpertID: "expert-1", StartsAt: at(9), EndsAt: at(10), Duration: 60, SeatsLeft: 5},
+		{ExpertID: "expert-1", StartsAt: at(11), EndsAt: at(12), Duration: 60, SeatsLeft: 1},
+		{ExpertID: "expert-1", StartsAt: at(12), EndsAt: at(13), Duration: 60, SeatsLeft: 5},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("mergeClassSlots = %+v\nwant %+v", got, want)
+	}
+}
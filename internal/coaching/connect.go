@@ -31,6 +31,9 @@ type connectResponse struct {
 	ScheduledEndsAt time.Time               `json:"scheduled_ends_at"`
 	Student         participantPresentation `json:"student"`
 	Expert          participantPresentation `json:"expert"`
+	// Participants lists everyone allowed into the call: the student and
+	// expert, or every attendee of a class followed by its expert.
+	Participants []participantPresentation `json:"participants"`
 }
 
 type participantPresentation struct {
@@ -42,7 +45,8 @@ type participantPresentation struct {
 
 // ConnectToBooking generates an Agora RTC token for an existing booking.
 // The caller must be a participant (student or expert) and the current time
-// must fall within the connectable window.
+// must fall within the connectable window. Attendees of a class all join the
+// call of its host booking, each with their own UID.
 func (h *Handler) ConnectToBooking(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx, h.logger)
@@ -93,8 +97,19 @@ func (h *Handler) ConnectToBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	channelName := "coaching_" + uuidToString(booking.ID)
-	uid := participantUIDForBooking(user.ID, booking)
+	call, err := resolveBookingCall(ctx, h.q, booking)
+	if err != nil {
+		log.ErrorContext(ctx, "coaching_call_resolve_failed", slog.String("component", "coaching"), slog.Any("err", err))
+		http.Error(w, "Failed to prepare session", http.StatusInternalServerError)
+		return
+	}
+	caller, ok := call.participant(user.ID)
+	if !ok {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return
+	}
+	channelName := call.channel()
+	uid := caller.UID
 
 	tokenTTL := uint32(max(300, int(time.Until(windowEnd).Seconds())))
 	token, err := rtctokenbuilder.BuildTokenWithUid(
@@ -115,19 +130,13 @@ func (h *Handler) ConnectToBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	student, err := h.participantPresentation(ctx, booking.StudentID, studentParticipantUID, "student")
+	participants, err := h.presentCallParticipants(ctx, call.Participants)
 	if err != nil {
 		log.ErrorContext(ctx, "coaching_participant_presentation_failed", slog.String("component", "coaching"), slog.Any("err", err))
 		http.Error(w, "Failed to prepare session", http.StatusInternalServerError)
 		return
 	}
-	expert, err := h.participantPresentation(ctx, booking.ExpertID, expertParticipantUID, "expert")
-	if err != nil {
-		log.ErrorContext(ctx, "coaching_participant_presentation_failed", slog.String("component", "coaching"), slog.Any("err", err))
-		http.Error(w, "Failed to prepare session", http.StatusInternalServerError)
-		return
-	}
-	callerRole := participantRoleForBooking(user.ID, booking)
+	student, expert := bookingPresentations(participants, call.Participants, booking)
 
 	log.InfoContext(ctx, "agora_token_issued",
 		slog.String("component", "coaching"),
@@ -142,30 +151,42 @@ func (h *Handler) ConnectToBooking(w http.ResponseWriter, r *http.Request) {
 		Token:           token,
 		UID:             uid,
 		ConnectionID:    uuid.NewString(),
-		CallerRole:      callerRole,
+		CallerRole:      caller.Role,
 		ScheduledAt:     booking.ScheduledAt.Time,
 		ScheduledEndsAt: booking.ScheduledAt.Time.Add(time.Duration(booking.DurationMinutes) * time.Minute),
 		Student:         student,
 		Expert:          expert,
+		Participants:    participants,
 	})
 }
 
-func (h *Handler) participantPresentation(ctx context.Context, userID, agoraUID, role string) (participantPresentation, error) {
-	prefs, err := h.q.GetUserPreferences(ctx, userID)
-	if err != nil {
-		return participantPresentation{}, err
+func (h *Handler) presentCallParticipants(ctx context.Context, participants []callParticipant) ([]participantPresentation, error) {
+	out := make([]participantPresentation, 0, len(participants))
+	for _, p := range participants {
+		prefs, err := h.q.GetUserPreferences(ctx, p.UserID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, participantPresentation{
+			UID:         p.UID,
+			Role:        p.Role,
+			DisplayName: preferences.PublicDisplayName(prefs),
+			Avatar:      prefs.Avatar,
+		})
 	}
-	return participantPresentation{
-		UID:         uint32(mustParseNumericUID(agoraUID)),
-		Role:        role,
-		DisplayName: preferences.PublicDisplayName(prefs),
-		Avatar:      prefs.Avatar,
-	}, nil
+	return out, nil
 }
 
-func mustParseNumericUID(value string) int {
-	if value == studentParticipantUID {
-		return 1
+// bookingPresentations picks the student of b and the expert out of the
+// presented call participants.
+func bookingPresentations(participants []participantPresentation, call []callParticipant, b db.CoachingBooking) (student, expert participantPresentation) {
+	for i, p := range call {
+		switch {
+		case p.Role == "expert":
+			expert = participants[i]
+		case p.UserID == b.StudentID:
+			student = participants[i]
+		}
 	}
-	return 2
+	return student, expert
 }
@@ -0,0 +1,446 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// errNoCredit reports that a student has no usable credit for a booking of a
+// credit-only session type.
+var errNoCredit = errors.New("no session credit available")
+
+// --- DTO ---
+
+type creditGrantResponse struct {
+	ID        string     `json:"id"`
+	StudentID string     `json:"student_id"`
+	ExpertID  string     `json:"expert_id"`
+	GroupID   string     `json:"group_id"`
+	Quantity  int32      `json:"quantity"`
+	Remaining int32      `json:"remaining"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+	GrantedBy string     `json:"granted_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type creditLedgerEntryResponse struct {
+	ID        string    `json:"id"`
+	GrantID   string    `json:"grant_id"`
+	StudentID string    `json:"student_id"`
+	ExpertID  string    `json:"expert_id"`
+	BookingID *string   `json:"booking_id,omitempty"`
+	Kind      string    `json:"kind"`
+	Delta     int32     `json:"delta"`
+	ActorID   *string   `json:"actor_id,omitempty"` // nil for system movements (expiry)
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// grantCreditsRequest grants a package of credits the student can spend on
+// the granting expert's credit-only session types in this group.
+type grantCreditsRequest struct {
+	StudentID string     `json:"student_id"`
+	Quantity  int32      `json:"quantity"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+}
+
+func toCreditGrantResponse(g db.CoachingCreditGrant) creditGrantResponse {
+	resp := creditGrantResponse{
+		ID:        uuidToString(g.ID),
+		StudentID: g.StudentID,
+		ExpertID:  g.ExpertID,
+		GroupID:   uuidToString(g.GroupID),
+		Quantity:  g.Quantity,
+		Remaining: g.Remaining,
+		GrantedBy: g.GrantedBy,
+		CreatedAt: g.CreatedAt.Time,
+	}
+	if g.ExpiresAt.Valid {
+		resp.ExpiresAt = &g.ExpiresAt.Time
+	}
+	if g.Note.Valid {
+		resp.Note = &g.Note.String
+	}
+	return resp
+}
+
+func toCreditLedgerEntryResponse(e db.CoachingCreditLedger) creditLedgerEntryResponse {
+	resp := creditLedgerEntryResponse{
+		ID:        uuidToString(e.ID),
+		GrantID:   uuidToString(e.GrantID),
+		StudentID: e.StudentID,
+		ExpertID:  e.ExpertID,
+		Kind:      string(e.Kind),
+		Delta:     e.Delta,
+		CreatedAt: e.CreatedAt.Time,
+	}
+	if e.BookingID.Valid {
+		id := uuidToString(e.BookingID)
+		resp.BookingID = &id
+	}
+	if e.ActorID.Valid {
+		resp.ActorID = &e.ActorID.String
+	}
+	return resp
+}
+
+// validateGrantCredits returns an error string suitable for http.Error, or "".
+func validateGrantCredits(req grantCreditsRequest, expertID string, now time.Time) string {
+	if req.StudentID == "" {
+		return "student_id is required"
+	}
+	if req.StudentID == expertID {
+		return "Experts cannot grant credits to themselves"
+	}
+	if req.Quantity < 1 || req.Quantity > MaxCreditGrantQuantity {
+		return fmt.Sprintf("quantity must be between 1 and %d", MaxCreditGrantQuantity)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return "expires_at must be in the future"
+	}
+	return ""
+}
+
+// --- Handlers ---
+
+// GrantCredits handles POST /groups/{groupID}/coaching/credits: the expert
+// grants a student of the group a package of session credits.
+func (h *Handler) GrantCredits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req grantCreditsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if errMsg := validateGrantCredits(req, user.ID, time.Now()); errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	isMember, err := h.q.CheckUserGroup(ctx, db.CheckUserGroupParams{UserID: req.StudentID, GroupID: groupID})
+	if err != nil {
+		log.ErrorContext(ctx, "check_student_membership_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to check group membership", http.StatusInternalServerError)
+		return
+	}
+	if !isMember {
+		http.Error(w, "Student is not a member of this group", http.StatusNotFound)
+		return
+	}
+
+	arg := db.CreateCreditGrantParams{
+		StudentID: req.StudentID,
+		ExpertID:  user.ID,
+		GroupID:   groupID,
+		Quantity:  req.Quantity,
+		GrantedBy: user.ID,
+	}
+	if req.ExpiresAt != nil {
+		arg.ExpiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+	if req.Note != nil {
+		arg.Note = pgtype.Text{String: *req.Note, Valid: true}
+	}
+
+	grant, err := h.grantCredits(ctx, arg)
+	if err != nil {
+		log.ErrorContext(ctx, "grant_credits_failed",
+			slog.String("component", "coaching"),
+			slog.String("student_id", req.StudentID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to grant credits", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "credits_granted",
+		slog.String("component", "coaching"),
+		slog.String("grant_id", uuidToString(grant.ID)),
+		slog.String("student_id", grant.StudentID),
+		slog.Int("quantity", int(grant.Quantity)),
+	)
+	writeJSON(w, http.StatusCreated, toCreditGrantResponse(grant))
+}
+
+// grantCredits stores the grant with its ledger entry and
+// coaching_credit.granted event in one transaction.
+func (h *Handler) grantCredits(ctx context.Context, arg db.CreateCreditGrantParams) (db.CoachingCreditGrant, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingCreditGrant{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	grant, err := db.New(tx).CreateCreditGrant(ctx, arg)
+	if err != nil {
+		return db.CoachingCreditGrant{}, err
+	}
+	if err := h.recordCreditMovementInTx(ctx, tx, db.CoachingCreditEntryKindGrant, nil, grant, pgtype.UUID{}, arg.GrantedBy); err != nil {
+		return db.CoachingCreditGrant{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingCreditGrant{}, err
+	}
+	return grant, nil
+}
+
+// ListCredits handles GET /groups/{groupID}/coaching/credits: the grants the
+// caller holds as student or issued as expert, optionally for one student.
+func (h *Handler) ListCredits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	grants, err := h.q.ListCreditGrants(ctx, db.ListCreditGrantsParams{
+		GroupID:   groupID,
+		UserID:    user.ID,
+		StudentID: r.URL.Query().Get("student_id"),
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_credit_grants_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list credits", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]creditGrantResponse, len(grants))
+	for i, g := range grants {
+		resp[i] = toCreditGrantResponse(g)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ListCreditLedger handles GET /groups/{groupID}/coaching/credits/ledger: the
+// latest credit movements visible to the caller, newest first.
+func (h *Handler) ListCreditLedger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.q.ListCreditLedger(ctx, db.ListCreditLedgerParams{
+		GroupID:   groupID,
+		UserID:    user.ID,
+		StudentID: r.URL.Query().Get("student_id"),
+		PageLimit: CreditLedgerPageSize,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_credit_ledger_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list credit ledger", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]creditLedgerEntryResponse, len(entries))
+	for i, e := range entries {
+		resp[i] = toCreditLedgerEntryResponse(e)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ExpireCredits handles POST /internal/coaching/credits/expire: credits left
+// on grants past their expiry lapse, one ledger entry per grant.
+func (h *Handler) ExpireCredits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	expired, lapsed, err := h.expireCreditGrants(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "expire_credits_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to expire credits", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "credits_expired",
+		slog.String("component", "coaching"),
+		slog.Int("grants", expired),
+		slog.Int("credits", lapsed),
+	)
+	writeJSON(w, http.StatusOK, map[string]int{"grants": expired, "credits": lapsed})
+}
+
+// expireCreditGrants zeroes up to CreditExpiryBatchSize expired grants in one
+// transaction and returns how many grants and credits lapsed.
+func (h *Handler) expireCreditGrants(ctx context.Context) (grants, credits int, err error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	due, err := qtx.ListExpiredCreditGrants(ctx, CreditExpiryBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, g := range due {
+		expired, err := qtx.ExpireCreditGrant(ctx, g.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := h.recordCreditMovementInTx(ctx, tx, db.CoachingCreditEntryKindExpire, &g, expired, pgtype.UUID{}, ""); err != nil {
+			return 0, 0, err
+		}
+		credits += int(g.Remaining)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, err
+	}
+	return len(due), credits, nil
+}
+
+// --- Booking integration ---
+
+// reserveCreditInTx takes one credit for a booking of a credit-only session
+// type and puts its grant on arg; other session types need none. It returns
+// errNoCredit when the student has no usable credit with the expert. Once the
+// booking exists, recordCreditConsumedInTx books the movement.
+func reserveCreditInTx(ctx context.Context, q db.Querier, arg *db.CreateBookingParams, st db.CoachingSessionType) (db.CoachingCreditGrant, error) {
+	if !st.RequiresCredit {
+		return db.CoachingCreditGrant{}, nil
+	}
+	grant, err := q.ConsumeCredit(ctx, db.ConsumeCreditParams{
+		StudentID: arg.StudentID,
+		ExpertID:  arg.ExpertID,
+		GroupID:   arg.GroupID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.CoachingCreditGrant{}, errNoCredit
+	}
+	if err != nil {
+		return db.CoachingCreditGrant{}, err
+	}
+	arg.CreditGrantID = grant.ID
+	return grant, nil
+}
+
+// recordCreditConsumedInTx records the credit reserved for b, if any, as
+// consumed by the student.
+func (h *Handler) recordCreditConsumedInTx(ctx context.Context, tx pgx.Tx, grant db.CoachingCreditGrant, b db.CoachingBooking) error {
+	if !b.CreditGrantID.Valid {
+		return nil
+	}
+	old := grant
+	old.Remaining++
+	return h.recordCreditMovementInTx(ctx, tx, db.CoachingCreditEntryKindConsume, &old, grant, b.ID, b.StudentID)
+}
+
+// restoreCreditInTx gives the credit of a cancelled or missed booking back to
+// its grant. Cancellations within the notice are refused, so every
+// cancellation that happens is eligible; settleAttendance restores a missed
+// session according to the session type's no-show policy. A credit restored
+// to a grant that has meanwhile expired lapses again with the next expiry run.
+func (h *Handler) restoreCreditInTx(ctx context.Context, tx pgx.Tx, b db.CoachingBooking, actorID string) error {
+	if !b.CreditGrantID.Valid {
+		return nil
+	}
+	grant, err := db.New(tx).RestoreCredit(ctx, db.RestoreCreditParams{
+		ID:        b.CreditGrantID,
+		BookingID: b.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// This credit was already returned.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	old := grant
+	old.Remaining--
+	return h.recordCreditMovementInTx(ctx, tx, db.CoachingCreditEntryKindRestore, &old, grant, b.ID, actorID)
+}
+
+// creditActions maps ledger entry kinds to their audit actions.
+var creditActions = map[db.CoachingCreditEntryKind]string{
+	db.CoachingCreditEntryKindGrant:   audit.ActionCoachingCreditGranted,
+	db.CoachingCreditEntryKindConsume: audit.ActionCoachingCreditConsumed,
+	db.CoachingCreditEntryKindRestore: audit.ActionCoachingCreditRestored,
+	db.CoachingCreditEntryKindExpire:  audit.ActionCoachingCreditExpired,
+}
+
+// recordCreditMovementInTx writes the ledger entry for a change of grant from
+// old to updated and its audit event. old is nil for a new grant; an empty
+// actorID marks a system movement.
+func (h *Handler) recordCreditMovementInTx(ctx context.Context, tx pgx.Tx, kind db.CoachingCreditEntryKind, old *db.CoachingCreditGrant, updated db.CoachingCreditGrant, bookingID pgtype.UUID, actorID string) error {
+	delta := updated.Remaining
+	if old != nil {
+		delta -= old.Remaining
+	}
+	if _, err := db.New(tx).CreateCreditLedgerEntry(ctx, db.CreateCreditLedgerEntryParams{
+		GrantID:   updated.ID,
+		StudentID: updated.StudentID,
+		ExpertID:  updated.ExpertID,
+		GroupID:   updated.GroupID,
+		BookingID: bookingID,
+		Kind:      kind,
+		Delta:     delta,
+		ActorID:   optionalText(actorID),
+	}); err != nil {
+		return err
+	}
+	e := audit.Event{
+		Action:       creditActions[kind],
+		ResourceType: audit.ResourceCoachingCredit,
+		ResourceID:   uuidToString(updated.ID),
+		GroupID:      uuidToString(updated.GroupID),
+		NewValues:    audit.CreditGrantSnapshotOf(updated, bookingID),
+	}
+	if old != nil {
+		e.OldValues = audit.CreditGrantSnapshotOf(*old, bookingID)
+	}
+	return h.audit.Record(ctx, tx, e)
+}
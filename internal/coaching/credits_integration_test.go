@@ -0,0 +1,187 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CoachingCredits(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if _, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Priced package", DurationMinutes: 60,
+		PriceCents: 2500, Currency: pgtype.Text{String: "EUR", Valid: true}, RequiresCredit: true,
+	}); err == nil {
+		t.Fatal("priced credit-only session type was accepted")
+	}
+
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	grant := func(quantity int32, expiresAt pgtype.Timestamptz) db.CoachingCreditGrant {
+		t.Helper()
+		g, err := q.CreateCreditGrant(ctx, db.CreateCreditGrantParams{
+			StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID,
+			Quantity: quantity, ExpiresAt: expiresAt, GrantedBy: "expert-1",
+		})
+		if err != nil {
+			t.Fatalf("CreateCreditGrant: %v", err)
+		}
+		if g.Remaining != quantity {
+			t.Fatalf("remaining = %d, want %d", g.Remaining, quantity)
+		}
+		return g
+	}
+	lasting := grant(2, pgtype.Timestamptz{})
+	soon := grant(1, ts(time.Now().Add(24*time.Hour)))
+	lapsed := grant(3, ts(time.Now().Add(-time.Minute)))
+
+	consume := func() (db.CoachingCreditGrant, error) {
+		return q.ConsumeCredit(ctx, db.ConsumeCreditParams{StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID})
+	}
+	// The grant expiring first is used first; expired grants are skipped.
+	for _, want := range []pgtype.UUID{soon.ID, lasting.ID, lasting.ID} {
+		got, err := consume()
+		if err != nil || got.ID != want {
+			t.Fatalf("ConsumeCredit = %v, %v; want grant %v", got.ID, err, want)
+		}
+	}
+	if _, err := consume(); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("ConsumeCredit with no credits left error = %v; want pgx.ErrNoRows", err)
+	}
+
+	restored, err := q.RestoreCredit(ctx, db.RestoreCreditParams{ID: lasting.ID})
+	if err != nil || restored.Remaining != 1 {
+		t.Fatalf("RestoreCredit = %d, %v; want 1 remaining", restored.Remaining, err)
+	}
+	if restored, err = q.RestoreCredit(ctx, db.RestoreCreditParams{ID: lasting.ID}); err != nil || restored.Remaining != 2 {
+		t.Fatalf("RestoreCredit = %d, %v; want 2 remaining", restored.Remaining, err)
+	}
+	if _, err := q.RestoreCredit(ctx, db.RestoreCreditParams{ID: lasting.ID}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("RestoreCredit beyond the quantity error = %v; want pgx.ErrNoRows", err)
+	}
+
+	due, err := q.ListExpiredCreditGrants(ctx, 10)
+	if err != nil || len(due) != 1 || due[0].ID != lapsed.ID {
+		t.Fatalf("ListExpiredCreditGrants = %+v, %v; want only the lapsed grant", due, err)
+	}
+	expired, err := q.ExpireCreditGrant(ctx, lapsed.ID)
+	if err != nil || expired.Remaining != 0 {
+		t.Fatalf("ExpireCreditGrant = %d, %v; want 0 remaining", expired.Remaining, err)
+	}
+	if _, err := q.ExpireCreditGrant(ctx, lapsed.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("second ExpireCreditGrant error = %v; want pgx.ErrNoRows", err)
+	}
+
+	if _, err := q.CreateCreditLedgerEntry(ctx, db.CreateCreditLedgerEntryParams{
+		GrantID: lapsed.ID, StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID,
+		Kind: db.CoachingCreditEntryKindExpire, Delta: -3,
+	}); err != nil {
+		t.Fatalf("CreateCreditLedgerEntry: %v", err)
+	}
+	if _, err := q.CreateCreditLedgerEntry(ctx, db.CreateCreditLedgerEntryParams{
+		GrantID: lapsed.ID, StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID,
+		Kind: db.CoachingCreditEntryKindGrant, Delta: 0,
+	}); err == nil {
+		t.Fatal("ledger entry without a movement was accepted")
+	}
+
+	for _, userID := range []string{"student-1", "expert-1"} {
+		grants, err := q.ListCreditGrants(ctx, db.ListCreditGrantsParams{GroupID: group.ID, UserID: userID})
+		if err != nil || len(grants) != 3 {
+			t.Fatalf("ListCreditGrants(%s) = %d grants, %v; want 3", userID, len(grants), err)
+		}
+		entries, err := q.ListCreditLedger(ctx, db.ListCreditLedgerParams{GroupID: group.ID, UserID: userID, PageLimit: 10})
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("ListCreditLedger(%s) = %d entries, %v; want 1", userID, len(entries), err)
+		}
+	}
+	if grants, err := q.ListCreditGrants(ctx, db.ListCreditGrantsParams{
+		GroupID: group.ID, UserID: "student-2",
+	}); err != nil || len(grants) != 0 {
+		t.Fatalf("ListCreditGrants(student-2) = %d grants, %v; want none", len(grants), err)
+	}
+}
+
+func TestIntegration_CancelledBookingRestoresCreditOnce(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	grant, err := q.CreateCreditGrant(ctx, db.CreateCreditGrantParams{
+		StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID, Quantity: 2, GrantedBy: "expert-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateCreditGrant: %v", err)
+	}
+	consume := func() {
+		t.Helper()
+		if _, err := q.ConsumeCredit(ctx, db.ConsumeCreditParams{StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID}); err != nil {
+			t.Fatalf("ConsumeCredit: %v", err)
+		}
+	}
+	consume()
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt:     pgtype.Timestamptz{Time: time.Now().Add(48 * time.Hour).Truncate(time.Hour), Valid: true},
+		DurationMinutes: 60, CreditGrantID: grant.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	cancel := db.CancelBookingParams{
+		ID: booking.ID, CancelledBy: pgtype.Text{String: "student-1", Valid: true}, ExpertID: "student-1",
+	}
+	if _, err := q.CancelBooking(ctx, cancel); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+	if _, err := q.CancelBooking(ctx, cancel); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("second CancelBooking error = %v; want pgx.ErrNoRows", err)
+	}
+
+	restore := db.RestoreCreditParams{ID: grant.ID, BookingID: booking.ID}
+	restored, err := q.RestoreCredit(ctx, restore)
+	if err != nil || restored.Remaining != 2 {
+		t.Fatalf("RestoreCredit = %d, %v; want 2 remaining", restored.Remaining, err)
+	}
+	entry := db.CreateCreditLedgerEntryParams{
+		GrantID: grant.ID, StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID,
+		BookingID: booking.ID, Kind: db.CoachingCreditEntryKindRestore, Delta: 1,
+	}
+	if _, err := q.CreateCreditLedgerEntry(ctx, entry); err != nil {
+		t.Fatalf("CreateCreditLedgerEntry: %v", err)
+	}
+	// With another credit spent the grant is short again, but this
+	// booking's credit is already back.
+	consume()
+	if _, err := q.RestoreCredit(ctx, restore); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("second RestoreCredit error = %v; want pgx.ErrNoRows", err)
+	}
+	if _, err := q.CreateCreditLedgerEntry(ctx, entry); err == nil {
+		t.Fatal("second restore entry for the booking was accepted")
+	}
+}
@@ -0,0 +1,58 @@
+package coaching
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidateGrantCredits(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		req     grantCreditsRequest
+		wantErr string
+	}{
+		{"valid", grantCreditsRequest{StudentID: "student-1", Quantity: 10, ExpiresAt: &future}, ""},
+		{"never expires", grantCreditsRequest{StudentID: "student-1", Quantity: 1}, ""},
+		{"missing student", grantCreditsRequest{Quantity: 10}, "student_id"},
+		{"to themselves", grantCreditsRequest{StudentID: "expert-1", Quantity: 10}, "Experts cannot grant credits to themselves"},
+		{"zero quantity", grantCreditsRequest{StudentID: "student-1"}, "quantity"},
+		{"quantity over the limit", grantCreditsRequest{StudentID: "student-1", Quantity: MaxCreditGrantQuantity + 1}, "quantity"},
+		{"already expired", grantCreditsRequest{StudentID: "student-1", Quantity: 10, ExpiresAt: &past}, "expires_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateGrantCredits(tt.req, "expert-1", now)
+			if tt.wantErr == "" && got != "" || !strings.HasPrefix(got, tt.wantErr) {
+				t.Fatalf("validateGrantCredits() = %q, want error naming %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGrantCreditsRequiresGroupMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t)
+
+	q.EXPECT().CheckUserGroup(gomock.Any(), db.CheckUserGroupParams{UserID: "outsider", GroupID: b.GroupID}).Return(false, nil)
+
+	rec := httptest.NewRecorder()
+	h.GrantCredits(rec, seriesRequest(t, b.GroupID, `{"student_id":"outsider","quantity":5}`))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
@@ -0,0 +1,115 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxExternalCalendarBytes caps a fetched .ics body; larger files are
+// rejected rather than truncated so a partial calendar never frees busy time.
+const maxExternalCalendarBytes = 5 << 20
+
+var errPrivateCalendarHost = errors.New("calendar host resolves to a private address")
+
+// ExternalCalendarFetcher downloads an expert's subscribed calendar.
+type ExternalCalendarFetcher interface {
+	Fetch(ctx context.Context, rawURL string) ([]byte, error)
+}
+
+type httpCalendarFetcher struct {
+	client *http.Client
+}
+
+// NewHTTPCalendarFetcher fetches calendars over HTTP(S). The URLs are chosen
+// by users, so connections to loopback, private and link-local addresses are
+// refused, including after redirects.
+func NewHTTPCalendarFetcher() ExternalCalendarFetcher {
+	return newHTTPCalendarFetcher(false)
+}
+
+func newHTTPCalendarFetcher(allowPrivate bool) *httpCalendarFetcher {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return errPrivateCalendarHost
+			}
+			return nil
+		}
+	}
+	return &httpCalendarFetcher{client: &http.Client{
+		Timeout: 20 * time.Second,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 15 * time.Second,
+		},
+	}}
+}
+
+func (f *httpCalendarFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	target, err := normalizeCalendarURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar, */*;q=0.5")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errPrivateCalendarHost) {
+			return nil, errPrivateCalendarHost
+		}
+		return nil, fmt.Errorf("could not reach calendar server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("calendar server responded %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxExternalCalendarBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	if len(body) > maxExternalCalendarBytes {
+		return nil, fmt.Errorf("calendar is larger than %d MB", maxExternalCalendarBytes>>20)
+	}
+	return body, nil
+}
+
+// normalizeCalendarURL accepts http, https and webcal URLs. webcal is the
+// subscription scheme calendar apps hand out and is fetched over HTTPS.
+func normalizeCalendarURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", errors.New("invalid calendar URL")
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+	case "webcal", "webcals":
+		u.Scheme = "https"
+	default:
+		return "", errors.New("calendar URL must use http, https or webcal")
+	}
+	return u.String(), nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || ip.IsInterfaceLocalMulticast())
+}
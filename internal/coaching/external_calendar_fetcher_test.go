@@ -0,0 +1,66 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPCalendarFetcherReadsCalendar(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cal.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer srv.Close()
+
+	f := newHTTPCalendarFetcher(true)
+	body, err := f.Fetch(context.Background(), srv.URL+"/cal.ics")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !strings.HasPrefix(string(body), "BEGIN:VCALENDAR") {
+		t.Fatalf("body = %q", body)
+	}
+
+	if _, err := f.Fetch(context.Background(), srv.URL+"/missing.ics"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("missing calendar err = %v; want the status", err)
+	}
+}
+
+func TestHTTPCalendarFetcherRefusesPrivateHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer srv.Close()
+
+	_, err := NewHTTPCalendarFetcher().Fetch(context.Background(), srv.URL)
+	if !errors.Is(err, errPrivateCalendarHost) {
+		t.Fatalf("err = %v; want errPrivateCalendarHost", err)
+	}
+}
+
+func TestNormalizeCalendarURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "https://calendar.example.com/a.ics", want: "https://calendar.example.com/a.ics"},
+		{in: "  webcal://p01-calendars.example.com/published/2/abc ", want: "https://p01-calendars.example.com/published/2/abc"},
+		{in: "ftp://example.com/a.ics", wantErr: true},
+		{in: "not a url", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeCalendarURL(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeCalendarURL(%q) = %q, %v; want %q (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
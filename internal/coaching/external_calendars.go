@@ -0,0 +1,448 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// External calendar limits.
+const (
+	maxExternalCalendarsPerExpert = 10
+	maxExternalCalendarLabel      = 100
+	maxExternalBusyBlocks         = 5000
+	// externalBusyHorizon covers the furthest slot a booking series or a
+	// reschedule can reach, not just the slot lookahead.
+	externalBusyHorizon         = 400 * 24 * time.Hour
+	externalCalendarRefresh     = 30 * time.Minute
+	maxExternalCalendarsPerSync = 50
+	maxExternalCalendarErrorLen = 300
+)
+
+type externalCalendarResponse struct {
+	ID              string     `json:"id"`
+	Label           string     `json:"label"`
+	Source          string     `json:"source"`
+	URL             string     `json:"url,omitempty"`
+	LastAttemptedAt *time.Time `json:"last_attempted_at,omitempty"`
+	LastSyncedAt    *time.Time `json:"last_synced_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func toExternalCalendarResponse(c db.CoachingExternalCalendar) externalCalendarResponse {
+	resp := externalCalendarResponse{
+		ID:        uuidToString(c.ID),
+		Label:     c.Label,
+		Source:    "upload",
+		LastError: c.LastError.String,
+		CreatedAt: c.CreatedAt.Time,
+	}
+	if c.SourceUrl.Valid {
+		resp.Source = "url"
+		resp.URL = c.SourceUrl.String
+	}
+	if c.LastAttemptedAt.Valid {
+		t := c.LastAttemptedAt.Time
+		resp.LastAttemptedAt = &t
+	}
+	if c.LastSyncedAt.Valid {
+		t := c.LastSyncedAt.Time
+		resp.LastSyncedAt = &t
+	}
+	return resp
+}
+
+func (h *Handler) ListExternalCalendars(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	calendars, err := h.q.ListExternalCalendarsByExpert(ctx, user.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "list_external_calendars_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list external calendars", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]externalCalendarResponse, len(calendars))
+	for i, c := range calendars {
+		resp[i] = toExternalCalendarResponse(c)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type createExternalCalendarRequest struct {
+	Label string `json:"label"`
+	// Exactly one of URL (a subscription refreshed periodically) or ICS (the
+	// contents of an uploaded .ics file, imported once) is set.
+	URL string `json:"url,omitempty"`
+	ICS string `json:"ics,omitempty"`
+}
+
+func (h *Handler) CreateExternalCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createExternalCalendarRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExternalCalendarBytes+4096)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Label = strings.TrimSpace(req.Label)
+	if req.Label == "" || utf8.RuneCountInString(req.Label) > maxExternalCalendarLabel {
+		http.Error(w, "label is required and must be at most 100 characters", http.StatusBadRequest)
+		return
+	}
+	if (req.URL == "") == (req.ICS == "") {
+		http.Error(w, "Provide exactly one of url or ics", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.q.ListExternalCalendarsByExpert(ctx, user.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "list_external_calendars_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create external calendar", http.StatusInternalServerError)
+		return
+	}
+	if len(existing) >= maxExternalCalendarsPerExpert {
+		http.Error(w, "At most 10 external calendars are allowed", http.StatusConflict)
+		return
+	}
+
+	if req.ICS != "" {
+		h.importUploadedCalendar(w, r, user.ID, req)
+		return
+	}
+
+	sourceURL, err := normalizeCalendarURL(req.URL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	calendar, err := h.q.CreateExternalCalendar(ctx, db.CreateExternalCalendarParams{
+		ExpertID:  user.ID,
+		Label:     req.Label,
+		SourceUrl: pgtype.Text{String: sourceURL, Valid: true},
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "create_external_calendar_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create external calendar", http.StatusInternalServerError)
+		return
+	}
+
+	// Fetch right away so the availability page shows the outcome. A failed
+	// fetch is recorded on the calendar rather than rejecting it.
+	writeJSON(w, http.StatusCreated, toExternalCalendarResponse(h.refreshExternalCalendar(ctx, calendar)))
+}
+
+func (h *Handler) importUploadedCalendar(w http.ResponseWriter, r *http.Request, expertID string, req createExternalCalendarRequest) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	blocks, err := h.parseExternalBusy(ctx, expertID, []byte(req.ICS))
+	if err != nil {
+		http.Error(w, "Invalid calendar file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.createUploadedCalendar(ctx, expertID, req.Label, blocks)
+	if err != nil {
+		log.ErrorContext(ctx, "import_external_calendar_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create external calendar", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "external_calendar_imported",
+		slog.String("component", "coaching"),
+		slog.String("calendar_id", uuidToString(calendar.ID)),
+		slog.Int("busy_blocks", len(blocks)),
+	)
+	now := time.Now()
+	calendar.LastAttemptedAt = pgtype.Timestamptz{Time: now, Valid: true}
+	calendar.LastSyncedAt = pgtype.Timestamptz{Time: now, Valid: true}
+	writeJSON(w, http.StatusCreated, toExternalCalendarResponse(calendar))
+}
+
+func (h *Handler) DeleteExternalCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	calendarID, err := parseUUID(chi.URLParam(r, "calendarID"))
+	if err != nil {
+		http.Error(w, "Invalid calendar ID", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.q.DeleteExternalCalendar(ctx, db.DeleteExternalCalendarParams{ID: calendarID, ExpertID: user.ID})
+	if err != nil {
+		log.ErrorContext(ctx, "delete_external_calendar_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete external calendar", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "External calendar not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefreshExternalCalendar refetches one subscribed calendar on demand, e.g.
+// after the expert fixed a broken URL on the provider's side.
+func (h *Handler) RefreshExternalCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	calendarID, err := parseUUID(chi.URLParam(r, "calendarID"))
+	if err != nil {
+		http.Error(w, "Invalid calendar ID", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.q.GetExternalCalendar(ctx, db.GetExternalCalendarParams{ID: calendarID, ExpertID: user.ID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "External calendar not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "get_external_calendar_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to refresh external calendar", http.StatusInternalServerError)
+		return
+	}
+	if !calendar.SourceUrl.Valid {
+		http.Error(w, "Uploaded calendars cannot be refreshed; upload the file again", http.StatusConflict)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toExternalCalendarResponse(h.refreshExternalCalendar(ctx, calendar)))
+}
+
+// SyncExternalCalendars refreshes subscribed calendars that are due. Called
+// by Cloud Scheduler.
+func (h *Handler) SyncExternalCalendars(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	calendars, err := h.q.ClaimDueExternalCalendars(ctx, db.ClaimDueExternalCalendarsParams{
+		RefreshSeconds: int32(externalCalendarRefresh / time.Second),
+		LimitCount:     maxExternalCalendarsPerSync,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "claim_external_calendars_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to claim external calendars", http.StatusInternalServerError)
+		return
+	}
+
+	synced, failed := 0, 0
+	for _, c := range calendars {
+		if refreshed := h.refreshExternalCalendar(ctx, c); refreshed.LastError.Valid {
+			failed++
+		} else {
+			synced++
+		}
+	}
+
+	log.InfoContext(ctx, "external_calendars_synced",
+		slog.String("component", "coaching"),
+		slog.Int("total", len(calendars)),
+		slog.Int("synced", synced),
+		slog.Int("failed", failed),
+	)
+	writeJSON(w, http.StatusOK, map[string]int{"processed": len(calendars), "synced": synced, "failed": failed})
+}
+
+// refreshExternalCalendar fetches and stores c's busy time and returns c with
+// its sync state updated. Failures are recorded on the calendar and keep the
+// busy blocks of the last successful fetch.
+func (h *Handler) refreshExternalCalendar(ctx context.Context, c db.CoachingExternalCalendar) db.CoachingExternalCalendar {
+	log := logger.From(ctx, h.logger)
+	now := time.Now()
+	c.LastAttemptedAt = pgtype.Timestamptz{Time: now, Valid: true}
+
+	blocks, err := h.fetchExternalBusy(ctx, c)
+	if err == nil {
+		if err = h.replaceExternalBusy(ctx, c.ID, blocks); err != nil {
+			log.ErrorContext(ctx, "store_external_busy_failed",
+				slog.String("component", "coaching"),
+				slog.String("calendar_id", uuidToString(c.ID)),
+				slog.Any("err", err),
+			)
+			err = errors.New("could not save busy times")
+		}
+	}
+	if err != nil {
+		message := err.Error()
+		if len(message) > maxExternalCalendarErrorLen {
+			message = strings.ToValidUTF8(message[:maxExternalCalendarErrorLen], "")
+		}
+		c.LastError = pgtype.Text{String: message, Valid: true}
+		log.WarnContext(ctx, "external_calendar_sync_failed",
+			slog.String("component", "coaching"),
+			slog.String("calendar_id", uuidToString(c.ID)),
+			slog.String("expert_id", c.ExpertID),
+			slog.String("error", message),
+		)
+		if markErr := h.q.MarkExternalCalendarFailed(ctx, db.MarkExternalCalendarFailedParams{
+			ID: c.ID, LastError: c.LastError,
+		}); markErr != nil {
+			log.ErrorContext(ctx, "mark_external_calendar_failed_failed",
+				slog.String("component", "coaching"),
+				slog.String("calendar_id", uuidToString(c.ID)),
+				slog.Any("err", markErr),
+			)
+		}
+		return c
+	}
+
+	c.LastError = pgtype.Text{}
+	c.LastSyncedAt = pgtype.Timestamptz{Time: now, Valid: true}
+	log.InfoContext(ctx, "external_calendar_synced",
+		slog.String("component", "coaching"),
+		slog.String("calendar_id", uuidToString(c.ID)),
+		slog.Int("busy_blocks", len(blocks)),
+	)
+	return c
+}
+
+func (h *Handler) fetchExternalBusy(ctx context.Context, c db.CoachingExternalCalendar) ([]busyInterval, error) {
+	data, err := h.calendarFetcher.Fetch(ctx, c.SourceUrl.String)
+	if err != nil {
+		return nil, err
+	}
+	return h.parseExternalBusy(ctx, c.ExpertID, data)
+}
+
+// parseExternalBusy reads busy time in the expert's timezone for the window
+// slot computation can ask about.
+func (h *Handler) parseExternalBusy(ctx context.Context, expertID string, data []byte) ([]busyInterval, error) {
+	loc, err := userLocation(ctx, h.q, expertID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return parseICSBusy(data, loc, now.Add(-24*time.Hour), now.Add(externalBusyHorizon), maxExternalBusyBlocks)
+}
+
+func (h *Handler) createUploadedCalendar(ctx context.Context, expertID, label string, blocks []busyInterval) (db.CoachingExternalCalendar, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingExternalCalendar{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+	calendar, err := qtx.CreateExternalCalendar(ctx, db.CreateExternalCalendarParams{ExpertID: expertID, Label: label})
+	if err != nil {
+		return db.CoachingExternalCalendar{}, err
+	}
+	if err := storeExternalBusy(ctx, qtx, calendar.ID, blocks); err != nil {
+		return db.CoachingExternalCalendar{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingExternalCalendar{}, err
+	}
+	return calendar, nil
+}
+
+func (h *Handler) replaceExternalBusy(ctx context.Context, calendarID pgtype.UUID, blocks []busyInterval) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	if err := storeExternalBusy(ctx, db.New(tx), calendarID, blocks); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// storeExternalBusy replaces the cached busy blocks of a calendar and marks it
+// synced. q should be bound to a transaction.
+func storeExternalBusy(ctx context.Context, q db.Querier, calendarID pgtype.UUID, blocks []busyInterval) error {
+	if err := q.DeleteExternalBusyBlocks(ctx, calendarID); err != nil {
+		return err
+	}
+	if len(blocks) > 0 {
+		starts := make([]pgtype.Timestamptz, len(blocks))
+		ends := make([]pgtype.Timestamptz, len(blocks))
+		for i, b := range blocks {
+			starts[i] = pgtype.Timestamptz{Time: b.Start, Valid: true}
+			ends[i] = pgtype.Timestamptz{Time: b.End, Valid: true}
+		}
+		if err := q.InsertExternalBusyBlocks(ctx, db.InsertExternalBusyBlocksParams{
+			CalendarID: calendarID, StartsAt: starts, EndsAt: ends,
+		}); err != nil {
+			return err
+		}
+	}
+	return q.MarkExternalCalendarSynced(ctx, calendarID)
+}
+
+// listExternalBusy loads an expert's cached external busy time overlapping
+// [from, to).
+func listExternalBusy(ctx context.Context, q db.Querier, expertID string, from, to time.Time) ([]busyInterval, error) {
+	rows, err := q.ListExternalBusyBlocks(ctx, db.ListExternalBusyBlocksParams{
+		ExpertID: expertID,
+		FromAt:   pgtype.Timestamptz{Time: from, Valid: true},
+		ToAt:     pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	busy := make([]busyInterval, len(rows))
+	for i, r := range rows {
+		busy[i] = busyInterval{Start: r.StartsAt.Time, End: r.EndsAt.Time}
+	}
+	return busy, nil
+}
@@ -0,0 +1,105 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_ExternalBusyBlocksLifecycle(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	subscribed, err := q.CreateExternalCalendar(ctx, db.CreateExternalCalendarParams{
+		ExpertID: "expert-1", Label: "Work",
+		SourceUrl: pgtype.Text{String: "https://calendar.example.com/work.ics", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateExternalCalendar: %v", err)
+	}
+	uploaded, err := q.CreateExternalCalendar(ctx, db.CreateExternalCalendarParams{ExpertID: "expert-1", Label: "Export"})
+	if err != nil {
+		t.Fatalf("CreateExternalCalendar upload: %v", err)
+	}
+
+	base := time.Now().Truncate(time.Hour).Add(24 * time.Hour)
+	ts := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: base.Add(d), Valid: true} }
+	if err := q.InsertExternalBusyBlocks(ctx, db.InsertExternalBusyBlocksParams{
+		CalendarID: subscribed.ID,
+		StartsAt:   []pgtype.Timestamptz{ts(0), ts(48 * time.Hour)},
+		EndsAt:     []pgtype.Timestamptz{ts(time.Hour), ts(49 * time.Hour)},
+	}); err != nil {
+		t.Fatalf("InsertExternalBusyBlocks: %v", err)
+	}
+	if err := q.InsertExternalBusyBlocks(ctx, db.InsertExternalBusyBlocksParams{
+		CalendarID: uploaded.ID,
+		StartsAt:   []pgtype.Timestamptz{ts(2 * time.Hour)},
+		EndsAt:     []pgtype.Timestamptz{ts(3 * time.Hour)},
+	}); err != nil {
+		t.Fatalf("InsertExternalBusyBlocks upload: %v", err)
+	}
+
+	rows, err := q.ListExternalBusyBlocks(ctx, db.ListExternalBusyBlocksParams{
+		ExpertID: "expert-1", FromAt: ts(30 * time.Minute), ToAt: ts(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("ListExternalBusyBlocks: %v", err)
+	}
+	if len(rows) != 2 || !rows[0].StartsAt.Time.Equal(base) || !rows[1].StartsAt.Time.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("ListExternalBusyBlocks = %+v; want the two blocks overlapping the first day", rows)
+	}
+
+	// Only subscriptions are claimed, and a claimed calendar is not due again.
+	claimed, err := q.ClaimDueExternalCalendars(ctx, db.ClaimDueExternalCalendarsParams{RefreshSeconds: 1800, LimitCount: 10})
+	if err != nil {
+		t.Fatalf("ClaimDueExternalCalendars: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != subscribed.ID || !claimed[0].LastAttemptedAt.Valid {
+		t.Fatalf("claimed = %+v; want the subscribed calendar", claimed)
+	}
+	again, err := q.ClaimDueExternalCalendars(ctx, db.ClaimDueExternalCalendarsParams{RefreshSeconds: 1800, LimitCount: 10})
+	if err != nil || len(again) != 0 {
+		t.Fatalf("second claim = %+v, %v; want none", again, err)
+	}
+
+	if err := q.MarkExternalCalendarFailed(ctx, db.MarkExternalCalendarFailedParams{
+		ID: subscribed.ID, LastError: pgtype.Text{String: "calendar server responded 404 Not Found", Valid: true},
+	}); err != nil {
+		t.Fatalf("MarkExternalCalendarFailed: %v", err)
+	}
+	failed, err := q.GetExternalCalendar(ctx, db.GetExternalCalendarParams{ID: subscribed.ID, ExpertID: "expert-1"})
+	if err != nil || failed.LastError.String == "" || failed.LastSyncedAt.Valid {
+		t.Fatalf("GetExternalCalendar = %+v, %v; want the error recorded", failed, err)
+	}
+	if err := q.MarkExternalCalendarSynced(ctx, subscribed.ID); err != nil {
+		t.Fatalf("MarkExternalCalendarSynced: %v", err)
+	}
+	synced, err := q.GetExternalCalendar(ctx, db.GetExternalCalendarParams{ID: subscribed.ID, ExpertID: "expert-1"})
+	if err != nil || synced.LastError.Valid || !synced.LastSyncedAt.Valid {
+		t.Fatalf("GetExternalCalendar = %+v, %v; want the error cleared", synced, err)
+	}
+
+	// Deleting a calendar drops its busy blocks; another expert cannot.
+	if n, err := q.DeleteExternalCalendar(ctx, db.DeleteExternalCalendarParams{ID: subscribed.ID, ExpertID: "expert-2"}); err != nil || n != 0 {
+		t.Fatalf("DeleteExternalCalendar by other expert = %d, %v; want 0", n, err)
+	}
+	if n, err := q.DeleteExternalCalendar(ctx, db.DeleteExternalCalendarParams{ID: subscribed.ID, ExpertID: "expert-1"}); err != nil || n != 1 {
+		t.Fatalf("DeleteExternalCalendar = %d, %v; want 1", n, err)
+	}
+	rows, err = q.ListExternalBusyBlocks(ctx, db.ListExternalBusyBlocksParams{
+		ExpertID: "expert-1", FromAt: ts(-time.Hour), ToAt: ts(72 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("ListExternalBusyBlocks: %v", err)
+	}
+	if len(rows) != 1 || !rows[0].StartsAt.Time.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("ListExternalBusyBlocks after delete = %+v; want only the uploaded block", rows)
+	}
+}
@@ -0,0 +1,149 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const externalCalendarTestID = "7b0d6c2e-2f4a-4c41-9e0a-3f1d7c5b9a10"
+
+type fakeCalendarFetcher struct {
+	body []byte
+	err  error
+	urls []string
+}
+
+func (f *fakeCalendarFetcher) Fetch(_ context.Context, rawURL string) ([]byte, error) {
+	f.urls = append(f.urls, rawURL)
+	return f.body, f.err
+}
+
+func externalCalendarRequest(method, body string) *http.Request {
+	req := httptest.NewRequest(method, "/groups/g/coaching/external-calendars", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), auth.UserKey, &auth.UserContext{ID: "expert-1"})
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("calendarID", externalCalendarTestID)
+	return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+func TestCreateExternalCalendarRecordsFetchFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	fetcher := &fakeCalendarFetcher{err: errors.New("calendar server responded 401 Unauthorized")}
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{CalendarFetcher: fetcher})
+	calendarID, _ := parseUUID(externalCalendarTestID)
+
+	q.EXPECT().ListExternalCalendarsByExpert(gomock.Any(), "expert-1").Return(nil, nil)
+	q.EXPECT().CreateExternalCalendar(gomock.Any(), db.CreateExternalCalendarParams{
+		ExpertID:  "expert-1",
+		Label:     "Work",
+		SourceUrl: pgtype.Text{String: "https://calendar.example.com/work.ics", Valid: true},
+	}).Return(db.CoachingExternalCalendar{
+		ID: calendarID, ExpertID: "expert-1", Label: "Work",
+		SourceUrl: pgtype.Text{String: "https://calendar.example.com/work.ics", Valid: true},
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}, nil)
+	q.EXPECT().MarkExternalCalendarFailed(gomock.Any(), db.MarkExternalCalendarFailedParams{
+		ID:        calendarID,
+		LastError: pgtype.Text{String: "calendar server responded 401 Unauthorized", Valid: true},
+	}).Return(nil)
+
+	rec := httptest.NewRecorder()
+	h.CreateExternalCalendar(rec, externalCalendarRequest(http.MethodPost,
+		`{"label":" Work ","url":"webcal://calendar.example.com/work.ics"}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	var resp externalCalendarResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Source != "url" || resp.LastError == "" || resp.LastAttemptedAt == nil || resp.LastSyncedAt != nil {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(fetcher.urls) != 1 || fetcher.urls[0] != "https://calendar.example.com/work.ics" {
+		t.Fatalf("fetched %v", fetcher.urls)
+	}
+}
+
+func TestCreateExternalCalendarValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		existing int
+		want     int
+	}{
+		{"missing label", `{"url":"https://example.com/a.ics"}`, -1, http.StatusBadRequest},
+		{"both sources", `{"label":"Work","url":"https://example.com/a.ics","ics":"BEGIN:VCALENDAR"}`, -1, http.StatusBadRequest},
+		{"no source", `{"label":"Work"}`, -1, http.StatusBadRequest},
+		{"unsupported scheme", `{"label":"Work","url":"file:///etc/passwd"}`, 0, http.StatusBadRequest},
+		{"too many calendars", `{"label":"Work","url":"https://example.com/a.ics"}`, maxExternalCalendarsPerExpert, http.StatusConflict},
+		{"upload is not a calendar", `{"label":"Work","ics":"hello"}`, 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{CalendarFetcher: &fakeCalendarFetcher{}})
+			if tt.existing >= 0 {
+				q.EXPECT().ListExternalCalendarsByExpert(gomock.Any(), "expert-1").
+					Return(make([]db.CoachingExternalCalendar, tt.existing), nil)
+			}
+			q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("UTC", nil).AnyTimes()
+
+			rec := httptest.NewRecorder()
+			h.CreateExternalCalendar(rec, externalCalendarRequest(http.MethodPost, tt.body))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d; want %d (body %s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRefreshUploadedExternalCalendarConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{CalendarFetcher: &fakeCalendarFetcher{}})
+	calendarID, _ := parseUUID(externalCalendarTestID)
+
+	q.EXPECT().GetExternalCalendar(gomock.Any(), db.GetExternalCalendarParams{ID: calendarID, ExpertID: "expert-1"}).
+		Return(db.CoachingExternalCalendar{ID: calendarID, ExpertID: "expert-1", Label: "Exported"}, nil)
+
+	rec := httptest.NewRecorder()
+	h.RefreshExternalCalendar(rec, externalCalendarRequest(http.MethodPost, ""))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d; want 409", rec.Code)
+	}
+}
+
+func TestDeleteExternalCalendarNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{CalendarFetcher: &fakeCalendarFetcher{}})
+	calendarID, _ := parseUUID(externalCalendarTestID)
+
+	q.EXPECT().DeleteExternalCalendar(gomock.Any(), db.DeleteExternalCalendarParams{ID: calendarID, ExpertID: "expert-1"}).
+		Return(int64(0), nil)
+
+	rec := httptest.NewRecorder()
+	h.DeleteExternalCalendar(rec, externalCalendarRequest(http.MethodDelete, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d; want 404", rec.Code)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/email"
+	"github.com/OZIOisgood/zeta/internal/notifications"
 	"github.com/OZIOisgood/zeta/internal/permissions"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,14 +18,28 @@ import (
 const (
 	SlotLookaheadDays          = 28
 	BlockedSlotRangeMonths     = 3
+	AbsenceRangeMonths         = 18
 	DefaultMinSessionDuration  = int32(15)
 	MaxSessionDuration         = int32(120)
 	DefaultSessionDurationStep = int32(5)
+	MaxBufferMinutes           = int32(120)
+	MaxSessionsPerDayLimit     = int32(48)
+	MaxBookingHorizonDays      = int32(365)
+	MaxNoticeMinutes           = int32(30 * 24 * 60)
+	MaxWaitlistWindows         = 5
+	WaitlistOfferBatchSize     = int32(50)
+	MaxPriceCents              = int32(1000000)
+	PaymentBatchSize           = int32(50)
+	MaxCreditGrantQuantity     = int32(100)
+	CreditLedgerPageSize       = int32(200)
+	CreditExpiryBatchSize      = int32(100)
+	MaxClassCapacity           = int32(50)
 )
 
 type Handler struct {
 	q                    db.Querier
 	pool                 *pgxpool.Pool
+	audit                *audit.Recorder
 	logger               *slog.Logger
 	emailService         email.Sender
 	workos               auth.UserManagement
@@ -33,13 +49,20 @@ type Handler struct {
 	recordingClient      RecordingClient
 	recordingStore       RecordingObjectStore
 	recordingMux         RecordingMuxClient
+	recordingStitcher    RecordingStitcher
+	calendarFetcher      ExternalCalendarFetcher
+	paymentProvider      PaymentProvider
+	chatHub              *notifications.Hub
 	recordingEmptyGrace  time.Duration
 	recordingPresenceTTL time.Duration
 	recordingEndGrace    time.Duration
 	appBaseURL           string
+	apiBaseURL           string
 	minBookingNotice     time.Duration
 	cancellationNotice   time.Duration
 	connectWindow        time.Duration
+	waitlistHoldTTL      time.Duration
+	paymentHoldTTL       time.Duration
 	minSessionDuration   int32
 	sessionDurationStep  int32
 }
@@ -52,13 +75,20 @@ type HandlerConfig struct {
 	RecordingClient      RecordingClient
 	RecordingStore       RecordingObjectStore
 	RecordingMux         RecordingMuxClient
+	RecordingStitcher    RecordingStitcher       // nil: the parts of a recording stay separate videos
+	CalendarFetcher      ExternalCalendarFetcher // default: HTTP fetcher that refuses private addresses
+	PaymentProvider      PaymentProvider         // nil: paid session types cannot be offered
+	ChatHub              *notifications.Hub      // default: a hub of its own; share it with the chat listener
 	RecordingEmptyGrace  time.Duration
 	RecordingPresenceTTL time.Duration
 	RecordingEndGrace    time.Duration
 	AppBaseURL           string        // base URL of the frontend app (e.g. https://app.example.com)
+	APIBaseURL           string        // public base URL of this API; calendar feed URLs point here
 	MinBookingNotice     time.Duration // default: 2h
 	CancellationNotice   time.Duration // default: 1h
 	ConnectWindow        time.Duration // default: 15m — how early before a session participants may join
+	WaitlistHoldTTL      time.Duration // default: 2h — how long a freed slot is held for a waitlisted student
+	PaymentHoldTTL       time.Duration // default: 35m — how long a paid booking holds its slot awaiting payment; raised to the provider's minimum checkout lifetime
 	MinSessionDuration   int32         // default: 15 minutes; dev may lower this for smoke tests
 	SessionDurationStep  int32         // default: 5 minutes; dev may use one-minute increments
 }
@@ -73,6 +103,28 @@ func NewHandler(q db.Querier, pool *pgxpool.Pool, emailService email.Sender, wor
 	if cfg.RecordingEndGrace <= 0 {
 		cfg.RecordingEndGrace = 15 * time.Minute
 	}
+	if cfg.CalendarFetcher == nil {
+		cfg.CalendarFetcher = NewHTTPCalendarFetcher()
+	}
+	if cfg.ChatHub == nil {
+		cfg.ChatHub = notifications.NewHub()
+	}
+	if cfg.WaitlistHoldTTL <= 0 {
+		cfg.WaitlistHoldTTL = 2 * time.Hour
+	}
+	if cfg.PaymentHoldTTL <= 0 {
+		cfg.PaymentHoldTTL = 35 * time.Minute
+	}
+	if cfg.PaymentProvider != nil && cfg.PaymentHoldTTL < cfg.PaymentProvider.MinCheckoutLifetime() {
+		// The checkout outlives a shorter hold: the student could pay after
+		// the slot went to someone else.
+		logger.Warn("payment_hold_ttl_raised",
+			slog.String("component", "coaching"),
+			slog.Duration("configured", cfg.PaymentHoldTTL),
+			slog.Duration("min_checkout_lifetime", cfg.PaymentProvider.MinCheckoutLifetime()),
+		)
+		cfg.PaymentHoldTTL = cfg.PaymentProvider.MinCheckoutLifetime()
+	}
 	if cfg.MinSessionDuration <= 0 {
 		cfg.MinSessionDuration = DefaultMinSessionDuration
 	}
@@ -82,6 +134,7 @@ func NewHandler(q db.Querier, pool *pgxpool.Pool, emailService email.Sender, wor
 	return &Handler{
 		q:                    q,
 		pool:                 pool,
+		audit:                audit.NewRecorder(),
 		logger:               logger,
 		emailService:         emailService,
 		workos:               workos,
@@ -91,13 +144,20 @@ func NewHandler(q db.Querier, pool *pgxpool.Pool, emailService email.Sender, wor
 		recordingClient:      cfg.RecordingClient,
 		recordingStore:       cfg.RecordingStore,
 		recordingMux:         cfg.RecordingMux,
+		recordingStitcher:    cfg.RecordingStitcher,
+		calendarFetcher:      cfg.CalendarFetcher,
+		paymentProvider:      cfg.PaymentProvider,
+		chatHub:              cfg.ChatHub,
 		recordingEmptyGrace:  cfg.RecordingEmptyGrace,
 		recordingPresenceTTL: cfg.RecordingPresenceTTL,
 		recordingEndGrace:    cfg.RecordingEndGrace,
 		appBaseURL:           cfg.AppBaseURL,
+		apiBaseURL:           cfg.APIBaseURL,
 		minBookingNotice:     cfg.MinBookingNotice,
 		cancellationNotice:   cfg.CancellationNotice,
 		connectWindow:        cfg.ConnectWindow,
+		waitlistHoldTTL:      cfg.WaitlistHoldTTL,
+		paymentHoldTTL:       cfg.PaymentHoldTTL,
 		minSessionDuration:   cfg.MinSessionDuration,
 		sessionDurationStep:  cfg.SessionDurationStep,
 	}
@@ -108,6 +168,10 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Group(func(r chi.Router) {
 		r.Use(auth.RequirePermission(permissions.CoachingBookingsRead))
 		r.Get("/coaching/bookings", h.ListAllMyBookings)
+		// Personal iCalendar feed — the URL is a secret, rotate to revoke it
+		r.Get("/coaching/calendar-feed", h.GetCalendarFeed)
+		r.Post("/coaching/calendar-feed", h.RotateCalendarFeed)
+		r.Delete("/coaching/calendar-feed", h.RevokeCalendarFeed)
 	})
 
 	r.Route("/groups/{groupID}/coaching", func(r chi.Router) {
@@ -139,6 +203,27 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Delete("/blocked-slots/{slotID}", h.DeleteBlockedSlot)
 		})
 
+		// Dated extra hours and absences — experts only
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingAvailabilityManage))
+			r.Get("/availability-overrides", h.ListAvailabilityOverrides)
+			r.Post("/availability-overrides", h.CreateAvailabilityOverride)
+			r.Delete("/availability-overrides/{overrideID}", h.DeleteAvailabilityOverride)
+			r.Get("/absences", h.ListAbsences)
+			r.Get("/absences/conflicts", h.ListAbsenceConflicts)
+			r.Post("/absences", h.CreateAbsence)
+			r.Delete("/absences/{absenceID}", h.DeleteAbsence)
+		})
+
+		// External calendars — experts only; busy time is kept out of slots
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingAvailabilityManage))
+			r.Get("/external-calendars", h.ListExternalCalendars)
+			r.Post("/external-calendars", h.CreateExternalCalendar)
+			r.Post("/external-calendars/{calendarID}/refresh", h.RefreshExternalCalendar)
+			r.Delete("/external-calendars/{calendarID}", h.DeleteExternalCalendar)
+		})
+
 		// Slot computation + experts listing — students/readers
 		r.Group(func(r chi.Router) {
 			r.Use(auth.RequirePermission(permissions.CoachingSlotsRead))
@@ -150,14 +235,68 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Group(func(r chi.Router) {
 			r.Use(auth.RequirePermission(permissions.CoachingBook))
 			r.Post("/bookings", h.CreateBooking)
+			r.Post("/booking-series", h.CreateBookingSeries)
+		})
+
+		// Waitlist — students wait for freed slots and confirm holds
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingBook))
+			r.Get("/waitlist", h.ListMyWaitlistEntries)
+			r.Post("/waitlist", h.JoinWaitlist)
+			r.Delete("/waitlist/{entryID}", h.LeaveWaitlist)
+			r.Put("/waitlist/{entryID}/hold/confirm", h.ConfirmWaitlistHold)
+			r.Put("/waitlist/{entryID}/hold/decline", h.DeclineWaitlistHold)
 		})
 		r.Group(func(r chi.Router) {
 			r.Use(auth.RequirePermission(permissions.CoachingBookingsRead))
 			r.Get("/bookings", h.ListMyBookings)
 			r.Get("/sessions", h.ListGroupSessions)
 		})
+
+		// Session credits — experts grant; students and experts see their balances
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingAvailabilityManage))
+			r.Post("/credits", h.GrantCredits)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingBookingsRead))
+			r.Get("/credits", h.ListCredits)
+			r.Get("/credits/ledger", h.ListCreditLedger)
+		})
+		// Session follow-up — the expert writes notes and assigns homework,
+		// the student ticks homework off; both read the session summary
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingAvailabilityManage))
+			r.Put("/bookings/{bookingID}/notes", h.PutSessionNotes)
+			r.Post("/bookings/{bookingID}/homework", h.CreateHomework)
+			r.Put("/homework/{homeworkID}", h.UpdateHomework)
+			r.Delete("/homework/{homeworkID}", h.DeleteHomework)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingBookingsRead))
+			r.Get("/bookings/{bookingID}/summary", h.GetSessionSummary)
+			r.Get("/homework", h.ListHomework)
+			r.Put("/homework/{homeworkID}/complete", h.CompleteHomework)
+			r.Put("/homework/{homeworkID}/reopen", h.ReopenHomework)
+		})
+		// Booking chat — participants of the call, before, during and after it
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingBookingsRead))
+			r.Get("/bookings/{bookingID}/messages", h.ListBookingMessages)
+			r.Post("/bookings/{bookingID}/messages", h.PostBookingMessage)
+			r.Get("/bookings/{bookingID}/messages/stream", h.StreamBookingMessages)
+		})
+		// Recording status — participants of the call follow its import
+		r.Group(func(r chi.Router) {
+			r.Use(auth.RequirePermission(permissions.CoachingBookingsRead))
+			r.Get("/bookings/{bookingID}/recording", h.GetBookingRecording)
+		})
 		// CancelBooking — fine-grained auth handled inside the handler
 		r.Put("/bookings/{bookingID}/cancel", h.CancelBooking)
+		// Rescheduling — either participant proposes, the other accepts or declines
+		r.Put("/bookings/{bookingID}/reschedule", h.ProposeReschedule)
+		r.Put("/bookings/{bookingID}/reschedule/accept", h.AcceptReschedule)
+		r.Put("/bookings/{bookingID}/reschedule/decline", h.DeclineReschedule)
 
 		// Video connect — generate Agora RTC token
 		r.Group(func(r chi.Router) {
@@ -1,9 +1,13 @@
 package coaching
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/OZIOisgood/zeta/internal/pgutil"
@@ -11,6 +15,29 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// --- Secret helpers ---
+
+// newHashedSecret returns a random URL-safe token and its SHA-256 digest. Only
+// the digest is stored; the plaintext is handed out once.
+func newHashedSecret() (plaintext string, tokenHash []byte, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	plaintext = base64.RawURLEncoding.EncodeToString(raw)
+	digest := sha256.Sum256([]byte(plaintext))
+	return plaintext, digest[:], nil
+}
+
+// bookingCallURL is the frontend page that joins the booking's video call.
+// Empty when no app base URL is configured.
+func (h *Handler) bookingCallURL(groupID, bookingID pgtype.UUID) string {
+	if h.appBaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(h.appBaseURL, "/") + "/sessions/" + uuidToString(groupID) + "/" + uuidToString(bookingID) + "/call"
+}
+
 // --- UUID helpers ---
 
 func uuidToString(u pgtype.UUID) string {
@@ -0,0 +1,703 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Limits on what an expert can write up after a session.
+const (
+	MaxSessionNotesLength        = 10000
+	MaxHomeworkTitleLength       = 200
+	MaxHomeworkDescriptionLength = 5000
+	MaxHomeworkPerBooking        = 20
+	HomeworkPageSize             = int32(200)
+)
+
+// homeworkReminderOffsets are how long before the due date the student is
+// reminded; offsets already in the past are skipped.
+var homeworkReminderOffsets = []time.Duration{24 * time.Hour, time.Hour}
+
+// --- DTO ---
+
+type sessionNotesResponse struct {
+	BookingID string    `json:"booking_id"`
+	AuthorID  string    `json:"author_id"`
+	Summary   string    `json:"summary"`
+	WentWell  string    `json:"went_well"`
+	ToImprove string    `json:"to_improve"`
+	NextFocus string    `json:"next_focus"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toSessionNotesResponse(n db.CoachingSessionNote) sessionNotesResponse {
+	return sessionNotesResponse{
+		BookingID: uuidToString(n.BookingID),
+		AuthorID:  n.AuthorID,
+		Summary:   n.Summary,
+		WentWell:  n.WentWell,
+		ToImprove: n.ToImprove,
+		NextFocus: n.NextFocus,
+		CreatedAt: n.CreatedAt.Time,
+		UpdatedAt: n.UpdatedAt.Time,
+	}
+}
+
+type homeworkResponse struct {
+	ID                 string     `json:"id"`
+	BookingID          string     `json:"booking_id"`
+	StudentID          string     `json:"student_id"`
+	ExpertID           string     `json:"expert_id"`
+	GroupID            string     `json:"group_id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	DueAt              *time.Time `json:"due_at,omitempty"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	SessionScheduledAt *time.Time `json:"session_scheduled_at,omitempty"`
+	RecordingAssetID   *string    `json:"recording_asset_id,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func toHomeworkResponse(hw db.CoachingHomework) homeworkResponse {
+	resp := homeworkResponse{
+		ID:          uuidToString(hw.ID),
+		BookingID:   uuidToString(hw.BookingID),
+		StudentID:   hw.StudentID,
+		ExpertID:    hw.ExpertID,
+		GroupID:     uuidToString(hw.GroupID),
+		Title:       hw.Title,
+		Description: hw.Description,
+		CreatedAt:   hw.CreatedAt.Time,
+		UpdatedAt:   hw.UpdatedAt.Time,
+	}
+	if hw.DueAt.Valid {
+		resp.DueAt = &hw.DueAt.Time
+	}
+	if hw.CompletedAt.Valid {
+		resp.CompletedAt = &hw.CompletedAt.Time
+	}
+	return resp
+}
+
+func homeworkRowToResponse(row db.ListHomeworkRow) homeworkResponse {
+	resp := toHomeworkResponse(db.CoachingHomework{
+		ID:          row.ID,
+		BookingID:   row.BookingID,
+		StudentID:   row.StudentID,
+		ExpertID:    row.ExpertID,
+		GroupID:     row.GroupID,
+		Title:       row.Title,
+		Description: row.Description,
+		DueAt:       row.DueAt,
+		CompletedAt: row.CompletedAt,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	})
+	resp.SessionScheduledAt = &row.SessionScheduledAt.Time
+	if row.RecordingAssetID.Valid {
+		id := uuidToString(row.RecordingAssetID)
+		resp.RecordingAssetID = &id
+	}
+	return resp
+}
+
+// sessionSummaryResponse is everything that came out of one session: the
+// expert's notes (nil until written), the homework and the recording.
+type sessionSummaryResponse struct {
+	BookingID        string                `json:"booking_id"`
+	ScheduledAt      time.Time             `json:"scheduled_at"`
+	Notes            *sessionNotesResponse `json:"notes"`
+	Homework         []homeworkResponse    `json:"homework"`
+	RecordingAssetID *string               `json:"recording_asset_id,omitempty"`
+}
+
+type sessionNotesRequest struct {
+	Summary   string `json:"summary"`
+	WentWell  string `json:"went_well"`
+	ToImprove string `json:"to_improve"`
+	NextFocus string `json:"next_focus"`
+}
+
+// validate trims the fields in place and returns a client-facing message, or
+// "" when the notes are acceptable.
+func (req *sessionNotesRequest) validate() string {
+	req.Summary = strings.TrimSpace(req.Summary)
+	req.WentWell = strings.TrimSpace(req.WentWell)
+	req.ToImprove = strings.TrimSpace(req.ToImprove)
+	req.NextFocus = strings.TrimSpace(req.NextFocus)
+	if req.Summary == "" {
+		return "summary is required"
+	}
+	for _, field := range []string{req.Summary, req.WentWell, req.ToImprove, req.NextFocus} {
+		if len(field) > MaxSessionNotesLength {
+			return "each notes field must be at most 10000 characters"
+		}
+	}
+	return ""
+}
+
+type homeworkRequest struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	DueAt       *string `json:"due_at,omitempty"` // RFC3339
+}
+
+// parse validates the request and returns the due date, which is unset when
+// the homework has none. A due date must lie in the future unless it equals
+// keepDueAt, so an edit may leave an overdue date untouched.
+func (req *homeworkRequest) parse(now time.Time, keepDueAt pgtype.Timestamptz) (pgtype.Timestamptz, string) {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Title == "" || len(req.Title) > MaxHomeworkTitleLength {
+		return pgtype.Timestamptz{}, "title must be between 1 and 200 characters"
+	}
+	if len(req.Description) > MaxHomeworkDescriptionLength {
+		return pgtype.Timestamptz{}, "description must be at most 5000 characters"
+	}
+	if req.DueAt == nil || *req.DueAt == "" {
+		return pgtype.Timestamptz{}, ""
+	}
+	dueAt, err := time.Parse(time.RFC3339, *req.DueAt)
+	if err != nil {
+		return pgtype.Timestamptz{}, "Invalid due_at (use RFC3339)"
+	}
+	if keepDueAt.Valid && dueAt.Equal(keepDueAt.Time) {
+		return keepDueAt, ""
+	}
+	if !dueAt.After(now) {
+		return pgtype.Timestamptz{}, "due_at must be in the future"
+	}
+	return pgtype.Timestamptz{Time: dueAt, Valid: true}, ""
+}
+
+// --- Handlers ---
+
+// PutSessionNotes handles PUT /groups/{groupID}/coaching/bookings/{bookingID}/notes.
+// The booking's expert writes or rewrites the notes once the session has
+// started; the student is notified the first time they are shared.
+func (h *Handler) PutSessionNotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req sessionNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	b, ok := h.loadFollowUpBooking(ctx, w, r, user.ID, true)
+	if !ok {
+		return
+	}
+
+	_, err := h.q.GetSessionNotes(ctx, b.ID)
+	firstShare := errors.Is(err, pgx.ErrNoRows)
+	if err != nil && !firstShare {
+		log.ErrorContext(ctx, "get_session_notes_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to save notes", http.StatusInternalServerError)
+		return
+	}
+
+	notes, err := h.q.UpsertSessionNotes(ctx, db.UpsertSessionNotesParams{
+		BookingID: b.ID,
+		AuthorID:  user.ID,
+		Summary:   req.Summary,
+		WentWell:  req.WentWell,
+		ToImprove: req.ToImprove,
+		NextFocus: req.NextFocus,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "upsert_session_notes_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to save notes", http.StatusInternalServerError)
+		return
+	}
+
+	if firstShare {
+		h.notifySessionNotesShared(ctx, b)
+	}
+
+	writeJSON(w, http.StatusOK, toSessionNotesResponse(notes))
+}
+
+// CreateHomework handles POST /groups/{groupID}/coaching/bookings/{bookingID}/homework.
+// The booking's expert assigns homework to the student after the session has
+// started; due dates get reminders through the session reminder pipeline.
+func (h *Handler) CreateHomework(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req homeworkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	dueAt, msg := req.parse(time.Now(), pgtype.Timestamptz{})
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	b, ok := h.loadFollowUpBooking(ctx, w, r, user.ID, true)
+	if !ok {
+		return
+	}
+
+	count, err := h.q.CountBookingHomework(ctx, b.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "count_booking_homework_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to assign homework", http.StatusInternalServerError)
+		return
+	}
+	if count >= MaxHomeworkPerBooking {
+		http.Error(w, "A session can have at most 20 homework items", http.StatusConflict)
+		return
+	}
+
+	hw, err := h.q.CreateHomework(ctx, db.CreateHomeworkParams{
+		BookingID:   b.ID,
+		StudentID:   b.StudentID,
+		ExpertID:    b.ExpertID,
+		GroupID:     b.GroupID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       dueAt,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "create_homework_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to assign homework", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "homework_created",
+		slog.String("component", "coaching"),
+		slog.String("homework_id", uuidToString(hw.ID)),
+		slog.String("booking_id", uuidToString(b.ID)),
+	)
+
+	h.scheduleHomeworkReminders(ctx, hw)
+	h.notifyHomeworkAssigned(ctx, hw)
+
+	writeJSON(w, http.StatusCreated, toHomeworkResponse(hw))
+}
+
+// UpdateHomework handles PUT /groups/{groupID}/coaching/homework/{homeworkID}.
+// Only the assigning expert may edit; a changed due date reschedules its
+// reminders.
+func (h *Handler) UpdateHomework(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req homeworkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	existing, ok := h.loadHomework(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+	if existing.ExpertID != user.ID {
+		http.Error(w, "Only the assigning expert can edit homework", http.StatusForbidden)
+		return
+	}
+
+	dueAt, msg := req.parse(time.Now(), existing.DueAt)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	hw, err := h.q.UpdateHomework(ctx, db.UpdateHomeworkParams{
+		ID:          existing.ID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       dueAt,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "update_homework_failed",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(existing.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to update homework", http.StatusInternalServerError)
+		return
+	}
+
+	if hw.DueAt != existing.DueAt {
+		if err := h.q.DeleteUnsentHomeworkReminders(ctx, hw.ID); err != nil {
+			log.ErrorContext(ctx, "delete_homework_reminders_failed",
+				slog.String("component", "coaching"),
+				slog.String("homework_id", uuidToString(hw.ID)),
+				slog.Any("err", err),
+			)
+		} else {
+			h.scheduleHomeworkReminders(ctx, hw)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, toHomeworkResponse(hw))
+}
+
+// DeleteHomework handles DELETE /groups/{groupID}/coaching/homework/{homeworkID}.
+// Its pending reminders go with it.
+func (h *Handler) DeleteHomework(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	hw, ok := h.loadHomework(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+	if hw.ExpertID != user.ID {
+		http.Error(w, "Only the assigning expert can delete homework", http.StatusForbidden)
+		return
+	}
+
+	if err := h.q.DeleteHomework(ctx, hw.ID); err != nil {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "delete_homework_failed",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(hw.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete homework", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CompleteHomework handles PUT /groups/{groupID}/coaching/homework/{homeworkID}/complete.
+func (h *Handler) CompleteHomework(w http.ResponseWriter, r *http.Request) {
+	h.setHomeworkCompleted(w, r, true)
+}
+
+// ReopenHomework handles PUT /groups/{groupID}/coaching/homework/{homeworkID}/reopen.
+func (h *Handler) ReopenHomework(w http.ResponseWriter, r *http.Request) {
+	h.setHomeworkCompleted(w, r, false)
+}
+
+// setHomeworkCompleted lets the student tick homework off or open it again.
+// Completing is idempotent and keeps the first completion time.
+func (h *Handler) setHomeworkCompleted(w http.ResponseWriter, r *http.Request, completed bool) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	existing, ok := h.loadHomework(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+	if existing.StudentID != user.ID {
+		http.Error(w, "Only the student can complete homework", http.StatusForbidden)
+		return
+	}
+
+	hw, err := h.q.SetHomeworkCompleted(ctx, db.SetHomeworkCompletedParams{Completed: completed, ID: existing.ID})
+	if err != nil {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "set_homework_completed_failed",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(existing.ID)),
+			slog.Bool("completed", completed),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to update homework", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toHomeworkResponse(hw))
+}
+
+// ListHomework handles GET /groups/{groupID}/coaching/homework: homework the
+// caller assigned or was assigned in the group. ?status=open hides completed
+// items.
+func (h *Handler) ListHomework(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != "open" && status != "all" {
+		http.Error(w, "status must be open or all", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.q.ListHomework(ctx, db.ListHomeworkParams{
+		GroupID:   groupID,
+		UserID:    user.ID,
+		OpenOnly:  status == "open",
+		PageLimit: HomeworkPageSize,
+	})
+	if err != nil {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "list_homework_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list homework", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]homeworkResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = homeworkRowToResponse(row)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetSessionSummary handles GET /groups/{groupID}/coaching/bookings/{bookingID}/summary
+// for either participant once the session has started.
+func (h *Handler) GetSessionSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	b, ok := h.loadFollowUpBooking(ctx, w, r, user.ID, false)
+	if !ok {
+		return
+	}
+
+	resp := sessionSummaryResponse{
+		BookingID:   uuidToString(b.ID),
+		ScheduledAt: b.ScheduledAt.Time,
+		Homework:    []homeworkResponse{},
+	}
+	if b.RecordingAssetID.Valid {
+		id := uuidToString(b.RecordingAssetID)
+		resp.RecordingAssetID = &id
+	}
+
+	notes, err := h.q.GetSessionNotes(ctx, b.ID)
+	switch {
+	case err == nil:
+		n := toSessionNotesResponse(notes)
+		resp.Notes = &n
+	case !errors.Is(err, pgx.ErrNoRows):
+		log.ErrorContext(ctx, "get_session_notes_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch session summary", http.StatusInternalServerError)
+		return
+	}
+
+	homework, err := h.q.ListBookingHomework(ctx, b.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "list_booking_homework_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch session summary", http.StatusInternalServerError)
+		return
+	}
+	for _, hw := range homework {
+		item := toHomeworkResponse(hw)
+		item.RecordingAssetID = resp.RecordingAssetID
+		resp.Homework = append(resp.Homework, item)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// --- Helpers ---
+
+// loadFollowUpBooking fetches the booking named in the URL for a participant
+// and checks that its session has taken place. expertOnly restricts it to the
+// booking's expert, who writes the follow-up.
+func (h *Handler) loadFollowUpBooking(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string, expertOnly bool) (db.CoachingBooking, bool) {
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return db.CoachingBooking{}, false
+	}
+	bookingID, err := parseUUID(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return db.CoachingBooking{}, false
+	}
+
+	// GetBooking is scoped to expert_id OR student_id — ensures caller is a participant.
+	b, err := h.q.GetBooking(ctx, db.GetBookingParams{ID: bookingID, ExpertID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Booking not found", http.StatusNotFound)
+			return db.CoachingBooking{}, false
+		}
+		logger.From(ctx, h.logger).ErrorContext(ctx, "get_booking_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(bookingID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch booking", http.StatusInternalServerError)
+		return db.CoachingBooking{}, false
+	}
+	if b.GroupID != groupID {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return db.CoachingBooking{}, false
+	}
+	if expertOnly && b.ExpertID != userID {
+		http.Error(w, "Only the session's expert can do this", http.StatusForbidden)
+		return db.CoachingBooking{}, false
+	}
+	if b.IsCancelled {
+		http.Error(w, "Booking is cancelled", http.StatusConflict)
+		return db.CoachingBooking{}, false
+	}
+	if time.Now().Before(b.ScheduledAt.Time) {
+		http.Error(w, "Session has not started yet", http.StatusConflict)
+		return db.CoachingBooking{}, false
+	}
+	return b, true
+}
+
+// loadHomework fetches the homework named in the URL and writes a 404 unless
+// the caller assigned it or was assigned it.
+func (h *Handler) loadHomework(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) (db.CoachingHomework, bool) {
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return db.CoachingHomework{}, false
+	}
+	homeworkID, err := parseUUID(chi.URLParam(r, "homeworkID"))
+	if err != nil {
+		http.Error(w, "Invalid homework ID", http.StatusBadRequest)
+		return db.CoachingHomework{}, false
+	}
+
+	hw, err := h.q.GetHomework(ctx, db.GetHomeworkParams{ID: homeworkID, GroupID: groupID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Homework not found", http.StatusNotFound)
+			return db.CoachingHomework{}, false
+		}
+		logger.From(ctx, h.logger).ErrorContext(ctx, "get_homework_failed",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(homeworkID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch homework", http.StatusInternalServerError)
+		return db.CoachingHomework{}, false
+	}
+	if hw.ExpertID != userID && hw.StudentID != userID {
+		http.Error(w, "Homework not found", http.StatusNotFound)
+		return db.CoachingHomework{}, false
+	}
+	return hw, true
+}
+
+// scheduleHomeworkReminders creates the due-date reminder rows for homework.
+// Failures are logged but do not fail the request, as for session reminders.
+func (h *Handler) scheduleHomeworkReminders(ctx context.Context, hw db.CoachingHomework) {
+	if !hw.DueAt.Valid || hw.CompletedAt.Valid {
+		return
+	}
+	log := logger.From(ctx, h.logger)
+	for _, offset := range homeworkReminderOffsets {
+		remindAt := hw.DueAt.Time.Add(-offset)
+		if remindAt.Before(time.Now()) {
+			continue
+		}
+		err := h.q.CreateHomeworkReminder(ctx, db.CreateHomeworkReminderParams{
+			BookingID:  hw.BookingID,
+			HomeworkID: hw.ID,
+			RemindAt:   pgtype.Timestamptz{Time: remindAt, Valid: true},
+		})
+		if err != nil {
+			log.ErrorContext(ctx, "schedule_homework_reminder_failed",
+				slog.String("component", "coaching"),
+				slog.String("homework_id", uuidToString(hw.ID)),
+				slog.Duration("offset", offset),
+				slog.Any("err", err),
+			)
+		}
+	}
+}
+
+// notifySessionNotesShared tells the student in-app that notes are ready.
+func (h *Handler) notifySessionNotesShared(ctx context.Context, b db.CoachingBooking) {
+	groupName := ""
+	if group, err := h.q.GetGroup(ctx, b.GroupID); err == nil {
+		groupName = group.Name
+	}
+	expert := h.resolveParticipant(ctx, b.ExpertID)
+
+	notifications.Record(ctx, h.q, h.logger, b.StudentID, notifications.TypeCoachingSessionNotesShared,
+		notifications.CoachingSessionNotesSharedPayload{
+			BookingID:   uuidToString(b.ID),
+			GroupID:     uuidToString(b.GroupID),
+			GroupName:   groupName,
+			ExpertName:  expert.name,
+			ScheduledAt: b.ScheduledAt.Time.UTC().Format(time.RFC3339),
+		})
+}
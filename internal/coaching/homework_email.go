@@ -0,0 +1,143 @@
+package coaching
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/email"
+	"github.com/OZIOisgood/zeta/internal/i18n"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/OZIOisgood/zeta/internal/preferences"
+)
+
+// notifyHomeworkAssigned tells the student by email and in-app notification
+// what they were assigned and by when.
+func (h *Handler) notifyHomeworkAssigned(ctx context.Context, hw db.CoachingHomework) {
+	log := logger.From(ctx, h.logger)
+
+	groupName := ""
+	if group, err := h.q.GetGroup(ctx, hw.GroupID); err != nil {
+		log.WarnContext(ctx, "homework_assigned_fetch_group_failed",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(hw.ID)),
+			slog.Any("err", err),
+		)
+	} else {
+		groupName = group.Name
+	}
+	expert := h.resolveParticipant(ctx, hw.ExpertID)
+
+	payload := notifications.CoachingHomeworkAssignedPayload{
+		HomeworkID: uuidToString(hw.ID),
+		BookingID:  uuidToString(hw.BookingID),
+		GroupID:    uuidToString(hw.GroupID),
+		GroupName:  groupName,
+		ExpertName: expert.name,
+		Title:      hw.Title,
+	}
+	if hw.DueAt.Valid {
+		payload.DueAt = hw.DueAt.Time.UTC().Format(time.RFC3339)
+	}
+	notifications.Record(ctx, h.q, h.logger, hw.StudentID, notifications.TypeCoachingHomeworkAssigned, payload)
+
+	student := h.resolveParticipant(ctx, hw.StudentID)
+	if student.email == "" {
+		return
+	}
+	if !preferences.AllowsUserEmail(ctx, h.q, h.logger, hw.StudentID, preferences.EmailCategoryCoachingBookingUpdates) {
+		log.InfoContext(ctx, "homework_assigned_email_skipped_by_preferences",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(hw.ID)),
+			slog.String("user_id", hw.StudentID),
+		)
+		return
+	}
+
+	localization := h.resolveRecipientLocalization(ctx, hw.StudentID)
+	loc := localization.localizer
+	introKey := "email.homework_assigned.intro"
+	data := map[string]any{
+		"ExpertName": expert.name,
+		"Title":      hw.Title,
+		"GroupName":  groupName,
+	}
+	if hw.DueAt.Valid {
+		introKey = "email.homework_assigned.intro_due"
+		data["DueAt"] = formatEmailDateTime(hw.DueAt.Time, localization)
+	}
+	subject := i18n.T(loc, "email.homework_assigned.subject")
+	message := email.Message{
+		Copy: email.Copy{
+			Preheader: i18n.T(loc, "email.homework_assigned.preheader"),
+			Title:     i18n.T(loc, "email.homework_assigned.title"),
+			Intro:     i18n.T(loc, introKey, data),
+		},
+	}
+
+	if err := h.emailService.SendTemplate([]string{student.email}, subject, email.TemplateNotification, message); err != nil {
+		log.ErrorContext(ctx, "homework_assigned_email_failed",
+			slog.String("component", "coaching"),
+			slog.String("homework_id", uuidToString(hw.ID)),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	log.InfoContext(ctx, "homework_assigned_email_sent",
+		slog.String("component", "coaching"),
+		slog.String("homework_id", uuidToString(hw.ID)),
+	)
+}
+
+// sendHomeworkReminder emails the student that homework is coming due. It
+// returns an error only when sending failed, so the reminder is retried;
+// skipping the email by preference or for want of an address is not an error.
+func (h *Handler) sendHomeworkReminder(ctx context.Context, rem db.ListPendingRemindersRow) error {
+	log := logger.From(ctx, h.logger)
+
+	if !preferences.AllowsUserEmail(ctx, h.q, h.logger, rem.StudentID, preferences.EmailCategoryCoachingReminders) {
+		log.InfoContext(ctx, "homework_reminder_email_skipped_by_preferences",
+			slog.String("component", "coaching"),
+			slog.String("reminder_id", uuidToString(rem.ID)),
+			slog.String("user_id", rem.StudentID),
+		)
+		return nil
+	}
+	student := h.resolveParticipant(ctx, rem.StudentID)
+	if student.email == "" {
+		return nil
+	}
+
+	localization := h.resolveRecipientLocalization(ctx, rem.StudentID)
+	loc := localization.localizer
+	subject := i18n.T(loc, "email.homework_reminder.subject")
+	message := email.Message{
+		Copy: email.Copy{
+			Preheader: i18n.T(loc, "email.homework_reminder.preheader"),
+			Title:     i18n.T(loc, "email.homework_reminder.title"),
+			Intro: i18n.T(loc, "email.homework_reminder.intro", map[string]any{
+				"Title": rem.HomeworkTitle.String,
+				"DueAt": formatEmailDateTime(rem.HomeworkDueAt.Time, localization),
+			}),
+		},
+	}
+
+	if err := h.emailService.SendTemplate([]string{student.email}, subject, email.TemplateNotification, message); err != nil {
+		log.ErrorContext(ctx, "homework_reminder_email_failed",
+			slog.String("component", "coaching"),
+			slog.String("reminder_id", uuidToString(rem.ID)),
+			slog.Any("err", err),
+		)
+		return err
+	}
+
+	log.InfoContext(ctx, "homework_reminder_email_sent",
+		slog.String("component", "coaching"),
+		slog.String("reminder_id", uuidToString(rem.ID)),
+		slog.String("homework_id", uuidToString(rem.HomeworkID)),
+	)
+	return nil
+}
@@ -0,0 +1,151 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CoachingHomework(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private lesson", DurationMinutes: 60, Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	now := time.Now().UTC().Truncate(time.Second)
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: ts(now.Add(-2 * time.Hour)), DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	// Notes are one row per booking; a second write replaces the first.
+	if _, err := q.GetSessionNotes(ctx, booking.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("GetSessionNotes before writing = %v, want ErrNoRows", err)
+	}
+	for _, summary := range []string{"Jab drills", "Jab drills and footwork"} {
+		if _, err := q.UpsertSessionNotes(ctx, db.UpsertSessionNotesParams{
+			BookingID: booking.ID, AuthorID: "expert-1", Summary: summary,
+		}); err != nil {
+			t.Fatalf("UpsertSessionNotes(%q): %v", summary, err)
+		}
+	}
+	if notes, err := q.GetSessionNotes(ctx, booking.ID); err != nil || notes.Summary != "Jab drills and footwork" {
+		t.Fatalf("GetSessionNotes = %+v, %v; want the rewritten summary", notes, err)
+	}
+
+	create := func(title string, dueAt pgtype.Timestamptz) db.CoachingHomework {
+		hw, err := q.CreateHomework(ctx, db.CreateHomeworkParams{
+			BookingID: booking.ID, StudentID: "student-1", ExpertID: "expert-1", GroupID: group.ID,
+			Title: title, DueAt: dueAt,
+		})
+		if err != nil {
+			t.Fatalf("CreateHomework(%s): %v", title, err)
+		}
+		return hw
+	}
+	clips := create("Upload 3 clips of drill X", ts(now.Add(72*time.Hour)))
+	later := create("Shadow boxing", ts(now.Add(96*time.Hour)))
+	undated := create("Watch the recording", pgtype.Timestamptz{})
+
+	if n, err := q.CountBookingHomework(ctx, booking.ID); err != nil || n != 3 {
+		t.Fatalf("CountBookingHomework = %d, %v; want 3", n, err)
+	}
+	if _, err := q.GetHomework(ctx, db.GetHomeworkParams{ID: clips.ID, GroupID: booking.ID}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("GetHomework in another group = %v, want ErrNoRows", err)
+	}
+
+	// The recording reaches homework through its booking.
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Live coaching recording", GroupID: group.ID, OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	if _, err := q.AssignBookingRecordingAsset(ctx, db.AssignBookingRecordingAssetParams{ID: booking.ID, RecordingAssetID: asset.ID}); err != nil {
+		t.Fatalf("AssignBookingRecordingAsset: %v", err)
+	}
+
+	done, err := q.SetHomeworkCompleted(ctx, db.SetHomeworkCompletedParams{Completed: true, ID: later.ID})
+	if err != nil || !done.CompletedAt.Valid {
+		t.Fatalf("SetHomeworkCompleted = %+v, %v; want completed", done, err)
+	}
+	if again, err := q.SetHomeworkCompleted(ctx, db.SetHomeworkCompletedParams{Completed: true, ID: later.ID}); err != nil || again.CompletedAt != done.CompletedAt {
+		t.Fatalf("completing twice moved completed_at: %v -> %v (%v)", done.CompletedAt, again.CompletedAt, err)
+	}
+
+	list := func(userID string, openOnly bool) []db.ListHomeworkRow {
+		rows, err := q.ListHomework(ctx, db.ListHomeworkParams{GroupID: group.ID, UserID: userID, OpenOnly: openOnly, PageLimit: 10})
+		if err != nil {
+			t.Fatalf("ListHomework(%s): %v", userID, err)
+		}
+		return rows
+	}
+	all := list("student-1", false)
+	if len(all) != 3 || all[0].ID != clips.ID || all[1].ID != undated.ID || all[2].ID != later.ID {
+		t.Fatalf("ListHomework order = %v, want open by due date, then completed", all)
+	}
+	if all[0].RecordingAssetID != asset.ID || !all[0].SessionScheduledAt.Valid {
+		t.Fatalf("ListHomework row = %+v, want the session's recording", all[0])
+	}
+	if open := list("expert-1", true); len(open) != 2 {
+		t.Fatalf("open homework for the expert = %d, want 2", len(open))
+	}
+	if none := list("student-2", false); len(none) != 0 {
+		t.Fatalf("homework visible to another student: %d", len(none))
+	}
+
+	// Session reminders and homework reminders share the table but not their
+	// lifecycles.
+	if err := q.CreateBookingReminder(ctx, db.CreateBookingReminderParams{BookingID: booking.ID, RemindAt: ts(now.Add(time.Hour))}); err != nil {
+		t.Fatalf("CreateBookingReminder: %v", err)
+	}
+	if err := q.CreateHomeworkReminder(ctx, db.CreateHomeworkReminderParams{
+		BookingID: booking.ID, HomeworkID: clips.ID, RemindAt: ts(now.Add(-time.Minute)),
+	}); err != nil {
+		t.Fatalf("CreateHomeworkReminder: %v", err)
+	}
+	if err := q.DeleteUnsentBookingReminders(ctx, booking.ID); err != nil {
+		t.Fatalf("DeleteUnsentBookingReminders: %v", err)
+	}
+	pending, err := q.ListPendingReminders(ctx)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("ListPendingReminders = %d, %v; want the homework reminder", len(pending), err)
+	}
+	if rem := pending[0]; rem.HomeworkID != clips.ID || rem.HomeworkTitle.String != clips.Title || rem.HomeworkDueAt != clips.DueAt {
+		t.Fatalf("pending reminder = %+v, want the clips homework", rem)
+	}
+
+	if err := q.DeleteUnsentHomeworkReminders(ctx, clips.ID); err != nil {
+		t.Fatalf("DeleteUnsentHomeworkReminders: %v", err)
+	}
+	if pending, err := q.ListPendingReminders(ctx); err != nil || len(pending) != 0 {
+		t.Fatalf("ListPendingReminders after delete = %d, %v; want none", len(pending), err)
+	}
+
+	if err := q.DeleteHomework(ctx, clips.ID); err != nil {
+		t.Fatalf("DeleteHomework: %v", err)
+	}
+	if remaining, err := q.ListBookingHomework(ctx, booking.ID); err != nil || len(remaining) != 2 {
+		t.Fatalf("ListBookingHomework = %d, %v; want 2", len(remaining), err)
+	}
+}
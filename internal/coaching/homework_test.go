@@ -0,0 +1,278 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	authmocks "github.com/OZIOisgood/zeta/internal/auth/mocks"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/email"
+	emailmocks "github.com/OZIOisgood/zeta/internal/email/mocks"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/workos/workos-go/v4/pkg/usermanagement"
+	"go.uber.org/mock/gomock"
+)
+
+const homeworkTestID = "44444444-4444-4444-4444-444444444444"
+
+func homeworkTestRequest(method, body, userID string, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("groupID", rescheduleTestBookingID)
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(context.WithValue(ctx, auth.UserKey, &auth.UserContext{ID: userID}))
+}
+
+// pastBooking is a session that took place an hour ago.
+func pastBooking(t *testing.T) db.CoachingBooking {
+	b := rescheduleBooking(t)
+	b.ScheduledAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+	return b
+}
+
+func TestSessionNotesRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     sessionNotesRequest
+		wantMsg string
+	}{
+		{"summary only", sessionNotesRequest{Summary: " Worked on the jab. "}, ""},
+		{"blank summary", sessionNotesRequest{Summary: "  ", WentWell: "Footwork"}, "summary is required"},
+		{"field too long", sessionNotesRequest{Summary: "ok", NextFocus: strings.Repeat("x", MaxSessionNotesLength+1)}, "each notes field must be at most 10000 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.validate(); got != tt.wantMsg {
+				t.Fatalf("validate() = %q, want %q", got, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestHomeworkRequestParse(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	overdue := pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+
+	tests := []struct {
+		name      string
+		req       homeworkRequest
+		keep      pgtype.Timestamptz
+		wantDueAt pgtype.Timestamptz
+		wantMsg   string
+	}{
+		{"no due date", homeworkRequest{Title: "Upload 3 clips of drill X"}, pgtype.Timestamptz{}, pgtype.Timestamptz{}, ""},
+		{"future due date", homeworkRequest{Title: "Drill", DueAt: str("2030-01-11T17:00:00Z")}, pgtype.Timestamptz{},
+			pgtype.Timestamptz{Time: time.Date(2030, 1, 11, 17, 0, 0, 0, time.UTC), Valid: true}, ""},
+		{"past due date", homeworkRequest{Title: "Drill", DueAt: str("2030-01-07T09:00:00Z")}, pgtype.Timestamptz{}, pgtype.Timestamptz{}, "due_at must be in the future"},
+		{"overdue date kept on edit", homeworkRequest{Title: "Drill", DueAt: str("2030-01-07T09:00:00Z")}, overdue, overdue, ""},
+		{"bad due date", homeworkRequest{Title: "Drill", DueAt: str("Friday")}, pgtype.Timestamptz{}, pgtype.Timestamptz{}, "Invalid due_at (use RFC3339)"},
+		{"blank title", homeworkRequest{Title: " "}, pgtype.Timestamptz{}, pgtype.Timestamptz{}, "title must be between 1 and 200 characters"},
+		{"long description", homeworkRequest{Title: "Drill", Description: strings.Repeat("x", MaxHomeworkDescriptionLength+1)}, pgtype.Timestamptz{}, pgtype.Timestamptz{}, "description must be at most 5000 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dueAt, msg := tt.req.parse(now, tt.keep)
+			if msg != tt.wantMsg || dueAt != tt.wantDueAt {
+				t.Fatalf("parse() = %v, %q; want %v, %q", dueAt, msg, tt.wantDueAt, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestPutSessionNotes(t *testing.T) {
+	body := `{"summary":"Worked on the jab","next_focus":"Footwork"}`
+	params := map[string]string{"bookingID": rescheduleTestBookingID}
+
+	t.Run("only the expert writes notes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		q := dbmocks.NewMockQuerier(ctrl)
+		h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+		q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(pastBooking(t), nil)
+
+		rec := httptest.NewRecorder()
+		h.PutSessionNotes(rec, homeworkTestRequest(http.MethodPut, body, "student-1", params))
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want 403", rec.Code)
+		}
+	})
+
+	t.Run("not before the session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		q := dbmocks.NewMockQuerier(ctrl)
+		h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+		q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(rescheduleBooking(t), nil)
+
+		rec := httptest.NewRecorder()
+		h.PutSessionNotes(rec, homeworkTestRequest(http.MethodPut, body, "expert-1", params))
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want 409", rec.Code)
+		}
+	})
+
+	for _, tt := range []struct {
+		name       string
+		existing   error
+		wantNotify bool
+	}{
+		{"first notes notify the student", pgx.ErrNoRows, true},
+		{"edits stay quiet", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			workos := authmocks.NewMockUserManagement(ctrl)
+			h := NewHandler(q, nil, nil, workos, slog.Default(), HandlerConfig{})
+			b := pastBooking(t)
+
+			q.EXPECT().GetBooking(gomock.Any(), db.GetBookingParams{ID: b.ID, ExpertID: "expert-1"}).Return(b, nil)
+			q.EXPECT().GetSessionNotes(gomock.Any(), b.ID).Return(db.CoachingSessionNote{}, tt.existing)
+			q.EXPECT().UpsertSessionNotes(gomock.Any(), db.UpsertSessionNotesParams{
+				BookingID: b.ID, AuthorID: "expert-1", Summary: "Worked on the jab", NextFocus: "Footwork",
+			}).Return(db.CoachingSessionNote{BookingID: b.ID, AuthorID: "expert-1", Summary: "Worked on the jab"}, nil)
+			if tt.wantNotify {
+				q.EXPECT().GetGroup(gomock.Any(), b.GroupID).Return(db.Group{Name: "Academy"}, nil)
+				workos.EXPECT().GetUser(gomock.Any(), usermanagement.GetUserOpts{User: "expert-1"}).
+					Return(usermanagement.User{ID: "expert-1"}, nil)
+				q.EXPECT().GetUserPreferences(gomock.Any(), "expert-1").
+					Return(db.UserPreference{UserID: "expert-1", FirstName: "Ivo"}, nil)
+				q.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+						var payload notifications.CoachingSessionNotesSharedPayload
+						if err := json.Unmarshal(arg.Payload, &payload); err != nil {
+							t.Fatalf("unmarshal payload: %v", err)
+						}
+						if arg.RecipientID != "student-1" || payload.ExpertName != "Ivo" || payload.GroupName != "Academy" {
+							t.Fatalf("notification = %s %+v", arg.RecipientID, payload)
+						}
+						return db.Notification{}, nil
+					})
+			}
+
+			rec := httptest.NewRecorder()
+			h.PutSessionNotes(rec, homeworkTestRequest(http.MethodPut, body, "expert-1", params))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateHomeworkRefusesPastLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := pastBooking(t)
+	q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(b, nil)
+	q.EXPECT().CountBookingHomework(gomock.Any(), b.ID).Return(int64(MaxHomeworkPerBooking), nil)
+
+	rec := httptest.NewRecorder()
+	h.CreateHomework(rec, homeworkTestRequest(http.MethodPost, `{"title":"Drill"}`, "expert-1",
+		map[string]string{"bookingID": rescheduleTestBookingID}))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", rec.Code)
+	}
+}
+
+func TestSetHomeworkCompletedIsForTheStudent(t *testing.T) {
+	var id pgtype.UUID
+	if err := id.Scan(homeworkTestID); err != nil {
+		t.Fatalf("scan id: %v", err)
+	}
+	hw := db.CoachingHomework{ID: id, ExpertID: "expert-1", StudentID: "student-1"}
+	params := map[string]string{"homeworkID": homeworkTestID}
+
+	tests := []struct {
+		userID   string
+		wantCode int
+	}{
+		{"student-1", http.StatusOK},
+		{"expert-1", http.StatusForbidden},
+		{"student-2", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+			q.EXPECT().GetHomework(gomock.Any(), db.GetHomeworkParams{ID: id, GroupID: rescheduleBooking(t).GroupID}).Return(hw, nil)
+			if tt.wantCode == http.StatusOK {
+				q.EXPECT().SetHomeworkCompleted(gomock.Any(), db.SetHomeworkCompletedParams{Completed: true, ID: id}).Return(hw, nil)
+			}
+
+			rec := httptest.NewRecorder()
+			h.CompleteHomework(rec, homeworkTestRequest(http.MethodPut, "", tt.userID, params))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestProcessRemindersHomework(t *testing.T) {
+	dueAt := time.Date(2030, 1, 11, 17, 0, 0, 0, time.UTC)
+	reminder := func(id byte, completed bool) db.ListPendingRemindersRow {
+		return db.ListPendingRemindersRow{
+			ID:                  pgtype.UUID{Bytes: [16]byte{id}, Valid: true},
+			ExpertID:            "expert-1",
+			StudentID:           "student-1",
+			HomeworkID:          pgtype.UUID{Bytes: [16]byte{9}, Valid: true},
+			HomeworkTitle:       pgtype.Text{String: "Upload 3 clips", Valid: true},
+			HomeworkDueAt:       pgtype.Timestamptz{Time: dueAt, Valid: true},
+			HomeworkCompletedAt: pgtype.Timestamptz{Time: dueAt.Add(-48 * time.Hour), Valid: completed},
+		}
+	}
+	open, done := reminder(1, false), reminder(2, true)
+
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	sender := emailmocks.NewMockSender(ctrl)
+	workos := authmocks.NewMockUserManagement(ctrl)
+	h := NewHandler(q, nil, sender, workos, slog.Default(), HandlerConfig{})
+
+	q.EXPECT().ListPendingReminders(gomock.Any()).Return([]db.ListPendingRemindersRow{open, done}, nil)
+	q.EXPECT().GetUserEmailPreferences(gomock.Any(), "student-1").Return(
+		db.GetUserEmailPreferencesRow{EmailNotificationsEnabled: true, EmailCoachingRemindersEnabled: true}, nil,
+	)
+	workos.EXPECT().GetUser(gomock.Any(), usermanagement.GetUserOpts{User: "student-1"}).
+		Return(usermanagement.User{ID: "student-1", Email: "student@example.com"}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "student-1").
+		Return(db.UserPreference{UserID: "student-1", Language: db.LanguageCodeEn, Timezone: "UTC"}, nil).AnyTimes()
+	sender.EXPECT().SendTemplate(
+		[]string{"student@example.com"},
+		"Coaching Homework Due Soon",
+		email.TemplateNotification,
+		gomock.Any(),
+	).DoAndReturn(func(_ []string, _ string, _ email.TemplateName, msg email.Message) error {
+		if !strings.Contains(msg.Copy.Intro, "Upload 3 clips") {
+			t.Fatalf("intro = %q, want the homework title", msg.Copy.Intro)
+		}
+		return nil
+	})
+	q.EXPECT().MarkReminderSent(gomock.Any(), open.ID).Return(nil)
+	q.EXPECT().MarkReminderSent(gomock.Any(), done.ID).Return(nil)
+
+	rec := httptest.NewRecorder()
+	h.ProcessReminders(rec, httptest.NewRequest(http.MethodPost, "/internal/coaching/reminders", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var got map[string]int
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got["sent"] != 1 {
+		t.Fatalf("response = %v, %v; want one reminder sent", got, err)
+	}
+}
@@ -0,0 +1,155 @@
+package coaching
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// iCalendar (RFC 5545) output for the calendar feed and the .ics invitations
+// attached to booking emails. Times are written in UTC; X-WR-TIMEZONE tells
+// subscribing clients which zone to display the feed in.
+
+const (
+	icsProductID     = "-//Strido//Coaching//EN"
+	icsLineLimit     = 75
+	icsMethodPublish = "PUBLISH"
+	icsMethodRequest = "REQUEST"
+	icsMethodCancel  = "CANCEL"
+)
+
+type icsParty struct {
+	Name  string
+	Email string
+}
+
+type icsEvent struct {
+	UID         string
+	Sequence    int64
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Summary     string
+	Description string
+	URL         string
+	Cancelled   bool
+	Organizer   *icsParty
+	Attendees   []icsParty
+}
+
+// bookingEventUID is stable across reschedules and cancellation so clients
+// update the existing entry instead of adding a new one.
+func bookingEventUID(bookingID pgtype.UUID) string {
+	return "coaching-booking-" + uuidToString(bookingID) + "@strido.net"
+}
+
+// classEventUID names the one entry a class has in its expert's calendar,
+// however many seats are booked.
+func classEventUID(classID pgtype.UUID) string {
+	return "coaching-class-" + uuidToString(classID) + "@strido.net"
+}
+
+// bookingEventSequence grows with every change to the booking, which is what
+// calendar clients compare to decide whether an update supersedes their copy.
+func bookingEventSequence(createdAt, updatedAt pgtype.Timestamptz) int64 {
+	if !createdAt.Valid || !updatedAt.Valid || !updatedAt.Time.After(createdAt.Time) {
+		return 0
+	}
+	return int64(updatedAt.Time.Sub(createdAt.Time) / time.Second)
+}
+
+// renderICS serialises events into a VCALENDAR with CRLF line endings and
+// folded long lines.
+func renderICS(method, calendarName, timezone string, events []icsEvent) []byte {
+	var b strings.Builder
+	w := func(line string) { writeICSLine(&b, line) }
+
+	w("BEGIN:VCALENDAR")
+	w("VERSION:2.0")
+	w("PRODID:" + icsProductID)
+	w("CALSCALE:GREGORIAN")
+	w("METHOD:" + method)
+	if calendarName != "" {
+		w("X-WR-CALNAME:" + escapeICSText(calendarName))
+	}
+	if timezone != "" {
+		w("X-WR-TIMEZONE:" + timezone)
+	}
+	for _, e := range events {
+		w("BEGIN:VEVENT")
+		w("UID:" + e.UID)
+		w("SEQUENCE:" + strconv.FormatInt(e.Sequence, 10))
+		w("DTSTAMP:" + formatICSTime(e.Stamp))
+		w("DTSTART:" + formatICSTime(e.Start))
+		w("DTEND:" + formatICSTime(e.End))
+		w("SUMMARY:" + escapeICSText(e.Summary))
+		if e.Description != "" {
+			w("DESCRIPTION:" + escapeICSText(e.Description))
+		}
+		if e.URL != "" {
+			w("URL:" + e.URL)
+		}
+		if e.Organizer != nil {
+			w("ORGANIZER" + icsPartyParams(*e.Organizer) + ":mailto:" + e.Organizer.Email)
+		}
+		for _, a := range e.Attendees {
+			w("ATTENDEE;ROLE=REQ-PARTICIPANT" + icsPartyParams(a) + ":mailto:" + a.Email)
+		}
+		if e.Cancelled {
+			w("STATUS:CANCELLED")
+		} else {
+			w("STATUS:CONFIRMED")
+		}
+		w("END:VEVENT")
+	}
+	w("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func icsPartyParams(p icsParty) string {
+	if p.Name == "" {
+		return ""
+	}
+	return `;CN="` + strings.NewReplacer(`"`, "'", "\r", "", "\n", " ").Replace(p.Name) + `"`
+}
+
+func formatICSTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeICSText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// writeICSLine folds line into chunks of at most 75 octets, never splitting a
+// UTF-8 sequence, and terminates each with CRLF.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines spend one octet on the leading space.
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isUTF8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
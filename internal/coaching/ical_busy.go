@@ -0,0 +1,445 @@
+package coaching
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Busy-time extraction from external iCalendar files. Only what is needed to
+// keep slots free is understood: VEVENT start/end, TRANSP, STATUS, EXDATE,
+// RECURRENCE-ID overrides and simple RRULEs (DAILY, WEEKLY with BYDAY,
+// MONTHLY and YEARLY by start date). Rules with other BY* parts contribute
+// their first occurrence only.
+
+// maxRecurrenceSteps bounds how many periods one RRULE is expanded over.
+const maxRecurrenceSteps = 5000
+
+var errNotICalendar = errors.New("not an iCalendar file")
+
+type busyInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+type icsProperty struct {
+	params map[string]string
+	value  string
+}
+
+type icsBusyEvent struct {
+	uid          string
+	start        time.Time
+	allDay       bool
+	end          time.Time
+	hasEnd       bool
+	duration     time.Duration
+	hasDuration  bool
+	rrule        string
+	exdates      map[int64]bool
+	recurrenceID time.Time
+	isOverride   bool
+	free         bool
+}
+
+// parseICSBusy returns the busy intervals in data that overlap [from, to),
+// sorted by start and capped at limit. Floating times and all-day dates are
+// read in loc, the expert's timezone.
+func parseICSBusy(data []byte, loc *time.Location, from, to time.Time, limit int) ([]busyInterval, error) {
+	lines := unfoldICSLines(string(data))
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, errNotICalendar
+	}
+
+	var events []icsBusyEvent
+	var stack []string
+	var props map[string][]icsProperty
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		name, prop, ok := parseICSProperty(line)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			stack = append(stack, component)
+			if component == "VEVENT" {
+				props = map[string][]icsProperty{}
+			}
+			continue
+		case "END":
+			if len(stack) > 0 {
+				if stack[len(stack)-1] == "VEVENT" {
+					if ev, err := buildICSBusyEvent(props, loc); err == nil {
+						events = append(events, ev)
+					}
+					props = nil
+				}
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		// Properties of nested components such as VALARM are ignored.
+		if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+			props[name] = append(props[name], prop)
+		}
+	}
+
+	overrides := make(map[string]map[int64]bool)
+	for _, ev := range events {
+		if ev.isOverride {
+			if overrides[ev.uid] == nil {
+				overrides[ev.uid] = map[int64]bool{}
+			}
+			overrides[ev.uid][ev.recurrenceID.Unix()] = true
+		}
+	}
+
+	var out []busyInterval
+	for _, ev := range events {
+		if ev.free {
+			continue
+		}
+		length := ev.length()
+		if length <= 0 {
+			continue
+		}
+		add := func(start time.Time) {
+			end := start.Add(length)
+			if start.Before(to) && end.After(from) {
+				out = append(out, busyInterval{Start: start.UTC(), End: end.UTC()})
+			}
+		}
+		if ev.isOverride || ev.rrule == "" {
+			add(ev.start)
+			continue
+		}
+		skip := overrides[ev.uid]
+		expandICSRecurrence(ev, from, to, func(start time.Time) {
+			if ev.exdates[start.Unix()] || skip[start.Unix()] {
+				return
+			}
+			add(start)
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (ev icsBusyEvent) length() time.Duration {
+	switch {
+	case ev.hasEnd:
+		return ev.end.Sub(ev.start)
+	case ev.hasDuration:
+		return ev.duration
+	case ev.allDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+func buildICSBusyEvent(props map[string][]icsProperty, loc *time.Location) (icsBusyEvent, error) {
+	ev := icsBusyEvent{exdates: map[int64]bool{}}
+	first := func(name string) (icsProperty, bool) {
+		if p := props[name]; len(p) > 0 {
+			return p[0], true
+		}
+		return icsProperty{}, false
+	}
+
+	startProp, ok := first("DTSTART")
+	if !ok {
+		return ev, errors.New("event without DTSTART")
+	}
+	var err error
+	if ev.start, ev.allDay, err = parseICSDateTime(startProp, loc); err != nil {
+		return ev, err
+	}
+	if p, ok := first("DTEND"); ok {
+		if ev.end, _, err = parseICSDateTime(p, loc); err != nil {
+			return ev, err
+		}
+		ev.hasEnd = true
+	} else if p, ok := first("DURATION"); ok {
+		if ev.duration, err = parseICSDuration(p.value); err != nil {
+			return ev, err
+		}
+		ev.hasDuration = true
+	}
+	if p, ok := first("UID"); ok {
+		ev.uid = p.value
+	}
+	if p, ok := first("RRULE"); ok {
+		ev.rrule = p.value
+	}
+	if p, ok := first("RECURRENCE-ID"); ok {
+		if ev.recurrenceID, _, err = parseICSDateTime(p, loc); err != nil {
+			return ev, err
+		}
+		ev.isOverride = true
+	}
+	for _, p := range props["EXDATE"] {
+		for _, v := range strings.Split(p.value, ",") {
+			if t, _, err := parseICSDateTime(icsProperty{params: p.params, value: v}, loc); err == nil {
+				ev.exdates[t.Unix()] = true
+			}
+		}
+	}
+	if p, ok := first("TRANSP"); ok && strings.EqualFold(p.value, "TRANSPARENT") {
+		ev.free = true
+	}
+	if p, ok := first("STATUS"); ok && strings.EqualFold(p.value, "CANCELLED") {
+		ev.free = true
+	}
+	return ev, nil
+}
+
+// expandICSRecurrence calls emit for every occurrence of ev's RRULE starting
+// before to, in order. Occurrences keep the wall-clock time of DTSTART. Rules
+// without COUNT jump ahead to shortly before from, so old daily events still
+// reach the current window.
+func expandICSRecurrence(ev icsBusyEvent, from, to time.Time, emit func(time.Time)) {
+	rule := map[string]string{}
+	for _, part := range strings.Split(ev.rrule, ";") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			rule[strings.ToUpper(k)] = strings.ToUpper(v)
+		}
+	}
+	interval := 1
+	if n, err := strconv.Atoi(rule["INTERVAL"]); err == nil && n > 0 {
+		interval = n
+	}
+	count := -1
+	if n, err := strconv.Atoi(rule["COUNT"]); err == nil && n > 0 {
+		count = n
+	}
+	var until time.Time
+	if v := rule["UNTIL"]; v != "" {
+		if t, allDay, err := parseICSDateTime(icsProperty{value: v}, ev.start.Location()); err == nil {
+			until = t
+			if allDay {
+				until = t.Add(24*time.Hour - time.Second)
+			}
+		}
+	}
+
+	emitted := 0
+	// next reports whether expansion should continue after start.
+	next := func(start time.Time) bool {
+		if !until.IsZero() && start.After(until) {
+			return false
+		}
+		if !start.Before(to) {
+			return false
+		}
+		emit(start)
+		emitted++
+		return count < 0 || emitted < count
+	}
+
+	start := ev.start
+	// skip is the number of whole periods of length period that can be passed
+	// over without missing an occurrence overlapping from.
+	skip := func(period time.Duration) int {
+		if count >= 0 || !from.After(start) {
+			return 0
+		}
+		n := int(from.Sub(start)/period) - 1
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	simple := true
+	for k := range rule {
+		if strings.HasPrefix(k, "BY") && !(k == "BYDAY" && rule["FREQ"] == "WEEKLY") {
+			simple = false
+		}
+	}
+	if !simple {
+		next(start)
+		return
+	}
+
+	switch rule["FREQ"] {
+	case "DAILY":
+		first := skip(time.Duration(interval) * 24 * time.Hour)
+		for i := first; i < first+maxRecurrenceSteps; i++ {
+			if !next(start.AddDate(0, 0, i*interval)) {
+				return
+			}
+		}
+	case "WEEKLY":
+		days := parseICSByDay(rule["BYDAY"])
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		// Weeks start on Monday (the RFC 5545 default WKST).
+		offset := (int(start.Weekday()) + 6) % 7
+		weekStart := start.AddDate(0, 0, -offset)
+		first := skip(time.Duration(interval) * 7 * 24 * time.Hour)
+		for w := first; w < first+maxRecurrenceSteps; w++ {
+			week := weekStart.AddDate(0, 0, 7*w*interval)
+			for _, d := range days {
+				candidate := week.AddDate(0, 0, (int(d)+6)%7)
+				if candidate.Before(start) {
+					continue
+				}
+				if !next(candidate) {
+					return
+				}
+			}
+		}
+	case "MONTHLY", "YEARLY":
+		period := time.Duration(interval) * 31 * 24 * time.Hour
+		if rule["FREQ"] == "YEARLY" {
+			period = time.Duration(interval) * 366 * 24 * time.Hour
+		}
+		first := skip(period)
+		for i := first; i < first+maxRecurrenceSteps; i++ {
+			candidate := start.AddDate(0, i*interval, 0)
+			if rule["FREQ"] == "YEARLY" {
+				candidate = start.AddDate(i*interval, 0, 0)
+			}
+			// Months without the start's day (e.g. the 31st) have no occurrence.
+			if candidate.Day() != start.Day() {
+				if !candidate.Before(to) {
+					return
+				}
+				continue
+			}
+			if !next(candidate) {
+				return
+			}
+		}
+	default:
+		next(start)
+	}
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseICSByDay returns the weekdays of a WEEKLY BYDAY list ordered from
+// Monday.
+func parseICSByDay(v string) []time.Weekday {
+	var days []time.Weekday
+	for _, d := range strings.Split(v, ",") {
+		if wd, ok := icsWeekdays[strings.TrimSpace(d)]; ok {
+			days = append(days, wd)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return (days[i]+6)%7 < (days[j]+6)%7 })
+	return days
+}
+
+// parseICSDateTime reads a DATE or DATE-TIME value. UTC ("Z") and TZID times
+// are honoured; floating times, dates and unknown TZIDs fall back to loc.
+func parseICSDateTime(p icsProperty, loc *time.Location) (time.Time, bool, error) {
+	v := strings.TrimSpace(p.value)
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(v) == 8 {
+		t, err := time.ParseInLocation("20060102", v, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+	tz := loc
+	if id := p.params["TZID"]; id != "" {
+		if l, err := time.LoadLocation(strings.Trim(id, "/")); err == nil {
+			tz = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", v, tz)
+	return t, false, err
+}
+
+// parseICSDuration parses RFC 5545 durations such as PT1H30M, P1D or P2W.
+func parseICSDuration(v string) (time.Duration, error) {
+	s := strings.ToUpper(strings.TrimSpace(v))
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+	}
+	s = strings.TrimLeft(s, "+-")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	var total time.Duration
+	num := ""
+	for _, c := range s[1:] {
+		if c >= '0' && c <= '9' {
+			num += string(c)
+			continue
+		}
+		if c == 'T' {
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", v)
+		}
+		num = ""
+		switch c {
+		case 'W':
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case 'D':
+			total += time.Duration(n) * 24 * time.Hour
+		case 'H':
+			total += time.Duration(n) * time.Hour
+		case 'M':
+			total += time.Duration(n) * time.Minute
+		case 'S':
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", v)
+		}
+	}
+	return sign * total, nil
+}
+
+func unfoldICSLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\n ", "")
+	s = strings.ReplaceAll(s, "\n\t", "")
+	return strings.Split(strings.TrimPrefix(s, "\ufeff"), "\n")
+}
+
+// parseICSProperty splits "NAME;PARAM=x:value" into its parts. Colons inside
+// quoted parameter values do not end the name.
+func parseICSProperty(line string) (string, icsProperty, bool) {
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", icsProperty{}, false
+	}
+	head := strings.Split(line[:colon], ";")
+	prop := icsProperty{params: map[string]string{}, value: strings.TrimRight(line[colon+1:], "\r")}
+	for _, p := range head[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			prop.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(head[0]), prop, true
+}
@@ -0,0 +1,154 @@
+package coaching
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func icsCalendar(lines ...string) []byte {
+	return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n")
+}
+
+func busyStarts(busy []busyInterval) []time.Time {
+	starts := make([]time.Time, len(busy))
+	for i, b := range busy {
+		starts[i] = b.Start
+	}
+	return starts
+}
+
+func TestParseICSBusySingleEvents(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	data := icsCalendar(
+		"BEGIN:VEVENT", "UID:utc", "DTSTART:20300107T100000Z", "DTEND:20300107T110000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:tzid", "DTSTART;TZID=America/New_York:20300108T090000", "DURATION:PT30M",
+		"BEGIN:VALARM", "TRIGGER:-PT15M", "DTSTART:20300101T000000Z", "END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT", "UID:floating", "DTSTART:20300109T090000", "DTEND:20300109T100000", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:allday", "DTSTART;VALUE=DATE:20300110", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:free", "DTSTART:20300111T100000Z", "DTEND:20300111T110000Z", "TRANSP:TRANSPARENT", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:cancelled", "DTSTART:20300112T100000Z", "DTEND:20300112T110000Z", "STATUS:CANCELLED", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:outside", "DTSTART:20300301T100000Z", "DTEND:20300301T110000Z", "END:VEVENT",
+	)
+
+	got, err := parseICSBusy(data, berlin, from, to, 0)
+	if err != nil {
+		t.Fatalf("parseICSBusy: %v", err)
+	}
+	want := []busyInterval{
+		{time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC), time.Date(2030, 1, 7, 11, 0, 0, 0, time.UTC)},
+		{time.Date(2030, 1, 8, 14, 0, 0, 0, time.UTC), time.Date(2030, 1, 8, 14, 30, 0, 0, time.UTC)},
+		{time.Date(2030, 1, 9, 8, 0, 0, 0, time.UTC), time.Date(2030, 1, 9, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2030, 1, 9, 23, 0, 0, 0, time.UTC), time.Date(2030, 1, 10, 23, 0, 0, 0, time.UTC)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("busy[%d] = %v–%v; want %v–%v", i, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
+
+func TestParseICSBusyWeeklyRecurrence(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2030, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	data := icsCalendar(
+		"BEGIN:VEVENT", "UID:standup", "DTSTART:20300107T090000Z", "DTEND:20300107T091500Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE", "EXDATE:20300109T090000Z", "END:VEVENT",
+		// The second Monday moved to the afternoon.
+		"BEGIN:VEVENT", "UID:standup", "RECURRENCE-ID:20300114T090000Z",
+		"DTSTART:20300114T150000Z", "DTEND:20300114T151500Z", "END:VEVENT",
+	)
+
+	got, err := parseICSBusy(data, time.UTC, from, to, 0)
+	if err != nil {
+		t.Fatalf("parseICSBusy: %v", err)
+	}
+	want := []time.Time{
+		time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 14, 15, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 16, 9, 0, 0, 0, time.UTC),
+	}
+	if starts := busyStarts(got); len(starts) != len(want) {
+		t.Fatalf("got %v; want %v", starts, want)
+	}
+	for i, w := range want {
+		if !got[i].Start.Equal(w) {
+			t.Errorf("busy[%d] starts %v; want %v", i, got[i].Start, w)
+		}
+	}
+}
+
+func TestParseICSBusyCountAndUntil(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 2, 0)
+
+	data := icsCalendar(
+		"BEGIN:VEVENT", "UID:count", "DTSTART:20300101T080000Z", "DURATION:PT1H", "RRULE:FREQ=DAILY;COUNT=3", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:until", "DTSTART:20300105T120000Z", "DURATION:PT1H",
+		"RRULE:FREQ=MONTHLY;UNTIL=20300206T000000Z", "END:VEVENT",
+	)
+
+	got, err := parseICSBusy(data, time.UTC, from, to, 0)
+	if err != nil {
+		t.Fatalf("parseICSBusy: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("got %v; want 3 daily and 2 monthly occurrences", busyStarts(got))
+	}
+	if last := got[len(got)-1].Start; !last.Equal(time.Date(2030, 2, 5, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("last occurrence %v; want 2030-02-05 12:00", last)
+	}
+}
+
+func TestParseICSBusySkipsToWindowForOldSeries(t *testing.T) {
+	from := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+
+	// A daily event that started decades before the window would exceed the
+	// step bound without skipping ahead.
+	data := icsCalendar(
+		"BEGIN:VEVENT", "UID:old", "DTSTART:19900101T070000Z", "DTEND:19900101T073000Z", "RRULE:FREQ=DAILY", "END:VEVENT",
+	)
+
+	got, err := parseICSBusy(data, time.UTC, from, to, 0)
+	if err != nil {
+		t.Fatalf("parseICSBusy: %v", err)
+	}
+	if len(got) != 3 || !got[0].Start.Equal(time.Date(2030, 6, 1, 7, 0, 0, 0, time.UTC)) {
+		t.Fatalf("got %v; want 3 occurrences from 2030-06-01 07:00", busyStarts(got))
+	}
+}
+
+func TestParseICSBusyLimit(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := icsCalendar(
+		"BEGIN:VEVENT", "UID:daily", "DTSTART:20300101T080000Z", "DURATION:PT1H", "RRULE:FREQ=DAILY", "END:VEVENT",
+	)
+
+	got, err := parseICSBusy(data, time.UTC, from, from.AddDate(1, 0, 0), 10)
+	if err != nil {
+		t.Fatalf("parseICSBusy: %v", err)
+	}
+	if len(got) != 10 || !got[9].Start.Equal(time.Date(2030, 1, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("got %v; want the first 10 occurrences", busyStarts(got))
+	}
+}
+
+func TestParseICSBusyRejectsNonCalendar(t *testing.T) {
+	_, err := parseICSBusy([]byte("<html>Sign in</html>"), time.UTC, time.Now(), time.Now().Add(time.Hour), 0)
+	if !errors.Is(err, errNotICalendar) {
+		t.Fatalf("err = %v; want errNotICalendar", err)
+	}
+}
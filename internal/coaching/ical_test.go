@@ -0,0 +1,102 @@
+package coaching
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	got := escapeICSText("Dressage, level 2; \"flat\" work\\notes\nline two")
+	want := `Dressage\, level 2\; "flat" work\\notes\nline two`
+	if got != want {
+		t.Fatalf("escapeICSText = %q; want %q", got, want)
+	}
+}
+
+func TestWriteICSLineFoldsAtOctetLimitWithoutSplittingRunes(t *testing.T) {
+	var b strings.Builder
+	line := "SUMMARY:" + strings.Repeat("ü", 80)
+	writeICSLine(&b, line)
+
+	out := b.String()
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Fatalf("line not CRLF terminated: %q", out)
+	}
+	parts := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	if len(parts) < 2 {
+		t.Fatalf("expected folded output, got %q", out)
+	}
+	var unfolded strings.Builder
+	for i, p := range parts {
+		if len(p) > icsLineLimit {
+			t.Errorf("part %d is %d octets: %q", i, len(p), p)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(p, " ") {
+				t.Fatalf("continuation %d does not start with a space: %q", i, p)
+			}
+			p = p[1:]
+		}
+		if !strings.HasPrefix(p, "SUMMARY") && !strings.HasPrefix(p, "ü") {
+			t.Errorf("part %d starts inside a rune: %q", i, p)
+		}
+		unfolded.WriteString(p)
+	}
+	if unfolded.String() != line {
+		t.Fatalf("unfolded = %q; want %q", unfolded.String(), line)
+	}
+}
+
+func TestRenderICSCancelledInvitation(t *testing.T) {
+	start := time.Date(2030, time.January, 7, 9, 0, 0, 0, time.UTC)
+	out := string(renderICS(icsMethodCancel, "", "", []icsEvent{{
+		UID:       "coaching-booking-1@strido.net",
+		Sequence:  42,
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Stamp:     start.Add(-time.Hour),
+		Summary:   "Private, 1:1",
+		Cancelled: true,
+		Organizer: &icsParty{Name: "Alex Coach", Email: "expert@example.com"},
+		Attendees: []icsParty{{Email: "student@example.com"}},
+	}}))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"METHOD:CANCEL\r\n",
+		"UID:coaching-booking-1@strido.net\r\n",
+		"SEQUENCE:42\r\n",
+		"DTSTAMP:20300107T080000Z\r\n",
+		"DTSTART:20300107T090000Z\r\n",
+		"DTEND:20300107T100000Z\r\n",
+		"SUMMARY:Private\\, 1:1\r\n",
+		"ORGANIZER;CN=\"Alex Coach\":mailto:expert@example.com\r\n",
+		"ATTENDEE;ROLE=REQ-PARTICIPANT:mailto:student@example.com\r\n",
+		"STATUS:CANCELLED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "X-WR-TIMEZONE") {
+		t.Errorf("invitation should not carry a display timezone:\n%s", out)
+	}
+}
+
+func TestBookingEventSequenceGrowsWithUpdates(t *testing.T) {
+	created := pgtype.Timestamptz{Time: time.Date(2030, time.January, 1, 9, 0, 0, 0, time.UTC), Valid: true}
+	if got := bookingEventSequence(created, created); got != 0 {
+		t.Errorf("unchanged booking sequence = %d; want 0", got)
+	}
+	updated := pgtype.Timestamptz{Time: created.Time.Add(90 * time.Second), Valid: true}
+	if got := bookingEventSequence(created, updated); got != 90 {
+		t.Errorf("updated booking sequence = %d; want 90", got)
+	}
+	if got := bookingEventSequence(created, pgtype.Timestamptz{}); got != 0 {
+		t.Errorf("missing updated_at sequence = %d; want 0", got)
+	}
+}
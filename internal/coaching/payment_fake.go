@@ -0,0 +1,131 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/webhooksig"
+)
+
+// FakePaymentProvider is an in-memory PaymentProvider for tests and local
+// development. Checkouts never leave the server: CreateCheckout redirects
+// straight to the success URL and a payment only completes when a webhook
+// signed with SignWebhook arrives.
+type FakePaymentProvider struct {
+	secret string
+
+	mu        sync.Mutex
+	checkouts []CheckoutRequest
+	expired   []string
+	refunds   map[string]RefundRequest // by idempotency key
+	// FailRefunds makes Refund return an error, to exercise retries.
+	FailRefunds bool
+}
+
+func NewFakePaymentProvider(webhookSecret string) *FakePaymentProvider {
+	return &FakePaymentProvider{
+		secret:  webhookSecret,
+		refunds: make(map[string]RefundRequest),
+	}
+}
+
+func (p *FakePaymentProvider) Name() string { return "fake" }
+
+func (p *FakePaymentProvider) CreateCheckout(_ context.Context, req CheckoutRequest) (CheckoutSession, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checkouts = append(p.checkouts, req)
+	return CheckoutSession{
+		ID:  "fake_cs_" + req.BookingID,
+		URL: req.SuccessURL,
+	}, nil
+}
+
+// MinCheckoutLifetime is zero: a fake checkout has no expiry of its own.
+func (p *FakePaymentProvider) MinCheckoutLifetime() time.Duration { return 0 }
+
+func (p *FakePaymentProvider) ExpireCheckout(_ context.Context, checkoutSessionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expired = append(p.expired, checkoutSessionID)
+	return nil
+}
+
+func (p *FakePaymentProvider) Refund(_ context.Context, req RefundRequest) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.FailRefunds {
+		return "", errors.New("fake refund failure")
+	}
+	p.refunds[req.IdempotencyKey] = req
+	return "fake_re_" + req.IdempotencyKey, nil
+}
+
+// Checkouts returns the checkout requests made so far.
+func (p *FakePaymentProvider) Checkouts() []CheckoutRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]CheckoutRequest(nil), p.checkouts...)
+}
+
+// ExpiredCheckouts returns the checkout sessions expired so far.
+func (p *FakePaymentProvider) ExpiredCheckouts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.expired...)
+}
+
+// Refunds returns the distinct refunds made so far; retries with the same
+// idempotency key count once.
+func (p *FakePaymentProvider) Refunds() []RefundRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	refunds := make([]RefundRequest, 0, len(p.refunds))
+	for _, r := range p.refunds {
+		refunds = append(refunds, r)
+	}
+	return refunds
+}
+
+// fakeWebhookEvent is the JSON body of a fake webhook delivery.
+type fakeWebhookEvent struct {
+	ID                string `json:"id"`
+	Type              string `json:"type"`
+	CheckoutSessionID string `json:"checkout_session_id"`
+	PaymentID         string `json:"payment_id"`
+}
+
+// CompletedWebhook returns a signed checkout.completed delivery for the
+// checkout session: the body and its Fake-Signature header value.
+func (p *FakePaymentProvider) CompletedWebhook(checkoutSessionID string) ([]byte, string) {
+	payload, _ := json.Marshal(fakeWebhookEvent{
+		ID:                "fake_evt_" + checkoutSessionID,
+		Type:              PaymentEventCheckoutCompleted,
+		CheckoutSessionID: checkoutSessionID,
+		PaymentID:         "fake_pi_" + checkoutSessionID,
+	})
+	return payload, p.SignWebhook(payload, time.Now())
+}
+
+// SignWebhook returns the Fake-Signature header value for payload.
+func (p *FakePaymentProvider) SignWebhook(payload []byte, at time.Time) string {
+	return webhooksig.Sign(p.secret, at, payload)
+}
+
+func (p *FakePaymentProvider) ParseWebhook(payload []byte, header http.Header) (PaymentEvent, error) {
+	if p.secret == "" {
+		return PaymentEvent{}, errors.New("fake webhook signing secret is not configured")
+	}
+	if err := webhooksig.Verify("Fake-Signature", header.Get("Fake-Signature"), payload, p.secret, paymentSignatureTolerance, time.Now()); err != nil {
+		return PaymentEvent{}, err
+	}
+	var e fakeWebhookEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return PaymentEvent{}, err
+	}
+	return PaymentEvent(e), nil
+}
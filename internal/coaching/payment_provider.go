@@ -0,0 +1,249 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/webhooksig"
+)
+
+// Payment events the webhook acts on; providers map their own event names to
+// these and pass everything else through unchanged.
+const (
+	PaymentEventCheckoutCompleted = "checkout.completed"
+)
+
+// paymentSignatureTolerance bounds how old a signed webhook delivery may be,
+// so a captured request cannot be replayed indefinitely.
+const paymentSignatureTolerance = 5 * time.Minute
+
+// PaymentProvider abstracts the payment service that collects the price of a
+// paid session type: a hosted checkout per booking, refunds and its signed
+// webhooks.
+type PaymentProvider interface {
+	// Name is stored with every payment so webhooks and refunds of a
+	// booking go to the provider that took the payment.
+	Name() string
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (CheckoutSession, error)
+	// MinCheckoutLifetime is how long a new checkout stays payable at the
+	// least. A payment hold must not be shorter.
+	MinCheckoutLifetime() time.Duration
+	// ExpireCheckout closes a checkout that was not paid, so a booking
+	// whose slot has been released can no longer be paid for.
+	ExpireCheckout(ctx context.Context, checkoutSessionID string) error
+	// Refund returns the provider's refund ID. The idempotency key makes
+	// retries of the same refund safe.
+	Refund(ctx context.Context, req RefundRequest) (string, error)
+	// ParseWebhook verifies the delivery's signature before decoding it.
+	ParseWebhook(payload []byte, header http.Header) (PaymentEvent, error)
+}
+
+type CheckoutRequest struct {
+	BookingID     string
+	Description   string
+	CustomerEmail string
+	AmountCents   int32
+	Currency      string // ISO 4217, upper case
+	ExpiresAt     time.Time
+	SuccessURL    string
+	CancelURL     string
+}
+
+type CheckoutSession struct {
+	ID  string
+	URL string
+}
+
+type RefundRequest struct {
+	PaymentID      string
+	AmountCents    int32
+	IdempotencyKey string
+}
+
+// PaymentEvent is a verified webhook delivery. PaymentID is set once the
+// checkout captured a payment.
+type PaymentEvent struct {
+	ID                string
+	Type              string
+	CheckoutSessionID string
+	PaymentID         string
+}
+
+// --- Stripe ---
+
+// stripeMinCheckoutLifetime is the shortest expiry Stripe accepts for a
+// checkout session (30 minutes) plus slack for the request's round trip. The
+// handler raises a shorter payment hold to it, so the slot is held for as long
+// as the checkout can be paid.
+const stripeMinCheckoutLifetime = 30*time.Minute + time.Minute
+
+type StripeConfig struct {
+	SecretKey            string
+	WebhookSigningSecret string
+	BaseURL              string // default: https://api.stripe.com
+}
+
+type stripeProvider struct {
+	httpClient *http.Client
+	cfg        StripeConfig
+	now        func() time.Time
+}
+
+// NewStripePaymentProvider takes payments with Stripe Checkout and verifies
+// Stripe-Signature on webhooks.
+func NewStripePaymentProvider(cfg StripeConfig) PaymentProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.stripe.com"
+	}
+	return &stripeProvider{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+func (p *stripeProvider) Name() string { return "stripe" }
+
+func (p *stripeProvider) CreateCheckout(ctx context.Context, req CheckoutRequest) (CheckoutSession, error) {
+	expiresAt := req.ExpiresAt
+	if minExpiry := p.now().Add(stripeMinCheckoutLifetime); expiresAt.Before(minExpiry) {
+		expiresAt = minExpiry
+	}
+	form := url.Values{
+		"mode":                                   {"payment"},
+		"client_reference_id":                    {req.BookingID},
+		"metadata[booking_id]":                   {req.BookingID},
+		"success_url":                            {req.SuccessURL},
+		"cancel_url":                             {req.CancelURL},
+		"expires_at":                             {strconv.FormatInt(expiresAt.Unix(), 10)},
+		"line_items[0][quantity]":                {"1"},
+		"line_items[0][price_data][currency]":    {strings.ToLower(req.Currency)},
+		"line_items[0][price_data][unit_amount]": {strconv.Itoa(int(req.AmountCents))},
+		"line_items[0][price_data][product_data][name]": {req.Description},
+	}
+	if req.CustomerEmail != "" {
+		form.Set("customer_email", req.CustomerEmail)
+	}
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := p.post(ctx, "/v1/checkout/sessions", form, "checkout-"+req.BookingID, &session); err != nil {
+		return CheckoutSession{}, fmt.Errorf("create stripe checkout session: %w", err)
+	}
+	if session.ID == "" || session.URL == "" {
+		return CheckoutSession{}, errors.New("create stripe checkout session: empty session")
+	}
+	return CheckoutSession{ID: session.ID, URL: session.URL}, nil
+}
+
+func (p *stripeProvider) MinCheckoutLifetime() time.Duration { return stripeMinCheckoutLifetime }
+
+func (p *stripeProvider) ExpireCheckout(ctx context.Context, checkoutSessionID string) error {
+	var session struct {
+		ID string `json:"id"`
+	}
+	if err := p.post(ctx, "/v1/checkout/sessions/"+url.PathEscape(checkoutSessionID)+"/expire", url.Values{}, "", &session); err != nil {
+		return fmt.Errorf("expire stripe checkout session: %w", err)
+	}
+	return nil
+}
+
+func (p *stripeProvider) Refund(ctx context.Context, req RefundRequest) (string, error) {
+	form := url.Values{
+		"payment_intent": {req.PaymentID},
+		"amount":         {strconv.Itoa(int(req.AmountCents))},
+	}
+	var refund struct {
+		ID string `json:"id"`
+	}
+	if err := p.post(ctx, "/v1/refunds", form, req.IdempotencyKey, &refund); err != nil {
+		return "", fmt.Errorf("create stripe refund: %w", err)
+	}
+	if refund.ID == "" {
+		return "", errors.New("create stripe refund: empty refund")
+	}
+	return refund.ID, nil
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID            string `json:"id"`
+			PaymentIntent string `json:"payment_intent"`
+			PaymentStatus string `json:"payment_status"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+func (p *stripeProvider) ParseWebhook(payload []byte, header http.Header) (PaymentEvent, error) {
+	if p.cfg.WebhookSigningSecret == "" {
+		return PaymentEvent{}, errors.New("stripe webhook signing secret is not configured")
+	}
+	if err := webhooksig.Verify("Stripe-Signature", header.Get("Stripe-Signature"), payload, p.cfg.WebhookSigningSecret, paymentSignatureTolerance, p.now()); err != nil {
+		return PaymentEvent{}, err
+	}
+	var e stripeEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return PaymentEvent{}, err
+	}
+	event := PaymentEvent{
+		ID:                e.ID,
+		Type:              e.Type,
+		CheckoutSessionID: e.Data.Object.ID,
+		PaymentID:         e.Data.Object.PaymentIntent,
+	}
+	// Delayed payment methods complete the session unpaid and succeed later.
+	switch {
+	case e.Type == "checkout.session.completed" && e.Data.Object.PaymentStatus == "paid",
+		e.Type == "checkout.session.async_payment_succeeded":
+		event.Type = PaymentEventCheckoutCompleted
+	}
+	return event, nil
+}
+
+func (p *stripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	if p.cfg.SecretKey == "" {
+		return errors.New("stripe secret key is not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.cfg.BaseURL, "/")+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return fmt.Errorf("stripe returned %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,472 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxPaymentWebhookBodySize = 1 << 20
+
+var errPaymentEventIncomplete = errors.New("payment event is missing its identifiers")
+
+// setBookingPrice copies the session type's price onto a new booking. A paid
+// booking starts pending and holds its slot for the payment hold.
+func (h *Handler) setBookingPrice(arg *db.CreateBookingParams, st db.CoachingSessionType) {
+	arg.PriceCents = st.PriceCents
+	arg.Currency = st.Currency
+	if st.PriceCents > 0 {
+		arg.PaymentStatus = db.NullCoachingPaymentStatus{CoachingPaymentStatus: db.CoachingPaymentStatusPending, Valid: true}
+		arg.PaymentExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(h.paymentHoldTTL), Valid: true}
+	}
+}
+
+func hasPaymentStatus(b db.CoachingBooking, status db.CoachingPaymentStatus) bool {
+	return b.PaymentStatus.Valid && b.PaymentStatus.CoachingPaymentStatus == status
+}
+
+// completeNewBooking tells the participants about a new booking. A paid
+// booking instead gets its checkout, whose URL is returned; the announcement
+// then waits for the payment webhook.
+func (h *Handler) completeNewBooking(ctx context.Context, b db.CoachingBooking, sessionTypeName, customerEmail string) (string, error) {
+	if !hasPaymentStatus(b, db.CoachingPaymentStatusPending) {
+		h.sendBookingCreatedEmail(ctx, b, sessionTypeName)
+		h.recordBookingCreatedNotification(b, sessionTypeName)
+		h.scheduleReminders(ctx, b)
+		return "", nil
+	}
+	checkout, err := h.startCheckout(ctx, b, sessionTypeName, customerEmail)
+	if err != nil {
+		// Without a checkout the booking can never be paid; free its slot now.
+		if abandonErr := h.abandonCheckout(ctx, b, checkout.ID); abandonErr != nil {
+			logger.From(ctx, h.logger).ErrorContext(ctx, "abandon_checkout_failed",
+				slog.String("component", "coaching"),
+				slog.String("booking_id", uuidToString(b.ID)),
+				slog.Any("err", abandonErr),
+			)
+		}
+		return "", err
+	}
+	return checkout.URL, nil
+}
+
+// startCheckout opens the provider's checkout for a booking awaiting payment
+// and records it, so the webhook can find the booking again. A checkout that
+// could not be recorded is still returned with the error, so it can be closed.
+func (h *Handler) startCheckout(ctx context.Context, b db.CoachingBooking, sessionTypeName, customerEmail string) (CheckoutSession, error) {
+	if h.paymentProvider == nil {
+		return CheckoutSession{}, errors.New("no payment provider configured")
+	}
+	bookingID := uuidToString(b.ID)
+	checkout, err := h.paymentProvider.CreateCheckout(ctx, CheckoutRequest{
+		BookingID:     bookingID,
+		Description:   sessionTypeName,
+		CustomerEmail: customerEmail,
+		AmountCents:   b.PriceCents,
+		Currency:      b.Currency.String,
+		ExpiresAt:     b.PaymentExpiresAt.Time,
+		SuccessURL:    h.checkoutReturnURL("/sessions/upcoming", bookingID, "success"),
+		CancelURL:     h.checkoutReturnURL("/sessions/book", bookingID, "cancelled"),
+	})
+	if err != nil {
+		return CheckoutSession{}, err
+	}
+	if _, err := h.q.CreateCoachingPayment(ctx, db.CreateCoachingPaymentParams{
+		BookingID:         b.ID,
+		Provider:          h.paymentProvider.Name(),
+		CheckoutSessionID: checkout.ID,
+		AmountCents:       b.PriceCents,
+		Currency:          b.Currency.String,
+	}); err != nil {
+		return checkout, err
+	}
+	return checkout, nil
+}
+
+// checkoutReturnURL is the frontend page the checkout returns to.
+func (h *Handler) checkoutReturnURL(path, bookingID, result string) string {
+	query := url.Values{"booking": {bookingID}, "payment": {result}}
+	return strings.TrimRight(h.appBaseURL, "/") + path + "?" + query.Encode()
+}
+
+// abandonCheckout cancels a booking whose checkout could not be started, puts
+// a student who booked off the waitlist back in line, closes the checkout if
+// one was opened and offers the slot to the waitlist.
+func (h *Handler) abandonCheckout(ctx context.Context, b db.CoachingBooking, checkoutSessionID string) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	expired, err := h.expireBookingPaymentInTx(ctx, tx, b)
+	if err != nil {
+		return err
+	}
+	// A student booked off the waitlist keeps their place.
+	if err := db.New(tx).ReopenWaitlistEntryForBooking(ctx, b.ID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	if checkoutSessionID != "" {
+		h.expireCheckout(ctx, b.ID, h.paymentProvider.Name(), checkoutSessionID)
+	}
+	h.fillFromWaitlist(expired.ExpertID, expired.ScheduledAt.Time, bookingEnd(expired))
+	return nil
+}
+
+// expireBookingCheckout closes the checkout of a booking whose payment hold ran
+// out, before its slot is offered to someone else.
+func (h *Handler) expireBookingCheckout(ctx context.Context, bookingID pgtype.UUID) {
+	p, err := h.q.GetCoachingPayment(ctx, bookingID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.From(ctx, h.logger).WarnContext(ctx, "get_coaching_payment_failed",
+				slog.String("component", "coaching"),
+				slog.String("booking_id", uuidToString(bookingID)),
+				slog.Any("err", err),
+			)
+		}
+		return
+	}
+	h.expireCheckout(ctx, bookingID, p.Provider, p.CheckoutSessionID)
+}
+
+// expireCheckout closes an unpaid checkout. A failure is only logged: a
+// payment that still completes lands on a cancelled booking and is refunded.
+func (h *Handler) expireCheckout(ctx context.Context, bookingID pgtype.UUID, provider, checkoutSessionID string) {
+	err := fmt.Errorf("payment provider %q is not configured", provider)
+	if h.paymentProvider != nil && h.paymentProvider.Name() == provider {
+		err = h.paymentProvider.ExpireCheckout(ctx, checkoutSessionID)
+	}
+	if err != nil {
+		logger.From(ctx, h.logger).WarnContext(ctx, "expire_checkout_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(bookingID)),
+			slog.String("checkout_session_id", checkoutSessionID),
+			slog.Any("err", err),
+		)
+	}
+}
+
+// expireBookingPaymentInTx cancels a booking still waiting for its payment
+// and records booking.cancelled.
+func (h *Handler) expireBookingPaymentInTx(ctx context.Context, tx pgx.Tx, b db.CoachingBooking) (db.CoachingBooking, error) {
+	expired, err := db.New(tx).ExpireBookingPayment(ctx, b.ID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingCancelled, expired, &b)); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return expired, nil
+}
+
+// PaymentWebhook handles POST /webhooks/payments. Applying a completed
+// checkout is idempotent, so provider retries converge on the same state. A
+// 5xx asks the provider to redeliver.
+func (h *Handler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	if h.paymentProvider == nil {
+		http.Error(w, "Payments are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPaymentWebhookBodySize)
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	event, err := h.paymentProvider.ParseWebhook(payload, r.Header)
+	if err != nil {
+		log.WarnContext(ctx, "payment_webhook_verification_failed",
+			slog.String("component", "coaching"),
+			slog.String("provider", h.paymentProvider.Name()),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	status := "ignored"
+	if event.Type == PaymentEventCheckoutCompleted {
+		status, err = h.applyCheckoutCompleted(ctx, event)
+	}
+	if err != nil {
+		if errors.Is(err, errPaymentEventIncomplete) {
+			http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+			return
+		}
+		log.ErrorContext(ctx, "payment_webhook_apply_failed",
+			slog.String("component", "coaching"),
+			slog.String("event_id", event.ID),
+			slog.String("event_type", event.Type),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to apply webhook", http.StatusInternalServerError)
+		return
+	}
+
+	log.InfoContext(ctx, "payment_webhook_handled",
+		slog.String("component", "coaching"),
+		slog.String("event_id", event.ID),
+		slog.String("event_type", event.Type),
+		slog.String("status", status),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
+
+// applyCheckoutCompleted marks the checkout's booking paid and announces it.
+// A payment that arrives after the booking was cancelled or its hold ran out
+// is refunded instead.
+func (h *Handler) applyCheckoutCompleted(ctx context.Context, event PaymentEvent) (string, error) {
+	if event.CheckoutSessionID == "" || event.PaymentID == "" {
+		return "", errPaymentEventIncomplete
+	}
+	payment, err := h.q.GetCoachingPaymentByCheckout(ctx, db.GetCoachingPaymentByCheckoutParams{
+		Provider:          h.paymentProvider.Name(),
+		CheckoutSessionID: event.CheckoutSessionID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "ignored", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	booking, err := h.markBookingPaid(ctx, payment.BookingID, event.PaymentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "ignored", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if booking.IsCancelled {
+		h.refundBooking(ctx, booking)
+		return "refunded", nil
+	}
+
+	sessionTypeName := ""
+	if st, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{ID: booking.SessionTypeID, GroupID: booking.GroupID}); err == nil {
+		sessionTypeName = st.Name
+	}
+	h.sendBookingCreatedEmail(ctx, booking, sessionTypeName)
+	h.recordBookingCreatedNotification(booking, sessionTypeName)
+	h.scheduleReminders(ctx, booking)
+	return "accepted", nil
+}
+
+// markBookingPaid records the captured payment and marks the booking paid,
+// recording booking.paid in one transaction. pgx.ErrNoRows means the payment
+// was already applied.
+func (h *Handler) markBookingPaid(ctx context.Context, bookingID pgtype.UUID, paymentID string) (db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	existing, err := qtx.GetBookingForPaymentUpdate(ctx, bookingID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	booking, err := qtx.MarkBookingPaid(ctx, bookingID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := qtx.MarkCoachingPaymentPaid(ctx, db.MarkCoachingPaymentPaidParams{
+		BookingID:         bookingID,
+		ProviderPaymentID: pgtype.Text{String: paymentID, Valid: true},
+	}); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingPaid, booking, &existing)); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return booking, nil
+}
+
+// refundBooking refunds a cancelled, paid booking in full and returns it
+// updated. Cancellations are only accepted within the session type's
+// cancellation notice, so every one of them is refunded. A failed refund is
+// logged and left to ProcessPayments to retry.
+func (h *Handler) refundBooking(ctx context.Context, b db.CoachingBooking) db.CoachingBooking {
+	if !b.IsCancelled || !hasPaymentStatus(b, db.CoachingPaymentStatusPaid) {
+		return b
+	}
+	payment, err := h.q.GetCoachingPayment(ctx, b.ID)
+	if err == nil {
+		var refunded db.CoachingBooking
+		refunded, err = h.refundPayment(ctx, payment)
+		if err == nil {
+			return refunded
+		}
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logger.From(ctx, h.logger).ErrorContext(ctx, "refund_booking_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+	}
+	return b
+}
+
+// refundPayment refunds the payment with the provider that took it, then marks
+// the booking refunded and records booking.refunded. The booking ID is the
+// idempotency key, so a retry never refunds twice. pgx.ErrNoRows means the
+// booking was refunded concurrently.
+func (h *Handler) refundPayment(ctx context.Context, p db.CoachingPayment) (db.CoachingBooking, error) {
+	if h.paymentProvider == nil || h.paymentProvider.Name() != p.Provider {
+		return db.CoachingBooking{}, fmt.Errorf("payment provider %q is not configured", p.Provider)
+	}
+	if !p.ProviderPaymentID.Valid {
+		return db.CoachingBooking{}, errors.New("payment has no provider payment ID")
+	}
+	refundID, err := h.paymentProvider.Refund(ctx, RefundRequest{
+		PaymentID:      p.ProviderPaymentID.String,
+		AmountCents:    p.AmountCents,
+		IdempotencyKey: "refund-" + uuidToString(p.BookingID),
+	})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	existing, err := qtx.GetBookingForPaymentUpdate(ctx, p.BookingID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	booking, err := qtx.MarkBookingRefunded(ctx, p.BookingID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := qtx.MarkCoachingPaymentRefunded(ctx, db.MarkCoachingPaymentRefundedParams{
+		BookingID: p.BookingID,
+		RefundID:  pgtype.Text{String: refundID, Valid: true},
+	}); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingRefunded, booking, &existing)); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return booking, nil
+}
+
+// ProcessPayments expires unpaid bookings and retries owed refunds. Called by
+// the scheduler.
+func (h *Handler) ProcessPayments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	expired, err := h.expireUnpaidBookings(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "expire_unpaid_bookings_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to expire unpaid bookings", http.StatusInternalServerError)
+		return
+	}
+	for _, b := range expired {
+		h.expireBookingCheckout(ctx, b.ID)
+		h.fillFromWaitlist(b.ExpertID, b.ScheduledAt.Time, bookingEnd(b))
+	}
+
+	payments, err := h.q.ListPaymentsAwaitingRefund(ctx, PaymentBatchSize)
+	if err != nil {
+		log.ErrorContext(ctx, "list_payments_awaiting_refund_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list refunds", http.StatusInternalServerError)
+		return
+	}
+	refunded, failed := 0, 0
+	for _, p := range payments {
+		if _, err := h.refundPayment(ctx, p); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			failed++
+			log.WarnContext(ctx, "refund_payment_failed",
+				slog.String("component", "coaching"),
+				slog.String("booking_id", uuidToString(p.BookingID)),
+				slog.Any("err", err),
+			)
+			continue
+		}
+		refunded++
+	}
+
+	log.InfoContext(ctx, "payments_processed",
+		slog.String("component", "coaching"),
+		slog.Int("expired", len(expired)),
+		slog.Int("refunded", refunded),
+		slog.Int("refund_failed", failed),
+	)
+
+	writeJSON(w, http.StatusOK, map[string]int{"expired": len(expired), "refunded": refunded, "refund_failed": failed})
+}
+
+// expireUnpaidBookings cancels up to PaymentBatchSize bookings whose payment
+// hold ran out, recording booking.cancelled for each, in one transaction.
+func (h *Handler) expireUnpaidBookings(ctx context.Context) ([]db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	due, err := db.New(tx).ListUnpaidExpiredBookings(ctx, PaymentBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	expired := make([]db.CoachingBooking, 0, len(due))
+	for _, b := range due {
+		updated, err := h.expireBookingPaymentInTx(ctx, tx, b)
+		if err != nil {
+			return nil, err
+		}
+		expired = append(expired, updated)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func bookingEnd(b db.CoachingBooking) time.Time {
+	return b.ScheduledAt.Time.Add(time.Duration(b.DurationMinutes) * time.Minute)
+}
@@ -0,0 +1,132 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_CoachingPayments(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if _, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "No currency", DurationMinutes: 60, PriceCents: 2500,
+	}); err == nil {
+		t.Fatal("paid session type without a currency was accepted")
+	}
+	eur := pgtype.Text{String: "EUR", Valid: true}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60, PriceCents: 2500, Currency: eur,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	pending := db.NullCoachingPaymentStatus{CoachingPaymentStatus: db.CoachingPaymentStatusPending, Valid: true}
+	book := func(studentID string, start time.Time, expiresAt time.Time) db.CoachingBooking {
+		t.Helper()
+		b, err := q.CreateBooking(ctx, db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: studentID, GroupID: group.ID, SessionTypeID: sessionType.ID,
+			ScheduledAt: ts(start), DurationMinutes: 60,
+			PriceCents: sessionType.PriceCents, Currency: sessionType.Currency,
+			PaymentStatus: pending, PaymentExpiresAt: ts(expiresAt),
+		})
+		if err != nil {
+			t.Fatalf("CreateBooking: %v", err)
+		}
+		return b
+	}
+	start := time.Date(2030, 7, 1, 10, 0, 0, 0, time.UTC)
+	paid := book("student-1", start, time.Now().Add(30*time.Minute))
+	unpaid := book("student-2", start.Add(2*time.Hour), time.Now().Add(-time.Minute))
+
+	// A pending booking still takes its slot.
+	n, err := q.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
+		ExpertID: "expert-1", ScheduledAt: ts(start), ScheduledAt_2: ts(start.Add(time.Hour)),
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("CountConflictingBookings = %d, %v; want the pending booking", n, err)
+	}
+
+	if _, err := q.CreateCoachingPayment(ctx, db.CreateCoachingPaymentParams{
+		BookingID: paid.ID, Provider: "fake", CheckoutSessionID: "cs_1", AmountCents: 2500, Currency: "EUR",
+	}); err != nil {
+		t.Fatalf("CreateCoachingPayment: %v", err)
+	}
+	if _, err := q.CreateCoachingPayment(ctx, db.CreateCoachingPaymentParams{
+		BookingID: unpaid.ID, Provider: "fake", CheckoutSessionID: "cs_1", AmountCents: 2500, Currency: "EUR",
+	}); err == nil {
+		t.Fatal("checkout session was recorded twice")
+	}
+	payment, err := q.GetCoachingPaymentByCheckout(ctx, db.GetCoachingPaymentByCheckoutParams{Provider: "fake", CheckoutSessionID: "cs_1"})
+	if err != nil || payment.BookingID != paid.ID {
+		t.Fatalf("GetCoachingPaymentByCheckout = %+v, %v", payment, err)
+	}
+
+	if err := q.MarkCoachingPaymentPaid(ctx, db.MarkCoachingPaymentPaidParams{
+		BookingID: paid.ID, ProviderPaymentID: pgtype.Text{String: "pi_1", Valid: true},
+	}); err != nil {
+		t.Fatalf("MarkCoachingPaymentPaid: %v", err)
+	}
+	marked, err := q.MarkBookingPaid(ctx, paid.ID)
+	if err != nil || marked.PaymentStatus.CoachingPaymentStatus != db.CoachingPaymentStatusPaid {
+		t.Fatalf("MarkBookingPaid = %+v, %v", marked.PaymentStatus, err)
+	}
+	// A redelivered webhook finds nothing left to apply.
+	if _, err := q.MarkBookingPaid(ctx, paid.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("second MarkBookingPaid error = %v; want pgx.ErrNoRows", err)
+	}
+
+	due, err := q.ListUnpaidExpiredBookings(ctx, 10)
+	if err != nil || len(due) != 1 || due[0].ID != unpaid.ID {
+		t.Fatalf("ListUnpaidExpiredBookings = %+v, %v; want only the unpaid booking", due, err)
+	}
+	expired, err := q.ExpireBookingPayment(ctx, unpaid.ID)
+	if err != nil || !expired.IsCancelled || expired.PaymentStatus.CoachingPaymentStatus != db.CoachingPaymentStatusExpired {
+		t.Fatalf("ExpireBookingPayment = %+v, %v", expired, err)
+	}
+	if _, err := q.ExpireBookingPayment(ctx, paid.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("ExpireBookingPayment of a paid booking error = %v; want pgx.ErrNoRows", err)
+	}
+
+	awaiting, err := q.ListPaymentsAwaitingRefund(ctx, 10)
+	if err != nil || len(awaiting) != 0 {
+		t.Fatalf("ListPaymentsAwaitingRefund = %+v, %v; want none while the paid booking is active", awaiting, err)
+	}
+	if _, err := q.CancelBooking(ctx, db.CancelBookingParams{
+		ID: paid.ID, CancelledBy: pgtype.Text{String: "student-1", Valid: true}, ExpertID: "student-1",
+	}); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+	awaiting, err = q.ListPaymentsAwaitingRefund(ctx, 10)
+	if err != nil || len(awaiting) != 1 || awaiting[0].BookingID != paid.ID {
+		t.Fatalf("ListPaymentsAwaitingRefund = %+v, %v; want the cancelled paid booking", awaiting, err)
+	}
+	if err := q.MarkCoachingPaymentRefunded(ctx, db.MarkCoachingPaymentRefundedParams{
+		BookingID: paid.ID, RefundID: pgtype.Text{String: "re_1", Valid: true},
+	}); err != nil {
+		t.Fatalf("MarkCoachingPaymentRefunded: %v", err)
+	}
+	refunded, err := q.MarkBookingRefunded(ctx, paid.ID)
+	if err != nil || refunded.PaymentStatus.CoachingPaymentStatus != db.CoachingPaymentStatusRefunded {
+		t.Fatalf("MarkBookingRefunded = %+v, %v", refunded.PaymentStatus, err)
+	}
+	if awaiting, err := q.ListPaymentsAwaitingRefund(ctx, 10); err != nil || len(awaiting) != 0 {
+		t.Fatalf("ListPaymentsAwaitingRefund = %+v, %v; want none after the refund", awaiting, err)
+	}
+}
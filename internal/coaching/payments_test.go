@@ -0,0 +1,240 @@
+package coaching
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/webhooksig"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerifyPaymentSignature(t *testing.T) {
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1"}`)
+	sign := func(at time.Time, secret string) string {
+		return webhooksig.Sign(secret, at, payload)
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		wantErr bool
+	}{
+		{"valid", sign(now, "whsec"), payload, false},
+		{"second signature matches", sign(now, "whsec") + ",v1=00ff", payload, false},
+		{"wrong secret", sign(now, "other"), payload, true},
+		{"tampered payload", sign(now, "whsec"), []byte(`{"id":"evt_2"}`), true},
+		{"too old", sign(now.Add(-10*time.Minute), "whsec"), payload, true},
+		{"from the future", sign(now.Add(10*time.Minute), "whsec"), payload, true},
+		{"missing timestamp", strings.SplitN(sign(now, "whsec"), ",", 2)[1], payload, true},
+		{"empty", "", payload, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhooksig.Verify("Stripe-Signature", tt.header, tt.payload, "whsec", paymentSignatureTolerance, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStripeParseWebhookMapsCompletedCheckouts(t *testing.T) {
+	p := NewStripePaymentProvider(StripeConfig{WebhookSigningSecret: "whsec"}).(*stripeProvider)
+	tests := []struct {
+		name     string
+		payload  string
+		wantType string
+	}{
+		{"paid checkout", `{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","payment_intent":"pi_1","payment_status":"paid"}}}`, PaymentEventCheckoutCompleted},
+		{"unpaid checkout", `{"id":"evt_2","type":"checkout.session.completed","data":{"object":{"id":"cs_1","payment_status":"unpaid"}}}`, "checkout.session.completed"},
+		{"async payment succeeded", `{"id":"evt_3","type":"checkout.session.async_payment_succeeded","data":{"object":{"id":"cs_1","payment_intent":"pi_1","payment_status":"paid"}}}`, PaymentEventCheckoutCompleted},
+		{"other event", `{"id":"evt_4","type":"charge.refunded","data":{"object":{"id":"ch_1"}}}`, "charge.refunded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Stripe-Signature", webhooksig.Sign("whsec", time.Now(), []byte(tt.payload)))
+
+			event, err := p.ParseWebhook([]byte(tt.payload), header)
+			if err != nil {
+				t.Fatalf("ParseWebhook() error = %v", err)
+			}
+			if event.Type != tt.wantType {
+				t.Fatalf("Type = %q, want %q", event.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestApplyBookingPayment(t *testing.T) {
+	expiresAt := time.Now().Add(30 * time.Minute)
+	tests := []struct {
+		name       string
+		status     db.NullCoachingPaymentStatus
+		wantStatus string
+		wantPrice  int32
+		wantExpiry bool
+	}{
+		{"free", db.NullCoachingPaymentStatus{}, "pending", 0, false},
+		{"awaiting payment", db.NullCoachingPaymentStatus{CoachingPaymentStatus: db.CoachingPaymentStatusPending, Valid: true}, "pending_payment", 2500, true},
+		{"paid", db.NullCoachingPaymentStatus{CoachingPaymentStatus: db.CoachingPaymentStatusPaid, Valid: true}, "pending", 2500, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := bookingResponse{Status: "pending"}
+			applyBookingPayment(&resp, 2500, pgtype.Text{String: "EUR", Valid: true}, tt.status, pgtype.Timestamptz{Time: expiresAt, Valid: true})
+			if resp.Status != tt.wantStatus || resp.PriceCents != tt.wantPrice {
+				t.Fatalf("status = %q, price = %d; want %q, %d", resp.Status, resp.PriceCents, tt.wantStatus, tt.wantPrice)
+			}
+			if (resp.PaymentExpiresAt != nil) != tt.wantExpiry {
+				t.Fatalf("payment_expires_at = %v, want set %v", resp.PaymentExpiresAt, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestCreateBookingOfPaidSessionTypeWithoutProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{MinBookingNotice: 2 * time.Hour})
+	b := rescheduleBooking(t)
+
+	q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(db.CoachingSessionType{
+		ID: b.SessionTypeID, DurationMinutes: 60, PriceCents: 2500, Currency: pgtype.Text{String: "EUR", Valid: true},
+	}, nil)
+
+	body := `{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","scheduled_at":"2030-01-07T10:00:00Z"}`
+	rec := httptest.NewRecorder()
+	h.CreateBooking(rec, seriesRequest(t, b.GroupID, body))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusServiceUnavailable, rec.Body.String())
+	}
+}
+
+func TestCreateBookingSeriesRejectsPaidSessionType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{PaymentProvider: NewFakePaymentProvider("whsec")})
+	b := rescheduleBooking(t)
+
+	q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(db.CoachingSessionType{
+		ID: b.SessionTypeID, DurationMinutes: 60, PriceCents: 2500, Currency: pgtype.Text{String: "EUR", Valid: true},
+	}, nil)
+
+	body := `{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","starts_at":"2030-01-07T10:00:00Z","recurrence":{"frequency":"weekly","count":3}}`
+	rec := httptest.NewRecorder()
+	h.CreateBookingSeries(rec, seriesRequest(t, b.GroupID, body))
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "Paid session types") {
+		t.Fatalf("status = %d, body = %q; want 400 rejecting the paid session type", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	provider := NewFakePaymentProvider("whsec")
+	payload, signature := provider.CompletedWebhook("fake_cs_unknown")
+
+	tests := []struct {
+		name       string
+		provider   PaymentProvider
+		signature  string
+		unknown    bool
+		wantStatus int
+		wantBody   string
+	}{
+		{"no provider", nil, signature, false, http.StatusServiceUnavailable, ""},
+		{"invalid signature", provider, "t=1,v1=00", false, http.StatusBadRequest, ""},
+		{"unknown checkout", provider, signature, true, http.StatusOK, `"ignored"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{PaymentProvider: tt.provider})
+			if tt.unknown {
+				q.EXPECT().GetCoachingPaymentByCheckout(gomock.Any(), db.GetCoachingPaymentByCheckoutParams{
+					Provider: "fake", CheckoutSessionID: "fake_cs_unknown",
+				}).Return(db.CoachingPayment{}, pgx.ErrNoRows)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", strings.NewReader(string(payload)))
+			req.Header.Set("Fake-Signature", tt.signature)
+			rec := httptest.NewRecorder()
+			h.PaymentWebhook(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestNewHandlerHoldsPaymentsAsLongAsTheCheckout(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider PaymentProvider
+		hold     time.Duration
+		want     time.Duration
+	}{
+		{"default", NewStripePaymentProvider(StripeConfig{}), 0, 35 * time.Minute},
+		{"shorter than a stripe checkout", NewStripePaymentProvider(StripeConfig{}), 10 * time.Minute, stripeMinCheckoutLifetime},
+		{"longer than a stripe checkout", NewStripePaymentProvider(StripeConfig{}), time.Hour, time.Hour},
+		{"fake checkout", NewFakePaymentProvider(""), 10 * time.Minute, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, nil, nil, nil, slog.Default(), HandlerConfig{PaymentProvider: tt.provider, PaymentHoldTTL: tt.hold})
+			if h.paymentHoldTTL != tt.want {
+				t.Fatalf("paymentHoldTTL = %v, want %v", h.paymentHoldTTL, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripeExpireCheckout(t *testing.T) {
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"id":"cs_1","status":"expired"}`))
+	}))
+	defer srv.Close()
+	p := NewStripePaymentProvider(StripeConfig{SecretKey: "sk_test", BaseURL: srv.URL})
+
+	if err := p.ExpireCheckout(t.Context(), "cs_1"); err != nil {
+		t.Fatalf("ExpireCheckout() error = %v", err)
+	}
+	if gotPath != "/v1/checkout/sessions/cs_1/expire" || gotAuth != "Bearer sk_test" {
+		t.Fatalf("request = %s with %q, want the session's expire endpoint", gotPath, gotAuth)
+	}
+}
+
+func TestExpireBookingCheckoutClosesTheRecordedCheckout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	provider := NewFakePaymentProvider("whsec")
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{PaymentProvider: provider})
+	bookingID := pgtype.UUID{Bytes: [16]byte{0x42}, Valid: true}
+
+	q.EXPECT().GetCoachingPayment(gomock.Any(), bookingID).Return(db.CoachingPayment{
+		BookingID: bookingID, Provider: "fake", CheckoutSessionID: "fake_cs_1",
+	}, nil)
+	h.expireBookingCheckout(t.Context(), bookingID)
+
+	if got := provider.ExpiredCheckouts(); len(got) != 1 || got[0] != "fake_cs_1" {
+		t.Fatalf("expired checkouts = %v, want [fake_cs_1]", got)
+	}
+}
@@ -21,15 +21,20 @@ import (
 )
 
 const (
-	studentParticipantUID = "1"
-	expertParticipantUID  = "2"
-	recordingBotUID       = "3"
-	recordingBotUIDNum    = uint32(3)
+	studentParticipantUID    = "1"
+	studentParticipantUIDNum = uint32(1)
+	expertParticipantUID     = "2"
+	expertParticipantUIDNum  = uint32(2)
+	recordingBotUID          = "3"
+	recordingBotUIDNum       = uint32(3)
 )
 
-// RecordingClient abstracts Agora Cloud Recording for tests and for keeping
-// HTTP details out of request handlers.
+// RecordingClient abstracts the cloud recording provider (Agora Cloud
+// Recording in production) for tests and for keeping HTTP details out of
+// request handlers.
 type RecordingClient interface {
+	// Name is stored with every recording part it starts.
+	Name() string
 	Start(ctx context.Context, req StartRecordingRequest) (StartedRecording, error)
 	Stop(ctx context.Context, req StopRecordingRequest) error
 }
@@ -41,6 +46,9 @@ type StartRecordingRequest struct {
 	AttemptID   string
 	UID         string
 	RendererURL string
+	// VideoUIDs are the participants a mixed recording subscribes to; empty
+	// means the student and expert of a one-to-one call.
+	VideoUIDs []string
 }
 
 type StartedRecording struct {
@@ -48,6 +56,9 @@ type StartedRecording struct {
 	SID            string
 	UID            string
 	FileNamePrefix []string
+	// Ready reports that the provider captures the call on its own, so the
+	// part records without waiting for the renderer page to join.
+	Ready bool
 }
 
 type StopRecordingRequest struct {
@@ -121,6 +132,8 @@ func NewAgoraCloudRecordingClient(logger *slog.Logger, cfg AgoraCloudRecordingCo
 	}
 }
 
+func (c *agoraCloudRecordingClient) Name() string { return recordingProviderAgoraPage }
+
 func (c *agoraCloudRecordingClient) Start(ctx context.Context, req StartRecordingRequest) (StartedRecording, error) {
 	if err := c.validate(); err != nil {
 		return StartedRecording{}, err
@@ -203,13 +216,17 @@ func (c *agoraCloudRecordingClient) Start(ctx context.Context, req StartRecordin
 			},
 		}
 	} else {
+		videoUIDs := req.VideoUIDs
+		if len(videoUIDs) == 0 {
+			videoUIDs = []string{studentParticipantUID, expertParticipantUID}
+		}
 		startReq.ClientRequest.RecordingConfig = &recordingConfig{
 			ChannelType:        0,
 			MaxIdleTime:        c.cfg.MaxIdleTime,
 			StreamTypes:        2,
 			VideoStreamType:    0,
 			SubscribeAudioUIDs: []string{"#allstream#"},
-			SubscribeVideoUIDs: []string{studentParticipantUID, expertParticipantUID},
+			SubscribeVideoUIDs: videoUIDs,
 			AudioProfile:       1,
 			TranscodingConfig: transcodingConfig{
 				Width:            c.cfg.TranscodingWidth,
@@ -566,6 +583,15 @@ func (h *Handler) CleanupFinishedRecordings(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "Failed to process recording imports", http.StatusInternalServerError)
 		return
 	}
+	stitches, err := h.processPendingRecordingStitches(ctx, maxRecordingStitchesPerRun)
+	if err != nil {
+		log.ErrorContext(ctx, "recording_stitch_cleanup_process_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to process recording imports", http.StatusInternalServerError)
+		return
+	}
 
 	writeJSON(w, http.StatusOK, map[string]int{
 		"parts_processed":  len(parts),
@@ -574,6 +600,9 @@ func (h *Handler) CleanupFinishedRecordings(w http.ResponseWriter, r *http.Reque
 		"imports_ready":    imports.Ready,
 		"imports_deferred": imports.Deferred,
 		"imports_failed":   imports.Failed,
+		"stitches":         stitches.Processed,
+		"stitches_ready":   stitches.Ready,
+		"stitches_failed":  stitches.Failed,
 	})
 }
 
@@ -581,20 +610,6 @@ func recordingBookingEnd(booking db.CoachingBooking) time.Time {
 	return booking.ScheduledAt.Time.Add(time.Duration(booking.DurationMinutes) * time.Minute)
 }
 
-func participantUIDForBooking(userID string, booking db.CoachingBooking) uint32 {
-	if userID == booking.ExpertID {
-		return 2
-	}
-	return 1
-}
-
-func participantRoleForBooking(userID string, booking db.CoachingBooking) string {
-	if userID == booking.ExpertID {
-		return "expert"
-	}
-	return "student"
-}
-
 func sanitizeAgoraPathPart(value string) string {
 	replacer := strings.NewReplacer("-", "", "_", "")
 	return replacer.Replace(value)
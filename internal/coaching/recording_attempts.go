@@ -2,9 +2,7 @@ package coaching
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -39,6 +37,9 @@ type bookingPresenceResponse struct {
 
 // UpdateBookingPresence tracks only authenticated human liveness. Joining the
 // Agora channel counts even if camera and microphone permissions were denied.
+// Presence is kept per participant on the call's host booking, so everyone in
+// a class counts towards the same recording. Each heartbeat also adds to the
+// participant's attendance of the session.
 func (h *Handler) UpdateBookingPresence(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx, h.logger)
@@ -72,11 +73,23 @@ func (h *Handler) UpdateBookingPresence(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Invalid connection ID", http.StatusBadRequest)
 		return
 	}
-	role := participantRoleForBooking(user.ID, booking)
+	call, err := resolveBookingCall(ctx, h.q, booking)
+	if err != nil {
+		log.ErrorContext(ctx, "coaching_call_resolve_failed", slog.String("component", "coaching"), slog.Any("err", err))
+		http.Error(w, "Failed to update presence", http.StatusInternalServerError)
+		return
+	}
+	caller, ok := call.participant(user.ID)
+	if !ok {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return
+	}
+	host := call.Host
+	h.recordAttendance(ctx, host, caller)
 
 	if req.State == "left" {
 		if _, err := h.q.RemoveBookingPresence(ctx, db.RemoveBookingPresenceParams{
-			BookingID: booking.ID, ParticipantRole: role, ConnectionID: connectionID,
+			BookingID: host.ID, ParticipantID: user.ID, ConnectionID: connectionID,
 		}); err != nil {
 			log.ErrorContext(ctx, "coaching_presence_remove_failed", slog.String("component", "coaching"), slog.Any("err", err))
 			http.Error(w, "Failed to update presence", http.StatusInternalServerError)
@@ -84,17 +97,17 @@ func (h *Handler) UpdateBookingPresence(w http.ResponseWriter, r *http.Request)
 		}
 	} else {
 		if _, err := h.q.UpsertBookingPresence(ctx, db.UpsertBookingPresenceParams{
-			BookingID: booking.ID, ParticipantRole: role, ConnectionID: connectionID,
+			BookingID: host.ID, ParticipantID: user.ID, ParticipantRole: caller.Role, ConnectionID: connectionID,
 		}); err != nil {
 			log.ErrorContext(ctx, "coaching_presence_update_failed", slog.String("component", "coaching"), slog.Any("err", err))
 			http.Error(w, "Failed to update presence", http.StatusInternalServerError)
 			return
 		}
-		_ = h.q.ClearRecordingPartEmptySince(ctx, booking.ID)
+		_ = h.q.ClearRecordingPartEmptySince(ctx, host.ID)
 	}
 
 	activeHumans, err := h.q.CountFreshBookingParticipants(ctx, db.CountFreshBookingParticipantsParams{
-		BookingID: booking.ID, FreshSeconds: int32(h.recordingPresenceTTL / time.Second),
+		BookingID: host.ID, FreshSeconds: int32(h.recordingPresenceTTL / time.Second),
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "coaching_presence_count_failed", slog.String("component", "coaching"), slog.Any("err", err))
@@ -106,16 +119,16 @@ func (h *Handler) UpdateBookingPresence(w http.ResponseWriter, r *http.Request)
 	if h.recordingEnabled {
 		recordingStatus = "idle"
 		if activeHumans > 0 {
-			if part, getErr := h.q.GetActiveRecordingPart(ctx, booking.ID); getErr == nil {
+			if part, getErr := h.q.GetActiveRecordingPart(ctx, host.ID); getErr == nil {
 				recordingStatus = string(part.Status)
 			} else if errors.Is(getErr, pgx.ErrNoRows) {
 				recordingStatus = "starting"
-				h.startRecordingPartAsync(ctx, booking)
+				h.startRecordingPartAsync(ctx, call)
 			}
-		} else if part, getErr := h.q.GetActiveRecordingPart(ctx, booking.ID); getErr == nil {
+		} else if part, getErr := h.q.GetActiveRecordingPart(ctx, host.ID); getErr == nil {
 			recordingStatus = string(part.Status)
 			if _, markErr := h.q.MarkEmptyRecordingPartsWithoutFreshHumans(ctx, int32(h.recordingPresenceTTL/time.Second)); markErr == nil {
-				h.reconcileRecordingAfterGrace(ctx, booking.ID)
+				h.reconcileRecordingAfterGrace(ctx, host.ID)
 			}
 		}
 	}
@@ -123,18 +136,18 @@ func (h *Handler) UpdateBookingPresence(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, bookingPresenceResponse{Status: "ok", RecordingStatus: recordingStatus})
 }
 
-func (h *Handler) startRecordingPartAsync(ctx context.Context, booking db.CoachingBooking) {
+func (h *Handler) startRecordingPartAsync(ctx context.Context, call bookingCall) {
 	go func() {
 		runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 105*time.Second)
 		defer cancel()
-		if err := h.startRecordingPart(runCtx, booking); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		if err := h.startRecordingPart(runCtx, call); err != nil && !errors.Is(err, pgx.ErrNoRows) {
 			logger.From(runCtx, h.logger).ErrorContext(runCtx, "coaching_recording_part_start_failed",
-				slog.String("component", "coaching"), slog.String("booking_id", uuidToString(booking.ID)), slog.Any("err", err))
+				slog.String("component", "coaching"), slog.String("booking_id", uuidToString(call.Host.ID)), slog.Any("err", err))
 		}
 	}()
 }
 
-func (h *Handler) startRecordingPart(ctx context.Context, booking db.CoachingBooking) error {
+func (h *Handler) startRecordingPart(ctx context.Context, call bookingCall) error {
 	if !h.recordingEnabled || h.recordingClient == nil {
 		return nil
 	}
@@ -142,12 +155,13 @@ func (h *Handler) startRecordingPart(ctx context.Context, booking db.CoachingBoo
 	if err != nil {
 		return err
 	}
+	booking := call.Host
 	hardStop := recordingBookingEnd(booking).Add(h.recordingEndGrace)
 	if hardStop.Before(time.Now().Add(5 * time.Minute)) {
 		hardStop = time.Now().Add(5 * time.Minute)
 	}
 	part, err := h.q.ClaimNextRecordingPart(ctx, db.ClaimNextRecordingPartParams{
-		BookingID: booking.ID, Provider: recordingProviderAgoraPage,
+		BookingID: booking.ID, Provider: h.recordingClient.Name(),
 		ProviderUid: nullableText(recordingBotUID), RendererTokenHash: tokenHash,
 		RendererTokenExpiresAt: pgtype.Timestamptz{Time: hardStop.Add(5 * time.Minute), Valid: true},
 	})
@@ -155,7 +169,7 @@ func (h *Handler) startRecordingPart(ctx context.Context, booking db.CoachingBoo
 		return err
 	}
 
-	channelName := "coaching_" + uuidToString(booking.ID)
+	channelName := call.channel()
 	providerTokenTTL := uint32(max(300, int(time.Until(hardStop).Seconds())))
 	recordingToken, err := rtctokenbuilder.BuildTokenWithUid(
 		h.agoraAppID, h.agoraAppCertificate, channelName, recordingBotUIDNum,
@@ -169,6 +183,7 @@ func (h *Handler) startRecordingPart(ctx context.Context, booking db.CoachingBoo
 	started, err := h.recordingClient.Start(ctx, StartRecordingRequest{
 		ChannelName: channelName, Token: recordingToken, BookingID: uuidToString(booking.ID),
 		AttemptID: uuidToString(part.ID), UID: recordingBotUID, RendererURL: rendererURL,
+		VideoUIDs: call.videoUIDs(),
 	})
 	if err != nil {
 		_ = h.q.MarkRecordingPartFailed(ctx, db.MarkRecordingPartFailedParams{ID: part.ID, Error: nullableText(truncateRecordingError(err.Error()))})
@@ -179,6 +194,9 @@ func (h *Handler) startRecordingPart(ctx context.Context, booking db.CoachingBoo
 		ProviderRecordingID: nullableText(started.SID), ProviderUid: nullableText(started.UID),
 		OutputPrefix: started.FileNamePrefix,
 	})
+	if err == nil && started.Ready {
+		_, err = h.q.MarkRecordingRendererReady(ctx, tokenHash)
+	}
 	if err != nil {
 		_ = h.recordingClient.Stop(ctx, StopRecordingRequest{
 			ChannelName: channelName, ResourceID: started.ResourceID, SID: started.SID, UID: started.UID,
@@ -188,13 +206,7 @@ func (h *Handler) startRecordingPart(ctx context.Context, booking db.CoachingBoo
 }
 
 func newRendererCapability() (plaintext string, tokenHash []byte, err error) {
-	raw := make([]byte, 32)
-	if _, err := rand.Read(raw); err != nil {
-		return "", nil, err
-	}
-	plaintext = base64.RawURLEncoding.EncodeToString(raw)
-	digest := sha256.Sum256([]byte(plaintext))
-	return plaintext, digest[:], nil
+	return newHashedSecret()
 }
 
 func (h *Handler) reconcileRecordingAfterGrace(ctx context.Context, bookingID pgtype.UUID) {
@@ -285,12 +297,13 @@ type rendererExchangeRequest struct {
 }
 
 type rendererExchangeResponse struct {
-	AppID   string                  `json:"app_id"`
-	Channel string                  `json:"channel"`
-	Token   string                  `json:"token"`
-	UID     uint32                  `json:"uid"`
-	Student participantPresentation `json:"student"`
-	Expert  participantPresentation `json:"expert"`
+	AppID        string                    `json:"app_id"`
+	Channel      string                    `json:"channel"`
+	Token        string                    `json:"token"`
+	UID          uint32                    `json:"uid"`
+	Student      participantPresentation   `json:"student"`
+	Expert       participantPresentation   `json:"expert"`
+	Participants []participantPresentation `json:"participants"`
 }
 
 func (h *Handler) ExchangeRecordingRendererCapability(w http.ResponseWriter, r *http.Request) {
@@ -321,19 +334,23 @@ func (h *Handler) ExchangeRecordingRendererCapability(w http.ResponseWriter, r *
 		http.Error(w, "Recording view unavailable", http.StatusNotFound)
 		return
 	}
-	student, err := h.participantPresentation(r.Context(), contextRow.StudentID, studentParticipantUID, "student")
-	if err != nil {
-		http.Error(w, "Recording view unavailable", http.StatusNotFound)
-		return
+	call := oneToOneParticipants(contextRow.StudentID, contextRow.ExpertID)
+	if contextRow.ClassID.Valid {
+		call, err = classParticipants(r.Context(), h.q, contextRow.ClassID, contextRow.ExpertID)
+		if err != nil {
+			http.Error(w, "Recording view unavailable", http.StatusNotFound)
+			return
+		}
 	}
-	expert, err := h.participantPresentation(r.Context(), contextRow.ExpertID, expertParticipantUID, "expert")
+	participants, err := h.presentCallParticipants(r.Context(), call)
 	if err != nil {
 		http.Error(w, "Recording view unavailable", http.StatusNotFound)
 		return
 	}
+	student, expert := bookingPresentations(participants, call, db.CoachingBooking{StudentID: contextRow.StudentID})
 	writeJSON(w, http.StatusOK, rendererExchangeResponse{
 		AppID: h.agoraAppID, Channel: channelName, Token: token, UID: recordingRendererUID,
-		Student: student, Expert: expert,
+		Student: student, Expert: expert, Participants: participants,
 	})
 }
 
@@ -21,6 +21,7 @@ const maxRecordingImportsPerRun = int32(25)
 type RecordingMuxClient interface {
 	CreateAsset(req muxgo.CreateAssetRequest) (muxgo.AssetResponse, error)
 	GetAsset(assetID string) (muxgo.AssetResponse, error)
+	DeleteAsset(assetID string) error
 }
 
 func (h *Handler) ProcessRecordingImports(w http.ResponseWriter, r *http.Request) {
@@ -31,14 +32,25 @@ func (h *Handler) ProcessRecordingImports(w http.ResponseWriter, r *http.Request
 		http.Error(w, "Failed to process recordings", http.StatusInternalServerError)
 		return
 	}
+	stitches, err := h.processPendingRecordingStitches(r.Context(), maxRecordingStitchesPerRun)
+	if err != nil {
+		logger.From(r.Context(), h.logger).ErrorContext(r.Context(), "recording_stitch_process_failed",
+			slog.String("component", "coaching"), slog.Any("err", err))
+		http.Error(w, "Failed to process recordings", http.StatusInternalServerError)
+		return
+	}
+	result.StitchesReady, result.StitchesDeferred, result.StitchesFailed = stitches.Ready, stitches.Deferred, stitches.Failed
 	writeJSON(w, http.StatusOK, result)
 }
 
 type recordingImportRunResult struct {
-	Processed int `json:"processed"`
-	Ready     int `json:"ready"`
-	Deferred  int `json:"deferred"`
-	Failed    int `json:"failed"`
+	Processed        int `json:"processed"`
+	Ready            int `json:"ready"`
+	Deferred         int `json:"deferred"`
+	Failed           int `json:"failed"`
+	StitchesReady    int `json:"stitches_ready"`
+	StitchesDeferred int `json:"stitches_deferred"`
+	StitchesFailed   int `json:"stitches_failed"`
 }
 
 func (h *Handler) kickRecordingImportProcessing(ctx context.Context) {
@@ -210,9 +222,14 @@ func (h *Handler) createReviewableRecordingPart(ctx context.Context, pending db.
 	}
 	assetID := booking.RecordingAssetID
 	if !assetID.Valid {
+		// A class recording belongs to the expert; its attendees are let in as viewers.
+		ownerID := pending.StudentID
+		if booking.ClassID.Valid {
+			ownerID = booking.ExpertID
+		}
 		asset, err := qtx.CreateAsset(ctx, db.CreateAssetParams{
 			Name: recordingPartAssetTitle(pending), Description: recordingPartAssetDescription(pending),
-			GroupID: pending.GroupID, OwnerID: pending.StudentID,
+			GroupID: pending.GroupID, OwnerID: ownerID,
 		})
 		if err != nil {
 			return false, false, err
@@ -225,6 +242,13 @@ func (h *Handler) createReviewableRecordingPart(ctx context.Context, pending db.
 			return false, false, err
 		}
 		assetID = booking.RecordingAssetID
+		if booking.ClassID.Valid {
+			if err := qtx.AssignClassRecordingAsset(ctx, db.AssignClassRecordingAssetParams{
+				AssetID: assetID, ClassID: booking.ClassID,
+			}); err != nil {
+				return false, false, err
+			}
+		}
 	}
 	sortOrder := pending.PartNumber*1000 + pending.FileIndex
 	video, err := qtx.CreateOrderedVideoFromMuxAsset(ctx, db.CreateOrderedVideoFromMuxAssetParams{
@@ -0,0 +1,182 @@
+package coaching
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Recording providers and object stores the server can be configured with.
+const (
+	RecordingProviderAgora = "agora"
+	RecordingProviderFake  = "fake"
+
+	RecordingStoreGCS   = "gcs"
+	RecordingStoreS3    = "s3"
+	RecordingStoreLocal = "local"
+)
+
+// RecordingConfig selects the provider that records calls and the object
+// store its files land in, and carries the settings of each.
+type RecordingConfig struct {
+	Provider string // default: agora
+	Store    string // default: gcs
+	Agora    AgoraCloudRecordingConfig
+	GCS      GCSRecordingStoreConfig
+	S3       S3RecordingStoreConfig
+	Local    LocalRecordingStoreConfig
+	// FakeSample is the file the fake provider writes for every part;
+	// default: a few bytes that only a fake Mux client accepts.
+	FakeSample []byte
+}
+
+type GCSRecordingStoreConfig struct {
+	Bucket                string
+	SigningServiceAccount string
+}
+
+type recordingStoreFactory func(ctx context.Context, cfg RecordingConfig) (RecordingObjectStore, error)
+
+type recordingProviderFactory func(logger *slog.Logger, cfg RecordingConfig, store RecordingObjectStore) (RecordingClient, error)
+
+var recordingStores = map[string]recordingStoreFactory{
+	RecordingStoreGCS: func(ctx context.Context, cfg RecordingConfig) (RecordingObjectStore, error) {
+		if cfg.GCS.Bucket == "" {
+			// Parts are still recorded, but nothing imports them.
+			return nil, nil
+		}
+		return NewGCSRecordingObjectStore(ctx, cfg.GCS.Bucket, cfg.GCS.SigningServiceAccount)
+	},
+	RecordingStoreS3: func(_ context.Context, cfg RecordingConfig) (RecordingObjectStore, error) {
+		return NewS3RecordingObjectStore(cfg.S3)
+	},
+	RecordingStoreLocal: func(_ context.Context, cfg RecordingConfig) (RecordingObjectStore, error) {
+		return NewLocalRecordingObjectStore(cfg.Local)
+	},
+}
+
+var recordingProviders = map[string]recordingProviderFactory{
+	RecordingProviderAgora: func(logger *slog.Logger, cfg RecordingConfig, _ RecordingObjectStore) (RecordingClient, error) {
+		return NewAgoraCloudRecordingClient(logger, cfg.Agora), nil
+	},
+	RecordingProviderFake: func(_ *slog.Logger, cfg RecordingConfig, store RecordingObjectStore) (RecordingClient, error) {
+		return NewFakeRecordingClient(store, cfg.FakeSample)
+	},
+}
+
+// NewRecordingProvider builds the recording client and object store named in
+// cfg. The store is nil when the configuration leaves it out.
+func NewRecordingProvider(ctx context.Context, logger *slog.Logger, cfg RecordingConfig) (RecordingClient, RecordingObjectStore, error) {
+	storeName := cmp.Or(cfg.Store, RecordingStoreGCS)
+	newStore, ok := recordingStores[storeName]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown recording store %q (want one of %s)", storeName, registryNames(recordingStores))
+	}
+	providerName := cmp.Or(cfg.Provider, RecordingProviderAgora)
+	newClient, ok := recordingProviders[providerName]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown recording provider %q (want one of %s)", providerName, registryNames(recordingProviders))
+	}
+
+	store, err := newStore(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("recording store %s: %w", storeName, err)
+	}
+	client, err := newClient(logger, cfg, store)
+	if err != nil {
+		return nil, nil, fmt.Errorf("recording provider %s: %w", providerName, err)
+	}
+	return client, store, nil
+}
+
+func registryNames[T any](registry map[string]T) string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// recordingObjectWriter is a store the API can write recordings to: parts
+// from the fake provider and stitched recordings.
+type recordingObjectWriter interface {
+	PutObject(ctx context.Context, objectName string, body io.Reader, size int64) error
+}
+
+// fakeRecordingSample is the start of an MP4 file: enough for discovery and a
+// fake Mux client, not for a real one.
+var fakeRecordingSample = []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2")
+
+// FakeRecordingClient records nothing. Start hands out a handle straight
+// away and Stop writes a sample MP4 under the part's prefix, so the discover
+// and import steps run as they would after a real call. For tests and local
+// development.
+type FakeRecordingClient struct {
+	store  recordingObjectWriter
+	sample []byte
+
+	mu     sync.Mutex
+	starts []StartRecordingRequest
+	stops  []StopRecordingRequest
+}
+
+func NewFakeRecordingClient(store RecordingObjectStore, sample []byte) (*FakeRecordingClient, error) {
+	writer, ok := store.(recordingObjectWriter)
+	if !ok {
+		return nil, errors.New("the fake provider needs a store it can write to")
+	}
+	if len(sample) == 0 {
+		sample = fakeRecordingSample
+	}
+	return &FakeRecordingClient{store: writer, sample: sample}, nil
+}
+
+func (c *FakeRecordingClient) Name() string { return RecordingProviderFake }
+
+func (c *FakeRecordingClient) Start(_ context.Context, req StartRecordingRequest) (StartedRecording, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.starts = append(c.starts, req)
+	return StartedRecording{
+		ResourceID:     "fake_resource_" + req.AttemptID,
+		SID:            req.AttemptID,
+		UID:            req.UID,
+		FileNamePrefix: fakeRecordingPrefix(req.AttemptID),
+		Ready:          true,
+	}, nil
+}
+
+// Stop writes the part's file. It is safe to repeat: the file is replaced.
+func (c *FakeRecordingClient) Stop(ctx context.Context, req StopRecordingRequest) error {
+	c.mu.Lock()
+	c.stops = append(c.stops, req)
+	c.mu.Unlock()
+	objectName := recordingObjectPrefix(fakeRecordingPrefix(req.SID)) + req.SID + ".mp4"
+	return c.store.PutObject(ctx, objectName, bytes.NewReader(c.sample), int64(len(c.sample)))
+}
+
+// Starts returns the start requests made so far.
+func (c *FakeRecordingClient) Starts() []StartRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]StartRecordingRequest(nil), c.starts...)
+}
+
+// Stops returns the stop requests made so far.
+func (c *FakeRecordingClient) Stops() []StopRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]StopRecordingRequest(nil), c.stops...)
+}
+
+func fakeRecordingPrefix(attemptID string) []string {
+	return []string{"fakeRecordings", sanitizeAgoraPathPart(attemptID)}
+}
@@ -0,0 +1,169 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/coaching"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+)
+
+// fetchingMux is a Mux client that downloads each input the way Mux would
+// and reports the asset ready straight away.
+type fetchingMux struct {
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (m *fetchingMux) CreateAsset(req muxgo.CreateAssetRequest) (muxgo.AssetResponse, error) {
+	resp, err := http.Get(req.Input[0].Url)
+	if err != nil {
+		return muxgo.AssetResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return muxgo.AssetResponse{}, fmt.Errorf("fetch %s: %s", req.Input[0].Url, resp.Status)
+	}
+	m.mu.Lock()
+	m.fetched = append(m.fetched, string(body))
+	m.mu.Unlock()
+	return m.GetAsset("mux-asset-1")
+}
+
+func (m *fetchingMux) GetAsset(assetID string) (muxgo.AssetResponse, error) {
+	return muxgo.AssetResponse{Data: muxgo.Asset{
+		Id: assetID, Status: "ready", Duration: 12,
+		PlaybackIds: []muxgo.PlaybackId{{Id: "playback-1", Policy: muxgo.PUBLIC}},
+	}}, nil
+}
+
+func (m *fetchingMux) DeleteAsset(string) error { return nil }
+
+func (m *fetchingMux) Fetched() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.fetched...)
+}
+
+func TestIntegration_FakeRecordingProviderWithLocalStore(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private lesson", DurationMinutes: 60, Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: pgtype.Timestamptz{Time: time.Now().Add(-5 * time.Minute), Valid: true}, DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	// The API serves the local store's files; Mux downloads them from here.
+	var h *coaching.Handler
+	router := chi.NewRouter()
+	router.Get("/public/coaching/recording-files/*", func(w http.ResponseWriter, r *http.Request) { h.ServeRecordingFile(w, r) })
+	api := httptest.NewServer(router)
+	t.Cleanup(api.Close)
+
+	client, store, err := coaching.NewRecordingProvider(ctx, slog.Default(), coaching.RecordingConfig{
+		Provider:   coaching.RecordingProviderFake,
+		Store:      coaching.RecordingStoreLocal,
+		Local:      coaching.LocalRecordingStoreConfig{Dir: t.TempDir(), BaseURL: api.URL, SigningSecret: "secret"},
+		FakeSample: []byte("sample call"),
+	})
+	if err != nil {
+		t.Fatalf("NewRecordingProvider: %v", err)
+	}
+	mux := &fetchingMux{}
+	h = coaching.NewHandler(q, pool, nil, nil, slog.Default(), coaching.HandlerConfig{
+		AgoraAppID:           "970ca35de60c44645bbae8a215061b33",
+		AgoraAppCertificate:  "5cfd2fd1755d40ecb72977518be15d3b",
+		RecordingEnabled:     true,
+		RecordingClient:      client,
+		RecordingStore:       store,
+		RecordingMux:         mux,
+		RecordingPresenceTTL: time.Second,
+		RecordingEmptyGrace:  time.Second,
+		AppBaseURL:           "https://app.example.com",
+	})
+
+	presence := func(state string) {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("bookingID", booking.ID.String())
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(
+			`{"connection_id":"6f9619ff-8b86-d011-b42d-00cf4fc964ff","state":"`+state+`"}`))
+		reqCtx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		req = req.WithContext(context.WithValue(reqCtx, auth.UserKey, &auth.UserContext{ID: "student-1"}))
+		rec := httptest.NewRecorder()
+		h.UpdateBookingPresence(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("presence %s = %d: %s", state, rec.Code, rec.Body.String())
+		}
+	}
+	eventually := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(15 * time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	// The fake provider needs no renderer page, so the part records at once.
+	presence("joined")
+	eventually("the part to start", func() bool {
+		part, err := q.GetActiveRecordingPart(ctx, booking.ID)
+		return err == nil && part.Status == db.CoachingRecordingStatusStarted && part.Provider == coaching.RecordingProviderFake
+	})
+
+	presence("left")
+	eventually("the recording to be imported", func() bool {
+		rec := httptest.NewRecorder()
+		h.CleanupFinishedRecordings(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		got, err := q.GetBooking(ctx, db.GetBookingParams{ID: booking.ID, ExpertID: "expert-1"})
+		return err == nil && got.RecordingAssetID.Valid
+	})
+
+	got, err := q.GetBooking(ctx, db.GetBookingParams{ID: booking.ID, ExpertID: "expert-1"})
+	if err != nil {
+		t.Fatalf("GetBooking: %v", err)
+	}
+	var videos int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM videos WHERE asset_id = $1 AND mux_asset_id = 'mux-asset-1'`, got.RecordingAssetID).Scan(&videos); err != nil || videos != 1 {
+		t.Fatalf("recording videos = %d, %v; want one", videos, err)
+	}
+	if fetched := mux.Fetched(); len(fetched) != 1 || fetched[0] != "sample call" {
+		t.Fatalf("Mux fetched %q, want the part the fake provider wrote", fetched)
+	}
+	if fake := client.(*coaching.FakeRecordingClient); len(fake.Starts()) != 1 || len(fake.Stops()) != 1 {
+		t.Fatalf("fake provider saw %d starts and %d stops, want one of each", len(fake.Starts()), len(fake.Stops()))
+	}
+}
@@ -0,0 +1,80 @@
+package coaching
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewRecordingProviderSelection(t *testing.T) {
+	local := LocalRecordingStoreConfig{Dir: t.TempDir(), BaseURL: "https://api.example.com", SigningSecret: "secret"}
+
+	client, store, err := NewRecordingProvider(t.Context(), slog.Default(), RecordingConfig{})
+	if err != nil || client.Name() != recordingProviderAgoraPage || store != nil {
+		t.Fatalf("default = %v, %v, %v; want the Agora client without a store", client, store, err)
+	}
+
+	client, store, err = NewRecordingProvider(t.Context(), slog.Default(), RecordingConfig{
+		Provider: RecordingProviderFake, Store: RecordingStoreLocal, Local: local,
+	})
+	if err != nil || client.Name() != RecordingProviderFake {
+		t.Fatalf("fake/local = %v, %v; want the fake client", client, err)
+	}
+	if _, ok := store.(*localRecordingObjectStore); !ok {
+		t.Fatalf("fake/local store = %T, want the local store", store)
+	}
+
+	tests := []struct {
+		name string
+		cfg  RecordingConfig
+		want string
+	}{
+		{"unknown provider", RecordingConfig{Provider: "zoom"}, `unknown recording provider "zoom"`},
+		{"unknown store", RecordingConfig{Store: "ftp"}, `unknown recording store "ftp"`},
+		{"fake without writable store", RecordingConfig{Provider: RecordingProviderFake}, "recording provider fake"},
+		{"s3 without bucket", RecordingConfig{Store: RecordingStoreS3}, "recording store s3"},
+		{"local without secret", RecordingConfig{Store: RecordingStoreLocal, Local: LocalRecordingStoreConfig{Dir: t.TempDir()}}, "recording store local"},
+	}
+	for _, tt := range tests {
+		client, store, err := NewRecordingProvider(t.Context(), slog.Default(), tt.cfg)
+		if err == nil || !strings.Contains(err.Error(), tt.want) || client != nil || store != nil {
+			t.Errorf("%s: got %v, %v, %v; want an error containing %q", tt.name, client, store, err, tt.want)
+		}
+	}
+}
+
+func TestFakeRecordingClientWritesPartOnStop(t *testing.T) {
+	store := newTestLocalStore(t)
+	client, err := NewFakeRecordingClient(store, nil)
+	if err != nil {
+		t.Fatalf("NewFakeRecordingClient: %v", err)
+	}
+	started, err := client.Start(t.Context(), StartRecordingRequest{AttemptID: "attempt-1", UID: "42"})
+	if err != nil || !started.Ready || started.SID != "attempt-1" {
+		t.Fatalf("Start = %+v, %v; want a ready part", started, err)
+	}
+	if _, err := store.ListMP4(t.Context(), started.FileNamePrefix); !errors.Is(err, ErrRecordingMP4NotFound) {
+		t.Fatalf("files before Stop: %v, want none", err)
+	}
+
+	if err := client.Stop(t.Context(), StopRecordingRequest{ResourceID: started.ResourceID, SID: started.SID, UID: started.UID}); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	objects, err := store.ListMP4(t.Context(), started.FileNamePrefix)
+	if err != nil || len(objects) != 1 {
+		t.Fatalf("files after Stop = %+v, %v; want one", objects, err)
+	}
+	f, err := store.open(objects[0].Name, "9999999999", store.sign(objects[0].Name, "9999999999"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	if body, _ := io.ReadAll(f); string(body) != string(fakeRecordingSample) {
+		t.Fatalf("written file = %q, want the default sample", body)
+	}
+	if len(client.Starts()) != 1 || len(client.Stops()) != 1 {
+		t.Fatalf("recorded %d starts and %d stops, want one of each", len(client.Starts()), len(client.Stops()))
+	}
+}
@@ -0,0 +1,222 @@
+package coaching
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+// Overall state of a booking's recording, as reported to participants.
+const (
+	recordingStateNone      = "none"      // the call was never recorded
+	recordingStateRecording = "recording" // a part is still being recorded
+	recordingStateImporting = "importing" // files are being found or imported
+	recordingStateStitching = "stitching" // the parts are being joined into one video
+	recordingStateReady     = "ready"     // every part can be watched
+	recordingStatePartial   = "partial"   // some parts could not be imported
+	recordingStateFailed    = "failed"    // nothing could be imported
+)
+
+// recordingDiscoveryWindow is how long after a part stops its files may still
+// turn up in the store.
+const recordingDiscoveryWindow = 15 * time.Minute
+
+type bookingRecordingStatusResponse struct {
+	BookingID string                          `json:"booking_id"`
+	Status    string                          `json:"status"` // "none" | "recording" | "importing" | "stitching" | "ready" | "partial" | "failed"
+	AssetID   string                          `json:"asset_id,omitempty"`
+	Parts     []bookingRecordingPartResponse  `json:"parts"`
+	Stitch    *bookingRecordingStitchResponse `json:"stitch,omitempty"`
+}
+
+type bookingRecordingPartResponse struct {
+	PartNumber int32                          `json:"part_number"`
+	Status     string                         `json:"status"`
+	Error      string                         `json:"error,omitempty"`
+	StartedAt  *time.Time                     `json:"started_at,omitempty"`
+	StoppedAt  *time.Time                     `json:"stopped_at,omitempty"`
+	Files      []bookingRecordingFileResponse `json:"files"`
+}
+
+type bookingRecordingFileResponse struct {
+	FileIndex int32  `json:"file_index"`
+	Status    string `json:"status"`
+	Attempts  int32  `json:"attempts"`
+	Error     string `json:"error,omitempty"`
+	VideoID   string `json:"video_id,omitempty"`
+}
+
+type bookingRecordingStitchResponse struct {
+	Status    string `json:"status"`
+	PartCount int32  `json:"part_count"`
+	Attempts  int32  `json:"attempts"`
+	Error     string `json:"error,omitempty"`
+	VideoID   string `json:"video_id,omitempty"`
+}
+
+// GetBookingRecording handles GET /groups/{groupID}/coaching/bookings/{bookingID}/recording.
+// Reports how far the call's recording has got: each part with the import of
+// its files and, when parts are stitched, the stitch.
+func (h *Handler) GetBookingRecording(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, call, _, ok := h.loadChatCall(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+
+	rows, err := h.q.ListBookingRecordingImports(ctx, call.Host.ID)
+	if err != nil {
+		log.ErrorContext(ctx, "list_booking_recording_imports_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(call.Host.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch recording", http.StatusInternalServerError)
+		return
+	}
+	var stitch *db.CoachingRecordingStitch
+	if found, err := h.q.GetRecordingStitch(ctx, call.Host.ID); err == nil {
+		stitch = &found
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		log.ErrorContext(ctx, "get_recording_stitch_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(call.Host.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch recording", http.StatusInternalServerError)
+		return
+	}
+
+	resp := bookingRecordingStatusResponse{
+		BookingID: uuidToString(call.Host.ID),
+		Status:    bookingRecordingState(rows, stitch, h.recordingStitcher != nil, time.Now()),
+		Parts:     toBookingRecordingParts(rows),
+	}
+	if call.Host.RecordingAssetID.Valid {
+		resp.AssetID = uuidToString(call.Host.RecordingAssetID)
+	}
+	if stitch != nil {
+		resp.Stitch = &bookingRecordingStitchResponse{
+			Status:    string(stitch.Status),
+			PartCount: stitch.PartCount,
+			Attempts:  stitch.Attempts,
+			Error:     textOrEmpty(stitch.Error),
+		}
+		if stitch.VideoID.Valid {
+			resp.Stitch.VideoID = uuidToString(stitch.VideoID)
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// bookingRecordingState sums up a recording from its parts and stitch. A
+// stitch that has given up does not count: the parts are still watchable.
+// stitching tells whether ready parts are yet to be joined.
+func bookingRecordingState(rows []db.ListBookingRecordingImportsRow, stitch *db.CoachingRecordingStitch, stitching bool, now time.Time) string {
+	if len(rows) == 0 {
+		return recordingStateNone
+	}
+	if stitch != nil {
+		switch stitch.Status {
+		case db.CoachingRecordingImportStatusReady:
+			return recordingStateReady
+		case db.CoachingRecordingImportStatusFailed:
+			if stitch.Attempts < recordingStitchMaxAttempts {
+				return recordingStateStitching
+			}
+		default:
+			return recordingStateStitching
+		}
+	}
+
+	var ready, failed, inFlight int
+	for _, row := range rows {
+		switch row.RecordingStatus {
+		case db.CoachingRecordingStatusStarting, db.CoachingRecordingStatusStarted, db.CoachingRecordingStatusStopping:
+			return recordingStateRecording
+		}
+		if !row.ImportStatus.Valid {
+			// No files found for the part (yet).
+			if row.RecordingStatus == db.CoachingRecordingStatusStopped && row.StoppedAt.Valid &&
+				now.Sub(row.StoppedAt.Time) < recordingDiscoveryWindow {
+				inFlight++
+			} else {
+				failed++
+			}
+			continue
+		}
+		switch row.ImportStatus.CoachingRecordingImportStatus {
+		case db.CoachingRecordingImportStatusReady:
+			ready++
+		case db.CoachingRecordingImportStatusFailed:
+			if row.ImportAttempts.Int32 < recordingImportMaxAttempts {
+				inFlight++
+			} else {
+				failed++
+			}
+		default:
+			inFlight++
+		}
+	}
+	switch {
+	case inFlight > 0:
+		return recordingStateImporting
+	case ready == 0:
+		return recordingStateFailed
+	case failed > 0:
+		return recordingStatePartial
+	case stitching && stitch == nil && ready > 1:
+		return recordingStateStitching
+	default:
+		return recordingStateReady
+	}
+}
+
+func toBookingRecordingParts(rows []db.ListBookingRecordingImportsRow) []bookingRecordingPartResponse {
+	parts := make([]bookingRecordingPartResponse, 0, len(rows))
+	for _, row := range rows {
+		if len(parts) == 0 || parts[len(parts)-1].PartNumber != row.PartNumber {
+			part := bookingRecordingPartResponse{
+				PartNumber: row.PartNumber,
+				Status:     string(row.RecordingStatus),
+				Error:      textOrEmpty(row.RecordingError),
+				Files:      []bookingRecordingFileResponse{},
+			}
+			if row.StartedAt.Valid {
+				part.StartedAt = &row.StartedAt.Time
+			}
+			if row.StoppedAt.Valid {
+				part.StoppedAt = &row.StoppedAt.Time
+			}
+			parts = append(parts, part)
+		}
+		if !row.ImportStatus.Valid {
+			continue
+		}
+		file := bookingRecordingFileResponse{
+			FileIndex: row.FileIndex.Int32,
+			Status:    string(row.ImportStatus.CoachingRecordingImportStatus),
+			Attempts:  row.ImportAttempts.Int32,
+			Error:     textOrEmpty(row.ImportError),
+		}
+		if row.VideoID.Valid {
+			file.VideoID = uuidToString(row.VideoID)
+		}
+		part := &parts[len(parts)-1]
+		part.Files = append(part.Files, file)
+	}
+	return parts
+}
@@ -0,0 +1,80 @@
+package coaching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestBookingRecordingState(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	stoppedAt := func(ago time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: now.Add(-ago), Valid: true}
+	}
+	part := func(number int32, status db.CoachingRecordingStatus) db.ListBookingRecordingImportsRow {
+		return db.ListBookingRecordingImportsRow{PartNumber: number, RecordingStatus: status, StoppedAt: stoppedAt(time.Hour)}
+	}
+	imported := func(number int32, status db.CoachingRecordingImportStatus, attempts int32) db.ListBookingRecordingImportsRow {
+		row := part(number, db.CoachingRecordingStatusStopped)
+		row.FileIndex = pgtype.Int4{Int32: 1, Valid: true}
+		row.ImportStatus = db.NullCoachingRecordingImportStatus{CoachingRecordingImportStatus: status, Valid: true}
+		row.ImportAttempts = pgtype.Int4{Int32: attempts, Valid: true}
+		return row
+	}
+	justStopped := part(2, db.CoachingRecordingStatusStopped)
+	justStopped.StoppedAt = stoppedAt(time.Minute)
+	stitchWith := func(status db.CoachingRecordingImportStatus, attempts int32) *db.CoachingRecordingStitch {
+		return &db.CoachingRecordingStitch{Status: status, Attempts: attempts}
+	}
+	ready := db.CoachingRecordingImportStatusReady
+	failed := db.CoachingRecordingImportStatusFailed
+
+	tests := []struct {
+		name      string
+		rows      []db.ListBookingRecordingImportsRow
+		stitch    *db.CoachingRecordingStitch
+		stitching bool
+		want      string
+	}{
+		{"never recorded", nil, nil, false, recordingStateNone},
+		{"part recording", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), part(2, db.CoachingRecordingStatusStarted)}, nil, false, recordingStateRecording},
+		{"files not found yet", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), justStopped}, nil, false, recordingStateImporting},
+		{"import retrying", []db.ListBookingRecordingImportsRow{imported(1, failed, 2)}, nil, false, recordingStateImporting},
+		{"all parts ready", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, ready, 1)}, nil, false, recordingStateReady},
+		{"ready parts awaiting a stitch", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, ready, 1)}, nil, true, recordingStateStitching},
+		{"one part lost", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, failed, 5)}, nil, true, recordingStatePartial},
+		{"no files ever found", []db.ListBookingRecordingImportsRow{part(1, db.CoachingRecordingStatusStopped)}, nil, false, recordingStateFailed},
+		{"stitch running", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, ready, 1)}, stitchWith(db.CoachingRecordingImportStatusProcessing, 1), true, recordingStateStitching},
+		{"stitch retrying", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, ready, 1)}, stitchWith(failed, 1), true, recordingStateStitching},
+		{"stitched", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, ready, 1)}, stitchWith(ready, 1), true, recordingStateReady},
+		{"stitch given up", []db.ListBookingRecordingImportsRow{imported(1, ready, 1), imported(2, ready, 1)}, stitchWith(failed, 3), true, recordingStateReady},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bookingRecordingState(tt.rows, tt.stitch, tt.stitching, now); got != tt.want {
+				t.Fatalf("bookingRecordingState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToBookingRecordingPartsGroupsFiles(t *testing.T) {
+	videoID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	rows := []db.ListBookingRecordingImportsRow{
+		{PartNumber: 1, RecordingStatus: db.CoachingRecordingStatusStopped, FileIndex: pgtype.Int4{Int32: 1, Valid: true},
+			ImportStatus: db.NullCoachingRecordingImportStatus{CoachingRecordingImportStatus: db.CoachingRecordingImportStatusReady, Valid: true}, VideoID: videoID},
+		{PartNumber: 1, RecordingStatus: db.CoachingRecordingStatusStopped, FileIndex: pgtype.Int4{Int32: 2, Valid: true},
+			ImportStatus: db.NullCoachingRecordingImportStatus{CoachingRecordingImportStatus: db.CoachingRecordingImportStatusFailed, Valid: true},
+			ImportError:  pgtype.Text{String: "mux asset errored", Valid: true}},
+		{PartNumber: 2, RecordingStatus: db.CoachingRecordingStatusFailed, RecordingError: pgtype.Text{String: "start failed", Valid: true}},
+	}
+	parts := toBookingRecordingParts(rows)
+	if len(parts) != 2 || len(parts[0].Files) != 2 || len(parts[1].Files) != 0 {
+		t.Fatalf("parts = %+v, want two parts with two and no files", parts)
+	}
+	if parts[0].Files[0].VideoID != uuidToString(videoID) || parts[0].Files[1].Error != "mux asset errored" || parts[1].Error != "start failed" {
+		t.Fatalf("parts = %+v, want video and errors carried over", parts)
+	}
+}
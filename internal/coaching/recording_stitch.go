@@ -0,0 +1,342 @@
+package coaching
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+)
+
+const (
+	maxRecordingStitchesPerRun = int32(2)
+	// recordingStitchMaxAttempts matches the claim query: a stitch that has
+	// failed this often keeps its per-part videos.
+	recordingStitchMaxAttempts = 3
+	// recordingStitchMaxAge bounds how long a stitch may wait on Mux.
+	recordingStitchMaxAge = 24 * time.Hour
+	// recordingImportMaxAttempts matches ClaimPendingRecordingPartImports.
+	recordingImportMaxAttempts = 5
+)
+
+// RecordingStitcher joins the MP4 files of a recording, in order, into one
+// file at outputPath.
+type RecordingStitcher interface {
+	Stitch(ctx context.Context, inputURLs []string, outputPath string) error
+}
+
+type ffmpegRecordingStitcher struct {
+	binary string
+}
+
+// NewFFmpegRecordingStitcher joins files with ffmpeg's concat demuxer. The
+// streams are copied, not re-encoded: every part of a call is recorded with
+// the same settings. binary defaults to ffmpeg on the PATH.
+func NewFFmpegRecordingStitcher(binary string) RecordingStitcher {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+	return &ffmpegRecordingStitcher{binary: binary}
+}
+
+func (s *ffmpegRecordingStitcher) Stitch(ctx context.Context, inputURLs []string, outputPath string) error {
+	if len(inputURLs) == 0 {
+		return errors.New("nothing to stitch")
+	}
+	var list strings.Builder
+	for _, input := range inputURLs {
+		list.WriteString("file '" + strings.ReplaceAll(input, "'", `'\''`) + "'\n")
+	}
+	listPath := outputPath + ".txt"
+	if err := os.WriteFile(listPath, []byte(list.String()), 0o600); err != nil {
+		return err
+	}
+	defer os.Remove(listPath)
+
+	cmd := exec.CommandContext(ctx, s.binary,
+		"-hide_banner", "-loglevel", "error", "-nostdin",
+		"-f", "concat", "-safe", "0", "-protocol_whitelist", "file,http,https,tcp,tls,crypto",
+		"-i", listPath,
+		"-c", "copy", "-movflags", "+faststart",
+		"-y", outputPath,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, truncateRecordingError(strings.TrimSpace(stderr.String())))
+	}
+	return nil
+}
+
+type recordingStitchRunResult struct {
+	Processed int
+	Ready     int
+	Deferred  int
+	Failed    int
+}
+
+// processPendingRecordingStitches queues a stitch for every recording whose
+// parts have all been imported and works on a few of them. It does nothing
+// unless a stitcher is configured.
+func (h *Handler) processPendingRecordingStitches(ctx context.Context, limit int32) (recordingStitchRunResult, error) {
+	var result recordingStitchRunResult
+	if h.recordingStitcher == nil || h.recordingStore == nil || h.recordingMux == nil {
+		return result, nil
+	}
+	if _, err := h.q.EnqueueRecordingStitches(ctx, int32(h.recordingEndGrace/time.Second)); err != nil {
+		return result, err
+	}
+	stitches, err := h.q.ClaimPendingRecordingStitches(ctx, limit)
+	if err != nil {
+		return result, err
+	}
+	result.Processed = len(stitches)
+	for _, stitch := range stitches {
+		ready, deferred, err := h.processRecordingStitch(ctx, stitch)
+		switch {
+		case err != nil:
+			logger.From(ctx, h.logger).WarnContext(ctx, "recording_stitch_failed",
+				slog.String("component", "coaching"), slog.String("booking_id", uuidToString(stitch.BookingID)),
+				slog.Int("attempts", int(stitch.Attempts)), slog.Any("err", err))
+			result.Failed++
+		case ready:
+			result.Ready++
+		case deferred:
+			result.Deferred++
+		}
+	}
+	return result, nil
+}
+
+func (h *Handler) processRecordingStitch(ctx context.Context, stitch db.ClaimPendingRecordingStitchesRow) (ready, deferred bool, err error) {
+	objectName := textOrEmpty(stitch.ObjectName)
+	muxAssetID := textOrEmpty(stitch.MuxAssetID)
+	fail := func(err error) (bool, bool, error) {
+		// No video plays the stitched asset yet, so it is deleted. If Mux
+		// refuses, the stitch keeps its ID and the next attempt tries again.
+		keptAssetID := ""
+		if muxAssetID != "" {
+			if derr := h.recordingMux.DeleteAsset(muxAssetID); derr != nil && !isMuxNotFound(derr) {
+				keptAssetID = muxAssetID
+			}
+		}
+		_ = h.q.MarkRecordingStitchFailed(ctx, db.MarkRecordingStitchFailedParams{
+			BookingID: stitch.BookingID, ObjectName: nullableText(objectName), MuxAssetID: nullableText(keptAssetID),
+			Error: nullableText(truncateRecordingError(err.Error())),
+		})
+		return false, false, err
+	}
+	if stitch.Attempts > recordingStitchMaxAttempts || time.Since(stitch.CreatedAt.Time) > recordingStitchMaxAge {
+		return fail(errors.New("gave up stitching; the parts stay separate videos"))
+	}
+	inputs, err := h.q.ListRecordingStitchInputs(ctx, stitch.BookingID)
+	if err != nil {
+		return false, false, err
+	}
+	if len(inputs) != int(stitch.PartCount) {
+		return fail(fmt.Errorf("the recording has %d part videos, %d when the stitch was queued", len(inputs), stitch.PartCount))
+	}
+
+	playbackID := textOrEmpty(stitch.MuxPlaybackID)
+	if muxAssetID == "" {
+		if objectName == "" {
+			objectName, err = h.stitchRecordingParts(ctx, stitch.BookingID, inputs)
+			if err != nil {
+				return fail(err)
+			}
+		}
+		signedURL, err := h.recordingStore.SignedURL(ctx, objectName, recordingSignedURLTTL)
+		if err != nil {
+			return fail(err)
+		}
+		assetResp, err := h.recordingMux.CreateAsset(muxgo.CreateAssetRequest{
+			Input: []muxgo.InputSettings{{Url: signedURL}}, PlaybackPolicy: []muxgo.PlaybackPolicy{muxgo.PUBLIC},
+			Passthrough: "coaching_recording_stitch:" + uuidToString(stitch.BookingID),
+		})
+		if err != nil {
+			return fail(err)
+		}
+		muxAssetID = assetResp.Data.Id
+		playbackID = publicPlaybackID(assetResp.Data.PlaybackIds)
+		if muxAssetID == "" {
+			return fail(errors.New("mux create asset response did not include asset id"))
+		}
+		if _, err := h.q.MarkRecordingStitchMuxCreated(ctx, db.MarkRecordingStitchMuxCreatedParams{
+			BookingID: stitch.BookingID, ObjectName: nullableText(objectName),
+			MuxAssetID: nullableText(muxAssetID), MuxPlaybackID: nullableText(playbackID),
+		}); err != nil {
+			return false, false, err
+		}
+	}
+
+	assetResp, err := h.recordingMux.GetAsset(muxAssetID)
+	if err != nil {
+		// Mux may be briefly unreachable; the next run asks again.
+		return false, true, nil
+	}
+	if playbackID == "" {
+		playbackID = publicPlaybackID(assetResp.Data.PlaybackIds)
+	}
+	switch strings.ToLower(assetResp.Data.Status) {
+	case "ready":
+		if playbackID == "" {
+			return fail(errors.New("ready mux asset has no public playback id"))
+		}
+		if err := h.finishRecordingStitch(ctx, stitch, inputs, muxAssetID, playbackID, assetResp.Data.Duration); err != nil {
+			return fail(err)
+		}
+		return true, false, nil
+	case "errored":
+		return fail(fmt.Errorf("mux asset %s errored", muxAssetID))
+	default:
+		return false, true, nil
+	}
+}
+
+// stitchRecordingParts joins the part files into one and stores it next to
+// them, returning its object name.
+func (h *Handler) stitchRecordingParts(ctx context.Context, bookingID pgtype.UUID, inputs []db.ListRecordingStitchInputsRow) (string, error) {
+	writer, ok := h.recordingStore.(recordingObjectWriter)
+	if !ok {
+		return "", errors.New("the recording store cannot store stitched recordings")
+	}
+	urls := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		name := textOrEmpty(input.GcsObjectName)
+		if name == "" {
+			return "", fmt.Errorf("part %d has no recording file", input.PartNumber)
+		}
+		signedURL, err := h.recordingStore.SignedURL(ctx, name, recordingSignedURLTTL)
+		if err != nil {
+			return "", err
+		}
+		urls = append(urls, signedURL)
+	}
+
+	dir, err := os.MkdirTemp("", "recording-stitch-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "recording.mp4")
+	if err := h.recordingStitcher.Stitch(ctx, urls, outputPath); err != nil {
+		return "", err
+	}
+	file, err := os.Open(outputPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	objectName := recordingObjectPrefix([]string{"stitchedRecordings", sanitizeAgoraPathPart(uuidToString(bookingID))}) + "recording.mp4"
+	if err := writer.PutObject(ctx, objectName, file, info.Size()); err != nil {
+		return "", err
+	}
+	return objectName, nil
+}
+
+// finishRecordingStitch swaps the part videos for the stitched one. Each part
+// becomes a chapter, and the reviews and chapters left on the parts move to
+// where that part starts in the stitched video.
+func (h *Handler) finishRecordingStitch(ctx context.Context, stitch db.ClaimPendingRecordingStitchesRow, inputs []db.ListRecordingStitchInputsRow, muxAssetID, playbackID string, duration float64) error {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+	booking, err := qtx.GetBookingForRecordingAssetUpdate(ctx, stitch.BookingID)
+	if err != nil {
+		return err
+	}
+	if !booking.RecordingAssetID.Valid {
+		return errors.New("the booking no longer has a recording asset")
+	}
+	video, err := qtx.CreateOrderedVideoFromMuxAsset(ctx, db.CreateOrderedVideoFromMuxAssetParams{
+		AssetID: booking.RecordingAssetID, MuxAssetID: nullableText(muxAssetID), PlaybackID: nullableText(playbackID),
+		SortOrder: pgtype.Int4{Int32: 0, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	starts := recordingStitchChapterStarts(inputs)
+	for i, input := range inputs {
+		end := pgtype.Int4{}
+		if i+1 < len(starts) && starts[i+1] > starts[i] {
+			end = pgtype.Int4{Int32: starts[i+1], Valid: true}
+		}
+		if _, err := qtx.CreateVideoChapter(ctx, db.CreateVideoChapterParams{
+			VideoID: video.ID, Title: recordingStitchChapterTitle(input), StartSeconds: starts[i], EndSeconds: end,
+			CreatedBy: booking.ExpertID,
+		}); err != nil {
+			return err
+		}
+		move := db.MoveVideoReviewsParams{ToVideoID: video.ID, OffsetSeconds: starts[i], FromVideoID: input.VideoID}
+		if _, err := qtx.MoveVideoReviews(ctx, move); err != nil {
+			return err
+		}
+		if _, err := qtx.MoveVideoChapters(ctx, db.MoveVideoChaptersParams(move)); err != nil {
+			return err
+		}
+		if _, err := qtx.SoftDeleteVideo(ctx, db.SoftDeleteVideoParams{
+			ID: input.VideoID, AssetID: booking.RecordingAssetID,
+		}); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+	}
+	if _, err := qtx.MarkRecordingStitchReady(ctx, db.MarkRecordingStitchReadyParams{
+		BookingID: stitch.BookingID, VideoID: video.ID,
+	}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	h.persistImportDuration(ctx, video.ID, duration)
+	logger.From(ctx, h.logger).InfoContext(ctx, "recording_stitch_ready",
+		slog.String("component", "coaching"), slog.String("booking_id", uuidToString(stitch.BookingID)),
+		slog.String("asset_id", uuidToString(booking.RecordingAssetID)), slog.String("video_id", uuidToString(video.ID)),
+		slog.Int("parts", len(inputs)), slog.String("mux_asset_id", muxAssetID))
+	return nil
+}
+
+// recordingStitchChapterStarts returns the second at which each part starts
+// in the stitched video.
+func recordingStitchChapterStarts(inputs []db.ListRecordingStitchInputsRow) []int32 {
+	starts := make([]int32, len(inputs))
+	var offset float64
+	for i, input := range inputs {
+		starts[i] = int32(math.Round(offset))
+		offset += input.DurationSeconds.Float64
+	}
+	return starts
+}
+
+func recordingStitchChapterTitle(input db.ListRecordingStitchInputsRow) string {
+	if input.FileIndex > 1 {
+		return fmt.Sprintf("Part %d (%d)", input.PartNumber, input.FileIndex)
+	}
+	return fmt.Sprintf("Part %d", input.PartNumber)
+}
+
+func isMuxNotFound(err error) bool {
+	var nf muxgo.NotFoundError
+	return errors.As(err, &nf)
+}
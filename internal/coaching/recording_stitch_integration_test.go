@@ -0,0 +1,203 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/coaching"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+)
+
+type copyingStitcher struct{ inputs []string }
+
+func (s *copyingStitcher) Stitch(_ context.Context, inputURLs []string, outputPath string) error {
+	s.inputs = inputURLs
+	return os.WriteFile(outputPath, []byte("stitched"), 0o600)
+}
+
+type readyMux struct{}
+
+func (readyMux) CreateAsset(muxgo.CreateAssetRequest) (muxgo.AssetResponse, error) {
+	return readyMux{}.GetAsset("mux-stitched")
+}
+
+func (readyMux) GetAsset(assetID string) (muxgo.AssetResponse, error) {
+	return muxgo.AssetResponse{Data: muxgo.Asset{
+		Id: assetID, Status: "ready", Duration: 90,
+		PlaybackIds: []muxgo.PlaybackId{{Id: "playback-stitched", Policy: muxgo.PUBLIC}},
+	}}, nil
+}
+
+func (readyMux) DeleteAsset(string) error { return nil }
+
+func TestIntegration_RecordingPartsAreStitchedIntoOneVideo(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private lesson", DurationMinutes: 60, Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: pgtype.Timestamptz{Time: time.Now().Add(-3 * time.Hour), Valid: true}, DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Live coaching recording", GroupID: group.ID, OwnerID: "student-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	if _, err := q.AssignBookingRecordingAsset(ctx, db.AssignBookingRecordingAssetParams{ID: booking.ID, RecordingAssetID: asset.ID}); err != nil {
+		t.Fatalf("AssignBookingRecordingAsset: %v", err)
+	}
+
+	// Two stopped parts, each imported as its own video.
+	var partVideos []pgtype.UUID
+	for part, seconds := range []float64{60, 30} {
+		video, err := q.CreateOrderedVideoFromMuxAsset(ctx, db.CreateOrderedVideoFromMuxAssetParams{
+			AssetID: asset.ID, MuxAssetID: pgtype.Text{String: fmt.Sprintf("mux-part-%d", part+1), Valid: true},
+			PlaybackID: pgtype.Text{String: "playback-part", Valid: true}, SortOrder: pgtype.Int4{Int32: int32(part+1) * 1000, Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("CreateOrderedVideoFromMuxAsset: %v", err)
+		}
+		if err := q.SetVideoDurationByID(ctx, db.SetVideoDurationByIDParams{ID: video.ID, DurationSeconds: pgtype.Float8{Float64: seconds, Valid: true}}); err != nil {
+			t.Fatalf("SetVideoDurationByID: %v", err)
+		}
+		var recordingID pgtype.UUID
+		if err := pool.QueryRow(ctx, `
+			INSERT INTO coaching_booking_recordings (booking_id, part_number, status, provider, started_at, stopped_at)
+			VALUES ($1, $2, 'stopped', 'fake', NOW() - interval '2 hours', NOW() - interval '90 minutes')
+			RETURNING id`, booking.ID, part+1).Scan(&recordingID); err != nil {
+			t.Fatalf("insert recording part: %v", err)
+		}
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO coaching_recording_imports (recording_id, file_index, status, gcs_object_name, video_id, attempts)
+			VALUES ($1, 1, 'ready', $2, $3, 1)`, recordingID, fmt.Sprintf("calls/part%d.mp4", part+1), video.ID); err != nil {
+			t.Fatalf("insert recording import: %v", err)
+		}
+		partVideos = append(partVideos, video.ID)
+	}
+	if _, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{
+		VideoID: partVideos[1], Content: "Watch your footwork", TimestampSeconds: pgtype.Int4{Int32: 10, Valid: true},
+		AuthorID: pgtype.Text{String: "expert-1", Valid: true},
+	}); err != nil {
+		t.Fatalf("CreateVideoReview: %v", err)
+	}
+	if _, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{
+		VideoID: partVideos[1], Content: "Knee angle", TimestampSeconds: pgtype.Int4{Int32: 20, Valid: true},
+		AuthorID:   pgtype.Text{String: "expert-1", Valid: true},
+		Annotation: []byte(`{"start_seconds":18.5,"end_seconds":25,"shapes":[{"type":"circle","points":[{"x":0.5,"y":0.5}],"radius":0.1}]}`),
+	}); err != nil {
+		t.Fatalf("CreateVideoReview with annotation: %v", err)
+	}
+
+	store, err := coaching.NewLocalRecordingObjectStore(coaching.LocalRecordingStoreConfig{
+		Dir: t.TempDir(), BaseURL: "https://api.example.com", SigningSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewLocalRecordingObjectStore: %v", err)
+	}
+	stitcher := &copyingStitcher{}
+	h := coaching.NewHandler(q, pool, nil, nil, slog.Default(), coaching.HandlerConfig{
+		RecordingEnabled:  true,
+		RecordingStore:    store,
+		RecordingMux:      readyMux{},
+		RecordingStitcher: stitcher,
+		RecordingEndGrace: time.Minute,
+	})
+
+	rec := httptest.NewRecorder()
+	h.ProcessRecordingImports(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	var run map[string]int
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil || rec.Code != http.StatusOK || run["stitches_ready"] != 1 {
+		t.Fatalf("ProcessRecordingImports = %d %s, want one stitch ready", rec.Code, rec.Body.String())
+	}
+	if len(stitcher.inputs) != 2 {
+		t.Fatalf("stitcher got %d inputs, want both parts", len(stitcher.inputs))
+	}
+
+	stitch, err := q.GetRecordingStitch(ctx, booking.ID)
+	if err != nil || stitch.Status != db.CoachingRecordingImportStatusReady || !stitch.VideoID.Valid {
+		t.Fatalf("stitch = %+v, %v; want it ready with a video", stitch, err)
+	}
+	chapters, err := q.ListVideoChapters(ctx, stitch.VideoID)
+	if err != nil || len(chapters) != 2 || chapters[0].Title != "Part 1" || chapters[1].Title != "Part 2" || chapters[1].StartSeconds != 60 {
+		t.Fatalf("chapters = %+v, %v; want Part 1 at 0 and Part 2 at 60", chapters, err)
+	}
+	reviews, err := q.ListVideoReviews(ctx, stitch.VideoID)
+	if err != nil || len(reviews) != 2 {
+		t.Fatalf("reviews = %+v, %v; want both part 2 reviews moved", reviews, err)
+	}
+	for _, r := range reviews {
+		if r.Annotation == nil {
+			if r.TimestampSeconds.Int32 != 70 {
+				t.Errorf("review timestamp = %d, want 70", r.TimestampSeconds.Int32)
+			}
+			continue
+		}
+		var layer struct {
+			StartSeconds float64 `json:"start_seconds"`
+			EndSeconds   float64 `json:"end_seconds"`
+		}
+		if err := json.Unmarshal(r.Annotation, &layer); err != nil {
+			t.Fatalf("annotation: %v", err)
+		}
+		if r.TimestampSeconds.Int32 != 80 || layer.StartSeconds != 78.5 || layer.EndSeconds != 85 || r.AnnotationVersion != 1 {
+			t.Errorf("annotated review at %ds, layer %+v, version %d; want 80s, 78.5-85 and version 1",
+				r.TimestampSeconds.Int32, layer, r.AnnotationVersion)
+		}
+	}
+	var liveParts int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM videos WHERE id = ANY($1) AND deleted_at IS NULL`, partVideos).Scan(&liveParts); err != nil || liveParts != 0 {
+		t.Fatalf("part videos still listed = %d, %v; want them replaced", liveParts, err)
+	}
+
+	// Participants see the stitched video as the booking's recording.
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("groupID", group.ID.String())
+	rctx.URLParams.Add("bookingID", booking.ID.String())
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	reqCtx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	req = req.WithContext(context.WithValue(reqCtx, auth.UserKey, &auth.UserContext{ID: "student-1"}))
+	rec = httptest.NewRecorder()
+	h.GetBookingRecording(rec, req)
+	var status struct {
+		Status string `json:"status"`
+		Parts  []struct {
+			PartNumber int32 `json:"part_number"`
+		} `json:"parts"`
+		Stitch struct {
+			VideoID string `json:"video_id"`
+		} `json:"stitch"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GetBookingRecording = %d %s", rec.Code, rec.Body.String())
+	}
+	if status.Status != "ready" || len(status.Parts) != 2 || status.Stitch.VideoID != stitch.VideoID.String() {
+		t.Fatalf("recording status = %+v, want ready with the stitched video", status)
+	}
+}
@@ -0,0 +1,266 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	muxgo "github.com/muxinc/mux-go"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeFFmpeg writes a script that stands in for ffmpeg: it saves its
+// arguments and the concat list, then writes the list as the output file.
+func fakeFFmpeg(t *testing.T, script string) (binary, argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	binary = filepath.Join(dir, "ffmpeg")
+	argsFile = filepath.Join(dir, "args")
+	body := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\n" + script + "\n"
+	if err := os.WriteFile(binary, []byte(body), 0o755); err != nil {
+		t.Fatalf("write fake ffmpeg: %v", err)
+	}
+	return binary, argsFile
+}
+
+func TestFFmpegRecordingStitcherConcatenatesInOrder(t *testing.T) {
+	// The list file follows -i; the output path is the last argument.
+	binary, argsFile := fakeFFmpeg(t, `while [ "$1" != "-i" ]; do shift; done; list="$2"; for out; do :; done; cp "$list" "$out"`)
+	output := filepath.Join(t.TempDir(), "recording.mp4")
+
+	inputs := []string{"https://store.example.com/part1.mp4?sig=a", "https://store.example.com/it's part2.mp4"}
+	if err := NewFFmpegRecordingStitcher(binary).Stitch(t.Context(), inputs, output); err != nil {
+		t.Fatalf("Stitch: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	want := "file 'https://store.example.com/part1.mp4?sig=a'\nfile 'https://store.example.com/it'\\''s part2.mp4'\n"
+	if string(got) != want {
+		t.Fatalf("concat list =\n%s\nwant\n%s", got, want)
+	}
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("read args: %v", err)
+	}
+	for _, arg := range []string{"concat", "-safe", "copy", "+faststart"} {
+		if !strings.Contains(string(args), arg+"\n") {
+			t.Errorf("ffmpeg args missing %q:\n%s", arg, args)
+		}
+	}
+	if _, err := os.Stat(output + ".txt"); !os.IsNotExist(err) {
+		t.Errorf("concat list left behind: %v", err)
+	}
+}
+
+func TestFFmpegRecordingStitcherReportsStderr(t *testing.T) {
+	binary, _ := fakeFFmpeg(t, `echo "Invalid data found when processing input" >&2; exit 1`)
+	err := NewFFmpegRecordingStitcher(binary).Stitch(t.Context(), []string{"https://a"}, filepath.Join(t.TempDir(), "out.mp4"))
+	if err == nil || !strings.Contains(err.Error(), "Invalid data found") {
+		t.Fatalf("Stitch error = %v, want ffmpeg's message", err)
+	}
+}
+
+func TestRecordingStitchChapterStarts(t *testing.T) {
+	duration := func(seconds float64) pgtype.Float8 { return pgtype.Float8{Float64: seconds, Valid: true} }
+	inputs := []db.ListRecordingStitchInputsRow{
+		{PartNumber: 1, FileIndex: 1, DurationSeconds: duration(61.4)},
+		{PartNumber: 2, FileIndex: 1, DurationSeconds: duration(30.3)},
+		{PartNumber: 2, FileIndex: 2, DurationSeconds: duration(10)},
+	}
+	starts := recordingStitchChapterStarts(inputs)
+	if len(starts) != 3 || starts[0] != 0 || starts[1] != 61 || starts[2] != 92 {
+		t.Fatalf("chapter starts = %v, want [0 61 92]", starts)
+	}
+	if got := recordingStitchChapterTitle(inputs[2]); got != "Part 2 (2)" {
+		t.Fatalf("chapter title = %q, want Part 2 (2)", got)
+	}
+}
+
+type fakeStitcher struct {
+	inputs []string
+	err    error
+}
+
+func (s *fakeStitcher) Stitch(_ context.Context, inputURLs []string, outputPath string) error {
+	s.inputs = inputURLs
+	if s.err != nil {
+		return s.err
+	}
+	return os.WriteFile(outputPath, []byte("stitched"), 0o600)
+}
+
+type stubMux struct {
+	created   []muxgo.CreateAssetRequest
+	deleted   []string
+	deleteErr error
+	status    string
+}
+
+func (m *stubMux) CreateAsset(req muxgo.CreateAssetRequest) (muxgo.AssetResponse, error) {
+	m.created = append(m.created, req)
+	return muxgo.AssetResponse{Data: muxgo.Asset{Id: "mux-stitched", Status: "preparing"}}, nil
+}
+
+func (m *stubMux) GetAsset(assetID string) (muxgo.AssetResponse, error) {
+	return muxgo.AssetResponse{Data: muxgo.Asset{
+		Id: assetID, Status: m.status,
+		PlaybackIds: []muxgo.PlaybackId{{Id: "playback-stitched", Policy: muxgo.PUBLIC}},
+	}}, nil
+}
+
+func (m *stubMux) DeleteAsset(assetID string) error {
+	m.deleted = append(m.deleted, assetID)
+	return m.deleteErr
+}
+
+func stitchInputs() []db.ListRecordingStitchInputsRow {
+	return []db.ListRecordingStitchInputsRow{
+		{PartNumber: 1, FileIndex: 1, GcsObjectName: pgtype.Text{String: "calls/p1/a.mp4", Valid: true}, DurationSeconds: pgtype.Float8{Float64: 60, Valid: true}},
+		{PartNumber: 2, FileIndex: 1, GcsObjectName: pgtype.Text{String: "calls/p2/b.mp4", Valid: true}, DurationSeconds: pgtype.Float8{Float64: 30, Valid: true}},
+	}
+}
+
+func TestProcessRecordingStitchUploadsJoinedFileToMux(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	store := newTestLocalStore(t)
+	stitcher := &fakeStitcher{}
+	mux := &stubMux{status: "preparing"}
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{
+		RecordingStore: store, RecordingMux: mux, RecordingStitcher: stitcher,
+	})
+	bookingID := pgtype.UUID{Bytes: [16]byte{0xab, 0xcd}, Valid: true}
+
+	q.EXPECT().ListRecordingStitchInputs(gomock.Any(), bookingID).Return(stitchInputs(), nil)
+	var objectName string
+	q.EXPECT().MarkRecordingStitchMuxCreated(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.MarkRecordingStitchMuxCreatedParams) (db.CoachingRecordingStitch, error) {
+			objectName = arg.ObjectName.String
+			if arg.MuxAssetID.String != "mux-stitched" {
+				t.Errorf("stored Mux asset %q, want mux-stitched", arg.MuxAssetID.String)
+			}
+			return db.CoachingRecordingStitch{}, nil
+		})
+
+	ready, deferred, err := h.processRecordingStitch(t.Context(), db.ClaimPendingRecordingStitchesRow{
+		BookingID: bookingID, PartCount: 2, Attempts: 1,
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil || ready || !deferred {
+		t.Fatalf("processRecordingStitch = %v, %v, %v; want deferred until Mux is ready", ready, deferred, err)
+	}
+	if len(stitcher.inputs) != 2 || !strings.Contains(stitcher.inputs[0], "calls/p1/a.mp4") || !strings.Contains(stitcher.inputs[1], "calls/p2/b.mp4") {
+		t.Fatalf("stitched %v, want both parts in order", stitcher.inputs)
+	}
+	if !strings.HasPrefix(objectName, "stitchedRecordings/") {
+		t.Fatalf("stitched object %q, want it under stitchedRecordings/", objectName)
+	}
+	if data, err := os.ReadFile(filepath.Join(store.root, filepath.FromSlash(objectName))); err != nil || string(data) != "stitched" {
+		t.Fatalf("stored stitched file = %q, %v", data, err)
+	}
+	if len(mux.created) != 1 || mux.created[0].Passthrough != "coaching_recording_stitch:"+uuidToString(bookingID) {
+		t.Fatalf("Mux assets created = %+v, want one for the stitch", mux.created)
+	}
+}
+
+func TestProcessRecordingStitchFallsBackWhenStitchingFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	mux := &stubMux{}
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{
+		RecordingStore: newTestLocalStore(t), RecordingMux: mux,
+		RecordingStitcher: &fakeStitcher{err: errors.New("ffmpeg: codec mismatch")},
+	})
+	bookingID := pgtype.UUID{Bytes: [16]byte{0xab}, Valid: true}
+
+	q.EXPECT().ListRecordingStitchInputs(gomock.Any(), bookingID).Return(stitchInputs(), nil)
+	q.EXPECT().MarkRecordingStitchFailed(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg db.MarkRecordingStitchFailedParams) error {
+			if !strings.Contains(arg.Error.String, "codec mismatch") {
+				t.Errorf("stitch error = %q, want the stitcher's", arg.Error.String)
+			}
+			return nil
+		})
+
+	_, _, err := h.processRecordingStitch(t.Context(), db.ClaimPendingRecordingStitchesRow{
+		BookingID: bookingID, PartCount: 2, Attempts: 1,
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err == nil {
+		t.Fatal("processRecordingStitch succeeded, want the stitcher's error")
+	}
+	if len(mux.created) != 0 {
+		t.Fatalf("Mux assets created = %d, want none", len(mux.created))
+	}
+}
+
+func TestProcessRecordingStitchGivesUpWhenPartsChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{
+		RecordingStore: newTestLocalStore(t), RecordingMux: &stubMux{}, RecordingStitcher: &fakeStitcher{},
+	})
+	bookingID := pgtype.UUID{Bytes: [16]byte{0xcd}, Valid: true}
+
+	q.EXPECT().ListRecordingStitchInputs(gomock.Any(), bookingID).Return(stitchInputs()[:1], nil)
+	q.EXPECT().MarkRecordingStitchFailed(gomock.Any(), gomock.Any()).Return(nil)
+
+	if _, _, err := h.processRecordingStitch(t.Context(), db.ClaimPendingRecordingStitchesRow{
+		BookingID: bookingID, PartCount: 2, Attempts: 1,
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}); err == nil {
+		t.Fatal("processRecordingStitch succeeded with a part missing")
+	}
+}
+
+func TestProcessRecordingStitchDeletesErroredMuxAsset(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		deleteErr error
+		wantKept  string
+	}{
+		{name: "deleted"},
+		{name: "delete fails", deleteErr: errors.New("mux unavailable"), wantKept: "mux-stitched"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			mux := &stubMux{status: "errored", deleteErr: tc.deleteErr}
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{
+				RecordingStore: newTestLocalStore(t), RecordingMux: mux, RecordingStitcher: &fakeStitcher{},
+			})
+			bookingID := pgtype.UUID{Bytes: [16]byte{0xef}, Valid: true}
+
+			q.EXPECT().ListRecordingStitchInputs(gomock.Any(), bookingID).Return(stitchInputs(), nil)
+			q.EXPECT().MarkRecordingStitchFailed(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, arg db.MarkRecordingStitchFailedParams) error {
+					if arg.MuxAssetID.String != tc.wantKept {
+						t.Errorf("kept Mux asset %q, want %q", arg.MuxAssetID.String, tc.wantKept)
+					}
+					return nil
+				})
+
+			if _, _, err := h.processRecordingStitch(t.Context(), db.ClaimPendingRecordingStitchesRow{
+				BookingID: bookingID, PartCount: 2, Attempts: 2,
+				ObjectName: pgtype.Text{String: "stitchedRecordings/x/recording.mp4", Valid: true},
+				MuxAssetID: pgtype.Text{String: "mux-stitched", Valid: true},
+				CreatedAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			}); err == nil {
+				t.Fatal("processRecordingStitch succeeded with an errored Mux asset")
+			}
+			if len(mux.deleted) != 1 || mux.deleted[0] != "mux-stitched" {
+				t.Fatalf("Mux assets deleted = %v, want the stitched one", mux.deleted)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"sort"
@@ -112,6 +113,18 @@ func (s *gcsRecordingObjectStore) SignedURL(ctx context.Context, objectName stri
 	})
 }
 
+// PutObject uploads body as objectName. The object only becomes visible once
+// the writer is closed.
+func (s *gcsRecordingObjectStore) PutObject(ctx context.Context, objectName string, body io.Reader, _ int64) error {
+	w := s.client.Bucket(s.bucket).Object(objectName).NewWriter(ctx)
+	w.ContentType = "video/mp4"
+	if _, err := io.Copy(w, body); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
 func (s *gcsRecordingObjectStore) signingServiceAccountEmail(ctx context.Context) (string, error) {
 	if s.serviceAccountEmail != "" {
 		return s.serviceAccountEmail, nil
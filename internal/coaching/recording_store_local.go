@@ -0,0 +1,224 @@
+package coaching
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// localRecordingFilesPath is where the API serves the files of a local store
+// to whoever holds a signed URL, Mux among them.
+const localRecordingFilesPath = "/public/coaching/recording-files/"
+
+var errRecordingFileURLInvalid = errors.New("recording file url is invalid or expired")
+
+// LocalRecordingStoreConfig keeps recordings in a directory on the API host,
+// for self-hosted deployments and tests. Signed URLs point back at this API.
+type LocalRecordingStoreConfig struct {
+	Dir           string
+	BaseURL       string // public base URL of this API
+	SigningSecret string
+}
+
+type localRecordingObjectStore struct {
+	root    string
+	baseURL string
+	secret  []byte
+	now     func() time.Time
+}
+
+func NewLocalRecordingObjectStore(cfg LocalRecordingStoreConfig) (RecordingObjectStore, error) {
+	store, err := newLocalRecordingObjectStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func newLocalRecordingObjectStore(cfg LocalRecordingStoreConfig) (*localRecordingObjectStore, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("local recording directory is not configured")
+	}
+	if cfg.BaseURL == "" || cfg.SigningSecret == "" {
+		return nil, errors.New("local recording store needs a base URL and a signing secret")
+	}
+	root, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &localRecordingObjectStore{
+		root:    root,
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		secret:  []byte(cfg.SigningSecret),
+		now:     time.Now,
+	}, nil
+}
+
+func (s *localRecordingObjectStore) FindMP4(ctx context.Context, prefix []string) (RecordingObject, error) {
+	objects, err := s.ListMP4(ctx, prefix)
+	if err != nil {
+		return RecordingObject{}, err
+	}
+	return objects[0], nil
+}
+
+func (s *localRecordingObjectStore) ListMP4(_ context.Context, prefix []string) ([]RecordingObject, error) {
+	objectPrefix := recordingObjectPrefix(prefix)
+	if objectPrefix == "" {
+		return nil, errors.New("recording file prefix is empty")
+	}
+	dir, err := s.path(objectPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []RecordingObject
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(strings.ToLower(d.Name()), ".mp4") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, RecordingObject{Name: filepath.ToSlash(rel), Size: info.Size(), Updated: info.ModTime()})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("%w under prefix %q", ErrRecordingMP4NotFound, objectPrefix)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+// SignedURL returns a URL of ServeRecordingFile that stops working after ttl.
+func (s *localRecordingObjectStore) SignedURL(_ context.Context, objectName string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = recordingSignedURLTTL
+	}
+	if _, err := s.path(objectName); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	return s.baseURL + localRecordingFilesPath + s3Escape(objectName, false) +
+		"?expires=" + expires + "&signature=" + s.sign(objectName, expires), nil
+}
+
+// PutObject writes body as objectName. The file appears under its name only
+// once complete, so discovery never picks up a partial recording.
+func (s *localRecordingObjectStore) PutObject(_ context.Context, objectName string, body io.Reader, _ int64) error {
+	path, err := s.path(objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// open returns the file behind a signed URL.
+func (s *localRecordingObjectStore) open(objectName, expires, signature string) (*os.File, error) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > unix {
+		return nil, errRecordingFileURLInvalid
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, errRecordingFileURLInvalid
+	}
+	want, _ := hex.DecodeString(s.sign(objectName, expires))
+	if !hmac.Equal(got, want) {
+		return nil, errRecordingFileURLInvalid
+	}
+	path, err := s.path(objectName)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *localRecordingObjectStore) sign(objectName, expires string) string {
+	return hex.EncodeToString(hmacSHA256(s.secret, objectName+"\n"+expires))
+}
+
+// path maps an object name to a file below the root, refusing names that
+// would leave it.
+func (s *localRecordingObjectStore) path(objectName string) (string, error) {
+	name := strings.TrimSuffix(objectName, "/")
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("invalid recording object name %q", objectName)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(name)), nil
+}
+
+// ServeRecordingFile handles GET /public/coaching/recording-files/*. It serves
+// a file of the local recording store to the holder of a signed URL, which is
+// how Mux fetches recordings that never left the API host.
+func (h *Handler) ServeRecordingFile(w http.ResponseWriter, r *http.Request) {
+	store, ok := h.recordingStore.(*localRecordingObjectStore)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	objectName, err := url.PathUnescape(chi.URLParam(r, "*"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	query := r.URL.Query()
+	f, err := store.open(objectName, query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
@@ -0,0 +1,115 @@
+package coaching
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func newTestLocalStore(t *testing.T) *localRecordingObjectStore {
+	t.Helper()
+	store, err := newLocalRecordingObjectStore(LocalRecordingStoreConfig{
+		Dir: t.TempDir(), BaseURL: "https://api.example.com/", SigningSecret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("newLocalRecordingObjectStore: %v", err)
+	}
+	return store
+}
+
+func TestLocalStoreListsWrittenFiles(t *testing.T) {
+	store := newTestLocalStore(t)
+	ctx := t.Context()
+	for _, name := range []string{"calls/b1/part 2.mp4", "calls/b1/part_1.mp4", "calls/b1/index.m3u8", "calls/b2/other.mp4"} {
+		if err := store.PutObject(ctx, name, strings.NewReader("data"), 4); err != nil {
+			t.Fatalf("PutObject(%q): %v", name, err)
+		}
+	}
+
+	objects, err := store.ListMP4(ctx, []string{"calls", "b1"})
+	if err != nil {
+		t.Fatalf("ListMP4: %v", err)
+	}
+	if len(objects) != 2 || objects[0].Name != "calls/b1/part 2.mp4" || objects[1].Name != "calls/b1/part_1.mp4" || objects[0].Size != 4 {
+		t.Fatalf("ListMP4 = %+v, want the two MP4 files of b1", objects)
+	}
+	if _, err := store.ListMP4(ctx, []string{"calls", "b3"}); !errors.Is(err, ErrRecordingMP4NotFound) {
+		t.Fatalf("ListMP4 of a missing prefix = %v, want ErrRecordingMP4NotFound", err)
+	}
+}
+
+func TestLocalStoreRefusesNamesOutsideRoot(t *testing.T) {
+	store := newTestLocalStore(t)
+	for _, name := range []string{"../escape.mp4", "/etc/passwd", "calls/../../escape.mp4", ""} {
+		if err := store.PutObject(t.Context(), name, strings.NewReader("x"), 1); err == nil {
+			t.Errorf("PutObject(%q) succeeded, want it refused", name)
+		}
+		if _, err := store.SignedURL(t.Context(), name, time.Minute); err == nil {
+			t.Errorf("SignedURL(%q) succeeded, want it refused", name)
+		}
+	}
+	if _, err := store.ListMP4(t.Context(), []string{".."}); err == nil {
+		t.Fatal("ListMP4 of a parent directory succeeded, want it refused")
+	}
+}
+
+func TestServeRecordingFileChecksSignature(t *testing.T) {
+	store := newTestLocalStore(t)
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	h := NewHandler(nil, nil, nil, nil, slog.Default(), HandlerConfig{RecordingStore: store})
+	r := chi.NewRouter()
+	r.Get(localRecordingFilesPath+"*", h.ServeRecordingFile)
+
+	const name = "calls/b1/part 1.mp4"
+	if err := store.PutObject(t.Context(), name, strings.NewReader("mp4 bytes"), 9); err != nil {
+		t.Fatalf("PutObject: %v", err)
+	}
+	signed, err := store.SignedURL(t.Context(), name, time.Hour)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if !strings.HasPrefix(signed, "https://api.example.com/public/coaching/recording-files/calls/b1/part%201.mp4?expires=") {
+		t.Fatalf("SignedURL = %s, want a file URL of this API", signed)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+	rec := get(u.RequestURI())
+	if rec.Code != http.StatusOK || rec.Body.String() != "mp4 bytes" || rec.Header().Get("Content-Type") != "video/mp4" {
+		t.Fatalf("signed GET = %d %q (%s), want the file", rec.Code, rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+
+	tampered := *u
+	tampered.Path = localRecordingFilesPath + "calls/b1/other.mp4"
+	query := u.Query()
+	query.Set("expires", "9999999999")
+	for _, target := range []string{
+		tampered.RequestURI(),
+		localRecordingFilesPath + "calls/b1/part%201.mp4?" + query.Encode(),
+		localRecordingFilesPath + "calls/b1/part%201.mp4",
+	} {
+		if rec := get(target); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", target, rec.Code)
+		}
+	}
+
+	now = now.Add(2 * time.Hour)
+	if rec := get(u.RequestURI()); rec.Code != http.StatusNotFound {
+		t.Fatalf("expired GET = %d, want 404", rec.Code)
+	}
+}
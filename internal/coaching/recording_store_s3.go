@@ -0,0 +1,292 @@
+package coaching
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3DefaultRegion    = "us-east-1"
+	s3MaxPresignTTL    = 7 * 24 * time.Hour
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	// s3RequestTimeout bounds listing requests; uploads run as long as the
+	// caller's context allows.
+	s3RequestTimeout = 30 * time.Second
+)
+
+// S3RecordingStoreConfig points at an S3-compatible bucket: AWS S3, MinIO or
+// any other service speaking the S3 API with Signature Version 4.
+type S3RecordingStoreConfig struct {
+	Endpoint        string // e.g. https://s3.eu-central-1.amazonaws.com or http://localhost:9000
+	Region          string // default: us-east-1, which is also what MinIO expects
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket in the path instead of the host name,
+	// as MinIO and most self-hosted services require.
+	PathStyle bool
+}
+
+type s3RecordingObjectStore struct {
+	httpClient *http.Client
+	cfg        S3RecordingStoreConfig
+	endpoint   *url.URL
+	now        func() time.Time
+}
+
+func NewS3RecordingObjectStore(cfg S3RecordingStoreConfig) (RecordingObjectStore, error) {
+	store, err := newS3RecordingObjectStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func newS3RecordingObjectStore(cfg S3RecordingStoreConfig) (*s3RecordingObjectStore, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("recording storage bucket is not configured")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3 recording store credentials are not configured")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = s3DefaultRegion
+	}
+	return &s3RecordingObjectStore{
+		httpClient: &http.Client{},
+		cfg:        cfg,
+		endpoint:   endpoint,
+		now:        time.Now,
+	}, nil
+}
+
+func (s *s3RecordingObjectStore) FindMP4(ctx context.Context, prefix []string) (RecordingObject, error) {
+	objects, err := s.ListMP4(ctx, prefix)
+	if err != nil {
+		return RecordingObject{}, err
+	}
+	return objects[0], nil
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3RecordingObjectStore) ListMP4(ctx context.Context, prefix []string) ([]RecordingObject, error) {
+	objectPrefix := recordingObjectPrefix(prefix)
+	if objectPrefix == "" {
+		return nil, errors.New("recording file prefix is empty")
+	}
+
+	var objects []RecordingObject
+	query := map[string]string{"list-type": "2", "prefix": objectPrefix}
+	for {
+		body, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page s3ListBucketResult
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("decode s3 object list: %w", err)
+		}
+		for _, c := range page.Contents {
+			if strings.HasSuffix(strings.ToLower(c.Key), ".mp4") {
+				objects = append(objects, RecordingObject{Name: c.Key, Size: c.Size, Updated: c.LastModified})
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		query["continuation-token"] = page.NextContinuationToken
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("%w under prefix %q", ErrRecordingMP4NotFound, objectPrefix)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+// SignedURL returns a presigned GET URL, which S3 caps at seven days.
+func (s *s3RecordingObjectStore) SignedURL(_ context.Context, objectName string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = recordingSignedURLTTL
+	}
+	ttl = min(ttl, s3MaxPresignTTL)
+
+	u := s.objectURL(objectName)
+	amzDate, scope := s.credentialScope(s.now())
+	query := map[string]string{
+		"X-Amz-Algorithm":     s3SigningAlgorithm,
+		"X-Amz-Credential":    s.cfg.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(ttl / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	canonicalQuery := s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet, u.RawPath, canonicalQuery, "host:" + u.Host + "\n", "host", s3UnsignedPayload,
+	}, "\n")
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(amzDate, scope, canonicalRequest)
+	return u.String(), nil
+}
+
+// PutObject streams body, size bytes long, to objectName. The payload is left
+// out of the signature so it does not have to be read twice.
+func (s *s3RecordingObjectStore) PutObject(ctx context.Context, objectName string, body io.Reader, size int64) error {
+	req, err := s.newRequest(ctx, http.MethodPut, objectName, nil, body, s3UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "video/mp4")
+	_, err = s.send(req, objectName)
+	return err
+}
+
+// do sends a small request and returns the response body. An empty key
+// addresses the bucket itself.
+func (s *s3RecordingObjectStore) do(ctx context.Context, method, key string, query map[string]string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s3RequestTimeout)
+	defer cancel()
+	req, err := s.newRequest(ctx, method, key, query, bytes.NewReader(body), sha256Hex(body))
+	if err != nil {
+		return nil, err
+	}
+	return s.send(req, key)
+}
+
+// newRequest builds a request signed in the Authorization header.
+func (s *s3RecordingObjectStore) newRequest(ctx context.Context, method, key string, query map[string]string, body io.Reader, payloadHash string) (*http.Request, error) {
+	u := s.objectURL(key)
+	u.RawQuery = s3CanonicalQuery(query)
+	amzDate, scope := s.credentialScope(s.now())
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + u.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		method, u.RawPath, u.RawQuery, canonicalHeaders, signedHeaders, payloadHash,
+	}, "\n")
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, s.cfg.AccessKeyID, scope, signedHeaders, s.signature(amzDate, scope, canonicalRequest)))
+	return req, nil
+}
+
+func (s *s3RecordingObjectStore) send(req *http.Request, key string) ([]byte, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("s3 %s %q returned %d: %s", req.Method, key, resp.StatusCode, truncateRecordingError(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// objectURL addresses key in the bucket. RawPath holds the path encoded the
+// way Signature Version 4 expects, so it is both signed and sent verbatim.
+func (s *s3RecordingObjectStore) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	path := "/" + key
+	if s.cfg.PathStyle {
+		path = "/" + s.cfg.Bucket + path
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path = strings.TrimRight(s.endpoint.Path, "/") + path
+	u.RawPath = s3Escape(u.Path, false)
+	return &u
+}
+
+func (s *s3RecordingObjectStore) credentialScope(now time.Time) (amzDate, scope string) {
+	amzDate = now.UTC().Format("20060102T150405Z")
+	return amzDate, amzDate[:8] + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func (s *s3RecordingObjectStore) signature(amzDate, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{s3SigningAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	key := []byte("AWS4" + s.cfg.SecretAccessKey)
+	for _, part := range []string{amzDate[:8], s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func s3CanonicalQuery(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, s3Escape(k, true)+"="+s3Escape(query[k], true))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent-encodes everything but the unreserved characters and,
+// unless encodeSlash is set, the path separator.
+func s3Escape(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -18,25 +18,26 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
-func TestParticipantUIDForBooking(t *testing.T) {
+func TestBookingCallParticipant(t *testing.T) {
 	booking := db.CoachingBooking{
 		StudentID: "student-1",
 		ExpertID:  "expert-1",
 	}
+	call := bookingCall{Host: booking, Participants: oneToOneParticipants(booking.StudentID, booking.ExpertID)}
 
-	if got := participantUIDForBooking("student-1", booking); got != 1 {
-		t.Fatalf("student uid = %d, want 1", got)
+	if got, ok := call.participant("student-1"); !ok || got.UID != 1 || got.Role != "student" {
+		t.Fatalf("student participant = %+v, %v; want uid 1 as student", got, ok)
 	}
-	if got := participantUIDForBooking("expert-1", booking); got != 2 {
-		t.Fatalf("expert uid = %d, want 2", got)
+	if got, ok := call.participant("expert-1"); !ok || got.UID != 2 || got.Role != "expert" {
+		t.Fatalf("expert participant = %+v, %v; want uid 2 as expert", got, ok)
 	}
-
-	selfBooking := db.CoachingBooking{StudentID: "admin-1", ExpertID: "admin-1"}
-	if got := participantUIDForBooking("admin-1", selfBooking); got != 2 {
-		t.Fatalf("self-booking admin uid = %d, want expert uid 2", got)
+	if _, ok := call.participant("outsider"); ok {
+		t.Fatal("outsider joined the call")
 	}
-	if got := participantRoleForBooking("admin-1", selfBooking); got != "expert" {
-		t.Fatalf("self-booking admin role = %q, want expert", got)
+
+	selfCall := bookingCall{Participants: oneToOneParticipants("admin-1", "admin-1")}
+	if got, _ := selfCall.participant("admin-1"); got.UID != 2 || got.Role != "expert" {
+		t.Fatalf("self-booking admin = %+v, want expert uid 2", got)
 	}
 }
 
@@ -17,7 +17,7 @@ import (
 
 // ProcessReminders is an internal endpoint called by Cloud Scheduler.
 // It finds all pending reminders whose remind_at <= now, sends emails,
-// and marks them as sent.
+// and marks them as sent. Homework reminders go to the student alone.
 func (h *Handler) ProcessReminders(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx, h.logger)
@@ -34,6 +34,27 @@ func (h *Handler) ProcessReminders(w http.ResponseWriter, r *http.Request) {
 
 	sent := 0
 	for _, rem := range reminders {
+		if rem.HomeworkID.Valid {
+			// Homework due-date reminder — only the student is emailed, and
+			// homework done in the meantime needs no reminder.
+			if rem.HomeworkCompletedAt.Valid {
+				_ = h.q.MarkReminderSent(ctx, rem.ID)
+				continue
+			}
+			if err := h.sendHomeworkReminder(ctx, rem); err != nil {
+				continue // retried on the next poll
+			}
+			if err := h.q.MarkReminderSent(ctx, rem.ID); err != nil {
+				log.ErrorContext(ctx, "mark_reminder_sent_failed",
+					slog.String("component", "coaching"),
+					slog.String("reminder_id", uuidToString(rem.ID)),
+					slog.Any("err", err),
+				)
+			}
+			sent++
+			continue
+		}
+
 		if rem.IsCancelled {
 			// Booking was cancelled — just mark as sent so we skip it.
 			_ = h.q.MarkReminderSent(ctx, rem.ID)
@@ -45,10 +66,8 @@ func (h *Handler) ProcessReminders(w http.ResponseWriter, r *http.Request) {
 		isImminent := diff <= 20*time.Minute
 
 		var joinURL string
-		if isImminent && h.appBaseURL != "" {
-			groupID := uuidToString(rem.GroupID)
-			bookingID := uuidToString(rem.BookingID)
-			joinURL = h.appBaseURL + "/sessions/" + groupID + "/" + bookingID + "/call"
+		if isImminent {
+			joinURL = h.bookingCallURL(rem.GroupID, rem.BookingID)
 		}
 
 		// Send a separate, language-aware email per recipient.
@@ -0,0 +1,492 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// errRescheduleSlotTaken reports that the proposed slot stopped being open
+// (another booking, an absence, busy calendar time or changed availability)
+// before the proposal was accepted.
+var errRescheduleSlotTaken = errors.New("proposed slot is taken")
+
+// --- DTO ---
+
+type rescheduleResponse struct {
+	ID                  string     `json:"id"`
+	BookingID           string     `json:"booking_id"`
+	ProposedBy          string     `json:"proposed_by"`
+	ScheduledAt         time.Time  `json:"scheduled_at"`
+	PreviousScheduledAt time.Time  `json:"previous_scheduled_at"`
+	Note                *string    `json:"note,omitempty"`
+	Status              string     `json:"status"` // "pending" | "accepted" | "declined" | "withdrawn"
+	RespondedBy         *string    `json:"responded_by,omitempty"`
+	RespondedAt         *time.Time `json:"responded_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// pendingRescheduleResponse is attached to a booking while a proposal awaits an
+// answer, so clients can show who has to respond.
+type pendingRescheduleResponse struct {
+	ID          string    `json:"id"`
+	ProposedBy  string    `json:"proposed_by"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+func toRescheduleResponse(p db.CoachingBookingReschedule) rescheduleResponse {
+	resp := rescheduleResponse{
+		ID:                  uuidToString(p.ID),
+		BookingID:           uuidToString(p.BookingID),
+		ProposedBy:          p.ProposedBy,
+		ScheduledAt:         p.ScheduledAt.Time,
+		PreviousScheduledAt: p.PreviousScheduledAt.Time,
+		Status:              string(p.Status),
+		CreatedAt:           p.CreatedAt.Time,
+	}
+	if p.Note.Valid {
+		resp.Note = &p.Note.String
+	}
+	if p.RespondedBy.Valid {
+		resp.RespondedBy = &p.RespondedBy.String
+	}
+	if p.RespondedAt.Valid {
+		resp.RespondedAt = &p.RespondedAt.Time
+	}
+	return resp
+}
+
+// buildPendingReschedule maps the LEFT JOINed proposal columns of the booking
+// list queries; it returns nil when the booking has no pending proposal.
+func buildPendingReschedule(id pgtype.UUID, proposedBy pgtype.Text, scheduledAt pgtype.Timestamptz) *pendingRescheduleResponse {
+	if !id.Valid {
+		return nil
+	}
+	return &pendingRescheduleResponse{
+		ID:          uuidToString(id),
+		ProposedBy:  proposedBy.String,
+		ScheduledAt: scheduledAt.Time,
+	}
+}
+
+// --- Handlers ---
+
+type proposeRescheduleRequest struct {
+	ScheduledAt string  `json:"scheduled_at"` // RFC3339
+	Note        *string `json:"note,omitempty"`
+}
+
+// ProposeReschedule lets either participant propose a new start time. A new
+// proposal replaces any pending one, which is how counter-proposals work.
+func (h *Handler) ProposeReschedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	bookingID, err := parseUUID(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	var req proposeRescheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
+	if err != nil {
+		http.Error(w, "Invalid scheduled_at (use RFC3339)", http.StatusBadRequest)
+		return
+	}
+
+	existing, ok := h.loadReschedulableBooking(ctx, w, bookingID, user.ID)
+	if !ok {
+		return
+	}
+
+	if scheduledAt.Equal(existing.ScheduledAt.Time) {
+		http.Error(w, "scheduled_at must differ from the current session time", http.StatusBadRequest)
+		return
+	}
+
+	rules, ok := h.loadBookingRules(ctx, w, existing)
+	if !ok {
+		return
+	}
+	if msg := rules.checkStart(time.Now(), scheduledAt); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	open, err := h.isOpenSlot(ctx, h.q, existing, rules, scheduledAt)
+	if err != nil {
+		log.ErrorContext(ctx, "reschedule_slot_check_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(existing.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to check availability", http.StatusInternalServerError)
+		return
+	}
+	if !open {
+		http.Error(w, "Time slot is not available", http.StatusConflict)
+		return
+	}
+
+	var note pgtype.Text
+	if req.Note != nil && *req.Note != "" {
+		note = pgtype.Text{String: *req.Note, Valid: true}
+	}
+
+	proposal, err := h.createRescheduleProposal(ctx, existing, db.CreateBookingRescheduleParams{
+		BookingID:           existing.ID,
+		ProposedBy:          user.ID,
+		ScheduledAt:         pgtype.Timestamptz{Time: scheduledAt, Valid: true},
+		PreviousScheduledAt: existing.ScheduledAt,
+		Note:                note,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			http.Error(w, "Another proposal was just made for this booking", http.StatusConflict)
+			return
+		}
+		log.ErrorContext(ctx, "propose_reschedule_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(existing.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to propose new time", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendRescheduleProposedEmail(ctx, existing, proposal)
+	h.recordRescheduleProposedNotification(existing, proposal)
+
+	writeJSON(w, http.StatusCreated, toRescheduleResponse(proposal))
+}
+
+// AcceptReschedule moves the booking to the pending proposal's time. Only the
+// participant who did not propose may accept. The slot is re-validated because
+// availability and other bookings may have changed since the proposal.
+func (h *Handler) AcceptReschedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	bookingID, err := parseUUID(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, ok := h.loadReschedulableBooking(ctx, w, bookingID, user.ID)
+	if !ok {
+		return
+	}
+	proposal, ok := h.loadPendingReschedule(ctx, w, existing)
+	if !ok {
+		return
+	}
+	if proposal.ProposedBy == user.ID {
+		http.Error(w, "Only the other participant can accept this proposal", http.StatusForbidden)
+		return
+	}
+
+	rules, ok := h.loadBookingRules(ctx, w, existing)
+	if !ok {
+		return
+	}
+	newStart := proposal.ScheduledAt.Time
+	if msg := rules.checkStart(time.Now(), newStart); msg != "" {
+		http.Error(w, "Proposed time is no longer available: "+msg, http.StatusConflict)
+		return
+	}
+
+	updated, err := h.acceptReschedule(ctx, existing, proposal, rules, user.ID)
+	if err != nil {
+		var ruleErr *bookingRuleError
+		switch {
+		case errors.Is(err, errRescheduleSlotTaken):
+			http.Error(w, "Proposed time is no longer available", http.StatusConflict)
+		case errors.As(err, &ruleErr):
+			http.Error(w, "Proposed time is no longer available: "+ruleErr.msg, http.StatusConflict)
+		case errors.Is(err, pgx.ErrNoRows):
+			http.Error(w, "Booking or proposal has changed", http.StatusConflict)
+		default:
+			log.ErrorContext(ctx, "accept_reschedule_failed",
+				slog.String("component", "coaching"),
+				slog.String("booking_id", uuidToString(existing.ID)),
+				slog.Any("err", err),
+			)
+			http.Error(w, "Failed to reschedule booking", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.scheduleReminders(ctx, updated)
+	h.sendRescheduledEmail(ctx, updated, existing.ScheduledAt.Time)
+	h.recordRescheduledNotification(updated, existing.ScheduledAt.Time, user.ID)
+	h.fillFromWaitlist(existing.ExpertID, existing.ScheduledAt.Time,
+		existing.ScheduledAt.Time.Add(time.Duration(existing.DurationMinutes)*time.Minute))
+
+	users, err := h.resolveUsers(ctx, []string{updated.ExpertID, updated.StudentID})
+	if err != nil {
+		log.ErrorContext(ctx, "resolve_booking_users_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to resolve booking users", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBookingResponse(updated, users, ""))
+}
+
+// DeclineReschedule closes the pending proposal and keeps the current time.
+// The other participant declines it; the proposer withdraws it.
+func (h *Handler) DeclineReschedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	bookingID, err := parseUUID(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, ok := h.loadReschedulableBooking(ctx, w, bookingID, user.ID)
+	if !ok {
+		return
+	}
+	proposal, ok := h.loadPendingReschedule(ctx, w, existing)
+	if !ok {
+		return
+	}
+
+	status := db.CoachingRescheduleStatusDeclined
+	if proposal.ProposedBy == user.ID {
+		status = db.CoachingRescheduleStatusWithdrawn
+	}
+	updated, err := h.q.RespondToBookingReschedule(ctx, db.RespondToBookingRescheduleParams{
+		ID:          proposal.ID,
+		Status:      status,
+		RespondedBy: pgtype.Text{String: user.ID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Proposal has already been answered", http.StatusConflict)
+			return
+		}
+		log.ErrorContext(ctx, "decline_reschedule_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(existing.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to decline proposal", http.StatusInternalServerError)
+		return
+	}
+
+	if status == db.CoachingRescheduleStatusDeclined {
+		h.recordRescheduleDeclinedNotification(existing, updated)
+	}
+
+	writeJSON(w, http.StatusOK, toRescheduleResponse(updated))
+}
+
+// --- Helpers ---
+
+// loadReschedulableBooking fetches a booking the caller participates in and
+// writes the error response when it cannot be moved any more.
+func (h *Handler) loadReschedulableBooking(ctx context.Context, w http.ResponseWriter, bookingID pgtype.UUID, userID string) (db.CoachingBooking, bool) {
+	// GetBooking is scoped to expert_id OR student_id — ensures caller is a participant.
+	b, err := h.q.GetBooking(ctx, db.GetBookingParams{ID: bookingID, ExpertID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Booking not found", http.StatusNotFound)
+			return db.CoachingBooking{}, false
+		}
+		http.Error(w, "Failed to fetch booking", http.StatusInternalServerError)
+		return db.CoachingBooking{}, false
+	}
+	if b.IsCancelled {
+		http.Error(w, "Booking is already cancelled", http.StatusConflict)
+		return db.CoachingBooking{}, false
+	}
+	if !time.Now().Before(b.ScheduledAt.Time) {
+		http.Error(w, "Booking has already started", http.StatusConflict)
+		return db.CoachingBooking{}, false
+	}
+	// A seat moves only with its class; the student cancels and books another.
+	if b.ClassID.Valid {
+		http.Error(w, "Class bookings cannot be rescheduled", http.StatusBadRequest)
+		return db.CoachingBooking{}, false
+	}
+	return b, true
+}
+
+// loadPendingReschedule fetches the booking's pending proposal and writes a 404
+// when there is none.
+func (h *Handler) loadPendingReschedule(ctx context.Context, w http.ResponseWriter, b db.CoachingBooking) (db.CoachingBookingReschedule, bool) {
+	p, err := h.q.GetPendingBookingReschedule(ctx, b.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "No pending reschedule proposal", http.StatusNotFound)
+			return db.CoachingBookingReschedule{}, false
+		}
+		logger.From(ctx, h.logger).ErrorContext(ctx, "get_pending_reschedule_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch proposal", http.StatusInternalServerError)
+		return db.CoachingBookingReschedule{}, false
+	}
+	return p, true
+}
+
+// isOpenSlot reports whether start is a slot computeSlots would offer for the
+// booking's expert under rules. The booking itself is ignored so a session can
+// move into a slot that overlaps its current time.
+func (h *Handler) isOpenSlot(ctx context.Context, q db.Querier, b db.CoachingBooking, rules bookingRules, start time.Time) (bool, error) {
+	open, err := h.openSlotStarts(ctx, q, b.ExpertID, b.GroupID, rules, b.ID, start, start)
+	if err != nil {
+		return false, err
+	}
+	return open[start.Unix()], nil
+}
+
+// createRescheduleProposal withdraws any pending proposal for the booking and
+// inserts the new one in one transaction.
+func (h *Handler) createRescheduleProposal(ctx context.Context, b db.CoachingBooking, arg db.CreateBookingRescheduleParams) (db.CoachingBookingReschedule, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingBookingReschedule{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+	if _, err := qtx.WithdrawPendingBookingReschedule(ctx, db.WithdrawPendingBookingRescheduleParams{
+		BookingID:   b.ID,
+		RespondedBy: pgtype.Text{String: arg.ProposedBy, Valid: true},
+	}); err != nil {
+		return db.CoachingBookingReschedule{}, err
+	}
+	proposal, err := qtx.CreateBookingReschedule(ctx, arg)
+	if err != nil {
+		return db.CoachingBookingReschedule{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBookingReschedule{}, err
+	}
+	return proposal, nil
+}
+
+// acceptReschedule runs acceptRescheduleTx, retrying up to 3× on serialization
+// failure like CreateBooking.
+func (h *Handler) acceptReschedule(ctx context.Context, existing db.CoachingBooking, proposal db.CoachingBookingReschedule, rules bookingRules, userID string) (db.CoachingBooking, error) {
+	const maxRetries = 3
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		var updated db.CoachingBooking
+		updated, err = h.acceptRescheduleTx(ctx, existing, proposal, rules, userID)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "40001" {
+			continue
+		}
+		return updated, err
+	}
+	return db.CoachingBooking{}, err
+}
+
+// acceptRescheduleTx re-checks conflicts, the buffer and daily-cap rules and
+// that the slot is still open (availability, absences, external busy time),
+// closes the proposal, moves the booking, drops its unsent reminders and
+// records booking.rescheduled in one SERIALIZABLE transaction. The booking ID
+// never changes.
+func (h *Handler) acceptRescheduleTx(ctx context.Context, existing db.CoachingBooking, proposal db.CoachingBookingReschedule, rules bookingRules, userID string) (db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	var slotEnd pgtype.Timestamptz
+	_ = slotEnd.Scan(proposal.ScheduledAt.Time.Add(time.Duration(existing.DurationMinutes) * time.Minute))
+	conflicts, err := qtx.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
+		ExpertID:      existing.ExpertID,
+		ScheduledAt:   proposal.ScheduledAt,
+		ScheduledAt_2: slotEnd,
+		ID:            existing.ID,
+	})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if conflicts > 0 {
+		return db.CoachingBooking{}, errRescheduleSlotTaken
+	}
+	msg, err := checkBookingRulesInTx(ctx, qtx, rules, existing.ExpertID, proposal.ScheduledAt.Time, existing.ID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if msg != "" {
+		return db.CoachingBooking{}, &bookingRuleError{msg: msg}
+	}
+	open, err := h.isOpenSlot(ctx, qtx, existing, rules, proposal.ScheduledAt.Time)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if !open {
+		return db.CoachingBooking{}, errRescheduleSlotTaken
+	}
+
+	if _, err := qtx.RespondToBookingReschedule(ctx, db.RespondToBookingRescheduleParams{
+		ID:          proposal.ID,
+		Status:      db.CoachingRescheduleStatusAccepted,
+		RespondedBy: pgtype.Text{String: userID, Valid: true},
+	}); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	updated, err := qtx.RescheduleBooking(ctx, db.RescheduleBookingParams{
+		ID:          existing.ID,
+		ScheduledAt: proposal.ScheduledAt,
+	})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := qtx.DeleteUnsentBookingReminders(ctx, existing.ID); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingRescheduled, updated, &existing)); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,253 @@
+package coaching
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/email"
+	"github.com/OZIOisgood/zeta/internal/i18n"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/OZIOisgood/zeta/internal/preferences"
+)
+
+// otherParticipant returns the booking participant who is not userID.
+func otherParticipant(b db.CoachingBooking, userID string) string {
+	if userID == b.StudentID {
+		return b.ExpertID
+	}
+	return b.StudentID
+}
+
+// bookingLabels resolves the session type and group names shown in reschedule
+// and series emails and notifications. Lookup failures are logged and leave the name empty.
+func (h *Handler) bookingLabels(ctx context.Context, b db.CoachingBooking) (sessionTypeName, groupName string) {
+	log := logger.From(ctx, h.logger)
+	if st, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{ID: b.SessionTypeID, GroupID: b.GroupID}); err != nil {
+		log.WarnContext(ctx, "booking_labels_fetch_session_type_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+	} else {
+		sessionTypeName = st.Name
+	}
+	if group, err := h.q.GetGroup(ctx, b.GroupID); err != nil {
+		log.WarnContext(ctx, "booking_labels_fetch_group_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+	} else {
+		groupName = group.Name
+	}
+	return sessionTypeName, groupName
+}
+
+// sendRescheduleProposedEmail asks the participant who did not propose to
+// answer the proposal.
+func (h *Handler) sendRescheduleProposedEmail(ctx context.Context, b db.CoachingBooking, p db.CoachingBookingReschedule) {
+	log := logger.From(ctx, h.logger)
+
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	proposer := h.resolveParticipant(ctx, p.ProposedBy)
+	recipientID := otherParticipant(b, p.ProposedBy)
+	recipient := h.resolveParticipant(ctx, recipientID)
+	if recipient.email == "" {
+		return
+	}
+	if !preferences.AllowsUserEmail(ctx, h.q, h.logger, recipientID, preferences.EmailCategoryCoachingBookingUpdates) {
+		log.InfoContext(ctx, "reschedule_proposed_email_skipped_by_preferences",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.String("user_id", recipientID),
+		)
+		return
+	}
+
+	localization := h.resolveRecipientLocalization(ctx, recipientID)
+	loc := localization.localizer
+	note := ""
+	if p.Note.Valid && p.Note.String != "" {
+		note = i18n.T(loc, "email.booking_reschedule_proposed.note", map[string]any{"Note": p.Note.String})
+	}
+	subject := i18n.T(loc, "email.booking_reschedule_proposed.subject")
+	message := email.Message{
+		Copy: email.Copy{
+			Preheader: i18n.T(loc, "email.booking_reschedule_proposed.preheader"),
+			Title:     i18n.T(loc, "email.booking_reschedule_proposed.title"),
+			Intro: i18n.T(loc, "email.booking_reschedule_proposed.intro", map[string]any{
+				"ProposerName": proposer.name,
+				"SessionName":  sessionTypeName,
+				"GroupName":    groupName,
+				"ScheduledAt":  formatEmailDateTime(b.ScheduledAt.Time, localization),
+				"ProposedAt":   formatEmailDateTime(p.ScheduledAt.Time, localization),
+			}),
+			Note: note,
+		},
+	}
+
+	if err := h.emailService.SendTemplate([]string{recipient.email}, subject, email.TemplateNotification, message); err != nil {
+		log.ErrorContext(ctx, "reschedule_proposed_email_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	log.InfoContext(ctx, "reschedule_proposed_email_sent",
+		slog.String("component", "coaching"),
+		slog.String("booking_id", uuidToString(b.ID)),
+		slog.String("proposed_by", p.ProposedBy),
+		slog.String("proposed_at", p.ScheduledAt.Time.UTC().Format(time.RFC3339)),
+	)
+}
+
+// sendRescheduledEmail tells both participants about the new time.
+func (h *Handler) sendRescheduledEmail(ctx context.Context, b db.CoachingBooking, previous time.Time) {
+	log := logger.From(ctx, h.logger)
+
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	expert := h.resolveParticipant(ctx, b.ExpertID)
+	student := h.resolveParticipant(ctx, b.StudentID)
+	// Same UID with a higher SEQUENCE moves the existing calendar entry.
+	invitation := h.bookingInvitation(icsMethodRequest, b, sessionTypeName, groupName, expert, student)
+
+	type emailTarget struct {
+		userID  string
+		addr    string
+		partner string
+	}
+	targets := []emailTarget{
+		{userID: b.StudentID, addr: student.email, partner: expert.name},
+		{userID: b.ExpertID, addr: expert.email, partner: student.name},
+	}
+
+	for _, t := range targets {
+		if t.addr == "" {
+			continue
+		}
+		if !preferences.AllowsUserEmail(ctx, h.q, h.logger, t.userID, preferences.EmailCategoryCoachingBookingUpdates) {
+			log.InfoContext(ctx, "rescheduled_email_skipped_by_preferences",
+				slog.String("component", "coaching"),
+				slog.String("booking_id", uuidToString(b.ID)),
+				slog.String("user_id", t.userID),
+			)
+			continue
+		}
+		localization := h.resolveRecipientLocalization(ctx, t.userID)
+		loc := localization.localizer
+		subject := i18n.T(loc, "email.booking_rescheduled.subject")
+		message := email.Message{
+			Copy: email.Copy{
+				Preheader: i18n.T(loc, "email.booking_rescheduled.preheader"),
+				Title:     i18n.T(loc, "email.booking_rescheduled.title"),
+				Intro: i18n.T(loc, "email.booking_rescheduled.intro", map[string]any{
+					"SessionName":         sessionTypeName,
+					"PartnerName":         t.partner,
+					"GroupName":           groupName,
+					"PreviousScheduledAt": formatEmailDateTime(previous, localization),
+					"ScheduledAt":         formatEmailDateTime(b.ScheduledAt.Time, localization),
+					"Duration":            formatEmailDuration(b.DurationMinutes, localization),
+				}),
+			},
+			Attachments: []email.Attachment{invitation},
+		}
+		if err := h.emailService.SendTemplate([]string{t.addr}, subject, email.TemplateNotification, message); err != nil {
+			log.ErrorContext(ctx, "rescheduled_email_failed",
+				slog.String("component", "coaching"),
+				slog.String("user_id", t.userID),
+				slog.Any("err", err),
+			)
+			continue
+		}
+		log.InfoContext(ctx, "rescheduled_email_sent",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.String("user_id", t.userID),
+			slog.String("scheduled_at", b.ScheduledAt.Time.UTC().Format(time.RFC3339)),
+		)
+	}
+}
+
+// recordRescheduleProposedNotification notifies the participant who has to
+// answer the proposal. Runs detached from the request context.
+func (h *Handler) recordRescheduleProposedNotification(b db.CoachingBooking, p db.CoachingBookingReschedule) {
+	go h.writeRescheduleProposedNotification(context.Background(), b, p)
+}
+
+func (h *Handler) writeRescheduleProposedNotification(ctx context.Context, b db.CoachingBooking, p db.CoachingBookingReschedule) {
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	actor := h.resolveParticipant(ctx, p.ProposedBy)
+
+	notifications.Record(ctx, h.q, h.logger, otherParticipant(b, p.ProposedBy), notifications.TypeCoachingBookingRescheduleProposed,
+		notifications.CoachingBookingRescheduleProposedPayload{
+			BookingID:           uuidToString(b.ID),
+			GroupID:             uuidToString(b.GroupID),
+			GroupName:           groupName,
+			ActorName:           actor.name,
+			SessionName:         sessionTypeName,
+			ScheduledAt:         formatNotificationTime(b.ScheduledAt.Time),
+			ProposedScheduledAt: formatNotificationTime(p.ScheduledAt.Time),
+			DurationMinutes:     int(b.DurationMinutes),
+		})
+}
+
+// recordRescheduleDeclinedNotification tells the proposer their proposal was
+// declined. Runs detached from the request context.
+func (h *Handler) recordRescheduleDeclinedNotification(b db.CoachingBooking, p db.CoachingBookingReschedule) {
+	go h.writeRescheduleDeclinedNotification(context.Background(), b, p)
+}
+
+func (h *Handler) writeRescheduleDeclinedNotification(ctx context.Context, b db.CoachingBooking, p db.CoachingBookingReschedule) {
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	actor := h.resolveParticipant(ctx, otherParticipant(b, p.ProposedBy))
+
+	notifications.Record(ctx, h.q, h.logger, p.ProposedBy, notifications.TypeCoachingBookingRescheduleDeclined,
+		notifications.CoachingBookingRescheduleDeclinedPayload{
+			BookingID:           uuidToString(b.ID),
+			GroupID:             uuidToString(b.GroupID),
+			GroupName:           groupName,
+			ActorName:           actor.name,
+			SessionName:         sessionTypeName,
+			ScheduledAt:         formatNotificationTime(b.ScheduledAt.Time),
+			ProposedScheduledAt: formatNotificationTime(p.ScheduledAt.Time),
+			DurationMinutes:     int(b.DurationMinutes),
+		})
+}
+
+// recordRescheduledNotification notifies both participants of the new time;
+// acceptedByID is named as the actor. Runs detached from the request context.
+func (h *Handler) recordRescheduledNotification(b db.CoachingBooking, previous time.Time, acceptedByID string) {
+	go h.writeRescheduledNotification(context.Background(), b, previous, acceptedByID)
+}
+
+func (h *Handler) writeRescheduledNotification(ctx context.Context, b db.CoachingBooking, previous time.Time, acceptedByID string) {
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	actor := h.resolveParticipant(ctx, acceptedByID)
+
+	payload := notifications.CoachingBookingRescheduledPayload{
+		BookingID:           uuidToString(b.ID),
+		GroupID:             uuidToString(b.GroupID),
+		GroupName:           groupName,
+		ActorName:           actor.name,
+		SessionName:         sessionTypeName,
+		ScheduledAt:         formatNotificationTime(b.ScheduledAt.Time),
+		PreviousScheduledAt: formatNotificationTime(previous),
+		DurationMinutes:     int(b.DurationMinutes),
+	}
+	for _, recipientID := range []string{b.StudentID, b.ExpertID} {
+		notifications.Record(ctx, h.q, h.logger, recipientID, notifications.TypeCoachingBookingRescheduled, payload)
+	}
+}
+
+// formatNotificationTime renders t as RFC3339 UTC, or "" for the zero time.
+func formatNotificationTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
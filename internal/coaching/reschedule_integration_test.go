@@ -0,0 +1,189 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/coaching"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_RescheduleKeepsBookingAndSinglePendingProposal(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	at := func(hours int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Now().Add(time.Duration(hours) * time.Hour).Truncate(time.Hour), Valid: true}
+	}
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID,
+		SessionTypeID: sessionType.ID, ScheduledAt: at(48), DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	if err := q.CreateBookingReminder(ctx, db.CreateBookingReminderParams{BookingID: booking.ID, RemindAt: at(47)}); err != nil {
+		t.Fatalf("CreateBookingReminder: %v", err)
+	}
+
+	// Moving half an hour overlaps the booking's own slot only.
+	newStart := pgtype.Timestamptz{Time: booking.ScheduledAt.Time.Add(30 * time.Minute), Valid: true}
+	newEnd := pgtype.Timestamptz{Time: newStart.Time.Add(time.Hour), Valid: true}
+	conflictArg := db.CountConflictingBookingsParams{ExpertID: "expert-1", ScheduledAt: newStart, ScheduledAt_2: newEnd}
+	if n, err := q.CountConflictingBookings(ctx, conflictArg); err != nil || n != 1 {
+		t.Fatalf("CountConflictingBookings without exclusion = %d, %v; want 1", n, err)
+	}
+	conflictArg.ID = booking.ID
+	if n, err := q.CountConflictingBookings(ctx, conflictArg); err != nil || n != 0 {
+		t.Fatalf("CountConflictingBookings excluding the booking = %d, %v; want 0", n, err)
+	}
+
+	first, err := q.CreateBookingReschedule(ctx, db.CreateBookingRescheduleParams{
+		BookingID: booking.ID, ProposedBy: "student-1", ScheduledAt: newStart, PreviousScheduledAt: booking.ScheduledAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateBookingReschedule: %v", err)
+	}
+	if _, err := q.CreateBookingReschedule(ctx, db.CreateBookingRescheduleParams{
+		BookingID: booking.ID, ProposedBy: "expert-1", ScheduledAt: at(72), PreviousScheduledAt: booking.ScheduledAt,
+	}); err == nil {
+		t.Fatal("second pending proposal was accepted; want the unique index to reject it")
+	}
+
+	// A counter-proposal withdraws the pending one first.
+	if n, err := q.WithdrawPendingBookingReschedule(ctx, db.WithdrawPendingBookingRescheduleParams{
+		BookingID: booking.ID, RespondedBy: pgtype.Text{String: "expert-1", Valid: true},
+	}); err != nil || n != 1 {
+		t.Fatalf("WithdrawPendingBookingReschedule = %d, %v", n, err)
+	}
+	counter, err := q.CreateBookingReschedule(ctx, db.CreateBookingRescheduleParams{
+		BookingID: booking.ID, ProposedBy: "expert-1", ScheduledAt: newStart, PreviousScheduledAt: booking.ScheduledAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateBookingReschedule counter: %v", err)
+	}
+
+	rows, err := q.ListMyBookings(ctx, db.ListMyBookingsParams{ExpertID: "student-1", GroupID: group.ID})
+	if err != nil || len(rows) != 1 || rows[0].RescheduleID != counter.ID || rows[0].RescheduleProposedBy.String != "expert-1" {
+		t.Fatalf("ListMyBookings = %+v, %v; want the counter-proposal attached", rows, err)
+	}
+
+	if _, err := q.RespondToBookingReschedule(ctx, db.RespondToBookingRescheduleParams{
+		ID: first.ID, Status: db.CoachingRescheduleStatusAccepted, RespondedBy: pgtype.Text{String: "expert-1", Valid: true},
+	}); err == nil {
+		t.Error("accepted a withdrawn proposal")
+	}
+	if _, err := q.RespondToBookingReschedule(ctx, db.RespondToBookingRescheduleParams{
+		ID: counter.ID, Status: db.CoachingRescheduleStatusAccepted, RespondedBy: pgtype.Text{String: "student-1", Valid: true},
+	}); err != nil {
+		t.Fatalf("RespondToBookingReschedule: %v", err)
+	}
+	moved, err := q.RescheduleBooking(ctx, db.RescheduleBookingParams{ID: booking.ID, ScheduledAt: newStart})
+	if err != nil || moved.ID != booking.ID || !moved.ScheduledAt.Time.Equal(newStart.Time) {
+		t.Fatalf("RescheduleBooking = %+v, %v", moved, err)
+	}
+	if err := q.DeleteUnsentBookingReminders(ctx, booking.ID); err != nil {
+		t.Fatalf("DeleteUnsentBookingReminders: %v", err)
+	}
+	var reminders int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM coaching_booking_reminders WHERE booking_id = $1`, booking.ID).Scan(&reminders); err != nil || reminders != 0 {
+		t.Fatalf("reminders after reschedule = %d, %v; want 0", reminders, err)
+	}
+
+	rows, err = q.ListMyBookings(ctx, db.ListMyBookingsParams{ExpertID: "student-1", GroupID: group.ID})
+	if err != nil || len(rows) != 1 || rows[0].RescheduleID.Valid {
+		t.Fatalf("ListMyBookings = %+v, %v; want no pending proposal", rows, err)
+	}
+}
+
+// An absence taken after a proposal was made closes the slot; accepting it
+// must fail rather than move the session into the absence.
+func TestIntegration_AcceptRescheduleRechecksOpenSlot(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60, Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+	for day := range int16(7) {
+		if _, err := q.CreateAvailability(ctx, db.CreateAvailabilityParams{
+			ExpertID: "expert-1", GroupID: group.ID, DayOfWeek: day,
+			StartTime: pgtype.Time{Microseconds: 0, Valid: true},
+			EndTime:   pgtype.Time{Microseconds: int64(23 * time.Hour / time.Microsecond), Valid: true},
+		}); err != nil {
+			t.Fatalf("CreateAvailability: %v", err)
+		}
+	}
+
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: pgtype.Timestamptz{Time: day.Add(9 * time.Hour), Valid: true}, DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	proposal, err := q.CreateBookingReschedule(ctx, db.CreateBookingRescheduleParams{
+		BookingID: booking.ID, ProposedBy: "student-1", PreviousScheduledAt: booking.ScheduledAt,
+		ScheduledAt: pgtype.Timestamptz{Time: day.Add(12 * time.Hour), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateBookingReschedule: %v", err)
+	}
+	var date pgtype.Date
+	_ = date.Scan(day.Format("2006-01-02"))
+	if _, err := q.CreateAbsence(ctx, db.CreateAbsenceParams{ExpertID: "expert-1", StartsOn: date, EndsOn: date}); err != nil {
+		t.Fatalf("CreateAbsence: %v", err)
+	}
+
+	h := coaching.NewHandler(q, pool, nil, nil, slog.Default(), coaching.HandlerConfig{})
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("bookingID", booking.ID.String())
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	reqCtx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	req = req.WithContext(context.WithValue(reqCtx, auth.UserKey, &auth.UserContext{ID: "expert-1"}))
+	rec := httptest.NewRecorder()
+	h.AcceptReschedule(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("AcceptReschedule = %d %s, want 409", rec.Code, rec.Body.String())
+	}
+	got, err := q.GetPendingBookingReschedule(ctx, booking.ID)
+	if err != nil || got.ID != proposal.ID {
+		t.Errorf("pending proposal = %+v, %v; want it kept", got, err)
+	}
+	var at time.Time
+	if err := pool.QueryRow(ctx, `SELECT scheduled_at FROM coaching_bookings WHERE id = $1`, booking.ID).Scan(&at); err != nil || !at.Equal(booking.ScheduledAt.Time) {
+		t.Errorf("scheduled_at = %s, %v; want unchanged", at, err)
+	}
+}
@@ -0,0 +1,372 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	authmocks "github.com/OZIOisgood/zeta/internal/auth/mocks"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/email"
+	emailmocks "github.com/OZIOisgood/zeta/internal/email/mocks"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/workos/workos-go/v4/pkg/usermanagement"
+	"go.uber.org/mock/gomock"
+)
+
+const rescheduleTestBookingID = "11111111-1111-1111-1111-111111111111"
+
+// rescheduleMonday is far enough ahead to clear any minimum booking notice.
+var rescheduleMonday = time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC)
+
+func rescheduleBooking(t *testing.T) db.CoachingBooking {
+	t.Helper()
+	var id pgtype.UUID
+	if err := id.Scan(rescheduleTestBookingID); err != nil {
+		t.Fatalf("scan id: %v", err)
+	}
+	return db.CoachingBooking{
+		ID:              id,
+		ExpertID:        "expert-1",
+		StudentID:       "student-1",
+		GroupID:         id,
+		SessionTypeID:   id,
+		ScheduledAt:     pgtype.Timestamptz{Time: rescheduleMonday.Add(10 * time.Hour), Valid: true},
+		DurationMinutes: 60,
+	}
+}
+
+func rescheduleRequest(body, userID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("bookingID", rescheduleTestBookingID)
+	req := httptest.NewRequest(http.MethodPut, "/bookings/"+rescheduleTestBookingID+"/reschedule", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(context.WithValue(ctx, auth.UserKey, &auth.UserContext{ID: userID}))
+}
+
+// expectMondayMorning serves 09:00–12:00 UTC availability on Mondays with the
+// booking itself at 10:00 and another booking at 11:00.
+func expectMondayMorning(t *testing.T, q *dbmocks.MockQuerier, b db.CoachingBooking) {
+	t.Helper()
+	start, _ := parseTime("09:00")
+	end, _ := parseTime("12:00")
+	var otherID pgtype.UUID
+	if err := otherID.Scan("22222222-2222-2222-2222-222222222222"); err != nil {
+		t.Fatalf("scan id: %v", err)
+	}
+	q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("UTC", nil)
+	q.EXPECT().ListAvailabilityByExpertGroup(gomock.Any(), db.ListAvailabilityByExpertGroupParams{ExpertID: "expert-1", GroupID: b.GroupID}).
+		Return([]db.CoachingAvailability{{ExpertID: "expert-1", DayOfWeek: 1, StartTime: start, EndTime: end}}, nil)
+	q.EXPECT().ListAvailabilityOverrides(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListBlockedSlots(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListAbsences(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListWaitlistHoldsByExpertInRange(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListBookingsByExpertInRange(gomock.Any(), gomock.Any()).Return([]db.CoachingBooking{
+		b,
+		{ID: otherID, ExpertID: "expert-1", ScheduledAt: pgtype.Timestamptz{Time: rescheduleMonday.Add(11 * time.Hour), Valid: true}, DurationMinutes: 60},
+	}, nil)
+	q.EXPECT().ListExternalBusyBlocks(gomock.Any(), gomock.Any()).Return(nil, nil)
+}
+
+func TestIsOpenSlot(t *testing.T) {
+	tests := []struct {
+		name        string
+		start       time.Time
+		bufferAfter int32
+		want        bool
+	}{
+		{"free slot", rescheduleMonday.Add(9 * time.Hour), 0, true},
+		{"the booking's own slot is not a conflict", rescheduleMonday.Add(10 * time.Hour), 0, true},
+		{"another booking's slot", rescheduleMonday.Add(11 * time.Hour), 0, false},
+		{"buffer runs into another booking", rescheduleMonday.Add(10 * time.Hour), 15, false},
+		{"off the slot grid", rescheduleMonday.Add(9*time.Hour + 30*time.Minute), 0, false},
+		{"outside availability", rescheduleMonday.Add(14 * time.Hour), 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{MinBookingNotice: 2 * time.Hour})
+			b := rescheduleBooking(t)
+			expectMondayMorning(t, q, b)
+
+			rules := h.sessionTypeRules(db.CoachingSessionType{DurationMinutes: 60, BufferAfterMinutes: tc.bufferAfter})
+			got, err := h.isOpenSlot(t.Context(), q, b, rules, tc.start)
+			if err != nil {
+				t.Fatalf("isOpenSlot: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("isOpenSlot(%s) = %v, want %v", tc.start.Format(time.RFC3339), got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProposeRescheduleValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		loadBooking bool
+		cancelled   bool
+		sessionType *db.CoachingSessionType
+		expectSlots bool
+		wantStatus  int
+		wantRule    string
+	}{
+		{"invalid time", `{"scheduled_at":"tomorrow"}`, false, false, nil, false, http.StatusBadRequest, ""},
+		{"cancelled booking", `{"scheduled_at":"2030-01-07T09:00:00Z"}`, true, true, nil, false, http.StatusConflict, ""},
+		{"same time", `{"scheduled_at":"2030-01-07T10:00:00Z"}`, true, false, nil, false, http.StatusBadRequest, ""},
+		{
+			"inside minimum notice", `{"scheduled_at":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`,
+			true, false, &db.CoachingSessionType{}, false, http.StatusBadRequest, "min_booking_notice_minutes",
+		},
+		{
+			"inside the session type's notice", `{"scheduled_at":"` + time.Now().Add(3*time.Hour).UTC().Format(time.RFC3339) + `"}`,
+			true, false, &db.CoachingSessionType{MinBookingNoticeMinutes: pgtype.Int4{Int32: 24 * 60, Valid: true}},
+			false, http.StatusBadRequest, "min_booking_notice_minutes",
+		},
+		{
+			"beyond booking horizon", `{"scheduled_at":"2030-01-07T09:00:00Z"}`,
+			true, false, &db.CoachingSessionType{BookingHorizonDays: pgtype.Int4{Int32: 30, Valid: true}},
+			false, http.StatusBadRequest, "booking_horizon_days",
+		},
+		{"slot taken", `{"scheduled_at":"2030-01-07T11:00:00Z"}`, true, false, &db.CoachingSessionType{}, true, http.StatusConflict, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{MinBookingNotice: 2 * time.Hour})
+			b := rescheduleBooking(t)
+			b.IsCancelled = tc.cancelled
+			if tc.loadBooking {
+				q.EXPECT().GetBooking(gomock.Any(), db.GetBookingParams{ID: b.ID, ExpertID: "student-1"}).Return(b, nil)
+			}
+			if tc.sessionType != nil {
+				st := *tc.sessionType
+				st.DurationMinutes = 60
+				q.EXPECT().GetSessionType(gomock.Any(), db.GetSessionTypeParams{ID: b.SessionTypeID, GroupID: b.GroupID}).Return(st, nil)
+			}
+			if tc.expectSlots {
+				expectMondayMorning(t, q, b)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ProposeReschedule(rec, rescheduleRequest(tc.body, "student-1"))
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body.String())
+			}
+			if tc.wantRule != "" && !strings.Contains(rec.Body.String(), tc.wantRule) {
+				t.Fatalf("body = %q, want it to name %s", rec.Body.String(), tc.wantRule)
+			}
+		})
+	}
+}
+
+func TestAcceptRescheduleRejectsProposer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t)
+
+	q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(b, nil)
+	q.EXPECT().GetPendingBookingReschedule(gomock.Any(), b.ID).Return(db.CoachingBookingReschedule{
+		BookingID:   b.ID,
+		ProposedBy:  "student-1",
+		ScheduledAt: pgtype.Timestamptz{Time: rescheduleMonday.Add(9 * time.Hour), Valid: true},
+		Status:      db.CoachingRescheduleStatusPending,
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	h.AcceptReschedule(rec, rescheduleRequest("", "student-1"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestAcceptRescheduleWithoutProposal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t)
+
+	q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(b, nil)
+	q.EXPECT().GetPendingBookingReschedule(gomock.Any(), b.ID).Return(db.CoachingBookingReschedule{}, pgx.ErrNoRows)
+
+	rec := httptest.NewRecorder()
+	h.AcceptReschedule(rec, rescheduleRequest("", "expert-1"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestDeclineReschedule(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		want   db.CoachingRescheduleStatus
+		notify bool
+	}{
+		{"other participant declines", "expert-1", db.CoachingRescheduleStatusDeclined, true},
+		{"proposer withdraws", "student-1", db.CoachingRescheduleStatusWithdrawn, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			workos := authmocks.NewMockUserManagement(ctrl)
+			h := NewHandler(q, nil, nil, workos, slog.Default(), HandlerConfig{})
+			b := rescheduleBooking(t)
+			proposal := db.CoachingBookingReschedule{
+				ID:          b.ID,
+				BookingID:   b.ID,
+				ProposedBy:  "student-1",
+				ScheduledAt: pgtype.Timestamptz{Time: rescheduleMonday.Add(9 * time.Hour), Valid: true},
+				Status:      db.CoachingRescheduleStatusPending,
+			}
+
+			q.EXPECT().GetBooking(gomock.Any(), gomock.Any()).Return(b, nil)
+			q.EXPECT().GetPendingBookingReschedule(gomock.Any(), b.ID).Return(proposal, nil)
+			q.EXPECT().RespondToBookingReschedule(gomock.Any(), db.RespondToBookingRescheduleParams{
+				ID:          proposal.ID,
+				Status:      tc.want,
+				RespondedBy: pgtype.Text{String: tc.userID, Valid: true},
+			}).DoAndReturn(func(_ context.Context, arg db.RespondToBookingRescheduleParams) (db.CoachingBookingReschedule, error) {
+				proposal.Status = arg.Status
+				proposal.RespondedBy = arg.RespondedBy
+				return proposal, nil
+			})
+			notified := make(chan struct{})
+			if tc.notify {
+				q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(db.CoachingSessionType{}, nil)
+				q.EXPECT().GetGroup(gomock.Any(), gomock.Any()).Return(db.Group{}, nil)
+				workos.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(usermanagement.User{}, pgx.ErrNoRows)
+				q.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+						defer close(notified)
+						if arg.RecipientID != "student-1" || arg.Type != db.NotificationTypeCoachingBookingRescheduleDeclined {
+							t.Errorf("notification = %s to %s, want decline to the proposer", arg.Type, arg.RecipientID)
+						}
+						return db.Notification{}, nil
+					})
+			}
+
+			rec := httptest.NewRecorder()
+			h.DeclineReschedule(rec, rescheduleRequest("", tc.userID))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			var resp rescheduleResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Status != string(tc.want) {
+				t.Fatalf("status = %q, want %q", resp.Status, tc.want)
+			}
+			if tc.notify {
+				select {
+				case <-notified:
+				case <-time.After(time.Second):
+					t.Fatal("decline notification was not recorded")
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRescheduledNotificationNotifiesBothParticipants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	workos := authmocks.NewMockUserManagement(ctrl)
+	h := NewHandler(q, nil, nil, workos, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t)
+	previous := rescheduleMonday.Add(9 * time.Hour)
+
+	q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(db.CoachingSessionType{Name: "Private Session"}, nil)
+	q.EXPECT().GetGroup(gomock.Any(), b.GroupID).Return(db.Group{Name: "Training"}, nil)
+	workos.EXPECT().GetUser(gomock.Any(), usermanagement.GetUserOpts{User: "expert-1"}).
+		Return(usermanagement.User{ID: "expert-1", Email: "expert@example.com"}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "expert-1").Return(
+		db.UserPreference{UserID: "expert-1", FirstName: "Alex", LastName: "Coach", Language: db.LanguageCodeEn}, nil,
+	)
+
+	var got []db.CreateNotificationParams
+	q.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+			got = append(got, arg)
+			return db.Notification{}, nil
+		})
+
+	h.writeRescheduledNotification(t.Context(), b, previous, "expert-1")
+
+	if len(got) != 2 || got[0].RecipientID != "student-1" || got[1].RecipientID != "expert-1" {
+		t.Fatalf("recipients = %+v, want student-1 and expert-1", got)
+	}
+	var payload notifications.CoachingBookingRescheduledPayload
+	if err := json.Unmarshal(got[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if got[0].Type != db.NotificationTypeCoachingBookingRescheduled ||
+		payload.ActorName != "Alex Coach" ||
+		payload.ScheduledAt != "2030-01-07T10:00:00Z" ||
+		payload.PreviousScheduledAt != "2030-01-07T09:00:00Z" {
+		t.Fatalf("notification = %s %+v", got[0].Type, payload)
+	}
+}
+
+func TestSendRescheduleProposedEmailTargetsOtherParticipant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	sender := emailmocks.NewMockSender(ctrl)
+	workos := authmocks.NewMockUserManagement(ctrl)
+	h := NewHandler(q, nil, sender, workos, slog.Default(), HandlerConfig{})
+	b := rescheduleBooking(t)
+	proposal := db.CoachingBookingReschedule{
+		BookingID:   b.ID,
+		ProposedBy:  "student-1",
+		ScheduledAt: pgtype.Timestamptz{Time: rescheduleMonday.Add(9 * time.Hour), Valid: true},
+		Note:        pgtype.Text{String: "School run", Valid: true},
+	}
+	expertPrefs := db.UserPreference{
+		UserID: "expert-1", FirstName: "Alex", LastName: "Coach",
+		Language: db.LanguageCodeEn, Timezone: "UTC",
+	}
+
+	q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(db.CoachingSessionType{Name: "Private Session"}, nil)
+	q.EXPECT().GetGroup(gomock.Any(), b.GroupID).Return(db.Group{Name: "Training"}, nil)
+	workos.EXPECT().GetUser(gomock.Any(), usermanagement.GetUserOpts{User: "student-1"}).
+		Return(usermanagement.User{ID: "student-1", Email: "student@example.com"}, nil)
+	workos.EXPECT().GetUser(gomock.Any(), usermanagement.GetUserOpts{User: "expert-1"}).
+		Return(usermanagement.User{ID: "expert-1", Email: "expert@example.com"}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "student-1").Return(
+		db.UserPreference{UserID: "student-1", FirstName: "Bea", LastName: "Rider", Language: db.LanguageCodeEn}, nil,
+	)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "expert-1").Return(expertPrefs, nil).Times(2)
+	q.EXPECT().GetUserEmailPreferences(gomock.Any(), "expert-1").Return(
+		db.GetUserEmailPreferencesRow{EmailNotificationsEnabled: true, EmailCoachingBookingUpdatesEnabled: true}, nil,
+	)
+
+	sender.EXPECT().SendTemplate(
+		[]string{"expert@example.com"},
+		"New Time Proposed for Your Coaching Session",
+		email.TemplateNotification,
+		email.Message{Copy: email.Copy{
+			Preheader: "A new time has been proposed for your coaching session.",
+			Title:     "New time proposed",
+			Intro:     "**Bea Rider** would like to move the **“Private Session”** session for **“Training”** from **Monday, 7 January 2030 at 10:00 (UTC, UTC+00:00)** to **Monday, 7 January 2030 at 09:00 (UTC, UTC+00:00)**. Open your sessions to accept or decline.",
+			Note:      "Message: School run",
+		}},
+	).Return(nil)
+
+	h.sendRescheduleProposedEmail(t.Context(), b, proposal)
+}
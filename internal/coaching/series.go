@@ -0,0 +1,459 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxSeriesOccurrences caps how many bookings one series creates — half a
+// year of weekly sessions.
+const maxSeriesOccurrences = 26
+
+// Recurrence frequencies accepted by CreateBookingSeries.
+const (
+	seriesFrequencyWeekly   = "weekly"
+	seriesFrequencyBiweekly = "biweekly"
+)
+
+// seriesConflictError lists the occurrences that are not open slots.
+type seriesConflictError struct {
+	starts []time.Time
+}
+
+func (e *seriesConflictError) Error() string {
+	return "series occurrences are not available: " + formatSeriesConflicts(e.starts)
+}
+
+// --- DTO ---
+
+type seriesRecurrenceRequest struct {
+	Frequency string  `json:"frequency"`       // "weekly" | "biweekly"
+	Count     *int    `json:"count,omitempty"` // exactly one of count / until
+	Until     *string `json:"until,omitempty"` // YYYY-MM-DD in the expert's timezone, inclusive
+}
+
+type createBookingSeriesRequest struct {
+	ExpertID      string                  `json:"expert_id"`
+	SessionTypeID string                  `json:"session_type_id"`
+	StartsAt      string                  `json:"starts_at"` // RFC3339, first occurrence
+	Recurrence    seriesRecurrenceRequest `json:"recurrence"`
+	Notes         *string                 `json:"notes,omitempty"`
+}
+
+type bookingSeriesResponse struct {
+	ID              string            `json:"id"`
+	ExpertID        string            `json:"expert_id"`
+	StudentID       string            `json:"student_id"`
+	GroupID         string            `json:"group_id"`
+	SessionTypeID   string            `json:"session_type_id"`
+	StartsAt        time.Time         `json:"starts_at"`
+	Timezone        string            `json:"timezone"`
+	RRule           string            `json:"rrule"`
+	DurationMinutes int32             `json:"duration_minutes"`
+	Notes           *string           `json:"notes,omitempty"`
+	Bookings        []bookingResponse `json:"bookings"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// seriesRecurrence is a validated recurrence: exactly one of count and until
+// is set.
+type seriesRecurrence struct {
+	intervalWeeks int
+	count         int
+	until         time.Time // local calendar date; zero when count is set
+}
+
+// --- Handlers ---
+
+// CreateBookingSeries books the caller into a weekly or biweekly run of
+// sessions. Every occurrence must be an open slot; otherwise nothing is booked
+// and the conflicting times are reported.
+func (h *Handler) CreateBookingSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req createBookingSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ExpertID == "" {
+		http.Error(w, "expert_id is required", http.StatusBadRequest)
+		return
+	}
+
+	sessionTypeID, err := parseUUID(req.SessionTypeID)
+	if err != nil {
+		http.Error(w, "Invalid session_type_id", http.StatusBadRequest)
+		return
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		http.Error(w, "Invalid starts_at (use RFC3339)", http.StatusBadRequest)
+		return
+	}
+
+	recurrence, errMsg := parseSeriesRecurrence(req.Recurrence)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	sessionType, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{
+		ID:      sessionTypeID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Session type not found", http.StatusNotFound)
+			return
+		}
+		log.ErrorContext(ctx, "get_session_type_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get session type", http.StatusInternalServerError)
+		return
+	}
+	// Each occurrence would need its own checkout; paid sessions are booked
+	// one at a time.
+	if sessionType.PriceCents > 0 {
+		http.Error(w, "Paid session types cannot be booked as a series", http.StatusBadRequest)
+		return
+	}
+	if sessionType.Capacity > 1 {
+		http.Error(w, "Group classes cannot be booked as a series", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := userLocation(ctx, h.q, req.ExpertID)
+	if err != nil {
+		log.ErrorContext(ctx, "get_expert_timezone_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get expert timezone", http.StatusInternalServerError)
+		return
+	}
+
+	starts, errMsg := seriesOccurrences(startsAt, loc, recurrence)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	// The notice applies to the first occurrence and the horizon to the last.
+	rules := h.sessionTypeRules(sessionType)
+	now := time.Now()
+	for _, start := range starts {
+		if msg := rules.checkStart(now, start); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	open, err := h.openSlotStarts(ctx, h.q, req.ExpertID, groupID, rules, pgtype.UUID{}, starts[0], starts[len(starts)-1])
+	if err != nil {
+		log.ErrorContext(ctx, "series_slot_check_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to check availability", http.StatusInternalServerError)
+		return
+	}
+	var unavailable []time.Time
+	for _, start := range starts {
+		if !open[start.Unix()] {
+			unavailable = append(unavailable, start)
+		}
+	}
+	if len(unavailable) > 0 {
+		http.Error(w, "Time slots are not available: "+formatSeriesConflicts(unavailable), http.StatusConflict)
+		return
+	}
+
+	arg := db.CreateBookingSeriesParams{
+		ExpertID:        req.ExpertID,
+		StudentID:       user.ID,
+		GroupID:         groupID,
+		SessionTypeID:   sessionTypeID,
+		StartsAt:        pgtype.Timestamptz{Time: starts[0], Valid: true},
+		Timezone:        loc.String(),
+		IntervalWeeks:   int32(recurrence.intervalWeeks),
+		DurationMinutes: sessionType.DurationMinutes,
+	}
+	if recurrence.count > 0 {
+		arg.OccurrenceCount = pgtype.Int4{Int32: int32(recurrence.count), Valid: true}
+	} else {
+		arg.UntilDate = pgtype.Date{Time: recurrence.until, Valid: true}
+	}
+	if req.Notes != nil {
+		arg.Notes = pgtype.Text{String: *req.Notes, Valid: true}
+	}
+
+	series, bookings, err := h.createBookingSeries(ctx, arg, starts, sessionType, rules)
+	if err != nil {
+		var conflictErr *seriesConflictError
+		if errors.As(err, &conflictErr) {
+			http.Error(w, "Time slots are no longer available: "+formatSeriesConflicts(conflictErr.starts), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, errNoCredit) {
+			http.Error(w, fmt.Sprintf("Not enough session credits for %d sessions", len(starts)), http.StatusConflict)
+			return
+		}
+		log.ErrorContext(ctx, "create_booking_series_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create booking series", http.StatusInternalServerError)
+		return
+	}
+
+	for _, b := range bookings {
+		h.scheduleReminders(ctx, b)
+	}
+	h.sendSeriesCreatedEmail(ctx, series, bookings, sessionType.Name)
+	h.recordSeriesCreatedNotification(series, bookings, sessionType.Name)
+
+	users, err := h.resolveUsers(ctx, []string{series.ExpertID, series.StudentID})
+	if err != nil {
+		log.ErrorContext(ctx, "resolve_booking_users_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to resolve booking users", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toBookingSeriesResponse(series, bookings, users, sessionType.Name))
+}
+
+// createBookingSeries runs createBookingSeriesTx, retrying up to 3× on
+// serialization failure like CreateBooking.
+func (h *Handler) createBookingSeries(ctx context.Context, arg db.CreateBookingSeriesParams, starts []time.Time, sessionType db.CoachingSessionType, rules bookingRules) (db.CoachingBookingSeries, []db.CoachingBooking, error) {
+	const maxRetries = 3
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		var series db.CoachingBookingSeries
+		var bookings []db.CoachingBooking
+		series, bookings, err = h.createBookingSeriesTx(ctx, arg, starts, sessionType, rules)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "40001" {
+			continue
+		}
+		return series, bookings, err
+	}
+	return db.CoachingBookingSeries{}, nil, err
+}
+
+// createBookingSeriesTx re-checks every occurrence for conflicts and the
+// buffer and daily-cap rules, then inserts the series and one booking per
+// occurrence with a booking.created event each, in one SERIALIZABLE
+// transaction. A credit-only session type takes one credit per occurrence;
+// errNoCredit leaves the whole series unbooked.
+func (h *Handler) createBookingSeriesTx(ctx context.Context, arg db.CreateBookingSeriesParams, starts []time.Time, sessionType db.CoachingSessionType, rules bookingRules) (db.CoachingBookingSeries, []db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return db.CoachingBookingSeries{}, nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	duration := time.Duration(arg.DurationMinutes) * time.Minute
+	var taken []time.Time
+	for _, start := range starts {
+		conflicts, err := qtx.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
+			ExpertID:      arg.ExpertID,
+			ScheduledAt:   pgtype.Timestamptz{Time: start, Valid: true},
+			ScheduledAt_2: pgtype.Timestamptz{Time: start.Add(duration), Valid: true},
+		})
+		if err != nil {
+			return db.CoachingBookingSeries{}, nil, err
+		}
+		if conflicts > 0 {
+			taken = append(taken, start)
+			continue
+		}
+		msg, err := checkBookingRulesInTx(ctx, qtx, rules, arg.ExpertID, start, pgtype.UUID{})
+		if err != nil {
+			return db.CoachingBookingSeries{}, nil, err
+		}
+		if msg != "" {
+			taken = append(taken, start)
+		}
+	}
+	if len(taken) > 0 {
+		return db.CoachingBookingSeries{}, nil, &seriesConflictError{starts: taken}
+	}
+
+	series, err := qtx.CreateBookingSeries(ctx, arg)
+	if err != nil {
+		return db.CoachingBookingSeries{}, nil, err
+	}
+	bookings := make([]db.CoachingBooking, 0, len(starts))
+	for _, start := range starts {
+		bArg := db.CreateBookingParams{
+			ExpertID:            series.ExpertID,
+			StudentID:           series.StudentID,
+			GroupID:             series.GroupID,
+			SessionTypeID:       series.SessionTypeID,
+			ScheduledAt:         pgtype.Timestamptz{Time: start, Valid: true},
+			DurationMinutes:     series.DurationMinutes,
+			Notes:               series.Notes,
+			SeriesID:            series.ID,
+			BufferBeforeMinutes: int32(rules.BufferBefore / time.Minute),
+			BufferAfterMinutes:  int32(rules.BufferAfter / time.Minute),
+		}
+		grant, err := reserveCreditInTx(ctx, qtx, &bArg, sessionType)
+		if err != nil {
+			return db.CoachingBookingSeries{}, nil, err
+		}
+		b, err := qtx.CreateBooking(ctx, bArg)
+		if err != nil {
+			return db.CoachingBookingSeries{}, nil, err
+		}
+		if err := h.recordCreditConsumedInTx(ctx, tx, grant, b); err != nil {
+			return db.CoachingBookingSeries{}, nil, err
+		}
+		if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingCreated, b, nil)); err != nil {
+			return db.CoachingBookingSeries{}, nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBookingSeries{}, nil, err
+	}
+	return series, bookings, nil
+}
+
+// --- Recurrence ---
+
+// parseSeriesRecurrence validates the request's recurrence. Returns an error
+// string suitable for http.Error.
+func parseSeriesRecurrence(req seriesRecurrenceRequest) (seriesRecurrence, string) {
+	var rec seriesRecurrence
+	switch req.Frequency {
+	case seriesFrequencyWeekly:
+		rec.intervalWeeks = 1
+	case seriesFrequencyBiweekly:
+		rec.intervalWeeks = 2
+	default:
+		return seriesRecurrence{}, "recurrence.frequency must be weekly or biweekly"
+	}
+	if (req.Count == nil) == (req.Until == nil) {
+		return seriesRecurrence{}, "Exactly one of recurrence.count and recurrence.until is required"
+	}
+	if req.Count != nil {
+		if *req.Count < 2 || *req.Count > maxSeriesOccurrences {
+			return seriesRecurrence{}, fmt.Sprintf("recurrence.count must be between 2 and %d", maxSeriesOccurrences)
+		}
+		rec.count = *req.Count
+		return rec, ""
+	}
+	until, err := time.Parse("2006-01-02", *req.Until)
+	if err != nil {
+		return seriesRecurrence{}, "Invalid recurrence.until (use YYYY-MM-DD)"
+	}
+	rec.until = until
+	return rec, ""
+}
+
+// seriesOccurrences expands rec from first. Occurrences keep first's wall-clock
+// time in loc, so a series does not drift by an hour across DST changes.
+// Returns an error string suitable for http.Error.
+func seriesOccurrences(first time.Time, loc *time.Location, rec seriesRecurrence) ([]time.Time, string) {
+	local := first.In(loc)
+	var untilEnd time.Time
+	if rec.count == 0 {
+		untilEnd = time.Date(rec.until.Year(), rec.until.Month(), rec.until.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	}
+
+	var starts []time.Time
+	for i := 0; ; i++ {
+		start := local.AddDate(0, 0, 7*rec.intervalWeeks*i)
+		if rec.count > 0 && i == rec.count {
+			break
+		}
+		if rec.count == 0 && !start.Before(untilEnd) {
+			break
+		}
+		if len(starts) == maxSeriesOccurrences {
+			return nil, fmt.Sprintf("A series can have at most %d sessions", maxSeriesOccurrences)
+		}
+		starts = append(starts, start.UTC())
+	}
+	if len(starts) < 2 {
+		return nil, "recurrence.until must leave room for at least 2 sessions"
+	}
+	return starts, ""
+}
+
+// seriesRRule renders the series recurrence as an RFC 5545 RRULE value.
+func seriesRRule(s db.CoachingBookingSeries) string {
+	rule := fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d", s.IntervalWeeks)
+	if s.OccurrenceCount.Valid {
+		return rule + fmt.Sprintf(";COUNT=%d", s.OccurrenceCount.Int32)
+	}
+	return rule + ";UNTIL=" + s.UntilDate.Time.Format("20060102")
+}
+
+// formatSeriesConflicts joins starts as RFC3339 UTC times.
+func formatSeriesConflicts(starts []time.Time) string {
+	parts := make([]string, len(starts))
+	for i, t := range starts {
+		parts[i] = t.UTC().Format(time.RFC3339)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// --- Mappers ---
+
+func toBookingSeriesResponse(s db.CoachingBookingSeries, bookings []db.CoachingBooking, users map[string]userInfo, sessionTypeName string) bookingSeriesResponse {
+	resp := bookingSeriesResponse{
+		ID:              uuidToString(s.ID),
+		ExpertID:        s.ExpertID,
+		StudentID:       s.StudentID,
+		GroupID:         uuidToString(s.GroupID),
+		SessionTypeID:   uuidToString(s.SessionTypeID),
+		StartsAt:        s.StartsAt.Time,
+		Timezone:        s.Timezone,
+		RRule:           seriesRRule(s),
+		DurationMinutes: s.DurationMinutes,
+		Bookings:        make([]bookingResponse, 0, len(bookings)),
+		CreatedAt:       s.CreatedAt.Time,
+	}
+	if s.Notes.Valid {
+		resp.Notes = &s.Notes.String
+	}
+	for _, b := range bookings {
+		resp.Bookings = append(resp.Bookings, toBookingResponse(b, users, sessionTypeName))
+	}
+	return resp
+}
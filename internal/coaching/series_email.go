@@ -0,0 +1,208 @@
+package coaching
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/email"
+	"github.com/OZIOisgood/zeta/internal/i18n"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/OZIOisgood/zeta/internal/preferences"
+)
+
+// sendSeriesCreatedEmail sends both participants one confirmation for the
+// whole series instead of one per occurrence.
+func (h *Handler) sendSeriesCreatedEmail(ctx context.Context, s db.CoachingBookingSeries, bookings []db.CoachingBooking, sessionTypeName string) {
+	log := logger.From(ctx, h.logger)
+	if len(bookings) == 0 {
+		return
+	}
+	first, last := bookings[0], bookings[len(bookings)-1]
+
+	_, groupName := h.bookingLabels(ctx, first)
+	expert := h.resolveParticipant(ctx, s.ExpertID)
+	student := h.resolveParticipant(ctx, s.StudentID)
+
+	frequencyKey := "email.booking_series_confirmed.frequency_weekly"
+	if s.IntervalWeeks == 2 {
+		frequencyKey = "email.booking_series_confirmed.frequency_biweekly"
+	}
+
+	type emailTarget struct {
+		userID  string
+		addr    string
+		partner string
+	}
+	targets := []emailTarget{
+		{userID: s.StudentID, addr: student.email, partner: expert.name},
+		{userID: s.ExpertID, addr: expert.email, partner: student.name},
+	}
+
+	for _, t := range targets {
+		if t.addr == "" {
+			continue
+		}
+		if !preferences.AllowsUserEmail(ctx, h.q, h.logger, t.userID, preferences.EmailCategoryCoachingBookingUpdates) {
+			log.InfoContext(ctx, "series_created_email_skipped_by_preferences",
+				slog.String("component", "coaching"),
+				slog.String("series_id", uuidToString(s.ID)),
+				slog.String("user_id", t.userID),
+			)
+			continue
+		}
+		localization := h.resolveRecipientLocalization(ctx, t.userID)
+		loc := localization.localizer
+		note := ""
+		if s.Notes.Valid && s.Notes.String != "" {
+			note = i18n.T(loc, "email.booking_series_confirmed.note", map[string]any{"Note": s.Notes.String})
+		}
+		subject := i18n.T(loc, "email.booking_series_confirmed.subject")
+		message := email.Message{
+			Copy: email.Copy{
+				Preheader: i18n.T(loc, "email.booking_series_confirmed.preheader"),
+				Title:     i18n.T(loc, "email.booking_series_confirmed.title"),
+				Intro: i18n.T(loc, "email.booking_series_confirmed.intro", map[string]any{
+					"SessionName":      sessionTypeName,
+					"PartnerName":      t.partner,
+					"GroupName":        groupName,
+					"Frequency":        i18n.T(loc, frequencyKey),
+					"Count":            len(bookings),
+					"FirstScheduledAt": formatEmailDateTime(first.ScheduledAt.Time, localization),
+					"LastScheduledAt":  formatEmailDateTime(last.ScheduledAt.Time, localization),
+					"Duration":         formatEmailDuration(s.DurationMinutes, localization),
+				}),
+				Note: note,
+			},
+		}
+		if err := h.emailService.SendTemplate([]string{t.addr}, subject, email.TemplateNotification, message); err != nil {
+			log.ErrorContext(ctx, "series_created_email_failed",
+				slog.String("component", "coaching"),
+				slog.String("user_id", t.userID),
+				slog.Any("err", err),
+			)
+			continue
+		}
+		log.InfoContext(ctx, "series_created_email_sent",
+			slog.String("component", "coaching"),
+			slog.String("series_id", uuidToString(s.ID)),
+			slog.String("user_id", t.userID),
+			slog.Int("occurrences", len(bookings)),
+		)
+	}
+}
+
+// sendSeriesCancellationEmail tells the participant who did not cancel that
+// count sessions of the series, starting with b, were cancelled.
+func (h *Handler) sendSeriesCancellationEmail(ctx context.Context, b db.CoachingBooking, cancelledByID string, count int) {
+	log := logger.From(ctx, h.logger)
+
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	canceller := h.resolveParticipant(ctx, cancelledByID)
+	recipientID := otherParticipant(b, cancelledByID)
+	recipient := h.resolveParticipant(ctx, recipientID)
+	if recipient.email == "" {
+		return
+	}
+	if !preferences.AllowsUserEmail(ctx, h.q, h.logger, recipientID, preferences.EmailCategoryCoachingBookingUpdates) {
+		log.InfoContext(ctx, "series_cancellation_email_skipped_by_preferences",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.String("user_id", recipientID),
+		)
+		return
+	}
+
+	localization := h.resolveRecipientLocalization(ctx, recipientID)
+	loc := localization.localizer
+	note := ""
+	if reason := b.CancellationReason.String; reason != "" {
+		note = i18n.T(loc, "email.booking_series_cancelled.note", map[string]any{"Reason": reason})
+	}
+	subject := i18n.T(loc, "email.booking_series_cancelled.subject")
+	message := email.Message{
+		Copy: email.Copy{
+			Preheader: i18n.T(loc, "email.booking_series_cancelled.preheader"),
+			Title:     i18n.T(loc, "email.booking_series_cancelled.title"),
+			Intro: i18n.T(loc, "email.booking_series_cancelled.intro", map[string]any{
+				"CancellerName": canceller.name,
+				"Count":         count,
+				"SessionName":   sessionTypeName,
+				"GroupName":     groupName,
+				"ScheduledAt":   formatEmailDateTime(b.ScheduledAt.Time, localization),
+			}),
+			Note: note,
+		},
+	}
+
+	if err := h.emailService.SendTemplate([]string{recipient.email}, subject, email.TemplateNotification, message); err != nil {
+		log.ErrorContext(ctx, "series_cancellation_email_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(b.ID)),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	log.InfoContext(ctx, "series_cancellation_email_sent",
+		slog.String("component", "coaching"),
+		slog.String("booking_id", uuidToString(b.ID)),
+		slog.String("series_id", uuidToString(b.SeriesID)),
+		slog.String("cancelled_by", cancelledByID),
+		slog.Int("occurrences", count),
+	)
+}
+
+// recordSeriesCreatedNotification records one in-app notification for the
+// expert covering the whole series. Runs detached from the request context.
+func (h *Handler) recordSeriesCreatedNotification(s db.CoachingBookingSeries, bookings []db.CoachingBooking, sessionTypeName string) {
+	go h.writeSeriesCreatedNotification(context.Background(), s, bookings, sessionTypeName)
+}
+
+func (h *Handler) writeSeriesCreatedNotification(ctx context.Context, s db.CoachingBookingSeries, bookings []db.CoachingBooking, sessionTypeName string) {
+	if len(bookings) == 0 {
+		return
+	}
+	first := bookings[0]
+	_, groupName := h.bookingLabels(ctx, first)
+	student := h.resolveParticipant(ctx, s.StudentID)
+
+	notifications.Record(ctx, h.q, h.logger, s.ExpertID, notifications.TypeCoachingBookingCreated,
+		notifications.CoachingBookingCreatedPayload{
+			BookingID:       uuidToString(first.ID),
+			GroupID:         uuidToString(s.GroupID),
+			GroupName:       groupName,
+			StudentName:     student.name,
+			SessionName:     sessionTypeName,
+			ScheduledAt:     formatNotificationTime(first.ScheduledAt.Time),
+			DurationMinutes: int(s.DurationMinutes),
+			SeriesID:        uuidToString(s.ID),
+			Occurrences:     len(bookings),
+		})
+}
+
+// recordSeriesCancelledNotification notifies the participant who did not
+// cancel, once for all count cancelled sessions. Runs detached from the request
+// context.
+func (h *Handler) recordSeriesCancelledNotification(b db.CoachingBooking, cancelledByID string, count int) {
+	go h.writeSeriesCancelledNotification(context.Background(), b, cancelledByID, count)
+}
+
+func (h *Handler) writeSeriesCancelledNotification(ctx context.Context, b db.CoachingBooking, cancelledByID string, count int) {
+	sessionTypeName, groupName := h.bookingLabels(ctx, b)
+	actor := h.resolveParticipant(ctx, cancelledByID)
+
+	notifications.Record(ctx, h.q, h.logger, otherParticipant(b, cancelledByID), notifications.TypeCoachingBookingCancelled,
+		notifications.CoachingBookingCancelledPayload{
+			BookingID:       uuidToString(b.ID),
+			GroupID:         uuidToString(b.GroupID),
+			GroupName:       groupName,
+			ActorName:       actor.name,
+			SessionName:     sessionTypeName,
+			ScheduledAt:     formatNotificationTime(b.ScheduledAt.Time),
+			DurationMinutes: int(b.DurationMinutes),
+			SeriesID:        uuidToString(b.SeriesID),
+			Occurrences:     count,
+		})
+}
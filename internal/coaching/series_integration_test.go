@@ -0,0 +1,80 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_BookingSeriesOccurrences(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	first := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	seriesArg := db.CreateBookingSeriesParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		StartsAt: pgtype.Timestamptz{Time: first, Valid: true}, Timezone: "UTC", IntervalWeeks: 1,
+		DurationMinutes: 60,
+	}
+	if _, err := q.CreateBookingSeries(ctx, seriesArg); err == nil {
+		t.Fatal("series without count or until was accepted")
+	}
+	seriesArg.OccurrenceCount = pgtype.Int4{Int32: 3, Valid: true}
+	series, err := q.CreateBookingSeries(ctx, seriesArg)
+	if err != nil {
+		t.Fatalf("CreateBookingSeries: %v", err)
+	}
+
+	var bookings []db.CoachingBooking
+	for i := 0; i < 3; i++ {
+		b, err := q.CreateBooking(ctx, db.CreateBookingParams{
+			ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+			ScheduledAt:     pgtype.Timestamptz{Time: first.AddDate(0, 0, 7*i), Valid: true},
+			DurationMinutes: 60, SeriesID: series.ID,
+		})
+		if err != nil {
+			t.Fatalf("CreateBooking %d: %v", i, err)
+		}
+		bookings = append(bookings, b)
+	}
+
+	// Cancelling the last one on its own leaves it out of "this and following".
+	if _, err := q.CancelBooking(ctx, db.CancelBookingParams{
+		ID: bookings[2].ID, CancelledBy: pgtype.Text{String: "student-1", Valid: true}, ExpertID: "student-1",
+	}); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	following, err := db.New(tx).ListActiveSeriesBookingsFrom(ctx, db.ListActiveSeriesBookingsFromParams{
+		SeriesID: series.ID, ScheduledAt: bookings[1].ScheduledAt,
+	})
+	if err != nil {
+		t.Fatalf("ListActiveSeriesBookingsFrom: %v", err)
+	}
+	if len(following) != 1 || following[0].ID != bookings[1].ID || following[0].SeriesID != series.ID {
+		t.Fatalf("ListActiveSeriesBookingsFrom = %+v; want only the second occurrence", following)
+	}
+}
@@ -0,0 +1,284 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	authmocks "github.com/OZIOisgood/zeta/internal/auth/mocks"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/workos/workos-go/v4/pkg/usermanagement"
+	"go.uber.org/mock/gomock"
+)
+
+func intPtr(v int) *int { return &v }
+
+func strPtr(v string) *string { return &v }
+
+func TestParseSeriesRecurrence(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     seriesRecurrenceRequest
+		want    seriesRecurrence
+		wantErr bool
+	}{
+		{"weekly count", seriesRecurrenceRequest{Frequency: "weekly", Count: intPtr(8)}, seriesRecurrence{intervalWeeks: 1, count: 8}, false},
+		{"biweekly until", seriesRecurrenceRequest{Frequency: "biweekly", Until: strPtr("2030-03-01")},
+			seriesRecurrence{intervalWeeks: 2, until: time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"unknown frequency", seriesRecurrenceRequest{Frequency: "daily", Count: intPtr(3)}, seriesRecurrence{}, true},
+		{"neither count nor until", seriesRecurrenceRequest{Frequency: "weekly"}, seriesRecurrence{}, true},
+		{"both count and until", seriesRecurrenceRequest{Frequency: "weekly", Count: intPtr(3), Until: strPtr("2030-03-01")}, seriesRecurrence{}, true},
+		{"single occurrence", seriesRecurrenceRequest{Frequency: "weekly", Count: intPtr(1)}, seriesRecurrence{}, true},
+		{"count over the cap", seriesRecurrenceRequest{Frequency: "weekly", Count: intPtr(maxSeriesOccurrences + 1)}, seriesRecurrence{}, true},
+		{"malformed until", seriesRecurrenceRequest{Frequency: "weekly", Until: strPtr("01/03/2030")}, seriesRecurrence{}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, errMsg := parseSeriesRecurrence(tc.req)
+			if (errMsg != "") != tc.wantErr {
+				t.Fatalf("error = %q, wantErr %v", errMsg, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("recurrence = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSeriesOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// 18:00 in Berlin on the Monday before DST ends (2030-10-27).
+	first := time.Date(2030, time.October, 21, 18, 0, 0, 0, berlin)
+
+	t.Run("weekly keeps the local wall time across DST", func(t *testing.T) {
+		got, errMsg := seriesOccurrences(first, berlin, seriesRecurrence{intervalWeeks: 1, count: 3})
+		if errMsg != "" {
+			t.Fatal(errMsg)
+		}
+		want := []string{"2030-10-21T16:00:00Z", "2030-10-28T17:00:00Z", "2030-11-04T17:00:00Z"}
+		if len(got) != len(want) {
+			t.Fatalf("got %d occurrences, want %d", len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Format(time.RFC3339) != want[i] {
+				t.Errorf("occurrence %d = %s, want %s", i, got[i].Format(time.RFC3339), want[i])
+			}
+		}
+	})
+
+	t.Run("biweekly until is inclusive", func(t *testing.T) {
+		until := time.Date(2030, time.November, 18, 0, 0, 0, 0, time.UTC)
+		got, errMsg := seriesOccurrences(first, berlin, seriesRecurrence{intervalWeeks: 2, until: until})
+		if errMsg != "" {
+			t.Fatal(errMsg)
+		}
+		if len(got) != 3 || got[2].In(berlin).Format("2006-01-02 15:04") != "2030-11-18 18:00" {
+			t.Fatalf("occurrences = %v", got)
+		}
+	})
+
+	t.Run("until too close for two sessions", func(t *testing.T) {
+		until := time.Date(2030, time.October, 27, 0, 0, 0, 0, time.UTC)
+		if _, errMsg := seriesOccurrences(first, berlin, seriesRecurrence{intervalWeeks: 1, until: until}); errMsg == "" {
+			t.Fatal("expected an error for a one-session series")
+		}
+	})
+
+	t.Run("until beyond the cap", func(t *testing.T) {
+		until := first.AddDate(2, 0, 0)
+		if _, errMsg := seriesOccurrences(first, berlin, seriesRecurrence{intervalWeeks: 1, until: until}); errMsg == "" {
+			t.Fatal("expected an error past maxSeriesOccurrences")
+		}
+	})
+}
+
+func TestSeriesRRule(t *testing.T) {
+	count := db.CoachingBookingSeries{IntervalWeeks: 1, OccurrenceCount: pgtype.Int4{Int32: 8, Valid: true}}
+	if got := seriesRRule(count); got != "FREQ=WEEKLY;INTERVAL=1;COUNT=8" {
+		t.Errorf("seriesRRule(count) = %q", got)
+	}
+	until := db.CoachingBookingSeries{IntervalWeeks: 2, UntilDate: pgtype.Date{Time: time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC), Valid: true}}
+	if got := seriesRRule(until); got != "FREQ=WEEKLY;INTERVAL=2;UNTIL=20300301" {
+		t.Errorf("seriesRRule(until) = %q", got)
+	}
+}
+
+func seriesRequest(t *testing.T, groupID pgtype.UUID, body string) *http.Request {
+	t.Helper()
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("groupID", uuidToString(groupID))
+	req := httptest.NewRequest(http.MethodPost, "/booking-series", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(context.WithValue(ctx, auth.UserKey, &auth.UserContext{ID: "student-1"}))
+}
+
+func TestCreateBookingSeriesReportsUnavailableOccurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{MinBookingNotice: 2 * time.Hour})
+	b := rescheduleBooking(t)
+	start, _ := parseTime("09:00")
+	end, _ := parseTime("12:00")
+
+	q.EXPECT().GetSessionType(gomock.Any(), db.GetSessionTypeParams{ID: b.SessionTypeID, GroupID: b.GroupID}).
+		Return(db.CoachingSessionType{ID: b.SessionTypeID, Name: "Private Session", DurationMinutes: 60}, nil)
+	q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("UTC", nil).Times(2)
+	q.EXPECT().ListAvailabilityByExpertGroup(gomock.Any(), db.ListAvailabilityByExpertGroupParams{ExpertID: "expert-1", GroupID: b.GroupID}).
+		Return([]db.CoachingAvailability{{ExpertID: "expert-1", DayOfWeek: 1, StartTime: start, EndTime: end}}, nil)
+	q.EXPECT().ListAvailabilityOverrides(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListBlockedSlots(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListAbsences(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListWaitlistHoldsByExpertInRange(gomock.Any(), gomock.Any()).Return(nil, nil)
+	// The second Monday's 10:00 slot is already booked.
+	q.EXPECT().ListBookingsByExpertInRange(gomock.Any(), gomock.Any()).Return([]db.CoachingBooking{{
+		ExpertID:        "expert-1",
+		ScheduledAt:     pgtype.Timestamptz{Time: rescheduleMonday.AddDate(0, 0, 7).Add(10 * time.Hour), Valid: true},
+		DurationMinutes: 60,
+	}}, nil)
+	// The third Monday's 10:30 is busy in the expert's external calendar.
+	q.EXPECT().ListExternalBusyBlocks(gomock.Any(), gomock.Any()).Return([]db.ListExternalBusyBlocksRow{{
+		StartsAt: pgtype.Timestamptz{Time: rescheduleMonday.AddDate(0, 0, 14).Add(10*time.Hour + 30*time.Minute), Valid: true},
+		EndsAt:   pgtype.Timestamptz{Time: rescheduleMonday.AddDate(0, 0, 14).Add(11 * time.Hour), Valid: true},
+	}}, nil)
+
+	body := `{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","starts_at":"2030-01-07T10:00:00Z","recurrence":{"frequency":"weekly","count":3}}`
+	rec := httptest.NewRecorder()
+	h.CreateBookingSeries(rec, seriesRequest(t, b.GroupID, body))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body.String())
+	}
+	got := rec.Body.String()
+	if !strings.Contains(got, "2030-01-14T10:00:00Z") || !strings.Contains(got, "2030-01-21T10:00:00Z") || strings.Contains(got, "2030-01-07T10:00:00Z") {
+		t.Fatalf("body = %q, want the booked and the externally busy occurrence listed", got)
+	}
+}
+
+func TestCreateBookingSeriesValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		sessionType *db.CoachingSessionType
+		wantRule    string
+	}{
+		{"missing expert", `{"session_type_id":"` + rescheduleTestBookingID + `","starts_at":"2030-01-07T10:00:00Z","recurrence":{"frequency":"weekly","count":3}}`, nil, ""},
+		{"invalid start", `{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","starts_at":"monday","recurrence":{"frequency":"weekly","count":3}}`, nil, ""},
+		{"invalid recurrence", `{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","starts_at":"2030-01-07T10:00:00Z","recurrence":{"frequency":"monthly","count":3}}`, nil, ""},
+		{
+			"inside minimum notice",
+			`{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","starts_at":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `","recurrence":{"frequency":"weekly","count":3}}`,
+			&db.CoachingSessionType{}, "min_booking_notice_minutes",
+		},
+		{
+			// The first occurrence is inside the horizon, the third is not.
+			"last occurrence beyond booking horizon",
+			`{"expert_id":"expert-1","session_type_id":"` + rescheduleTestBookingID + `","starts_at":"` + time.Now().AddDate(0, 0, 3).UTC().Format(time.RFC3339) + `","recurrence":{"frequency":"weekly","count":3}}`,
+			&db.CoachingSessionType{BookingHorizonDays: pgtype.Int4{Int32: 14, Valid: true}}, "booking_horizon_days",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{MinBookingNotice: 2 * time.Hour})
+			if tc.sessionType != nil {
+				st := *tc.sessionType
+				st.DurationMinutes = 60
+				q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(st, nil)
+				q.EXPECT().GetUserTimezone(gomock.Any(), "expert-1").Return("UTC", nil)
+			}
+
+			rec := httptest.NewRecorder()
+			h.CreateBookingSeries(rec, seriesRequest(t, rescheduleBooking(t).GroupID, tc.body))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+			if tc.wantRule != "" && !strings.Contains(rec.Body.String(), tc.wantRule) {
+				t.Fatalf("body = %q, want it to name %s", rec.Body.String(), tc.wantRule)
+			}
+		})
+	}
+}
+
+func TestCancelBookingScopeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		loadBooking bool
+	}{
+		{"unknown scope", `{"scope":"all"}`, false},
+		{"following on a standalone booking", `{"scope":"following"}`, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+			b := rescheduleBooking(t)
+			if tc.loadBooking {
+				q.EXPECT().GetBooking(gomock.Any(), db.GetBookingParams{ID: b.ID, ExpertID: "student-1"}).Return(b, nil)
+			}
+
+			rec := httptest.NewRecorder()
+			h.CancelBooking(rec, rescheduleRequest(tc.body, "student-1"))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriteSeriesCreatedNotificationCoversWholeSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	workos := authmocks.NewMockUserManagement(ctrl)
+	h := NewHandler(q, nil, nil, workos, slog.Default(), HandlerConfig{})
+	first := rescheduleBooking(t)
+	second := first
+	second.ScheduledAt = pgtype.Timestamptz{Time: first.ScheduledAt.Time.AddDate(0, 0, 7), Valid: true}
+	series := db.CoachingBookingSeries{
+		ID: first.ID, ExpertID: "expert-1", StudentID: "student-1", GroupID: first.GroupID, DurationMinutes: 60,
+	}
+
+	q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(db.CoachingSessionType{Name: "Private Session"}, nil)
+	q.EXPECT().GetGroup(gomock.Any(), first.GroupID).Return(db.Group{Name: "Training"}, nil)
+	workos.EXPECT().GetUser(gomock.Any(), usermanagement.GetUserOpts{User: "student-1"}).
+		Return(usermanagement.User{ID: "student-1", Email: "student@example.com"}, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "student-1").Return(
+		db.UserPreference{UserID: "student-1", FirstName: "Bea", LastName: "Rider", Language: db.LanguageCodeEn}, nil,
+	)
+
+	var got db.CreateNotificationParams
+	q.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+			got = arg
+			return db.Notification{}, nil
+		})
+
+	h.writeSeriesCreatedNotification(t.Context(), series, []db.CoachingBooking{first, second}, "Private Session")
+
+	var payload notifications.CoachingBookingCreatedPayload
+	if err := json.Unmarshal(got.Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if got.RecipientID != "expert-1" ||
+		got.Type != db.NotificationTypeCoachingBookingCreated ||
+		payload.Occurrences != 2 ||
+		payload.SeriesID != rescheduleTestBookingID ||
+		payload.ScheduledAt != "2030-01-07T10:00:00Z" {
+		t.Fatalf("notification = %s %s %+v", got.RecipientID, got.Type, payload)
+	}
+}
@@ -1,11 +1,13 @@
 package coaching
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/OZIOisgood/zeta/internal/auth"
@@ -14,23 +16,55 @@ import (
 	"github.com/OZIOisgood/zeta/internal/permissions"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type sessionTypeResponse struct {
-	ID              string    `json:"id"`
-	ExpertID        string    `json:"expert_id"`
-	GroupID         string    `json:"group_id"`
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	DurationMinutes int32     `json:"duration_minutes"`
-	IsActive        bool      `json:"is_active"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID                        string    `json:"id"`
+	ExpertID                  string    `json:"expert_id"`
+	GroupID                   string    `json:"group_id"`
+	Name                      string    `json:"name"`
+	Description               string    `json:"description"`
+	DurationMinutes           int32     `json:"duration_minutes"`
+	BufferBeforeMinutes       int32     `json:"buffer_before_minutes"`
+	BufferAfterMinutes        int32     `json:"buffer_after_minutes"`
+	MaxSessionsPerDay         *int32    `json:"max_sessions_per_day,omitempty"`
+	BookingHorizonDays        *int32    `json:"booking_horizon_days,omitempty"`
+	MinBookingNoticeMinutes   *int32    `json:"min_booking_notice_minutes,omitempty"`
+	CancellationNoticeMinutes *int32    `json:"cancellation_notice_minutes,omitempty"`
+	PriceCents                int32     `json:"price_cents"`
+	Currency                  string    `json:"currency,omitempty"`
+	RequiresCredit            bool      `json:"requires_credit"`
+	Capacity                  int32     `json:"capacity"`
+	NoShowCreditPolicy        string    `json:"no_show_credit_policy"`
+	NotifyOwnerOnNoShow       bool      `json:"notify_owner_on_no_show"`
+	IsActive                  bool      `json:"is_active"`
+	CreatedAt                 time.Time `json:"created_at"`
 }
 
+// createSessionTypeRequest carries the session type, its booking rules and
+// price. Nil notices use the server-wide defaults; a nil cap or horizon means
+// no limit. A zero price makes the session type free; requires_credit makes a
+// free session type bookable only with a prepaid credit. A capacity above one
+// turns the session type into a group class; zero means one-to-one.
+// no_show_credit_policy decides whether a student who does not show up gets
+// their credit back ("restore") or loses it ("forfeit", the default).
 type createSessionTypeRequest struct {
-	Name            string `json:"name"`
-	Description     string `json:"description"`
-	DurationMinutes int32  `json:"duration_minutes"`
+	Name                      string `json:"name"`
+	Description               string `json:"description"`
+	DurationMinutes           int32  `json:"duration_minutes"`
+	BufferBeforeMinutes       int32  `json:"buffer_before_minutes"`
+	BufferAfterMinutes        int32  `json:"buffer_after_minutes"`
+	MaxSessionsPerDay         *int32 `json:"max_sessions_per_day,omitempty"`
+	BookingHorizonDays        *int32 `json:"booking_horizon_days,omitempty"`
+	MinBookingNoticeMinutes   *int32 `json:"min_booking_notice_minutes,omitempty"`
+	CancellationNoticeMinutes *int32 `json:"cancellation_notice_minutes,omitempty"`
+	PriceCents                int32  `json:"price_cents"`
+	Currency                  string `json:"currency,omitempty"` // ISO 4217; required when priced
+	RequiresCredit            bool   `json:"requires_credit"`
+	Capacity                  int32  `json:"capacity,omitempty"`
+	NoShowCreditPolicy        string `json:"no_show_credit_policy,omitempty"`
+	NotifyOwnerOnNoShow       bool   `json:"notify_owner_on_no_show"`
 }
 
 // updateSessionTypeRequest reuses the same fields as create.
@@ -38,17 +72,117 @@ type updateSessionTypeRequest = createSessionTypeRequest
 
 func toSessionTypeResponse(st db.CoachingSessionType) sessionTypeResponse {
 	return sessionTypeResponse{
-		ID:              uuidToString(st.ID),
-		ExpertID:        st.ExpertID,
-		GroupID:         uuidToString(st.GroupID),
-		Name:            st.Name,
-		Description:     st.Description,
-		DurationMinutes: st.DurationMinutes,
-		IsActive:        st.IsActive,
-		CreatedAt:       st.CreatedAt.Time,
+		ID:                        uuidToString(st.ID),
+		ExpertID:                  st.ExpertID,
+		GroupID:                   uuidToString(st.GroupID),
+		Name:                      st.Name,
+		Description:               st.Description,
+		DurationMinutes:           st.DurationMinutes,
+		BufferBeforeMinutes:       st.BufferBeforeMinutes,
+		BufferAfterMinutes:        st.BufferAfterMinutes,
+		MaxSessionsPerDay:         int4Ptr(st.MaxSessionsPerDay),
+		BookingHorizonDays:        int4Ptr(st.BookingHorizonDays),
+		MinBookingNoticeMinutes:   int4Ptr(st.MinBookingNoticeMinutes),
+		CancellationNoticeMinutes: int4Ptr(st.CancellationNoticeMinutes),
+		PriceCents:                st.PriceCents,
+		Currency:                  st.Currency.String,
+		RequiresCredit:            st.RequiresCredit,
+		Capacity:                  st.Capacity,
+		NoShowCreditPolicy:        st.NoShowCreditPolicy,
+		NotifyOwnerOnNoShow:       st.NotifyOwnerOnNoShow,
+		IsActive:                  st.IsActive,
+		CreatedAt:                 st.CreatedAt.Time,
 	}
 }
 
+func int4Ptr(v pgtype.Int4) *int32 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int32
+}
+
+func optionalInt4(p *int32) pgtype.Int4 {
+	if p == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *p, Valid: true}
+}
+
+// validateSessionTypeRules returns an error string suitable for http.Error
+// naming the first booking rule out of range, or "".
+func validateSessionTypeRules(f createSessionTypeRequest) string {
+	if f.BufferBeforeMinutes < 0 || f.BufferBeforeMinutes > MaxBufferMinutes {
+		return fmt.Sprintf("buffer_before_minutes must be between 0 and %d", MaxBufferMinutes)
+	}
+	if f.BufferAfterMinutes < 0 || f.BufferAfterMinutes > MaxBufferMinutes {
+		return fmt.Sprintf("buffer_after_minutes must be between 0 and %d", MaxBufferMinutes)
+	}
+	if v := f.MaxSessionsPerDay; v != nil && (*v < 1 || *v > MaxSessionsPerDayLimit) {
+		return fmt.Sprintf("max_sessions_per_day must be between 1 and %d", MaxSessionsPerDayLimit)
+	}
+	if v := f.BookingHorizonDays; v != nil && (*v < 1 || *v > MaxBookingHorizonDays) {
+		return fmt.Sprintf("booking_horizon_days must be between 1 and %d", MaxBookingHorizonDays)
+	}
+	if v := f.MinBookingNoticeMinutes; v != nil && (*v < 0 || *v > MaxNoticeMinutes) {
+		return fmt.Sprintf("min_booking_notice_minutes must be between 0 and %d", MaxNoticeMinutes)
+	}
+	if v := f.CancellationNoticeMinutes; v != nil && (*v < 0 || *v > MaxNoticeMinutes) {
+		return fmt.Sprintf("cancellation_notice_minutes must be between 0 and %d", MaxNoticeMinutes)
+	}
+	if f.Capacity < 0 || f.Capacity > MaxClassCapacity {
+		return fmt.Sprintf("capacity must be between 1 and %d", MaxClassCapacity)
+	}
+	switch f.NoShowCreditPolicy {
+	case "", NoShowCreditForfeit, NoShowCreditRestore:
+	default:
+		return fmt.Sprintf("no_show_credit_policy must be %q or %q", NoShowCreditForfeit, NoShowCreditRestore)
+	}
+	return ""
+}
+
+// validateSessionTypePrice normalizes the currency of f and returns an error
+// string suitable for http.Error, or "".
+func (h *Handler) validateSessionTypePrice(f *createSessionTypeRequest) string {
+	if f.PriceCents < 0 || f.PriceCents > MaxPriceCents {
+		return fmt.Sprintf("price_cents must be between 0 and %d", MaxPriceCents)
+	}
+	if f.PriceCents > 0 && f.RequiresCredit {
+		return "A session type is either priced or requires a credit, not both"
+	}
+	if f.PriceCents == 0 {
+		f.Currency = ""
+		return ""
+	}
+	f.Currency = strings.ToUpper(strings.TrimSpace(f.Currency))
+	if !isCurrencyCode(f.Currency) {
+		return "currency must be a three-letter ISO 4217 code"
+	}
+	if h.paymentProvider == nil {
+		return "Payments are not configured; price_cents must be 0"
+	}
+	return ""
+}
+
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func optionalText(s string) pgtype.Text {
+	if s == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: s, Valid: true}
+}
+
 func (h *Handler) isValidSessionDuration(durationMinutes int32) bool {
 	return durationMinutes >= h.minSessionDuration &&
 		durationMinutes <= MaxSessionDuration &&
@@ -144,13 +278,33 @@ func (h *Handler) CreateSessionType(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, h.sessionDurationValidationMessage(), http.StatusBadRequest)
 		return
 	}
+	if errMsg := validateSessionTypeRules(req); errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+	if errMsg := h.validateSessionTypePrice(&req); errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
 
 	st, err := h.q.CreateSessionType(ctx, db.CreateSessionTypeParams{
-		ExpertID:        user.ID,
-		GroupID:         groupID,
-		Name:            req.Name,
-		Description:     req.Description,
-		DurationMinutes: req.DurationMinutes,
+		ExpertID:                  user.ID,
+		GroupID:                   groupID,
+		Name:                      req.Name,
+		Description:               req.Description,
+		DurationMinutes:           req.DurationMinutes,
+		BufferBeforeMinutes:       req.BufferBeforeMinutes,
+		BufferAfterMinutes:        req.BufferAfterMinutes,
+		MaxSessionsPerDay:         optionalInt4(req.MaxSessionsPerDay),
+		BookingHorizonDays:        optionalInt4(req.BookingHorizonDays),
+		MinBookingNoticeMinutes:   optionalInt4(req.MinBookingNoticeMinutes),
+		CancellationNoticeMinutes: optionalInt4(req.CancellationNoticeMinutes),
+		PriceCents:                req.PriceCents,
+		Currency:                  optionalText(req.Currency),
+		RequiresCredit:            req.RequiresCredit,
+		Capacity:                  max(req.Capacity, 1),
+		NoShowCreditPolicy:        cmp.Or(req.NoShowCreditPolicy, NoShowCreditForfeit),
+		NotifyOwnerOnNoShow:       req.NotifyOwnerOnNoShow,
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "create_session_type_failed",
@@ -200,14 +354,34 @@ func (h *Handler) UpdateSessionType(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, h.sessionDurationValidationMessage(), http.StatusBadRequest)
 		return
 	}
+	if errMsg := validateSessionTypeRules(req); errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+	if errMsg := h.validateSessionTypePrice(&req); errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
 
 	st, err := h.q.UpdateSessionType(ctx, db.UpdateSessionTypeParams{
-		ID:              sessionTypeID,
-		Name:            req.Name,
-		Description:     req.Description,
-		DurationMinutes: req.DurationMinutes,
-		ExpertID:        user.ID,
-		GroupID:         groupID,
+		ID:                        sessionTypeID,
+		Name:                      req.Name,
+		Description:               req.Description,
+		DurationMinutes:           req.DurationMinutes,
+		ExpertID:                  user.ID,
+		GroupID:                   groupID,
+		BufferBeforeMinutes:       req.BufferBeforeMinutes,
+		BufferAfterMinutes:        req.BufferAfterMinutes,
+		MaxSessionsPerDay:         optionalInt4(req.MaxSessionsPerDay),
+		BookingHorizonDays:        optionalInt4(req.BookingHorizonDays),
+		MinBookingNoticeMinutes:   optionalInt4(req.MinBookingNoticeMinutes),
+		CancellationNoticeMinutes: optionalInt4(req.CancellationNoticeMinutes),
+		PriceCents:                req.PriceCents,
+		Currency:                  optionalText(req.Currency),
+		RequiresCredit:            req.RequiresCredit,
+		Capacity:                  max(req.Capacity, 1),
+		NoShowCreditPolicy:        cmp.Or(req.NoShowCreditPolicy, NoShowCreditForfeit),
+		NotifyOwnerOnNoShow:       req.NotifyOwnerOnNoShow,
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
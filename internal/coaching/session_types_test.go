@@ -1,6 +1,9 @@
 package coaching
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestIsValidSessionDuration(t *testing.T) {
 	defaultHandler := &Handler{
@@ -43,3 +46,69 @@ func TestIsValidSessionDurationAllowsOneMinuteDevSessions(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateSessionTypeRules(t *testing.T) {
+	n := func(v int32) *int32 { return &v }
+	tests := []struct {
+		name    string
+		req     createSessionTypeRequest
+		wantErr string
+	}{
+		{"defaults", createSessionTypeRequest{}, ""},
+		{"all rules set", createSessionTypeRequest{
+			BufferBeforeMinutes: 10, BufferAfterMinutes: 120, MaxSessionsPerDay: n(4),
+			BookingHorizonDays: n(60), MinBookingNoticeMinutes: n(0), CancellationNoticeMinutes: n(24 * 60),
+		}, ""},
+		{"negative buffer", createSessionTypeRequest{BufferBeforeMinutes: -5}, "buffer_before_minutes"},
+		{"long buffer", createSessionTypeRequest{BufferAfterMinutes: 121}, "buffer_after_minutes"},
+		{"zero daily cap", createSessionTypeRequest{MaxSessionsPerDay: n(0)}, "max_sessions_per_day"},
+		{"horizon over a year", createSessionTypeRequest{BookingHorizonDays: n(366)}, "booking_horizon_days"},
+		{"negative notice", createSessionTypeRequest{MinBookingNoticeMinutes: n(-1)}, "min_booking_notice_minutes"},
+		{"cancellation notice over 30 days", createSessionTypeRequest{CancellationNoticeMinutes: n(MaxNoticeMinutes + 1)}, "cancellation_notice_minutes"},
+		{"group class", createSessionTypeRequest{Capacity: MaxClassCapacity}, ""},
+		{"class over the limit", createSessionTypeRequest{Capacity: MaxClassCapacity + 1}, "capacity"},
+		{"no-show credits restored", createSessionTypeRequest{NoShowCreditPolicy: NoShowCreditRestore}, ""},
+		{"unknown no-show policy", createSessionTypeRequest{NoShowCreditPolicy: "refund"}, "no_show_credit_policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateSessionTypeRules(tt.req)
+			if tt.wantErr == "" && got != "" || !strings.HasPrefix(got, tt.wantErr) {
+				t.Fatalf("validateSessionTypeRules() = %q, want error naming %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSessionTypePrice(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          createSessionTypeRequest
+		provider     PaymentProvider
+		wantErr      string
+		wantCurrency string
+	}{
+		{"free", createSessionTypeRequest{Currency: "eur"}, nil, "", ""},
+		{"paid", createSessionTypeRequest{PriceCents: 2500, Currency: " eur "}, NewFakePaymentProvider(""), "", "EUR"},
+		{"negative price", createSessionTypeRequest{PriceCents: -1}, NewFakePaymentProvider(""), "price_cents", ""},
+		{"price over the limit", createSessionTypeRequest{PriceCents: MaxPriceCents + 1, Currency: "EUR"}, NewFakePaymentProvider(""), "price_cents", ""},
+		{"missing currency", createSessionTypeRequest{PriceCents: 2500}, NewFakePaymentProvider(""), "currency", ""},
+		{"invalid currency", createSessionTypeRequest{PriceCents: 2500, Currency: "EURO"}, NewFakePaymentProvider(""), "currency", ""},
+		{"no payment provider", createSessionTypeRequest{PriceCents: 2500, Currency: "EUR"}, nil, "Payments are not configured", ""},
+		{"credit only", createSessionTypeRequest{RequiresCredit: true}, nil, "", ""},
+		{"priced and credit only", createSessionTypeRequest{PriceCents: 2500, Currency: "EUR", RequiresCredit: true}, NewFakePaymentProvider(""), "A session type is either priced", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{paymentProvider: tt.provider}
+			req := tt.req
+			got := h.validateSessionTypePrice(&req)
+			if tt.wantErr == "" && got != "" || !strings.HasPrefix(got, tt.wantErr) {
+				t.Fatalf("validateSessionTypePrice() = %q, want error naming %q", got, tt.wantErr)
+			}
+			if tt.wantErr == "" && req.Currency != tt.wantCurrency {
+				t.Fatalf("currency = %q, want %q", req.Currency, tt.wantCurrency)
+			}
+		})
+	}
+}
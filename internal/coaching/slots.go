@@ -1,10 +1,12 @@
 package coaching
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/OZIOisgood/zeta/internal/auth"
@@ -14,12 +16,14 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// SlotResponse is the public shape of a computed availability slot.
+// SlotResponse is the public shape of a computed availability slot. SeatsLeft
+// is set only for group classes.
 type SlotResponse struct {
-	ExpertID string    `json:"expert_id"`
-	StartsAt time.Time `json:"starts_at"`
-	EndsAt   time.Time `json:"ends_at"`
-	Duration int32     `json:"duration_minutes"`
+	ExpertID  string    `json:"expert_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Duration  int32     `json:"duration_minutes"`
+	SeatsLeft int32     `json:"seats_left,omitempty"`
 }
 
 func (h *Handler) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
@@ -54,7 +58,7 @@ func (h *Handler) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Load session type to get duration.
+	// Load session type to get duration and booking rules.
 	sessionType, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{
 		ID:      sessionTypeID,
 		GroupID: groupID,
@@ -112,10 +116,25 @@ func (h *Handler) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. Fetch blocked slots in range.
+	// 3. Fetch extra dated hours and blocked slots in range.
 	var fromDate, toDate pgtype.Date
 	_ = fromDate.Scan(rangeStart.Format("2006-01-02"))
 	_ = toDate.Scan(rangeEnd.Format("2006-01-02"))
+	overrides, err := h.q.ListAvailabilityOverrides(ctx, db.ListAvailabilityOverridesParams{
+		ExpertID: expertID,
+		GroupID:  groupID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_availability_overrides_for_slots_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list availability overrides", http.StatusInternalServerError)
+		return
+	}
+
 	blocked, err := h.q.ListBlockedSlots(ctx, db.ListBlockedSlotsParams{
 		ExpertID: expertID,
 		FromDate: fromDate,
@@ -130,9 +149,10 @@ func (h *Handler) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 4. Fetch existing bookings in range.
+	// 4. Fetch existing bookings in range, from a day earlier so sessions
+	// earlier today count towards buffers and the daily cap.
 	var pgRangeStart, pgRangeEnd pgtype.Timestamptz
-	_ = pgRangeStart.Scan(rangeStart)
+	_ = pgRangeStart.Scan(rangeStart.AddDate(0, 0, -1))
 	_ = pgRangeEnd.Scan(rangeEnd)
 	bookings, err := h.q.ListBookingsByExpertInRange(ctx, db.ListBookingsByExpertInRangeParams{
 		ExpertID:      expertID,
@@ -148,9 +168,38 @@ func (h *Handler) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 5. Compute slots using the session type's duration.
-	minNotice := now.Add(h.minBookingNotice)
-	slots := computeSlots(avail, blocked, bookings, loc, rangeStart, rangeEnd, minNotice, sessionType.DurationMinutes)
+	// 5. Fetch absences and cached busy time from external calendars.
+	busy, err := listExpertBusy(ctx, h.q, expertID, loc, rangeStart, rangeEnd)
+	if err != nil {
+		log.ErrorContext(ctx, "list_expert_busy_for_slots_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list busy time", http.StatusInternalServerError)
+		return
+	}
+
+	// 6. Compute slots under the session type's rules.
+	rules := h.sessionTypeRules(sessionType)
+	slots := computeSlots(avail, overrides, blocked, bookings, busy, loc, rangeStart, rangeEnd, now, rules)
+
+	// 7. A group class also offers the seats left in its classes already under way.
+	if sessionType.Capacity > 1 {
+		classes, err := h.q.ListOpenClasses(ctx, db.ListOpenClassesParams{
+			SessionTypeID: sessionType.ID,
+			FromTime:      pgtype.Timestamptz{Time: rangeStart, Valid: true},
+			ToTime:        pgtype.Timestamptz{Time: rangeEnd, Valid: true},
+		})
+		if err != nil {
+			log.ErrorContext(ctx, "list_open_classes_failed",
+				slog.String("component", "coaching"),
+				slog.Any("err", err),
+			)
+			http.Error(w, "Failed to list classes", http.StatusInternalServerError)
+			return
+		}
+		slots = mergeClassSlots(slots, classes, sessionType.Capacity, now, rules)
+	}
 	if slots == nil {
 		slots = []SlotResponse{}
 	}
@@ -159,19 +208,139 @@ func (h *Handler) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(slots) //nolint:errcheck
 }
 
-// computeSlots generates available slot windows for an expert given a specific session duration.
+// userLocation loads the user's timezone, falling back to UTC when none is
+// stored or it does not parse.
+func userLocation(ctx context.Context, q db.Querier, userID string) (*time.Location, error) {
+	tz, err := q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+	return loc, nil
+}
+
+// openSlotStarts returns the slot starts computeSlots offers for the expert
+// under rules between the local days of from and to, keyed by Unix seconds.
+// The booking exclude (if valid) is ignored so it can move into a slot
+// overlapping its own time.
+func (h *Handler) openSlotStarts(
+	ctx context.Context,
+	q db.Querier,
+	expertID string,
+	groupID pgtype.UUID,
+	rules bookingRules,
+	exclude pgtype.UUID,
+	from, to time.Time,
+) (map[int64]bool, error) {
+	loc, err := userLocation(ctx, q, expertID)
+	if err != nil {
+		return nil, err
+	}
+
+	avail, err := q.ListAvailabilityByExpertGroup(ctx, db.ListAvailabilityByExpertGroupParams{
+		ExpertID: expertID,
+		GroupID:  groupID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pad the range by a local day on either side so windows crossing
+	// midnight are covered.
+	localFrom, localTo := from.In(loc), to.In(loc)
+	rangeStart := time.Date(localFrom.Year(), localFrom.Month(), localFrom.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	rangeEnd := time.Date(localTo.Year(), localTo.Month(), localTo.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 2)
+
+	var fromDate, toDate pgtype.Date
+	_ = fromDate.Scan(rangeStart.Format("2006-01-02"))
+	_ = toDate.Scan(rangeEnd.Format("2006-01-02"))
+	overrides, err := q.ListAvailabilityOverrides(ctx, db.ListAvailabilityOverridesParams{
+		ExpertID: expertID,
+		GroupID:  groupID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := q.ListBlockedSlots(ctx, db.ListBlockedSlotsParams{
+		ExpertID: expertID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := q.ListBookingsByExpertInRange(ctx, db.ListBookingsByExpertInRangeParams{
+		ExpertID:      expertID,
+		ScheduledAt:   pgtype.Timestamptz{Time: rangeStart, Valid: true},
+		ScheduledAt_2: pgtype.Timestamptz{Time: rangeEnd, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	others := make([]db.CoachingBooking, 0, len(bookings))
+	for _, o := range bookings {
+		if !exclude.Valid || o.ID != exclude {
+			others = append(others, o)
+		}
+	}
+
+	busy, err := listExpertBusy(ctx, q, expertID, loc, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[int64]bool)
+	for _, s := range computeSlots(avail, overrides, blocked, others, busy, loc, rangeStart, rangeEnd, time.Now(), rules) {
+		open[s.StartsAt.Unix()] = true
+	}
+	return open, nil
+}
+
+// computeSlots generates available slot windows for an expert under a session
+// type's rules. overrides add dated windows to the weekly availability; busy
+// holds absences and intervals imported from external calendars. Buffers keep
+// bookings and busy time clear around a slot but may extend past the
+// availability window.
 func computeSlots(
 	avail []db.CoachingAvailability,
+	overrides []db.CoachingAvailabilityOverride,
 	blocked []db.CoachingBlockedSlot,
 	bookings []db.CoachingBooking,
+	busy []busyInterval,
 	loc *time.Location,
-	rangeStart, rangeEnd, minNotice time.Time,
-	durationMinutes int32,
+	rangeStart, rangeEnd, now time.Time,
+	rules bookingRules,
 ) []SlotResponse {
+	durationMinutes := rules.DurationMinutes
 	duration := time.Duration(durationMinutes) * time.Minute
 	if duration <= 0 {
 		return nil
 	}
+	minNotice := now.Add(rules.MinNotice)
+
+	// Count sessions per local day for the daily cap; a class is one session
+	// however many seats it fills.
+	bookingsByDate := make(map[string]int)
+	countedClasses := make(map[pgtype.UUID]bool)
+	for _, b := range bookings {
+		if b.ClassID.Valid {
+			if countedClasses[b.ClassID] {
+				continue
+			}
+			countedClasses[b.ClassID] = true
+		}
+		bookingsByDate[b.ScheduledAt.Time.In(loc).Format("2006-01-02")]++
+	}
 
 	// Index availability by day_of_week.
 	byDay := make(map[int16][]db.CoachingAvailability)
@@ -179,6 +348,19 @@ func computeSlots(
 		byDay[a.DayOfWeek] = append(byDay[a.DayOfWeek], a)
 	}
 
+	// Index extra dated hours by date string, as availability windows.
+	overridesByDate := make(map[string][]db.CoachingAvailability)
+	for _, o := range overrides {
+		key := o.OverrideDate.Time.Format("2006-01-02")
+		overridesByDate[key] = append(overridesByDate[key], db.CoachingAvailability{
+			ExpertID:  o.ExpertID,
+			GroupID:   o.GroupID,
+			DayOfWeek: int16(o.OverrideDate.Time.Weekday()),
+			StartTime: o.StartTime,
+			EndTime:   o.EndTime,
+		})
+	}
+
 	// Index blocked slots by date string.
 	blockedByDate := make(map[string][]db.CoachingBlockedSlot)
 	for _, b := range blocked {
@@ -190,13 +372,25 @@ func computeSlots(
 	for d := rangeStart; d.Before(rangeEnd); d = d.AddDate(0, 0, 1) {
 		// day_of_week: 0=Sun … 6=Sat
 		dow := int16(d.Weekday())
-		dayAvail, ok := byDay[dow]
-		if !ok {
+		dateKey := d.Format("2006-01-02")
+		dayAvail := byDay[dow]
+		if extra := overridesByDate[dateKey]; len(extra) > 0 {
+			dayAvail = append(append([]db.CoachingAvailability(nil), dayAvail...), extra...)
+			sort.Slice(dayAvail, func(i, j int) bool {
+				return dayAvail[i].StartTime.Microseconds < dayAvail[j].StartTime.Microseconds
+			})
+		}
+		if len(dayAvail) == 0 {
+			continue
+		}
+		if rules.MaxPerDay > 0 && bookingsByDate[dateKey] >= rules.MaxPerDay {
 			continue
 		}
 
-		dateKey := d.Format("2006-01-02")
 		blockedToday := blockedByDate[dateKey]
+		// Extra hours may overlap the weekly windows; both share the
+		// duration grid, so each start is offered once.
+		offered := make(map[int64]bool)
 
 		for _, a := range dayAvail {
 			startH, startM := pgTimeParts(a.StartTime)
@@ -212,15 +406,27 @@ func computeSlots(
 				if slotStart.Before(minNotice) {
 					continue
 				}
+				if rules.Horizon > 0 && slotStart.Sub(now) > rules.Horizon {
+					continue
+				}
 
 				if isBlocked(slotStart, slotEnd, blockedToday, loc) {
 					continue
 				}
 
-				if overlapsBooking(slotStart, slotEnd, bookings) {
+				if withinBookingBuffers(slotStart, slotEnd, rules, bookings) {
+					continue
+				}
+
+				if overlapsBusy(slotStart.Add(-rules.BufferBefore), slotEnd.Add(rules.BufferAfter), busy) {
 					continue
 				}
 
+				if offered[slotStart.Unix()] {
+					continue
+				}
+				offered[slotStart.Unix()] = true
+
 				slots = append(slots, SlotResponse{
 					ExpertID: a.ExpertID,
 					StartsAt: slotStart,
@@ -281,3 +487,28 @@ func overlapsBooking(slotStart, slotEnd time.Time, bookings []db.CoachingBooking
 	}
 	return false
 }
+
+// withinBookingBuffers reports whether a slot is closer to a booking than the
+// larger of the two buffers between them. Zero buffers reduce it to an overlap
+// check.
+func withinBookingBuffers(slotStart, slotEnd time.Time, rules bookingRules, bookings []db.CoachingBooking) bool {
+	for _, b := range bookings {
+		before := max(rules.BufferBefore, time.Duration(b.BufferAfterMinutes)*time.Minute)
+		after := max(rules.BufferAfter, time.Duration(b.BufferBeforeMinutes)*time.Minute)
+		bookingStart := b.ScheduledAt.Time
+		bookingEnd := bookingStart.Add(time.Duration(b.DurationMinutes) * time.Minute)
+		if slotStart.Add(-before).Before(bookingEnd) && slotEnd.Add(after).After(bookingStart) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsBusy(slotStart, slotEnd time.Time, busy []busyInterval) bool {
+	for _, b := range busy {
+		if slotStart.Before(b.End) && slotEnd.After(b.Start) {
+			return true
+		}
+	}
+	return false
+}
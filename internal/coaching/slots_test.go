@@ -125,6 +125,10 @@ func TestComputeSlots(t *testing.T) {
 	end1030, _ := parseTime("10:30")
 	end1400, _ := parseTime("14:00")
 	end1700, _ := parseTime("17:00")
+	start1100, _ := parseTime("11:00")
+	end1300, _ := parseTime("13:00")
+	start1500, _ := parseTime("15:00")
+	mondayDate := pgtype.Date{Time: monday, Valid: true}
 
 	// Availability: Monday 09:00–12:00 UTC, yields three 60-min slots.
 	mondayAvail := []db.CoachingAvailability{{
@@ -137,24 +141,27 @@ func TestComputeSlots(t *testing.T) {
 	tests := []struct {
 		name       string
 		avail      []db.CoachingAvailability
+		overrides  []db.CoachingAvailabilityOverride
 		blocked    []db.CoachingBlockedSlot
 		bookings   []db.CoachingBooking
-		minNotice  time.Time
+		busy       []busyInterval
+		now        time.Time
 		duration   int32
+		rules      bookingRules
 		wantStarts []time.Time
 	}{
 		{
 			name:       "no availability: no slots",
 			avail:      nil,
-			minNotice:  monday,
+			now:        monday,
 			duration:   60,
 			wantStarts: nil,
 		},
 		{
-			name:      "basic: three 60-min slots in 09:00–12:00 window",
-			avail:     mondayAvail,
-			minNotice: monday, // far in the past, no filtering
-			duration:  60,
+			name:     "basic: three 60-min slots in 09:00–12:00 window",
+			avail:    mondayAvail,
+			now:      monday, // far in the past, no filtering
+			duration: 60,
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
 				time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC),
@@ -169,22 +176,23 @@ func TestComputeSlots(t *testing.T) {
 				ExpertID: "expert-1", DayOfWeek: 1,
 				StartTime: start900, EndTime: end1030,
 			}},
-			minNotice: monday,
-			duration:  60,
+			now:      monday,
+			duration: 60,
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
 			},
 		},
 		{
-			// minNotice = 10:30. Slots that START before 10:30 must be excluded.
+			// now + notice = 10:30. Slots that START before 10:30 must be excluded.
 			// 09:00 slot starts at 09:00 < 10:30 → excluded.
 			// 10:00 slot starts at 10:00 < 10:30 → excluded.   ← BUG: current
 			//   code checks slotEnd (11:00 > 10:30) and shows this slot.
 			// 11:00 slot starts at 11:00 ≥ 10:30 → shown.
-			name:      "minNotice: excludes slots whose START is before the notice deadline",
-			avail:     mondayAvail,
-			minNotice: time.Date(2025, 1, 20, 10, 30, 0, 0, time.UTC),
-			duration:  60,
+			name:     "minNotice: excludes slots whose START is before the notice deadline",
+			avail:    mondayAvail,
+			now:      time.Date(2025, 1, 20, 8, 30, 0, 0, time.UTC),
+			duration: 60,
+			rules:    bookingRules{MinNotice: 2 * time.Hour},
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC),
 			},
@@ -197,7 +205,7 @@ func TestComputeSlots(t *testing.T) {
 				BlockedDate: pgtype.Date{Time: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Valid: true},
 				// StartTime / EndTime left as zero → Valid:false → full-day block
 			}},
-			minNotice:  monday,
+			now:        monday,
 			duration:   60,
 			wantStarts: nil,
 		},
@@ -209,21 +217,159 @@ func TestComputeSlots(t *testing.T) {
 				ScheduledAt:     pgtype.Timestamptz{Time: time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC), Valid: true},
 				DurationMinutes: 60,
 			}},
-			minNotice: monday,
-			duration:  60,
+			now:      monday,
+			duration: 60,
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
 				time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC),
 			},
 		},
+		{
+			// A 15-minute buffer after a 60-minute slot reaches the 11:00
+			// booking, and the booking's own 30-minute buffer before it
+			// reaches back into the 10:00 slot too.
+			name:  "buffers: slots too close to a booking are removed",
+			avail: mondayAvail,
+			bookings: []db.CoachingBooking{{
+				ScheduledAt:         pgtype.Timestamptz{Time: time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC), Valid: true},
+				DurationMinutes:     60,
+				BufferBeforeMinutes: 30,
+			}},
+			now:      monday,
+			duration: 60,
+			rules:    bookingRules{BufferAfter: 15 * time.Minute},
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "buffers: busy time inside the buffer removes the slot",
+			avail: mondayAvail,
+			busy: []busyInterval{
+				{Start: time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 20, 12, 30, 0, 0, time.UTC)},
+			},
+			now:      monday,
+			duration: 60,
+			rules:    bookingRules{BufferAfter: 10 * time.Minute},
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "daily cap: a fully booked day offers no slots",
+			avail: mondayAvail,
+			bookings: []db.CoachingBooking{{
+				ScheduledAt:     pgtype.Timestamptz{Time: time.Date(2025, 1, 20, 7, 0, 0, 0, time.UTC), Valid: true},
+				DurationMinutes: 60,
+			}},
+			now:        monday,
+			duration:   60,
+			rules:      bookingRules{MaxPerDay: 1},
+			wantStarts: nil,
+		},
+		{
+			// Two seats of one class at 07:00 count as a single session.
+			name:  "daily cap: a class counts once",
+			avail: mondayAvail,
+			bookings: []db.CoachingBooking{
+				{
+					ScheduledAt:     pgtype.Timestamptz{Time: time.Date(2025, 1, 20, 7, 0, 0, 0, time.UTC), Valid: true},
+					DurationMinutes: 60,
+					ClassID:         pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+				},
+				{
+					ScheduledAt:     pgtype.Timestamptz{Time: time.Date(2025, 1, 20, 7, 0, 0, 0, time.UTC), Valid: true},
+					DurationMinutes: 60,
+					ClassID:         pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+				},
+			},
+			now:      monday,
+			duration: 60,
+			rules:    bookingRules{MaxPerDay: 2},
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "horizon: slots beyond the booking horizon are removed",
+			avail:    mondayAvail,
+			now:      time.Date(2025, 1, 18, 10, 0, 0, 0, time.UTC),
+			duration: 60,
+			rules:    bookingRules{Horizon: 2 * 24 * time.Hour},
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Busy 09:30–09:45 removes the 09:00 slot and 10:59–11:00 the
+			// 10:00 one; the adjacent 11:00 slot stays.
+			name:  "external busy: overlapping slots are removed",
+			avail: mondayAvail,
+			busy: []busyInterval{
+				{Start: time.Date(2025, 1, 20, 9, 30, 0, 0, time.UTC), End: time.Date(2025, 1, 20, 9, 45, 0, 0, time.UTC)},
+				{Start: time.Date(2025, 1, 20, 10, 59, 0, 0, time.UTC), End: time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC)},
+			},
+			now:      monday,
+			duration: 60,
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Extra hours 11:00–13:00 overlap the weekly 09:00–12:00 window;
+			// the shared 11:00 slot is offered once.
+			name:  "override: extra dated hours extend the day",
+			avail: mondayAvail,
+			overrides: []db.CoachingAvailabilityOverride{{
+				ExpertID: "expert-1", OverrideDate: mondayDate,
+				StartTime: start1100, EndTime: end1300,
+			}},
+			now:      monday,
+			duration: 60,
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 11, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "override: hours on a day without weekly availability",
+			overrides: []db.CoachingAvailabilityOverride{{
+				ExpertID: "expert-1", OverrideDate: mondayDate,
+				StartTime: start1500, EndTime: end1700,
+			}},
+			now:      monday,
+			duration: 60,
+			wantStarts: []time.Time{
+				time.Date(2025, 1, 20, 15, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 20, 16, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "absence: whole day removes weekly and extra hours",
+			avail: mondayAvail,
+			overrides: []db.CoachingAvailabilityOverride{{
+				ExpertID: "expert-1", OverrideDate: mondayDate,
+				StartTime: start1500, EndTime: end1700,
+			}},
+			busy:       []busyInterval{absenceInterval(mondayDate, mondayDate, loc)},
+			now:        monday,
+			duration:   60,
+			wantStarts: nil,
+		},
 		{
 			name: "15-minute slots align odd availability starts to the next duration boundary",
 			avail: []db.CoachingAvailability{{
 				ExpertID: "expert-1", DayOfWeek: 1,
 				StartTime: start1601, EndTime: end1700,
 			}},
-			minNotice: monday,
-			duration:  15,
+			now:      monday,
+			duration: 15,
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 16, 15, 0, 0, time.UTC),
 				time.Date(2025, 1, 20, 16, 30, 0, 0, time.UTC),
@@ -236,8 +382,8 @@ func TestComputeSlots(t *testing.T) {
 				ExpertID: "expert-1", DayOfWeek: 1,
 				StartTime: start1646, EndTime: end1700,
 			}},
-			minNotice: monday,
-			duration:  5,
+			now:      monday,
+			duration: 5,
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 16, 50, 0, 0, time.UTC),
 				time.Date(2025, 1, 20, 16, 55, 0, 0, time.UTC),
@@ -249,8 +395,8 @@ func TestComputeSlots(t *testing.T) {
 				ExpertID: "expert-1", DayOfWeek: 1,
 				StartTime: start1333, EndTime: end1400,
 			}},
-			minNotice: monday,
-			duration:  10,
+			now:      monday,
+			duration: 10,
 			wantStarts: []time.Time{
 				time.Date(2025, 1, 20, 13, 40, 0, 0, time.UTC),
 				time.Date(2025, 1, 20, 13, 50, 0, 0, time.UTC),
@@ -260,8 +406,10 @@ func TestComputeSlots(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := computeSlots(tc.avail, tc.blocked, tc.bookings, loc,
-				rangeStart, rangeEnd, tc.minNotice, tc.duration)
+			rules := tc.rules
+			rules.DurationMinutes = tc.duration
+			got := computeSlots(tc.avail, tc.overrides, tc.blocked, tc.bookings, tc.busy, loc,
+				rangeStart, rangeEnd, tc.now, rules)
 
 			if len(got) != len(tc.wantStarts) {
 				t.Fatalf("got %d slots %v, want %d %v",
@@ -0,0 +1,928 @@
+package coaching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// errWaitlistSlotTaken reports that a held or offered time was booked or
+// held by someone else first.
+var errWaitlistSlotTaken = errors.New("waitlist slot taken")
+
+// --- DTO ---
+
+// waitlistWindow is a preferred time of day in the student's timezone.
+type waitlistWindow struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// waitlistHoldResponse is a freed slot held for the student until ExpiresAt.
+type waitlistHoldResponse struct {
+	ID              string    `json:"id"`
+	ScheduledAt     time.Time `json:"scheduled_at"`
+	DurationMinutes int32     `json:"duration_minutes"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+type waitlistEntryResponse struct {
+	ID                string                `json:"id"`
+	ExpertID          string                `json:"expert_id"`
+	GroupID           string                `json:"group_id"`
+	SessionTypeID     string                `json:"session_type_id"`
+	SessionTypeName   string                `json:"session_type_name,omitempty"`
+	PreferredWeekdays []int16               `json:"preferred_weekdays"`
+	PreferredWindows  []waitlistWindow      `json:"preferred_windows"`
+	Hold              *waitlistHoldResponse `json:"hold,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+type joinWaitlistRequest struct {
+	ExpertID          string           `json:"expert_id"`
+	SessionTypeID     string           `json:"session_type_id"`
+	PreferredWeekdays []int16          `json:"preferred_weekdays,omitempty"`
+	PreferredWindows  []waitlistWindow `json:"preferred_windows,omitempty"`
+}
+
+func toWaitlistWindows(windows []db.CoachingWaitlistWindow) []waitlistWindow {
+	resp := make([]waitlistWindow, len(windows))
+	for i, w := range windows {
+		resp[i] = waitlistWindow{StartTime: pgTimeToString(w.StartTime), EndTime: pgTimeToString(w.EndTime)}
+	}
+	return resp
+}
+
+func toWaitlistEntryResponseFromRow(e db.ListMyWaitlistEntriesRow, windows []db.CoachingWaitlistWindow) waitlistEntryResponse {
+	resp := waitlistEntryResponse{
+		ID:                uuidToString(e.ID),
+		ExpertID:          e.ExpertID,
+		GroupID:           uuidToString(e.GroupID),
+		SessionTypeID:     uuidToString(e.SessionTypeID),
+		SessionTypeName:   e.SessionTypeName,
+		PreferredWeekdays: e.PreferredWeekdays,
+		PreferredWindows:  toWaitlistWindows(windows),
+		CreatedAt:         e.CreatedAt.Time,
+	}
+	if resp.PreferredWeekdays == nil {
+		resp.PreferredWeekdays = []int16{}
+	}
+	if e.HoldID.Valid {
+		resp.Hold = &waitlistHoldResponse{
+			ID:              uuidToString(e.HoldID),
+			ScheduledAt:     e.HoldScheduledAt.Time,
+			DurationMinutes: e.HoldDurationMinutes.Int32,
+			ExpiresAt:       e.HoldExpiresAt.Time,
+		}
+	}
+	return resp
+}
+
+// --- Handlers ---
+
+func (h *Handler) ListMyWaitlistEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.q.ListMyWaitlistEntries(ctx, db.ListMyWaitlistEntriesParams{
+		StudentID: user.ID,
+		GroupID:   groupID,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_waitlist_entries_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list waitlist entries", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]waitlistEntryResponse, 0, len(entries))
+	if len(entries) == 0 {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	ids := make([]pgtype.UUID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	windows, err := h.q.ListWaitlistWindows(ctx, ids)
+	if err != nil {
+		log.ErrorContext(ctx, "list_waitlist_windows_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list waitlist entries", http.StatusInternalServerError)
+		return
+	}
+	byEntry := groupWaitlistWindows(windows)
+	for _, e := range entries {
+		resp = append(resp, toWaitlistEntryResponseFromRow(e, byEntry[e.ID]))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// JoinWaitlist puts the student on the expert's waitlist for a session type.
+// Freed slots matching the preferences are held for the earliest entries.
+func (h *Handler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req joinWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ExpertID == "" {
+		http.Error(w, "expert_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.ExpertID == user.ID {
+		http.Error(w, "You cannot join your own waitlist", http.StatusBadRequest)
+		return
+	}
+
+	sessionTypeID, err := parseUUID(req.SessionTypeID)
+	if err != nil {
+		http.Error(w, "Invalid session_type_id", http.StatusBadRequest)
+		return
+	}
+
+	weekdays, starts, ends, errMsg := parseWaitlistPreferences(req.PreferredWeekdays, req.PreferredWindows)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	sessionType, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{
+		ID:      sessionTypeID,
+		GroupID: groupID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Session type not found", http.StatusNotFound)
+			return
+		}
+		log.ErrorContext(ctx, "get_session_type_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get session type", http.StatusInternalServerError)
+		return
+	}
+	if !sessionType.IsActive || sessionType.ExpertID != req.ExpertID {
+		http.Error(w, "Session type not found", http.StatusNotFound)
+		return
+	}
+	// A full class frees no slot for anyone else, so there is nothing to wait for.
+	if sessionType.Capacity > 1 {
+		http.Error(w, "Group classes have no waitlist", http.StatusBadRequest)
+		return
+	}
+
+	entry, windows, err := h.createWaitlistEntry(ctx, db.CreateWaitlistEntryParams{
+		StudentID:         user.ID,
+		ExpertID:          req.ExpertID,
+		GroupID:           groupID,
+		SessionTypeID:     sessionTypeID,
+		PreferredWeekdays: weekdays,
+	}, starts, ends)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			http.Error(w, "You are already on the waitlist for this session type", http.StatusConflict)
+			return
+		}
+		log.ErrorContext(ctx, "create_waitlist_entry_failed",
+			slog.String("component", "coaching"),
+			slog.String("user_id", user.ID),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to join waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	// Slots may already be open at times the student prefers.
+	now := time.Now()
+	h.fillFromWaitlist(entry.ExpertID, now, now.AddDate(0, 0, SlotLookaheadDays))
+
+	writeJSON(w, http.StatusCreated, toWaitlistEntryResponseFromRow(db.ListMyWaitlistEntriesRow{
+		ID:                entry.ID,
+		StudentID:         entry.StudentID,
+		ExpertID:          entry.ExpertID,
+		GroupID:           entry.GroupID,
+		SessionTypeID:     entry.SessionTypeID,
+		PreferredWeekdays: entry.PreferredWeekdays,
+		CreatedAt:         entry.CreatedAt,
+		SessionTypeName:   sessionType.Name,
+	}, windows))
+}
+
+// LeaveWaitlist removes the entry; a slot still held for it goes to the next
+// student in line.
+func (h *Handler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entryID, err := parseUUID(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.q.GetOfferedWaitlistHold(ctx, entryID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.ErrorContext(ctx, "get_waitlist_hold_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(entryID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to leave waitlist", http.StatusInternalServerError)
+		return
+	}
+
+	n, err := h.q.DeleteWaitlistEntry(ctx, db.DeleteWaitlistEntryParams{
+		ID:        entryID,
+		StudentID: user.ID,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "delete_waitlist_entry_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(entryID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to leave waitlist", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Waitlist entry not found", http.StatusNotFound)
+		return
+	}
+
+	if hold.ID.Valid && hold.ExpiresAt.Time.After(time.Now()) {
+		h.fillFromWaitlist(hold.ExpertID, hold.ScheduledAt.Time, holdEnd(hold))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ConfirmWaitlistHold books the slot held for the entry and takes the student
+// off the waitlist.
+func (h *Handler) ConfirmWaitlistHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entry, hold, ok := h.loadWaitlistHold(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if !hold.ExpiresAt.Time.After(now) {
+		http.Error(w, "The hold has expired", http.StatusConflict)
+		return
+	}
+
+	sessionType, err := h.q.GetSessionType(ctx, db.GetSessionTypeParams{
+		ID:      entry.SessionTypeID,
+		GroupID: entry.GroupID,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "get_session_type_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(entry.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to get session type", http.StatusInternalServerError)
+		return
+	}
+	if !sessionType.IsActive {
+		http.Error(w, "Session type is no longer offered", http.StatusConflict)
+		return
+	}
+	if sessionType.PriceCents > 0 && h.paymentProvider == nil {
+		http.Error(w, "Payments are not configured", http.StatusServiceUnavailable)
+		return
+	}
+	rules := h.sessionTypeRules(sessionType)
+	rules.DurationMinutes = hold.DurationMinutes
+	if msg := rules.checkStart(now, hold.ScheduledAt.Time); msg != "" {
+		http.Error(w, "Held time is no longer available: "+msg, http.StatusConflict)
+		return
+	}
+
+	booking, err := h.confirmWaitlistHold(ctx, entry, hold, sessionType, rules)
+	if err != nil {
+		var ruleErr *bookingRuleError
+		switch {
+		case errors.Is(err, errWaitlistSlotTaken):
+			http.Error(w, "Held time is no longer available", http.StatusConflict)
+		case errors.Is(err, errNoCredit):
+			http.Error(w, "No session credit available", http.StatusConflict)
+		case errors.As(err, &ruleErr):
+			http.Error(w, "Held time is no longer available: "+ruleErr.msg, http.StatusConflict)
+		case errors.Is(err, pgx.ErrNoRows):
+			http.Error(w, "The hold has changed", http.StatusConflict)
+		default:
+			log.ErrorContext(ctx, "confirm_waitlist_hold_failed",
+				slog.String("component", "coaching"),
+				slog.String("hold_id", uuidToString(hold.ID)),
+				slog.Any("err", err),
+			)
+			http.Error(w, "Failed to create booking", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	checkoutURL, err := h.completeNewBooking(ctx, booking, sessionType.Name, user.Email)
+	if err != nil {
+		log.ErrorContext(ctx, "start_checkout_failed",
+			slog.String("component", "coaching"),
+			slog.String("booking_id", uuidToString(booking.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to start checkout", http.StatusBadGateway)
+		return
+	}
+
+	users, err := h.resolveUsers(ctx, []string{booking.ExpertID, booking.StudentID})
+	if err != nil {
+		log.ErrorContext(ctx, "resolve_booking_users_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to resolve booking users", http.StatusInternalServerError)
+		return
+	}
+	resp := toBookingResponse(booking, users, sessionType.Name)
+	resp.CheckoutURL = checkoutURL
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// DeclineWaitlistHold releases the slot held for the entry to the next
+// student in line. The entry stays on the waitlist for later slots.
+func (h *Handler) DeclineWaitlistHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+	user := auth.GetUser(ctx)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entry, hold, ok := h.loadWaitlistHold(ctx, w, r, user.ID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.q.RespondToWaitlistHold(ctx, db.RespondToWaitlistHoldParams{
+		Status: db.CoachingWaitlistHoldStatusDeclined,
+		ID:     hold.ID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "No slot is held for this entry", http.StatusNotFound)
+			return
+		}
+		log.ErrorContext(ctx, "decline_waitlist_hold_failed",
+			slog.String("component", "coaching"),
+			slog.String("hold_id", uuidToString(hold.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to decline hold", http.StatusInternalServerError)
+		return
+	}
+
+	if hold.ExpiresAt.Time.After(time.Now()) {
+		go h.offerWaitlistSlots(context.Background(), hold.ExpertID, hold.ScheduledAt.Time, holdEnd(hold), entry.ID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ProcessWaitlistHolds is an internal endpoint called by Cloud Scheduler. It
+// expires holds nobody answered in time and offers their slots to the next
+// students in line.
+func (h *Handler) ProcessWaitlistHolds(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	expired, err := h.q.ExpireWaitlistHolds(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "expire_waitlist_holds_failed",
+			slog.String("component", "coaching"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to expire waitlist holds", http.StatusInternalServerError)
+		return
+	}
+
+	offered := 0
+	for _, hold := range expired {
+		offered += h.offerWaitlistSlots(ctx, hold.ExpertID, hold.ScheduledAt.Time, holdEnd(hold), hold.EntryID)
+	}
+
+	log.InfoContext(ctx, "waitlist_holds_processed",
+		slog.String("component", "coaching"),
+		slog.Int("expired", len(expired)),
+		slog.Int("offered", offered),
+	)
+
+	writeJSON(w, http.StatusOK, map[string]int{"expired": len(expired), "offered": offered})
+}
+
+// --- Helpers ---
+
+// loadWaitlistHold fetches the caller's waiting entry from the URL and the
+// slot currently offered to it, writing the error response when either is
+// missing.
+func (h *Handler) loadWaitlistHold(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) (db.CoachingWaitlistEntry, db.CoachingWaitlistHold, bool) {
+	log := logger.From(ctx, h.logger)
+
+	groupID, err := parseGroupID(r)
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return db.CoachingWaitlistEntry{}, db.CoachingWaitlistHold{}, false
+	}
+	entryID, err := parseUUID(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+		return db.CoachingWaitlistEntry{}, db.CoachingWaitlistHold{}, false
+	}
+
+	entry, err := h.q.GetWaitlistEntry(ctx, db.GetWaitlistEntryParams{
+		ID:        entryID,
+		StudentID: userID,
+	})
+	if err == nil && entry.GroupID != groupID {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Waitlist entry not found", http.StatusNotFound)
+			return db.CoachingWaitlistEntry{}, db.CoachingWaitlistHold{}, false
+		}
+		log.ErrorContext(ctx, "get_waitlist_entry_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(entryID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch waitlist entry", http.StatusInternalServerError)
+		return db.CoachingWaitlistEntry{}, db.CoachingWaitlistHold{}, false
+	}
+
+	hold, err := h.q.GetOfferedWaitlistHold(ctx, entry.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "No slot is held for this entry", http.StatusNotFound)
+			return db.CoachingWaitlistEntry{}, db.CoachingWaitlistHold{}, false
+		}
+		log.ErrorContext(ctx, "get_waitlist_hold_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(entry.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch waitlist hold", http.StatusInternalServerError)
+		return db.CoachingWaitlistEntry{}, db.CoachingWaitlistHold{}, false
+	}
+	return entry, hold, true
+}
+
+// parseWaitlistPreferences validates the preferred weekdays (0 = Sunday) and
+// time windows of a join request, dropping repeated weekdays. Returns an error
+// string suitable for http.Error.
+func parseWaitlistPreferences(weekdays []int16, windows []waitlistWindow) (days []int16, starts, ends []pgtype.Time, errMsg string) {
+	seen := make(map[int16]bool, len(weekdays))
+	days = make([]int16, 0, len(weekdays))
+	for _, d := range weekdays {
+		if d < 0 || d > 6 {
+			return nil, nil, nil, "preferred_weekdays must be 0–6"
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	if len(windows) > MaxWaitlistWindows {
+		return nil, nil, nil, "At most 5 preferred_windows are allowed"
+	}
+	for _, win := range windows {
+		start, end, msg := parseTimeRange(win.StartTime, win.EndTime)
+		if msg != "" {
+			return nil, nil, nil, "preferred_windows: " + msg
+		}
+		starts = append(starts, start)
+		ends = append(ends, end)
+	}
+	return days, starts, ends, ""
+}
+
+// matchesWaitlistPreferences reports whether a session starting at start
+// suits the student: on a preferred weekday and inside a preferred window,
+// both in the student's timezone loc. No weekdays or no windows mean any.
+func matchesWaitlistPreferences(start time.Time, durationMinutes int32, weekdays []int16, windows []db.CoachingWaitlistWindow, loc *time.Location) bool {
+	local := start.In(loc)
+	if len(weekdays) > 0 {
+		found := false
+		for _, d := range weekdays {
+			if time.Weekday(d) == local.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(windows) == 0 {
+		return true
+	}
+	startMinute := local.Hour()*60 + local.Minute()
+	endMinute := startMinute + int(durationMinutes)
+	for _, win := range windows {
+		wh, wm := pgTimeParts(win.StartTime)
+		eh, em := pgTimeParts(win.EndTime)
+		if startMinute >= wh*60+wm && endMinute <= eh*60+em {
+			return true
+		}
+	}
+	return false
+}
+
+func groupWaitlistWindows(windows []db.CoachingWaitlistWindow) map[pgtype.UUID][]db.CoachingWaitlistWindow {
+	byEntry := make(map[pgtype.UUID][]db.CoachingWaitlistWindow)
+	for _, win := range windows {
+		byEntry[win.EntryID] = append(byEntry[win.EntryID], win)
+	}
+	return byEntry
+}
+
+func holdEnd(hold db.CoachingWaitlistHold) time.Time {
+	return hold.ScheduledAt.Time.Add(time.Duration(hold.DurationMinutes) * time.Minute)
+}
+
+// fillFromWaitlist offers the expert's open slots overlapping [from, to) to
+// waiting students. Runs detached from the request context.
+func (h *Handler) fillFromWaitlist(expertID string, from, to time.Time) {
+	go h.offerWaitlistSlots(context.Background(), expertID, from, to, pgtype.UUID{})
+}
+
+// offerWaitlistSlots holds an open slot of the expert overlapping [from, to)
+// for each of the earliest waiting entries whose preferences it matches, and
+// tells the students. skip (if valid) is an entry that just gave up a slot in
+// the range. Failures are logged and the entry skipped; it returns the number
+// of holds made.
+func (h *Handler) offerWaitlistSlots(ctx context.Context, expertID string, from, to time.Time, skip pgtype.UUID) int {
+	log := logger.From(ctx, h.logger)
+	now := time.Now()
+	if from.Before(now) {
+		from = now
+	}
+	if !to.After(from) {
+		return 0
+	}
+
+	entries, err := h.q.ListWaitingEntriesByExpert(ctx, db.ListWaitingEntriesByExpertParams{
+		ExpertID: expertID,
+		Limit:    WaitlistOfferBatchSize,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_waitlist_entries_for_offer_failed",
+			slog.String("component", "coaching"),
+			slog.String("expert_id", expertID),
+			slog.Any("err", err),
+		)
+		return 0
+	}
+	if len(entries) == 0 {
+		return 0
+	}
+	ids := make([]pgtype.UUID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	windows, err := h.q.ListWaitlistWindows(ctx, ids)
+	if err != nil {
+		log.ErrorContext(ctx, "list_waitlist_windows_for_offer_failed",
+			slog.String("component", "coaching"),
+			slog.String("expert_id", expertID),
+			slog.Any("err", err),
+		)
+		return 0
+	}
+	byEntry := groupWaitlistWindows(windows)
+
+	offered := 0
+	for _, e := range entries {
+		if skip.Valid && e.ID == skip {
+			continue
+		}
+		hold, sessionType, ok, err := h.offerWaitlistSlot(ctx, e, byEntry[e.ID], from, to, now)
+		if err != nil {
+			log.ErrorContext(ctx, "offer_waitlist_slot_failed",
+				slog.String("component", "coaching"),
+				slog.String("entry_id", uuidToString(e.ID)),
+				slog.Any("err", err),
+			)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		offered++
+		log.InfoContext(ctx, "waitlist_slot_offered",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(e.ID)),
+			slog.String("hold_id", uuidToString(hold.ID)),
+			slog.String("scheduled_at", hold.ScheduledAt.Time.UTC().Format(time.RFC3339)),
+		)
+		h.notifyWaitlistSlotOffered(ctx, e, hold, sessionType.Name)
+	}
+	return offered
+}
+
+// offerWaitlistSlot holds the earliest open slot overlapping [from, to) that
+// suits the entry. ok is false when none does.
+func (h *Handler) offerWaitlistSlot(ctx context.Context, e db.CoachingWaitlistEntry, windows []db.CoachingWaitlistWindow, from, to, now time.Time) (hold db.CoachingWaitlistHold, sessionType db.CoachingSessionType, ok bool, err error) {
+	sessionType, err = h.q.GetSessionType(ctx, db.GetSessionTypeParams{ID: e.SessionTypeID, GroupID: e.GroupID})
+	if err != nil {
+		return hold, sessionType, false, err
+	}
+	rules := h.sessionTypeRules(sessionType)
+	duration := time.Duration(rules.DurationMinutes) * time.Minute
+
+	// Slots are computed with active holds as busy time, so slots held for
+	// earlier entries in this run are already gone.
+	open, err := h.openSlotStarts(ctx, h.q, e.ExpertID, e.GroupID, rules, pgtype.UUID{}, from.Add(-duration), to)
+	if err != nil {
+		return hold, sessionType, false, err
+	}
+	studentLoc, err := userLocation(ctx, h.q, e.StudentID)
+	if err != nil {
+		return hold, sessionType, false, err
+	}
+
+	starts := make([]int64, 0, len(open))
+	for s := range open {
+		starts = append(starts, s)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	for _, s := range starts {
+		start := time.Unix(s, 0)
+		if !start.Before(to) || !start.Add(duration).After(from) {
+			continue
+		}
+		if !matchesWaitlistPreferences(start, rules.DurationMinutes, e.PreferredWeekdays, windows, studentLoc) {
+			continue
+		}
+		hold, err = h.createWaitlistHold(ctx, e, rules, start, now)
+		if errors.Is(err, errWaitlistSlotTaken) {
+			continue
+		}
+		if err != nil {
+			return hold, sessionType, false, err
+		}
+		return hold, sessionType, true, nil
+	}
+	return hold, sessionType, false, nil
+}
+
+// createWaitlistHold runs createWaitlistHoldTx, retrying up to 3× on
+// serialization failure like CreateBooking.
+func (h *Handler) createWaitlistHold(ctx context.Context, e db.CoachingWaitlistEntry, rules bookingRules, start, now time.Time) (db.CoachingWaitlistHold, error) {
+	const maxRetries = 3
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		var hold db.CoachingWaitlistHold
+		hold, err = h.createWaitlistHoldTx(ctx, e, rules, start, now)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "40001" {
+			continue
+		}
+		return hold, err
+	}
+	return db.CoachingWaitlistHold{}, err
+}
+
+// createWaitlistHoldTx re-checks conflicts, holds and the buffer and daily-cap
+// rules, then holds the slot in one SERIALIZABLE transaction. The hold lasts
+// the configured TTL but ends no later than the session type's booking notice
+// allows.
+func (h *Handler) createWaitlistHoldTx(ctx context.Context, e db.CoachingWaitlistEntry, rules bookingRules, start, now time.Time) (db.CoachingWaitlistHold, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return db.CoachingWaitlistHold{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	var slotStart, slotEnd pgtype.Timestamptz
+	_ = slotStart.Scan(start)
+	_ = slotEnd.Scan(start.Add(time.Duration(rules.DurationMinutes) * time.Minute))
+	conflicts, err := qtx.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
+		ExpertID:      e.ExpertID,
+		ScheduledAt:   slotStart,
+		ScheduledAt_2: slotEnd,
+	})
+	if err != nil {
+		return db.CoachingWaitlistHold{}, err
+	}
+	if conflicts > 0 {
+		return db.CoachingWaitlistHold{}, errWaitlistSlotTaken
+	}
+	msg, err := checkBookingRulesInTx(ctx, qtx, rules, e.ExpertID, start, pgtype.UUID{})
+	if err != nil {
+		return db.CoachingWaitlistHold{}, err
+	}
+	if msg != "" {
+		return db.CoachingWaitlistHold{}, errWaitlistSlotTaken
+	}
+
+	expiresAt := now.Add(h.waitlistHoldTTL)
+	if latest := start.Add(-rules.MinNotice); latest.Before(expiresAt) {
+		expiresAt = latest
+	}
+	hold, err := qtx.CreateWaitlistHold(ctx, db.CreateWaitlistHoldParams{
+		EntryID:         e.ID,
+		ExpertID:        e.ExpertID,
+		ScheduledAt:     slotStart,
+		DurationMinutes: rules.DurationMinutes,
+		ExpiresAt:       pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return db.CoachingWaitlistHold{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingWaitlistHold{}, err
+	}
+	return hold, nil
+}
+
+// createWaitlistEntry inserts the entry and its preferred windows in one
+// transaction.
+func (h *Handler) createWaitlistEntry(ctx context.Context, arg db.CreateWaitlistEntryParams, starts, ends []pgtype.Time) (db.CoachingWaitlistEntry, []db.CoachingWaitlistWindow, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return db.CoachingWaitlistEntry{}, nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	entry, err := qtx.CreateWaitlistEntry(ctx, arg)
+	if err != nil {
+		return db.CoachingWaitlistEntry{}, nil, err
+	}
+	var windows []db.CoachingWaitlistWindow
+	if len(starts) > 0 {
+		if err := qtx.InsertWaitlistWindows(ctx, db.InsertWaitlistWindowsParams{
+			EntryID:    entry.ID,
+			StartTimes: starts,
+			EndTimes:   ends,
+		}); err != nil {
+			return db.CoachingWaitlistEntry{}, nil, err
+		}
+		if windows, err = qtx.ListWaitlistWindows(ctx, []pgtype.UUID{entry.ID}); err != nil {
+			return db.CoachingWaitlistEntry{}, nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingWaitlistEntry{}, nil, err
+	}
+	return entry, windows, nil
+}
+
+// confirmWaitlistHold runs confirmWaitlistHoldTx, retrying up to 3× on
+// serialization failure like CreateBooking.
+func (h *Handler) confirmWaitlistHold(ctx context.Context, entry db.CoachingWaitlistEntry, hold db.CoachingWaitlistHold, sessionType db.CoachingSessionType, rules bookingRules) (db.CoachingBooking, error) {
+	const maxRetries = 3
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		var booking db.CoachingBooking
+		booking, err = h.confirmWaitlistHoldTx(ctx, entry, hold, sessionType, rules)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "40001" {
+			continue
+		}
+		return booking, err
+	}
+	return db.CoachingBooking{}, err
+}
+
+// confirmWaitlistHoldTx re-checks conflicts and the booking rules, books the
+// held slot (taking a credit if the session type needs one), closes the hold,
+// takes the entry off the waitlist and records booking.created in one
+// SERIALIZABLE transaction.
+func (h *Handler) confirmWaitlistHoldTx(ctx context.Context, entry db.CoachingWaitlistEntry, hold db.CoachingWaitlistHold, sessionType db.CoachingSessionType, rules bookingRules) (db.CoachingBooking, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := db.New(tx)
+
+	var slotEnd pgtype.Timestamptz
+	_ = slotEnd.Scan(holdEnd(hold))
+	conflicts, err := qtx.CountConflictingBookings(ctx, db.CountConflictingBookingsParams{
+		ExpertID:      hold.ExpertID,
+		ScheduledAt:   hold.ScheduledAt,
+		ScheduledAt_2: slotEnd,
+	})
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if conflicts > 0 {
+		return db.CoachingBooking{}, errWaitlistSlotTaken
+	}
+	msg, err := checkBookingRulesInTx(ctx, qtx, rules, hold.ExpertID, hold.ScheduledAt.Time, hold.ID)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if msg != "" {
+		return db.CoachingBooking{}, &bookingRuleError{msg: msg}
+	}
+
+	arg := db.CreateBookingParams{
+		ExpertID:            hold.ExpertID,
+		StudentID:           entry.StudentID,
+		GroupID:             entry.GroupID,
+		SessionTypeID:       entry.SessionTypeID,
+		ScheduledAt:         hold.ScheduledAt,
+		DurationMinutes:     hold.DurationMinutes,
+		BufferBeforeMinutes: sessionType.BufferBeforeMinutes,
+		BufferAfterMinutes:  sessionType.BufferAfterMinutes,
+	}
+	h.setBookingPrice(&arg, sessionType)
+	grant, err := reserveCreditInTx(ctx, qtx, &arg, sessionType)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	booking, err := qtx.CreateBooking(ctx, arg)
+	if err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.recordCreditConsumedInTx(ctx, tx, grant, booking); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if _, err := qtx.RespondToWaitlistHold(ctx, db.RespondToWaitlistHoldParams{
+		Status:    db.CoachingWaitlistHoldStatusConfirmed,
+		BookingID: booking.ID,
+		ID:        hold.ID,
+	}); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := qtx.MarkWaitlistEntryBooked(ctx, entry.ID); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := h.audit.Record(ctx, tx, bookingEvent(audit.ActionBookingCreated, booking, nil)); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.CoachingBooking{}, err
+	}
+	return booking, nil
+}
@@ -0,0 +1,90 @@
+package coaching
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/email"
+	"github.com/OZIOisgood/zeta/internal/i18n"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/OZIOisgood/zeta/internal/preferences"
+)
+
+// notifyWaitlistSlotOffered tells the student by email and in-app notification
+// that a slot is held for them and until when.
+func (h *Handler) notifyWaitlistSlotOffered(ctx context.Context, e db.CoachingWaitlistEntry, hold db.CoachingWaitlistHold, sessionTypeName string) {
+	log := logger.From(ctx, h.logger)
+
+	groupName := ""
+	if group, err := h.q.GetGroup(ctx, e.GroupID); err != nil {
+		log.WarnContext(ctx, "waitlist_offer_fetch_group_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(e.ID)),
+			slog.Any("err", err),
+		)
+	} else {
+		groupName = group.Name
+	}
+	expert := h.resolveParticipant(ctx, e.ExpertID)
+
+	notifications.Record(ctx, h.q, h.logger, e.StudentID, notifications.TypeCoachingWaitlistSlotOffered,
+		notifications.CoachingWaitlistSlotOfferedPayload{
+			EntryID:         uuidToString(e.ID),
+			GroupID:         uuidToString(e.GroupID),
+			GroupName:       groupName,
+			ExpertName:      expert.name,
+			SessionName:     sessionTypeName,
+			ScheduledAt:     hold.ScheduledAt.Time.UTC().Format(time.RFC3339),
+			ExpiresAt:       hold.ExpiresAt.Time.UTC().Format(time.RFC3339),
+			DurationMinutes: int(hold.DurationMinutes),
+		})
+
+	student := h.resolveParticipant(ctx, e.StudentID)
+	if student.email == "" {
+		return
+	}
+	if !preferences.AllowsUserEmail(ctx, h.q, h.logger, e.StudentID, preferences.EmailCategoryCoachingBookingUpdates) {
+		log.InfoContext(ctx, "waitlist_offer_email_skipped_by_preferences",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(e.ID)),
+			slog.String("user_id", e.StudentID),
+		)
+		return
+	}
+
+	localization := h.resolveRecipientLocalization(ctx, e.StudentID)
+	loc := localization.localizer
+	subject := i18n.T(loc, "email.waitlist_slot_offered.subject")
+	message := email.Message{
+		Copy: email.Copy{
+			Preheader: i18n.T(loc, "email.waitlist_slot_offered.preheader"),
+			Title:     i18n.T(loc, "email.waitlist_slot_offered.title"),
+			Intro: i18n.T(loc, "email.waitlist_slot_offered.intro", map[string]any{
+				"SessionName": sessionTypeName,
+				"ExpertName":  expert.name,
+				"GroupName":   groupName,
+				"ScheduledAt": formatEmailDateTime(hold.ScheduledAt.Time, localization),
+				"Duration":    formatEmailDuration(hold.DurationMinutes, localization),
+				"ExpiresAt":   formatEmailDateTime(hold.ExpiresAt.Time, localization),
+			}),
+		},
+	}
+
+	if err := h.emailService.SendTemplate([]string{student.email}, subject, email.TemplateNotification, message); err != nil {
+		log.ErrorContext(ctx, "waitlist_offer_email_failed",
+			slog.String("component", "coaching"),
+			slog.String("entry_id", uuidToString(e.ID)),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	log.InfoContext(ctx, "waitlist_offer_email_sent",
+		slog.String("component", "coaching"),
+		slog.String("entry_id", uuidToString(e.ID)),
+		slog.String("hold_id", uuidToString(hold.ID)),
+	)
+}
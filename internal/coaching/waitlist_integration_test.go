@@ -0,0 +1,172 @@
+//go:build integration
+
+package coaching_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_WaitlistHolds(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	sessionType, err := q.CreateSessionType(ctx, db.CreateSessionTypeParams{
+		ExpertID: "expert-1", GroupID: group.ID, Name: "Private", DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateSessionType: %v", err)
+	}
+
+	join := func(studentID string, weekdays []int16) (db.CoachingWaitlistEntry, error) {
+		return q.CreateWaitlistEntry(ctx, db.CreateWaitlistEntryParams{
+			StudentID: studentID, ExpertID: "expert-1", GroupID: group.ID,
+			SessionTypeID: sessionType.ID, PreferredWeekdays: weekdays,
+		})
+	}
+	if _, err := join("student-9", []int16{7}); err == nil {
+		t.Fatal("weekday 7 was accepted")
+	}
+	first, err := join("student-1", []int16{1, 3})
+	if err != nil {
+		t.Fatalf("CreateWaitlistEntry: %v", err)
+	}
+	if _, err := join("student-1", nil); err == nil {
+		t.Fatal("second waiting entry for the same session type was accepted")
+	}
+	second, err := join("student-2", nil)
+	if err != nil {
+		t.Fatalf("CreateWaitlistEntry: %v", err)
+	}
+
+	at := func(h int) pgtype.Time {
+		return pgtype.Time{Microseconds: int64(h) * int64(time.Hour/time.Microsecond), Valid: true}
+	}
+	if err := q.InsertWaitlistWindows(ctx, db.InsertWaitlistWindowsParams{
+		EntryID: first.ID, StartTimes: []pgtype.Time{at(17), at(8)}, EndTimes: []pgtype.Time{at(20), at(10)},
+	}); err != nil {
+		t.Fatalf("InsertWaitlistWindows: %v", err)
+	}
+	windows, err := q.ListWaitlistWindows(ctx, []pgtype.UUID{first.ID, second.ID})
+	if err != nil {
+		t.Fatalf("ListWaitlistWindows: %v", err)
+	}
+	if len(windows) != 2 || windows[0].StartTime != at(8) {
+		t.Fatalf("ListWaitlistWindows = %+v; want both windows of the first entry in order", windows)
+	}
+
+	waiting, err := q.ListWaitingEntriesByExpert(ctx, db.ListWaitingEntriesByExpertParams{ExpertID: "expert-1", Limit: 10})
+	if err != nil || len(waiting) != 2 || waiting[0].ID != first.ID {
+		t.Fatalf("ListWaitingEntriesByExpert = %+v, %v; want both entries, oldest first", waiting, err)
+	}
+
+	start := time.Date(2030, 7, 1, 10, 0, 0, 0, time.UTC)
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	hold, err := q.CreateWaitlistHold(ctx, db.CreateWaitlistHoldParams{
+		EntryID: first.ID, ExpertID: "expert-1", ScheduledAt: ts(start), DurationMinutes: 60,
+		ExpiresAt: ts(time.Now().Add(time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("CreateWaitlistHold: %v", err)
+	}
+
+	// An entry with an active hold waits for its answer before getting another.
+	waiting, err = q.ListWaitingEntriesByExpert(ctx, db.ListWaitingEntriesByExpertParams{ExpertID: "expert-1", Limit: 10})
+	if err != nil || len(waiting) != 1 || waiting[0].ID != second.ID {
+		t.Fatalf("ListWaitingEntriesByExpert = %+v, %v; want only the second entry", waiting, err)
+	}
+
+	tests := []struct {
+		name       string
+		from, to   time.Time
+		exclude    pgtype.UUID
+		wantCount  int64
+		wantListed int
+	}{
+		{"overlapping", start.Add(30 * time.Minute), start.Add(90 * time.Minute), pgtype.UUID{}, 1, 1},
+		{"back to back", start.Add(time.Hour), start.Add(2 * time.Hour), pgtype.UUID{}, 0, 0},
+		{"excluding the hold", start, start.Add(time.Hour), hold.ID, 0, 1},
+	}
+	for _, tt := range tests {
+		n, err := q.CountConflictingWaitlistHolds(ctx, db.CountConflictingWaitlistHoldsParams{
+			ExpertID: "expert-1", EndsAt: ts(tt.to), StartsAt: ts(tt.from), ExcludeID: tt.exclude,
+		})
+		if err != nil || n != tt.wantCount {
+			t.Errorf("%s: CountConflictingWaitlistHolds = %d, %v; want %d", tt.name, n, err, tt.wantCount)
+		}
+		listed, err := q.ListWaitlistHoldsByExpertInRange(ctx, db.ListWaitlistHoldsByExpertInRangeParams{
+			ExpertID: "expert-1", ToAt: ts(tt.to), FromAt: ts(tt.from),
+		})
+		if err != nil || len(listed) != tt.wantListed {
+			t.Errorf("%s: ListWaitlistHoldsByExpertInRange = %d holds, %v; want %d", tt.name, len(listed), err, tt.wantListed)
+		}
+	}
+
+	booking, err := q.CreateBooking(ctx, db.CreateBookingParams{
+		ExpertID: "expert-1", StudentID: "student-1", GroupID: group.ID, SessionTypeID: sessionType.ID,
+		ScheduledAt: ts(start), DurationMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	confirmed, err := q.RespondToWaitlistHold(ctx, db.RespondToWaitlistHoldParams{
+		Status: db.CoachingWaitlistHoldStatusConfirmed, BookingID: booking.ID, ID: hold.ID,
+	})
+	if err != nil || confirmed.BookingID != booking.ID {
+		t.Fatalf("RespondToWaitlistHold = %+v, %v", confirmed, err)
+	}
+	if _, err := q.RespondToWaitlistHold(ctx, db.RespondToWaitlistHoldParams{
+		Status: db.CoachingWaitlistHoldStatusDeclined, ID: hold.ID,
+	}); err == nil {
+		t.Fatal("answered hold was answered again")
+	}
+	if err := q.MarkWaitlistEntryBooked(ctx, first.ID); err != nil {
+		t.Fatalf("MarkWaitlistEntryBooked: %v", err)
+	}
+	if _, err := q.GetWaitlistEntry(ctx, db.GetWaitlistEntryParams{ID: first.ID, StudentID: "student-1"}); err == nil {
+		t.Fatal("booked entry is still waiting")
+	}
+	// A failed checkout puts the entry back in line.
+	if err := q.ReopenWaitlistEntryForBooking(ctx, booking.ID); err != nil {
+		t.Fatalf("ReopenWaitlistEntryForBooking: %v", err)
+	}
+	if _, err := q.GetWaitlistEntry(ctx, db.GetWaitlistEntryParams{ID: first.ID, StudentID: "student-1"}); err != nil {
+		t.Fatalf("reopened entry is not waiting: %v", err)
+	}
+	if err := q.MarkWaitlistEntryBooked(ctx, first.ID); err != nil {
+		t.Fatalf("MarkWaitlistEntryBooked: %v", err)
+	}
+	// Once booked, the student may join again.
+	if _, err := join("student-1", nil); err != nil {
+		t.Fatalf("rejoin after booking: %v", err)
+	}
+
+	stale, err := q.CreateWaitlistHold(ctx, db.CreateWaitlistHoldParams{
+		EntryID: second.ID, ExpertID: "expert-1", ScheduledAt: ts(start.Add(2 * time.Hour)), DurationMinutes: 60,
+		ExpiresAt: ts(time.Now().Add(-time.Minute)),
+	})
+	if err != nil {
+		t.Fatalf("CreateWaitlistHold: %v", err)
+	}
+	expired, err := q.ExpireWaitlistHolds(ctx)
+	if err != nil || len(expired) != 1 || expired[0].ID != stale.ID || expired[0].Status != db.CoachingWaitlistHoldStatusExpired {
+		t.Fatalf("ExpireWaitlistHolds = %+v, %v; want the stale hold", expired, err)
+	}
+
+	if n, err := q.DeleteWaitlistEntry(ctx, db.DeleteWaitlistEntryParams{ID: second.ID, StudentID: "student-1"}); err != nil || n != 0 {
+		t.Fatalf("DeleteWaitlistEntry by another student = %d, %v; want 0", n, err)
+	}
+	if n, err := q.DeleteWaitlistEntry(ctx, db.DeleteWaitlistEntryParams{ID: second.ID, StudentID: "student-2"}); err != nil || n != 1 {
+		t.Fatalf("DeleteWaitlistEntry = %d, %v; want 1", n, err)
+	}
+}
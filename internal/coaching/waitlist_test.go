@@ -0,0 +1,188 @@
+package coaching
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	waitlistTestGroupID = "33333333-3333-3333-3333-333333333333"
+	waitlistTestEntryID = "44444444-4444-4444-4444-444444444444"
+)
+
+func waitlistRequest(method, body, userID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("groupID", waitlistTestGroupID)
+	rctx.URLParams.Add("entryID", waitlistTestEntryID)
+	req := httptest.NewRequest(method, "/groups/"+waitlistTestGroupID+"/coaching/waitlist", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(context.WithValue(ctx, auth.UserKey, &auth.UserContext{ID: userID}))
+}
+
+func TestParseWaitlistPreferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		weekdays []int16
+		windows  []waitlistWindow
+		wantDays []int16
+		wantErr  bool
+	}{
+		{"none", nil, nil, []int16{}, false},
+		{"sorted and deduplicated", []int16{5, 1, 5}, nil, []int16{1, 5}, false},
+		{"weekday out of range", []int16{7}, nil, nil, true},
+		{"window", nil, []waitlistWindow{{"17:00", "20:00"}}, []int16{}, false},
+		{"window ending before start", nil, []waitlistWindow{{"20:00", "17:00"}}, nil, true},
+		{"too many windows", nil, make([]waitlistWindow, MaxWaitlistWindows+1), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, starts, ends, errMsg := parseWaitlistPreferences(tt.weekdays, tt.windows)
+			if (errMsg != "") != tt.wantErr {
+				t.Fatalf("error = %q; want error %v", errMsg, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(days) != len(tt.wantDays) {
+				t.Fatalf("days = %v; want %v", days, tt.wantDays)
+			}
+			for i := range days {
+				if days[i] != tt.wantDays[i] {
+					t.Fatalf("days = %v; want %v", days, tt.wantDays)
+				}
+			}
+			if len(starts) != len(tt.windows) || len(ends) != len(tt.windows) {
+				t.Errorf("got %d starts and %d ends; want %d", len(starts), len(ends), len(tt.windows))
+			}
+		})
+	}
+}
+
+func TestMatchesWaitlistPreferences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	evening := func() []db.CoachingWaitlistWindow {
+		start, _ := parseTime("17:00")
+		end, _ := parseTime("20:00")
+		return []db.CoachingWaitlistWindow{{StartTime: start, EndTime: end}}
+	}
+	// Monday 2030-01-07 16:00 UTC is 17:00 in Berlin.
+	monday := time.Date(2030, 1, 7, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		duration int32
+		weekdays []int16
+		windows  []db.CoachingWaitlistWindow
+		want     bool
+	}{
+		{"no preferences", monday, 60, nil, nil, true},
+		{"preferred weekday", monday, 60, []int16{1, 3}, nil, true},
+		{"other weekday", monday, 60, []int16{2}, nil, false},
+		{"inside window in student timezone", monday, 60, nil, evening(), true},
+		{"ends at window end", monday.Add(2 * time.Hour), 60, nil, evening(), true},
+		{"runs past window end", monday.Add(150 * time.Minute), 60, nil, evening(), false},
+		{"before window", monday.Add(-time.Hour), 60, nil, evening(), false},
+		{"weekday and window", monday, 60, []int16{1}, evening(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesWaitlistPreferences(tt.start, tt.duration, tt.weekdays, tt.windows, berlin); got != tt.want {
+				t.Fatalf("matchesWaitlistPreferences = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinWaitlistValidation(t *testing.T) {
+	const sessionTypeID = "55555555-5555-5555-5555-555555555555"
+	tests := []struct {
+		name        string
+		body        string
+		sessionType *db.CoachingSessionType
+		wantStatus  int
+	}{
+		{"missing expert", `{"session_type_id":"` + sessionTypeID + `"}`, nil, http.StatusBadRequest},
+		{"own waitlist", `{"expert_id":"student-1","session_type_id":"` + sessionTypeID + `"}`, nil, http.StatusBadRequest},
+		{"invalid weekday", `{"expert_id":"expert-1","session_type_id":"` + sessionTypeID + `","preferred_weekdays":[9]}`, nil, http.StatusBadRequest},
+		{"session type of another expert", `{"expert_id":"expert-1","session_type_id":"` + sessionTypeID + `"}`,
+			&db.CoachingSessionType{ExpertID: "expert-2", IsActive: true}, http.StatusNotFound},
+		{"inactive session type", `{"expert_id":"expert-1","session_type_id":"` + sessionTypeID + `"}`,
+			&db.CoachingSessionType{ExpertID: "expert-1"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+			if tt.sessionType != nil {
+				q.EXPECT().GetSessionType(gomock.Any(), gomock.Any()).Return(*tt.sessionType, nil)
+			}
+
+			rec := httptest.NewRecorder()
+			h.JoinWaitlist(rec, waitlistRequest(http.MethodPost, tt.body, "student-1"))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d; want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestConfirmWaitlistHoldRejectsExpiredHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+
+	groupID, _ := parseUUID(waitlistTestGroupID)
+	entryID, _ := parseUUID(waitlistTestEntryID)
+	q.EXPECT().GetWaitlistEntry(gomock.Any(), db.GetWaitlistEntryParams{ID: entryID, StudentID: "student-1"}).
+		Return(db.CoachingWaitlistEntry{ID: entryID, StudentID: "student-1", ExpertID: "expert-1", GroupID: groupID}, nil)
+	q.EXPECT().GetOfferedWaitlistHold(gomock.Any(), entryID).Return(db.CoachingWaitlistHold{
+		EntryID:     entryID,
+		ExpertID:    "expert-1",
+		ScheduledAt: pgtype.Timestamptz{Time: time.Now().Add(48 * time.Hour), Valid: true},
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+		Status:      db.CoachingWaitlistHoldStatusOffered,
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ConfirmWaitlistHold(rec, waitlistRequest(http.MethodPut, "", "student-1"))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d; want 409 (body %s)", rec.Code, rec.Body.String())
+	}
+}
+
+func TestConfirmWaitlistHoldOfAnotherGroupIsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, nil, nil, nil, slog.Default(), HandlerConfig{})
+
+	entryID, _ := parseUUID(waitlistTestEntryID)
+	otherGroup, _ := parseUUID("66666666-6666-6666-6666-666666666666")
+	q.EXPECT().GetWaitlistEntry(gomock.Any(), gomock.Any()).
+		Return(db.CoachingWaitlistEntry{ID: entryID, StudentID: "student-1", GroupID: otherGroup}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ConfirmWaitlistHold(rec, waitlistRequest(http.MethodPut, "", "student-1"))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d; want 404", rec.Code)
+	}
+}
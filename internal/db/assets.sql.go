@@ -11,8 +11,31 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const attachVideoMuxAsset = `-- name: AttachVideoMuxAsset :execrows
+UPDATE videos
+SET mux_asset_id = $1::text, updated_at = NOW()
+WHERE mux_upload_id = $2::text
+  AND $2::text <> ''
+  AND (mux_asset_id IS NULL OR mux_asset_id = '')
+`
+
+type AttachVideoMuxAssetParams struct {
+	MuxAssetID  string `json:"mux_asset_id"`
+	MuxUploadID string `json:"mux_upload_id"`
+}
+
+// Records the asset Mux created for a direct upload. A later or repeated
+// delivery never overwrites an asset id that is already set.
+func (q *Queries) AttachVideoMuxAsset(ctx context.Context, arg AttachVideoMuxAssetParams) (int64, error) {
+	result, err := q.db.Exec(ctx, attachVideoMuxAsset, arg.MuxAssetID, arg.MuxUploadID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createAsset = `-- name: CreateAsset :one
-INSERT INTO assets (name, description, group_id, owner_id) VALUES ($1, $2, $3, $4) RETURNING id, name, description, status, created_at, updated_at, group_id, owner_id
+INSERT INTO assets (name, description, group_id, owner_id) VALUES ($1, $2, $3, $4) RETURNING id, name, description, status, created_at, updated_at, group_id, owner_id, deleted_at, deleted_by, last_review_at, review_threads, open_review_threads
 `
 
 type CreateAssetParams struct {
@@ -39,6 +62,11 @@ func (q *Queries) CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset
 		&i.UpdatedAt,
 		&i.GroupID,
 		&i.OwnerID,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.LastReviewAt,
+		&i.ReviewThreads,
+		&i.OpenReviewThreads,
 	)
 	return i, err
 }
@@ -46,7 +74,7 @@ func (q *Queries) CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset
 const createOrderedVideoFromMuxAsset = `-- name: CreateOrderedVideoFromMuxAsset :one
 INSERT INTO videos (asset_id, mux_upload_id, mux_asset_id, playback_id, status, sort_order)
 VALUES ($1, '', $2, $3, 'ready', $4)
-RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order
+RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order, mux_error, deleted_at, deleted_by
 `
 
 type CreateOrderedVideoFromMuxAssetParams struct {
@@ -75,12 +103,15 @@ func (q *Queries) CreateOrderedVideoFromMuxAsset(ctx context.Context, arg Create
 		&i.UpdatedAt,
 		&i.DurationSeconds,
 		&i.SortOrder,
+		&i.MuxError,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
 const createVideo = `-- name: CreateVideo :one
-INSERT INTO videos (asset_id, mux_upload_id, status) VALUES ($1, $2, $3) RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order
+INSERT INTO videos (asset_id, mux_upload_id, status) VALUES ($1, $2, $3) RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order, mux_error, deleted_at, deleted_by
 `
 
 type CreateVideoParams struct {
@@ -103,6 +134,9 @@ func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (Video
 		&i.UpdatedAt,
 		&i.DurationSeconds,
 		&i.SortOrder,
+		&i.MuxError,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
@@ -110,7 +144,7 @@ func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (Video
 const createVideoFromMuxAsset = `-- name: CreateVideoFromMuxAsset :one
 INSERT INTO videos (asset_id, mux_upload_id, mux_asset_id, playback_id, status)
 VALUES ($1, '', $2, $3, 'ready')
-RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order
+RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order, mux_error, deleted_at, deleted_by
 `
 
 type CreateVideoFromMuxAssetParams struct {
@@ -133,12 +167,15 @@ func (q *Queries) CreateVideoFromMuxAsset(ctx context.Context, arg CreateVideoFr
 		&i.UpdatedAt,
 		&i.DurationSeconds,
 		&i.SortOrder,
+		&i.MuxError,
+		&i.DeletedAt,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
 const getAsset = `-- name: GetAsset :one
-SELECT a.id, a.name, a.description, a.status, a.created_at, a.updated_at, a.owner_id, a.group_id, COALESCE(v.playback_id, '') as playback_id, COALESCE(v.mux_upload_id, '') as mux_upload_id, COALESCE(v.mux_asset_id, '') as mux_asset_id, g.name as group_name, g.avatar as group_avatar FROM assets a LEFT JOIN LATERAL (SELECT playback_id, mux_upload_id, mux_asset_id FROM videos WHERE asset_id = a.id ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC LIMIT 1) v ON true LEFT JOIN groups g ON g.id = a.group_id WHERE a.id = $1
+SELECT a.id, a.name, a.description, a.status, a.created_at, a.updated_at, a.owner_id, a.group_id, COALESCE(v.playback_id, '') as playback_id, COALESCE(v.mux_upload_id, '') as mux_upload_id, COALESCE(v.mux_asset_id, '') as mux_asset_id, g.name as group_name, g.avatar as group_avatar FROM assets a LEFT JOIN LATERAL (SELECT playback_id, mux_upload_id, mux_asset_id FROM videos WHERE asset_id = a.id AND deleted_at IS NULL ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC LIMIT 1) v ON true LEFT JOIN groups g ON g.id = a.group_id WHERE a.id = $1 AND a.deleted_at IS NULL
 `
 
 type GetAssetRow struct {
@@ -179,10 +216,10 @@ func (q *Queries) GetAsset(ctx context.Context, id pgtype.UUID) (GetAssetRow, er
 }
 
 const getAssetVideos = `-- name: GetAssetVideos :many
-SELECT v.id, v.mux_upload_id, v.mux_asset_id, v.playback_id, v.status, v.duration_seconds, v.created_at, COUNT(r.id) as review_count
+SELECT v.id, v.mux_upload_id, v.mux_asset_id, v.playback_id, v.status, v.duration_seconds, v.mux_error, v.created_at, COUNT(r.id) as review_count
 FROM videos v
 LEFT JOIN video_reviews r ON v.id = r.video_id
-WHERE v.asset_id = $1
+WHERE v.asset_id = $1 AND v.deleted_at IS NULL
 GROUP BY v.id
 ORDER BY v.sort_order ASC NULLS LAST, v.created_at ASC, v.id ASC
 `
@@ -194,6 +231,7 @@ type GetAssetVideosRow struct {
 	PlaybackID      pgtype.Text        `json:"playback_id"`
 	Status          VideoStatus        `json:"status"`
 	DurationSeconds pgtype.Float8      `json:"duration_seconds"`
+	MuxError        pgtype.Text        `json:"mux_error"`
 	CreatedAt       pgtype.Timestamptz `json:"created_at"`
 	ReviewCount     int64              `json:"review_count"`
 }
@@ -214,6 +252,7 @@ func (q *Queries) GetAssetVideos(ctx context.Context, assetID pgtype.UUID) ([]Ge
 			&i.PlaybackID,
 			&i.Status,
 			&i.DurationSeconds,
+			&i.MuxError,
 			&i.CreatedAt,
 			&i.ReviewCount,
 		); err != nil {
@@ -237,6 +276,8 @@ SELECT
     a.updated_at,
     a.owner_id,
     a.group_id,
+    a.review_threads,
+    a.open_review_threads,
     COALESCE(v.playback_id, '') as playback_id,
     COALESCE(v.mux_upload_id, '') as mux_upload_id,
     COALESCE(v.mux_asset_id, '') as mux_asset_id,
@@ -250,15 +291,19 @@ FROM assets a
 LEFT JOIN LATERAL (
     SELECT playback_id, mux_upload_id, mux_asset_id
     FROM videos
-    WHERE asset_id = a.id
+    WHERE asset_id = a.id AND deleted_at IS NULL
     ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
     LIMIT 1
 ) v ON true
 LEFT JOIN groups g ON g.id = a.group_id
 LEFT JOIN user_preferences up ON up.user_id = a.owner_id
 WHERE a.id = $1
+  AND a.deleted_at IS NULL
   AND (
-    ($2::boolean AND a.owner_id = $3)
+    ($2::boolean AND (
+      a.owner_id = $3
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = $3)
+    ))
     OR (
       NOT $2::boolean
       AND EXISTS (
@@ -286,6 +331,8 @@ type GetVisibleAssetRow struct {
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
 	OwnerID            string             `json:"owner_id"`
 	GroupID            pgtype.UUID        `json:"group_id"`
+	ReviewThreads      int32              `json:"review_threads"`
+	OpenReviewThreads  int32              `json:"open_review_threads"`
 	PlaybackID         string             `json:"playback_id"`
 	MuxUploadID        string             `json:"mux_upload_id"`
 	MuxAssetID         string             `json:"mux_asset_id"`
@@ -309,6 +356,8 @@ func (q *Queries) GetVisibleAsset(ctx context.Context, arg GetVisibleAssetParams
 		&i.UpdatedAt,
 		&i.OwnerID,
 		&i.GroupID,
+		&i.ReviewThreads,
+		&i.OpenReviewThreads,
 		&i.PlaybackID,
 		&i.MuxUploadID,
 		&i.MuxAssetID,
@@ -341,6 +390,15 @@ SELECT EXISTS (
                     OR recording_import.status IN ('pending', 'importing', 'processing', 'failed')
                 )
           )
+          OR EXISTS (
+              SELECT 1
+              FROM coaching_recording_stitches stitch
+              WHERE stitch.booking_id = booking.id
+                AND (
+                    stitch.status IN ('pending', 'importing', 'processing')
+                    OR (stitch.status = 'failed' AND stitch.attempts < 3)
+                )
+          )
       )
 )
 `
@@ -352,11 +410,120 @@ func (q *Queries) IsRecordingAssetStillOpen(ctx context.Context, recordingAssetI
 	return exists, err
 }
 
+const listAssetMuxIdentifiers = `-- name: ListAssetMuxIdentifiers :many
+SELECT id, mux_asset_id, mux_upload_id
+FROM videos
+WHERE asset_id = $1
+`
+
+type ListAssetMuxIdentifiersRow struct {
+	ID          pgtype.UUID `json:"id"`
+	MuxAssetID  pgtype.Text `json:"mux_asset_id"`
+	MuxUploadID pgtype.Text `json:"mux_upload_id"`
+}
+
+// Every video of the asset, deleted or not, so no Mux asset is left behind.
+func (q *Queries) ListAssetMuxIdentifiers(ctx context.Context, assetID pgtype.UUID) ([]ListAssetMuxIdentifiersRow, error) {
+	rows, err := q.db.Query(ctx, listAssetMuxIdentifiers, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAssetMuxIdentifiersRow
+	for rows.Next() {
+		var i ListAssetMuxIdentifiersRow
+		if err := rows.Scan(&i.ID, &i.MuxAssetID, &i.MuxUploadID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAssetsDueForPurge = `-- name: ListAssetsDueForPurge :many
+SELECT id
+FROM assets
+WHERE deleted_at < $1
+ORDER BY deleted_at
+LIMIT $2
+`
+
+type ListAssetsDueForPurgeParams struct {
+	DeletedBefore pgtype.Timestamptz `json:"deleted_before"`
+	BatchLimit    int32              `json:"batch_limit"`
+}
+
+func (q *Queries) ListAssetsDueForPurge(ctx context.Context, arg ListAssetsDueForPurgeParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listAssetsDueForPurge, arg.DeletedBefore, arg.BatchLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosDueForPurge = `-- name: ListVideosDueForPurge :many
+SELECT v.id, v.mux_asset_id, v.mux_upload_id
+FROM videos v
+JOIN assets a ON a.id = v.asset_id
+WHERE v.deleted_at < $1
+  AND a.deleted_at IS NULL
+ORDER BY v.deleted_at
+LIMIT $2
+`
+
+type ListVideosDueForPurgeParams struct {
+	DeletedBefore pgtype.Timestamptz `json:"deleted_before"`
+	BatchLimit    int32              `json:"batch_limit"`
+}
+
+type ListVideosDueForPurgeRow struct {
+	ID          pgtype.UUID `json:"id"`
+	MuxAssetID  pgtype.Text `json:"mux_asset_id"`
+	MuxUploadID pgtype.Text `json:"mux_upload_id"`
+}
+
+// Videos deleted on their own. Videos of a deleted asset go with the asset.
+func (q *Queries) ListVideosDueForPurge(ctx context.Context, arg ListVideosDueForPurgeParams) ([]ListVideosDueForPurgeRow, error) {
+	rows, err := q.db.Query(ctx, listVideosDueForPurge, arg.DeletedBefore, arg.BatchLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVideosDueForPurgeRow
+	for rows.Next() {
+		var i ListVideosDueForPurgeRow
+		if err := rows.Scan(&i.ID, &i.MuxAssetID, &i.MuxUploadID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideosMissingDuration = `-- name: ListVideosMissingDuration :many
 SELECT id, mux_asset_id, mux_upload_id
 FROM videos
 WHERE status = 'ready'
   AND duration_seconds IS NULL
+  AND deleted_at IS NULL
   AND (mux_asset_id <> '' OR mux_upload_id <> '')
 ORDER BY created_at
 LIMIT $1
@@ -399,15 +566,18 @@ SELECT
     a.created_at,
     a.updated_at,
     a.owner_id,
+    COALESCE(a.last_review_at, a.created_at)::timestamptz as last_activity_at,
     COALESCE(v.playback_id, '') as playback_id,
     COALESCE(v.mux_upload_id, '') as mux_upload_id,
     COALESCE(v.mux_asset_id, '') as mux_asset_id,
-    COALESCE(rv.review_count, 0)::bigint as review_count
+    COALESCE(rv.review_count, 0)::bigint as review_count,
+    a.review_threads,
+    a.open_review_threads
 FROM assets a
 LEFT JOIN LATERAL (
     SELECT playback_id, mux_upload_id, mux_asset_id
     FROM videos
-    WHERE asset_id = a.id
+    WHERE asset_id = a.id AND deleted_at IS NULL
     ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
     LIMIT 1
 ) v ON true
@@ -416,10 +586,14 @@ LEFT JOIN LATERAL (
     FROM videos review_videos
     LEFT JOIN video_reviews r ON r.video_id = review_videos.id
     WHERE review_videos.asset_id = a.id
+      AND review_videos.deleted_at IS NULL
 ) rv ON true
-WHERE a.status != 'waiting_upload'
+WHERE a.deleted_at IS NULL
   AND (
-    ($1::boolean AND a.owner_id = $2)
+    ($1::boolean AND (
+      a.owner_id = $2
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = $2)
+    ))
     OR (
       NOT $1::boolean
       AND EXISTS (
@@ -430,30 +604,80 @@ WHERE a.status != 'waiting_upload'
       )
     )
   )
-ORDER BY a.created_at DESC
+  AND (
+    ($3::text = '' AND a.status != 'waiting_upload')
+    OR a.status::text = $3
+  )
+  AND ($4::uuid IS NULL OR a.group_id = $4)
+  AND ($5::text = '' OR a.owner_id = $5)
+  AND (
+    $6::text = ''
+    OR ($6 = 'to_review' AND a.status = 'pending' AND a.last_review_at IS NULL)
+    OR ($6 = 'in_review' AND (
+      (a.status = 'pending' AND a.last_review_at IS NOT NULL)
+      OR (a.status = 'completed' AND a.open_review_threads > 0)
+    ))
+    OR ($6 = 'reviewed' AND a.status = 'completed' AND a.open_review_threads = 0)
+  )
+  AND (
+    $7::text = ''
+    OR to_tsvector('simple', a.name || ' ' || a.description) @@ to_tsquery('simple', $7)
+  )
+  AND (
+    NOT $8::boolean
+    OR (a.created_at, a.id) < ($9::timestamptz, $10::uuid)
+  )
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT $11::int
 `
 
 type ListVisibleAssetsParams struct {
-	IsStudent bool   `json:"is_student"`
-	UserID    string `json:"user_id"`
+	IsStudent   bool               `json:"is_student"`
+	UserID      string             `json:"user_id"`
+	Status      string             `json:"status"`
+	GroupID     pgtype.UUID        `json:"group_id"`
+	OwnerID     string             `json:"owner_id"`
+	ReviewState string             `json:"review_state"`
+	SearchQuery string             `json:"search_query"`
+	HasCursor   bool               `json:"has_cursor"`
+	CursorAt    pgtype.Timestamptz `json:"cursor_at"`
+	CursorID    pgtype.UUID        `json:"cursor_id"`
+	PageLimit   pgtype.Int4        `json:"page_limit"`
 }
 
 type ListVisibleAssetsRow struct {
-	ID          pgtype.UUID        `json:"id"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	Status      AssetStatus        `json:"status"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	OwnerID     string             `json:"owner_id"`
-	PlaybackID  string             `json:"playback_id"`
-	MuxUploadID string             `json:"mux_upload_id"`
-	MuxAssetID  string             `json:"mux_asset_id"`
-	ReviewCount int64              `json:"review_count"`
+	ID                pgtype.UUID        `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Status            AssetStatus        `json:"status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	OwnerID           string             `json:"owner_id"`
+	LastActivityAt    pgtype.Timestamptz `json:"last_activity_at"`
+	PlaybackID        string             `json:"playback_id"`
+	MuxUploadID       string             `json:"mux_upload_id"`
+	MuxAssetID        string             `json:"mux_asset_id"`
+	ReviewCount       int64              `json:"review_count"`
+	ReviewThreads     int32              `json:"review_threads"`
+	OpenReviewThreads int32              `json:"open_review_threads"`
 }
 
+// Newest upload first. Without a status filter, assets still uploading are
+// hidden. A NULL page_limit returns every match.
 func (q *Queries) ListVisibleAssets(ctx context.Context, arg ListVisibleAssetsParams) ([]ListVisibleAssetsRow, error) {
-	rows, err := q.db.Query(ctx, listVisibleAssets, arg.IsStudent, arg.UserID)
+	rows, err := q.db.Query(ctx, listVisibleAssets,
+		arg.IsStudent,
+		arg.UserID,
+		arg.Status,
+		arg.GroupID,
+		arg.OwnerID,
+		arg.ReviewState,
+		arg.SearchQuery,
+		arg.HasCursor,
+		arg.CursorAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -469,10 +693,13 @@ func (q *Queries) ListVisibleAssets(ctx context.Context, arg ListVisibleAssetsPa
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.OwnerID,
+			&i.LastActivityAt,
 			&i.PlaybackID,
 			&i.MuxUploadID,
 			&i.MuxAssetID,
 			&i.ReviewCount,
+			&i.ReviewThreads,
+			&i.OpenReviewThreads,
 		); err != nil {
 			return nil, err
 		}
@@ -484,6 +711,455 @@ func (q *Queries) ListVisibleAssets(ctx context.Context, arg ListVisibleAssetsPa
 	return items, nil
 }
 
+const listVisibleAssetsByReviewActivity = `-- name: ListVisibleAssetsByReviewActivity :many
+SELECT
+    a.id,
+    a.name,
+    a.description,
+    a.status,
+    a.created_at,
+    a.updated_at,
+    a.owner_id,
+    COALESCE(a.last_review_at, a.created_at)::timestamptz as last_activity_at,
+    COALESCE(v.playback_id, '') as playback_id,
+    COALESCE(v.mux_upload_id, '') as mux_upload_id,
+    COALESCE(v.mux_asset_id, '') as mux_asset_id,
+    COALESCE(rv.review_count, 0)::bigint as review_count,
+    a.review_threads,
+    a.open_review_threads
+FROM assets a
+LEFT JOIN LATERAL (
+    SELECT playback_id, mux_upload_id, mux_asset_id
+    FROM videos
+    WHERE asset_id = a.id AND deleted_at IS NULL
+    ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
+    LIMIT 1
+) v ON true
+LEFT JOIN LATERAL (
+    SELECT COUNT(r.id) as review_count
+    FROM videos review_videos
+    LEFT JOIN video_reviews r ON r.video_id = review_videos.id
+    WHERE review_videos.asset_id = a.id
+      AND review_videos.deleted_at IS NULL
+) rv ON true
+WHERE a.deleted_at IS NULL
+  AND (
+    ($1::boolean AND (
+      a.owner_id = $2
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = $2)
+    ))
+    OR (
+      NOT $1::boolean
+      AND EXISTS (
+        SELECT 1
+        FROM user_groups ug
+        WHERE ug.user_id = $2
+          AND ug.group_id = a.group_id
+      )
+    )
+  )
+  AND (
+    ($3::text = '' AND a.status != 'waiting_upload')
+    OR a.status::text = $3
+  )
+  AND ($4::uuid IS NULL OR a.group_id = $4)
+  AND ($5::text = '' OR a.owner_id = $5)
+  AND (
+    $6::text = ''
+    OR ($6 = 'to_review' AND a.status = 'pending' AND a.last_review_at IS NULL)
+    OR ($6 = 'in_review' AND (
+      (a.status = 'pending' AND a.last_review_at IS NOT NULL)
+      OR (a.status = 'completed' AND a.open_review_threads > 0)
+    ))
+    OR ($6 = 'reviewed' AND a.status = 'completed' AND a.open_review_threads = 0)
+  )
+  AND (
+    $7::text = ''
+    OR to_tsvector('simple', a.name || ' ' || a.description) @@ to_tsquery('simple', $7)
+  )
+  AND (
+    NOT $8::boolean
+    OR (COALESCE(a.last_review_at, a.created_at), a.id) < ($9::timestamptz, $10::uuid)
+  )
+ORDER BY COALESCE(a.last_review_at, a.created_at) DESC, a.id DESC
+LIMIT $11::int
+`
+
+type ListVisibleAssetsByReviewActivityParams struct {
+	IsStudent   bool               `json:"is_student"`
+	UserID      string             `json:"user_id"`
+	Status      string             `json:"status"`
+	GroupID     pgtype.UUID        `json:"group_id"`
+	OwnerID     string             `json:"owner_id"`
+	ReviewState string             `json:"review_state"`
+	SearchQuery string             `json:"search_query"`
+	HasCursor   bool               `json:"has_cursor"`
+	CursorAt    pgtype.Timestamptz `json:"cursor_at"`
+	CursorID    pgtype.UUID        `json:"cursor_id"`
+	PageLimit   pgtype.Int4        `json:"page_limit"`
+}
+
+type ListVisibleAssetsByReviewActivityRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Status            AssetStatus        `json:"status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	OwnerID           string             `json:"owner_id"`
+	LastActivityAt    pgtype.Timestamptz `json:"last_activity_at"`
+	PlaybackID        string             `json:"playback_id"`
+	MuxUploadID       string             `json:"mux_upload_id"`
+	MuxAssetID        string             `json:"mux_asset_id"`
+	ReviewCount       int64              `json:"review_count"`
+	ReviewThreads     int32              `json:"review_threads"`
+	OpenReviewThreads int32              `json:"open_review_threads"`
+}
+
+// Same filters as ListVisibleAssets, most recent review activity first.
+// Assets nobody has reviewed yet rank by their upload time.
+func (q *Queries) ListVisibleAssetsByReviewActivity(ctx context.Context, arg ListVisibleAssetsByReviewActivityParams) ([]ListVisibleAssetsByReviewActivityRow, error) {
+	rows, err := q.db.Query(ctx, listVisibleAssetsByReviewActivity,
+		arg.IsStudent,
+		arg.UserID,
+		arg.Status,
+		arg.GroupID,
+		arg.OwnerID,
+		arg.ReviewState,
+		arg.SearchQuery,
+		arg.HasCursor,
+		arg.CursorAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVisibleAssetsByReviewActivityRow
+	for rows.Next() {
+		var i ListVisibleAssetsByReviewActivityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.LastActivityAt,
+			&i.PlaybackID,
+			&i.MuxUploadID,
+			&i.MuxAssetID,
+			&i.ReviewCount,
+			&i.ReviewThreads,
+			&i.OpenReviewThreads,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVisibleAssetsByUpdated = `-- name: ListVisibleAssetsByUpdated :many
+SELECT
+    a.id,
+    a.name,
+    a.description,
+    a.status,
+    a.created_at,
+    a.updated_at,
+    a.owner_id,
+    COALESCE(a.last_review_at, a.created_at)::timestamptz as last_activity_at,
+    COALESCE(v.playback_id, '') as playback_id,
+    COALESCE(v.mux_upload_id, '') as mux_upload_id,
+    COALESCE(v.mux_asset_id, '') as mux_asset_id,
+    COALESCE(rv.review_count, 0)::bigint as review_count,
+    a.review_threads,
+    a.open_review_threads
+FROM assets a
+LEFT JOIN LATERAL (
+    SELECT playback_id, mux_upload_id, mux_asset_id
+    FROM videos
+    WHERE asset_id = a.id AND deleted_at IS NULL
+    ORDER BY sort_order ASC NULLS LAST, created_at ASC, id ASC
+    LIMIT 1
+) v ON true
+LEFT JOIN LATERAL (
+    SELECT COUNT(r.id) as review_count
+    FROM videos review_videos
+    LEFT JOIN video_reviews r ON r.video_id = review_videos.id
+    WHERE review_videos.asset_id = a.id
+      AND review_videos.deleted_at IS NULL
+) rv ON true
+WHERE a.deleted_at IS NULL
+  AND (
+    ($1::boolean AND (
+      a.owner_id = $2
+      OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = $2)
+    ))
+    OR (
+      NOT $1::boolean
+      AND EXISTS (
+        SELECT 1
+        FROM user_groups ug
+        WHERE ug.user_id = $2
+          AND ug.group_id = a.group_id
+      )
+    )
+  )
+  AND (
+    ($3::text = '' AND a.status != 'waiting_upload')
+    OR a.status::text = $3
+  )
+  AND ($4::uuid IS NULL OR a.group_id = $4)
+  AND ($5::text = '' OR a.owner_id = $5)
+  AND (
+    $6::text = ''
+    OR ($6 = 'to_review' AND a.status = 'pending' AND a.last_review_at IS NULL)
+    OR ($6 = 'in_review' AND (
+      (a.status = 'pending' AND a.last_review_at IS NOT NULL)
+      OR (a.status = 'completed' AND a.open_review_threads > 0)
+    ))
+    OR ($6 = 'reviewed' AND a.status = 'completed' AND a.open_review_threads = 0)
+  )
+  AND (
+    $7::text = ''
+    OR to_tsvector('simple', a.name || ' ' || a.description) @@ to_tsquery('simple', $7)
+  )
+  AND (
+    NOT $8::boolean
+    OR (a.updated_at, a.id) < ($9::timestamptz, $10::uuid)
+  )
+ORDER BY a.updated_at DESC, a.id DESC
+LIMIT $11::int
+`
+
+type ListVisibleAssetsByUpdatedParams struct {
+	IsStudent   bool               `json:"is_student"`
+	UserID      string             `json:"user_id"`
+	Status      string             `json:"status"`
+	GroupID     pgtype.UUID        `json:"group_id"`
+	OwnerID     string             `json:"owner_id"`
+	ReviewState string             `json:"review_state"`
+	SearchQuery string             `json:"search_query"`
+	HasCursor   bool               `json:"has_cursor"`
+	CursorAt    pgtype.Timestamptz `json:"cursor_at"`
+	CursorID    pgtype.UUID        `json:"cursor_id"`
+	PageLimit   pgtype.Int4        `json:"page_limit"`
+}
+
+type ListVisibleAssetsByUpdatedRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Status            AssetStatus        `json:"status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	OwnerID           string             `json:"owner_id"`
+	LastActivityAt    pgtype.Timestamptz `json:"last_activity_at"`
+	PlaybackID        string             `json:"playback_id"`
+	MuxUploadID       string             `json:"mux_upload_id"`
+	MuxAssetID        string             `json:"mux_asset_id"`
+	ReviewCount       int64              `json:"review_count"`
+	ReviewThreads     int32              `json:"review_threads"`
+	OpenReviewThreads int32              `json:"open_review_threads"`
+}
+
+// Same filters as ListVisibleAssets, most recently changed first.
+func (q *Queries) ListVisibleAssetsByUpdated(ctx context.Context, arg ListVisibleAssetsByUpdatedParams) ([]ListVisibleAssetsByUpdatedRow, error) {
+	rows, err := q.db.Query(ctx, listVisibleAssetsByUpdated,
+		arg.IsStudent,
+		arg.UserID,
+		arg.Status,
+		arg.GroupID,
+		arg.OwnerID,
+		arg.ReviewState,
+		arg.SearchQuery,
+		arg.HasCursor,
+		arg.CursorAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListVisibleAssetsByUpdatedRow
+	for rows.Next() {
+		var i ListVisibleAssetsByUpdatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.LastActivityAt,
+			&i.PlaybackID,
+			&i.MuxUploadID,
+			&i.MuxAssetID,
+			&i.ReviewCount,
+			&i.ReviewThreads,
+			&i.OpenReviewThreads,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markVideoFailedFromMux = `-- name: MarkVideoFailedFromMux :many
+UPDATE videos
+SET mux_asset_id = $1::text,
+    status = 'failed',
+    mux_error = $2::text,
+    updated_at = NOW()
+WHERE (mux_asset_id = $1::text
+   OR ($3::text <> '' AND mux_upload_id = $3::text))
+  AND status <> 'ready'
+  AND deleted_at IS NULL
+RETURNING asset_id
+`
+
+type MarkVideoFailedFromMuxParams struct {
+	MuxAssetID  string `json:"mux_asset_id"`
+	MuxError    string `json:"mux_error"`
+	MuxUploadID string `json:"mux_upload_id"`
+}
+
+// A ready video stays ready: a stale errored delivery must not hide a
+// playable video, and deleted videos are left alone.
+func (q *Queries) MarkVideoFailedFromMux(ctx context.Context, arg MarkVideoFailedFromMuxParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, markVideoFailedFromMux, arg.MuxAssetID, arg.MuxError, arg.MuxUploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var asset_id pgtype.UUID
+		if err := rows.Scan(&asset_id); err != nil {
+			return nil, err
+		}
+		items = append(items, asset_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markVideoReadyFromMux = `-- name: MarkVideoReadyFromMux :many
+UPDATE videos
+SET mux_asset_id = $1::text,
+    playback_id = COALESCE(NULLIF($2::text, ''), playback_id),
+    duration_seconds = COALESCE(duration_seconds, $3),
+    status = 'ready',
+    mux_error = NULL,
+    updated_at = NOW()
+WHERE (mux_asset_id = $1::text
+   OR ($4::text <> '' AND mux_upload_id = $4::text))
+  AND deleted_at IS NULL
+RETURNING asset_id
+`
+
+type MarkVideoReadyFromMuxParams struct {
+	MuxAssetID      string        `json:"mux_asset_id"`
+	PlaybackID      string        `json:"playback_id"`
+	DurationSeconds pgtype.Float8 `json:"duration_seconds"`
+	MuxUploadID     string        `json:"mux_upload_id"`
+}
+
+// Matches by asset id, or by upload id when video.asset.ready overtakes
+// video.upload.asset_created. An existing duration or playback id is kept.
+// Deleted videos are left alone so a late delivery cannot revive them.
+func (q *Queries) MarkVideoReadyFromMux(ctx context.Context, arg MarkVideoReadyFromMuxParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, markVideoReadyFromMux,
+		arg.MuxAssetID,
+		arg.PlaybackID,
+		arg.DurationSeconds,
+		arg.MuxUploadID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var asset_id pgtype.UUID
+		if err := rows.Scan(&asset_id); err != nil {
+			return nil, err
+		}
+		items = append(items, asset_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const promoteUploadedAsset = `-- name: PromoteUploadedAsset :execrows
+UPDATE assets a
+SET status = 'pending', updated_at = NOW()
+WHERE a.id = $1
+  AND a.status = 'waiting_upload'
+  AND a.deleted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM videos v
+      WHERE v.asset_id = a.id AND v.deleted_at IS NULL AND v.status = 'waiting_upload'
+  )
+  AND EXISTS (
+      SELECT 1 FROM videos v
+      WHERE v.asset_id = a.id AND v.deleted_at IS NULL AND v.status = 'ready'
+  )
+`
+
+// Moves an asset out of waiting_upload once no video is still waiting on Mux
+// and at least one is playable, so uploads finish even if the client never
+// calls complete.
+func (q *Queries) PromoteUploadedAsset(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteUploadedAsset, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeAsset = `-- name: PurgeAsset :execrows
+DELETE FROM assets WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+// Reviews cascade with the videos.
+func (q *Queries) PurgeAsset(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeAsset, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeVideo = `-- name: PurgeVideo :execrows
+DELETE FROM videos WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) PurgeVideo(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeVideo, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const setVideoDurationByID = `-- name: SetVideoDurationByID :exec
 UPDATE videos
 SET duration_seconds = $2, updated_at = NOW()
@@ -516,6 +1192,75 @@ func (q *Queries) SetVideoDurationByUploadID(ctx context.Context, arg SetVideoDu
 	return err
 }
 
+const softDeleteAsset = `-- name: SoftDeleteAsset :one
+UPDATE assets
+SET deleted_at = NOW(), deleted_by = $1, updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, name, description, status, created_at, updated_at, group_id, owner_id, deleted_at, deleted_by, last_review_at, review_threads, open_review_threads
+`
+
+type SoftDeleteAssetParams struct {
+	DeletedBy pgtype.Text `json:"deleted_by"`
+	ID        pgtype.UUID `json:"id"`
+}
+
+// Hides the asset and all its videos at once. The purge job removes it for
+// good once the grace window has passed.
+func (q *Queries) SoftDeleteAsset(ctx context.Context, arg SoftDeleteAssetParams) (Asset, error) {
+	row := q.db.QueryRow(ctx, softDeleteAsset, arg.DeletedBy, arg.ID)
+	var i Asset
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GroupID,
+		&i.OwnerID,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.LastReviewAt,
+		&i.ReviewThreads,
+		&i.OpenReviewThreads,
+	)
+	return i, err
+}
+
+const softDeleteVideo = `-- name: SoftDeleteVideo :one
+UPDATE videos
+SET deleted_at = NOW(), deleted_by = $1, updated_at = NOW()
+WHERE id = $2 AND asset_id = $3 AND deleted_at IS NULL
+RETURNING id, asset_id, mux_upload_id, mux_asset_id, playback_id, status, created_at, updated_at, duration_seconds, sort_order, mux_error, deleted_at, deleted_by
+`
+
+type SoftDeleteVideoParams struct {
+	DeletedBy pgtype.Text `json:"deleted_by"`
+	ID        pgtype.UUID `json:"id"`
+	AssetID   pgtype.UUID `json:"asset_id"`
+}
+
+func (q *Queries) SoftDeleteVideo(ctx context.Context, arg SoftDeleteVideoParams) (Video, error) {
+	row := q.db.QueryRow(ctx, softDeleteVideo, arg.DeletedBy, arg.ID, arg.AssetID)
+	var i Video
+	err := row.Scan(
+		&i.ID,
+		&i.AssetID,
+		&i.MuxUploadID,
+		&i.MuxAssetID,
+		&i.PlaybackID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DurationSeconds,
+		&i.SortOrder,
+		&i.MuxError,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
 const updateAssetStatus = `-- name: UpdateAssetStatus :exec
 UPDATE assets SET status = $2, updated_at = NOW() WHERE id = $1
 `
@@ -13,31 +13,43 @@ import (
 
 const createAuditEvent = `-- name: CreateAuditEvent :exec
 INSERT INTO audit_events (
+    id, occurred_at,
     actor_id, actor_type, actor_label, action,
     resource_type, resource_id, group_id,
-    old_values, new_values, metadata
+    old_values, new_values, metadata,
+    chain_shard, chain_seq, prev_hash, hash
 ) VALUES (
-    $1, $2, $3, $4,
-    $5, $6, $7,
-    $8, $9, $10
+    $1, $2,
+    $3, $4, $5, $6,
+    $7, $8, $9,
+    $10, $11, $12,
+    $13, $14, $15, $16
 )
 `
 
 type CreateAuditEventParams struct {
-	ActorID      pgtype.Text `json:"actor_id"`
-	ActorType    string      `json:"actor_type"`
-	ActorLabel   pgtype.Text `json:"actor_label"`
-	Action       string      `json:"action"`
-	ResourceType string      `json:"resource_type"`
-	ResourceID   pgtype.Text `json:"resource_id"`
-	GroupID      pgtype.Text `json:"group_id"`
-	OldValues    []byte      `json:"old_values"`
-	NewValues    []byte      `json:"new_values"`
-	Metadata     []byte      `json:"metadata"`
+	ID           pgtype.UUID        `json:"id"`
+	OccurredAt   pgtype.Timestamptz `json:"occurred_at"`
+	ActorID      pgtype.Text        `json:"actor_id"`
+	ActorType    string             `json:"actor_type"`
+	ActorLabel   pgtype.Text        `json:"actor_label"`
+	Action       string             `json:"action"`
+	ResourceType string             `json:"resource_type"`
+	ResourceID   pgtype.Text        `json:"resource_id"`
+	GroupID      pgtype.Text        `json:"group_id"`
+	OldValues    []byte             `json:"old_values"`
+	NewValues    []byte             `json:"new_values"`
+	Metadata     []byte             `json:"metadata"`
+	ChainShard   int32              `json:"chain_shard"`
+	ChainSeq     pgtype.Int8        `json:"chain_seq"`
+	PrevHash     []byte             `json:"prev_hash"`
+	Hash         []byte             `json:"hash"`
 }
 
 func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error {
 	_, err := q.db.Exec(ctx, createAuditEvent,
+		arg.ID,
+		arg.OccurredAt,
 		arg.ActorID,
 		arg.ActorType,
 		arg.ActorLabel,
@@ -48,6 +60,405 @@ func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventPara
 		arg.OldValues,
 		arg.NewValues,
 		arg.Metadata,
+		arg.ChainShard,
+		arg.ChainSeq,
+		arg.PrevHash,
+		arg.Hash,
 	)
 	return err
 }
+
+const exportAuditEvents = `-- name: ExportAuditEvents :many
+SELECT id, occurred_at, actor_id, actor_type, actor_label, action, resource_type, resource_id, group_id, old_values, new_values, metadata, chain_seq, prev_hash, hash, chain_shard
+FROM audit_events
+WHERE occurred_at >= $1
+  AND occurred_at < $2
+  AND ($3::TEXT = '' OR group_id = $3)
+  AND (
+    $4::TEXT = ''
+    OR actor_id = $4
+    OR (resource_type = 'profile' AND resource_id = $4)
+    OR $4 IN (
+      new_values->>'user_id', old_values->>'user_id',
+      new_values->>'student_id', old_values->>'student_id',
+      new_values->>'expert_id', old_values->>'expert_id',
+      new_values->>'author_id', old_values->>'author_id'
+    )
+  )
+  AND (
+    NOT $5::BOOLEAN
+    OR (occurred_at, id) > ($6::TIMESTAMPTZ, $7::UUID)
+  )
+ORDER BY occurred_at, id
+LIMIT $8
+`
+
+type ExportAuditEventsParams struct {
+	OccurredFrom     pgtype.Timestamptz `json:"occurred_from"`
+	OccurredTo       pgtype.Timestamptz `json:"occurred_to"`
+	GroupID          string             `json:"group_id"`
+	SubjectID        string             `json:"subject_id"`
+	HasCursor        bool               `json:"has_cursor"`
+	CursorOccurredAt pgtype.Timestamptz `json:"cursor_occurred_at"`
+	CursorID         pgtype.UUID        `json:"cursor_id"`
+	PageLimit        int32              `json:"page_limit"`
+}
+
+// Chronological keyset page for compliance exports. subject_id matches events
+// the user performed as well as events about them: their profile, their
+// memberships, bookings they are party to and reviews they authored.
+func (q *Queries) ExportAuditEvents(ctx context.Context, arg ExportAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, exportAuditEvents,
+		arg.OccurredFrom,
+		arg.OccurredTo,
+		arg.GroupID,
+		arg.SubjectID,
+		arg.HasCursor,
+		arg.CursorOccurredAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.OccurredAt,
+			&i.ActorID,
+			&i.ActorType,
+			&i.ActorLabel,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.GroupID,
+			&i.OldValues,
+			&i.NewValues,
+			&i.Metadata,
+			&i.ChainSeq,
+			&i.PrevHash,
+			&i.Hash,
+			&i.ChainShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT id, occurred_at, actor_id, actor_type, actor_label, action, resource_type, resource_id, group_id, old_values, new_values, metadata, chain_seq, prev_hash, hash, chain_shard
+FROM audit_events
+WHERE occurred_at >= $1
+  AND occurred_at < $2
+  AND ($3::TEXT = '' OR actor_id = $3)
+  AND ($4::TEXT = '' OR group_id = $4)
+  AND ($5::TEXT = '' OR resource_type = $5)
+  AND ($6::TEXT = '' OR resource_id = $6)
+  AND ($7::TEXT = '' OR action = $7)
+  AND (
+    NOT $8::BOOLEAN
+    OR (occurred_at, id) < ($9::TIMESTAMPTZ, $10::UUID)
+  )
+ORDER BY occurred_at DESC, id DESC
+LIMIT $11
+`
+
+type ListAuditEventsParams struct {
+	OccurredFrom     pgtype.Timestamptz `json:"occurred_from"`
+	OccurredTo       pgtype.Timestamptz `json:"occurred_to"`
+	ActorID          string             `json:"actor_id"`
+	GroupID          string             `json:"group_id"`
+	ResourceType     string             `json:"resource_type"`
+	ResourceID       string             `json:"resource_id"`
+	Action           string             `json:"action"`
+	HasCursor        bool               `json:"has_cursor"`
+	CursorOccurredAt pgtype.Timestamptz `json:"cursor_occurred_at"`
+	CursorID         pgtype.UUID        `json:"cursor_id"`
+	PageLimit        int32              `json:"page_limit"`
+}
+
+// Keyset-paginated newest first. The occurred_at window is always bounded so
+// the planner prunes monthly partitions outside it.
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditEvents,
+		arg.OccurredFrom,
+		arg.OccurredTo,
+		arg.ActorID,
+		arg.GroupID,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Action,
+		arg.HasCursor,
+		arg.CursorOccurredAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.OccurredAt,
+			&i.ActorID,
+			&i.ActorType,
+			&i.ActorLabel,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.GroupID,
+			&i.OldValues,
+			&i.NewValues,
+			&i.Metadata,
+			&i.ChainSeq,
+			&i.PrevHash,
+			&i.Hash,
+			&i.ChainShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ensureAuditChainHead = `-- name: EnsureAuditChainHead :exec
+INSERT INTO audit_chain_heads (partition_name, shard)
+VALUES ('audit_events_' || to_char(NOW() AT TIME ZONE 'UTC', 'YYYY_MM'), $1)
+ON CONFLICT DO NOTHING
+`
+
+// Creates the shard's head for the partition NOW() falls into on its first
+// event; a concurrent first event waits on the insert instead of failing.
+func (q *Queries) EnsureAuditChainHead(ctx context.Context, shard int32) error {
+	_, err := q.db.Exec(ctx, ensureAuditChainHead, shard)
+	return err
+}
+
+const lockAuditChainHead = `-- name: LockAuditChainHead :one
+SELECT partition_name, genesis_hash, head_seq, head_hash, NOW()::TIMESTAMPTZ AS now
+FROM audit_chain_heads
+WHERE partition_name = 'audit_events_' || to_char(NOW() AT TIME ZONE 'UTC', 'YYYY_MM')
+  AND shard = $1
+FOR UPDATE
+`
+
+type LockAuditChainHeadRow struct {
+	PartitionName string             `json:"partition_name"`
+	GenesisHash   []byte             `json:"genesis_hash"`
+	HeadSeq       int64              `json:"head_seq"`
+	HeadHash      []byte             `json:"head_hash"`
+	Now           pgtype.Timestamptz `json:"now"`
+}
+
+// Locks one shard's chain head of the partition NOW() falls into. NOW() is the
+// transaction start, the same value occurred_at defaults to, so the event and
+// the head always agree on the partition.
+func (q *Queries) LockAuditChainHead(ctx context.Context, shard int32) (LockAuditChainHeadRow, error) {
+	row := q.db.QueryRow(ctx, lockAuditChainHead, shard)
+	var i LockAuditChainHeadRow
+	err := row.Scan(
+		&i.PartitionName,
+		&i.GenesisHash,
+		&i.HeadSeq,
+		&i.HeadHash,
+		&i.Now,
+	)
+	return i, err
+}
+
+const sealAuditChainHead = `-- name: SealAuditChainHead :one
+UPDATE audit_chain_heads
+SET sealed_seq = head_seq,
+    sealed_hash = head_hash,
+    updated_at = NOW()
+WHERE partition_name = $1
+  AND shard = $2
+  AND sealed_seq IS NULL
+RETURNING sealed_hash
+`
+
+type SealAuditChainHeadParams struct {
+	PartitionName string `json:"partition_name"`
+	Shard         int32  `json:"shard"`
+}
+
+// Freezes the head a following partition chains to. Late events may still
+// extend the sealed partition; the seal pins the link, not the tail.
+func (q *Queries) SealAuditChainHead(ctx context.Context, arg SealAuditChainHeadParams) ([]byte, error) {
+	row := q.db.QueryRow(ctx, sealAuditChainHead, arg.PartitionName, arg.Shard)
+	var sealed_hash []byte
+	err := row.Scan(&sealed_hash)
+	return sealed_hash, err
+}
+
+const advanceAuditChainHead = `-- name: AdvanceAuditChainHead :exec
+UPDATE audit_chain_heads
+SET head_seq = $1,
+    head_hash = $2,
+    genesis_hash = COALESCE(genesis_hash, $3),
+    chained_since = COALESCE(chained_since, $4),
+    updated_at = NOW()
+WHERE partition_name = $5
+  AND shard = $6
+`
+
+type AdvanceAuditChainHeadParams struct {
+	HeadSeq       int64              `json:"head_seq"`
+	HeadHash      []byte             `json:"head_hash"`
+	GenesisHash   []byte             `json:"genesis_hash"`
+	ChainedSince  pgtype.Timestamptz `json:"chained_since"`
+	PartitionName string             `json:"partition_name"`
+	Shard         int32              `json:"shard"`
+}
+
+func (q *Queries) AdvanceAuditChainHead(ctx context.Context, arg AdvanceAuditChainHeadParams) error {
+	_, err := q.db.Exec(ctx, advanceAuditChainHead,
+		arg.HeadSeq,
+		arg.HeadHash,
+		arg.GenesisHash,
+		arg.ChainedSince,
+		arg.PartitionName,
+		arg.Shard,
+	)
+	return err
+}
+
+const listAuditChainHeads = `-- name: ListAuditChainHeads :many
+SELECT partition_name, genesis_hash, chained_since, head_seq, head_hash, sealed_seq, sealed_hash, dropped_at, updated_at, shard
+FROM audit_chain_heads
+ORDER BY partition_name, shard
+`
+
+func (q *Queries) ListAuditChainHeads(ctx context.Context) ([]AuditChainHead, error) {
+	rows, err := q.db.Query(ctx, listAuditChainHeads)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditChainHead
+	for rows.Next() {
+		var i AuditChainHead
+		if err := rows.Scan(
+			&i.PartitionName,
+			&i.GenesisHash,
+			&i.ChainedSince,
+			&i.HeadSeq,
+			&i.HeadHash,
+			&i.SealedSeq,
+			&i.SealedHash,
+			&i.DroppedAt,
+			&i.UpdatedAt,
+			&i.Shard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditChainEvents = `-- name: ListAuditChainEvents :many
+SELECT id, occurred_at, actor_id, actor_type, actor_label, action, resource_type, resource_id, group_id, old_values, new_values, metadata, chain_seq, prev_hash, hash, chain_shard
+FROM audit_events
+WHERE occurred_at >= $1
+  AND occurred_at < $2
+  AND chain_shard = $3
+  AND chain_seq > $4
+ORDER BY chain_seq
+LIMIT $5
+`
+
+type ListAuditChainEventsParams struct {
+	OccurredFrom pgtype.Timestamptz `json:"occurred_from"`
+	OccurredTo   pgtype.Timestamptz `json:"occurred_to"`
+	ChainShard   int32              `json:"chain_shard"`
+	AfterSeq     pgtype.Int8        `json:"after_seq"`
+	PageLimit    int32              `json:"page_limit"`
+}
+
+// One shard's chain of a partition in chain_seq order, keyset-paginated on
+// chain_seq.
+func (q *Queries) ListAuditChainEvents(ctx context.Context, arg ListAuditChainEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditChainEvents,
+		arg.OccurredFrom,
+		arg.OccurredTo,
+		arg.ChainShard,
+		arg.AfterSeq,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.OccurredAt,
+			&i.ActorID,
+			&i.ActorType,
+			&i.ActorLabel,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.GroupID,
+			&i.OldValues,
+			&i.NewValues,
+			&i.Metadata,
+			&i.ChainSeq,
+			&i.PrevHash,
+			&i.Hash,
+			&i.ChainShard,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countUnchainedAuditEvents = `-- name: CountUnchainedAuditEvents :one
+SELECT COUNT(*)
+FROM audit_events
+WHERE occurred_at >= $1
+  AND occurred_at < $2
+  AND occurred_at >= $3
+  AND chain_seq IS NULL
+`
+
+type CountUnchainedAuditEventsParams struct {
+	OccurredFrom pgtype.Timestamptz `json:"occurred_from"`
+	OccurredTo   pgtype.Timestamptz `json:"occurred_to"`
+	ChainedSince pgtype.Timestamptz `json:"chained_since"`
+}
+
+// Rows without a chain position written after the partition's chain began can
+// only have been inserted around the recorder.
+func (q *Queries) CountUnchainedAuditEvents(ctx context.Context, arg CountUnchainedAuditEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnchainedAuditEvents, arg.OccurredFrom, arg.OccurredTo, arg.ChainedSince)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
@@ -15,7 +15,7 @@ const assignBookingRecordingAsset = `-- name: AssignBookingRecordingAsset :one
 UPDATE coaching_bookings
 SET recording_asset_id = COALESCE(recording_asset_id, $2), updated_at = NOW()
 WHERE id = $1
-RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
 `
 
 type AssignBookingRecordingAssetParams struct {
@@ -42,18 +42,54 @@ func (q *Queries) AssignBookingRecordingAsset(ctx context.Context, arg AssignBoo
 		&i.UpdatedAt,
 		&i.RecordingAssetID,
 		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
 	)
 	return i, err
 }
 
+const assignClassRecordingAsset = `-- name: AssignClassRecordingAsset :exec
+WITH assigned AS (
+    UPDATE coaching_bookings
+    SET recording_asset_id = COALESCE(recording_asset_id, $1), updated_at = NOW()
+    WHERE class_id = $2 AND is_cancelled = false
+    RETURNING student_id
+)
+INSERT INTO asset_viewers (asset_id, user_id)
+SELECT $1, student_id FROM assigned
+ON CONFLICT DO NOTHING
+`
+
+type AssignClassRecordingAssetParams struct {
+	AssetID pgtype.UUID `json:"asset_id"`
+	ClassID pgtype.UUID `json:"class_id"`
+}
+
+// Gives every active attendee of the class the recording asset on their own
+// booking and lets them watch it.
+func (q *Queries) AssignClassRecordingAsset(ctx context.Context, arg AssignClassRecordingAssetParams) error {
+	_, err := q.db.Exec(ctx, assignClassRecordingAsset, arg.AssetID, arg.ClassID)
+	return err
+}
+
 const cancelBooking = `-- name: CancelBooking :one
 UPDATE coaching_bookings
 SET is_cancelled = true,
     cancellation_reason = $2,
     cancelled_by = $3,
     updated_at = NOW()
-WHERE id = $1 AND (expert_id = $4 OR student_id = $4)
-RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number
+WHERE id = $1 AND (expert_id = $4 OR student_id = $4) AND is_cancelled = false
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
 `
 
 type CancelBookingParams struct {
@@ -63,6 +99,8 @@ type CancelBookingParams struct {
 	ExpertID           string      `json:"expert_id"`
 }
 
+// No row when the booking is already cancelled, so a repeated cancellation
+// cannot return its credit twice.
 func (q *Queries) CancelBooking(ctx context.Context, arg CancelBookingParams) (CoachingBooking, error) {
 	row := q.db.QueryRow(ctx, cancelBooking,
 		arg.ID,
@@ -87,10 +125,74 @@ func (q *Queries) CancelBooking(ctx context.Context, arg CancelBookingParams) (C
 		&i.UpdatedAt,
 		&i.RecordingAssetID,
 		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
 	)
 	return i, err
 }
 
+const claimDueExternalCalendars = `-- name: ClaimDueExternalCalendars :many
+WITH due AS (
+    SELECT id FROM coaching_external_calendars
+    WHERE source_url IS NOT NULL
+      AND (last_attempted_at IS NULL OR last_attempted_at <= NOW() - make_interval(secs => $1::int))
+    ORDER BY last_attempted_at NULLS FIRST
+    FOR UPDATE SKIP LOCKED
+    LIMIT $2
+)
+UPDATE coaching_external_calendars cal
+SET last_attempted_at = NOW()
+FROM due
+WHERE cal.id = due.id
+RETURNING cal.id, cal.expert_id, cal.label, cal.source_url, cal.last_attempted_at, cal.last_synced_at, cal.last_error, cal.created_at
+`
+
+type ClaimDueExternalCalendarsParams struct {
+	RefreshSeconds int32 `json:"refresh_seconds"`
+	LimitCount     int32 `json:"limit_count"`
+}
+
+// Subscribed calendars not attempted within the refresh interval. Claiming
+// stamps last_attempted_at so overlapping runs skip them.
+func (q *Queries) ClaimDueExternalCalendars(ctx context.Context, arg ClaimDueExternalCalendarsParams) ([]CoachingExternalCalendar, error) {
+	rows, err := q.db.Query(ctx, claimDueExternalCalendars, arg.RefreshSeconds, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingExternalCalendar
+	for rows.Next() {
+		var i CoachingExternalCalendar
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.Label,
+			&i.SourceUrl,
+			&i.LastAttemptedAt,
+			&i.LastSyncedAt,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const claimNextRecordingPart = `-- name: ClaimNextRecordingPart :one
 
 WITH claimed_booking AS (
@@ -261,6 +363,92 @@ func (q *Queries) ClaimPendingRecordingPartImports(ctx context.Context, limit in
 	return items, nil
 }
 
+const claimPendingRecordingStitches = `-- name: ClaimPendingRecordingStitches :many
+WITH candidates AS (
+    SELECT stitch.booking_id
+    FROM coaching_recording_stitches stitch
+    WHERE stitch.status IN ('pending', 'importing', 'processing', 'failed')
+      AND (stitch.attempts < 3 OR stitch.status = 'importing')
+      AND (
+          stitch.last_attempt_at IS NULL
+          OR (stitch.status = 'importing' AND stitch.last_attempt_at <= NOW() - interval '30 minutes')
+          OR (stitch.status <> 'importing' AND stitch.last_attempt_at <= NOW() - interval '1 minute')
+      )
+    ORDER BY stitch.created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT $1
+), claimed AS (
+    UPDATE coaching_recording_stitches stitch
+    SET status = (CASE WHEN stitch.mux_asset_id IS NULL THEN 'importing' ELSE 'processing' END)::coaching_recording_import_status,
+        attempts = stitch.attempts + (CASE WHEN stitch.mux_asset_id IS NULL THEN 1 ELSE 0 END),
+        last_attempt_at = NOW(), updated_at = NOW()
+    FROM candidates
+    WHERE stitch.booking_id = candidates.booking_id
+    RETURNING stitch.booking_id, stitch.status, stitch.part_count, stitch.object_name, stitch.mux_asset_id, stitch.mux_playback_id, stitch.video_id, stitch.attempts, stitch.last_attempt_at, stitch.stitched_at, stitch.error, stitch.created_at, stitch.updated_at
+)
+SELECT claimed.booking_id, claimed.status, claimed.part_count, claimed.object_name, claimed.mux_asset_id, claimed.mux_playback_id, claimed.video_id, claimed.attempts, claimed.last_attempt_at, claimed.stitched_at, claimed.error, claimed.created_at, claimed.updated_at, booking.recording_asset_id, booking.expert_id
+FROM claimed
+JOIN coaching_bookings booking ON booking.id = claimed.booking_id
+ORDER BY claimed.created_at
+`
+
+type ClaimPendingRecordingStitchesRow struct {
+	BookingID        pgtype.UUID                   `json:"booking_id"`
+	Status           CoachingRecordingImportStatus `json:"status"`
+	PartCount        int32                         `json:"part_count"`
+	ObjectName       pgtype.Text                   `json:"object_name"`
+	MuxAssetID       pgtype.Text                   `json:"mux_asset_id"`
+	MuxPlaybackID    pgtype.Text                   `json:"mux_playback_id"`
+	VideoID          pgtype.UUID                   `json:"video_id"`
+	Attempts         int32                         `json:"attempts"`
+	LastAttemptAt    pgtype.Timestamptz            `json:"last_attempt_at"`
+	StitchedAt       pgtype.Timestamptz            `json:"stitched_at"`
+	Error            pgtype.Text                   `json:"error"`
+	CreatedAt        pgtype.Timestamptz            `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz            `json:"updated_at"`
+	RecordingAssetID pgtype.UUID                   `json:"recording_asset_id"`
+	ExpertID         string                        `json:"expert_id"`
+}
+
+// Claims stitches to work on. Polling Mux does not use up an attempt; a stitch
+// that stopped halfway is claimed again once it has gone quiet for 30 minutes,
+// even past its last attempt, so it can be given up on.
+func (q *Queries) ClaimPendingRecordingStitches(ctx context.Context, limit int32) ([]ClaimPendingRecordingStitchesRow, error) {
+	rows, err := q.db.Query(ctx, claimPendingRecordingStitches, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimPendingRecordingStitchesRow
+	for rows.Next() {
+		var i ClaimPendingRecordingStitchesRow
+		if err := rows.Scan(
+			&i.BookingID,
+			&i.Status,
+			&i.PartCount,
+			&i.ObjectName,
+			&i.MuxAssetID,
+			&i.MuxPlaybackID,
+			&i.VideoID,
+			&i.Attempts,
+			&i.LastAttemptAt,
+			&i.StitchedAt,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.ExpertID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const clearRecordingPartEmptySince = `-- name: ClearRecordingPartEmptySince :exec
 UPDATE coaching_booking_recordings
 SET empty_since_at = NULL, updated_at = NOW()
@@ -272,22 +460,192 @@ func (q *Queries) ClearRecordingPartEmptySince(ctx context.Context, bookingID pg
 	return err
 }
 
+const consumeCredit = `-- name: ConsumeCredit :one
+UPDATE coaching_credit_grants
+SET remaining = remaining - 1, updated_at = NOW()
+WHERE id = (
+    SELECT g.id FROM coaching_credit_grants g
+    WHERE g.student_id = $1
+      AND g.expert_id = $2
+      AND g.group_id = $3
+      AND g.remaining > 0
+      AND (g.expires_at IS NULL OR g.expires_at > NOW())
+    ORDER BY g.expires_at NULLS LAST, g.created_at
+    LIMIT 1
+    FOR UPDATE
+  )
+  AND remaining > 0
+RETURNING id, student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by, created_at, updated_at
+`
+
+type ConsumeCreditParams struct {
+	StudentID string      `json:"student_id"`
+	ExpertID  string      `json:"expert_id"`
+	GroupID   pgtype.UUID `json:"group_id"`
+}
+
+// Takes one credit from the student's grant that expires first. No row when
+// the student has no usable credit with this expert.
+func (q *Queries) ConsumeCredit(ctx context.Context, arg ConsumeCreditParams) (CoachingCreditGrant, error) {
+	row := q.db.QueryRow(ctx, consumeCredit, arg.StudentID, arg.ExpertID, arg.GroupID)
+	var i CoachingCreditGrant
+	err := row.Scan(
+		&i.ID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Quantity,
+		&i.Remaining,
+		&i.ExpiresAt,
+		&i.Note,
+		&i.GrantedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countBookingHomework = `-- name: CountBookingHomework :one
+SELECT COUNT(*) FROM coaching_homework WHERE booking_id = $1
+`
+
+func (q *Queries) CountBookingHomework(ctx context.Context, bookingID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBookingHomework, bookingID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookingMessages = `-- name: CountBookingMessages :one
+SELECT COUNT(*) FROM coaching_booking_messages WHERE booking_id = $1
+`
+
+func (q *Queries) CountBookingMessages(ctx context.Context, bookingID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBookingMessages, bookingID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookingsStartingInRange = `-- name: CountBookingsStartingInRange :one
+SELECT COUNT(DISTINCT COALESCE(class_id, id)) FROM coaching_bookings
+WHERE expert_id = $1
+  AND is_cancelled = false
+  AND scheduled_at >= $2
+  AND scheduled_at < $3
+  AND id IS DISTINCT FROM $4
+`
+
+type CountBookingsStartingInRangeParams struct {
+	ExpertID  string             `json:"expert_id"`
+	FromAt    pgtype.Timestamptz `json:"from_at"`
+	ToAt      pgtype.Timestamptz `json:"to_at"`
+	ExcludeID pgtype.UUID        `json:"exclude_id"`
+}
+
+// Active sessions of the expert starting in [from_at, to_at), for daily caps.
+// The bookings of a class count as one session.
+func (q *Queries) CountBookingsStartingInRange(ctx context.Context, arg CountBookingsStartingInRangeParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countBookingsStartingInRange,
+		arg.ExpertID,
+		arg.FromAt,
+		arg.ToAt,
+		arg.ExcludeID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookingsWithinBuffers = `-- name: CountBookingsWithinBuffers :one
+SELECT COUNT(*) FROM coaching_bookings
+WHERE expert_id = $1
+  AND is_cancelled = false
+  AND scheduled_at - make_interval(mins => GREATEST(buffer_before_minutes, $2::int)) < $3
+  AND scheduled_at + make_interval(mins => duration_minutes + GREATEST(buffer_after_minutes, $4::int)) > $5
+  AND id IS DISTINCT FROM $6
+`
+
+type CountBookingsWithinBuffersParams struct {
+	ExpertID            string             `json:"expert_id"`
+	BufferAfterMinutes  int32              `json:"buffer_after_minutes"`
+	EndsAt              pgtype.Timestamptz `json:"ends_at"`
+	BufferBeforeMinutes int32              `json:"buffer_before_minutes"`
+	StartsAt            pgtype.Timestamptz `json:"starts_at"`
+	ExcludeID           pgtype.UUID        `json:"exclude_id"`
+}
+
+// Active bookings closer to the interval than the larger of the two buffers
+// between them. @exclude_id excludes the booking being rescheduled.
+func (q *Queries) CountBookingsWithinBuffers(ctx context.Context, arg CountBookingsWithinBuffersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countBookingsWithinBuffers,
+		arg.ExpertID,
+		arg.BufferAfterMinutes,
+		arg.EndsAt,
+		arg.BufferBeforeMinutes,
+		arg.StartsAt,
+		arg.ExcludeID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countConflictingBookings = `-- name: CountConflictingBookings :one
 SELECT COUNT(*) FROM coaching_bookings
 WHERE expert_id = $1
   AND is_cancelled = false
   AND scheduled_at < $3
   AND scheduled_at + (duration_minutes * interval '1 minute') > $2
+  AND id IS DISTINCT FROM $4
 `
 
 type CountConflictingBookingsParams struct {
 	ExpertID      string             `json:"expert_id"`
 	ScheduledAt   pgtype.Timestamptz `json:"scheduled_at"`
 	ScheduledAt_2 pgtype.Timestamptz `json:"scheduled_at_2"`
+	ID            pgtype.UUID        `json:"id"`
 }
 
+// $4 excludes the booking being rescheduled; pass NULL when creating a booking.
 func (q *Queries) CountConflictingBookings(ctx context.Context, arg CountConflictingBookingsParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countConflictingBookings, arg.ExpertID, arg.ScheduledAt, arg.ScheduledAt_2)
+	row := q.db.QueryRow(ctx, countConflictingBookings,
+		arg.ExpertID,
+		arg.ScheduledAt,
+		arg.ScheduledAt_2,
+		arg.ID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countConflictingWaitlistHolds = `-- name: CountConflictingWaitlistHolds :one
+SELECT COUNT(*) FROM coaching_waitlist_holds
+WHERE expert_id = $1
+  AND status = 'offered'
+  AND expires_at > NOW()
+  AND scheduled_at < $2
+  AND scheduled_at + (duration_minutes * interval '1 minute') > $3
+  AND id IS DISTINCT FROM $4
+`
+
+type CountConflictingWaitlistHoldsParams struct {
+	ExpertID  string             `json:"expert_id"`
+	EndsAt    pgtype.Timestamptz `json:"ends_at"`
+	StartsAt  pgtype.Timestamptz `json:"starts_at"`
+	ExcludeID pgtype.UUID        `json:"exclude_id"`
+}
+
+// Unexpired holds of the expert overlapping [starts_at, ends_at).
+// @exclude_id excludes the hold being confirmed.
+func (q *Queries) CountConflictingWaitlistHolds(ctx context.Context, arg CountConflictingWaitlistHoldsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countConflictingWaitlistHolds,
+		arg.ExpertID,
+		arg.EndsAt,
+		arg.StartsAt,
+		arg.ExcludeID,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -311,6 +669,40 @@ func (q *Queries) CountFreshBookingParticipants(ctx context.Context, arg CountFr
 	return count, err
 }
 
+const createAbsence = `-- name: CreateAbsence :one
+
+INSERT INTO coaching_absences (expert_id, starts_on, ends_on, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING id, expert_id, starts_on, ends_on, reason, created_at
+`
+
+type CreateAbsenceParams struct {
+	ExpertID string      `json:"expert_id"`
+	StartsOn pgtype.Date `json:"starts_on"`
+	EndsOn   pgtype.Date `json:"ends_on"`
+	Reason   pgtype.Text `json:"reason"`
+}
+
+// === Absences ===
+func (q *Queries) CreateAbsence(ctx context.Context, arg CreateAbsenceParams) (CoachingAbsence, error) {
+	row := q.db.QueryRow(ctx, createAbsence,
+		arg.ExpertID,
+		arg.StartsOn,
+		arg.EndsOn,
+		arg.Reason,
+	)
+	var i CoachingAbsence
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StartsOn,
+		&i.EndsOn,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createAvailability = `-- name: CreateAvailability :one
 
 INSERT INTO coaching_availability (expert_id, group_id, day_of_week, start_time, end_time)
@@ -350,6 +742,43 @@ func (q *Queries) CreateAvailability(ctx context.Context, arg CreateAvailability
 	return i, err
 }
 
+const createAvailabilityOverride = `-- name: CreateAvailabilityOverride :one
+
+INSERT INTO coaching_availability_overrides (expert_id, group_id, override_date, start_time, end_time)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, expert_id, group_id, override_date, start_time, end_time, created_at
+`
+
+type CreateAvailabilityOverrideParams struct {
+	ExpertID     string      `json:"expert_id"`
+	GroupID      pgtype.UUID `json:"group_id"`
+	OverrideDate pgtype.Date `json:"override_date"`
+	StartTime    pgtype.Time `json:"start_time"`
+	EndTime      pgtype.Time `json:"end_time"`
+}
+
+// === Availability Overrides ===
+func (q *Queries) CreateAvailabilityOverride(ctx context.Context, arg CreateAvailabilityOverrideParams) (CoachingAvailabilityOverride, error) {
+	row := q.db.QueryRow(ctx, createAvailabilityOverride,
+		arg.ExpertID,
+		arg.GroupID,
+		arg.OverrideDate,
+		arg.StartTime,
+		arg.EndTime,
+	)
+	var i CoachingAvailabilityOverride
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.OverrideDate,
+		&i.StartTime,
+		&i.EndTime,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createBlockedSlot = `-- name: CreateBlockedSlot :one
 
 INSERT INTO coaching_blocked_slots (expert_id, blocked_date, start_time, end_time, reason)
@@ -388,19 +817,33 @@ func (q *Queries) CreateBlockedSlot(ctx context.Context, arg CreateBlockedSlotPa
 }
 
 const createBooking = `-- name: CreateBooking :one
-INSERT INTO coaching_bookings (expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, notes)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number
+INSERT INTO coaching_bookings (
+    expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, notes, series_id,
+    buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at,
+    credit_grant_id, class_id, class_seat
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
 `
 
 type CreateBookingParams struct {
-	ExpertID        string             `json:"expert_id"`
-	StudentID       string             `json:"student_id"`
-	GroupID         pgtype.UUID        `json:"group_id"`
-	SessionTypeID   pgtype.UUID        `json:"session_type_id"`
-	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes int32              `json:"duration_minutes"`
-	Notes           pgtype.Text        `json:"notes"`
+	ExpertID            string                    `json:"expert_id"`
+	StudentID           string                    `json:"student_id"`
+	GroupID             pgtype.UUID               `json:"group_id"`
+	SessionTypeID       pgtype.UUID               `json:"session_type_id"`
+	ScheduledAt         pgtype.Timestamptz        `json:"scheduled_at"`
+	DurationMinutes     int32                     `json:"duration_minutes"`
+	Notes               pgtype.Text               `json:"notes"`
+	SeriesID            pgtype.UUID               `json:"series_id"`
+	BufferBeforeMinutes int32                     `json:"buffer_before_minutes"`
+	BufferAfterMinutes  int32                     `json:"buffer_after_minutes"`
+	PriceCents          int32                     `json:"price_cents"`
+	Currency            pgtype.Text               `json:"currency"`
+	PaymentStatus       NullCoachingPaymentStatus `json:"payment_status"`
+	PaymentExpiresAt    pgtype.Timestamptz        `json:"payment_expires_at"`
+	CreditGrantID       pgtype.UUID               `json:"credit_grant_id"`
+	ClassID             pgtype.UUID               `json:"class_id"`
+	ClassSeat           pgtype.Int4               `json:"class_seat"`
 }
 
 func (q *Queries) CreateBooking(ctx context.Context, arg CreateBookingParams) (CoachingBooking, error) {
@@ -412,6 +855,16 @@ func (q *Queries) CreateBooking(ctx context.Context, arg CreateBookingParams) (C
 		arg.ScheduledAt,
 		arg.DurationMinutes,
 		arg.Notes,
+		arg.SeriesID,
+		arg.BufferBeforeMinutes,
+		arg.BufferAfterMinutes,
+		arg.PriceCents,
+		arg.Currency,
+		arg.PaymentStatus,
+		arg.PaymentExpiresAt,
+		arg.CreditGrantID,
+		arg.ClassID,
+		arg.ClassSeat,
 	)
 	var i CoachingBooking
 	err := row.Scan(
@@ -430,6 +883,53 @@ func (q *Queries) CreateBooking(ctx context.Context, arg CreateBookingParams) (C
 		&i.UpdatedAt,
 		&i.RecordingAssetID,
 		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const createBookingMessage = `-- name: CreateBookingMessage :one
+INSERT INTO coaching_booking_messages (booking_id, author_id, author_role, body, links)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, booking_id, author_id, author_role, body, links, created_at
+`
+
+type CreateBookingMessageParams struct {
+	BookingID  pgtype.UUID `json:"booking_id"`
+	AuthorID   string      `json:"author_id"`
+	AuthorRole string      `json:"author_role"`
+	Body       string      `json:"body"`
+	Links      []string    `json:"links"`
+}
+
+func (q *Queries) CreateBookingMessage(ctx context.Context, arg CreateBookingMessageParams) (CoachingBookingMessage, error) {
+	row := q.db.QueryRow(ctx, createBookingMessage,
+		arg.BookingID,
+		arg.AuthorID,
+		arg.AuthorRole,
+		arg.Body,
+		arg.Links,
+	)
+	var i CoachingBookingMessage
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.AuthorID,
+		&i.AuthorRole,
+		&i.Body,
+		&i.Links,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -451,327 +951,2809 @@ func (q *Queries) CreateBookingReminder(ctx context.Context, arg CreateBookingRe
 	return err
 }
 
-const createSessionType = `-- name: CreateSessionType :one
-
-INSERT INTO coaching_session_types (expert_id, group_id, name, description, duration_minutes)
+const createBookingReschedule = `-- name: CreateBookingReschedule :one
+INSERT INTO coaching_booking_reschedules (booking_id, proposed_by, scheduled_at, previous_scheduled_at, note)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at
+RETURNING id, booking_id, proposed_by, scheduled_at, previous_scheduled_at, note, status, responded_by, responded_at, created_at
 `
 
-type CreateSessionTypeParams struct {
-	ExpertID        string      `json:"expert_id"`
-	GroupID         pgtype.UUID `json:"group_id"`
-	Name            string      `json:"name"`
-	Description     string      `json:"description"`
-	DurationMinutes int32       `json:"duration_minutes"`
+type CreateBookingRescheduleParams struct {
+	BookingID           pgtype.UUID        `json:"booking_id"`
+	ProposedBy          string             `json:"proposed_by"`
+	ScheduledAt         pgtype.Timestamptz `json:"scheduled_at"`
+	PreviousScheduledAt pgtype.Timestamptz `json:"previous_scheduled_at"`
+	Note                pgtype.Text        `json:"note"`
 }
 
-// === Session Types ===
-func (q *Queries) CreateSessionType(ctx context.Context, arg CreateSessionTypeParams) (CoachingSessionType, error) {
-	row := q.db.QueryRow(ctx, createSessionType,
-		arg.ExpertID,
-		arg.GroupID,
-		arg.Name,
-		arg.Description,
-		arg.DurationMinutes,
+func (q *Queries) CreateBookingReschedule(ctx context.Context, arg CreateBookingRescheduleParams) (CoachingBookingReschedule, error) {
+	row := q.db.QueryRow(ctx, createBookingReschedule,
+		arg.BookingID,
+		arg.ProposedBy,
+		arg.ScheduledAt,
+		arg.PreviousScheduledAt,
+		arg.Note,
 	)
-	var i CoachingSessionType
+	var i CoachingBookingReschedule
 	err := row.Scan(
 		&i.ID,
-		&i.ExpertID,
-		&i.GroupID,
-		&i.Name,
-		&i.Description,
-		&i.DurationMinutes,
-		&i.IsActive,
+		&i.BookingID,
+		&i.ProposedBy,
+		&i.ScheduledAt,
+		&i.PreviousScheduledAt,
+		&i.Note,
+		&i.Status,
+		&i.RespondedBy,
+		&i.RespondedAt,
 		&i.CreatedAt,
-		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const deactivateSessionType = `-- name: DeactivateSessionType :execrows
-UPDATE coaching_session_types
-SET is_active = false, updated_at = NOW()
-WHERE id = $1 AND expert_id = $2
+const createBookingSeries = `-- name: CreateBookingSeries :one
+INSERT INTO coaching_booking_series (
+    expert_id, student_id, group_id, session_type_id, starts_at, timezone,
+    interval_weeks, occurrence_count, until_date, duration_minutes, notes
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, expert_id, student_id, group_id, session_type_id, starts_at, timezone, interval_weeks, occurrence_count, until_date, duration_minutes, notes, created_at
 `
 
-type DeactivateSessionTypeParams struct {
-	ID       pgtype.UUID `json:"id"`
-	ExpertID string      `json:"expert_id"`
+type CreateBookingSeriesParams struct {
+	ExpertID        string             `json:"expert_id"`
+	StudentID       string             `json:"student_id"`
+	GroupID         pgtype.UUID        `json:"group_id"`
+	SessionTypeID   pgtype.UUID        `json:"session_type_id"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	Timezone        string             `json:"timezone"`
+	IntervalWeeks   int32              `json:"interval_weeks"`
+	OccurrenceCount pgtype.Int4        `json:"occurrence_count"`
+	UntilDate       pgtype.Date        `json:"until_date"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Notes           pgtype.Text        `json:"notes"`
 }
 
-func (q *Queries) DeactivateSessionType(ctx context.Context, arg DeactivateSessionTypeParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deactivateSessionType, arg.ID, arg.ExpertID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) CreateBookingSeries(ctx context.Context, arg CreateBookingSeriesParams) (CoachingBookingSeries, error) {
+	row := q.db.QueryRow(ctx, createBookingSeries,
+		arg.ExpertID,
+		arg.StudentID,
+		arg.GroupID,
+		arg.SessionTypeID,
+		arg.StartsAt,
+		arg.Timezone,
+		arg.IntervalWeeks,
+		arg.OccurrenceCount,
+		arg.UntilDate,
+		arg.DurationMinutes,
+		arg.Notes,
+	)
+	var i CoachingBookingSeries
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.StartsAt,
+		&i.Timezone,
+		&i.IntervalWeeks,
+		&i.OccurrenceCount,
+		&i.UntilDate,
+		&i.DurationMinutes,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const deleteAvailability = `-- name: DeleteAvailability :execrows
-DELETE FROM coaching_availability WHERE id = $1 AND expert_id = $2
+const createClass = `-- name: CreateClass :one
+INSERT INTO coaching_classes (expert_id, group_id, session_type_id, scheduled_at, duration_minutes, capacity)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, expert_id, group_id, session_type_id, scheduled_at, duration_minutes, capacity, created_at
 `
 
-type DeleteAvailabilityParams struct {
-	ID       pgtype.UUID `json:"id"`
-	ExpertID string      `json:"expert_id"`
+type CreateClassParams struct {
+	ExpertID        string             `json:"expert_id"`
+	GroupID         pgtype.UUID        `json:"group_id"`
+	SessionTypeID   pgtype.UUID        `json:"session_type_id"`
+	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Capacity        int32              `json:"capacity"`
 }
 
-func (q *Queries) DeleteAvailability(ctx context.Context, arg DeleteAvailabilityParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteAvailability, arg.ID, arg.ExpertID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) CreateClass(ctx context.Context, arg CreateClassParams) (CoachingClass, error) {
+	row := q.db.QueryRow(ctx, createClass,
+		arg.ExpertID,
+		arg.GroupID,
+		arg.SessionTypeID,
+		arg.ScheduledAt,
+		arg.DurationMinutes,
+		arg.Capacity,
+	)
+	var i CoachingClass
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.Capacity,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const deleteBlockedSlot = `-- name: DeleteBlockedSlot :execrows
-DELETE FROM coaching_blocked_slots WHERE id = $1 AND expert_id = $2
+const createCoachingPayment = `-- name: CreateCoachingPayment :one
+INSERT INTO coaching_payments (booking_id, provider, checkout_session_id, amount_cents, currency)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING booking_id, provider, checkout_session_id, amount_cents, currency, provider_payment_id, paid_at, refund_id, refunded_at, created_at
 `
 
-type DeleteBlockedSlotParams struct {
-	ID       pgtype.UUID `json:"id"`
-	ExpertID string      `json:"expert_id"`
+type CreateCoachingPaymentParams struct {
+	BookingID         pgtype.UUID `json:"booking_id"`
+	Provider          string      `json:"provider"`
+	CheckoutSessionID string      `json:"checkout_session_id"`
+	AmountCents       int32       `json:"amount_cents"`
+	Currency          string      `json:"currency"`
 }
 
-func (q *Queries) DeleteBlockedSlot(ctx context.Context, arg DeleteBlockedSlotParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteBlockedSlot, arg.ID, arg.ExpertID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) CreateCoachingPayment(ctx context.Context, arg CreateCoachingPaymentParams) (CoachingPayment, error) {
+	row := q.db.QueryRow(ctx, createCoachingPayment,
+		arg.BookingID,
+		arg.Provider,
+		arg.CheckoutSessionID,
+		arg.AmountCents,
+		arg.Currency,
+	)
+	var i CoachingPayment
+	err := row.Scan(
+		&i.BookingID,
+		&i.Provider,
+		&i.CheckoutSessionID,
+		&i.AmountCents,
+		&i.Currency,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.RefundID,
+		&i.RefundedAt,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const ensureRecordingPartImport = `-- name: EnsureRecordingPartImport :one
-INSERT INTO coaching_recording_imports (recording_id, file_index, gcs_object_name, status, error)
-VALUES ($1, $2, $3, 'pending', NULL)
-ON CONFLICT (recording_id, file_index) DO UPDATE SET
-    gcs_object_name = EXCLUDED.gcs_object_name,
-    status = CASE WHEN coaching_recording_imports.status = 'ready'
-                  THEN coaching_recording_imports.status ELSE 'pending' END,
-    error = NULL,
-    updated_at = NOW()
-RETURNING status, gcs_object_name, mux_asset_id, mux_playback_id, video_id, attempts, last_attempt_at, imported_at, error, created_at, updated_at, id, recording_id, file_index
+const createCreditGrant = `-- name: CreateCreditGrant :one
+INSERT INTO coaching_credit_grants (student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by)
+VALUES ($1, $2, $3, $4, $4, $5, $6, $7)
+RETURNING id, student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by, created_at, updated_at
 `
 
-type EnsureRecordingPartImportParams struct {
-	RecordingID   pgtype.UUID `json:"recording_id"`
-	FileIndex     int32       `json:"file_index"`
-	GcsObjectName pgtype.Text `json:"gcs_object_name"`
+type CreateCreditGrantParams struct {
+	StudentID string             `json:"student_id"`
+	ExpertID  string             `json:"expert_id"`
+	GroupID   pgtype.UUID        `json:"group_id"`
+	Quantity  int32              `json:"quantity"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Note      pgtype.Text        `json:"note"`
+	GrantedBy string             `json:"granted_by"`
 }
 
-func (q *Queries) EnsureRecordingPartImport(ctx context.Context, arg EnsureRecordingPartImportParams) (CoachingRecordingImport, error) {
-	row := q.db.QueryRow(ctx, ensureRecordingPartImport, arg.RecordingID, arg.FileIndex, arg.GcsObjectName)
-	var i CoachingRecordingImport
+func (q *Queries) CreateCreditGrant(ctx context.Context, arg CreateCreditGrantParams) (CoachingCreditGrant, error) {
+	row := q.db.QueryRow(ctx, createCreditGrant, arg.StudentID, arg.ExpertID, arg.GroupID, arg.Quantity, arg.ExpiresAt, arg.Note, arg.GrantedBy)
+	var i CoachingCreditGrant
 	err := row.Scan(
-		&i.Status,
-		&i.GcsObjectName,
-		&i.MuxAssetID,
-		&i.MuxPlaybackID,
-		&i.VideoID,
-		&i.Attempts,
-		&i.LastAttemptAt,
-		&i.ImportedAt,
-		&i.Error,
+		&i.ID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Quantity,
+		&i.Remaining,
+		&i.ExpiresAt,
+		&i.Note,
+		&i.GrantedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.ID,
-		&i.RecordingID,
-		&i.FileIndex,
 	)
 	return i, err
 }
 
-const exchangeRecordingRendererCapability = `-- name: ExchangeRecordingRendererCapability :one
-SELECT
-    recording.id AS recording_id,
-    recording.booking_id,
-    recording.provider_uid,
-    booking.student_id,
-    booking.expert_id,
-    booking.scheduled_at,
-    booking.duration_minutes
-FROM coaching_booking_recordings recording
-JOIN coaching_bookings booking ON booking.id = recording.booking_id
-WHERE recording.renderer_token_hash = $1
-  AND recording.renderer_token_expires_at > NOW()
-  AND recording.status IN ('starting', 'started')
+const createCreditLedgerEntry = `-- name: CreateCreditLedgerEntry :one
+INSERT INTO coaching_credit_ledger (grant_id, student_id, expert_id, group_id, booking_id, kind, delta, actor_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, grant_id, student_id, expert_id, group_id, booking_id, kind, delta, actor_id, created_at
 `
 
-type ExchangeRecordingRendererCapabilityRow struct {
-	RecordingID     pgtype.UUID        `json:"recording_id"`
-	BookingID       pgtype.UUID        `json:"booking_id"`
-	ProviderUid     pgtype.Text        `json:"provider_uid"`
-	StudentID       string             `json:"student_id"`
-	ExpertID        string             `json:"expert_id"`
-	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes int32              `json:"duration_minutes"`
+type CreateCreditLedgerEntryParams struct {
+	GrantID   pgtype.UUID             `json:"grant_id"`
+	StudentID string                  `json:"student_id"`
+	ExpertID  string                  `json:"expert_id"`
+	GroupID   pgtype.UUID             `json:"group_id"`
+	BookingID pgtype.UUID             `json:"booking_id"`
+	Kind      CoachingCreditEntryKind `json:"kind"`
+	Delta     int32                   `json:"delta"`
+	ActorID   pgtype.Text             `json:"actor_id"`
 }
 
-func (q *Queries) ExchangeRecordingRendererCapability(ctx context.Context, rendererTokenHash []byte) (ExchangeRecordingRendererCapabilityRow, error) {
-	row := q.db.QueryRow(ctx, exchangeRecordingRendererCapability, rendererTokenHash)
-	var i ExchangeRecordingRendererCapabilityRow
+func (q *Queries) CreateCreditLedgerEntry(ctx context.Context, arg CreateCreditLedgerEntryParams) (CoachingCreditLedger, error) {
+	row := q.db.QueryRow(ctx, createCreditLedgerEntry, arg.GrantID, arg.StudentID, arg.ExpertID, arg.GroupID, arg.BookingID, arg.Kind, arg.Delta, arg.ActorID)
+	var i CoachingCreditLedger
 	err := row.Scan(
-		&i.RecordingID,
-		&i.BookingID,
-		&i.ProviderUid,
+		&i.ID,
+		&i.GrantID,
 		&i.StudentID,
 		&i.ExpertID,
-		&i.ScheduledAt,
-		&i.DurationMinutes,
+		&i.GroupID,
+		&i.BookingID,
+		&i.Kind,
+		&i.Delta,
+		&i.ActorID,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const getActiveRecordingPart = `-- name: GetActiveRecordingPart :one
-SELECT booking_id, status, provider_resource_id, provider_recording_id, provider_uid, output_prefix, started_at, stopped_at, error, created_at, updated_at, id, part_number, provider, renderer_token_hash, renderer_token_expires_at, empty_since_at FROM coaching_booking_recordings
-WHERE booking_id = $1 AND status IN ('starting', 'started', 'stopping')
-ORDER BY part_number DESC
-LIMIT 1
+const createExternalCalendar = `-- name: CreateExternalCalendar :one
+INSERT INTO coaching_external_calendars (expert_id, label, source_url)
+VALUES ($1, $2, $3)
+RETURNING id, expert_id, label, source_url, last_attempted_at, last_synced_at, last_error, created_at
 `
 
-func (q *Queries) GetActiveRecordingPart(ctx context.Context, bookingID pgtype.UUID) (CoachingBookingRecording, error) {
-	row := q.db.QueryRow(ctx, getActiveRecordingPart, bookingID)
-	var i CoachingBookingRecording
+type CreateExternalCalendarParams struct {
+	ExpertID  string      `json:"expert_id"`
+	Label     string      `json:"label"`
+	SourceUrl pgtype.Text `json:"source_url"`
+}
+
+func (q *Queries) CreateExternalCalendar(ctx context.Context, arg CreateExternalCalendarParams) (CoachingExternalCalendar, error) {
+	row := q.db.QueryRow(ctx, createExternalCalendar, arg.ExpertID, arg.Label, arg.SourceUrl)
+	var i CoachingExternalCalendar
 	err := row.Scan(
-		&i.BookingID,
-		&i.Status,
-		&i.ProviderResourceID,
-		&i.ProviderRecordingID,
-		&i.ProviderUid,
-		&i.OutputPrefix,
-		&i.StartedAt,
-		&i.StoppedAt,
-		&i.Error,
-		&i.CreatedAt,
-		&i.UpdatedAt,
 		&i.ID,
-		&i.PartNumber,
-		&i.Provider,
-		&i.RendererTokenHash,
-		&i.RendererTokenExpiresAt,
-		&i.EmptySinceAt,
+		&i.ExpertID,
+		&i.Label,
+		&i.SourceUrl,
+		&i.LastAttemptedAt,
+		&i.LastSyncedAt,
+		&i.LastError,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const getBooking = `-- name: GetBooking :one
-SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number FROM coaching_bookings WHERE id = $1 AND (expert_id = $2 OR student_id = $2)
+const createHomework = `-- name: CreateHomework :one
+INSERT INTO coaching_homework (booking_id, student_id, expert_id, group_id, title, description, due_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, booking_id, student_id, expert_id, group_id, title, description, due_at, completed_at, created_at, updated_at
 `
 
-type GetBookingParams struct {
-	ID       pgtype.UUID `json:"id"`
-	ExpertID string      `json:"expert_id"`
+type CreateHomeworkParams struct {
+	BookingID   pgtype.UUID        `json:"booking_id"`
+	StudentID   string             `json:"student_id"`
+	ExpertID    string             `json:"expert_id"`
+	GroupID     pgtype.UUID        `json:"group_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	DueAt       pgtype.Timestamptz `json:"due_at"`
 }
 
-func (q *Queries) GetBooking(ctx context.Context, arg GetBookingParams) (CoachingBooking, error) {
-	row := q.db.QueryRow(ctx, getBooking, arg.ID, arg.ExpertID)
-	var i CoachingBooking
+func (q *Queries) CreateHomework(ctx context.Context, arg CreateHomeworkParams) (CoachingHomework, error) {
+	row := q.db.QueryRow(ctx, createHomework,
+		arg.BookingID,
+		arg.StudentID,
+		arg.ExpertID,
+		arg.GroupID,
+		arg.Title,
+		arg.Description,
+		arg.DueAt,
+	)
+	var i CoachingHomework
 	err := row.Scan(
 		&i.ID,
-		&i.ExpertID,
+		&i.BookingID,
 		&i.StudentID,
+		&i.ExpertID,
 		&i.GroupID,
-		&i.SessionTypeID,
-		&i.ScheduledAt,
-		&i.DurationMinutes,
-		&i.IsCancelled,
-		&i.CancellationReason,
-		&i.CancelledBy,
-		&i.Notes,
+		&i.Title,
+		&i.Description,
+		&i.DueAt,
+		&i.CompletedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.RecordingAssetID,
-		&i.NextRecordingPartNumber,
 	)
 	return i, err
 }
 
-const getBookingForRecordingAssetUpdate = `-- name: GetBookingForRecordingAssetUpdate :one
-SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number FROM coaching_bookings WHERE id = $1 FOR UPDATE
+const createHomeworkReminder = `-- name: CreateHomeworkReminder :exec
+INSERT INTO coaching_booking_reminders (booking_id, homework_id, remind_at)
+VALUES ($1, $2, $3)
 `
 
-func (q *Queries) GetBookingForRecordingAssetUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
-	row := q.db.QueryRow(ctx, getBookingForRecordingAssetUpdate, id)
-	var i CoachingBooking
+type CreateHomeworkReminderParams struct {
+	BookingID  pgtype.UUID        `json:"booking_id"`
+	HomeworkID pgtype.UUID        `json:"homework_id"`
+	RemindAt   pgtype.Timestamptz `json:"remind_at"`
+}
+
+func (q *Queries) CreateHomeworkReminder(ctx context.Context, arg CreateHomeworkReminderParams) error {
+	_, err := q.db.Exec(ctx, createHomeworkReminder, arg.BookingID, arg.HomeworkID, arg.RemindAt)
+	return err
+}
+
+const createSessionType = `-- name: CreateSessionType :one
+
+INSERT INTO coaching_session_types (
+    expert_id, group_id, name, description, duration_minutes,
+    buffer_before_minutes, buffer_after_minutes, max_sessions_per_day,
+    booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes,
+    price_cents, currency, requires_credit, capacity,
+    no_show_credit_policy, notify_owner_on_no_show
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+RETURNING id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at, buffer_before_minutes, buffer_after_minutes, max_sessions_per_day, booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes, price_cents, currency, requires_credit, capacity, no_show_credit_policy, notify_owner_on_no_show
+`
+
+type CreateSessionTypeParams struct {
+	ExpertID                  string      `json:"expert_id"`
+	GroupID                   pgtype.UUID `json:"group_id"`
+	Name                      string      `json:"name"`
+	Description               string      `json:"description"`
+	DurationMinutes           int32       `json:"duration_minutes"`
+	BufferBeforeMinutes       int32       `json:"buffer_before_minutes"`
+	BufferAfterMinutes        int32       `json:"buffer_after_minutes"`
+	MaxSessionsPerDay         pgtype.Int4 `json:"max_sessions_per_day"`
+	BookingHorizonDays        pgtype.Int4 `json:"booking_horizon_days"`
+	MinBookingNoticeMinutes   pgtype.Int4 `json:"min_booking_notice_minutes"`
+	CancellationNoticeMinutes pgtype.Int4 `json:"cancellation_notice_minutes"`
+	PriceCents                int32       `json:"price_cents"`
+	Currency                  pgtype.Text `json:"currency"`
+	RequiresCredit            bool        `json:"requires_credit"`
+	Capacity                  int32       `json:"capacity"`
+	NoShowCreditPolicy        string      `json:"no_show_credit_policy"`
+	NotifyOwnerOnNoShow       bool        `json:"notify_owner_on_no_show"`
+}
+
+// === Session Types ===
+func (q *Queries) CreateSessionType(ctx context.Context, arg CreateSessionTypeParams) (CoachingSessionType, error) {
+	row := q.db.QueryRow(ctx, createSessionType,
+		arg.ExpertID,
+		arg.GroupID,
+		arg.Name,
+		arg.Description,
+		arg.DurationMinutes,
+		arg.BufferBeforeMinutes,
+		arg.BufferAfterMinutes,
+		arg.MaxSessionsPerDay,
+		arg.BookingHorizonDays,
+		arg.MinBookingNoticeMinutes,
+		arg.CancellationNoticeMinutes,
+		arg.PriceCents,
+		arg.Currency,
+		arg.RequiresCredit,
+		arg.Capacity,
+		arg.NoShowCreditPolicy,
+		arg.NotifyOwnerOnNoShow,
+	)
+	var i CoachingSessionType
 	err := row.Scan(
 		&i.ID,
 		&i.ExpertID,
-		&i.StudentID,
 		&i.GroupID,
-		&i.SessionTypeID,
-		&i.ScheduledAt,
+		&i.Name,
+		&i.Description,
 		&i.DurationMinutes,
-		&i.IsCancelled,
-		&i.CancellationReason,
-		&i.CancelledBy,
-		&i.Notes,
+		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.RecordingAssetID,
-		&i.NextRecordingPartNumber,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.MaxSessionsPerDay,
+		&i.BookingHorizonDays,
+		&i.MinBookingNoticeMinutes,
+		&i.CancellationNoticeMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.RequiresCredit,
+		&i.Capacity,
+		&i.NoShowCreditPolicy,
+		&i.NotifyOwnerOnNoShow,
 	)
 	return i, err
 }
 
-const getSessionType = `-- name: GetSessionType :one
-SELECT id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at FROM coaching_session_types WHERE id = $1 AND group_id = $2
+const createWaitlistEntry = `-- name: CreateWaitlistEntry :one
+INSERT INTO coaching_waitlist_entries (student_id, expert_id, group_id, session_type_id, preferred_weekdays)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, student_id, expert_id, group_id, session_type_id, preferred_weekdays, booked_at, created_at
+`
+
+type CreateWaitlistEntryParams struct {
+	StudentID         string      `json:"student_id"`
+	ExpertID          string      `json:"expert_id"`
+	GroupID           pgtype.UUID `json:"group_id"`
+	SessionTypeID     pgtype.UUID `json:"session_type_id"`
+	PreferredWeekdays []int16     `json:"preferred_weekdays"`
+}
+
+func (q *Queries) CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (CoachingWaitlistEntry, error) {
+	row := q.db.QueryRow(ctx, createWaitlistEntry,
+		arg.StudentID,
+		arg.ExpertID,
+		arg.GroupID,
+		arg.SessionTypeID,
+		arg.PreferredWeekdays,
+	)
+	var i CoachingWaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.PreferredWeekdays,
+		&i.BookedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWaitlistHold = `-- name: CreateWaitlistHold :one
+INSERT INTO coaching_waitlist_holds (entry_id, expert_id, scheduled_at, duration_minutes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, entry_id, expert_id, scheduled_at, duration_minutes, expires_at, status, booking_id, responded_at, created_at
+`
+
+type CreateWaitlistHoldParams struct {
+	EntryID         pgtype.UUID        `json:"entry_id"`
+	ExpertID        string             `json:"expert_id"`
+	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateWaitlistHold(ctx context.Context, arg CreateWaitlistHoldParams) (CoachingWaitlistHold, error) {
+	row := q.db.QueryRow(ctx, createWaitlistHold,
+		arg.EntryID,
+		arg.ExpertID,
+		arg.ScheduledAt,
+		arg.DurationMinutes,
+		arg.ExpiresAt,
+	)
+	var i CoachingWaitlistHold
+	err := row.Scan(
+		&i.ID,
+		&i.EntryID,
+		&i.ExpertID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.BookingID,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deactivateSessionType = `-- name: DeactivateSessionType :execrows
+UPDATE coaching_session_types
+SET is_active = false, updated_at = NOW()
+WHERE id = $1 AND expert_id = $2
+`
+
+type DeactivateSessionTypeParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) DeactivateSessionType(ctx context.Context, arg DeactivateSessionTypeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deactivateSessionType, arg.ID, arg.ExpertID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAbsence = `-- name: DeleteAbsence :execrows
+DELETE FROM coaching_absences WHERE id = $1 AND expert_id = $2
+`
+
+type DeleteAbsenceParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) DeleteAbsence(ctx context.Context, arg DeleteAbsenceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAbsence, arg.ID, arg.ExpertID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAvailability = `-- name: DeleteAvailability :execrows
+DELETE FROM coaching_availability WHERE id = $1 AND expert_id = $2
+`
+
+type DeleteAvailabilityParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) DeleteAvailability(ctx context.Context, arg DeleteAvailabilityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAvailability, arg.ID, arg.ExpertID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAvailabilityOverride = `-- name: DeleteAvailabilityOverride :execrows
+DELETE FROM coaching_availability_overrides WHERE id = $1 AND expert_id = $2
+`
+
+type DeleteAvailabilityOverrideParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) DeleteAvailabilityOverride(ctx context.Context, arg DeleteAvailabilityOverrideParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAvailabilityOverride, arg.ID, arg.ExpertID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteBlockedSlot = `-- name: DeleteBlockedSlot :execrows
+DELETE FROM coaching_blocked_slots WHERE id = $1 AND expert_id = $2
+`
+
+type DeleteBlockedSlotParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) DeleteBlockedSlot(ctx context.Context, arg DeleteBlockedSlotParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBlockedSlot, arg.ID, arg.ExpertID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCalendarFeed = `-- name: DeleteCalendarFeed :execrows
+DELETE FROM coaching_calendar_feeds WHERE user_id = $1
+`
+
+func (q *Queries) DeleteCalendarFeed(ctx context.Context, userID string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCalendarFeed, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExternalBusyBlocks = `-- name: DeleteExternalBusyBlocks :exec
+DELETE FROM coaching_external_busy_blocks WHERE calendar_id = $1
+`
+
+func (q *Queries) DeleteExternalBusyBlocks(ctx context.Context, calendarID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteExternalBusyBlocks, calendarID)
+	return err
+}
+
+const deleteExternalCalendar = `-- name: DeleteExternalCalendar :execrows
+DELETE FROM coaching_external_calendars
+WHERE id = $1 AND expert_id = $2
+`
+
+type DeleteExternalCalendarParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) DeleteExternalCalendar(ctx context.Context, arg DeleteExternalCalendarParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExternalCalendar, arg.ID, arg.ExpertID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteHomework = `-- name: DeleteHomework :exec
+DELETE FROM coaching_homework WHERE id = $1
+`
+
+func (q *Queries) DeleteHomework(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteHomework, id)
+	return err
+}
+
+const deleteUnsentBookingReminders = `-- name: DeleteUnsentBookingReminders :exec
+DELETE FROM coaching_booking_reminders
+WHERE booking_id = $1 AND sent_at IS NULL AND homework_id IS NULL
+`
+
+// Homework reminders are kept: they follow the due date, not the session.
+func (q *Queries) DeleteUnsentBookingReminders(ctx context.Context, bookingID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUnsentBookingReminders, bookingID)
+	return err
+}
+
+const deleteUnsentHomeworkReminders = `-- name: DeleteUnsentHomeworkReminders :exec
+DELETE FROM coaching_booking_reminders WHERE homework_id = $1 AND sent_at IS NULL
+`
+
+func (q *Queries) DeleteUnsentHomeworkReminders(ctx context.Context, homeworkID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUnsentHomeworkReminders, homeworkID)
+	return err
+}
+
+const deleteWaitlistEntry = `-- name: DeleteWaitlistEntry :execrows
+DELETE FROM coaching_waitlist_entries
+WHERE id = $1 AND student_id = $2 AND booked_at IS NULL
+`
+
+type DeleteWaitlistEntryParams struct {
+	ID        pgtype.UUID `json:"id"`
+	StudentID string      `json:"student_id"`
+}
+
+func (q *Queries) DeleteWaitlistEntry(ctx context.Context, arg DeleteWaitlistEntryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWaitlistEntry, arg.ID, arg.StudentID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueRecordingStitches = `-- name: EnqueueRecordingStitches :execrows
+INSERT INTO coaching_recording_stitches (booking_id, part_count)
+SELECT recording.booking_id, COUNT(*)
+FROM coaching_booking_recordings recording
+JOIN coaching_bookings booking ON booking.id = recording.booking_id
+JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
+JOIN videos video ON video.id = recording_import.video_id AND video.deleted_at IS NULL
+WHERE recording_import.status = 'ready'
+  AND booking.recording_asset_id IS NOT NULL
+  AND booking.scheduled_at >= NOW() - interval '7 days'
+  AND booking.scheduled_at + (booking.duration_minutes * interval '1 minute')
+      + ($1::int * interval '1 second') <= NOW()
+  AND NOT EXISTS (
+      SELECT 1
+      FROM coaching_booking_recordings other
+      LEFT JOIN coaching_recording_imports other_import ON other_import.recording_id = other.id
+      LEFT JOIN videos other_video ON other_video.id = other_import.video_id AND other_video.deleted_at IS NULL
+      WHERE other.booking_id = recording.booking_id
+        AND (
+            other.status IN ('starting', 'started', 'stopping')
+            OR (other.status = 'stopped' AND other_import.id IS NULL AND other.stopped_at > NOW() - interval '15 minutes')
+            OR other_import.status IN ('pending', 'importing', 'processing')
+            OR (other_import.status = 'failed' AND other_import.attempts < 5)
+            OR (other_video.id IS NOT NULL AND other_video.duration_seconds IS NULL)
+        )
+  )
+GROUP BY recording.booking_id
+HAVING COUNT(*) > 1
+ON CONFLICT (booking_id) DO NOTHING
+`
+
+// Queues a stitch for every recent booking whose recording has more than one
+// imported part video once nothing is in flight: no part recording, no import
+// running or due a retry, no part stopped in the last 15 minutes without files,
+// and a known duration for every part video.
+func (q *Queries) EnqueueRecordingStitches(ctx context.Context, endGraceSeconds int32) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueRecordingStitches, endGraceSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const ensureRecordingPartImport = `-- name: EnsureRecordingPartImport :one
+INSERT INTO coaching_recording_imports (recording_id, file_index, gcs_object_name, status, error)
+VALUES ($1, $2, $3, 'pending', NULL)
+ON CONFLICT (recording_id, file_index) DO UPDATE SET
+    gcs_object_name = EXCLUDED.gcs_object_name,
+    status = CASE WHEN coaching_recording_imports.status = 'ready'
+                  THEN coaching_recording_imports.status ELSE 'pending' END,
+    error = NULL,
+    updated_at = NOW()
+RETURNING status, gcs_object_name, mux_asset_id, mux_playback_id, video_id, attempts, last_attempt_at, imported_at, error, created_at, updated_at, id, recording_id, file_index
+`
+
+type EnsureRecordingPartImportParams struct {
+	RecordingID   pgtype.UUID `json:"recording_id"`
+	FileIndex     int32       `json:"file_index"`
+	GcsObjectName pgtype.Text `json:"gcs_object_name"`
+}
+
+func (q *Queries) EnsureRecordingPartImport(ctx context.Context, arg EnsureRecordingPartImportParams) (CoachingRecordingImport, error) {
+	row := q.db.QueryRow(ctx, ensureRecordingPartImport, arg.RecordingID, arg.FileIndex, arg.GcsObjectName)
+	var i CoachingRecordingImport
+	err := row.Scan(
+		&i.Status,
+		&i.GcsObjectName,
+		&i.MuxAssetID,
+		&i.MuxPlaybackID,
+		&i.VideoID,
+		&i.Attempts,
+		&i.LastAttemptAt,
+		&i.ImportedAt,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ID,
+		&i.RecordingID,
+		&i.FileIndex,
+	)
+	return i, err
+}
+
+const exchangeRecordingRendererCapability = `-- name: ExchangeRecordingRendererCapability :one
+SELECT
+    recording.id AS recording_id,
+    recording.booking_id,
+    recording.provider_uid,
+    booking.student_id,
+    booking.expert_id,
+    booking.scheduled_at,
+    booking.duration_minutes,
+    booking.class_id
+FROM coaching_booking_recordings recording
+JOIN coaching_bookings booking ON booking.id = recording.booking_id
+WHERE recording.renderer_token_hash = $1
+  AND recording.renderer_token_expires_at > NOW()
+  AND recording.status IN ('starting', 'started')
+`
+
+type ExchangeRecordingRendererCapabilityRow struct {
+	RecordingID     pgtype.UUID        `json:"recording_id"`
+	BookingID       pgtype.UUID        `json:"booking_id"`
+	ProviderUid     pgtype.Text        `json:"provider_uid"`
+	StudentID       string             `json:"student_id"`
+	ExpertID        string             `json:"expert_id"`
+	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	ClassID         pgtype.UUID        `json:"class_id"`
+}
+
+func (q *Queries) ExchangeRecordingRendererCapability(ctx context.Context, rendererTokenHash []byte) (ExchangeRecordingRendererCapabilityRow, error) {
+	row := q.db.QueryRow(ctx, exchangeRecordingRendererCapability, rendererTokenHash)
+	var i ExchangeRecordingRendererCapabilityRow
+	err := row.Scan(
+		&i.RecordingID,
+		&i.BookingID,
+		&i.ProviderUid,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.ClassID,
+	)
+	return i, err
+}
+
+const expireBookingPayment = `-- name: ExpireBookingPayment :one
+UPDATE coaching_bookings
+SET is_cancelled = true,
+    cancellation_reason = 'Payment not completed',
+    payment_status = 'expired',
+    updated_at = NOW()
+WHERE id = $1 AND payment_status = 'pending' AND is_cancelled = false
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
+`
+
+// Cancels an active booking still waiting for its payment.
+func (q *Queries) ExpireBookingPayment(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, expireBookingPayment, id)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const expireCreditGrant = `-- name: ExpireCreditGrant :one
+UPDATE coaching_credit_grants
+SET remaining = 0, updated_at = NOW()
+WHERE id = $1 AND remaining > 0
+RETURNING id, student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by, created_at, updated_at
+`
+
+func (q *Queries) ExpireCreditGrant(ctx context.Context, id pgtype.UUID) (CoachingCreditGrant, error) {
+	row := q.db.QueryRow(ctx, expireCreditGrant, id)
+	var i CoachingCreditGrant
+	err := row.Scan(
+		&i.ID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Quantity,
+		&i.Remaining,
+		&i.ExpiresAt,
+		&i.Note,
+		&i.GrantedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const expireWaitlistHolds = `-- name: ExpireWaitlistHolds :many
+UPDATE coaching_waitlist_holds
+SET status = 'expired', responded_at = NOW()
+WHERE status = 'offered' AND expires_at <= NOW()
+RETURNING id, entry_id, expert_id, scheduled_at, duration_minutes, expires_at, status, booking_id, responded_at, created_at
+`
+
+// Closes offered holds past expires_at so their slots can go to the next
+// student in line.
+func (q *Queries) ExpireWaitlistHolds(ctx context.Context) ([]CoachingWaitlistHold, error) {
+	rows, err := q.db.Query(ctx, expireWaitlistHolds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingWaitlistHold
+	for rows.Next() {
+		var i CoachingWaitlistHold
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntryID,
+			&i.ExpertID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.ExpiresAt,
+			&i.Status,
+			&i.BookingID,
+			&i.RespondedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveRecordingPart = `-- name: GetActiveRecordingPart :one
+SELECT booking_id, status, provider_resource_id, provider_recording_id, provider_uid, output_prefix, started_at, stopped_at, error, created_at, updated_at, id, part_number, provider, renderer_token_hash, renderer_token_expires_at, empty_since_at FROM coaching_booking_recordings
+WHERE booking_id = $1 AND status IN ('starting', 'started', 'stopping')
+ORDER BY part_number DESC
+LIMIT 1
+`
+
+func (q *Queries) GetActiveRecordingPart(ctx context.Context, bookingID pgtype.UUID) (CoachingBookingRecording, error) {
+	row := q.db.QueryRow(ctx, getActiveRecordingPart, bookingID)
+	var i CoachingBookingRecording
+	err := row.Scan(
+		&i.BookingID,
+		&i.Status,
+		&i.ProviderResourceID,
+		&i.ProviderRecordingID,
+		&i.ProviderUid,
+		&i.OutputPrefix,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ID,
+		&i.PartNumber,
+		&i.Provider,
+		&i.RendererTokenHash,
+		&i.RendererTokenExpiresAt,
+		&i.EmptySinceAt,
+	)
+	return i, err
+}
+
+const getBooking = `-- name: GetBooking :one
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings WHERE id = $1 AND (expert_id = $2 OR student_id = $2)
+`
+
+type GetBookingParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) GetBooking(ctx context.Context, arg GetBookingParams) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, getBooking, arg.ID, arg.ExpertID)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const getBookingForMessageUpdate = `-- name: GetBookingForMessageUpdate :one
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings WHERE id = $1 FOR UPDATE
+`
+
+// Locks the call's host booking so posts to its chat are counted one at a time.
+func (q *Queries) GetBookingForMessageUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, getBookingForMessageUpdate, id)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const getBookingForPaymentUpdate = `-- name: GetBookingForPaymentUpdate :one
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetBookingForPaymentUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, getBookingForPaymentUpdate, id)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const getBookingForRecordingAssetUpdate = `-- name: GetBookingForRecordingAssetUpdate :one
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetBookingForRecordingAssetUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, getBookingForRecordingAssetUpdate, id)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const getBookingMessage = `-- name: GetBookingMessage :one
+SELECT id, booking_id, author_id, author_role, body, links, created_at FROM coaching_booking_messages WHERE id = $1
+`
+
+func (q *Queries) GetBookingMessage(ctx context.Context, id pgtype.UUID) (CoachingBookingMessage, error) {
+	row := q.db.QueryRow(ctx, getBookingMessage, id)
+	var i CoachingBookingMessage
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.AuthorID,
+		&i.AuthorRole,
+		&i.Body,
+		&i.Links,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCalendarFeed = `-- name: GetCalendarFeed :one
+SELECT user_id, token_hash, created_at, last_accessed_at FROM coaching_calendar_feeds WHERE user_id = $1
+`
+
+func (q *Queries) GetCalendarFeed(ctx context.Context, userID string) (CoachingCalendarFeed, error) {
+	row := q.db.QueryRow(ctx, getCalendarFeed, userID)
+	var i CoachingCalendarFeed
+	err := row.Scan(
+		&i.UserID,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.LastAccessedAt,
+	)
+	return i, err
+}
+
+const getClassForSlot = `-- name: GetClassForSlot :one
+SELECT id, expert_id, group_id, session_type_id, scheduled_at, duration_minutes, capacity, created_at FROM coaching_classes
+WHERE session_type_id = $1 AND scheduled_at = $2
+FOR UPDATE
+`
+
+type GetClassForSlotParams struct {
+	SessionTypeID pgtype.UUID        `json:"session_type_id"`
+	ScheduledAt   pgtype.Timestamptz `json:"scheduled_at"`
+}
+
+func (q *Queries) GetClassForSlot(ctx context.Context, arg GetClassForSlotParams) (CoachingClass, error) {
+	row := q.db.QueryRow(ctx, getClassForSlot, arg.SessionTypeID, arg.ScheduledAt)
+	var i CoachingClass
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.Capacity,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getClassHostBooking = `-- name: GetClassHostBooking :one
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings WHERE class_id = $1 AND class_seat = 1
+`
+
+func (q *Queries) GetClassHostBooking(ctx context.Context, classID pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, getClassHostBooking, classID)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const getClassSeats = `-- name: GetClassSeats :one
+SELECT COUNT(*) FILTER (WHERE is_cancelled = false) AS taken,
+       (COUNT(*) FILTER (WHERE is_cancelled = false AND student_id = $1) > 0)::boolean AS joined,
+       COALESCE(MAX(class_seat), 0)::int AS last_seat
+FROM coaching_bookings
+WHERE class_id = $2
+`
+
+type GetClassSeatsParams struct {
+	StudentID string      `json:"student_id"`
+	ClassID   pgtype.UUID `json:"class_id"`
+}
+
+type GetClassSeatsRow struct {
+	Taken    int64 `json:"taken"`
+	Joined   bool  `json:"joined"`
+	LastSeat int32 `json:"last_seat"`
+}
+
+// Seats taken by active bookings, whether the student holds one of them, and
+// the last seat number ever handed out in the class.
+func (q *Queries) GetClassSeats(ctx context.Context, arg GetClassSeatsParams) (GetClassSeatsRow, error) {
+	row := q.db.QueryRow(ctx, getClassSeats, arg.StudentID, arg.ClassID)
+	var i GetClassSeatsRow
+	err := row.Scan(
+		&i.Taken,
+		&i.Joined,
+		&i.LastSeat,
+	)
+	return i, err
+}
+
+const getCoachingPayment = `-- name: GetCoachingPayment :one
+SELECT booking_id, provider, checkout_session_id, amount_cents, currency, provider_payment_id, paid_at, refund_id, refunded_at, created_at FROM coaching_payments WHERE booking_id = $1
+`
+
+func (q *Queries) GetCoachingPayment(ctx context.Context, bookingID pgtype.UUID) (CoachingPayment, error) {
+	row := q.db.QueryRow(ctx, getCoachingPayment, bookingID)
+	var i CoachingPayment
+	err := row.Scan(
+		&i.BookingID,
+		&i.Provider,
+		&i.CheckoutSessionID,
+		&i.AmountCents,
+		&i.Currency,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.RefundID,
+		&i.RefundedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCoachingPaymentByCheckout = `-- name: GetCoachingPaymentByCheckout :one
+SELECT booking_id, provider, checkout_session_id, amount_cents, currency, provider_payment_id, paid_at, refund_id, refunded_at, created_at FROM coaching_payments WHERE provider = $1 AND checkout_session_id = $2
+`
+
+type GetCoachingPaymentByCheckoutParams struct {
+	Provider          string `json:"provider"`
+	CheckoutSessionID string `json:"checkout_session_id"`
+}
+
+func (q *Queries) GetCoachingPaymentByCheckout(ctx context.Context, arg GetCoachingPaymentByCheckoutParams) (CoachingPayment, error) {
+	row := q.db.QueryRow(ctx, getCoachingPaymentByCheckout, arg.Provider, arg.CheckoutSessionID)
+	var i CoachingPayment
+	err := row.Scan(
+		&i.BookingID,
+		&i.Provider,
+		&i.CheckoutSessionID,
+		&i.AmountCents,
+		&i.Currency,
+		&i.ProviderPaymentID,
+		&i.PaidAt,
+		&i.RefundID,
+		&i.RefundedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getExternalCalendar = `-- name: GetExternalCalendar :one
+SELECT id, expert_id, label, source_url, last_attempted_at, last_synced_at, last_error, created_at FROM coaching_external_calendars
+WHERE id = $1 AND expert_id = $2
+`
+
+type GetExternalCalendarParams struct {
+	ID       pgtype.UUID `json:"id"`
+	ExpertID string      `json:"expert_id"`
+}
+
+func (q *Queries) GetExternalCalendar(ctx context.Context, arg GetExternalCalendarParams) (CoachingExternalCalendar, error) {
+	row := q.db.QueryRow(ctx, getExternalCalendar, arg.ID, arg.ExpertID)
+	var i CoachingExternalCalendar
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.Label,
+		&i.SourceUrl,
+		&i.LastAttemptedAt,
+		&i.LastSyncedAt,
+		&i.LastError,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getHomework = `-- name: GetHomework :one
+SELECT id, booking_id, student_id, expert_id, group_id, title, description, due_at, completed_at, created_at, updated_at FROM coaching_homework WHERE id = $1 AND group_id = $2
+`
+
+type GetHomeworkParams struct {
+	ID      pgtype.UUID `json:"id"`
+	GroupID pgtype.UUID `json:"group_id"`
+}
+
+func (q *Queries) GetHomework(ctx context.Context, arg GetHomeworkParams) (CoachingHomework, error) {
+	row := q.db.QueryRow(ctx, getHomework, arg.ID, arg.GroupID)
+	var i CoachingHomework
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Title,
+		&i.Description,
+		&i.DueAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOfferedWaitlistHold = `-- name: GetOfferedWaitlistHold :one
+SELECT id, entry_id, expert_id, scheduled_at, duration_minutes, expires_at, status, booking_id, responded_at, created_at FROM coaching_waitlist_holds
+WHERE entry_id = $1 AND status = 'offered'
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetOfferedWaitlistHold(ctx context.Context, entryID pgtype.UUID) (CoachingWaitlistHold, error) {
+	row := q.db.QueryRow(ctx, getOfferedWaitlistHold, entryID)
+	var i CoachingWaitlistHold
+	err := row.Scan(
+		&i.ID,
+		&i.EntryID,
+		&i.ExpertID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.BookingID,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPendingBookingReschedule = `-- name: GetPendingBookingReschedule :one
+SELECT id, booking_id, proposed_by, scheduled_at, previous_scheduled_at, note, status, responded_by, responded_at, created_at FROM coaching_booking_reschedules
+WHERE booking_id = $1 AND status = 'pending'
+`
+
+func (q *Queries) GetPendingBookingReschedule(ctx context.Context, bookingID pgtype.UUID) (CoachingBookingReschedule, error) {
+	row := q.db.QueryRow(ctx, getPendingBookingReschedule, bookingID)
+	var i CoachingBookingReschedule
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.ProposedBy,
+		&i.ScheduledAt,
+		&i.PreviousScheduledAt,
+		&i.Note,
+		&i.Status,
+		&i.RespondedBy,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRecordingStitch = `-- name: GetRecordingStitch :one
+SELECT booking_id, status, part_count, object_name, mux_asset_id, mux_playback_id, video_id, attempts, last_attempt_at, stitched_at, error, created_at, updated_at FROM coaching_recording_stitches WHERE booking_id = $1
+`
+
+func (q *Queries) GetRecordingStitch(ctx context.Context, bookingID pgtype.UUID) (CoachingRecordingStitch, error) {
+	row := q.db.QueryRow(ctx, getRecordingStitch, bookingID)
+	var i CoachingRecordingStitch
+	err := row.Scan(
+		&i.BookingID,
+		&i.Status,
+		&i.PartCount,
+		&i.ObjectName,
+		&i.MuxAssetID,
+		&i.MuxPlaybackID,
+		&i.VideoID,
+		&i.Attempts,
+		&i.LastAttemptAt,
+		&i.StitchedAt,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSessionNotes = `-- name: GetSessionNotes :one
+SELECT booking_id, author_id, summary, went_well, to_improve, next_focus, created_at, updated_at FROM coaching_session_notes WHERE booking_id = $1
+`
+
+func (q *Queries) GetSessionNotes(ctx context.Context, bookingID pgtype.UUID) (CoachingSessionNote, error) {
+	row := q.db.QueryRow(ctx, getSessionNotes, bookingID)
+	var i CoachingSessionNote
+	err := row.Scan(
+		&i.BookingID,
+		&i.AuthorID,
+		&i.Summary,
+		&i.WentWell,
+		&i.ToImprove,
+		&i.NextFocus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSessionType = `-- name: GetSessionType :one
+SELECT id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at, buffer_before_minutes, buffer_after_minutes, max_sessions_per_day, booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes, price_cents, currency, requires_credit, capacity, no_show_credit_policy, notify_owner_on_no_show FROM coaching_session_types WHERE id = $1 AND group_id = $2
+`
+
+type GetSessionTypeParams struct {
+	ID      pgtype.UUID `json:"id"`
+	GroupID pgtype.UUID `json:"group_id"`
+}
+
+func (q *Queries) GetSessionType(ctx context.Context, arg GetSessionTypeParams) (CoachingSessionType, error) {
+	row := q.db.QueryRow(ctx, getSessionType, arg.ID, arg.GroupID)
+	var i CoachingSessionType
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Name,
+		&i.Description,
+		&i.DurationMinutes,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.MaxSessionsPerDay,
+		&i.BookingHorizonDays,
+		&i.MinBookingNoticeMinutes,
+		&i.CancellationNoticeMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.RequiresCredit,
+		&i.Capacity,
+		&i.NoShowCreditPolicy,
+		&i.NotifyOwnerOnNoShow,
+	)
+	return i, err
+}
+
+const getUserTimezone = `-- name: GetUserTimezone :one
+
+SELECT timezone FROM user_preferences WHERE user_id = $1
+`
+
+// === Timezone ===
+func (q *Queries) GetUserTimezone(ctx context.Context, userID string) (string, error) {
+	row := q.db.QueryRow(ctx, getUserTimezone, userID)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
+const getWaitlistEntry = `-- name: GetWaitlistEntry :one
+SELECT id, student_id, expert_id, group_id, session_type_id, preferred_weekdays, booked_at, created_at FROM coaching_waitlist_entries
+WHERE id = $1 AND student_id = $2 AND booked_at IS NULL
+`
+
+type GetWaitlistEntryParams struct {
+	ID        pgtype.UUID `json:"id"`
+	StudentID string      `json:"student_id"`
+}
+
+func (q *Queries) GetWaitlistEntry(ctx context.Context, arg GetWaitlistEntryParams) (CoachingWaitlistEntry, error) {
+	row := q.db.QueryRow(ctx, getWaitlistEntry, arg.ID, arg.StudentID)
+	var i CoachingWaitlistEntry
+	err := row.Scan(
+		&i.ID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.PreferredWeekdays,
+		&i.BookedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertExternalBusyBlocks = `-- name: InsertExternalBusyBlocks :exec
+INSERT INTO coaching_external_busy_blocks (calendar_id, starts_at, ends_at)
+SELECT $1::uuid, unnest($2::timestamptz[]), unnest($3::timestamptz[])
+`
+
+type InsertExternalBusyBlocksParams struct {
+	CalendarID pgtype.UUID          `json:"calendar_id"`
+	StartsAt   []pgtype.Timestamptz `json:"starts_at"`
+	EndsAt     []pgtype.Timestamptz `json:"ends_at"`
+}
+
+func (q *Queries) InsertExternalBusyBlocks(ctx context.Context, arg InsertExternalBusyBlocksParams) error {
+	_, err := q.db.Exec(ctx, insertExternalBusyBlocks, arg.CalendarID, arg.StartsAt, arg.EndsAt)
+	return err
+}
+
+const insertWaitlistWindows = `-- name: InsertWaitlistWindows :exec
+INSERT INTO coaching_waitlist_windows (entry_id, start_time, end_time)
+SELECT $1::uuid, unnest($2::time[]), unnest($3::time[])
+`
+
+type InsertWaitlistWindowsParams struct {
+	EntryID    pgtype.UUID   `json:"entry_id"`
+	StartTimes []pgtype.Time `json:"start_times"`
+	EndTimes   []pgtype.Time `json:"end_times"`
+}
+
+func (q *Queries) InsertWaitlistWindows(ctx context.Context, arg InsertWaitlistWindowsParams) error {
+	_, err := q.db.Exec(ctx, insertWaitlistWindows, arg.EntryID, arg.StartTimes, arg.EndTimes)
+	return err
+}
+
+const listAbsences = `-- name: ListAbsences :many
+SELECT id, expert_id, starts_on, ends_on, reason, created_at FROM coaching_absences
+WHERE expert_id = $1 AND ends_on >= $2 AND starts_on <= $3
+ORDER BY starts_on
+`
+
+type ListAbsencesParams struct {
+	ExpertID string      `json:"expert_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+// Absences overlapping the inclusive date range.
+func (q *Queries) ListAbsences(ctx context.Context, arg ListAbsencesParams) ([]CoachingAbsence, error) {
+	rows, err := q.db.Query(ctx, listAbsences, arg.ExpertID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingAbsence
+	for rows.Next() {
+		var i CoachingAbsence
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StartsOn,
+			&i.EndsOn,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveExpertsInGroup = `-- name: ListActiveExpertsInGroup :many
+SELECT DISTINCT expert_id FROM coaching_availability
+WHERE group_id = $1 AND is_active = true
+`
+
+func (q *Queries) ListActiveExpertsInGroup(ctx context.Context, groupID pgtype.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listActiveExpertsInGroup, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var expert_id string
+		if err := rows.Scan(&expert_id); err != nil {
+			return nil, err
+		}
+		items = append(items, expert_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveSeriesBookingsFrom = `-- name: ListActiveSeriesBookingsFrom :many
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings
+WHERE series_id = $1 AND scheduled_at >= $2 AND is_cancelled = false
+ORDER BY scheduled_at
+FOR UPDATE
+`
+
+type ListActiveSeriesBookingsFromParams struct {
+	SeriesID    pgtype.UUID        `json:"series_id"`
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+}
+
+func (q *Queries) ListActiveSeriesBookingsFrom(ctx context.Context, arg ListActiveSeriesBookingsFromParams) ([]CoachingBooking, error) {
+	rows, err := q.db.Query(ctx, listActiveSeriesBookingsFrom, arg.SeriesID, arg.ScheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBooking
+	for rows.Next() {
+		var i CoachingBooking
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllMyBookings = `-- name: ListAllMyBookings :many
+SELECT cb.id, cb.expert_id, cb.student_id, cb.group_id, cb.session_type_id, cb.scheduled_at, cb.duration_minutes, cb.is_cancelled, cb.cancellation_reason, cb.cancelled_by, cb.notes, cb.created_at, cb.updated_at, cb.recording_asset_id, cb.next_recording_part_number, cb.series_id, cb.buffer_before_minutes, cb.buffer_after_minutes, cb.price_cents, cb.currency, cb.payment_status, cb.payment_expires_at, cb.credit_grant_id, cb.class_id, cb.class_seat, cb.attendance_outcome, cb.attendance_recorded_at, cst.name AS session_type_name,
+       COALESCE(latest.recording_status, '')::varchar AS recording_status,
+       latest.video_id AS recording_video_id,
+       pending_reschedule.id AS reschedule_id,
+       pending_reschedule.proposed_by AS reschedule_proposed_by,
+       pending_reschedule.scheduled_at AS reschedule_scheduled_at
+FROM coaching_bookings cb
+JOIN coaching_session_types cst ON cst.id = cb.session_type_id
+LEFT JOIN LATERAL (
+    SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
+           COALESCE(stitch.video_id, recording_import.video_id) AS video_id
+    FROM coaching_booking_recordings recording
+    LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
+    LEFT JOIN coaching_recording_stitches stitch
+        ON stitch.booking_id = recording.booking_id AND stitch.status = 'ready'
+    WHERE recording.booking_id = COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = cb.class_id AND host.class_seat = 1), cb.id
+    )
+    ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
+    LIMIT 1
+) latest ON true
+LEFT JOIN coaching_booking_reschedules pending_reschedule
+    ON pending_reschedule.booking_id = cb.id AND pending_reschedule.status = 'pending'
+WHERE cb.expert_id = $1 OR cb.student_id = $1
+ORDER BY cb.scheduled_at ASC
+`
+
+type ListAllMyBookingsRow struct {
+	ID                      pgtype.UUID                   `json:"id"`
+	ExpertID                string                        `json:"expert_id"`
+	StudentID               string                        `json:"student_id"`
+	GroupID                 pgtype.UUID                   `json:"group_id"`
+	SessionTypeID           pgtype.UUID                   `json:"session_type_id"`
+	ScheduledAt             pgtype.Timestamptz            `json:"scheduled_at"`
+	DurationMinutes         int32                         `json:"duration_minutes"`
+	IsCancelled             bool                          `json:"is_cancelled"`
+	CancellationReason      pgtype.Text                   `json:"cancellation_reason"`
+	CancelledBy             pgtype.Text                   `json:"cancelled_by"`
+	Notes                   pgtype.Text                   `json:"notes"`
+	CreatedAt               pgtype.Timestamptz            `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz            `json:"updated_at"`
+	RecordingAssetID        pgtype.UUID                   `json:"recording_asset_id"`
+	NextRecordingPartNumber int32                         `json:"next_recording_part_number"`
+	SeriesID                pgtype.UUID                   `json:"series_id"`
+	BufferBeforeMinutes     int32                         `json:"buffer_before_minutes"`
+	BufferAfterMinutes      int32                         `json:"buffer_after_minutes"`
+	PriceCents              int32                         `json:"price_cents"`
+	Currency                pgtype.Text                   `json:"currency"`
+	PaymentStatus           NullCoachingPaymentStatus     `json:"payment_status"`
+	PaymentExpiresAt        pgtype.Timestamptz            `json:"payment_expires_at"`
+	CreditGrantID           pgtype.UUID                   `json:"credit_grant_id"`
+	ClassID                 pgtype.UUID                   `json:"class_id"`
+	ClassSeat               pgtype.Int4                   `json:"class_seat"`
+	AttendanceOutcome       NullCoachingAttendanceOutcome `json:"attendance_outcome"`
+	AttendanceRecordedAt    pgtype.Timestamptz            `json:"attendance_recorded_at"`
+	SessionTypeName         string                        `json:"session_type_name"`
+	RecordingStatus         string                        `json:"recording_status"`
+	RecordingVideoID        pgtype.UUID                   `json:"recording_video_id"`
+	RescheduleID            pgtype.UUID                   `json:"reschedule_id"`
+	RescheduleProposedBy    pgtype.Text                   `json:"reschedule_proposed_by"`
+	RescheduleScheduledAt   pgtype.Timestamptz            `json:"reschedule_scheduled_at"`
+}
+
+func (q *Queries) ListAllMyBookings(ctx context.Context, expertID string) ([]ListAllMyBookingsRow, error) {
+	rows, err := q.db.Query(ctx, listAllMyBookings, expertID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllMyBookingsRow
+	for rows.Next() {
+		var i ListAllMyBookingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
+			&i.SessionTypeName,
+			&i.RecordingStatus,
+			&i.RecordingVideoID,
+			&i.RescheduleID,
+			&i.RescheduleProposedBy,
+			&i.RescheduleScheduledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAvailabilityByExpertGroup = `-- name: ListAvailabilityByExpertGroup :many
+SELECT id, expert_id, group_id, day_of_week, start_time, end_time, is_active, created_at, updated_at FROM coaching_availability
+WHERE expert_id = $1 AND group_id = $2 AND is_active = true
+ORDER BY day_of_week, start_time
+`
+
+type ListAvailabilityByExpertGroupParams struct {
+	ExpertID string      `json:"expert_id"`
+	GroupID  pgtype.UUID `json:"group_id"`
+}
+
+func (q *Queries) ListAvailabilityByExpertGroup(ctx context.Context, arg ListAvailabilityByExpertGroupParams) ([]CoachingAvailability, error) {
+	rows, err := q.db.Query(ctx, listAvailabilityByExpertGroup, arg.ExpertID, arg.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingAvailability
+	for rows.Next() {
+		var i CoachingAvailability
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.DayOfWeek,
+			&i.StartTime,
+			&i.EndTime,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAvailabilityByExpertGroupDay = `-- name: ListAvailabilityByExpertGroupDay :many
+SELECT id, expert_id, group_id, day_of_week, start_time, end_time, is_active, created_at, updated_at FROM coaching_availability
+WHERE expert_id = $1 AND group_id = $2 AND day_of_week = $3 AND is_active = true
+ORDER BY start_time
+`
+
+type ListAvailabilityByExpertGroupDayParams struct {
+	ExpertID  string      `json:"expert_id"`
+	GroupID   pgtype.UUID `json:"group_id"`
+	DayOfWeek int16       `json:"day_of_week"`
+}
+
+func (q *Queries) ListAvailabilityByExpertGroupDay(ctx context.Context, arg ListAvailabilityByExpertGroupDayParams) ([]CoachingAvailability, error) {
+	rows, err := q.db.Query(ctx, listAvailabilityByExpertGroupDay, arg.ExpertID, arg.GroupID, arg.DayOfWeek)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingAvailability
+	for rows.Next() {
+		var i CoachingAvailability
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.DayOfWeek,
+			&i.StartTime,
+			&i.EndTime,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAvailabilityByGroup = `-- name: ListAvailabilityByGroup :many
+SELECT id, expert_id, group_id, day_of_week, start_time, end_time, is_active, created_at, updated_at FROM coaching_availability
+WHERE group_id = $1 AND is_active = true
+ORDER BY expert_id, day_of_week, start_time
+`
+
+func (q *Queries) ListAvailabilityByGroup(ctx context.Context, groupID pgtype.UUID) ([]CoachingAvailability, error) {
+	rows, err := q.db.Query(ctx, listAvailabilityByGroup, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingAvailability
+	for rows.Next() {
+		var i CoachingAvailability
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.DayOfWeek,
+			&i.StartTime,
+			&i.EndTime,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAvailabilityOverrides = `-- name: ListAvailabilityOverrides :many
+SELECT id, expert_id, group_id, override_date, start_time, end_time, created_at FROM coaching_availability_overrides
+WHERE expert_id = $1 AND group_id = $2
+  AND override_date >= $3 AND override_date <= $4
+ORDER BY override_date, start_time
+`
+
+type ListAvailabilityOverridesParams struct {
+	ExpertID string      `json:"expert_id"`
+	GroupID  pgtype.UUID `json:"group_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+func (q *Queries) ListAvailabilityOverrides(ctx context.Context, arg ListAvailabilityOverridesParams) ([]CoachingAvailabilityOverride, error) {
+	rows, err := q.db.Query(ctx, listAvailabilityOverrides,
+		arg.ExpertID,
+		arg.GroupID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingAvailabilityOverride
+	for rows.Next() {
+		var i CoachingAvailabilityOverride
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.OverrideDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBlockedSlots = `-- name: ListBlockedSlots :many
+SELECT id, expert_id, blocked_date, start_time, end_time, reason, created_at FROM coaching_blocked_slots
+WHERE expert_id = $1 AND blocked_date >= $2 AND blocked_date <= $3
+ORDER BY blocked_date, start_time
+`
+
+type ListBlockedSlotsParams struct {
+	ExpertID string      `json:"expert_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+func (q *Queries) ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsParams) ([]CoachingBlockedSlot, error) {
+	rows, err := q.db.Query(ctx, listBlockedSlots, arg.ExpertID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBlockedSlot
+	for rows.Next() {
+		var i CoachingBlockedSlot
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.BlockedDate,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingAttendance = `-- name: ListBookingAttendance :many
+SELECT booking_id, participant_id, participant_role, attended_seconds, first_seen_at, last_seen_at FROM coaching_booking_attendance
+WHERE booking_id = $1
+ORDER BY first_seen_at
+`
+
+func (q *Queries) ListBookingAttendance(ctx context.Context, bookingID pgtype.UUID) ([]CoachingBookingAttendance, error) {
+	rows, err := q.db.Query(ctx, listBookingAttendance, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBookingAttendance
+	for rows.Next() {
+		var i CoachingBookingAttendance
+		if err := rows.Scan(
+			&i.BookingID,
+			&i.ParticipantID,
+			&i.ParticipantRole,
+			&i.AttendedSeconds,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingHomework = `-- name: ListBookingHomework :many
+SELECT id, booking_id, student_id, expert_id, group_id, title, description, due_at, completed_at, created_at, updated_at FROM coaching_homework
+WHERE booking_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListBookingHomework(ctx context.Context, bookingID pgtype.UUID) ([]CoachingHomework, error) {
+	rows, err := q.db.Query(ctx, listBookingHomework, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingHomework
+	for rows.Next() {
+		var i CoachingHomework
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.StudentID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.Title,
+			&i.Description,
+			&i.DueAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingMessages = `-- name: ListBookingMessages :many
+SELECT id, booking_id, author_id, author_role, body, links, created_at FROM coaching_booking_messages
+WHERE booking_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListBookingMessages(ctx context.Context, bookingID pgtype.UUID) ([]CoachingBookingMessage, error) {
+	rows, err := q.db.Query(ctx, listBookingMessages, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBookingMessage
+	for rows.Next() {
+		var i CoachingBookingMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.AuthorID,
+			&i.AuthorRole,
+			&i.Body,
+			&i.Links,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingRecordingImports = `-- name: ListBookingRecordingImports :many
+SELECT recording.id AS recording_id, recording.part_number, recording.status AS recording_status,
+       recording.error AS recording_error, recording.started_at, recording.stopped_at,
+       recording_import.file_index, recording_import.status AS import_status,
+       recording_import.attempts AS import_attempts, recording_import.error AS import_error,
+       recording_import.video_id
+FROM coaching_booking_recordings recording
+LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
+WHERE recording.booking_id = $1
+ORDER BY recording.part_number, recording_import.file_index
+`
+
+type ListBookingRecordingImportsRow struct {
+	RecordingID     pgtype.UUID                       `json:"recording_id"`
+	PartNumber      int32                             `json:"part_number"`
+	RecordingStatus CoachingRecordingStatus           `json:"recording_status"`
+	RecordingError  pgtype.Text                       `json:"recording_error"`
+	StartedAt       pgtype.Timestamptz                `json:"started_at"`
+	StoppedAt       pgtype.Timestamptz                `json:"stopped_at"`
+	FileIndex       pgtype.Int4                       `json:"file_index"`
+	ImportStatus    NullCoachingRecordingImportStatus `json:"import_status"`
+	ImportAttempts  pgtype.Int4                       `json:"import_attempts"`
+	ImportError     pgtype.Text                       `json:"import_error"`
+	VideoID         pgtype.UUID                       `json:"video_id"`
+}
+
+// Every part of a booking's recording with the import of each of its files;
+// a part without files yet has a single row with no import.
+func (q *Queries) ListBookingRecordingImports(ctx context.Context, bookingID pgtype.UUID) ([]ListBookingRecordingImportsRow, error) {
+	rows, err := q.db.Query(ctx, listBookingRecordingImports, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookingRecordingImportsRow
+	for rows.Next() {
+		var i ListBookingRecordingImportsRow
+		if err := rows.Scan(
+			&i.RecordingID,
+			&i.PartNumber,
+			&i.RecordingStatus,
+			&i.RecordingError,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.FileIndex,
+			&i.ImportStatus,
+			&i.ImportAttempts,
+			&i.ImportError,
+			&i.VideoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingsAwaitingAttendance = `-- name: ListBookingsAwaitingAttendance :many
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings
+WHERE attendance_recorded_at IS NULL
+  AND is_cancelled = false
+  AND payment_status IS DISTINCT FROM 'pending'
+  AND scheduled_at + (duration_minutes * interval '1 minute')
+      + ($1::int * interval '1 second') <= NOW()
+ORDER BY scheduled_at
+LIMIT $2
+`
+
+type ListBookingsAwaitingAttendanceParams struct {
+	EndGraceSeconds int32 `json:"end_grace_seconds"`
+	LimitCount      int32 `json:"limit_count"`
+}
+
+// Sessions that ended more than end_grace_seconds ago without an attendance
+// outcome. Bookings still awaiting payment never took place.
+func (q *Queries) ListBookingsAwaitingAttendance(ctx context.Context, arg ListBookingsAwaitingAttendanceParams) ([]CoachingBooking, error) {
+	rows, err := q.db.Query(ctx, listBookingsAwaitingAttendance, arg.EndGraceSeconds, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBooking
+	for rows.Next() {
+		var i CoachingBooking
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookingsByExpertInRange = `-- name: ListBookingsByExpertInRange :many
+
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings
+WHERE expert_id = $1
+  AND scheduled_at >= $2
+  AND scheduled_at < $3
+  AND is_cancelled = false
+ORDER BY scheduled_at
+`
+
+type ListBookingsByExpertInRangeParams struct {
+	ExpertID      string             `json:"expert_id"`
+	ScheduledAt   pgtype.Timestamptz `json:"scheduled_at"`
+	ScheduledAt_2 pgtype.Timestamptz `json:"scheduled_at_2"`
+}
+
+// === Bookings ===
+func (q *Queries) ListBookingsByExpertInRange(ctx context.Context, arg ListBookingsByExpertInRangeParams) ([]CoachingBooking, error) {
+	rows, err := q.db.Query(ctx, listBookingsByExpertInRange, arg.ExpertID, arg.ScheduledAt, arg.ScheduledAt_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBooking
+	for rows.Next() {
+		var i CoachingBooking
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCalendarFeedBookings = `-- name: ListCalendarFeedBookings :many
+SELECT DISTINCT ON (cb.scheduled_at, e.entry_id)
+       cb.id, cb.class_id, cb.expert_id, cb.student_id, cb.group_id, cb.scheduled_at, cb.duration_minutes,
+       bool_and(cb.is_cancelled) OVER entry AS is_cancelled,
+       cb.created_at, max(cb.updated_at) OVER entry AS updated_at,
+       cst.name AS session_type_name, g.name AS group_name
+FROM coaching_bookings cb
+JOIN coaching_session_types cst ON cst.id = cb.session_type_id
+JOIN groups g ON g.id = cb.group_id
+CROSS JOIN LATERAL (
+    SELECT CASE WHEN cb.expert_id = $1 THEN COALESCE(cb.class_id, cb.id) ELSE cb.id END AS entry_id
+) e
+WHERE (cb.expert_id = $1 OR cb.student_id = $1)
+  AND cb.scheduled_at >= $2
+  AND cb.scheduled_at < $3
+WINDOW entry AS (PARTITION BY e.entry_id)
+ORDER BY cb.scheduled_at, e.entry_id, cb.class_seat NULLS FIRST
+`
+
+type ListCalendarFeedBookingsParams struct {
+	UserID string             `json:"user_id"`
+	FromAt pgtype.Timestamptz `json:"from_at"`
+	ToAt   pgtype.Timestamptz `json:"to_at"`
+}
+
+type ListCalendarFeedBookingsRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	ClassID         pgtype.UUID        `json:"class_id"`
+	ExpertID        string             `json:"expert_id"`
+	StudentID       string             `json:"student_id"`
+	GroupID         pgtype.UUID        `json:"group_id"`
+	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	IsCancelled     bool               `json:"is_cancelled"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	SessionTypeName string             `json:"session_type_name"`
+	GroupName       string             `json:"group_name"`
+}
+
+// Cancelled bookings stay in the window so subscribed calendars drop them.
+// The expert sees a class once, through its host booking: it is cancelled only
+// when every seat is, and a change to any seat updates it.
+func (q *Queries) ListCalendarFeedBookings(ctx context.Context, arg ListCalendarFeedBookingsParams) ([]ListCalendarFeedBookingsRow, error) {
+	rows, err := q.db.Query(ctx, listCalendarFeedBookings, arg.UserID, arg.FromAt, arg.ToAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCalendarFeedBookingsRow
+	for rows.Next() {
+		var i ListCalendarFeedBookingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ClassID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SessionTypeName,
+			&i.GroupName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClassAttendees = `-- name: ListClassAttendees :many
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings
+WHERE class_id = $1 AND is_cancelled = false
+ORDER BY class_seat
+`
+
+func (q *Queries) ListClassAttendees(ctx context.Context, classID pgtype.UUID) ([]CoachingBooking, error) {
+	rows, err := q.db.Query(ctx, listClassAttendees, classID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBooking
+	for rows.Next() {
+		var i CoachingBooking
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCreditGrants = `-- name: ListCreditGrants :many
+SELECT id, student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by, created_at, updated_at FROM coaching_credit_grants
+WHERE group_id = $1
+  AND (student_id = $2 OR expert_id = $2)
+  AND ($3::text = '' OR student_id = $3)
+ORDER BY created_at DESC
+`
+
+type ListCreditGrantsParams struct {
+	GroupID   pgtype.UUID `json:"group_id"`
+	UserID    string      `json:"user_id"`
+	StudentID string      `json:"student_id"`
+}
+
+// Grants the user holds as student or issued as expert, optionally narrowed
+// to one student.
+func (q *Queries) ListCreditGrants(ctx context.Context, arg ListCreditGrantsParams) ([]CoachingCreditGrant, error) {
+	rows, err := q.db.Query(ctx, listCreditGrants, arg.GroupID, arg.UserID, arg.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingCreditGrant
+	for rows.Next() {
+		var i CoachingCreditGrant
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.Quantity,
+			&i.Remaining,
+			&i.ExpiresAt,
+			&i.Note,
+			&i.GrantedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCreditLedger = `-- name: ListCreditLedger :many
+SELECT id, grant_id, student_id, expert_id, group_id, booking_id, kind, delta, actor_id, created_at FROM coaching_credit_ledger
+WHERE group_id = $1
+  AND (student_id = $2 OR expert_id = $2)
+  AND ($3::text = '' OR student_id = $3)
+ORDER BY created_at DESC
+LIMIT $4
+`
+
+type ListCreditLedgerParams struct {
+	GroupID   pgtype.UUID `json:"group_id"`
+	UserID    string      `json:"user_id"`
+	StudentID string      `json:"student_id"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+// Ledger movements visible to the user as student or expert, newest first.
+func (q *Queries) ListCreditLedger(ctx context.Context, arg ListCreditLedgerParams) ([]CoachingCreditLedger, error) {
+	rows, err := q.db.Query(ctx, listCreditLedger, arg.GroupID, arg.UserID, arg.StudentID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingCreditLedger
+	for rows.Next() {
+		var i CoachingCreditLedger
+		if err := rows.Scan(
+			&i.ID,
+			&i.GrantID,
+			&i.StudentID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.BookingID,
+			&i.Kind,
+			&i.Delta,
+			&i.ActorID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiredCreditGrants = `-- name: ListExpiredCreditGrants :many
+SELECT id, student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by, created_at, updated_at FROM coaching_credit_grants
+WHERE remaining > 0 AND expires_at <= NOW()
+ORDER BY expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+// Grants past their expiry that still hold credits, locked for expiry.
+func (q *Queries) ListExpiredCreditGrants(ctx context.Context, limit int32) ([]CoachingCreditGrant, error) {
+	rows, err := q.db.Query(ctx, listExpiredCreditGrants, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingCreditGrant
+	for rows.Next() {
+		var i CoachingCreditGrant
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.Quantity,
+			&i.Remaining,
+			&i.ExpiresAt,
+			&i.Note,
+			&i.GrantedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExternalBusyBlocks = `-- name: ListExternalBusyBlocks :many
+SELECT b.starts_at, b.ends_at
+FROM coaching_external_busy_blocks b
+JOIN coaching_external_calendars cal ON cal.id = b.calendar_id
+WHERE cal.expert_id = $1
+  AND b.starts_at < $2
+  AND b.ends_at > $3
+ORDER BY b.starts_at
+`
+
+type ListExternalBusyBlocksParams struct {
+	ExpertID string             `json:"expert_id"`
+	ToAt     pgtype.Timestamptz `json:"to_at"`
+	FromAt   pgtype.Timestamptz `json:"from_at"`
+}
+
+type ListExternalBusyBlocksRow struct {
+	StartsAt pgtype.Timestamptz `json:"starts_at"`
+	EndsAt   pgtype.Timestamptz `json:"ends_at"`
+}
+
+func (q *Queries) ListExternalBusyBlocks(ctx context.Context, arg ListExternalBusyBlocksParams) ([]ListExternalBusyBlocksRow, error) {
+	rows, err := q.db.Query(ctx, listExternalBusyBlocks, arg.ExpertID, arg.ToAt, arg.FromAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListExternalBusyBlocksRow
+	for rows.Next() {
+		var i ListExternalBusyBlocksRow
+		if err := rows.Scan(
+			&i.StartsAt,
+			&i.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExternalCalendarsByExpert = `-- name: ListExternalCalendarsByExpert :many
+SELECT id, expert_id, label, source_url, last_attempted_at, last_synced_at, last_error, created_at FROM coaching_external_calendars
+WHERE expert_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListExternalCalendarsByExpert(ctx context.Context, expertID string) ([]CoachingExternalCalendar, error) {
+	rows, err := q.db.Query(ctx, listExternalCalendarsByExpert, expertID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingExternalCalendar
+	for rows.Next() {
+		var i CoachingExternalCalendar
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.Label,
+			&i.SourceUrl,
+			&i.LastAttemptedAt,
+			&i.LastSyncedAt,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupBookings = `-- name: ListGroupBookings :many
+SELECT cb.id, cb.expert_id, cb.student_id, cb.group_id, cb.session_type_id, cb.scheduled_at, cb.duration_minutes, cb.is_cancelled, cb.cancellation_reason, cb.cancelled_by, cb.notes, cb.created_at, cb.updated_at, cb.recording_asset_id, cb.next_recording_part_number, cb.series_id, cb.buffer_before_minutes, cb.buffer_after_minutes, cb.price_cents, cb.currency, cb.payment_status, cb.payment_expires_at, cb.credit_grant_id, cb.class_id, cb.class_seat, cb.attendance_outcome, cb.attendance_recorded_at, cst.name AS session_type_name,
+       COALESCE(latest.recording_status, '')::varchar AS recording_status,
+       latest.video_id AS recording_video_id,
+       pending_reschedule.id AS reschedule_id,
+       pending_reschedule.proposed_by AS reschedule_proposed_by,
+       pending_reschedule.scheduled_at AS reschedule_scheduled_at
+FROM coaching_bookings cb
+JOIN coaching_session_types cst ON cst.id = cb.session_type_id
+LEFT JOIN LATERAL (
+    SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
+           COALESCE(stitch.video_id, recording_import.video_id) AS video_id
+    FROM coaching_booking_recordings recording
+    LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
+    LEFT JOIN coaching_recording_stitches stitch
+        ON stitch.booking_id = recording.booking_id AND stitch.status = 'ready'
+    WHERE recording.booking_id = COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = cb.class_id AND host.class_seat = 1), cb.id
+    )
+    ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
+    LIMIT 1
+) latest ON true
+LEFT JOIN coaching_booking_reschedules pending_reschedule
+    ON pending_reschedule.booking_id = cb.id AND pending_reschedule.status = 'pending'
+WHERE cb.group_id = $1
+ORDER BY cb.scheduled_at
 `
 
-type GetSessionTypeParams struct {
-	ID      pgtype.UUID `json:"id"`
-	GroupID pgtype.UUID `json:"group_id"`
+type ListGroupBookingsRow struct {
+	ID                      pgtype.UUID                   `json:"id"`
+	ExpertID                string                        `json:"expert_id"`
+	StudentID               string                        `json:"student_id"`
+	GroupID                 pgtype.UUID                   `json:"group_id"`
+	SessionTypeID           pgtype.UUID                   `json:"session_type_id"`
+	ScheduledAt             pgtype.Timestamptz            `json:"scheduled_at"`
+	DurationMinutes         int32                         `json:"duration_minutes"`
+	IsCancelled             bool                          `json:"is_cancelled"`
+	CancellationReason      pgtype.Text                   `json:"cancellation_reason"`
+	CancelledBy             pgtype.Text                   `json:"cancelled_by"`
+	Notes                   pgtype.Text                   `json:"notes"`
+	CreatedAt               pgtype.Timestamptz            `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz            `json:"updated_at"`
+	RecordingAssetID        pgtype.UUID                   `json:"recording_asset_id"`
+	NextRecordingPartNumber int32                         `json:"next_recording_part_number"`
+	SeriesID                pgtype.UUID                   `json:"series_id"`
+	BufferBeforeMinutes     int32                         `json:"buffer_before_minutes"`
+	BufferAfterMinutes      int32                         `json:"buffer_after_minutes"`
+	PriceCents              int32                         `json:"price_cents"`
+	Currency                pgtype.Text                   `json:"currency"`
+	PaymentStatus           NullCoachingPaymentStatus     `json:"payment_status"`
+	PaymentExpiresAt        pgtype.Timestamptz            `json:"payment_expires_at"`
+	CreditGrantID           pgtype.UUID                   `json:"credit_grant_id"`
+	ClassID                 pgtype.UUID                   `json:"class_id"`
+	ClassSeat               pgtype.Int4                   `json:"class_seat"`
+	AttendanceOutcome       NullCoachingAttendanceOutcome `json:"attendance_outcome"`
+	AttendanceRecordedAt    pgtype.Timestamptz            `json:"attendance_recorded_at"`
+	SessionTypeName         string                        `json:"session_type_name"`
+	RecordingStatus         string                        `json:"recording_status"`
+	RecordingVideoID        pgtype.UUID                   `json:"recording_video_id"`
+	RescheduleID            pgtype.UUID                   `json:"reschedule_id"`
+	RescheduleProposedBy    pgtype.Text                   `json:"reschedule_proposed_by"`
+	RescheduleScheduledAt   pgtype.Timestamptz            `json:"reschedule_scheduled_at"`
 }
 
-func (q *Queries) GetSessionType(ctx context.Context, arg GetSessionTypeParams) (CoachingSessionType, error) {
-	row := q.db.QueryRow(ctx, getSessionType, arg.ID, arg.GroupID)
-	var i CoachingSessionType
-	err := row.Scan(
-		&i.ID,
-		&i.ExpertID,
-		&i.GroupID,
-		&i.Name,
-		&i.Description,
-		&i.DurationMinutes,
-		&i.IsActive,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *Queries) ListGroupBookings(ctx context.Context, groupID pgtype.UUID) ([]ListGroupBookingsRow, error) {
+	rows, err := q.db.Query(ctx, listGroupBookings, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGroupBookingsRow
+	for rows.Next() {
+		var i ListGroupBookingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
+			&i.SessionTypeName,
+			&i.RecordingStatus,
+			&i.RecordingVideoID,
+			&i.RescheduleID,
+			&i.RescheduleProposedBy,
+			&i.RescheduleScheduledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUserTimezone = `-- name: GetUserTimezone :one
-
-SELECT timezone FROM user_preferences WHERE user_id = $1
+const listHomework = `-- name: ListHomework :many
+SELECT hw.id, hw.booking_id, hw.student_id, hw.expert_id, hw.group_id, hw.title, hw.description, hw.due_at, hw.completed_at, hw.created_at, hw.updated_at, b.scheduled_at AS session_scheduled_at, b.recording_asset_id
+FROM coaching_homework hw
+JOIN coaching_bookings b ON b.id = hw.booking_id
+WHERE hw.group_id = $1
+  AND (hw.student_id = $2 OR hw.expert_id = $2)
+  AND (NOT $3::boolean OR hw.completed_at IS NULL)
+ORDER BY hw.completed_at IS NOT NULL, hw.due_at NULLS LAST, hw.created_at
+LIMIT $4
 `
 
-// === Timezone ===
-func (q *Queries) GetUserTimezone(ctx context.Context, userID string) (string, error) {
-	row := q.db.QueryRow(ctx, getUserTimezone, userID)
-	var timezone string
-	err := row.Scan(&timezone)
-	return timezone, err
+type ListHomeworkParams struct {
+	GroupID   pgtype.UUID `json:"group_id"`
+	UserID    string      `json:"user_id"`
+	OpenOnly  bool        `json:"open_only"`
+	PageLimit int32       `json:"page_limit"`
 }
 
-const listActiveExpertsInGroup = `-- name: ListActiveExpertsInGroup :many
-SELECT DISTINCT expert_id FROM coaching_availability
-WHERE group_id = $1 AND is_active = true
-`
+type ListHomeworkRow struct {
+	ID                 pgtype.UUID        `json:"id"`
+	BookingID          pgtype.UUID        `json:"booking_id"`
+	StudentID          string             `json:"student_id"`
+	ExpertID           string             `json:"expert_id"`
+	GroupID            pgtype.UUID        `json:"group_id"`
+	Title              string             `json:"title"`
+	Description        string             `json:"description"`
+	DueAt              pgtype.Timestamptz `json:"due_at"`
+	CompletedAt        pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	SessionScheduledAt pgtype.Timestamptz `json:"session_scheduled_at"`
+	RecordingAssetID   pgtype.UUID        `json:"recording_asset_id"`
+}
 
-func (q *Queries) ListActiveExpertsInGroup(ctx context.Context, groupID pgtype.UUID) ([]string, error) {
-	rows, err := q.db.Query(ctx, listActiveExpertsInGroup, groupID)
+// Homework the user assigned or was assigned in the group, open items first
+// by due date, with the session it came from and that session's recording.
+func (q *Queries) ListHomework(ctx context.Context, arg ListHomeworkParams) ([]ListHomeworkRow, error) {
+	rows, err := q.db.Query(ctx, listHomework,
+		arg.GroupID,
+		arg.UserID,
+		arg.OpenOnly,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []ListHomeworkRow
 	for rows.Next() {
-		var expert_id string
-		if err := rows.Scan(&expert_id); err != nil {
+		var i ListHomeworkRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.StudentID,
+			&i.ExpertID,
+			&i.GroupID,
+			&i.Title,
+			&i.Description,
+			&i.DueAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SessionScheduledAt,
+			&i.RecordingAssetID,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, expert_id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -779,55 +3761,84 @@ func (q *Queries) ListActiveExpertsInGroup(ctx context.Context, groupID pgtype.U
 	return items, nil
 }
 
-const listAllMyBookings = `-- name: ListAllMyBookings :many
-SELECT cb.id, cb.expert_id, cb.student_id, cb.group_id, cb.session_type_id, cb.scheduled_at, cb.duration_minutes, cb.is_cancelled, cb.cancellation_reason, cb.cancelled_by, cb.notes, cb.created_at, cb.updated_at, cb.recording_asset_id, cb.next_recording_part_number, cst.name AS session_type_name,
+const listMyBookings = `-- name: ListMyBookings :many
+SELECT cb.id, cb.expert_id, cb.student_id, cb.group_id, cb.session_type_id, cb.scheduled_at, cb.duration_minutes, cb.is_cancelled, cb.cancellation_reason, cb.cancelled_by, cb.notes, cb.created_at, cb.updated_at, cb.recording_asset_id, cb.next_recording_part_number, cb.series_id, cb.buffer_before_minutes, cb.buffer_after_minutes, cb.price_cents, cb.currency, cb.payment_status, cb.payment_expires_at, cb.credit_grant_id, cb.class_id, cb.class_seat, cb.attendance_outcome, cb.attendance_recorded_at, cst.name AS session_type_name,
        COALESCE(latest.recording_status, '')::varchar AS recording_status,
-       latest.video_id AS recording_video_id
+       latest.video_id AS recording_video_id,
+       pending_reschedule.id AS reschedule_id,
+       pending_reschedule.proposed_by AS reschedule_proposed_by,
+       pending_reschedule.scheduled_at AS reschedule_scheduled_at
 FROM coaching_bookings cb
 JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 LEFT JOIN LATERAL (
     SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
-           recording_import.video_id
+           COALESCE(stitch.video_id, recording_import.video_id) AS video_id
     FROM coaching_booking_recordings recording
     LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
-    WHERE recording.booking_id = cb.id
+    LEFT JOIN coaching_recording_stitches stitch
+        ON stitch.booking_id = recording.booking_id AND stitch.status = 'ready'
+    WHERE recording.booking_id = COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = cb.class_id AND host.class_seat = 1), cb.id
+    )
     ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
     LIMIT 1
 ) latest ON true
-WHERE cb.expert_id = $1 OR cb.student_id = $1
-ORDER BY cb.scheduled_at ASC
+LEFT JOIN coaching_booking_reschedules pending_reschedule
+    ON pending_reschedule.booking_id = cb.id AND pending_reschedule.status = 'pending'
+WHERE (cb.expert_id = $1 OR cb.student_id = $1) AND cb.group_id = $2
+ORDER BY cb.scheduled_at DESC
 `
 
-type ListAllMyBookingsRow struct {
-	ID                      pgtype.UUID        `json:"id"`
-	ExpertID                string             `json:"expert_id"`
-	StudentID               string             `json:"student_id"`
-	GroupID                 pgtype.UUID        `json:"group_id"`
-	SessionTypeID           pgtype.UUID        `json:"session_type_id"`
-	ScheduledAt             pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes         int32              `json:"duration_minutes"`
-	IsCancelled             bool               `json:"is_cancelled"`
-	CancellationReason      pgtype.Text        `json:"cancellation_reason"`
-	CancelledBy             pgtype.Text        `json:"cancelled_by"`
-	Notes                   pgtype.Text        `json:"notes"`
-	CreatedAt               pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
-	RecordingAssetID        pgtype.UUID        `json:"recording_asset_id"`
-	NextRecordingPartNumber int32              `json:"next_recording_part_number"`
-	SessionTypeName         string             `json:"session_type_name"`
-	RecordingStatus         string             `json:"recording_status"`
-	RecordingVideoID        pgtype.UUID        `json:"recording_video_id"`
+type ListMyBookingsParams struct {
+	ExpertID string      `json:"expert_id"`
+	GroupID  pgtype.UUID `json:"group_id"`
 }
 
-func (q *Queries) ListAllMyBookings(ctx context.Context, expertID string) ([]ListAllMyBookingsRow, error) {
-	rows, err := q.db.Query(ctx, listAllMyBookings, expertID)
+type ListMyBookingsRow struct {
+	ID                      pgtype.UUID                   `json:"id"`
+	ExpertID                string                        `json:"expert_id"`
+	StudentID               string                        `json:"student_id"`
+	GroupID                 pgtype.UUID                   `json:"group_id"`
+	SessionTypeID           pgtype.UUID                   `json:"session_type_id"`
+	ScheduledAt             pgtype.Timestamptz            `json:"scheduled_at"`
+	DurationMinutes         int32                         `json:"duration_minutes"`
+	IsCancelled             bool                          `json:"is_cancelled"`
+	CancellationReason      pgtype.Text                   `json:"cancellation_reason"`
+	CancelledBy             pgtype.Text                   `json:"cancelled_by"`
+	Notes                   pgtype.Text                   `json:"notes"`
+	CreatedAt               pgtype.Timestamptz            `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz            `json:"updated_at"`
+	RecordingAssetID        pgtype.UUID                   `json:"recording_asset_id"`
+	NextRecordingPartNumber int32                         `json:"next_recording_part_number"`
+	SeriesID                pgtype.UUID                   `json:"series_id"`
+	BufferBeforeMinutes     int32                         `json:"buffer_before_minutes"`
+	BufferAfterMinutes      int32                         `json:"buffer_after_minutes"`
+	PriceCents              int32                         `json:"price_cents"`
+	Currency                pgtype.Text                   `json:"currency"`
+	PaymentStatus           NullCoachingPaymentStatus     `json:"payment_status"`
+	PaymentExpiresAt        pgtype.Timestamptz            `json:"payment_expires_at"`
+	CreditGrantID           pgtype.UUID                   `json:"credit_grant_id"`
+	ClassID                 pgtype.UUID                   `json:"class_id"`
+	ClassSeat               pgtype.Int4                   `json:"class_seat"`
+	AttendanceOutcome       NullCoachingAttendanceOutcome `json:"attendance_outcome"`
+	AttendanceRecordedAt    pgtype.Timestamptz            `json:"attendance_recorded_at"`
+	SessionTypeName         string                        `json:"session_type_name"`
+	RecordingStatus         string                        `json:"recording_status"`
+	RecordingVideoID        pgtype.UUID                   `json:"recording_video_id"`
+	RescheduleID            pgtype.UUID                   `json:"reschedule_id"`
+	RescheduleProposedBy    pgtype.Text                   `json:"reschedule_proposed_by"`
+	RescheduleScheduledAt   pgtype.Timestamptz            `json:"reschedule_scheduled_at"`
+}
+
+func (q *Queries) ListMyBookings(ctx context.Context, arg ListMyBookingsParams) ([]ListMyBookingsRow, error) {
+	rows, err := q.db.Query(ctx, listMyBookings, arg.ExpertID, arg.GroupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListAllMyBookingsRow
+	var items []ListMyBookingsRow
 	for rows.Next() {
-		var i ListAllMyBookingsRow
+		var i ListMyBookingsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.ExpertID,
@@ -844,9 +3855,24 @@ func (q *Queries) ListAllMyBookings(ctx context.Context, expertID string) ([]Lis
 			&i.UpdatedAt,
 			&i.RecordingAssetID,
 			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
 			&i.SessionTypeName,
 			&i.RecordingStatus,
 			&i.RecordingVideoID,
+			&i.RescheduleID,
+			&i.RescheduleProposedBy,
+			&i.RescheduleScheduledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -858,36 +3884,66 @@ func (q *Queries) ListAllMyBookings(ctx context.Context, expertID string) ([]Lis
 	return items, nil
 }
 
-const listAvailabilityByExpertGroup = `-- name: ListAvailabilityByExpertGroup :many
-SELECT id, expert_id, group_id, day_of_week, start_time, end_time, is_active, created_at, updated_at FROM coaching_availability
-WHERE expert_id = $1 AND group_id = $2 AND is_active = true
-ORDER BY day_of_week, start_time
+const listMyWaitlistEntries = `-- name: ListMyWaitlistEntries :many
+SELECT e.id, e.student_id, e.expert_id, e.group_id, e.session_type_id,
+       e.preferred_weekdays, e.created_at,
+       st.name AS session_type_name,
+       hold.id AS hold_id,
+       hold.scheduled_at AS hold_scheduled_at,
+       hold.duration_minutes AS hold_duration_minutes,
+       hold.expires_at AS hold_expires_at
+FROM coaching_waitlist_entries e
+JOIN coaching_session_types st ON st.id = e.session_type_id
+LEFT JOIN coaching_waitlist_holds hold
+    ON hold.entry_id = e.id AND hold.status = 'offered' AND hold.expires_at > NOW()
+WHERE e.student_id = $1 AND e.group_id = $2 AND e.booked_at IS NULL
+ORDER BY e.created_at
 `
 
-type ListAvailabilityByExpertGroupParams struct {
-	ExpertID string      `json:"expert_id"`
-	GroupID  pgtype.UUID `json:"group_id"`
+type ListMyWaitlistEntriesParams struct {
+	StudentID string      `json:"student_id"`
+	GroupID   pgtype.UUID `json:"group_id"`
 }
 
-func (q *Queries) ListAvailabilityByExpertGroup(ctx context.Context, arg ListAvailabilityByExpertGroupParams) ([]CoachingAvailability, error) {
-	rows, err := q.db.Query(ctx, listAvailabilityByExpertGroup, arg.ExpertID, arg.GroupID)
+type ListMyWaitlistEntriesRow struct {
+	ID                  pgtype.UUID        `json:"id"`
+	StudentID           string             `json:"student_id"`
+	ExpertID            string             `json:"expert_id"`
+	GroupID             pgtype.UUID        `json:"group_id"`
+	SessionTypeID       pgtype.UUID        `json:"session_type_id"`
+	PreferredWeekdays   []int16            `json:"preferred_weekdays"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	SessionTypeName     string             `json:"session_type_name"`
+	HoldID              pgtype.UUID        `json:"hold_id"`
+	HoldScheduledAt     pgtype.Timestamptz `json:"hold_scheduled_at"`
+	HoldDurationMinutes pgtype.Int4        `json:"hold_duration_minutes"`
+	HoldExpiresAt       pgtype.Timestamptz `json:"hold_expires_at"`
+}
+
+// The student's waiting entries in the group with the slot currently held
+// for them, if any.
+func (q *Queries) ListMyWaitlistEntries(ctx context.Context, arg ListMyWaitlistEntriesParams) ([]ListMyWaitlistEntriesRow, error) {
+	rows, err := q.db.Query(ctx, listMyWaitlistEntries, arg.StudentID, arg.GroupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingAvailability
+	var items []ListMyWaitlistEntriesRow
 	for rows.Next() {
-		var i CoachingAvailability
+		var i ListMyWaitlistEntriesRow
 		if err := rows.Scan(
 			&i.ID,
+			&i.StudentID,
 			&i.ExpertID,
 			&i.GroupID,
-			&i.DayOfWeek,
-			&i.StartTime,
-			&i.EndTime,
-			&i.IsActive,
+			&i.SessionTypeID,
+			&i.PreferredWeekdays,
 			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.SessionTypeName,
+			&i.HoldID,
+			&i.HoldScheduledAt,
+			&i.HoldDurationMinutes,
+			&i.HoldExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -899,37 +3955,57 @@ func (q *Queries) ListAvailabilityByExpertGroup(ctx context.Context, arg ListAva
 	return items, nil
 }
 
-const listAvailabilityByExpertGroupDay = `-- name: ListAvailabilityByExpertGroupDay :many
-SELECT id, expert_id, group_id, day_of_week, start_time, end_time, is_active, created_at, updated_at FROM coaching_availability
-WHERE expert_id = $1 AND group_id = $2 AND day_of_week = $3 AND is_active = true
-ORDER BY start_time
+const listOpenClasses = `-- name: ListOpenClasses :many
+SELECT c.id, c.expert_id, c.group_id, c.session_type_id, c.scheduled_at, c.duration_minutes, c.capacity, c.created_at, COUNT(cb.id) AS taken
+FROM coaching_classes c
+JOIN coaching_bookings cb ON cb.class_id = c.id AND cb.is_cancelled = false
+WHERE c.session_type_id = $1
+  AND c.scheduled_at >= $2
+  AND c.scheduled_at < $3
+GROUP BY c.id
+HAVING COUNT(cb.id) < c.capacity
+ORDER BY c.scheduled_at
 `
 
-type ListAvailabilityByExpertGroupDayParams struct {
-	ExpertID  string      `json:"expert_id"`
-	GroupID   pgtype.UUID `json:"group_id"`
-	DayOfWeek int16       `json:"day_of_week"`
+type ListOpenClassesParams struct {
+	SessionTypeID pgtype.UUID        `json:"session_type_id"`
+	FromTime      pgtype.Timestamptz `json:"from_time"`
+	ToTime        pgtype.Timestamptz `json:"to_time"`
 }
 
-func (q *Queries) ListAvailabilityByExpertGroupDay(ctx context.Context, arg ListAvailabilityByExpertGroupDayParams) ([]CoachingAvailability, error) {
-	rows, err := q.db.Query(ctx, listAvailabilityByExpertGroupDay, arg.ExpertID, arg.GroupID, arg.DayOfWeek)
+type ListOpenClassesRow struct {
+	ID              pgtype.UUID        `json:"id"`
+	ExpertID        string             `json:"expert_id"`
+	GroupID         pgtype.UUID        `json:"group_id"`
+	SessionTypeID   pgtype.UUID        `json:"session_type_id"`
+	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Capacity        int32              `json:"capacity"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	Taken           int64              `json:"taken"`
+}
+
+// Classes of the session type starting in the range that hold at least one
+// active booking and still have a seat left, with the seats taken.
+func (q *Queries) ListOpenClasses(ctx context.Context, arg ListOpenClassesParams) ([]ListOpenClassesRow, error) {
+	rows, err := q.db.Query(ctx, listOpenClasses, arg.SessionTypeID, arg.FromTime, arg.ToTime)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingAvailability
+	var items []ListOpenClassesRow
 	for rows.Next() {
-		var i CoachingAvailability
+		var i ListOpenClassesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.ExpertID,
 			&i.GroupID,
-			&i.DayOfWeek,
-			&i.StartTime,
-			&i.EndTime,
-			&i.IsActive,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.Capacity,
 			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Taken,
 		); err != nil {
 			return nil, err
 		}
@@ -941,31 +4017,142 @@ func (q *Queries) ListAvailabilityByExpertGroupDay(ctx context.Context, arg List
 	return items, nil
 }
 
-const listAvailabilityByGroup = `-- name: ListAvailabilityByGroup :many
-SELECT id, expert_id, group_id, day_of_week, start_time, end_time, is_active, created_at, updated_at FROM coaching_availability
-WHERE group_id = $1 AND is_active = true
-ORDER BY expert_id, day_of_week, start_time
+const listPaymentsAwaitingRefund = `-- name: ListPaymentsAwaitingRefund :many
+SELECT p.booking_id, p.provider, p.checkout_session_id, p.amount_cents, p.currency, p.provider_payment_id, p.paid_at, p.refund_id, p.refunded_at, p.created_at FROM coaching_payments p
+JOIN coaching_bookings b ON b.id = p.booking_id
+WHERE b.payment_status = 'paid' AND b.is_cancelled = true
+ORDER BY b.updated_at
+LIMIT $1
 `
 
-func (q *Queries) ListAvailabilityByGroup(ctx context.Context, groupID pgtype.UUID) ([]CoachingAvailability, error) {
-	rows, err := q.db.Query(ctx, listAvailabilityByGroup, groupID)
+// Payments of cancelled bookings that are still paid, oldest first.
+func (q *Queries) ListPaymentsAwaitingRefund(ctx context.Context, limit int32) ([]CoachingPayment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsAwaitingRefund, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingAvailability
+	var items []CoachingPayment
 	for rows.Next() {
-		var i CoachingAvailability
+		var i CoachingPayment
+		if err := rows.Scan(
+			&i.BookingID,
+			&i.Provider,
+			&i.CheckoutSessionID,
+			&i.AmountCents,
+			&i.Currency,
+			&i.ProviderPaymentID,
+			&i.PaidAt,
+			&i.RefundID,
+			&i.RefundedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingReminders = `-- name: ListPendingReminders :many
+SELECT r.id, r.booking_id, r.remind_at,
+       b.expert_id, b.student_id, b.group_id, b.scheduled_at, b.duration_minutes, b.is_cancelled,
+       r.homework_id, hw.title AS homework_title, hw.due_at AS homework_due_at,
+       hw.completed_at AS homework_completed_at
+FROM coaching_booking_reminders r
+JOIN coaching_bookings b ON b.id = r.booking_id
+LEFT JOIN coaching_homework hw ON hw.id = r.homework_id
+WHERE r.sent_at IS NULL
+  AND r.remind_at <= NOW()
+ORDER BY r.remind_at
+LIMIT 100
+`
+
+type ListPendingRemindersRow struct {
+	ID                  pgtype.UUID        `json:"id"`
+	BookingID           pgtype.UUID        `json:"booking_id"`
+	RemindAt            pgtype.Timestamptz `json:"remind_at"`
+	ExpertID            string             `json:"expert_id"`
+	StudentID           string             `json:"student_id"`
+	GroupID             pgtype.UUID        `json:"group_id"`
+	ScheduledAt         pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes     int32              `json:"duration_minutes"`
+	IsCancelled         bool               `json:"is_cancelled"`
+	HomeworkID          pgtype.UUID        `json:"homework_id"`
+	HomeworkTitle       pgtype.Text        `json:"homework_title"`
+	HomeworkDueAt       pgtype.Timestamptz `json:"homework_due_at"`
+	HomeworkCompletedAt pgtype.Timestamptz `json:"homework_completed_at"`
+}
+
+// Session reminders, and homework due-date reminders where homework_id is set.
+func (q *Queries) ListPendingReminders(ctx context.Context) ([]ListPendingRemindersRow, error) {
+	rows, err := q.db.Query(ctx, listPendingReminders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingRemindersRow
+	for rows.Next() {
+		var i ListPendingRemindersRow
 		if err := rows.Scan(
 			&i.ID,
+			&i.BookingID,
+			&i.RemindAt,
 			&i.ExpertID,
+			&i.StudentID,
 			&i.GroupID,
-			&i.DayOfWeek,
-			&i.StartTime,
-			&i.EndTime,
-			&i.IsActive,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.HomeworkID,
+			&i.HomeworkTitle,
+			&i.HomeworkDueAt,
+			&i.HomeworkCompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecordingAssetMessages = `-- name: ListRecordingAssetMessages :many
+SELECT m.id, m.booking_id, m.author_id, m.author_role, m.body, m.links, m.created_at FROM coaching_booking_messages m
+WHERE m.booking_id IN (
+    SELECT COALESCE(
+        (SELECT host.id FROM coaching_bookings host WHERE host.class_id = b.class_id AND host.class_seat = 1), b.id
+    )
+    FROM coaching_bookings b
+    WHERE b.recording_asset_id = $1
+)
+ORDER BY m.created_at, m.id
+`
+
+// Chat of the call the asset recorded. Messages live on the call's host
+// booking, which for a class is its first seat.
+func (q *Queries) ListRecordingAssetMessages(ctx context.Context, recordingAssetID pgtype.UUID) ([]CoachingBookingMessage, error) {
+	rows, err := q.db.Query(ctx, listRecordingAssetMessages, recordingAssetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoachingBookingMessage
+	for rows.Next() {
+		var i CoachingBookingMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookingID,
+			&i.AuthorID,
+			&i.AuthorRole,
+			&i.Body,
+			&i.Links,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -977,35 +4164,54 @@ func (q *Queries) ListAvailabilityByGroup(ctx context.Context, groupID pgtype.UU
 	return items, nil
 }
 
-const listBlockedSlots = `-- name: ListBlockedSlots :many
-SELECT id, expert_id, blocked_date, start_time, end_time, reason, created_at FROM coaching_blocked_slots
-WHERE expert_id = $1 AND blocked_date >= $2 AND blocked_date <= $3
-ORDER BY blocked_date, start_time
+const listRecordingPartsReadyToStop = `-- name: ListRecordingPartsReadyToStop :many
+SELECT recording.booking_id, recording.status, recording.provider_resource_id, recording.provider_recording_id, recording.provider_uid, recording.output_prefix, recording.started_at, recording.stopped_at, recording.error, recording.created_at, recording.updated_at, recording.id, recording.part_number, recording.provider, recording.renderer_token_hash, recording.renderer_token_expires_at, recording.empty_since_at
+FROM coaching_booking_recordings recording
+JOIN coaching_bookings booking ON booking.id = recording.booking_id
+WHERE recording.status IN ('starting', 'started', 'stopping')
+  AND (
+      recording.empty_since_at <= NOW() - ($1::int * interval '1 second')
+      OR booking.scheduled_at + (booking.duration_minutes * interval '1 minute')
+         + ($2::int * interval '1 second') <= NOW()
+      OR (recording.status = 'starting' AND recording.updated_at <= NOW() - interval '1 minute')
+  )
+ORDER BY recording.updated_at
+LIMIT $3
 `
 
-type ListBlockedSlotsParams struct {
-	ExpertID string      `json:"expert_id"`
-	FromDate pgtype.Date `json:"from_date"`
-	ToDate   pgtype.Date `json:"to_date"`
+type ListRecordingPartsReadyToStopParams struct {
+	EmptyGraceSeconds int32 `json:"empty_grace_seconds"`
+	EndGraceSeconds   int32 `json:"end_grace_seconds"`
+	LimitCount        int32 `json:"limit_count"`
 }
 
-func (q *Queries) ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsParams) ([]CoachingBlockedSlot, error) {
-	rows, err := q.db.Query(ctx, listBlockedSlots, arg.ExpertID, arg.FromDate, arg.ToDate)
+func (q *Queries) ListRecordingPartsReadyToStop(ctx context.Context, arg ListRecordingPartsReadyToStopParams) ([]CoachingBookingRecording, error) {
+	rows, err := q.db.Query(ctx, listRecordingPartsReadyToStop, arg.EmptyGraceSeconds, arg.EndGraceSeconds, arg.LimitCount)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingBlockedSlot
+	var items []CoachingBookingRecording
 	for rows.Next() {
-		var i CoachingBlockedSlot
+		var i CoachingBookingRecording
 		if err := rows.Scan(
-			&i.ID,
-			&i.ExpertID,
-			&i.BlockedDate,
-			&i.StartTime,
-			&i.EndTime,
-			&i.Reason,
+			&i.BookingID,
+			&i.Status,
+			&i.ProviderResourceID,
+			&i.ProviderRecordingID,
+			&i.ProviderUid,
+			&i.OutputPrefix,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.Error,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ID,
+			&i.PartNumber,
+			&i.Provider,
+			&i.RendererTokenHash,
+			&i.RendererTokenExpiresAt,
+			&i.EmptySinceAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1017,48 +4223,40 @@ func (q *Queries) ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsPara
 	return items, nil
 }
 
-const listBookingsByExpertInRange = `-- name: ListBookingsByExpertInRange :many
-
-SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number FROM coaching_bookings
-WHERE expert_id = $1
-  AND scheduled_at >= $2
-  AND scheduled_at < $3
-  AND is_cancelled = false
-ORDER BY scheduled_at
+const listRecordingStitchInputs = `-- name: ListRecordingStitchInputs :many
+SELECT recording.part_number, recording_import.file_index, recording_import.gcs_object_name,
+       video.id AS video_id, video.duration_seconds
+FROM coaching_recording_imports recording_import
+JOIN coaching_booking_recordings recording ON recording.id = recording_import.recording_id
+JOIN videos video ON video.id = recording_import.video_id AND video.deleted_at IS NULL
+WHERE recording.booking_id = $1 AND recording_import.status = 'ready'
+ORDER BY recording.part_number, recording_import.file_index
 `
 
-type ListBookingsByExpertInRangeParams struct {
-	ExpertID      string             `json:"expert_id"`
-	ScheduledAt   pgtype.Timestamptz `json:"scheduled_at"`
-	ScheduledAt_2 pgtype.Timestamptz `json:"scheduled_at_2"`
+type ListRecordingStitchInputsRow struct {
+	PartNumber      int32         `json:"part_number"`
+	FileIndex       int32         `json:"file_index"`
+	GcsObjectName   pgtype.Text   `json:"gcs_object_name"`
+	VideoID         pgtype.UUID   `json:"video_id"`
+	DurationSeconds pgtype.Float8 `json:"duration_seconds"`
 }
 
-// === Bookings ===
-func (q *Queries) ListBookingsByExpertInRange(ctx context.Context, arg ListBookingsByExpertInRangeParams) ([]CoachingBooking, error) {
-	rows, err := q.db.Query(ctx, listBookingsByExpertInRange, arg.ExpertID, arg.ScheduledAt, arg.ScheduledAt_2)
+// The part videos a stitch joins, in recording order.
+func (q *Queries) ListRecordingStitchInputs(ctx context.Context, bookingID pgtype.UUID) ([]ListRecordingStitchInputsRow, error) {
+	rows, err := q.db.Query(ctx, listRecordingStitchInputs, bookingID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingBooking
+	var items []ListRecordingStitchInputsRow
 	for rows.Next() {
-		var i CoachingBooking
+		var i ListRecordingStitchInputsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.ExpertID,
-			&i.StudentID,
-			&i.GroupID,
-			&i.SessionTypeID,
-			&i.ScheduledAt,
-			&i.DurationMinutes,
-			&i.IsCancelled,
-			&i.CancellationReason,
-			&i.CancelledBy,
-			&i.Notes,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.RecordingAssetID,
-			&i.NextRecordingPartNumber,
+			&i.PartNumber,
+			&i.FileIndex,
+			&i.GcsObjectName,
+			&i.VideoID,
+			&i.DurationSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -1070,74 +4268,48 @@ func (q *Queries) ListBookingsByExpertInRange(ctx context.Context, arg ListBooki
 	return items, nil
 }
 
-const listGroupBookings = `-- name: ListGroupBookings :many
-SELECT cb.id, cb.expert_id, cb.student_id, cb.group_id, cb.session_type_id, cb.scheduled_at, cb.duration_minutes, cb.is_cancelled, cb.cancellation_reason, cb.cancelled_by, cb.notes, cb.created_at, cb.updated_at, cb.recording_asset_id, cb.next_recording_part_number, cst.name AS session_type_name,
-       COALESCE(latest.recording_status, '')::varchar AS recording_status,
-       latest.video_id AS recording_video_id
-FROM coaching_bookings cb
-JOIN coaching_session_types cst ON cst.id = cb.session_type_id
-LEFT JOIN LATERAL (
-    SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
-           recording_import.video_id
-    FROM coaching_booking_recordings recording
-    LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
-    WHERE recording.booking_id = cb.id
-    ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
-    LIMIT 1
-) latest ON true
-WHERE cb.group_id = $1
-ORDER BY cb.scheduled_at
+const listSessionTypesByExpertGroup = `-- name: ListSessionTypesByExpertGroup :many
+SELECT id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at, buffer_before_minutes, buffer_after_minutes, max_sessions_per_day, booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes, price_cents, currency, requires_credit, capacity, no_show_credit_policy, notify_owner_on_no_show FROM coaching_session_types
+WHERE expert_id = $1 AND group_id = $2 AND is_active = true
+ORDER BY duration_minutes
 `
 
-type ListGroupBookingsRow struct {
-	ID                      pgtype.UUID        `json:"id"`
-	ExpertID                string             `json:"expert_id"`
-	StudentID               string             `json:"student_id"`
-	GroupID                 pgtype.UUID        `json:"group_id"`
-	SessionTypeID           pgtype.UUID        `json:"session_type_id"`
-	ScheduledAt             pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes         int32              `json:"duration_minutes"`
-	IsCancelled             bool               `json:"is_cancelled"`
-	CancellationReason      pgtype.Text        `json:"cancellation_reason"`
-	CancelledBy             pgtype.Text        `json:"cancelled_by"`
-	Notes                   pgtype.Text        `json:"notes"`
-	CreatedAt               pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
-	RecordingAssetID        pgtype.UUID        `json:"recording_asset_id"`
-	NextRecordingPartNumber int32              `json:"next_recording_part_number"`
-	SessionTypeName         string             `json:"session_type_name"`
-	RecordingStatus         string             `json:"recording_status"`
-	RecordingVideoID        pgtype.UUID        `json:"recording_video_id"`
+type ListSessionTypesByExpertGroupParams struct {
+	ExpertID string      `json:"expert_id"`
+	GroupID  pgtype.UUID `json:"group_id"`
 }
 
-func (q *Queries) ListGroupBookings(ctx context.Context, groupID pgtype.UUID) ([]ListGroupBookingsRow, error) {
-	rows, err := q.db.Query(ctx, listGroupBookings, groupID)
+func (q *Queries) ListSessionTypesByExpertGroup(ctx context.Context, arg ListSessionTypesByExpertGroupParams) ([]CoachingSessionType, error) {
+	rows, err := q.db.Query(ctx, listSessionTypesByExpertGroup, arg.ExpertID, arg.GroupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListGroupBookingsRow
+	var items []CoachingSessionType
 	for rows.Next() {
-		var i ListGroupBookingsRow
+		var i CoachingSessionType
 		if err := rows.Scan(
 			&i.ID,
 			&i.ExpertID,
-			&i.StudentID,
 			&i.GroupID,
-			&i.SessionTypeID,
-			&i.ScheduledAt,
+			&i.Name,
+			&i.Description,
 			&i.DurationMinutes,
-			&i.IsCancelled,
-			&i.CancellationReason,
-			&i.CancelledBy,
-			&i.Notes,
+			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.RecordingAssetID,
-			&i.NextRecordingPartNumber,
-			&i.SessionTypeName,
-			&i.RecordingStatus,
-			&i.RecordingVideoID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.MaxSessionsPerDay,
+			&i.BookingHorizonDays,
+			&i.MinBookingNoticeMinutes,
+			&i.CancellationNoticeMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.RequiresCredit,
+			&i.Capacity,
+			&i.NoShowCreditPolicy,
+			&i.NotifyOwnerOnNoShow,
 		); err != nil {
 			return nil, err
 		}
@@ -1149,79 +4321,43 @@ func (q *Queries) ListGroupBookings(ctx context.Context, groupID pgtype.UUID) ([
 	return items, nil
 }
 
-const listMyBookings = `-- name: ListMyBookings :many
-SELECT cb.id, cb.expert_id, cb.student_id, cb.group_id, cb.session_type_id, cb.scheduled_at, cb.duration_minutes, cb.is_cancelled, cb.cancellation_reason, cb.cancelled_by, cb.notes, cb.created_at, cb.updated_at, cb.recording_asset_id, cb.next_recording_part_number, cst.name AS session_type_name,
-       COALESCE(latest.recording_status, '')::varchar AS recording_status,
-       latest.video_id AS recording_video_id
-FROM coaching_bookings cb
-JOIN coaching_session_types cst ON cst.id = cb.session_type_id
-LEFT JOIN LATERAL (
-    SELECT COALESCE(recording_import.status::text, recording.status::text) AS recording_status,
-           recording_import.video_id
-    FROM coaching_booking_recordings recording
-    LEFT JOIN coaching_recording_imports recording_import ON recording_import.recording_id = recording.id
-    WHERE recording.booking_id = cb.id
-    ORDER BY recording.part_number DESC, recording_import.file_index DESC NULLS LAST
-    LIMIT 1
-) latest ON true
-WHERE (cb.expert_id = $1 OR cb.student_id = $1) AND cb.group_id = $2
-ORDER BY cb.scheduled_at DESC
+const listSessionTypesByGroup = `-- name: ListSessionTypesByGroup :many
+SELECT id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at, buffer_before_minutes, buffer_after_minutes, max_sessions_per_day, booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes, price_cents, currency, requires_credit, capacity, no_show_credit_policy, notify_owner_on_no_show FROM coaching_session_types
+WHERE group_id = $1 AND is_active = true
+ORDER BY expert_id, duration_minutes
 `
 
-type ListMyBookingsParams struct {
-	ExpertID string      `json:"expert_id"`
-	GroupID  pgtype.UUID `json:"group_id"`
-}
-
-type ListMyBookingsRow struct {
-	ID                      pgtype.UUID        `json:"id"`
-	ExpertID                string             `json:"expert_id"`
-	StudentID               string             `json:"student_id"`
-	GroupID                 pgtype.UUID        `json:"group_id"`
-	SessionTypeID           pgtype.UUID        `json:"session_type_id"`
-	ScheduledAt             pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes         int32              `json:"duration_minutes"`
-	IsCancelled             bool               `json:"is_cancelled"`
-	CancellationReason      pgtype.Text        `json:"cancellation_reason"`
-	CancelledBy             pgtype.Text        `json:"cancelled_by"`
-	Notes                   pgtype.Text        `json:"notes"`
-	CreatedAt               pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
-	RecordingAssetID        pgtype.UUID        `json:"recording_asset_id"`
-	NextRecordingPartNumber int32              `json:"next_recording_part_number"`
-	SessionTypeName         string             `json:"session_type_name"`
-	RecordingStatus         string             `json:"recording_status"`
-	RecordingVideoID        pgtype.UUID        `json:"recording_video_id"`
-}
-
-func (q *Queries) ListMyBookings(ctx context.Context, arg ListMyBookingsParams) ([]ListMyBookingsRow, error) {
-	rows, err := q.db.Query(ctx, listMyBookings, arg.ExpertID, arg.GroupID)
+func (q *Queries) ListSessionTypesByGroup(ctx context.Context, groupID pgtype.UUID) ([]CoachingSessionType, error) {
+	rows, err := q.db.Query(ctx, listSessionTypesByGroup, groupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListMyBookingsRow
+	var items []CoachingSessionType
 	for rows.Next() {
-		var i ListMyBookingsRow
+		var i CoachingSessionType
 		if err := rows.Scan(
 			&i.ID,
 			&i.ExpertID,
-			&i.StudentID,
 			&i.GroupID,
-			&i.SessionTypeID,
-			&i.ScheduledAt,
+			&i.Name,
+			&i.Description,
 			&i.DurationMinutes,
-			&i.IsCancelled,
-			&i.CancellationReason,
-			&i.CancelledBy,
-			&i.Notes,
+			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.RecordingAssetID,
-			&i.NextRecordingPartNumber,
-			&i.SessionTypeName,
-			&i.RecordingStatus,
-			&i.RecordingVideoID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.MaxSessionsPerDay,
+			&i.BookingHorizonDays,
+			&i.MinBookingNoticeMinutes,
+			&i.CancellationNoticeMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.RequiresCredit,
+			&i.Capacity,
+			&i.NoShowCreditPolicy,
+			&i.NotifyOwnerOnNoShow,
 		); err != nil {
 			return nil, err
 		}
@@ -1233,48 +4369,41 @@ func (q *Queries) ListMyBookings(ctx context.Context, arg ListMyBookingsParams)
 	return items, nil
 }
 
-const listPendingReminders = `-- name: ListPendingReminders :many
-SELECT r.id, r.booking_id, r.remind_at,
-       b.expert_id, b.student_id, b.group_id, b.scheduled_at, b.duration_minutes, b.is_cancelled
-FROM coaching_booking_reminders r
-JOIN coaching_bookings b ON b.id = r.booking_id
-WHERE r.sent_at IS NULL
-  AND r.remind_at <= NOW()
-ORDER BY r.remind_at
-LIMIT 100
+const listStoppedRecordingPartsForDiscovery = `-- name: ListStoppedRecordingPartsForDiscovery :many
+SELECT booking_id, status, provider_resource_id, provider_recording_id, provider_uid, output_prefix, started_at, stopped_at, error, created_at, updated_at, id, part_number, provider, renderer_token_hash, renderer_token_expires_at, empty_since_at FROM coaching_booking_recordings
+WHERE status = 'stopped'
+  AND stopped_at >= NOW() - interval '7 days'
+ORDER BY stopped_at DESC
+LIMIT $1
 `
 
-type ListPendingRemindersRow struct {
-	ID              pgtype.UUID        `json:"id"`
-	BookingID       pgtype.UUID        `json:"booking_id"`
-	RemindAt        pgtype.Timestamptz `json:"remind_at"`
-	ExpertID        string             `json:"expert_id"`
-	StudentID       string             `json:"student_id"`
-	GroupID         pgtype.UUID        `json:"group_id"`
-	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes int32              `json:"duration_minutes"`
-	IsCancelled     bool               `json:"is_cancelled"`
-}
-
-func (q *Queries) ListPendingReminders(ctx context.Context) ([]ListPendingRemindersRow, error) {
-	rows, err := q.db.Query(ctx, listPendingReminders)
+func (q *Queries) ListStoppedRecordingPartsForDiscovery(ctx context.Context, limit int32) ([]CoachingBookingRecording, error) {
+	rows, err := q.db.Query(ctx, listStoppedRecordingPartsForDiscovery, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPendingRemindersRow
+	var items []CoachingBookingRecording
 	for rows.Next() {
-		var i ListPendingRemindersRow
+		var i CoachingBookingRecording
 		if err := rows.Scan(
-			&i.ID,
 			&i.BookingID,
-			&i.RemindAt,
-			&i.ExpertID,
-			&i.StudentID,
-			&i.GroupID,
-			&i.ScheduledAt,
-			&i.DurationMinutes,
-			&i.IsCancelled,
+			&i.Status,
+			&i.ProviderResourceID,
+			&i.ProviderRecordingID,
+			&i.ProviderUid,
+			&i.OutputPrefix,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ID,
+			&i.PartNumber,
+			&i.Provider,
+			&i.RendererTokenHash,
+			&i.RendererTokenExpiresAt,
+			&i.EmptySinceAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1286,54 +4415,54 @@ func (q *Queries) ListPendingReminders(ctx context.Context) ([]ListPendingRemind
 	return items, nil
 }
 
-const listRecordingPartsReadyToStop = `-- name: ListRecordingPartsReadyToStop :many
-SELECT recording.booking_id, recording.status, recording.provider_resource_id, recording.provider_recording_id, recording.provider_uid, recording.output_prefix, recording.started_at, recording.stopped_at, recording.error, recording.created_at, recording.updated_at, recording.id, recording.part_number, recording.provider, recording.renderer_token_hash, recording.renderer_token_expires_at, recording.empty_since_at
-FROM coaching_booking_recordings recording
-JOIN coaching_bookings booking ON booking.id = recording.booking_id
-WHERE recording.status IN ('starting', 'started', 'stopping')
-  AND (
-      recording.empty_since_at <= NOW() - ($1::int * interval '1 second')
-      OR booking.scheduled_at + (booking.duration_minutes * interval '1 minute')
-         + ($2::int * interval '1 second') <= NOW()
-      OR (recording.status = 'starting' AND recording.updated_at <= NOW() - interval '1 minute')
-  )
-ORDER BY recording.updated_at
-LIMIT $3
+const listUnpaidExpiredBookings = `-- name: ListUnpaidExpiredBookings :many
+SELECT id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at FROM coaching_bookings
+WHERE payment_status = 'pending'
+  AND is_cancelled = false
+  AND payment_expires_at <= NOW()
+ORDER BY payment_expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED
 `
 
-type ListRecordingPartsReadyToStopParams struct {
-	EmptyGraceSeconds int32 `json:"empty_grace_seconds"`
-	EndGraceSeconds   int32 `json:"end_grace_seconds"`
-	LimitCount        int32 `json:"limit_count"`
-}
-
-func (q *Queries) ListRecordingPartsReadyToStop(ctx context.Context, arg ListRecordingPartsReadyToStopParams) ([]CoachingBookingRecording, error) {
-	rows, err := q.db.Query(ctx, listRecordingPartsReadyToStop, arg.EmptyGraceSeconds, arg.EndGraceSeconds, arg.LimitCount)
+// Active bookings whose payment hold ran out, locked for expiry.
+func (q *Queries) ListUnpaidExpiredBookings(ctx context.Context, limit int32) ([]CoachingBooking, error) {
+	rows, err := q.db.Query(ctx, listUnpaidExpiredBookings, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingBookingRecording
+	var items []CoachingBooking
 	for rows.Next() {
-		var i CoachingBookingRecording
+		var i CoachingBooking
 		if err := rows.Scan(
-			&i.BookingID,
-			&i.Status,
-			&i.ProviderResourceID,
-			&i.ProviderRecordingID,
-			&i.ProviderUid,
-			&i.OutputPrefix,
-			&i.StartedAt,
-			&i.StoppedAt,
-			&i.Error,
+			&i.ID,
+			&i.ExpertID,
+			&i.StudentID,
+			&i.GroupID,
+			&i.SessionTypeID,
+			&i.ScheduledAt,
+			&i.DurationMinutes,
+			&i.IsCancelled,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.ID,
-			&i.PartNumber,
-			&i.Provider,
-			&i.RendererTokenHash,
-			&i.RendererTokenExpiresAt,
-			&i.EmptySinceAt,
+			&i.RecordingAssetID,
+			&i.NextRecordingPartNumber,
+			&i.SeriesID,
+			&i.BufferBeforeMinutes,
+			&i.BufferAfterMinutes,
+			&i.PriceCents,
+			&i.Currency,
+			&i.PaymentStatus,
+			&i.PaymentExpiresAt,
+			&i.CreditGrantID,
+			&i.ClassID,
+			&i.ClassSeat,
+			&i.AttendanceOutcome,
+			&i.AttendanceRecordedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1345,36 +4474,44 @@ func (q *Queries) ListRecordingPartsReadyToStop(ctx context.Context, arg ListRec
 	return items, nil
 }
 
-const listSessionTypesByExpertGroup = `-- name: ListSessionTypesByExpertGroup :many
-SELECT id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at FROM coaching_session_types
-WHERE expert_id = $1 AND group_id = $2 AND is_active = true
-ORDER BY duration_minutes
+const listWaitingEntriesByExpert = `-- name: ListWaitingEntriesByExpert :many
+SELECT e.id, e.student_id, e.expert_id, e.group_id, e.session_type_id, e.preferred_weekdays, e.booked_at, e.created_at FROM coaching_waitlist_entries e
+JOIN coaching_session_types st ON st.id = e.session_type_id AND st.is_active = true
+WHERE e.expert_id = $1
+  AND e.booked_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM coaching_waitlist_holds h
+      WHERE h.entry_id = e.id AND h.status = 'offered' AND h.expires_at > NOW()
+  )
+ORDER BY e.created_at
+LIMIT $2
 `
 
-type ListSessionTypesByExpertGroupParams struct {
-	ExpertID string      `json:"expert_id"`
-	GroupID  pgtype.UUID `json:"group_id"`
+type ListWaitingEntriesByExpertParams struct {
+	ExpertID string `json:"expert_id"`
+	Limit    int32  `json:"limit"`
 }
 
-func (q *Queries) ListSessionTypesByExpertGroup(ctx context.Context, arg ListSessionTypesByExpertGroupParams) ([]CoachingSessionType, error) {
-	rows, err := q.db.Query(ctx, listSessionTypesByExpertGroup, arg.ExpertID, arg.GroupID)
+// Entries waiting for the expert without an unexpired hold, oldest first,
+// for session types that can still be booked.
+func (q *Queries) ListWaitingEntriesByExpert(ctx context.Context, arg ListWaitingEntriesByExpertParams) ([]CoachingWaitlistEntry, error) {
+	rows, err := q.db.Query(ctx, listWaitingEntriesByExpert, arg.ExpertID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingSessionType
+	var items []CoachingWaitlistEntry
 	for rows.Next() {
-		var i CoachingSessionType
+		var i CoachingWaitlistEntry
 		if err := rows.Scan(
 			&i.ID,
+			&i.StudentID,
 			&i.ExpertID,
 			&i.GroupID,
-			&i.Name,
-			&i.Description,
-			&i.DurationMinutes,
-			&i.IsActive,
+			&i.SessionTypeID,
+			&i.PreferredWeekdays,
+			&i.BookedAt,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1386,31 +4523,43 @@ func (q *Queries) ListSessionTypesByExpertGroup(ctx context.Context, arg ListSes
 	return items, nil
 }
 
-const listSessionTypesByGroup = `-- name: ListSessionTypesByGroup :many
-SELECT id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at FROM coaching_session_types
-WHERE group_id = $1 AND is_active = true
-ORDER BY expert_id, duration_minutes
+const listWaitlistHoldsByExpertInRange = `-- name: ListWaitlistHoldsByExpertInRange :many
+SELECT id, entry_id, expert_id, scheduled_at, duration_minutes, expires_at, status, booking_id, responded_at, created_at FROM coaching_waitlist_holds
+WHERE expert_id = $1
+  AND status = 'offered'
+  AND expires_at > NOW()
+  AND scheduled_at < $2
+  AND scheduled_at + (duration_minutes * interval '1 minute') > $3
+ORDER BY scheduled_at
 `
 
-func (q *Queries) ListSessionTypesByGroup(ctx context.Context, groupID pgtype.UUID) ([]CoachingSessionType, error) {
-	rows, err := q.db.Query(ctx, listSessionTypesByGroup, groupID)
+type ListWaitlistHoldsByExpertInRangeParams struct {
+	ExpertID string             `json:"expert_id"`
+	ToAt     pgtype.Timestamptz `json:"to_at"`
+	FromAt   pgtype.Timestamptz `json:"from_at"`
+}
+
+// Unexpired holds of the expert overlapping [from_at, to_at).
+func (q *Queries) ListWaitlistHoldsByExpertInRange(ctx context.Context, arg ListWaitlistHoldsByExpertInRangeParams) ([]CoachingWaitlistHold, error) {
+	rows, err := q.db.Query(ctx, listWaitlistHoldsByExpertInRange, arg.ExpertID, arg.ToAt, arg.FromAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingSessionType
+	var items []CoachingWaitlistHold
 	for rows.Next() {
-		var i CoachingSessionType
+		var i CoachingWaitlistHold
 		if err := rows.Scan(
 			&i.ID,
+			&i.EntryID,
 			&i.ExpertID,
-			&i.GroupID,
-			&i.Name,
-			&i.Description,
+			&i.ScheduledAt,
 			&i.DurationMinutes,
-			&i.IsActive,
+			&i.ExpiresAt,
+			&i.Status,
+			&i.BookingID,
+			&i.RespondedAt,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1422,41 +4571,26 @@ func (q *Queries) ListSessionTypesByGroup(ctx context.Context, groupID pgtype.UU
 	return items, nil
 }
 
-const listStoppedRecordingPartsForDiscovery = `-- name: ListStoppedRecordingPartsForDiscovery :many
-SELECT booking_id, status, provider_resource_id, provider_recording_id, provider_uid, output_prefix, started_at, stopped_at, error, created_at, updated_at, id, part_number, provider, renderer_token_hash, renderer_token_expires_at, empty_since_at FROM coaching_booking_recordings
-WHERE status = 'stopped'
-  AND stopped_at >= NOW() - interval '7 days'
-ORDER BY stopped_at DESC
-LIMIT $1
+const listWaitlistWindows = `-- name: ListWaitlistWindows :many
+SELECT id, entry_id, start_time, end_time FROM coaching_waitlist_windows
+WHERE entry_id = ANY($1::uuid[])
+ORDER BY entry_id, start_time
 `
 
-func (q *Queries) ListStoppedRecordingPartsForDiscovery(ctx context.Context, limit int32) ([]CoachingBookingRecording, error) {
-	rows, err := q.db.Query(ctx, listStoppedRecordingPartsForDiscovery, limit)
+func (q *Queries) ListWaitlistWindows(ctx context.Context, entryIds []pgtype.UUID) ([]CoachingWaitlistWindow, error) {
+	rows, err := q.db.Query(ctx, listWaitlistWindows, entryIds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CoachingBookingRecording
+	var items []CoachingWaitlistWindow
 	for rows.Next() {
-		var i CoachingBookingRecording
+		var i CoachingWaitlistWindow
 		if err := rows.Scan(
-			&i.BookingID,
-			&i.Status,
-			&i.ProviderResourceID,
-			&i.ProviderRecordingID,
-			&i.ProviderUid,
-			&i.OutputPrefix,
-			&i.StartedAt,
-			&i.StoppedAt,
-			&i.Error,
-			&i.CreatedAt,
-			&i.UpdatedAt,
 			&i.ID,
-			&i.PartNumber,
-			&i.Provider,
-			&i.RendererTokenHash,
-			&i.RendererTokenExpiresAt,
-			&i.EmptySinceAt,
+			&i.EntryID,
+			&i.StartTime,
+			&i.EndTime,
 		); err != nil {
 			return nil, err
 		}
@@ -1468,6 +4602,124 @@ func (q *Queries) ListStoppedRecordingPartsForDiscovery(ctx context.Context, lim
 	return items, nil
 }
 
+const markBookingPaid = `-- name: MarkBookingPaid :one
+UPDATE coaching_bookings
+SET payment_status = 'paid', updated_at = NOW()
+WHERE id = $1 AND payment_status IN ('pending', 'expired')
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
+`
+
+// Pending and expired bookings become paid; a cancelled one is then owed a
+// refund. No row when the payment was already applied.
+func (q *Queries) MarkBookingPaid(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, markBookingPaid, id)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const markBookingRefunded = `-- name: MarkBookingRefunded :one
+UPDATE coaching_bookings
+SET payment_status = 'refunded', updated_at = NOW()
+WHERE id = $1 AND payment_status = 'paid' AND is_cancelled = true
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
+`
+
+func (q *Queries) MarkBookingRefunded(ctx context.Context, id pgtype.UUID) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, markBookingRefunded, id)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const markCoachingPaymentPaid = `-- name: MarkCoachingPaymentPaid :exec
+UPDATE coaching_payments
+SET provider_payment_id = $2, paid_at = COALESCE(paid_at, NOW())
+WHERE booking_id = $1
+`
+
+type MarkCoachingPaymentPaidParams struct {
+	BookingID         pgtype.UUID `json:"booking_id"`
+	ProviderPaymentID pgtype.Text `json:"provider_payment_id"`
+}
+
+func (q *Queries) MarkCoachingPaymentPaid(ctx context.Context, arg MarkCoachingPaymentPaidParams) error {
+	_, err := q.db.Exec(ctx, markCoachingPaymentPaid, arg.BookingID, arg.ProviderPaymentID)
+	return err
+}
+
+const markCoachingPaymentRefunded = `-- name: MarkCoachingPaymentRefunded :exec
+UPDATE coaching_payments
+SET refund_id = $2, refunded_at = NOW()
+WHERE booking_id = $1
+`
+
+type MarkCoachingPaymentRefundedParams struct {
+	BookingID pgtype.UUID `json:"booking_id"`
+	RefundID  pgtype.Text `json:"refund_id"`
+}
+
+func (q *Queries) MarkCoachingPaymentRefunded(ctx context.Context, arg MarkCoachingPaymentRefundedParams) error {
+	_, err := q.db.Exec(ctx, markCoachingPaymentRefunded, arg.BookingID, arg.RefundID)
+	return err
+}
+
 const markEmptyRecordingPartsWithoutFreshHumans = `-- name: MarkEmptyRecordingPartsWithoutFreshHumans :execrows
 UPDATE coaching_booking_recordings recording
 SET empty_since_at = COALESCE(empty_since_at, NOW()), updated_at = NOW()
@@ -1487,6 +4739,34 @@ func (q *Queries) MarkEmptyRecordingPartsWithoutFreshHumans(ctx context.Context,
 	return result.RowsAffected(), nil
 }
 
+const markExternalCalendarFailed = `-- name: MarkExternalCalendarFailed :exec
+UPDATE coaching_external_calendars
+SET last_attempted_at = NOW(), last_error = $2
+WHERE id = $1
+`
+
+type MarkExternalCalendarFailedParams struct {
+	ID        pgtype.UUID `json:"id"`
+	LastError pgtype.Text `json:"last_error"`
+}
+
+// Keeps the busy blocks from the last successful fetch.
+func (q *Queries) MarkExternalCalendarFailed(ctx context.Context, arg MarkExternalCalendarFailedParams) error {
+	_, err := q.db.Exec(ctx, markExternalCalendarFailed, arg.ID, arg.LastError)
+	return err
+}
+
+const markExternalCalendarSynced = `-- name: MarkExternalCalendarSynced :exec
+UPDATE coaching_external_calendars
+SET last_attempted_at = NOW(), last_synced_at = NOW(), last_error = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkExternalCalendarSynced(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markExternalCalendarSynced, id)
+	return err
+}
+
 const markRecordingPartFailed = `-- name: MarkRecordingPartFailed :exec
 UPDATE coaching_booking_recordings
 SET status = 'failed', stopped_at = NOW(), renderer_token_hash = NULL,
@@ -1639,47 +4919,148 @@ WHERE id = $1 AND status IN ('started', 'stopping')
 RETURNING booking_id, status, provider_resource_id, provider_recording_id, provider_uid, output_prefix, started_at, stopped_at, error, created_at, updated_at, id, part_number, provider, renderer_token_hash, renderer_token_expires_at, empty_since_at
 `
 
-func (q *Queries) MarkRecordingPartStopping(ctx context.Context, id pgtype.UUID) (CoachingBookingRecording, error) {
-	row := q.db.QueryRow(ctx, markRecordingPartStopping, id)
-	var i CoachingBookingRecording
+func (q *Queries) MarkRecordingPartStopping(ctx context.Context, id pgtype.UUID) (CoachingBookingRecording, error) {
+	row := q.db.QueryRow(ctx, markRecordingPartStopping, id)
+	var i CoachingBookingRecording
+	err := row.Scan(
+		&i.BookingID,
+		&i.Status,
+		&i.ProviderResourceID,
+		&i.ProviderRecordingID,
+		&i.ProviderUid,
+		&i.OutputPrefix,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ID,
+		&i.PartNumber,
+		&i.Provider,
+		&i.RendererTokenHash,
+		&i.RendererTokenExpiresAt,
+		&i.EmptySinceAt,
+	)
+	return i, err
+}
+
+const markRecordingRendererReady = `-- name: MarkRecordingRendererReady :one
+UPDATE coaching_booking_recordings
+SET status = 'started', updated_at = NOW()
+WHERE renderer_token_hash = $1
+  AND renderer_token_expires_at > NOW()
+  AND status IN ('starting', 'started')
+RETURNING id
+`
+
+func (q *Queries) MarkRecordingRendererReady(ctx context.Context, rendererTokenHash []byte) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, markRecordingRendererReady, rendererTokenHash)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const markRecordingStitchFailed = `-- name: MarkRecordingStitchFailed :exec
+UPDATE coaching_recording_stitches
+SET status = 'failed', object_name = COALESCE($1, object_name),
+    mux_asset_id = $2, mux_playback_id = NULL, error = $3, updated_at = NOW()
+WHERE booking_id = $4
+`
+
+type MarkRecordingStitchFailedParams struct {
+	ObjectName pgtype.Text `json:"object_name"`
+	MuxAssetID pgtype.Text `json:"mux_asset_id"`
+	Error      pgtype.Text `json:"error"`
+	BookingID  pgtype.UUID `json:"booking_id"`
+}
+
+// Keeps the stitched file, if there is one, so a retry only imports it again.
+// mux_asset_id is set when the stitch's Mux asset could not be deleted, so a
+// retry checks on it again instead of losing track of it.
+func (q *Queries) MarkRecordingStitchFailed(ctx context.Context, arg MarkRecordingStitchFailedParams) error {
+	_, err := q.db.Exec(ctx, markRecordingStitchFailed,
+		arg.ObjectName,
+		arg.MuxAssetID,
+		arg.Error,
+		arg.BookingID,
+	)
+	return err
+}
+
+const markRecordingStitchMuxCreated = `-- name: MarkRecordingStitchMuxCreated :one
+UPDATE coaching_recording_stitches
+SET status = 'processing', object_name = $2, mux_asset_id = $3, mux_playback_id = $4,
+    error = NULL, updated_at = NOW()
+WHERE booking_id = $1
+RETURNING booking_id, status, part_count, object_name, mux_asset_id, mux_playback_id, video_id, attempts, last_attempt_at, stitched_at, error, created_at, updated_at
+`
+
+type MarkRecordingStitchMuxCreatedParams struct {
+	BookingID     pgtype.UUID `json:"booking_id"`
+	ObjectName    pgtype.Text `json:"object_name"`
+	MuxAssetID    pgtype.Text `json:"mux_asset_id"`
+	MuxPlaybackID pgtype.Text `json:"mux_playback_id"`
+}
+
+func (q *Queries) MarkRecordingStitchMuxCreated(ctx context.Context, arg MarkRecordingStitchMuxCreatedParams) (CoachingRecordingStitch, error) {
+	row := q.db.QueryRow(ctx, markRecordingStitchMuxCreated,
+		arg.BookingID,
+		arg.ObjectName,
+		arg.MuxAssetID,
+		arg.MuxPlaybackID,
+	)
+	var i CoachingRecordingStitch
+	err := row.Scan(
+		&i.BookingID,
+		&i.Status,
+		&i.PartCount,
+		&i.ObjectName,
+		&i.MuxAssetID,
+		&i.MuxPlaybackID,
+		&i.VideoID,
+		&i.Attempts,
+		&i.LastAttemptAt,
+		&i.StitchedAt,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markRecordingStitchReady = `-- name: MarkRecordingStitchReady :one
+UPDATE coaching_recording_stitches
+SET status = 'ready', video_id = $2, stitched_at = NOW(), error = NULL, updated_at = NOW()
+WHERE booking_id = $1
+RETURNING booking_id, status, part_count, object_name, mux_asset_id, mux_playback_id, video_id, attempts, last_attempt_at, stitched_at, error, created_at, updated_at
+`
+
+type MarkRecordingStitchReadyParams struct {
+	BookingID pgtype.UUID `json:"booking_id"`
+	VideoID   pgtype.UUID `json:"video_id"`
+}
+
+func (q *Queries) MarkRecordingStitchReady(ctx context.Context, arg MarkRecordingStitchReadyParams) (CoachingRecordingStitch, error) {
+	row := q.db.QueryRow(ctx, markRecordingStitchReady, arg.BookingID, arg.VideoID)
+	var i CoachingRecordingStitch
 	err := row.Scan(
 		&i.BookingID,
 		&i.Status,
-		&i.ProviderResourceID,
-		&i.ProviderRecordingID,
-		&i.ProviderUid,
-		&i.OutputPrefix,
-		&i.StartedAt,
-		&i.StoppedAt,
+		&i.PartCount,
+		&i.ObjectName,
+		&i.MuxAssetID,
+		&i.MuxPlaybackID,
+		&i.VideoID,
+		&i.Attempts,
+		&i.LastAttemptAt,
+		&i.StitchedAt,
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.ID,
-		&i.PartNumber,
-		&i.Provider,
-		&i.RendererTokenHash,
-		&i.RendererTokenExpiresAt,
-		&i.EmptySinceAt,
 	)
 	return i, err
 }
 
-const markRecordingRendererReady = `-- name: MarkRecordingRendererReady :one
-UPDATE coaching_booking_recordings
-SET status = 'started', updated_at = NOW()
-WHERE renderer_token_hash = $1
-  AND renderer_token_expires_at > NOW()
-  AND status IN ('starting', 'started')
-RETURNING id
-`
-
-func (q *Queries) MarkRecordingRendererReady(ctx context.Context, rendererTokenHash []byte) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, markRecordingRendererReady, rendererTokenHash)
-	var id pgtype.UUID
-	err := row.Scan(&id)
-	return id, err
-}
-
 const markReminderSent = `-- name: MarkReminderSent :exec
 UPDATE coaching_booking_reminders SET sent_at = NOW() WHERE id = $1
 `
@@ -1689,50 +5070,340 @@ func (q *Queries) MarkReminderSent(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const markWaitlistEntryBooked = `-- name: MarkWaitlistEntryBooked :exec
+UPDATE coaching_waitlist_entries SET booked_at = NOW() WHERE id = $1
+`
+
+func (q *Queries) MarkWaitlistEntryBooked(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markWaitlistEntryBooked, id)
+	return err
+}
+
+const recordBookingAttendance = `-- name: RecordBookingAttendance :exec
+INSERT INTO coaching_booking_attendance (booking_id, participant_id, participant_role)
+VALUES ($1, $2, $3)
+ON CONFLICT (booking_id, participant_id) DO UPDATE SET
+    attended_seconds = coaching_booking_attendance.attended_seconds
+        + LEAST(EXTRACT(EPOCH FROM NOW() - coaching_booking_attendance.last_seen_at), $4::int)::int,
+    last_seen_at = NOW()
+`
+
+type RecordBookingAttendanceParams struct {
+	BookingID       pgtype.UUID `json:"booking_id"`
+	ParticipantID   string      `json:"participant_id"`
+	ParticipantRole string      `json:"participant_role"`
+	MaxGapSeconds   int32       `json:"max_gap_seconds"`
+}
+
+// Adds the time since the participant's previous heartbeat to their attendance,
+// capped at max_gap_seconds so a dropped connection is not counted as time in
+// the call.
+func (q *Queries) RecordBookingAttendance(ctx context.Context, arg RecordBookingAttendanceParams) error {
+	_, err := q.db.Exec(ctx, recordBookingAttendance,
+		arg.BookingID,
+		arg.ParticipantID,
+		arg.ParticipantRole,
+		arg.MaxGapSeconds,
+	)
+	return err
+}
+
 const refreshBookingPresence = `-- name: RefreshBookingPresence :one
 UPDATE coaching_booking_presence
 SET last_seen_at = NOW()
-WHERE booking_id = $1 AND participant_role = $2 AND connection_id = $3
-RETURNING booking_id, participant_role, connection_id, last_seen_at
+WHERE booking_id = $1 AND participant_id = $2 AND connection_id = $3
+RETURNING booking_id, participant_role, connection_id, last_seen_at, participant_id
 `
 
 type RefreshBookingPresenceParams struct {
-	BookingID       pgtype.UUID `json:"booking_id"`
-	ParticipantRole string      `json:"participant_role"`
-	ConnectionID    pgtype.UUID `json:"connection_id"`
+	BookingID     pgtype.UUID `json:"booking_id"`
+	ParticipantID string      `json:"participant_id"`
+	ConnectionID  pgtype.UUID `json:"connection_id"`
 }
 
 func (q *Queries) RefreshBookingPresence(ctx context.Context, arg RefreshBookingPresenceParams) (CoachingBookingPresence, error) {
-	row := q.db.QueryRow(ctx, refreshBookingPresence, arg.BookingID, arg.ParticipantRole, arg.ConnectionID)
+	row := q.db.QueryRow(ctx, refreshBookingPresence, arg.BookingID, arg.ParticipantID, arg.ConnectionID)
 	var i CoachingBookingPresence
 	err := row.Scan(
 		&i.BookingID,
 		&i.ParticipantRole,
 		&i.ConnectionID,
 		&i.LastSeenAt,
+		&i.ParticipantID,
 	)
 	return i, err
 }
 
 const removeBookingPresence = `-- name: RemoveBookingPresence :execrows
 DELETE FROM coaching_booking_presence
-WHERE booking_id = $1 AND participant_role = $2 AND connection_id = $3
+WHERE booking_id = $1 AND participant_id = $2 AND connection_id = $3
 `
 
 type RemoveBookingPresenceParams struct {
-	BookingID       pgtype.UUID `json:"booking_id"`
-	ParticipantRole string      `json:"participant_role"`
-	ConnectionID    pgtype.UUID `json:"connection_id"`
+	BookingID     pgtype.UUID `json:"booking_id"`
+	ParticipantID string      `json:"participant_id"`
+	ConnectionID  pgtype.UUID `json:"connection_id"`
 }
 
 func (q *Queries) RemoveBookingPresence(ctx context.Context, arg RemoveBookingPresenceParams) (int64, error) {
-	result, err := q.db.Exec(ctx, removeBookingPresence, arg.BookingID, arg.ParticipantRole, arg.ConnectionID)
+	result, err := q.db.Exec(ctx, removeBookingPresence, arg.BookingID, arg.ParticipantID, arg.ConnectionID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const reopenWaitlistEntryForBooking = `-- name: ReopenWaitlistEntryForBooking :exec
+UPDATE coaching_waitlist_entries e
+SET booked_at = NULL
+FROM coaching_waitlist_holds h
+WHERE h.booking_id = $1
+  AND h.entry_id = e.id
+  AND e.booked_at IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM coaching_waitlist_entries w
+      WHERE w.student_id = e.student_id
+        AND w.session_type_id = e.session_type_id
+        AND w.booked_at IS NULL
+  )
+`
+
+// Puts the entry a confirmed hold booked back on the waitlist, in its old
+// place, when that booking's checkout could not be started. An entry the
+// student has since replaced stays booked.
+func (q *Queries) ReopenWaitlistEntryForBooking(ctx context.Context, bookingID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, reopenWaitlistEntryForBooking, bookingID)
+	return err
+}
+
+const rescheduleBooking = `-- name: RescheduleBooking :one
+UPDATE coaching_bookings
+SET scheduled_at = $2, updated_at = NOW()
+WHERE id = $1 AND is_cancelled = false
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
+`
+
+type RescheduleBookingParams struct {
+	ID          pgtype.UUID        `json:"id"`
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+}
+
+func (q *Queries) RescheduleBooking(ctx context.Context, arg RescheduleBookingParams) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, rescheduleBooking, arg.ID, arg.ScheduledAt)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const respondToBookingReschedule = `-- name: RespondToBookingReschedule :one
+UPDATE coaching_booking_reschedules
+SET status = $2, responded_by = $3, responded_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING id, booking_id, proposed_by, scheduled_at, previous_scheduled_at, note, status, responded_by, responded_at, created_at
+`
+
+type RespondToBookingRescheduleParams struct {
+	ID          pgtype.UUID              `json:"id"`
+	Status      CoachingRescheduleStatus `json:"status"`
+	RespondedBy pgtype.Text              `json:"responded_by"`
+}
+
+func (q *Queries) RespondToBookingReschedule(ctx context.Context, arg RespondToBookingRescheduleParams) (CoachingBookingReschedule, error) {
+	row := q.db.QueryRow(ctx, respondToBookingReschedule, arg.ID, arg.Status, arg.RespondedBy)
+	var i CoachingBookingReschedule
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.ProposedBy,
+		&i.ScheduledAt,
+		&i.PreviousScheduledAt,
+		&i.Note,
+		&i.Status,
+		&i.RespondedBy,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const respondToWaitlistHold = `-- name: RespondToWaitlistHold :one
+UPDATE coaching_waitlist_holds
+SET status = $1, booking_id = $2, responded_at = NOW()
+WHERE id = $3 AND status = 'offered'
+RETURNING id, entry_id, expert_id, scheduled_at, duration_minutes, expires_at, status, booking_id, responded_at, created_at
+`
+
+type RespondToWaitlistHoldParams struct {
+	Status    CoachingWaitlistHoldStatus `json:"status"`
+	BookingID pgtype.UUID                `json:"booking_id"`
+	ID        pgtype.UUID                `json:"id"`
+}
+
+func (q *Queries) RespondToWaitlistHold(ctx context.Context, arg RespondToWaitlistHoldParams) (CoachingWaitlistHold, error) {
+	row := q.db.QueryRow(ctx, respondToWaitlistHold, arg.Status, arg.BookingID, arg.ID)
+	var i CoachingWaitlistHold
+	err := row.Scan(
+		&i.ID,
+		&i.EntryID,
+		&i.ExpertID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.BookingID,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const restoreCredit = `-- name: RestoreCredit :one
+UPDATE coaching_credit_grants
+SET remaining = remaining + 1, updated_at = NOW()
+WHERE id = $1 AND remaining < quantity
+  AND NOT EXISTS (
+      SELECT 1 FROM coaching_credit_ledger
+      WHERE booking_id = $2 AND kind = 'restore'
+  )
+RETURNING id, student_id, expert_id, group_id, quantity, remaining, expires_at, note, granted_by, created_at, updated_at
+`
+
+type RestoreCreditParams struct {
+	ID        pgtype.UUID `json:"id"`
+	BookingID pgtype.UUID `json:"booking_id"`
+}
+
+// No row when the grant was never short of this credit or the booking's
+// credit was already restored.
+func (q *Queries) RestoreCredit(ctx context.Context, arg RestoreCreditParams) (CoachingCreditGrant, error) {
+	row := q.db.QueryRow(ctx, restoreCredit, arg.ID, arg.BookingID)
+	var i CoachingCreditGrant
+	err := row.Scan(
+		&i.ID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Quantity,
+		&i.Remaining,
+		&i.ExpiresAt,
+		&i.Note,
+		&i.GrantedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setBookingAttendance = `-- name: SetBookingAttendance :one
+UPDATE coaching_bookings
+SET attendance_outcome = $2, attendance_recorded_at = NOW()
+WHERE id = $1 AND attendance_recorded_at IS NULL
+RETURNING id, expert_id, student_id, group_id, session_type_id, scheduled_at, duration_minutes, is_cancelled, cancellation_reason, cancelled_by, notes, created_at, updated_at, recording_asset_id, next_recording_part_number, series_id, buffer_before_minutes, buffer_after_minutes, price_cents, currency, payment_status, payment_expires_at, credit_grant_id, class_id, class_seat, attendance_outcome, attendance_recorded_at
+`
+
+type SetBookingAttendanceParams struct {
+	ID                pgtype.UUID                   `json:"id"`
+	AttendanceOutcome NullCoachingAttendanceOutcome `json:"attendance_outcome"`
+}
+
+// Leaves updated_at alone: the outcome is not a change to the session itself,
+// and updated_at drives calendar feed sequence numbers.
+func (q *Queries) SetBookingAttendance(ctx context.Context, arg SetBookingAttendanceParams) (CoachingBooking, error) {
+	row := q.db.QueryRow(ctx, setBookingAttendance, arg.ID, arg.AttendanceOutcome)
+	var i CoachingBooking
+	err := row.Scan(
+		&i.ID,
+		&i.ExpertID,
+		&i.StudentID,
+		&i.GroupID,
+		&i.SessionTypeID,
+		&i.ScheduledAt,
+		&i.DurationMinutes,
+		&i.IsCancelled,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RecordingAssetID,
+		&i.NextRecordingPartNumber,
+		&i.SeriesID,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.PaymentStatus,
+		&i.PaymentExpiresAt,
+		&i.CreditGrantID,
+		&i.ClassID,
+		&i.ClassSeat,
+		&i.AttendanceOutcome,
+		&i.AttendanceRecordedAt,
+	)
+	return i, err
+}
+
+const setHomeworkCompleted = `-- name: SetHomeworkCompleted :one
+UPDATE coaching_homework
+SET completed_at = CASE WHEN $1::boolean THEN COALESCE(completed_at, NOW()) END,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING id, booking_id, student_id, expert_id, group_id, title, description, due_at, completed_at, created_at, updated_at
+`
+
+type SetHomeworkCompletedParams struct {
+	Completed bool        `json:"completed"`
+	ID        pgtype.UUID `json:"id"`
+}
+
+// Marks the homework done, or open again when completed is false.
+func (q *Queries) SetHomeworkCompleted(ctx context.Context, arg SetHomeworkCompletedParams) (CoachingHomework, error) {
+	row := q.db.QueryRow(ctx, setHomeworkCompleted, arg.Completed, arg.ID)
+	var i CoachingHomework
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Title,
+		&i.Description,
+		&i.DueAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const setRecordingPartProviderStarted = `-- name: SetRecordingPartProviderStarted :one
 UPDATE coaching_booking_recordings
 SET provider_resource_id = $2,
@@ -1786,6 +5457,24 @@ func (q *Queries) SetRecordingPartProviderStarted(ctx context.Context, arg SetRe
 	return i, err
 }
 
+const touchCalendarFeedByTokenHash = `-- name: TouchCalendarFeedByTokenHash :one
+UPDATE coaching_calendar_feeds SET last_accessed_at = NOW()
+WHERE token_hash = $1
+RETURNING user_id, token_hash, created_at, last_accessed_at
+`
+
+func (q *Queries) TouchCalendarFeedByTokenHash(ctx context.Context, tokenHash []byte) (CoachingCalendarFeed, error) {
+	row := q.db.QueryRow(ctx, touchCalendarFeedByTokenHash, tokenHash)
+	var i CoachingCalendarFeed
+	err := row.Scan(
+		&i.UserID,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.LastAccessedAt,
+	)
+	return i, err
+}
+
 const updateAvailability = `-- name: UpdateAvailability :one
 UPDATE coaching_availability
 SET day_of_week = $2, start_time = $3, end_time = $4, updated_at = NOW()
@@ -1826,20 +5515,74 @@ func (q *Queries) UpdateAvailability(ctx context.Context, arg UpdateAvailability
 	return i, err
 }
 
+const updateHomework = `-- name: UpdateHomework :one
+UPDATE coaching_homework
+SET title = $2, description = $3, due_at = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, booking_id, student_id, expert_id, group_id, title, description, due_at, completed_at, created_at, updated_at
+`
+
+type UpdateHomeworkParams struct {
+	ID          pgtype.UUID        `json:"id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	DueAt       pgtype.Timestamptz `json:"due_at"`
+}
+
+func (q *Queries) UpdateHomework(ctx context.Context, arg UpdateHomeworkParams) (CoachingHomework, error) {
+	row := q.db.QueryRow(ctx, updateHomework,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.DueAt,
+	)
+	var i CoachingHomework
+	err := row.Scan(
+		&i.ID,
+		&i.BookingID,
+		&i.StudentID,
+		&i.ExpertID,
+		&i.GroupID,
+		&i.Title,
+		&i.Description,
+		&i.DueAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const updateSessionType = `-- name: UpdateSessionType :one
 UPDATE coaching_session_types
-SET name = $2, description = $3, duration_minutes = $4, updated_at = NOW()
+SET name = $2, description = $3, duration_minutes = $4,
+    buffer_before_minutes = $7, buffer_after_minutes = $8, max_sessions_per_day = $9,
+    booking_horizon_days = $10, min_booking_notice_minutes = $11, cancellation_notice_minutes = $12,
+    price_cents = $13, currency = $14, requires_credit = $15, capacity = $16,
+    no_show_credit_policy = $17, notify_owner_on_no_show = $18, updated_at = NOW()
 WHERE id = $1 AND expert_id = $5 AND group_id = $6
-RETURNING id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at
+RETURNING id, expert_id, group_id, name, description, duration_minutes, is_active, created_at, updated_at, buffer_before_minutes, buffer_after_minutes, max_sessions_per_day, booking_horizon_days, min_booking_notice_minutes, cancellation_notice_minutes, price_cents, currency, requires_credit, capacity, no_show_credit_policy, notify_owner_on_no_show
 `
 
 type UpdateSessionTypeParams struct {
-	ID              pgtype.UUID `json:"id"`
-	Name            string      `json:"name"`
-	Description     string      `json:"description"`
-	DurationMinutes int32       `json:"duration_minutes"`
-	ExpertID        string      `json:"expert_id"`
-	GroupID         pgtype.UUID `json:"group_id"`
+	ID                        pgtype.UUID `json:"id"`
+	Name                      string      `json:"name"`
+	Description               string      `json:"description"`
+	DurationMinutes           int32       `json:"duration_minutes"`
+	ExpertID                  string      `json:"expert_id"`
+	GroupID                   pgtype.UUID `json:"group_id"`
+	BufferBeforeMinutes       int32       `json:"buffer_before_minutes"`
+	BufferAfterMinutes        int32       `json:"buffer_after_minutes"`
+	MaxSessionsPerDay         pgtype.Int4 `json:"max_sessions_per_day"`
+	BookingHorizonDays        pgtype.Int4 `json:"booking_horizon_days"`
+	MinBookingNoticeMinutes   pgtype.Int4 `json:"min_booking_notice_minutes"`
+	CancellationNoticeMinutes pgtype.Int4 `json:"cancellation_notice_minutes"`
+	PriceCents                int32       `json:"price_cents"`
+	Currency                  pgtype.Text `json:"currency"`
+	RequiresCredit            bool        `json:"requires_credit"`
+	Capacity                  int32       `json:"capacity"`
+	NoShowCreditPolicy        string      `json:"no_show_credit_policy"`
+	NotifyOwnerOnNoShow       bool        `json:"notify_owner_on_no_show"`
 }
 
 func (q *Queries) UpdateSessionType(ctx context.Context, arg UpdateSessionTypeParams) (CoachingSessionType, error) {
@@ -1850,6 +5593,18 @@ func (q *Queries) UpdateSessionType(ctx context.Context, arg UpdateSessionTypePa
 		arg.DurationMinutes,
 		arg.ExpertID,
 		arg.GroupID,
+		arg.BufferBeforeMinutes,
+		arg.BufferAfterMinutes,
+		arg.MaxSessionsPerDay,
+		arg.BookingHorizonDays,
+		arg.MinBookingNoticeMinutes,
+		arg.CancellationNoticeMinutes,
+		arg.PriceCents,
+		arg.Currency,
+		arg.RequiresCredit,
+		arg.Capacity,
+		arg.NoShowCreditPolicy,
+		arg.NotifyOwnerOnNoShow,
 	)
 	var i CoachingSessionType
 	err := row.Scan(
@@ -1862,33 +5617,142 @@ func (q *Queries) UpdateSessionType(ctx context.Context, arg UpdateSessionTypePa
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BufferBeforeMinutes,
+		&i.BufferAfterMinutes,
+		&i.MaxSessionsPerDay,
+		&i.BookingHorizonDays,
+		&i.MinBookingNoticeMinutes,
+		&i.CancellationNoticeMinutes,
+		&i.PriceCents,
+		&i.Currency,
+		&i.RequiresCredit,
+		&i.Capacity,
+		&i.NoShowCreditPolicy,
+		&i.NotifyOwnerOnNoShow,
 	)
 	return i, err
 }
 
 const upsertBookingPresence = `-- name: UpsertBookingPresence :one
-INSERT INTO coaching_booking_presence (booking_id, participant_role, connection_id, last_seen_at)
-VALUES ($1, $2, $3, NOW())
-ON CONFLICT (booking_id, participant_role) DO UPDATE SET
+INSERT INTO coaching_booking_presence (booking_id, participant_id, participant_role, connection_id, last_seen_at)
+VALUES ($1, $2, $3, $4, NOW())
+ON CONFLICT (booking_id, participant_id) DO UPDATE SET
     connection_id = EXCLUDED.connection_id,
     last_seen_at = NOW()
-RETURNING booking_id, participant_role, connection_id, last_seen_at
+RETURNING booking_id, participant_role, connection_id, last_seen_at, participant_id
 `
 
 type UpsertBookingPresenceParams struct {
 	BookingID       pgtype.UUID `json:"booking_id"`
+	ParticipantID   string      `json:"participant_id"`
 	ParticipantRole string      `json:"participant_role"`
 	ConnectionID    pgtype.UUID `json:"connection_id"`
 }
 
 func (q *Queries) UpsertBookingPresence(ctx context.Context, arg UpsertBookingPresenceParams) (CoachingBookingPresence, error) {
-	row := q.db.QueryRow(ctx, upsertBookingPresence, arg.BookingID, arg.ParticipantRole, arg.ConnectionID)
+	row := q.db.QueryRow(ctx, upsertBookingPresence,
+		arg.BookingID,
+		arg.ParticipantID,
+		arg.ParticipantRole,
+		arg.ConnectionID,
+	)
 	var i CoachingBookingPresence
 	err := row.Scan(
 		&i.BookingID,
 		&i.ParticipantRole,
 		&i.ConnectionID,
 		&i.LastSeenAt,
+		&i.ParticipantID,
+	)
+	return i, err
+}
+
+const upsertCalendarFeed = `-- name: UpsertCalendarFeed :one
+INSERT INTO coaching_calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET token_hash = EXCLUDED.token_hash, created_at = NOW(), last_accessed_at = NULL
+RETURNING user_id, token_hash, created_at, last_accessed_at
+`
+
+type UpsertCalendarFeedParams struct {
+	UserID    string `json:"user_id"`
+	TokenHash []byte `json:"token_hash"`
+}
+
+// Rotating replaces the token hash, so the previous feed URL stops working.
+func (q *Queries) UpsertCalendarFeed(ctx context.Context, arg UpsertCalendarFeedParams) (CoachingCalendarFeed, error) {
+	row := q.db.QueryRow(ctx, upsertCalendarFeed, arg.UserID, arg.TokenHash)
+	var i CoachingCalendarFeed
+	err := row.Scan(
+		&i.UserID,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.LastAccessedAt,
+	)
+	return i, err
+}
+
+const upsertSessionNotes = `-- name: UpsertSessionNotes :one
+INSERT INTO coaching_session_notes (booking_id, author_id, summary, went_well, to_improve, next_focus)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (booking_id) DO UPDATE SET
+    author_id = EXCLUDED.author_id,
+    summary = EXCLUDED.summary,
+    went_well = EXCLUDED.went_well,
+    to_improve = EXCLUDED.to_improve,
+    next_focus = EXCLUDED.next_focus,
+    updated_at = NOW()
+RETURNING booking_id, author_id, summary, went_well, to_improve, next_focus, created_at, updated_at
+`
+
+type UpsertSessionNotesParams struct {
+	BookingID pgtype.UUID `json:"booking_id"`
+	AuthorID  string      `json:"author_id"`
+	Summary   string      `json:"summary"`
+	WentWell  string      `json:"went_well"`
+	ToImprove string      `json:"to_improve"`
+	NextFocus string      `json:"next_focus"`
+}
+
+func (q *Queries) UpsertSessionNotes(ctx context.Context, arg UpsertSessionNotesParams) (CoachingSessionNote, error) {
+	row := q.db.QueryRow(ctx, upsertSessionNotes,
+		arg.BookingID,
+		arg.AuthorID,
+		arg.Summary,
+		arg.WentWell,
+		arg.ToImprove,
+		arg.NextFocus,
+	)
+	var i CoachingSessionNote
+	err := row.Scan(
+		&i.BookingID,
+		&i.AuthorID,
+		&i.Summary,
+		&i.WentWell,
+		&i.ToImprove,
+		&i.NextFocus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
+
+const withdrawPendingBookingReschedule = `-- name: WithdrawPendingBookingReschedule :execrows
+UPDATE coaching_booking_reschedules
+SET status = 'withdrawn', responded_by = $2, responded_at = NOW()
+WHERE booking_id = $1 AND status = 'pending'
+`
+
+type WithdrawPendingBookingRescheduleParams struct {
+	BookingID   pgtype.UUID `json:"booking_id"`
+	RespondedBy pgtype.Text `json:"responded_by"`
+}
+
+func (q *Queries) WithdrawPendingBookingReschedule(ctx context.Context, arg WithdrawPendingBookingRescheduleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, withdrawPendingBookingReschedule, arg.BookingID, arg.RespondedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
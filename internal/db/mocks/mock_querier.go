@@ -57,6 +57,21 @@ func (mr *MockQuerierMockRecorder) ActivateUserAccess(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateUserAccess", reflect.TypeOf((*MockQuerier)(nil).ActivateUserAccess), ctx, arg)
 }
 
+// AddReviewReaction mocks base method.
+func (m *MockQuerier) AddReviewReaction(ctx context.Context, arg db.AddReviewReactionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReviewReaction", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReviewReaction indicates an expected call of AddReviewReaction.
+func (mr *MockQuerierMockRecorder) AddReviewReaction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReviewReaction", reflect.TypeOf((*MockQuerier)(nil).AddReviewReaction), ctx, arg)
+}
+
 // AddUserToGroup mocks base method.
 func (m *MockQuerier) AddUserToGroup(ctx context.Context, arg db.AddUserToGroupParams) error {
 	m.ctrl.T.Helper()
@@ -71,6 +86,20 @@ func (mr *MockQuerierMockRecorder) AddUserToGroup(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserToGroup", reflect.TypeOf((*MockQuerier)(nil).AddUserToGroup), ctx, arg)
 }
 
+// AdvanceAuditChainHead mocks base method.
+func (m *MockQuerier) AdvanceAuditChainHead(ctx context.Context, arg db.AdvanceAuditChainHeadParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceAuditChainHead", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdvanceAuditChainHead indicates an expected call of AdvanceAuditChainHead.
+func (mr *MockQuerierMockRecorder) AdvanceAuditChainHead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceAuditChainHead", reflect.TypeOf((*MockQuerier)(nil).AdvanceAuditChainHead), ctx, arg)
+}
+
 // AssignBookingRecordingAsset mocks base method.
 func (m *MockQuerier) AssignBookingRecordingAsset(ctx context.Context, arg db.AssignBookingRecordingAssetParams) (db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +115,35 @@ func (mr *MockQuerierMockRecorder) AssignBookingRecordingAsset(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignBookingRecordingAsset", reflect.TypeOf((*MockQuerier)(nil).AssignBookingRecordingAsset), ctx, arg)
 }
 
+// AssignClassRecordingAsset mocks base method.
+func (m *MockQuerier) AssignClassRecordingAsset(ctx context.Context, arg db.AssignClassRecordingAssetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignClassRecordingAsset", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignClassRecordingAsset indicates an expected call of AssignClassRecordingAsset.
+func (mr *MockQuerierMockRecorder) AssignClassRecordingAsset(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignClassRecordingAsset", reflect.TypeOf((*MockQuerier)(nil).AssignClassRecordingAsset), ctx, arg)
+}
+
+// AttachVideoMuxAsset mocks base method.
+func (m *MockQuerier) AttachVideoMuxAsset(ctx context.Context, arg db.AttachVideoMuxAssetParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachVideoMuxAsset", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachVideoMuxAsset indicates an expected call of AttachVideoMuxAsset.
+func (mr *MockQuerierMockRecorder) AttachVideoMuxAsset(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachVideoMuxAsset", reflect.TypeOf((*MockQuerier)(nil).AttachVideoMuxAsset), ctx, arg)
+}
+
 // CancelBooking mocks base method.
 func (m *MockQuerier) CancelBooking(ctx context.Context, arg db.CancelBookingParams) (db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +189,21 @@ func (mr *MockQuerierMockRecorder) CheckVideoVisibleToUser(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVideoVisibleToUser", reflect.TypeOf((*MockQuerier)(nil).CheckVideoVisibleToUser), ctx, arg)
 }
 
+// ClaimDueExternalCalendars mocks base method.
+func (m *MockQuerier) ClaimDueExternalCalendars(ctx context.Context, arg db.ClaimDueExternalCalendarsParams) ([]db.CoachingExternalCalendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueExternalCalendars", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingExternalCalendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueExternalCalendars indicates an expected call of ClaimDueExternalCalendars.
+func (mr *MockQuerierMockRecorder) ClaimDueExternalCalendars(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueExternalCalendars", reflect.TypeOf((*MockQuerier)(nil).ClaimDueExternalCalendars), ctx, arg)
+}
+
 // ClaimInboundEmailByResendID mocks base method.
 func (m *MockQuerier) ClaimInboundEmailByResendID(ctx context.Context, resendEmailID string) (db.InboundEmail, error) {
 	m.ctrl.T.Helper()
@@ -191,6 +264,35 @@ func (mr *MockQuerierMockRecorder) ClaimPendingRecordingPartImports(ctx, limit a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingRecordingPartImports", reflect.TypeOf((*MockQuerier)(nil).ClaimPendingRecordingPartImports), ctx, limit)
 }
 
+// ClaimPendingRecordingStitches mocks base method.
+func (m *MockQuerier) ClaimPendingRecordingStitches(ctx context.Context, limit int32) ([]db.ClaimPendingRecordingStitchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingRecordingStitches", ctx, limit)
+	ret0, _ := ret[0].([]db.ClaimPendingRecordingStitchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingRecordingStitches indicates an expected call of ClaimPendingRecordingStitches.
+func (mr *MockQuerierMockRecorder) ClaimPendingRecordingStitches(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingRecordingStitches", reflect.TypeOf((*MockQuerier)(nil).ClaimPendingRecordingStitches), ctx, limit)
+}
+
+// ClearAssetModerationTargets mocks base method.
+func (m *MockQuerier) ClearAssetModerationTargets(ctx context.Context, assetID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearAssetModerationTargets", ctx, assetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearAssetModerationTargets indicates an expected call of ClearAssetModerationTargets.
+func (mr *MockQuerierMockRecorder) ClearAssetModerationTargets(ctx, assetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearAssetModerationTargets", reflect.TypeOf((*MockQuerier)(nil).ClearAssetModerationTargets), ctx, assetID)
+}
+
 // ClearRecordingPartEmptySince mocks base method.
 func (m *MockQuerier) ClearRecordingPartEmptySince(ctx context.Context, bookingID pgtype.UUID) error {
 	m.ctrl.T.Helper()
@@ -205,6 +307,35 @@ func (mr *MockQuerierMockRecorder) ClearRecordingPartEmptySince(ctx, bookingID a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearRecordingPartEmptySince", reflect.TypeOf((*MockQuerier)(nil).ClearRecordingPartEmptySince), ctx, bookingID)
 }
 
+// ClearVideoModerationTargets mocks base method.
+func (m *MockQuerier) ClearVideoModerationTargets(ctx context.Context, targetVideoID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearVideoModerationTargets", ctx, targetVideoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearVideoModerationTargets indicates an expected call of ClearVideoModerationTargets.
+func (mr *MockQuerierMockRecorder) ClearVideoModerationTargets(ctx, targetVideoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearVideoModerationTargets", reflect.TypeOf((*MockQuerier)(nil).ClearVideoModerationTargets), ctx, targetVideoID)
+}
+
+// ConsumeCredit mocks base method.
+func (m *MockQuerier) ConsumeCredit(ctx context.Context, arg db.ConsumeCreditParams) (db.CoachingCreditGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeCredit", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingCreditGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeCredit indicates an expected call of ConsumeCredit.
+func (mr *MockQuerierMockRecorder) ConsumeCredit(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeCredit", reflect.TypeOf((*MockQuerier)(nil).ConsumeCredit), ctx, arg)
+}
+
 // ConsumeSignupCode mocks base method.
 func (m *MockQuerier) ConsumeSignupCode(ctx context.Context, arg db.ConsumeSignupCodeParams) (db.SignupCode, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +366,66 @@ func (mr *MockQuerierMockRecorder) CountAdminInboundEmails(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAdminInboundEmails", reflect.TypeOf((*MockQuerier)(nil).CountAdminInboundEmails), ctx, arg)
 }
 
+// CountBookingHomework mocks base method.
+func (m *MockQuerier) CountBookingHomework(ctx context.Context, bookingID pgtype.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBookingHomework", ctx, bookingID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBookingHomework indicates an expected call of CountBookingHomework.
+func (mr *MockQuerierMockRecorder) CountBookingHomework(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBookingHomework", reflect.TypeOf((*MockQuerier)(nil).CountBookingHomework), ctx, bookingID)
+}
+
+// CountBookingMessages mocks base method.
+func (m *MockQuerier) CountBookingMessages(ctx context.Context, bookingID pgtype.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBookingMessages", ctx, bookingID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBookingMessages indicates an expected call of CountBookingMessages.
+func (mr *MockQuerierMockRecorder) CountBookingMessages(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBookingMessages", reflect.TypeOf((*MockQuerier)(nil).CountBookingMessages), ctx, bookingID)
+}
+
+// CountBookingsStartingInRange mocks base method.
+func (m *MockQuerier) CountBookingsStartingInRange(ctx context.Context, arg db.CountBookingsStartingInRangeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBookingsStartingInRange", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBookingsStartingInRange indicates an expected call of CountBookingsStartingInRange.
+func (mr *MockQuerierMockRecorder) CountBookingsStartingInRange(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBookingsStartingInRange", reflect.TypeOf((*MockQuerier)(nil).CountBookingsStartingInRange), ctx, arg)
+}
+
+// CountBookingsWithinBuffers mocks base method.
+func (m *MockQuerier) CountBookingsWithinBuffers(ctx context.Context, arg db.CountBookingsWithinBuffersParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBookingsWithinBuffers", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBookingsWithinBuffers indicates an expected call of CountBookingsWithinBuffers.
+func (mr *MockQuerierMockRecorder) CountBookingsWithinBuffers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBookingsWithinBuffers", reflect.TypeOf((*MockQuerier)(nil).CountBookingsWithinBuffers), ctx, arg)
+}
+
 // CountConflictingBookings mocks base method.
 func (m *MockQuerier) CountConflictingBookings(ctx context.Context, arg db.CountConflictingBookingsParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -250,6 +441,21 @@ func (mr *MockQuerierMockRecorder) CountConflictingBookings(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountConflictingBookings", reflect.TypeOf((*MockQuerier)(nil).CountConflictingBookings), ctx, arg)
 }
 
+// CountConflictingWaitlistHolds mocks base method.
+func (m *MockQuerier) CountConflictingWaitlistHolds(ctx context.Context, arg db.CountConflictingWaitlistHoldsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountConflictingWaitlistHolds", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountConflictingWaitlistHolds indicates an expected call of CountConflictingWaitlistHolds.
+func (mr *MockQuerierMockRecorder) CountConflictingWaitlistHolds(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountConflictingWaitlistHolds", reflect.TypeOf((*MockQuerier)(nil).CountConflictingWaitlistHolds), ctx, arg)
+}
+
 // CountFreshBookingParticipants mocks base method.
 func (m *MockQuerier) CountFreshBookingParticipants(ctx context.Context, arg db.CountFreshBookingParticipantsParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -280,6 +486,21 @@ func (mr *MockQuerierMockRecorder) CountSignupCodesByOwner(ctx, ownerUserID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSignupCodesByOwner", reflect.TypeOf((*MockQuerier)(nil).CountSignupCodesByOwner), ctx, ownerUserID)
 }
 
+// CountUnchainedAuditEvents mocks base method.
+func (m *MockQuerier) CountUnchainedAuditEvents(ctx context.Context, arg db.CountUnchainedAuditEventsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnchainedAuditEvents", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnchainedAuditEvents indicates an expected call of CountUnchainedAuditEvents.
+func (mr *MockQuerierMockRecorder) CountUnchainedAuditEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnchainedAuditEvents", reflect.TypeOf((*MockQuerier)(nil).CountUnchainedAuditEvents), ctx, arg)
+}
+
 // CountUnreadNotifications mocks base method.
 func (m *MockQuerier) CountUnreadNotifications(ctx context.Context, recipientID string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -310,6 +531,21 @@ func (mr *MockQuerierMockRecorder) CountVideosWithoutReviews(ctx, assetID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVideosWithoutReviews", reflect.TypeOf((*MockQuerier)(nil).CountVideosWithoutReviews), ctx, assetID)
 }
 
+// CreateAbsence mocks base method.
+func (m *MockQuerier) CreateAbsence(ctx context.Context, arg db.CreateAbsenceParams) (db.CoachingAbsence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAbsence", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingAbsence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAbsence indicates an expected call of CreateAbsence.
+func (mr *MockQuerierMockRecorder) CreateAbsence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAbsence", reflect.TypeOf((*MockQuerier)(nil).CreateAbsence), ctx, arg)
+}
+
 // CreateAsset mocks base method.
 func (m *MockQuerier) CreateAsset(ctx context.Context, arg db.CreateAssetParams) (db.Asset, error) {
 	m.ctrl.T.Helper()
@@ -354,6 +590,21 @@ func (mr *MockQuerierMockRecorder) CreateAvailability(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAvailability", reflect.TypeOf((*MockQuerier)(nil).CreateAvailability), ctx, arg)
 }
 
+// CreateAvailabilityOverride mocks base method.
+func (m *MockQuerier) CreateAvailabilityOverride(ctx context.Context, arg db.CreateAvailabilityOverrideParams) (db.CoachingAvailabilityOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAvailabilityOverride", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingAvailabilityOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAvailabilityOverride indicates an expected call of CreateAvailabilityOverride.
+func (mr *MockQuerierMockRecorder) CreateAvailabilityOverride(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAvailabilityOverride", reflect.TypeOf((*MockQuerier)(nil).CreateAvailabilityOverride), ctx, arg)
+}
+
 // CreateBlockedSlot mocks base method.
 func (m *MockQuerier) CreateBlockedSlot(ctx context.Context, arg db.CreateBlockedSlotParams) (db.CoachingBlockedSlot, error) {
 	m.ctrl.T.Helper()
@@ -384,6 +635,21 @@ func (mr *MockQuerierMockRecorder) CreateBooking(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBooking", reflect.TypeOf((*MockQuerier)(nil).CreateBooking), ctx, arg)
 }
 
+// CreateBookingMessage mocks base method.
+func (m *MockQuerier) CreateBookingMessage(ctx context.Context, arg db.CreateBookingMessageParams) (db.CoachingBookingMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookingMessage", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingBookingMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBookingMessage indicates an expected call of CreateBookingMessage.
+func (mr *MockQuerierMockRecorder) CreateBookingMessage(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingMessage", reflect.TypeOf((*MockQuerier)(nil).CreateBookingMessage), ctx, arg)
+}
+
 // CreateBookingReminder mocks base method.
 func (m *MockQuerier) CreateBookingReminder(ctx context.Context, arg db.CreateBookingReminderParams) error {
 	m.ctrl.T.Helper()
@@ -398,6 +664,111 @@ func (mr *MockQuerierMockRecorder) CreateBookingReminder(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingReminder", reflect.TypeOf((*MockQuerier)(nil).CreateBookingReminder), ctx, arg)
 }
 
+// CreateBookingReschedule mocks base method.
+func (m *MockQuerier) CreateBookingReschedule(ctx context.Context, arg db.CreateBookingRescheduleParams) (db.CoachingBookingReschedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookingReschedule", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingBookingReschedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBookingReschedule indicates an expected call of CreateBookingReschedule.
+func (mr *MockQuerierMockRecorder) CreateBookingReschedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingReschedule", reflect.TypeOf((*MockQuerier)(nil).CreateBookingReschedule), ctx, arg)
+}
+
+// CreateBookingSeries mocks base method.
+func (m *MockQuerier) CreateBookingSeries(ctx context.Context, arg db.CreateBookingSeriesParams) (db.CoachingBookingSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookingSeries", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingBookingSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBookingSeries indicates an expected call of CreateBookingSeries.
+func (mr *MockQuerierMockRecorder) CreateBookingSeries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingSeries", reflect.TypeOf((*MockQuerier)(nil).CreateBookingSeries), ctx, arg)
+}
+
+// CreateClass mocks base method.
+func (m *MockQuerier) CreateClass(ctx context.Context, arg db.CreateClassParams) (db.CoachingClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClass", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClass indicates an expected call of CreateClass.
+func (mr *MockQuerierMockRecorder) CreateClass(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClass", reflect.TypeOf((*MockQuerier)(nil).CreateClass), ctx, arg)
+}
+
+// CreateCoachingPayment mocks base method.
+func (m *MockQuerier) CreateCoachingPayment(ctx context.Context, arg db.CreateCoachingPaymentParams) (db.CoachingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoachingPayment", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCoachingPayment indicates an expected call of CreateCoachingPayment.
+func (mr *MockQuerierMockRecorder) CreateCoachingPayment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoachingPayment", reflect.TypeOf((*MockQuerier)(nil).CreateCoachingPayment), ctx, arg)
+}
+
+// CreateCreditGrant mocks base method.
+func (m *MockQuerier) CreateCreditGrant(ctx context.Context, arg db.CreateCreditGrantParams) (db.CoachingCreditGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditGrant", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingCreditGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditGrant indicates an expected call of CreateCreditGrant.
+func (mr *MockQuerierMockRecorder) CreateCreditGrant(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditGrant", reflect.TypeOf((*MockQuerier)(nil).CreateCreditGrant), ctx, arg)
+}
+
+// CreateCreditLedgerEntry mocks base method.
+func (m *MockQuerier) CreateCreditLedgerEntry(ctx context.Context, arg db.CreateCreditLedgerEntryParams) (db.CoachingCreditLedger, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditLedgerEntry", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingCreditLedger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditLedgerEntry indicates an expected call of CreateCreditLedgerEntry.
+func (mr *MockQuerierMockRecorder) CreateCreditLedgerEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditLedgerEntry", reflect.TypeOf((*MockQuerier)(nil).CreateCreditLedgerEntry), ctx, arg)
+}
+
+// CreateExternalCalendar mocks base method.
+func (m *MockQuerier) CreateExternalCalendar(ctx context.Context, arg db.CreateExternalCalendarParams) (db.CoachingExternalCalendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalCalendar", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingExternalCalendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalCalendar indicates an expected call of CreateExternalCalendar.
+func (mr *MockQuerierMockRecorder) CreateExternalCalendar(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalCalendar", reflect.TypeOf((*MockQuerier)(nil).CreateExternalCalendar), ctx, arg)
+}
+
 // CreateFeedbackSubmission mocks base method.
 func (m *MockQuerier) CreateFeedbackSubmission(ctx context.Context, arg db.CreateFeedbackSubmissionParams) (db.FeedbackSubmission, error) {
 	m.ctrl.T.Helper()
@@ -443,6 +814,35 @@ func (mr *MockQuerierMockRecorder) CreateGroupInvitation(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupInvitation", reflect.TypeOf((*MockQuerier)(nil).CreateGroupInvitation), ctx, arg)
 }
 
+// CreateHomework mocks base method.
+func (m *MockQuerier) CreateHomework(ctx context.Context, arg db.CreateHomeworkParams) (db.CoachingHomework, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHomework", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingHomework)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHomework indicates an expected call of CreateHomework.
+func (mr *MockQuerierMockRecorder) CreateHomework(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHomework", reflect.TypeOf((*MockQuerier)(nil).CreateHomework), ctx, arg)
+}
+
+// CreateHomeworkReminder mocks base method.
+func (m *MockQuerier) CreateHomeworkReminder(ctx context.Context, arg db.CreateHomeworkReminderParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHomeworkReminder", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHomeworkReminder indicates an expected call of CreateHomeworkReminder.
+func (mr *MockQuerierMockRecorder) CreateHomeworkReminder(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHomeworkReminder", reflect.TypeOf((*MockQuerier)(nil).CreateHomeworkReminder), ctx, arg)
+}
+
 // CreateInboundEmailReply mocks base method.
 func (m *MockQuerier) CreateInboundEmailReply(ctx context.Context, arg db.CreateInboundEmailReplyParams) (db.InboundEmailReply, error) {
 	m.ctrl.T.Helper()
@@ -548,6 +948,21 @@ func (mr *MockQuerierMockRecorder) CreateSignupCodeWithinLimit(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSignupCodeWithinLimit", reflect.TypeOf((*MockQuerier)(nil).CreateSignupCodeWithinLimit), ctx, arg)
 }
 
+// CreateSnippet mocks base method.
+func (m *MockQuerier) CreateSnippet(ctx context.Context, arg db.CreateSnippetParams) (db.ReviewSnippet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnippet", ctx, arg)
+	ret0, _ := ret[0].(db.ReviewSnippet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnippet indicates an expected call of CreateSnippet.
+func (mr *MockQuerierMockRecorder) CreateSnippet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnippet", reflect.TypeOf((*MockQuerier)(nil).CreateSnippet), ctx, arg)
+}
+
 // CreateVideo mocks base method.
 func (m *MockQuerier) CreateVideo(ctx context.Context, arg db.CreateVideoParams) (db.Video, error) {
 	m.ctrl.T.Helper()
@@ -563,6 +978,21 @@ func (mr *MockQuerierMockRecorder) CreateVideo(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVideo", reflect.TypeOf((*MockQuerier)(nil).CreateVideo), ctx, arg)
 }
 
+// CreateVideoChapter mocks base method.
+func (m *MockQuerier) CreateVideoChapter(ctx context.Context, arg db.CreateVideoChapterParams) (db.VideoChapter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVideoChapter", ctx, arg)
+	ret0, _ := ret[0].(db.VideoChapter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVideoChapter indicates an expected call of CreateVideoChapter.
+func (mr *MockQuerierMockRecorder) CreateVideoChapter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVideoChapter", reflect.TypeOf((*MockQuerier)(nil).CreateVideoChapter), ctx, arg)
+}
+
 // CreateVideoFromMuxAsset mocks base method.
 func (m *MockQuerier) CreateVideoFromMuxAsset(ctx context.Context, arg db.CreateVideoFromMuxAssetParams) (db.Video, error) {
 	m.ctrl.T.Helper()
@@ -593,38 +1023,112 @@ func (mr *MockQuerierMockRecorder) CreateVideoReview(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVideoReview", reflect.TypeOf((*MockQuerier)(nil).CreateVideoReview), ctx, arg)
 }
 
-// DeactivateSessionType mocks base method.
-func (m *MockQuerier) DeactivateSessionType(ctx context.Context, arg db.DeactivateSessionTypeParams) (int64, error) {
+// CreateWaitlistEntry mocks base method.
+func (m *MockQuerier) CreateWaitlistEntry(ctx context.Context, arg db.CreateWaitlistEntryParams) (db.CoachingWaitlistEntry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeactivateSessionType", ctx, arg)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "CreateWaitlistEntry", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingWaitlistEntry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeactivateSessionType indicates an expected call of DeactivateSessionType.
-func (mr *MockQuerierMockRecorder) DeactivateSessionType(ctx, arg any) *gomock.Call {
+// CreateWaitlistEntry indicates an expected call of CreateWaitlistEntry.
+func (mr *MockQuerierMockRecorder) CreateWaitlistEntry(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateSessionType", reflect.TypeOf((*MockQuerier)(nil).DeactivateSessionType), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWaitlistEntry", reflect.TypeOf((*MockQuerier)(nil).CreateWaitlistEntry), ctx, arg)
 }
 
-// DeleteAvailability mocks base method.
-func (m *MockQuerier) DeleteAvailability(ctx context.Context, arg db.DeleteAvailabilityParams) (int64, error) {
+// CreateWaitlistHold mocks base method.
+func (m *MockQuerier) CreateWaitlistHold(ctx context.Context, arg db.CreateWaitlistHoldParams) (db.CoachingWaitlistHold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAvailability", ctx, arg)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "CreateWaitlistHold", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingWaitlistHold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteAvailability indicates an expected call of DeleteAvailability.
-func (mr *MockQuerierMockRecorder) DeleteAvailability(ctx, arg any) *gomock.Call {
+// CreateWaitlistHold indicates an expected call of CreateWaitlistHold.
+func (mr *MockQuerierMockRecorder) CreateWaitlistHold(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailability", reflect.TypeOf((*MockQuerier)(nil).DeleteAvailability), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWaitlistHold", reflect.TypeOf((*MockQuerier)(nil).CreateWaitlistHold), ctx, arg)
 }
 
-// DeleteBlockedSlot mocks base method.
-func (m *MockQuerier) DeleteBlockedSlot(ctx context.Context, arg db.DeleteBlockedSlotParams) (int64, error) {
+// DeactivateSessionType mocks base method.
+func (m *MockQuerier) DeactivateSessionType(ctx context.Context, arg db.DeactivateSessionTypeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateSessionType", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateSessionType indicates an expected call of DeactivateSessionType.
+func (mr *MockQuerierMockRecorder) DeactivateSessionType(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateSessionType", reflect.TypeOf((*MockQuerier)(nil).DeactivateSessionType), ctx, arg)
+}
+
+// DeleteAbsence mocks base method.
+func (m *MockQuerier) DeleteAbsence(ctx context.Context, arg db.DeleteAbsenceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAbsence", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAbsence indicates an expected call of DeleteAbsence.
+func (mr *MockQuerierMockRecorder) DeleteAbsence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAbsence", reflect.TypeOf((*MockQuerier)(nil).DeleteAbsence), ctx, arg)
+}
+
+// DeleteAssetNotifications mocks base method.
+func (m *MockQuerier) DeleteAssetNotifications(ctx context.Context, assetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAssetNotifications", ctx, assetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAssetNotifications indicates an expected call of DeleteAssetNotifications.
+func (mr *MockQuerierMockRecorder) DeleteAssetNotifications(ctx, assetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAssetNotifications", reflect.TypeOf((*MockQuerier)(nil).DeleteAssetNotifications), ctx, assetID)
+}
+
+// DeleteAvailability mocks base method.
+func (m *MockQuerier) DeleteAvailability(ctx context.Context, arg db.DeleteAvailabilityParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailability", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAvailability indicates an expected call of DeleteAvailability.
+func (mr *MockQuerierMockRecorder) DeleteAvailability(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailability", reflect.TypeOf((*MockQuerier)(nil).DeleteAvailability), ctx, arg)
+}
+
+// DeleteAvailabilityOverride mocks base method.
+func (m *MockQuerier) DeleteAvailabilityOverride(ctx context.Context, arg db.DeleteAvailabilityOverrideParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailabilityOverride", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAvailabilityOverride indicates an expected call of DeleteAvailabilityOverride.
+func (mr *MockQuerierMockRecorder) DeleteAvailabilityOverride(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailabilityOverride", reflect.TypeOf((*MockQuerier)(nil).DeleteAvailabilityOverride), ctx, arg)
+}
+
+// DeleteBlockedSlot mocks base method.
+func (m *MockQuerier) DeleteBlockedSlot(ctx context.Context, arg db.DeleteBlockedSlotParams) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteBlockedSlot", ctx, arg)
 	ret0, _ := ret[0].(int64)
@@ -638,6 +1142,21 @@ func (mr *MockQuerierMockRecorder) DeleteBlockedSlot(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlockedSlot", reflect.TypeOf((*MockQuerier)(nil).DeleteBlockedSlot), ctx, arg)
 }
 
+// DeleteCalendarFeed mocks base method.
+func (m *MockQuerier) DeleteCalendarFeed(ctx context.Context, userID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendarFeed", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCalendarFeed indicates an expected call of DeleteCalendarFeed.
+func (mr *MockQuerierMockRecorder) DeleteCalendarFeed(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendarFeed", reflect.TypeOf((*MockQuerier)(nil).DeleteCalendarFeed), ctx, userID)
+}
+
 // DeleteDevice mocks base method.
 func (m *MockQuerier) DeleteDevice(ctx context.Context, arg db.DeleteDeviceParams) error {
 	m.ctrl.T.Helper()
@@ -666,6 +1185,35 @@ func (mr *MockQuerierMockRecorder) DeleteDeviceByToken(ctx, expoPushToken any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeviceByToken", reflect.TypeOf((*MockQuerier)(nil).DeleteDeviceByToken), ctx, expoPushToken)
 }
 
+// DeleteExternalBusyBlocks mocks base method.
+func (m *MockQuerier) DeleteExternalBusyBlocks(ctx context.Context, calendarID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExternalBusyBlocks", ctx, calendarID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExternalBusyBlocks indicates an expected call of DeleteExternalBusyBlocks.
+func (mr *MockQuerierMockRecorder) DeleteExternalBusyBlocks(ctx, calendarID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExternalBusyBlocks", reflect.TypeOf((*MockQuerier)(nil).DeleteExternalBusyBlocks), ctx, calendarID)
+}
+
+// DeleteExternalCalendar mocks base method.
+func (m *MockQuerier) DeleteExternalCalendar(ctx context.Context, arg db.DeleteExternalCalendarParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExternalCalendar", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExternalCalendar indicates an expected call of DeleteExternalCalendar.
+func (mr *MockQuerierMockRecorder) DeleteExternalCalendar(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExternalCalendar", reflect.TypeOf((*MockQuerier)(nil).DeleteExternalCalendar), ctx, arg)
+}
+
 // DeleteGroup mocks base method.
 func (m *MockQuerier) DeleteGroup(ctx context.Context, arg db.DeleteGroupParams) error {
 	m.ctrl.T.Helper()
@@ -680,6 +1228,92 @@ func (mr *MockQuerierMockRecorder) DeleteGroup(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockQuerier)(nil).DeleteGroup), ctx, arg)
 }
 
+// DeleteHomework mocks base method.
+func (m *MockQuerier) DeleteHomework(ctx context.Context, id pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHomework", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHomework indicates an expected call of DeleteHomework.
+func (mr *MockQuerierMockRecorder) DeleteHomework(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHomework", reflect.TypeOf((*MockQuerier)(nil).DeleteHomework), ctx, id)
+}
+
+// DeleteSnippet mocks base method.
+func (m *MockQuerier) DeleteSnippet(ctx context.Context, arg db.DeleteSnippetParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnippet", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSnippet indicates an expected call of DeleteSnippet.
+func (mr *MockQuerierMockRecorder) DeleteSnippet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnippet", reflect.TypeOf((*MockQuerier)(nil).DeleteSnippet), ctx, arg)
+}
+
+// DeleteUnsentBookingReminders mocks base method.
+func (m *MockQuerier) DeleteUnsentBookingReminders(ctx context.Context, bookingID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnsentBookingReminders", ctx, bookingID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnsentBookingReminders indicates an expected call of DeleteUnsentBookingReminders.
+func (mr *MockQuerierMockRecorder) DeleteUnsentBookingReminders(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnsentBookingReminders", reflect.TypeOf((*MockQuerier)(nil).DeleteUnsentBookingReminders), ctx, bookingID)
+}
+
+// DeleteUnsentHomeworkReminders mocks base method.
+func (m *MockQuerier) DeleteUnsentHomeworkReminders(ctx context.Context, homeworkID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnsentHomeworkReminders", ctx, homeworkID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnsentHomeworkReminders indicates an expected call of DeleteUnsentHomeworkReminders.
+func (mr *MockQuerierMockRecorder) DeleteUnsentHomeworkReminders(ctx, homeworkID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnsentHomeworkReminders", reflect.TypeOf((*MockQuerier)(nil).DeleteUnsentHomeworkReminders), ctx, homeworkID)
+}
+
+// DeleteVideoChapter mocks base method.
+func (m *MockQuerier) DeleteVideoChapter(ctx context.Context, arg db.DeleteVideoChapterParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVideoChapter", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVideoChapter indicates an expected call of DeleteVideoChapter.
+func (mr *MockQuerierMockRecorder) DeleteVideoChapter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVideoChapter", reflect.TypeOf((*MockQuerier)(nil).DeleteVideoChapter), ctx, arg)
+}
+
+// DeleteVideoNotifications mocks base method.
+func (m *MockQuerier) DeleteVideoNotifications(ctx context.Context, videoID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVideoNotifications", ctx, videoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVideoNotifications indicates an expected call of DeleteVideoNotifications.
+func (mr *MockQuerierMockRecorder) DeleteVideoNotifications(ctx, videoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVideoNotifications", reflect.TypeOf((*MockQuerier)(nil).DeleteVideoNotifications), ctx, videoID)
+}
+
 // DeleteVideoReview mocks base method.
 func (m *MockQuerier) DeleteVideoReview(ctx context.Context, arg db.DeleteVideoReviewParams) error {
 	m.ctrl.T.Helper()
@@ -694,6 +1328,50 @@ func (mr *MockQuerierMockRecorder) DeleteVideoReview(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVideoReview", reflect.TypeOf((*MockQuerier)(nil).DeleteVideoReview), ctx, arg)
 }
 
+// DeleteWaitlistEntry mocks base method.
+func (m *MockQuerier) DeleteWaitlistEntry(ctx context.Context, arg db.DeleteWaitlistEntryParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWaitlistEntry", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWaitlistEntry indicates an expected call of DeleteWaitlistEntry.
+func (mr *MockQuerierMockRecorder) DeleteWaitlistEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWaitlistEntry", reflect.TypeOf((*MockQuerier)(nil).DeleteWaitlistEntry), ctx, arg)
+}
+
+// EnqueueRecordingStitches mocks base method.
+func (m *MockQuerier) EnqueueRecordingStitches(ctx context.Context, endGraceSeconds int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueRecordingStitches", ctx, endGraceSeconds)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueRecordingStitches indicates an expected call of EnqueueRecordingStitches.
+func (mr *MockQuerierMockRecorder) EnqueueRecordingStitches(ctx, endGraceSeconds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueRecordingStitches", reflect.TypeOf((*MockQuerier)(nil).EnqueueRecordingStitches), ctx, endGraceSeconds)
+}
+
+// EnsureAuditChainHead mocks base method.
+func (m *MockQuerier) EnsureAuditChainHead(ctx context.Context, shard int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureAuditChainHead", ctx, shard)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureAuditChainHead indicates an expected call of EnsureAuditChainHead.
+func (mr *MockQuerierMockRecorder) EnsureAuditChainHead(ctx, shard any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureAuditChainHead", reflect.TypeOf((*MockQuerier)(nil).EnsureAuditChainHead), ctx, shard)
+}
+
 // EnsureRecordingPartImport mocks base method.
 func (m *MockQuerier) EnsureRecordingPartImport(ctx context.Context, arg db.EnsureRecordingPartImportParams) (db.CoachingRecordingImport, error) {
 	m.ctrl.T.Helper()
@@ -739,6 +1417,66 @@ func (mr *MockQuerierMockRecorder) ExchangeRecordingRendererCapability(ctx, rend
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeRecordingRendererCapability", reflect.TypeOf((*MockQuerier)(nil).ExchangeRecordingRendererCapability), ctx, rendererTokenHash)
 }
 
+// ExpireBookingPayment mocks base method.
+func (m *MockQuerier) ExpireBookingPayment(ctx context.Context, id pgtype.UUID) (db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBookingPayment", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireBookingPayment indicates an expected call of ExpireBookingPayment.
+func (mr *MockQuerierMockRecorder) ExpireBookingPayment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBookingPayment", reflect.TypeOf((*MockQuerier)(nil).ExpireBookingPayment), ctx, id)
+}
+
+// ExpireCreditGrant mocks base method.
+func (m *MockQuerier) ExpireCreditGrant(ctx context.Context, id pgtype.UUID) (db.CoachingCreditGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireCreditGrant", ctx, id)
+	ret0, _ := ret[0].(db.CoachingCreditGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireCreditGrant indicates an expected call of ExpireCreditGrant.
+func (mr *MockQuerierMockRecorder) ExpireCreditGrant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireCreditGrant", reflect.TypeOf((*MockQuerier)(nil).ExpireCreditGrant), ctx, id)
+}
+
+// ExpireWaitlistHolds mocks base method.
+func (m *MockQuerier) ExpireWaitlistHolds(ctx context.Context) ([]db.CoachingWaitlistHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireWaitlistHolds", ctx)
+	ret0, _ := ret[0].([]db.CoachingWaitlistHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireWaitlistHolds indicates an expected call of ExpireWaitlistHolds.
+func (mr *MockQuerierMockRecorder) ExpireWaitlistHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireWaitlistHolds", reflect.TypeOf((*MockQuerier)(nil).ExpireWaitlistHolds), ctx)
+}
+
+// ExportAuditEvents mocks base method.
+func (m *MockQuerier) ExportAuditEvents(ctx context.Context, arg db.ExportAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAuditEvents", ctx, arg)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportAuditEvents indicates an expected call of ExportAuditEvents.
+func (mr *MockQuerierMockRecorder) ExportAuditEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAuditEvents", reflect.TypeOf((*MockQuerier)(nil).ExportAuditEvents), ctx, arg)
+}
+
 // GetActiveRecordingPart mocks base method.
 func (m *MockQuerier) GetActiveRecordingPart(ctx context.Context, bookingID pgtype.UUID) (db.CoachingBookingRecording, error) {
 	m.ctrl.T.Helper()
@@ -844,6 +1582,36 @@ func (mr *MockQuerierMockRecorder) GetBooking(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooking", reflect.TypeOf((*MockQuerier)(nil).GetBooking), ctx, arg)
 }
 
+// GetBookingForMessageUpdate mocks base method.
+func (m *MockQuerier) GetBookingForMessageUpdate(ctx context.Context, id pgtype.UUID) (db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookingForMessageUpdate", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookingForMessageUpdate indicates an expected call of GetBookingForMessageUpdate.
+func (mr *MockQuerierMockRecorder) GetBookingForMessageUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingForMessageUpdate", reflect.TypeOf((*MockQuerier)(nil).GetBookingForMessageUpdate), ctx, id)
+}
+
+// GetBookingForPaymentUpdate mocks base method.
+func (m *MockQuerier) GetBookingForPaymentUpdate(ctx context.Context, id pgtype.UUID) (db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookingForPaymentUpdate", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookingForPaymentUpdate indicates an expected call of GetBookingForPaymentUpdate.
+func (mr *MockQuerierMockRecorder) GetBookingForPaymentUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingForPaymentUpdate", reflect.TypeOf((*MockQuerier)(nil).GetBookingForPaymentUpdate), ctx, id)
+}
+
 // GetBookingForRecordingAssetUpdate mocks base method.
 func (m *MockQuerier) GetBookingForRecordingAssetUpdate(ctx context.Context, id pgtype.UUID) (db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
@@ -859,98 +1627,278 @@ func (mr *MockQuerierMockRecorder) GetBookingForRecordingAssetUpdate(ctx, id any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingForRecordingAssetUpdate", reflect.TypeOf((*MockQuerier)(nil).GetBookingForRecordingAssetUpdate), ctx, id)
 }
 
-// GetGroup mocks base method.
-func (m *MockQuerier) GetGroup(ctx context.Context, id pgtype.UUID) (db.Group, error) {
+// GetBookingMessage mocks base method.
+func (m *MockQuerier) GetBookingMessage(ctx context.Context, id pgtype.UUID) (db.CoachingBookingMessage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGroup", ctx, id)
-	ret0, _ := ret[0].(db.Group)
+	ret := m.ctrl.Call(m, "GetBookingMessage", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBookingMessage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGroup indicates an expected call of GetGroup.
-func (mr *MockQuerierMockRecorder) GetGroup(ctx, id any) *gomock.Call {
+// GetBookingMessage indicates an expected call of GetBookingMessage.
+func (mr *MockQuerierMockRecorder) GetBookingMessage(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockQuerier)(nil).GetGroup), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingMessage", reflect.TypeOf((*MockQuerier)(nil).GetBookingMessage), ctx, id)
 }
 
-// GetGroupInvitationByCode mocks base method.
-func (m *MockQuerier) GetGroupInvitationByCode(ctx context.Context, code string) (db.GroupInvitation, error) {
+// GetCalendarFeed mocks base method.
+func (m *MockQuerier) GetCalendarFeed(ctx context.Context, userID string) (db.CoachingCalendarFeed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGroupInvitationByCode", ctx, code)
-	ret0, _ := ret[0].(db.GroupInvitation)
+	ret := m.ctrl.Call(m, "GetCalendarFeed", ctx, userID)
+	ret0, _ := ret[0].(db.CoachingCalendarFeed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGroupInvitationByCode indicates an expected call of GetGroupInvitationByCode.
-func (mr *MockQuerierMockRecorder) GetGroupInvitationByCode(ctx, code any) *gomock.Call {
+// GetCalendarFeed indicates an expected call of GetCalendarFeed.
+func (mr *MockQuerierMockRecorder) GetCalendarFeed(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitationByCode", reflect.TypeOf((*MockQuerier)(nil).GetGroupInvitationByCode), ctx, code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarFeed", reflect.TypeOf((*MockQuerier)(nil).GetCalendarFeed), ctx, userID)
 }
 
-// GetGroupInvitationByID mocks base method.
-func (m *MockQuerier) GetGroupInvitationByID(ctx context.Context, arg db.GetGroupInvitationByIDParams) (db.GroupInvitation, error) {
+// GetClassForSlot mocks base method.
+func (m *MockQuerier) GetClassForSlot(ctx context.Context, arg db.GetClassForSlotParams) (db.CoachingClass, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGroupInvitationByID", ctx, arg)
-	ret0, _ := ret[0].(db.GroupInvitation)
+	ret := m.ctrl.Call(m, "GetClassForSlot", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingClass)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGroupInvitationByID indicates an expected call of GetGroupInvitationByID.
-func (mr *MockQuerierMockRecorder) GetGroupInvitationByID(ctx, arg any) *gomock.Call {
+// GetClassForSlot indicates an expected call of GetClassForSlot.
+func (mr *MockQuerierMockRecorder) GetClassForSlot(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitationByID", reflect.TypeOf((*MockQuerier)(nil).GetGroupInvitationByID), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClassForSlot", reflect.TypeOf((*MockQuerier)(nil).GetClassForSlot), ctx, arg)
 }
 
-// GetGroupInvitationsByCodes mocks base method.
-func (m *MockQuerier) GetGroupInvitationsByCodes(ctx context.Context, dollar_1 []string) ([]db.GroupInvitation, error) {
+// GetClassHostBooking mocks base method.
+func (m *MockQuerier) GetClassHostBooking(ctx context.Context, classID pgtype.UUID) (db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGroupInvitationsByCodes", ctx, dollar_1)
-	ret0, _ := ret[0].([]db.GroupInvitation)
+	ret := m.ctrl.Call(m, "GetClassHostBooking", ctx, classID)
+	ret0, _ := ret[0].(db.CoachingBooking)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGroupInvitationsByCodes indicates an expected call of GetGroupInvitationsByCodes.
-func (mr *MockQuerierMockRecorder) GetGroupInvitationsByCodes(ctx, dollar_1 any) *gomock.Call {
+// GetClassHostBooking indicates an expected call of GetClassHostBooking.
+func (mr *MockQuerierMockRecorder) GetClassHostBooking(ctx, classID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitationsByCodes", reflect.TypeOf((*MockQuerier)(nil).GetGroupInvitationsByCodes), ctx, dollar_1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClassHostBooking", reflect.TypeOf((*MockQuerier)(nil).GetClassHostBooking), ctx, classID)
 }
 
-// GetModerationReport mocks base method.
-func (m *MockQuerier) GetModerationReport(ctx context.Context, id pgtype.UUID) (db.ModerationReport, error) {
+// GetClassSeats mocks base method.
+func (m *MockQuerier) GetClassSeats(ctx context.Context, arg db.GetClassSeatsParams) (db.GetClassSeatsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModerationReport", ctx, id)
-	ret0, _ := ret[0].(db.ModerationReport)
+	ret := m.ctrl.Call(m, "GetClassSeats", ctx, arg)
+	ret0, _ := ret[0].(db.GetClassSeatsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetModerationReport indicates an expected call of GetModerationReport.
-func (mr *MockQuerierMockRecorder) GetModerationReport(ctx, id any) *gomock.Call {
+// GetClassSeats indicates an expected call of GetClassSeats.
+func (mr *MockQuerierMockRecorder) GetClassSeats(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationReport", reflect.TypeOf((*MockQuerier)(nil).GetModerationReport), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClassSeats", reflect.TypeOf((*MockQuerier)(nil).GetClassSeats), ctx, arg)
 }
 
-// GetNotification mocks base method.
-func (m *MockQuerier) GetNotification(ctx context.Context, id pgtype.UUID) (db.Notification, error) {
+// GetCoachingPayment mocks base method.
+func (m *MockQuerier) GetCoachingPayment(ctx context.Context, bookingID pgtype.UUID) (db.CoachingPayment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNotification", ctx, id)
-	ret0, _ := ret[0].(db.Notification)
+	ret := m.ctrl.Call(m, "GetCoachingPayment", ctx, bookingID)
+	ret0, _ := ret[0].(db.CoachingPayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetNotification indicates an expected call of GetNotification.
-func (mr *MockQuerierMockRecorder) GetNotification(ctx, id any) *gomock.Call {
+// GetCoachingPayment indicates an expected call of GetCoachingPayment.
+func (mr *MockQuerierMockRecorder) GetCoachingPayment(ctx, bookingID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockQuerier)(nil).GetNotification), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoachingPayment", reflect.TypeOf((*MockQuerier)(nil).GetCoachingPayment), ctx, bookingID)
 }
 
-// GetReviewModerationTarget mocks base method.
-func (m *MockQuerier) GetReviewModerationTarget(ctx context.Context, id pgtype.UUID) (db.GetReviewModerationTargetRow, error) {
+// GetCoachingPaymentByCheckout mocks base method.
+func (m *MockQuerier) GetCoachingPaymentByCheckout(ctx context.Context, arg db.GetCoachingPaymentByCheckoutParams) (db.CoachingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoachingPaymentByCheckout", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoachingPaymentByCheckout indicates an expected call of GetCoachingPaymentByCheckout.
+func (mr *MockQuerierMockRecorder) GetCoachingPaymentByCheckout(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoachingPaymentByCheckout", reflect.TypeOf((*MockQuerier)(nil).GetCoachingPaymentByCheckout), ctx, arg)
+}
+
+// GetExternalCalendar mocks base method.
+func (m *MockQuerier) GetExternalCalendar(ctx context.Context, arg db.GetExternalCalendarParams) (db.CoachingExternalCalendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalCalendar", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingExternalCalendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalCalendar indicates an expected call of GetExternalCalendar.
+func (mr *MockQuerierMockRecorder) GetExternalCalendar(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalCalendar", reflect.TypeOf((*MockQuerier)(nil).GetExternalCalendar), ctx, arg)
+}
+
+// GetGroup mocks base method.
+func (m *MockQuerier) GetGroup(ctx context.Context, id pgtype.UUID) (db.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", ctx, id)
+	ret0, _ := ret[0].(db.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockQuerierMockRecorder) GetGroup(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockQuerier)(nil).GetGroup), ctx, id)
+}
+
+// GetGroupInvitationByCode mocks base method.
+func (m *MockQuerier) GetGroupInvitationByCode(ctx context.Context, code string) (db.GroupInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupInvitationByCode", ctx, code)
+	ret0, _ := ret[0].(db.GroupInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupInvitationByCode indicates an expected call of GetGroupInvitationByCode.
+func (mr *MockQuerierMockRecorder) GetGroupInvitationByCode(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitationByCode", reflect.TypeOf((*MockQuerier)(nil).GetGroupInvitationByCode), ctx, code)
+}
+
+// GetGroupInvitationByID mocks base method.
+func (m *MockQuerier) GetGroupInvitationByID(ctx context.Context, arg db.GetGroupInvitationByIDParams) (db.GroupInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupInvitationByID", ctx, arg)
+	ret0, _ := ret[0].(db.GroupInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupInvitationByID indicates an expected call of GetGroupInvitationByID.
+func (mr *MockQuerierMockRecorder) GetGroupInvitationByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitationByID", reflect.TypeOf((*MockQuerier)(nil).GetGroupInvitationByID), ctx, arg)
+}
+
+// GetGroupInvitationsByCodes mocks base method.
+func (m *MockQuerier) GetGroupInvitationsByCodes(ctx context.Context, dollar_1 []string) ([]db.GroupInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupInvitationsByCodes", ctx, dollar_1)
+	ret0, _ := ret[0].([]db.GroupInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupInvitationsByCodes indicates an expected call of GetGroupInvitationsByCodes.
+func (mr *MockQuerierMockRecorder) GetGroupInvitationsByCodes(ctx, dollar_1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitationsByCodes", reflect.TypeOf((*MockQuerier)(nil).GetGroupInvitationsByCodes), ctx, dollar_1)
+}
+
+// GetHomework mocks base method.
+func (m *MockQuerier) GetHomework(ctx context.Context, arg db.GetHomeworkParams) (db.CoachingHomework, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHomework", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingHomework)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHomework indicates an expected call of GetHomework.
+func (mr *MockQuerierMockRecorder) GetHomework(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHomework", reflect.TypeOf((*MockQuerier)(nil).GetHomework), ctx, arg)
+}
+
+// GetModerationReport mocks base method.
+func (m *MockQuerier) GetModerationReport(ctx context.Context, id pgtype.UUID) (db.ModerationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationReport", ctx, id)
+	ret0, _ := ret[0].(db.ModerationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationReport indicates an expected call of GetModerationReport.
+func (mr *MockQuerierMockRecorder) GetModerationReport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationReport", reflect.TypeOf((*MockQuerier)(nil).GetModerationReport), ctx, id)
+}
+
+// GetNotification mocks base method.
+func (m *MockQuerier) GetNotification(ctx context.Context, id pgtype.UUID) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotification", ctx, id)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotification indicates an expected call of GetNotification.
+func (mr *MockQuerierMockRecorder) GetNotification(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockQuerier)(nil).GetNotification), ctx, id)
+}
+
+// GetOfferedWaitlistHold mocks base method.
+func (m *MockQuerier) GetOfferedWaitlistHold(ctx context.Context, entryID pgtype.UUID) (db.CoachingWaitlistHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOfferedWaitlistHold", ctx, entryID)
+	ret0, _ := ret[0].(db.CoachingWaitlistHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOfferedWaitlistHold indicates an expected call of GetOfferedWaitlistHold.
+func (mr *MockQuerierMockRecorder) GetOfferedWaitlistHold(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOfferedWaitlistHold", reflect.TypeOf((*MockQuerier)(nil).GetOfferedWaitlistHold), ctx, entryID)
+}
+
+// GetPendingBookingReschedule mocks base method.
+func (m *MockQuerier) GetPendingBookingReschedule(ctx context.Context, bookingID pgtype.UUID) (db.CoachingBookingReschedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingBookingReschedule", ctx, bookingID)
+	ret0, _ := ret[0].(db.CoachingBookingReschedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingBookingReschedule indicates an expected call of GetPendingBookingReschedule.
+func (mr *MockQuerierMockRecorder) GetPendingBookingReschedule(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingBookingReschedule", reflect.TypeOf((*MockQuerier)(nil).GetPendingBookingReschedule), ctx, bookingID)
+}
+
+// GetRecordingStitch mocks base method.
+func (m *MockQuerier) GetRecordingStitch(ctx context.Context, bookingID pgtype.UUID) (db.CoachingRecordingStitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordingStitch", ctx, bookingID)
+	ret0, _ := ret[0].(db.CoachingRecordingStitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordingStitch indicates an expected call of GetRecordingStitch.
+func (mr *MockQuerierMockRecorder) GetRecordingStitch(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordingStitch", reflect.TypeOf((*MockQuerier)(nil).GetRecordingStitch), ctx, bookingID)
+}
+
+// GetReviewModerationTarget mocks base method.
+func (m *MockQuerier) GetReviewModerationTarget(ctx context.Context, id pgtype.UUID) (db.GetReviewModerationTargetRow, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetReviewModerationTarget", ctx, id)
 	ret0, _ := ret[0].(db.GetReviewModerationTargetRow)
@@ -964,6 +1912,21 @@ func (mr *MockQuerierMockRecorder) GetReviewModerationTarget(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewModerationTarget", reflect.TypeOf((*MockQuerier)(nil).GetReviewModerationTarget), ctx, id)
 }
 
+// GetSessionNotes mocks base method.
+func (m *MockQuerier) GetSessionNotes(ctx context.Context, bookingID pgtype.UUID) (db.CoachingSessionNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionNotes", ctx, bookingID)
+	ret0, _ := ret[0].(db.CoachingSessionNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionNotes indicates an expected call of GetSessionNotes.
+func (mr *MockQuerierMockRecorder) GetSessionNotes(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionNotes", reflect.TypeOf((*MockQuerier)(nil).GetSessionNotes), ctx, bookingID)
+}
+
 // GetSessionType mocks base method.
 func (m *MockQuerier) GetSessionType(ctx context.Context, arg db.GetSessionTypeParams) (db.CoachingSessionType, error) {
 	m.ctrl.T.Helper()
@@ -1054,11 +2017,41 @@ func (mr *MockQuerierMockRecorder) GetUserTimezone(ctx, userID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTimezone", reflect.TypeOf((*MockQuerier)(nil).GetUserTimezone), ctx, userID)
 }
 
+// GetVideoChapter mocks base method.
+func (m *MockQuerier) GetVideoChapter(ctx context.Context, arg db.GetVideoChapterParams) (db.VideoChapter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoChapter", ctx, arg)
+	ret0, _ := ret[0].(db.VideoChapter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoChapter indicates an expected call of GetVideoChapter.
+func (mr *MockQuerierMockRecorder) GetVideoChapter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoChapter", reflect.TypeOf((*MockQuerier)(nil).GetVideoChapter), ctx, arg)
+}
+
+// GetVideoDuration mocks base method.
+func (m *MockQuerier) GetVideoDuration(ctx context.Context, id pgtype.UUID) (pgtype.Float8, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoDuration", ctx, id)
+	ret0, _ := ret[0].(pgtype.Float8)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoDuration indicates an expected call of GetVideoDuration.
+func (mr *MockQuerierMockRecorder) GetVideoDuration(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoDuration", reflect.TypeOf((*MockQuerier)(nil).GetVideoDuration), ctx, id)
+}
+
 // GetVideoReview mocks base method.
-func (m *MockQuerier) GetVideoReview(ctx context.Context, id pgtype.UUID) (db.GetVideoReviewRow, error) {
+func (m *MockQuerier) GetVideoReview(ctx context.Context, id pgtype.UUID) (db.VideoReview, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetVideoReview", ctx, id)
-	ret0, _ := ret[0].(db.GetVideoReviewRow)
+	ret0, _ := ret[0].(db.VideoReview)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1084,6 +2077,36 @@ func (mr *MockQuerierMockRecorder) GetVisibleAsset(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVisibleAsset", reflect.TypeOf((*MockQuerier)(nil).GetVisibleAsset), ctx, arg)
 }
 
+// GetVisibleSnippet mocks base method.
+func (m *MockQuerier) GetVisibleSnippet(ctx context.Context, arg db.GetVisibleSnippetParams) (db.ReviewSnippet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVisibleSnippet", ctx, arg)
+	ret0, _ := ret[0].(db.ReviewSnippet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVisibleSnippet indicates an expected call of GetVisibleSnippet.
+func (mr *MockQuerierMockRecorder) GetVisibleSnippet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVisibleSnippet", reflect.TypeOf((*MockQuerier)(nil).GetVisibleSnippet), ctx, arg)
+}
+
+// GetWaitlistEntry mocks base method.
+func (m *MockQuerier) GetWaitlistEntry(ctx context.Context, arg db.GetWaitlistEntryParams) (db.CoachingWaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlistEntry", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingWaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlistEntry indicates an expected call of GetWaitlistEntry.
+func (mr *MockQuerierMockRecorder) GetWaitlistEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlistEntry", reflect.TypeOf((*MockQuerier)(nil).GetWaitlistEntry), ctx, arg)
+}
+
 // HasVideosWithoutReviews mocks base method.
 func (m *MockQuerier) HasVideosWithoutReviews(ctx context.Context, assetID pgtype.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1099,6 +2122,34 @@ func (mr *MockQuerierMockRecorder) HasVideosWithoutReviews(ctx, assetID any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasVideosWithoutReviews", reflect.TypeOf((*MockQuerier)(nil).HasVideosWithoutReviews), ctx, assetID)
 }
 
+// InsertExternalBusyBlocks mocks base method.
+func (m *MockQuerier) InsertExternalBusyBlocks(ctx context.Context, arg db.InsertExternalBusyBlocksParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertExternalBusyBlocks", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertExternalBusyBlocks indicates an expected call of InsertExternalBusyBlocks.
+func (mr *MockQuerierMockRecorder) InsertExternalBusyBlocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertExternalBusyBlocks", reflect.TypeOf((*MockQuerier)(nil).InsertExternalBusyBlocks), ctx, arg)
+}
+
+// InsertWaitlistWindows mocks base method.
+func (m *MockQuerier) InsertWaitlistWindows(ctx context.Context, arg db.InsertWaitlistWindowsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWaitlistWindows", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWaitlistWindows indicates an expected call of InsertWaitlistWindows.
+func (mr *MockQuerierMockRecorder) InsertWaitlistWindows(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWaitlistWindows", reflect.TypeOf((*MockQuerier)(nil).InsertWaitlistWindows), ctx, arg)
+}
+
 // IsRecordingAssetStillOpen mocks base method.
 func (m *MockQuerier) IsRecordingAssetStillOpen(ctx context.Context, recordingAssetID pgtype.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1129,6 +2180,21 @@ func (mr *MockQuerierMockRecorder) LeaveGroupIfNotLastMember(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveGroupIfNotLastMember", reflect.TypeOf((*MockQuerier)(nil).LeaveGroupIfNotLastMember), ctx, arg)
 }
 
+// ListAbsences mocks base method.
+func (m *MockQuerier) ListAbsences(ctx context.Context, arg db.ListAbsencesParams) ([]db.CoachingAbsence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAbsences", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingAbsence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAbsences indicates an expected call of ListAbsences.
+func (mr *MockQuerierMockRecorder) ListAbsences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAbsences", reflect.TypeOf((*MockQuerier)(nil).ListAbsences), ctx, arg)
+}
+
 // ListActiveExpertsInGroup mocks base method.
 func (m *MockQuerier) ListActiveExpertsInGroup(ctx context.Context, groupID pgtype.UUID) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -1144,6 +2210,21 @@ func (mr *MockQuerierMockRecorder) ListActiveExpertsInGroup(ctx, groupID any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveExpertsInGroup", reflect.TypeOf((*MockQuerier)(nil).ListActiveExpertsInGroup), ctx, groupID)
 }
 
+// ListActiveSeriesBookingsFrom mocks base method.
+func (m *MockQuerier) ListActiveSeriesBookingsFrom(ctx context.Context, arg db.ListActiveSeriesBookingsFromParams) ([]db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveSeriesBookingsFrom", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveSeriesBookingsFrom indicates an expected call of ListActiveSeriesBookingsFrom.
+func (mr *MockQuerierMockRecorder) ListActiveSeriesBookingsFrom(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSeriesBookingsFrom", reflect.TypeOf((*MockQuerier)(nil).ListActiveSeriesBookingsFrom), ctx, arg)
+}
+
 // ListAdminInboundEmails mocks base method.
 func (m *MockQuerier) ListAdminInboundEmails(ctx context.Context, arg db.ListAdminInboundEmailsParams) ([]db.InboundEmail, error) {
 	m.ctrl.T.Helper()
@@ -1174,6 +2255,81 @@ func (mr *MockQuerierMockRecorder) ListAllMyBookings(ctx, expertID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllMyBookings", reflect.TypeOf((*MockQuerier)(nil).ListAllMyBookings), ctx, expertID)
 }
 
+// ListAssetMuxIdentifiers mocks base method.
+func (m *MockQuerier) ListAssetMuxIdentifiers(ctx context.Context, assetID pgtype.UUID) ([]db.ListAssetMuxIdentifiersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssetMuxIdentifiers", ctx, assetID)
+	ret0, _ := ret[0].([]db.ListAssetMuxIdentifiersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssetMuxIdentifiers indicates an expected call of ListAssetMuxIdentifiers.
+func (mr *MockQuerierMockRecorder) ListAssetMuxIdentifiers(ctx, assetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssetMuxIdentifiers", reflect.TypeOf((*MockQuerier)(nil).ListAssetMuxIdentifiers), ctx, assetID)
+}
+
+// ListAssetsDueForPurge mocks base method.
+func (m *MockQuerier) ListAssetsDueForPurge(ctx context.Context, arg db.ListAssetsDueForPurgeParams) ([]pgtype.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAssetsDueForPurge", ctx, arg)
+	ret0, _ := ret[0].([]pgtype.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAssetsDueForPurge indicates an expected call of ListAssetsDueForPurge.
+func (mr *MockQuerierMockRecorder) ListAssetsDueForPurge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAssetsDueForPurge", reflect.TypeOf((*MockQuerier)(nil).ListAssetsDueForPurge), ctx, arg)
+}
+
+// ListAuditChainEvents mocks base method.
+func (m *MockQuerier) ListAuditChainEvents(ctx context.Context, arg db.ListAuditChainEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditChainEvents", ctx, arg)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditChainEvents indicates an expected call of ListAuditChainEvents.
+func (mr *MockQuerierMockRecorder) ListAuditChainEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditChainEvents", reflect.TypeOf((*MockQuerier)(nil).ListAuditChainEvents), ctx, arg)
+}
+
+// ListAuditChainHeads mocks base method.
+func (m *MockQuerier) ListAuditChainHeads(ctx context.Context) ([]db.AuditChainHead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditChainHeads", ctx)
+	ret0, _ := ret[0].([]db.AuditChainHead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditChainHeads indicates an expected call of ListAuditChainHeads.
+func (mr *MockQuerierMockRecorder) ListAuditChainHeads(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditChainHeads", reflect.TypeOf((*MockQuerier)(nil).ListAuditChainHeads), ctx)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockQuerier) ListAuditEvents(ctx context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", ctx, arg)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockQuerierMockRecorder) ListAuditEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockQuerier)(nil).ListAuditEvents), ctx, arg)
+}
+
 // ListAvailabilityByExpertGroup mocks base method.
 func (m *MockQuerier) ListAvailabilityByExpertGroup(ctx context.Context, arg db.ListAvailabilityByExpertGroupParams) ([]db.CoachingAvailability, error) {
 	m.ctrl.T.Helper()
@@ -1219,8 +2375,23 @@ func (mr *MockQuerierMockRecorder) ListAvailabilityByGroup(ctx, groupID any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityByGroup", reflect.TypeOf((*MockQuerier)(nil).ListAvailabilityByGroup), ctx, groupID)
 }
 
-// ListBlockedSlots mocks base method.
-func (m *MockQuerier) ListBlockedSlots(ctx context.Context, arg db.ListBlockedSlotsParams) ([]db.CoachingBlockedSlot, error) {
+// ListAvailabilityOverrides mocks base method.
+func (m *MockQuerier) ListAvailabilityOverrides(ctx context.Context, arg db.ListAvailabilityOverridesParams) ([]db.CoachingAvailabilityOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailabilityOverrides", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingAvailabilityOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailabilityOverrides indicates an expected call of ListAvailabilityOverrides.
+func (mr *MockQuerierMockRecorder) ListAvailabilityOverrides(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityOverrides", reflect.TypeOf((*MockQuerier)(nil).ListAvailabilityOverrides), ctx, arg)
+}
+
+// ListBlockedSlots mocks base method.
+func (m *MockQuerier) ListBlockedSlots(ctx context.Context, arg db.ListBlockedSlotsParams) ([]db.CoachingBlockedSlot, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListBlockedSlots", ctx, arg)
 	ret0, _ := ret[0].([]db.CoachingBlockedSlot)
@@ -1234,6 +2405,81 @@ func (mr *MockQuerierMockRecorder) ListBlockedSlots(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlockedSlots", reflect.TypeOf((*MockQuerier)(nil).ListBlockedSlots), ctx, arg)
 }
 
+// ListBookingAttendance mocks base method.
+func (m *MockQuerier) ListBookingAttendance(ctx context.Context, bookingID pgtype.UUID) ([]db.CoachingBookingAttendance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookingAttendance", ctx, bookingID)
+	ret0, _ := ret[0].([]db.CoachingBookingAttendance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookingAttendance indicates an expected call of ListBookingAttendance.
+func (mr *MockQuerierMockRecorder) ListBookingAttendance(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookingAttendance", reflect.TypeOf((*MockQuerier)(nil).ListBookingAttendance), ctx, bookingID)
+}
+
+// ListBookingHomework mocks base method.
+func (m *MockQuerier) ListBookingHomework(ctx context.Context, bookingID pgtype.UUID) ([]db.CoachingHomework, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookingHomework", ctx, bookingID)
+	ret0, _ := ret[0].([]db.CoachingHomework)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookingHomework indicates an expected call of ListBookingHomework.
+func (mr *MockQuerierMockRecorder) ListBookingHomework(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookingHomework", reflect.TypeOf((*MockQuerier)(nil).ListBookingHomework), ctx, bookingID)
+}
+
+// ListBookingMessages mocks base method.
+func (m *MockQuerier) ListBookingMessages(ctx context.Context, bookingID pgtype.UUID) ([]db.CoachingBookingMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookingMessages", ctx, bookingID)
+	ret0, _ := ret[0].([]db.CoachingBookingMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookingMessages indicates an expected call of ListBookingMessages.
+func (mr *MockQuerierMockRecorder) ListBookingMessages(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookingMessages", reflect.TypeOf((*MockQuerier)(nil).ListBookingMessages), ctx, bookingID)
+}
+
+// ListBookingRecordingImports mocks base method.
+func (m *MockQuerier) ListBookingRecordingImports(ctx context.Context, bookingID pgtype.UUID) ([]db.ListBookingRecordingImportsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookingRecordingImports", ctx, bookingID)
+	ret0, _ := ret[0].([]db.ListBookingRecordingImportsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookingRecordingImports indicates an expected call of ListBookingRecordingImports.
+func (mr *MockQuerierMockRecorder) ListBookingRecordingImports(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookingRecordingImports", reflect.TypeOf((*MockQuerier)(nil).ListBookingRecordingImports), ctx, bookingID)
+}
+
+// ListBookingsAwaitingAttendance mocks base method.
+func (m *MockQuerier) ListBookingsAwaitingAttendance(ctx context.Context, arg db.ListBookingsAwaitingAttendanceParams) ([]db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBookingsAwaitingAttendance", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBookingsAwaitingAttendance indicates an expected call of ListBookingsAwaitingAttendance.
+func (mr *MockQuerierMockRecorder) ListBookingsAwaitingAttendance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookingsAwaitingAttendance", reflect.TypeOf((*MockQuerier)(nil).ListBookingsAwaitingAttendance), ctx, arg)
+}
+
 // ListBookingsByExpertInRange mocks base method.
 func (m *MockQuerier) ListBookingsByExpertInRange(ctx context.Context, arg db.ListBookingsByExpertInRangeParams) ([]db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
@@ -1249,6 +2495,66 @@ func (mr *MockQuerierMockRecorder) ListBookingsByExpertInRange(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookingsByExpertInRange", reflect.TypeOf((*MockQuerier)(nil).ListBookingsByExpertInRange), ctx, arg)
 }
 
+// ListCalendarFeedBookings mocks base method.
+func (m *MockQuerier) ListCalendarFeedBookings(ctx context.Context, arg db.ListCalendarFeedBookingsParams) ([]db.ListCalendarFeedBookingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCalendarFeedBookings", ctx, arg)
+	ret0, _ := ret[0].([]db.ListCalendarFeedBookingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCalendarFeedBookings indicates an expected call of ListCalendarFeedBookings.
+func (mr *MockQuerierMockRecorder) ListCalendarFeedBookings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCalendarFeedBookings", reflect.TypeOf((*MockQuerier)(nil).ListCalendarFeedBookings), ctx, arg)
+}
+
+// ListClassAttendees mocks base method.
+func (m *MockQuerier) ListClassAttendees(ctx context.Context, classID pgtype.UUID) ([]db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClassAttendees", ctx, classID)
+	ret0, _ := ret[0].([]db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClassAttendees indicates an expected call of ListClassAttendees.
+func (mr *MockQuerierMockRecorder) ListClassAttendees(ctx, classID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClassAttendees", reflect.TypeOf((*MockQuerier)(nil).ListClassAttendees), ctx, classID)
+}
+
+// ListCreditGrants mocks base method.
+func (m *MockQuerier) ListCreditGrants(ctx context.Context, arg db.ListCreditGrantsParams) ([]db.CoachingCreditGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditGrants", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingCreditGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditGrants indicates an expected call of ListCreditGrants.
+func (mr *MockQuerierMockRecorder) ListCreditGrants(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditGrants", reflect.TypeOf((*MockQuerier)(nil).ListCreditGrants), ctx, arg)
+}
+
+// ListCreditLedger mocks base method.
+func (m *MockQuerier) ListCreditLedger(ctx context.Context, arg db.ListCreditLedgerParams) ([]db.CoachingCreditLedger, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditLedger", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingCreditLedger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditLedger indicates an expected call of ListCreditLedger.
+func (mr *MockQuerierMockRecorder) ListCreditLedger(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditLedger", reflect.TypeOf((*MockQuerier)(nil).ListCreditLedger), ctx, arg)
+}
+
 // ListDevicesForUser mocks base method.
 func (m *MockQuerier) ListDevicesForUser(ctx context.Context, userID string) ([]db.UserDevice, error) {
 	m.ctrl.T.Helper()
@@ -1264,6 +2570,51 @@ func (mr *MockQuerierMockRecorder) ListDevicesForUser(ctx, userID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevicesForUser", reflect.TypeOf((*MockQuerier)(nil).ListDevicesForUser), ctx, userID)
 }
 
+// ListExpiredCreditGrants mocks base method.
+func (m *MockQuerier) ListExpiredCreditGrants(ctx context.Context, limit int32) ([]db.CoachingCreditGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredCreditGrants", ctx, limit)
+	ret0, _ := ret[0].([]db.CoachingCreditGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredCreditGrants indicates an expected call of ListExpiredCreditGrants.
+func (mr *MockQuerierMockRecorder) ListExpiredCreditGrants(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredCreditGrants", reflect.TypeOf((*MockQuerier)(nil).ListExpiredCreditGrants), ctx, limit)
+}
+
+// ListExternalBusyBlocks mocks base method.
+func (m *MockQuerier) ListExternalBusyBlocks(ctx context.Context, arg db.ListExternalBusyBlocksParams) ([]db.ListExternalBusyBlocksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalBusyBlocks", ctx, arg)
+	ret0, _ := ret[0].([]db.ListExternalBusyBlocksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExternalBusyBlocks indicates an expected call of ListExternalBusyBlocks.
+func (mr *MockQuerierMockRecorder) ListExternalBusyBlocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalBusyBlocks", reflect.TypeOf((*MockQuerier)(nil).ListExternalBusyBlocks), ctx, arg)
+}
+
+// ListExternalCalendarsByExpert mocks base method.
+func (m *MockQuerier) ListExternalCalendarsByExpert(ctx context.Context, expertID string) ([]db.CoachingExternalCalendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalCalendarsByExpert", ctx, expertID)
+	ret0, _ := ret[0].([]db.CoachingExternalCalendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExternalCalendarsByExpert indicates an expected call of ListExternalCalendarsByExpert.
+func (mr *MockQuerierMockRecorder) ListExternalCalendarsByExpert(ctx, expertID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalCalendarsByExpert", reflect.TypeOf((*MockQuerier)(nil).ListExternalCalendarsByExpert), ctx, expertID)
+}
+
 // ListGroupBookings mocks base method.
 func (m *MockQuerier) ListGroupBookings(ctx context.Context, groupID pgtype.UUID) ([]db.ListGroupBookingsRow, error) {
 	m.ctrl.T.Helper()
@@ -1309,6 +2660,21 @@ func (mr *MockQuerierMockRecorder) ListGroupMembers(ctx, groupID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroupMembers", reflect.TypeOf((*MockQuerier)(nil).ListGroupMembers), ctx, groupID)
 }
 
+// ListHomework mocks base method.
+func (m *MockQuerier) ListHomework(ctx context.Context, arg db.ListHomeworkParams) ([]db.ListHomeworkRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHomework", ctx, arg)
+	ret0, _ := ret[0].([]db.ListHomeworkRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHomework indicates an expected call of ListHomework.
+func (mr *MockQuerierMockRecorder) ListHomework(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHomework", reflect.TypeOf((*MockQuerier)(nil).ListHomework), ctx, arg)
+}
+
 // ListInboundEmailReplies mocks base method.
 func (m *MockQuerier) ListInboundEmailReplies(ctx context.Context, inboundEmailID pgtype.UUID) ([]db.InboundEmailReply, error) {
 	m.ctrl.T.Helper()
@@ -1354,6 +2720,21 @@ func (mr *MockQuerierMockRecorder) ListMyBookings(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMyBookings", reflect.TypeOf((*MockQuerier)(nil).ListMyBookings), ctx, arg)
 }
 
+// ListMyWaitlistEntries mocks base method.
+func (m *MockQuerier) ListMyWaitlistEntries(ctx context.Context, arg db.ListMyWaitlistEntriesParams) ([]db.ListMyWaitlistEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMyWaitlistEntries", ctx, arg)
+	ret0, _ := ret[0].([]db.ListMyWaitlistEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMyWaitlistEntries indicates an expected call of ListMyWaitlistEntries.
+func (mr *MockQuerierMockRecorder) ListMyWaitlistEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMyWaitlistEntries", reflect.TypeOf((*MockQuerier)(nil).ListMyWaitlistEntries), ctx, arg)
+}
+
 // ListNotifications mocks base method.
 func (m *MockQuerier) ListNotifications(ctx context.Context, arg db.ListNotificationsParams) ([]db.Notification, error) {
 	m.ctrl.T.Helper()
@@ -1369,6 +2750,36 @@ func (mr *MockQuerierMockRecorder) ListNotifications(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockQuerier)(nil).ListNotifications), ctx, arg)
 }
 
+// ListOpenClasses mocks base method.
+func (m *MockQuerier) ListOpenClasses(ctx context.Context, arg db.ListOpenClassesParams) ([]db.ListOpenClassesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenClasses", ctx, arg)
+	ret0, _ := ret[0].([]db.ListOpenClassesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenClasses indicates an expected call of ListOpenClasses.
+func (mr *MockQuerierMockRecorder) ListOpenClasses(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenClasses", reflect.TypeOf((*MockQuerier)(nil).ListOpenClasses), ctx, arg)
+}
+
+// ListPaymentsAwaitingRefund mocks base method.
+func (m *MockQuerier) ListPaymentsAwaitingRefund(ctx context.Context, limit int32) ([]db.CoachingPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentsAwaitingRefund", ctx, limit)
+	ret0, _ := ret[0].([]db.CoachingPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentsAwaitingRefund indicates an expected call of ListPaymentsAwaitingRefund.
+func (mr *MockQuerierMockRecorder) ListPaymentsAwaitingRefund(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentsAwaitingRefund", reflect.TypeOf((*MockQuerier)(nil).ListPaymentsAwaitingRefund), ctx, limit)
+}
+
 // ListPendingReminders mocks base method.
 func (m *MockQuerier) ListPendingReminders(ctx context.Context) ([]db.ListPendingRemindersRow, error) {
 	m.ctrl.T.Helper()
@@ -1384,153 +2795,406 @@ func (mr *MockQuerierMockRecorder) ListPendingReminders(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingReminders", reflect.TypeOf((*MockQuerier)(nil).ListPendingReminders), ctx)
 }
 
+// ListRecordingAssetMessages mocks base method.
+func (m *MockQuerier) ListRecordingAssetMessages(ctx context.Context, recordingAssetID pgtype.UUID) ([]db.CoachingBookingMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecordingAssetMessages", ctx, recordingAssetID)
+	ret0, _ := ret[0].([]db.CoachingBookingMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecordingAssetMessages indicates an expected call of ListRecordingAssetMessages.
+func (mr *MockQuerierMockRecorder) ListRecordingAssetMessages(ctx, recordingAssetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordingAssetMessages", reflect.TypeOf((*MockQuerier)(nil).ListRecordingAssetMessages), ctx, recordingAssetID)
+}
+
 // ListRecordingPartsReadyToStop mocks base method.
 func (m *MockQuerier) ListRecordingPartsReadyToStop(ctx context.Context, arg db.ListRecordingPartsReadyToStopParams) ([]db.CoachingBookingRecording, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRecordingPartsReadyToStop", ctx, arg)
-	ret0, _ := ret[0].([]db.CoachingBookingRecording)
+	ret := m.ctrl.Call(m, "ListRecordingPartsReadyToStop", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingBookingRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecordingPartsReadyToStop indicates an expected call of ListRecordingPartsReadyToStop.
+func (mr *MockQuerierMockRecorder) ListRecordingPartsReadyToStop(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordingPartsReadyToStop", reflect.TypeOf((*MockQuerier)(nil).ListRecordingPartsReadyToStop), ctx, arg)
+}
+
+// ListRecordingStitchInputs mocks base method.
+func (m *MockQuerier) ListRecordingStitchInputs(ctx context.Context, bookingID pgtype.UUID) ([]db.ListRecordingStitchInputsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecordingStitchInputs", ctx, bookingID)
+	ret0, _ := ret[0].([]db.ListRecordingStitchInputsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecordingStitchInputs indicates an expected call of ListRecordingStitchInputs.
+func (mr *MockQuerierMockRecorder) ListRecordingStitchInputs(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordingStitchInputs", reflect.TypeOf((*MockQuerier)(nil).ListRecordingStitchInputs), ctx, bookingID)
+}
+
+// ListSessionTypesByExpertGroup mocks base method.
+func (m *MockQuerier) ListSessionTypesByExpertGroup(ctx context.Context, arg db.ListSessionTypesByExpertGroupParams) ([]db.CoachingSessionType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessionTypesByExpertGroup", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingSessionType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessionTypesByExpertGroup indicates an expected call of ListSessionTypesByExpertGroup.
+func (mr *MockQuerierMockRecorder) ListSessionTypesByExpertGroup(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionTypesByExpertGroup", reflect.TypeOf((*MockQuerier)(nil).ListSessionTypesByExpertGroup), ctx, arg)
+}
+
+// ListSessionTypesByGroup mocks base method.
+func (m *MockQuerier) ListSessionTypesByGroup(ctx context.Context, groupID pgtype.UUID) ([]db.CoachingSessionType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessionTypesByGroup", ctx, groupID)
+	ret0, _ := ret[0].([]db.CoachingSessionType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessionTypesByGroup indicates an expected call of ListSessionTypesByGroup.
+func (mr *MockQuerierMockRecorder) ListSessionTypesByGroup(ctx, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionTypesByGroup", reflect.TypeOf((*MockQuerier)(nil).ListSessionTypesByGroup), ctx, groupID)
+}
+
+// ListSignupCodesByOwner mocks base method.
+func (m *MockQuerier) ListSignupCodesByOwner(ctx context.Context, ownerUserID string) ([]db.SignupCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSignupCodesByOwner", ctx, ownerUserID)
+	ret0, _ := ret[0].([]db.SignupCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSignupCodesByOwner indicates an expected call of ListSignupCodesByOwner.
+func (mr *MockQuerierMockRecorder) ListSignupCodesByOwner(ctx, ownerUserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSignupCodesByOwner", reflect.TypeOf((*MockQuerier)(nil).ListSignupCodesByOwner), ctx, ownerUserID)
+}
+
+// ListStoppedRecordingPartsForDiscovery mocks base method.
+func (m *MockQuerier) ListStoppedRecordingPartsForDiscovery(ctx context.Context, limit int32) ([]db.CoachingBookingRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStoppedRecordingPartsForDiscovery", ctx, limit)
+	ret0, _ := ret[0].([]db.CoachingBookingRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStoppedRecordingPartsForDiscovery indicates an expected call of ListStoppedRecordingPartsForDiscovery.
+func (mr *MockQuerierMockRecorder) ListStoppedRecordingPartsForDiscovery(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStoppedRecordingPartsForDiscovery", reflect.TypeOf((*MockQuerier)(nil).ListStoppedRecordingPartsForDiscovery), ctx, limit)
+}
+
+// ListUnpaidExpiredBookings mocks base method.
+func (m *MockQuerier) ListUnpaidExpiredBookings(ctx context.Context, limit int32) ([]db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpaidExpiredBookings", ctx, limit)
+	ret0, _ := ret[0].([]db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpaidExpiredBookings indicates an expected call of ListUnpaidExpiredBookings.
+func (mr *MockQuerierMockRecorder) ListUnpaidExpiredBookings(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpaidExpiredBookings", reflect.TypeOf((*MockQuerier)(nil).ListUnpaidExpiredBookings), ctx, limit)
+}
+
+// ListUserGroups mocks base method.
+func (m *MockQuerier) ListUserGroups(ctx context.Context, userID string) ([]db.ListUserGroupsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserGroups", ctx, userID)
+	ret0, _ := ret[0].([]db.ListUserGroupsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserGroups indicates an expected call of ListUserGroups.
+func (mr *MockQuerierMockRecorder) ListUserGroups(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserGroups", reflect.TypeOf((*MockQuerier)(nil).ListUserGroups), ctx, userID)
+}
+
+// ListVideoChapters mocks base method.
+func (m *MockQuerier) ListVideoChapters(ctx context.Context, videoID pgtype.UUID) ([]db.VideoChapter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVideoChapters", ctx, videoID)
+	ret0, _ := ret[0].([]db.VideoChapter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVideoChapters indicates an expected call of ListVideoChapters.
+func (mr *MockQuerierMockRecorder) ListVideoChapters(ctx, videoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideoChapters", reflect.TypeOf((*MockQuerier)(nil).ListVideoChapters), ctx, videoID)
+}
+
+// ListVideoReviewReactions mocks base method.
+func (m *MockQuerier) ListVideoReviewReactions(ctx context.Context, arg db.ListVideoReviewReactionsParams) ([]db.ListVideoReviewReactionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVideoReviewReactions", ctx, arg)
+	ret0, _ := ret[0].([]db.ListVideoReviewReactionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVideoReviewReactions indicates an expected call of ListVideoReviewReactions.
+func (mr *MockQuerierMockRecorder) ListVideoReviewReactions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideoReviewReactions", reflect.TypeOf((*MockQuerier)(nil).ListVideoReviewReactions), ctx, arg)
+}
+
+// ListVideoReviews mocks base method.
+func (m *MockQuerier) ListVideoReviews(ctx context.Context, videoID pgtype.UUID) ([]db.ListVideoReviewsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVideoReviews", ctx, videoID)
+	ret0, _ := ret[0].([]db.ListVideoReviewsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVideoReviews indicates an expected call of ListVideoReviews.
+func (mr *MockQuerierMockRecorder) ListVideoReviews(ctx, videoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideoReviews", reflect.TypeOf((*MockQuerier)(nil).ListVideoReviews), ctx, videoID)
+}
+
+// ListVideosDueForPurge mocks base method.
+func (m *MockQuerier) ListVideosDueForPurge(ctx context.Context, arg db.ListVideosDueForPurgeParams) ([]db.ListVideosDueForPurgeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVideosDueForPurge", ctx, arg)
+	ret0, _ := ret[0].([]db.ListVideosDueForPurgeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVideosDueForPurge indicates an expected call of ListVideosDueForPurge.
+func (mr *MockQuerierMockRecorder) ListVideosDueForPurge(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideosDueForPurge", reflect.TypeOf((*MockQuerier)(nil).ListVideosDueForPurge), ctx, arg)
+}
+
+// ListVideosMissingDuration mocks base method.
+func (m *MockQuerier) ListVideosMissingDuration(ctx context.Context, limit int32) ([]db.ListVideosMissingDurationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVideosMissingDuration", ctx, limit)
+	ret0, _ := ret[0].([]db.ListVideosMissingDurationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVideosMissingDuration indicates an expected call of ListVideosMissingDuration.
+func (mr *MockQuerierMockRecorder) ListVideosMissingDuration(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideosMissingDuration", reflect.TypeOf((*MockQuerier)(nil).ListVideosMissingDuration), ctx, limit)
+}
+
+// ListVisibleAssets mocks base method.
+func (m *MockQuerier) ListVisibleAssets(ctx context.Context, arg db.ListVisibleAssetsParams) ([]db.ListVisibleAssetsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVisibleAssets", ctx, arg)
+	ret0, _ := ret[0].([]db.ListVisibleAssetsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVisibleAssets indicates an expected call of ListVisibleAssets.
+func (mr *MockQuerierMockRecorder) ListVisibleAssets(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVisibleAssets", reflect.TypeOf((*MockQuerier)(nil).ListVisibleAssets), ctx, arg)
+}
+
+// ListVisibleAssetsByReviewActivity mocks base method.
+func (m *MockQuerier) ListVisibleAssetsByReviewActivity(ctx context.Context, arg db.ListVisibleAssetsByReviewActivityParams) ([]db.ListVisibleAssetsByReviewActivityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVisibleAssetsByReviewActivity", ctx, arg)
+	ret0, _ := ret[0].([]db.ListVisibleAssetsByReviewActivityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVisibleAssetsByReviewActivity indicates an expected call of ListVisibleAssetsByReviewActivity.
+func (mr *MockQuerierMockRecorder) ListVisibleAssetsByReviewActivity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVisibleAssetsByReviewActivity", reflect.TypeOf((*MockQuerier)(nil).ListVisibleAssetsByReviewActivity), ctx, arg)
+}
+
+// ListVisibleAssetsByUpdated mocks base method.
+func (m *MockQuerier) ListVisibleAssetsByUpdated(ctx context.Context, arg db.ListVisibleAssetsByUpdatedParams) ([]db.ListVisibleAssetsByUpdatedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVisibleAssetsByUpdated", ctx, arg)
+	ret0, _ := ret[0].([]db.ListVisibleAssetsByUpdatedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVisibleAssetsByUpdated indicates an expected call of ListVisibleAssetsByUpdated.
+func (mr *MockQuerierMockRecorder) ListVisibleAssetsByUpdated(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVisibleAssetsByUpdated", reflect.TypeOf((*MockQuerier)(nil).ListVisibleAssetsByUpdated), ctx, arg)
+}
+
+// ListVisibleSnippets mocks base method.
+func (m *MockQuerier) ListVisibleSnippets(ctx context.Context, arg db.ListVisibleSnippetsParams) ([]db.ReviewSnippet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVisibleSnippets", ctx, arg)
+	ret0, _ := ret[0].([]db.ReviewSnippet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListRecordingPartsReadyToStop indicates an expected call of ListRecordingPartsReadyToStop.
-func (mr *MockQuerierMockRecorder) ListRecordingPartsReadyToStop(ctx, arg any) *gomock.Call {
+// ListVisibleSnippets indicates an expected call of ListVisibleSnippets.
+func (mr *MockQuerierMockRecorder) ListVisibleSnippets(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordingPartsReadyToStop", reflect.TypeOf((*MockQuerier)(nil).ListRecordingPartsReadyToStop), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVisibleSnippets", reflect.TypeOf((*MockQuerier)(nil).ListVisibleSnippets), ctx, arg)
 }
 
-// ListSessionTypesByExpertGroup mocks base method.
-func (m *MockQuerier) ListSessionTypesByExpertGroup(ctx context.Context, arg db.ListSessionTypesByExpertGroupParams) ([]db.CoachingSessionType, error) {
+// ListWaitingEntriesByExpert mocks base method.
+func (m *MockQuerier) ListWaitingEntriesByExpert(ctx context.Context, arg db.ListWaitingEntriesByExpertParams) ([]db.CoachingWaitlistEntry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSessionTypesByExpertGroup", ctx, arg)
-	ret0, _ := ret[0].([]db.CoachingSessionType)
+	ret := m.ctrl.Call(m, "ListWaitingEntriesByExpert", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingWaitlistEntry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListSessionTypesByExpertGroup indicates an expected call of ListSessionTypesByExpertGroup.
-func (mr *MockQuerierMockRecorder) ListSessionTypesByExpertGroup(ctx, arg any) *gomock.Call {
+// ListWaitingEntriesByExpert indicates an expected call of ListWaitingEntriesByExpert.
+func (mr *MockQuerierMockRecorder) ListWaitingEntriesByExpert(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionTypesByExpertGroup", reflect.TypeOf((*MockQuerier)(nil).ListSessionTypesByExpertGroup), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWaitingEntriesByExpert", reflect.TypeOf((*MockQuerier)(nil).ListWaitingEntriesByExpert), ctx, arg)
 }
 
-// ListSessionTypesByGroup mocks base method.
-func (m *MockQuerier) ListSessionTypesByGroup(ctx context.Context, groupID pgtype.UUID) ([]db.CoachingSessionType, error) {
+// ListWaitlistHoldsByExpertInRange mocks base method.
+func (m *MockQuerier) ListWaitlistHoldsByExpertInRange(ctx context.Context, arg db.ListWaitlistHoldsByExpertInRangeParams) ([]db.CoachingWaitlistHold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSessionTypesByGroup", ctx, groupID)
-	ret0, _ := ret[0].([]db.CoachingSessionType)
+	ret := m.ctrl.Call(m, "ListWaitlistHoldsByExpertInRange", ctx, arg)
+	ret0, _ := ret[0].([]db.CoachingWaitlistHold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListSessionTypesByGroup indicates an expected call of ListSessionTypesByGroup.
-func (mr *MockQuerierMockRecorder) ListSessionTypesByGroup(ctx, groupID any) *gomock.Call {
+// ListWaitlistHoldsByExpertInRange indicates an expected call of ListWaitlistHoldsByExpertInRange.
+func (mr *MockQuerierMockRecorder) ListWaitlistHoldsByExpertInRange(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionTypesByGroup", reflect.TypeOf((*MockQuerier)(nil).ListSessionTypesByGroup), ctx, groupID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWaitlistHoldsByExpertInRange", reflect.TypeOf((*MockQuerier)(nil).ListWaitlistHoldsByExpertInRange), ctx, arg)
 }
 
-// ListSignupCodesByOwner mocks base method.
-func (m *MockQuerier) ListSignupCodesByOwner(ctx context.Context, ownerUserID string) ([]db.SignupCode, error) {
+// ListWaitlistWindows mocks base method.
+func (m *MockQuerier) ListWaitlistWindows(ctx context.Context, entryIds []pgtype.UUID) ([]db.CoachingWaitlistWindow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSignupCodesByOwner", ctx, ownerUserID)
-	ret0, _ := ret[0].([]db.SignupCode)
+	ret := m.ctrl.Call(m, "ListWaitlistWindows", ctx, entryIds)
+	ret0, _ := ret[0].([]db.CoachingWaitlistWindow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListSignupCodesByOwner indicates an expected call of ListSignupCodesByOwner.
-func (mr *MockQuerierMockRecorder) ListSignupCodesByOwner(ctx, ownerUserID any) *gomock.Call {
+// ListWaitlistWindows indicates an expected call of ListWaitlistWindows.
+func (mr *MockQuerierMockRecorder) ListWaitlistWindows(ctx, entryIds any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSignupCodesByOwner", reflect.TypeOf((*MockQuerier)(nil).ListSignupCodesByOwner), ctx, ownerUserID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWaitlistWindows", reflect.TypeOf((*MockQuerier)(nil).ListWaitlistWindows), ctx, entryIds)
 }
 
-// ListStoppedRecordingPartsForDiscovery mocks base method.
-func (m *MockQuerier) ListStoppedRecordingPartsForDiscovery(ctx context.Context, limit int32) ([]db.CoachingBookingRecording, error) {
+// LockAuditChainHead mocks base method.
+func (m *MockQuerier) LockAuditChainHead(ctx context.Context, shard int32) (db.LockAuditChainHeadRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListStoppedRecordingPartsForDiscovery", ctx, limit)
-	ret0, _ := ret[0].([]db.CoachingBookingRecording)
+	ret := m.ctrl.Call(m, "LockAuditChainHead", ctx, shard)
+	ret0, _ := ret[0].(db.LockAuditChainHeadRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListStoppedRecordingPartsForDiscovery indicates an expected call of ListStoppedRecordingPartsForDiscovery.
-func (mr *MockQuerierMockRecorder) ListStoppedRecordingPartsForDiscovery(ctx, limit any) *gomock.Call {
+// LockAuditChainHead indicates an expected call of LockAuditChainHead.
+func (mr *MockQuerierMockRecorder) LockAuditChainHead(ctx, shard any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStoppedRecordingPartsForDiscovery", reflect.TypeOf((*MockQuerier)(nil).ListStoppedRecordingPartsForDiscovery), ctx, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAuditChainHead", reflect.TypeOf((*MockQuerier)(nil).LockAuditChainHead), ctx, shard)
 }
 
-// ListUserGroups mocks base method.
-func (m *MockQuerier) ListUserGroups(ctx context.Context, userID string) ([]db.ListUserGroupsRow, error) {
+// MarkAllNotificationsRead mocks base method.
+func (m *MockQuerier) MarkAllNotificationsRead(ctx context.Context, recipientID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUserGroups", ctx, userID)
-	ret0, _ := ret[0].([]db.ListUserGroupsRow)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, recipientID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListUserGroups indicates an expected call of ListUserGroups.
-func (mr *MockQuerierMockRecorder) ListUserGroups(ctx, userID any) *gomock.Call {
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockQuerierMockRecorder) MarkAllNotificationsRead(ctx, recipientID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserGroups", reflect.TypeOf((*MockQuerier)(nil).ListUserGroups), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockQuerier)(nil).MarkAllNotificationsRead), ctx, recipientID)
 }
 
-// ListVideoReviews mocks base method.
-func (m *MockQuerier) ListVideoReviews(ctx context.Context, videoID pgtype.UUID) ([]db.ListVideoReviewsRow, error) {
+// MarkBookingPaid mocks base method.
+func (m *MockQuerier) MarkBookingPaid(ctx context.Context, id pgtype.UUID) (db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListVideoReviews", ctx, videoID)
-	ret0, _ := ret[0].([]db.ListVideoReviewsRow)
+	ret := m.ctrl.Call(m, "MarkBookingPaid", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBooking)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListVideoReviews indicates an expected call of ListVideoReviews.
-func (mr *MockQuerierMockRecorder) ListVideoReviews(ctx, videoID any) *gomock.Call {
+// MarkBookingPaid indicates an expected call of MarkBookingPaid.
+func (mr *MockQuerierMockRecorder) MarkBookingPaid(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideoReviews", reflect.TypeOf((*MockQuerier)(nil).ListVideoReviews), ctx, videoID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBookingPaid", reflect.TypeOf((*MockQuerier)(nil).MarkBookingPaid), ctx, id)
 }
 
-// ListVideosMissingDuration mocks base method.
-func (m *MockQuerier) ListVideosMissingDuration(ctx context.Context, limit int32) ([]db.ListVideosMissingDurationRow, error) {
+// MarkBookingRefunded mocks base method.
+func (m *MockQuerier) MarkBookingRefunded(ctx context.Context, id pgtype.UUID) (db.CoachingBooking, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListVideosMissingDuration", ctx, limit)
-	ret0, _ := ret[0].([]db.ListVideosMissingDurationRow)
+	ret := m.ctrl.Call(m, "MarkBookingRefunded", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBooking)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListVideosMissingDuration indicates an expected call of ListVideosMissingDuration.
-func (mr *MockQuerierMockRecorder) ListVideosMissingDuration(ctx, limit any) *gomock.Call {
+// MarkBookingRefunded indicates an expected call of MarkBookingRefunded.
+func (mr *MockQuerierMockRecorder) MarkBookingRefunded(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVideosMissingDuration", reflect.TypeOf((*MockQuerier)(nil).ListVideosMissingDuration), ctx, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkBookingRefunded", reflect.TypeOf((*MockQuerier)(nil).MarkBookingRefunded), ctx, id)
 }
 
-// ListVisibleAssets mocks base method.
-func (m *MockQuerier) ListVisibleAssets(ctx context.Context, arg db.ListVisibleAssetsParams) ([]db.ListVisibleAssetsRow, error) {
+// MarkCoachingPaymentPaid mocks base method.
+func (m *MockQuerier) MarkCoachingPaymentPaid(ctx context.Context, arg db.MarkCoachingPaymentPaidParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListVisibleAssets", ctx, arg)
-	ret0, _ := ret[0].([]db.ListVisibleAssetsRow)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkCoachingPaymentPaid", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListVisibleAssets indicates an expected call of ListVisibleAssets.
-func (mr *MockQuerierMockRecorder) ListVisibleAssets(ctx, arg any) *gomock.Call {
+// MarkCoachingPaymentPaid indicates an expected call of MarkCoachingPaymentPaid.
+func (mr *MockQuerierMockRecorder) MarkCoachingPaymentPaid(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVisibleAssets", reflect.TypeOf((*MockQuerier)(nil).ListVisibleAssets), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkCoachingPaymentPaid", reflect.TypeOf((*MockQuerier)(nil).MarkCoachingPaymentPaid), ctx, arg)
 }
 
-// MarkAllNotificationsRead mocks base method.
-func (m *MockQuerier) MarkAllNotificationsRead(ctx context.Context, recipientID string) error {
+// MarkCoachingPaymentRefunded mocks base method.
+func (m *MockQuerier) MarkCoachingPaymentRefunded(ctx context.Context, arg db.MarkCoachingPaymentRefundedParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, recipientID)
+	ret := m.ctrl.Call(m, "MarkCoachingPaymentRefunded", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
-func (mr *MockQuerierMockRecorder) MarkAllNotificationsRead(ctx, recipientID any) *gomock.Call {
+// MarkCoachingPaymentRefunded indicates an expected call of MarkCoachingPaymentRefunded.
+func (mr *MockQuerierMockRecorder) MarkCoachingPaymentRefunded(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockQuerier)(nil).MarkAllNotificationsRead), ctx, recipientID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkCoachingPaymentRefunded", reflect.TypeOf((*MockQuerier)(nil).MarkCoachingPaymentRefunded), ctx, arg)
 }
 
 // MarkEmptyRecordingPartsWithoutFreshHumans mocks base method.
@@ -1548,6 +3212,34 @@ func (mr *MockQuerierMockRecorder) MarkEmptyRecordingPartsWithoutFreshHumans(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmptyRecordingPartsWithoutFreshHumans", reflect.TypeOf((*MockQuerier)(nil).MarkEmptyRecordingPartsWithoutFreshHumans), ctx, freshSeconds)
 }
 
+// MarkExternalCalendarFailed mocks base method.
+func (m *MockQuerier) MarkExternalCalendarFailed(ctx context.Context, arg db.MarkExternalCalendarFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExternalCalendarFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkExternalCalendarFailed indicates an expected call of MarkExternalCalendarFailed.
+func (mr *MockQuerierMockRecorder) MarkExternalCalendarFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExternalCalendarFailed", reflect.TypeOf((*MockQuerier)(nil).MarkExternalCalendarFailed), ctx, arg)
+}
+
+// MarkExternalCalendarSynced mocks base method.
+func (m *MockQuerier) MarkExternalCalendarSynced(ctx context.Context, id pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkExternalCalendarSynced", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkExternalCalendarSynced indicates an expected call of MarkExternalCalendarSynced.
+func (mr *MockQuerierMockRecorder) MarkExternalCalendarSynced(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkExternalCalendarSynced", reflect.TypeOf((*MockQuerier)(nil).MarkExternalCalendarSynced), ctx, id)
+}
+
 // MarkFeedbackDiscordFailed mocks base method.
 func (m *MockQuerier) MarkFeedbackDiscordFailed(ctx context.Context, arg db.MarkFeedbackDiscordFailedParams) error {
 	m.ctrl.T.Helper()
@@ -1783,155 +3475,346 @@ func (m *MockQuerier) MarkModerationReportDiscordSkipped(ctx context.Context, ar
 	return ret0
 }
 
-// MarkModerationReportDiscordSkipped indicates an expected call of MarkModerationReportDiscordSkipped.
-func (mr *MockQuerierMockRecorder) MarkModerationReportDiscordSkipped(ctx, arg any) *gomock.Call {
+// MarkModerationReportDiscordSkipped indicates an expected call of MarkModerationReportDiscordSkipped.
+func (mr *MockQuerierMockRecorder) MarkModerationReportDiscordSkipped(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkModerationReportDiscordSkipped", reflect.TypeOf((*MockQuerier)(nil).MarkModerationReportDiscordSkipped), ctx, arg)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockQuerier) MarkNotificationRead(ctx context.Context, arg db.MarkNotificationReadParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockQuerierMockRecorder) MarkNotificationRead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockQuerier)(nil).MarkNotificationRead), ctx, arg)
+}
+
+// MarkNotificationReadByInviteCode mocks base method.
+func (m *MockQuerier) MarkNotificationReadByInviteCode(ctx context.Context, arg db.MarkNotificationReadByInviteCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationReadByInviteCode", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationReadByInviteCode indicates an expected call of MarkNotificationReadByInviteCode.
+func (mr *MockQuerierMockRecorder) MarkNotificationReadByInviteCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationReadByInviteCode", reflect.TypeOf((*MockQuerier)(nil).MarkNotificationReadByInviteCode), ctx, arg)
+}
+
+// MarkRecordingPartFailed mocks base method.
+func (m *MockQuerier) MarkRecordingPartFailed(ctx context.Context, arg db.MarkRecordingPartFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingPartFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRecordingPartFailed indicates an expected call of MarkRecordingPartFailed.
+func (mr *MockQuerierMockRecorder) MarkRecordingPartFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartFailed", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartFailed), ctx, arg)
+}
+
+// MarkRecordingPartImportFailed mocks base method.
+func (m *MockQuerier) MarkRecordingPartImportFailed(ctx context.Context, arg db.MarkRecordingPartImportFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingPartImportFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRecordingPartImportFailed indicates an expected call of MarkRecordingPartImportFailed.
+func (mr *MockQuerierMockRecorder) MarkRecordingPartImportFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartImportFailed", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartImportFailed), ctx, arg)
+}
+
+// MarkRecordingPartImportMuxCreated mocks base method.
+func (m *MockQuerier) MarkRecordingPartImportMuxCreated(ctx context.Context, arg db.MarkRecordingPartImportMuxCreatedParams) (db.CoachingRecordingImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingPartImportMuxCreated", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingRecordingImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingPartImportMuxCreated indicates an expected call of MarkRecordingPartImportMuxCreated.
+func (mr *MockQuerierMockRecorder) MarkRecordingPartImportMuxCreated(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartImportMuxCreated", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartImportMuxCreated), ctx, arg)
+}
+
+// MarkRecordingPartImportReady mocks base method.
+func (m *MockQuerier) MarkRecordingPartImportReady(ctx context.Context, arg db.MarkRecordingPartImportReadyParams) (db.CoachingRecordingImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingPartImportReady", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingRecordingImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingPartImportReady indicates an expected call of MarkRecordingPartImportReady.
+func (mr *MockQuerierMockRecorder) MarkRecordingPartImportReady(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartImportReady", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartImportReady), ctx, arg)
+}
+
+// MarkRecordingPartStopped mocks base method.
+func (m *MockQuerier) MarkRecordingPartStopped(ctx context.Context, id pgtype.UUID) (db.CoachingBookingRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingPartStopped", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBookingRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingPartStopped indicates an expected call of MarkRecordingPartStopped.
+func (mr *MockQuerierMockRecorder) MarkRecordingPartStopped(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartStopped", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartStopped), ctx, id)
+}
+
+// MarkRecordingPartStopping mocks base method.
+func (m *MockQuerier) MarkRecordingPartStopping(ctx context.Context, id pgtype.UUID) (db.CoachingBookingRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingPartStopping", ctx, id)
+	ret0, _ := ret[0].(db.CoachingBookingRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingPartStopping indicates an expected call of MarkRecordingPartStopping.
+func (mr *MockQuerierMockRecorder) MarkRecordingPartStopping(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartStopping", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartStopping), ctx, id)
+}
+
+// MarkRecordingRendererReady mocks base method.
+func (m *MockQuerier) MarkRecordingRendererReady(ctx context.Context, rendererTokenHash []byte) (pgtype.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingRendererReady", ctx, rendererTokenHash)
+	ret0, _ := ret[0].(pgtype.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingRendererReady indicates an expected call of MarkRecordingRendererReady.
+func (mr *MockQuerierMockRecorder) MarkRecordingRendererReady(ctx, rendererTokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingRendererReady", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingRendererReady), ctx, rendererTokenHash)
+}
+
+// MarkRecordingStitchFailed mocks base method.
+func (m *MockQuerier) MarkRecordingStitchFailed(ctx context.Context, arg db.MarkRecordingStitchFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingStitchFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRecordingStitchFailed indicates an expected call of MarkRecordingStitchFailed.
+func (mr *MockQuerierMockRecorder) MarkRecordingStitchFailed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingStitchFailed", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingStitchFailed), ctx, arg)
+}
+
+// MarkRecordingStitchMuxCreated mocks base method.
+func (m *MockQuerier) MarkRecordingStitchMuxCreated(ctx context.Context, arg db.MarkRecordingStitchMuxCreatedParams) (db.CoachingRecordingStitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingStitchMuxCreated", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingRecordingStitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingStitchMuxCreated indicates an expected call of MarkRecordingStitchMuxCreated.
+func (mr *MockQuerierMockRecorder) MarkRecordingStitchMuxCreated(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingStitchMuxCreated", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingStitchMuxCreated), ctx, arg)
+}
+
+// MarkRecordingStitchReady mocks base method.
+func (m *MockQuerier) MarkRecordingStitchReady(ctx context.Context, arg db.MarkRecordingStitchReadyParams) (db.CoachingRecordingStitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecordingStitchReady", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingRecordingStitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecordingStitchReady indicates an expected call of MarkRecordingStitchReady.
+func (mr *MockQuerierMockRecorder) MarkRecordingStitchReady(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkModerationReportDiscordSkipped", reflect.TypeOf((*MockQuerier)(nil).MarkModerationReportDiscordSkipped), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingStitchReady", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingStitchReady), ctx, arg)
 }
 
-// MarkNotificationRead mocks base method.
-func (m *MockQuerier) MarkNotificationRead(ctx context.Context, arg db.MarkNotificationReadParams) error {
+// MarkReminderSent mocks base method.
+func (m *MockQuerier) MarkReminderSent(ctx context.Context, id pgtype.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret := m.ctrl.Call(m, "MarkReminderSent", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MarkNotificationRead indicates an expected call of MarkNotificationRead.
-func (mr *MockQuerierMockRecorder) MarkNotificationRead(ctx, arg any) *gomock.Call {
+// MarkReminderSent indicates an expected call of MarkReminderSent.
+func (mr *MockQuerierMockRecorder) MarkReminderSent(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockQuerier)(nil).MarkNotificationRead), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderSent", reflect.TypeOf((*MockQuerier)(nil).MarkReminderSent), ctx, id)
 }
 
-// MarkNotificationReadByInviteCode mocks base method.
-func (m *MockQuerier) MarkNotificationReadByInviteCode(ctx context.Context, arg db.MarkNotificationReadByInviteCodeParams) error {
+// MarkVideoFailedFromMux mocks base method.
+func (m *MockQuerier) MarkVideoFailedFromMux(ctx context.Context, arg db.MarkVideoFailedFromMuxParams) ([]pgtype.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkNotificationReadByInviteCode", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkVideoFailedFromMux", ctx, arg)
+	ret0, _ := ret[0].([]pgtype.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MarkNotificationReadByInviteCode indicates an expected call of MarkNotificationReadByInviteCode.
-func (mr *MockQuerierMockRecorder) MarkNotificationReadByInviteCode(ctx, arg any) *gomock.Call {
+// MarkVideoFailedFromMux indicates an expected call of MarkVideoFailedFromMux.
+func (mr *MockQuerierMockRecorder) MarkVideoFailedFromMux(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationReadByInviteCode", reflect.TypeOf((*MockQuerier)(nil).MarkNotificationReadByInviteCode), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVideoFailedFromMux", reflect.TypeOf((*MockQuerier)(nil).MarkVideoFailedFromMux), ctx, arg)
 }
 
-// MarkRecordingPartFailed mocks base method.
-func (m *MockQuerier) MarkRecordingPartFailed(ctx context.Context, arg db.MarkRecordingPartFailedParams) error {
+// MarkVideoReadyFromMux mocks base method.
+func (m *MockQuerier) MarkVideoReadyFromMux(ctx context.Context, arg db.MarkVideoReadyFromMuxParams) ([]pgtype.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingPartFailed", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkVideoReadyFromMux", ctx, arg)
+	ret0, _ := ret[0].([]pgtype.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MarkRecordingPartFailed indicates an expected call of MarkRecordingPartFailed.
-func (mr *MockQuerierMockRecorder) MarkRecordingPartFailed(ctx, arg any) *gomock.Call {
+// MarkVideoReadyFromMux indicates an expected call of MarkVideoReadyFromMux.
+func (mr *MockQuerierMockRecorder) MarkVideoReadyFromMux(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartFailed", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartFailed), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVideoReadyFromMux", reflect.TypeOf((*MockQuerier)(nil).MarkVideoReadyFromMux), ctx, arg)
 }
 
-// MarkRecordingPartImportFailed mocks base method.
-func (m *MockQuerier) MarkRecordingPartImportFailed(ctx context.Context, arg db.MarkRecordingPartImportFailedParams) error {
+// MarkWaitlistEntryBooked mocks base method.
+func (m *MockQuerier) MarkWaitlistEntryBooked(ctx context.Context, id pgtype.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingPartImportFailed", ctx, arg)
+	ret := m.ctrl.Call(m, "MarkWaitlistEntryBooked", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MarkRecordingPartImportFailed indicates an expected call of MarkRecordingPartImportFailed.
-func (mr *MockQuerierMockRecorder) MarkRecordingPartImportFailed(ctx, arg any) *gomock.Call {
+// MarkWaitlistEntryBooked indicates an expected call of MarkWaitlistEntryBooked.
+func (mr *MockQuerierMockRecorder) MarkWaitlistEntryBooked(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartImportFailed", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartImportFailed), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWaitlistEntryBooked", reflect.TypeOf((*MockQuerier)(nil).MarkWaitlistEntryBooked), ctx, id)
 }
 
-// MarkRecordingPartImportMuxCreated mocks base method.
-func (m *MockQuerier) MarkRecordingPartImportMuxCreated(ctx context.Context, arg db.MarkRecordingPartImportMuxCreatedParams) (db.CoachingRecordingImport, error) {
+// MoveVideoChapters mocks base method.
+func (m *MockQuerier) MoveVideoChapters(ctx context.Context, arg db.MoveVideoChaptersParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingPartImportMuxCreated", ctx, arg)
-	ret0, _ := ret[0].(db.CoachingRecordingImport)
+	ret := m.ctrl.Call(m, "MoveVideoChapters", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkRecordingPartImportMuxCreated indicates an expected call of MarkRecordingPartImportMuxCreated.
-func (mr *MockQuerierMockRecorder) MarkRecordingPartImportMuxCreated(ctx, arg any) *gomock.Call {
+// MoveVideoChapters indicates an expected call of MoveVideoChapters.
+func (mr *MockQuerierMockRecorder) MoveVideoChapters(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartImportMuxCreated", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartImportMuxCreated), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveVideoChapters", reflect.TypeOf((*MockQuerier)(nil).MoveVideoChapters), ctx, arg)
 }
 
-// MarkRecordingPartImportReady mocks base method.
-func (m *MockQuerier) MarkRecordingPartImportReady(ctx context.Context, arg db.MarkRecordingPartImportReadyParams) (db.CoachingRecordingImport, error) {
+// MoveVideoReviews mocks base method.
+func (m *MockQuerier) MoveVideoReviews(ctx context.Context, arg db.MoveVideoReviewsParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingPartImportReady", ctx, arg)
-	ret0, _ := ret[0].(db.CoachingRecordingImport)
+	ret := m.ctrl.Call(m, "MoveVideoReviews", ctx, arg)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkRecordingPartImportReady indicates an expected call of MarkRecordingPartImportReady.
-func (mr *MockQuerierMockRecorder) MarkRecordingPartImportReady(ctx, arg any) *gomock.Call {
+// MoveVideoReviews indicates an expected call of MoveVideoReviews.
+func (mr *MockQuerierMockRecorder) MoveVideoReviews(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartImportReady", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartImportReady), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveVideoReviews", reflect.TypeOf((*MockQuerier)(nil).MoveVideoReviews), ctx, arg)
 }
 
-// MarkRecordingPartStopped mocks base method.
-func (m *MockQuerier) MarkRecordingPartStopped(ctx context.Context, id pgtype.UUID) (db.CoachingBookingRecording, error) {
+// PromoteUploadedAsset mocks base method.
+func (m *MockQuerier) PromoteUploadedAsset(ctx context.Context, id pgtype.UUID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingPartStopped", ctx, id)
-	ret0, _ := ret[0].(db.CoachingBookingRecording)
+	ret := m.ctrl.Call(m, "PromoteUploadedAsset", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkRecordingPartStopped indicates an expected call of MarkRecordingPartStopped.
-func (mr *MockQuerierMockRecorder) MarkRecordingPartStopped(ctx, id any) *gomock.Call {
+// PromoteUploadedAsset indicates an expected call of PromoteUploadedAsset.
+func (mr *MockQuerierMockRecorder) PromoteUploadedAsset(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartStopped", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartStopped), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteUploadedAsset", reflect.TypeOf((*MockQuerier)(nil).PromoteUploadedAsset), ctx, id)
 }
 
-// MarkRecordingPartStopping mocks base method.
-func (m *MockQuerier) MarkRecordingPartStopping(ctx context.Context, id pgtype.UUID) (db.CoachingBookingRecording, error) {
+// PurgeAsset mocks base method.
+func (m *MockQuerier) PurgeAsset(ctx context.Context, id pgtype.UUID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingPartStopping", ctx, id)
-	ret0, _ := ret[0].(db.CoachingBookingRecording)
+	ret := m.ctrl.Call(m, "PurgeAsset", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkRecordingPartStopping indicates an expected call of MarkRecordingPartStopping.
-func (mr *MockQuerierMockRecorder) MarkRecordingPartStopping(ctx, id any) *gomock.Call {
+// PurgeAsset indicates an expected call of PurgeAsset.
+func (mr *MockQuerierMockRecorder) PurgeAsset(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingPartStopping", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingPartStopping), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeAsset", reflect.TypeOf((*MockQuerier)(nil).PurgeAsset), ctx, id)
 }
 
-// MarkRecordingRendererReady mocks base method.
-func (m *MockQuerier) MarkRecordingRendererReady(ctx context.Context, rendererTokenHash []byte) (pgtype.UUID, error) {
+// PurgeVideo mocks base method.
+func (m *MockQuerier) PurgeVideo(ctx context.Context, id pgtype.UUID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkRecordingRendererReady", ctx, rendererTokenHash)
-	ret0, _ := ret[0].(pgtype.UUID)
+	ret := m.ctrl.Call(m, "PurgeVideo", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkRecordingRendererReady indicates an expected call of MarkRecordingRendererReady.
-func (mr *MockQuerierMockRecorder) MarkRecordingRendererReady(ctx, rendererTokenHash any) *gomock.Call {
+// PurgeVideo indicates an expected call of PurgeVideo.
+func (mr *MockQuerierMockRecorder) PurgeVideo(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecordingRendererReady", reflect.TypeOf((*MockQuerier)(nil).MarkRecordingRendererReady), ctx, rendererTokenHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeVideo", reflect.TypeOf((*MockQuerier)(nil).PurgeVideo), ctx, id)
 }
 
-// MarkReminderSent mocks base method.
-func (m *MockQuerier) MarkReminderSent(ctx context.Context, id pgtype.UUID) error {
+// RecordBookingAttendance mocks base method.
+func (m *MockQuerier) RecordBookingAttendance(ctx context.Context, arg db.RecordBookingAttendanceParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkReminderSent", ctx, id)
+	ret := m.ctrl.Call(m, "RecordBookingAttendance", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MarkReminderSent indicates an expected call of MarkReminderSent.
-func (mr *MockQuerierMockRecorder) MarkReminderSent(ctx, id any) *gomock.Call {
+// RecordBookingAttendance indicates an expected call of RecordBookingAttendance.
+func (mr *MockQuerierMockRecorder) RecordBookingAttendance(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderSent", reflect.TypeOf((*MockQuerier)(nil).MarkReminderSent), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBookingAttendance", reflect.TypeOf((*MockQuerier)(nil).RecordBookingAttendance), ctx, arg)
+}
+
+// RecordSnippetUse mocks base method.
+func (m *MockQuerier) RecordSnippetUse(ctx context.Context, id pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSnippetUse", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSnippetUse indicates an expected call of RecordSnippetUse.
+func (mr *MockQuerierMockRecorder) RecordSnippetUse(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSnippetUse", reflect.TypeOf((*MockQuerier)(nil).RecordSnippetUse), ctx, id)
 }
 
 // RefreshBookingPresence mocks base method.
@@ -1992,6 +3875,21 @@ func (mr *MockQuerierMockRecorder) RemoveBookingPresence(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBookingPresence", reflect.TypeOf((*MockQuerier)(nil).RemoveBookingPresence), ctx, arg)
 }
 
+// RemoveReviewReaction mocks base method.
+func (m *MockQuerier) RemoveReviewReaction(ctx context.Context, arg db.RemoveReviewReactionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReviewReaction", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReviewReaction indicates an expected call of RemoveReviewReaction.
+func (mr *MockQuerierMockRecorder) RemoveReviewReaction(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReviewReaction", reflect.TypeOf((*MockQuerier)(nil).RemoveReviewReaction), ctx, arg)
+}
+
 // RemoveUserFromGroup mocks base method.
 func (m *MockQuerier) RemoveUserFromGroup(ctx context.Context, arg db.RemoveUserFromGroupParams) error {
 	m.ctrl.T.Helper()
@@ -2006,6 +3904,20 @@ func (mr *MockQuerierMockRecorder) RemoveUserFromGroup(ctx, arg any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserFromGroup", reflect.TypeOf((*MockQuerier)(nil).RemoveUserFromGroup), ctx, arg)
 }
 
+// ReopenWaitlistEntryForBooking mocks base method.
+func (m *MockQuerier) ReopenWaitlistEntryForBooking(ctx context.Context, bookingID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReopenWaitlistEntryForBooking", ctx, bookingID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReopenWaitlistEntryForBooking indicates an expected call of ReopenWaitlistEntryForBooking.
+func (mr *MockQuerierMockRecorder) ReopenWaitlistEntryForBooking(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenWaitlistEntryForBooking", reflect.TypeOf((*MockQuerier)(nil).ReopenWaitlistEntryForBooking), ctx, bookingID)
+}
+
 // ReportSessionEventsForExpert mocks base method.
 func (m *MockQuerier) ReportSessionEventsForExpert(ctx context.Context, expertID string) ([]db.ReportSessionEventsForExpertRow, error) {
 	m.ctrl.T.Helper()
@@ -2066,6 +3978,66 @@ func (mr *MockQuerierMockRecorder) ReportUploadEventsForStudent(ctx, studentID a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportUploadEventsForStudent", reflect.TypeOf((*MockQuerier)(nil).ReportUploadEventsForStudent), ctx, studentID)
 }
 
+// RescheduleBooking mocks base method.
+func (m *MockQuerier) RescheduleBooking(ctx context.Context, arg db.RescheduleBookingParams) (db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleBooking", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RescheduleBooking indicates an expected call of RescheduleBooking.
+func (mr *MockQuerierMockRecorder) RescheduleBooking(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleBooking", reflect.TypeOf((*MockQuerier)(nil).RescheduleBooking), ctx, arg)
+}
+
+// RespondToBookingReschedule mocks base method.
+func (m *MockQuerier) RespondToBookingReschedule(ctx context.Context, arg db.RespondToBookingRescheduleParams) (db.CoachingBookingReschedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToBookingReschedule", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingBookingReschedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToBookingReschedule indicates an expected call of RespondToBookingReschedule.
+func (mr *MockQuerierMockRecorder) RespondToBookingReschedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToBookingReschedule", reflect.TypeOf((*MockQuerier)(nil).RespondToBookingReschedule), ctx, arg)
+}
+
+// RespondToWaitlistHold mocks base method.
+func (m *MockQuerier) RespondToWaitlistHold(ctx context.Context, arg db.RespondToWaitlistHoldParams) (db.CoachingWaitlistHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToWaitlistHold", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingWaitlistHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToWaitlistHold indicates an expected call of RespondToWaitlistHold.
+func (mr *MockQuerierMockRecorder) RespondToWaitlistHold(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToWaitlistHold", reflect.TypeOf((*MockQuerier)(nil).RespondToWaitlistHold), ctx, arg)
+}
+
+// RestoreCredit mocks base method.
+func (m *MockQuerier) RestoreCredit(ctx context.Context, arg db.RestoreCreditParams) (db.CoachingCreditGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCredit", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingCreditGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCredit indicates an expected call of RestoreCredit.
+func (mr *MockQuerierMockRecorder) RestoreCredit(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCredit", reflect.TypeOf((*MockQuerier)(nil).RestoreCredit), ctx, arg)
+}
+
 // RevokeGroupInvitation mocks base method.
 func (m *MockQuerier) RevokeGroupInvitation(ctx context.Context, arg db.RevokeGroupInvitationParams) (db.GroupInvitation, error) {
 	m.ctrl.T.Helper()
@@ -2081,6 +4053,21 @@ func (mr *MockQuerierMockRecorder) RevokeGroupInvitation(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeGroupInvitation", reflect.TypeOf((*MockQuerier)(nil).RevokeGroupInvitation), ctx, arg)
 }
 
+// SealAuditChainHead mocks base method.
+func (m *MockQuerier) SealAuditChainHead(ctx context.Context, arg db.SealAuditChainHeadParams) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SealAuditChainHead", ctx, arg)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SealAuditChainHead indicates an expected call of SealAuditChainHead.
+func (mr *MockQuerierMockRecorder) SealAuditChainHead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SealAuditChainHead", reflect.TypeOf((*MockQuerier)(nil).SealAuditChainHead), ctx, arg)
+}
+
 // SeedUserPreferences mocks base method.
 func (m *MockQuerier) SeedUserPreferences(ctx context.Context, arg db.SeedUserPreferencesParams) (db.UserPreference, error) {
 	m.ctrl.T.Helper()
@@ -2111,6 +4098,36 @@ func (mr *MockQuerierMockRecorder) SeedUserPreferencesWithAvatar(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeedUserPreferencesWithAvatar", reflect.TypeOf((*MockQuerier)(nil).SeedUserPreferencesWithAvatar), ctx, arg)
 }
 
+// SetBookingAttendance mocks base method.
+func (m *MockQuerier) SetBookingAttendance(ctx context.Context, arg db.SetBookingAttendanceParams) (db.CoachingBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookingAttendance", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookingAttendance indicates an expected call of SetBookingAttendance.
+func (mr *MockQuerierMockRecorder) SetBookingAttendance(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookingAttendance", reflect.TypeOf((*MockQuerier)(nil).SetBookingAttendance), ctx, arg)
+}
+
+// SetHomeworkCompleted mocks base method.
+func (m *MockQuerier) SetHomeworkCompleted(ctx context.Context, arg db.SetHomeworkCompletedParams) (db.CoachingHomework, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHomeworkCompleted", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingHomework)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHomeworkCompleted indicates an expected call of SetHomeworkCompleted.
+func (mr *MockQuerierMockRecorder) SetHomeworkCompleted(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHomeworkCompleted", reflect.TypeOf((*MockQuerier)(nil).SetHomeworkCompleted), ctx, arg)
+}
+
 // SetRecordingPartProviderStarted mocks base method.
 func (m *MockQuerier) SetRecordingPartProviderStarted(ctx context.Context, arg db.SetRecordingPartProviderStartedParams) (db.CoachingBookingRecording, error) {
 	m.ctrl.T.Helper()
@@ -2126,6 +4143,21 @@ func (mr *MockQuerierMockRecorder) SetRecordingPartProviderStarted(ctx, arg any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecordingPartProviderStarted", reflect.TypeOf((*MockQuerier)(nil).SetRecordingPartProviderStarted), ctx, arg)
 }
 
+// SetReviewThreadState mocks base method.
+func (m *MockQuerier) SetReviewThreadState(ctx context.Context, arg db.SetReviewThreadStateParams) (db.VideoReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewThreadState", ctx, arg)
+	ret0, _ := ret[0].(db.VideoReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReviewThreadState indicates an expected call of SetReviewThreadState.
+func (mr *MockQuerierMockRecorder) SetReviewThreadState(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewThreadState", reflect.TypeOf((*MockQuerier)(nil).SetReviewThreadState), ctx, arg)
+}
+
 // SetVideoDurationByID mocks base method.
 func (m *MockQuerier) SetVideoDurationByID(ctx context.Context, arg db.SetVideoDurationByIDParams) error {
 	m.ctrl.T.Helper()
@@ -2154,6 +4186,51 @@ func (mr *MockQuerierMockRecorder) SetVideoDurationByUploadID(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoDurationByUploadID", reflect.TypeOf((*MockQuerier)(nil).SetVideoDurationByUploadID), ctx, arg)
 }
 
+// SoftDeleteAsset mocks base method.
+func (m *MockQuerier) SoftDeleteAsset(ctx context.Context, arg db.SoftDeleteAssetParams) (db.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteAsset", ctx, arg)
+	ret0, _ := ret[0].(db.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteAsset indicates an expected call of SoftDeleteAsset.
+func (mr *MockQuerierMockRecorder) SoftDeleteAsset(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteAsset", reflect.TypeOf((*MockQuerier)(nil).SoftDeleteAsset), ctx, arg)
+}
+
+// SoftDeleteVideo mocks base method.
+func (m *MockQuerier) SoftDeleteVideo(ctx context.Context, arg db.SoftDeleteVideoParams) (db.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteVideo", ctx, arg)
+	ret0, _ := ret[0].(db.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SoftDeleteVideo indicates an expected call of SoftDeleteVideo.
+func (mr *MockQuerierMockRecorder) SoftDeleteVideo(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteVideo", reflect.TypeOf((*MockQuerier)(nil).SoftDeleteVideo), ctx, arg)
+}
+
+// TouchCalendarFeedByTokenHash mocks base method.
+func (m *MockQuerier) TouchCalendarFeedByTokenHash(ctx context.Context, tokenHash []byte) (db.CoachingCalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchCalendarFeedByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(db.CoachingCalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TouchCalendarFeedByTokenHash indicates an expected call of TouchCalendarFeedByTokenHash.
+func (mr *MockQuerierMockRecorder) TouchCalendarFeedByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchCalendarFeedByTokenHash", reflect.TypeOf((*MockQuerier)(nil).TouchCalendarFeedByTokenHash), ctx, tokenHash)
+}
+
 // UpdateAssetStatus mocks base method.
 func (m *MockQuerier) UpdateAssetStatus(ctx context.Context, arg db.UpdateAssetStatusParams) error {
 	m.ctrl.T.Helper()
@@ -2212,6 +4289,21 @@ func (mr *MockQuerierMockRecorder) UpdateGroupInvitationStatus(ctx, arg any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupInvitationStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateGroupInvitationStatus), ctx, arg)
 }
 
+// UpdateHomework mocks base method.
+func (m *MockQuerier) UpdateHomework(ctx context.Context, arg db.UpdateHomeworkParams) (db.CoachingHomework, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHomework", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingHomework)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHomework indicates an expected call of UpdateHomework.
+func (mr *MockQuerierMockRecorder) UpdateHomework(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHomework", reflect.TypeOf((*MockQuerier)(nil).UpdateHomework), ctx, arg)
+}
+
 // UpdateInboundEmailContent mocks base method.
 func (m *MockQuerier) UpdateInboundEmailContent(ctx context.Context, arg db.UpdateInboundEmailContentParams) error {
 	m.ctrl.T.Helper()
@@ -2271,6 +4363,21 @@ func (mr *MockQuerierMockRecorder) UpdateSessionType(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionType", reflect.TypeOf((*MockQuerier)(nil).UpdateSessionType), ctx, arg)
 }
 
+// UpdateSnippet mocks base method.
+func (m *MockQuerier) UpdateSnippet(ctx context.Context, arg db.UpdateSnippetParams) (db.ReviewSnippet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSnippet", ctx, arg)
+	ret0, _ := ret[0].(db.ReviewSnippet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSnippet indicates an expected call of UpdateSnippet.
+func (mr *MockQuerierMockRecorder) UpdateSnippet(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSnippet", reflect.TypeOf((*MockQuerier)(nil).UpdateSnippet), ctx, arg)
+}
+
 // UpdateUserAvatar mocks base method.
 func (m *MockQuerier) UpdateUserAvatar(ctx context.Context, arg db.UpdateUserAvatarParams) (db.UserPreference, error) {
 	m.ctrl.T.Helper()
@@ -2331,6 +4438,21 @@ func (mr *MockQuerierMockRecorder) UpdateUserPushPreferences(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPushPreferences", reflect.TypeOf((*MockQuerier)(nil).UpdateUserPushPreferences), ctx, arg)
 }
 
+// UpdateVideoChapter mocks base method.
+func (m *MockQuerier) UpdateVideoChapter(ctx context.Context, arg db.UpdateVideoChapterParams) (db.VideoChapter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideoChapter", ctx, arg)
+	ret0, _ := ret[0].(db.VideoChapter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVideoChapter indicates an expected call of UpdateVideoChapter.
+func (mr *MockQuerierMockRecorder) UpdateVideoChapter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideoChapter", reflect.TypeOf((*MockQuerier)(nil).UpdateVideoChapter), ctx, arg)
+}
+
 // UpdateVideoMuxAssetID mocks base method.
 func (m *MockQuerier) UpdateVideoMuxAssetID(ctx context.Context, arg db.UpdateVideoMuxAssetIDParams) error {
 	m.ctrl.T.Helper()
@@ -2403,6 +4525,21 @@ func (mr *MockQuerierMockRecorder) UpsertBookingPresence(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBookingPresence", reflect.TypeOf((*MockQuerier)(nil).UpsertBookingPresence), ctx, arg)
 }
 
+// UpsertCalendarFeed mocks base method.
+func (m *MockQuerier) UpsertCalendarFeed(ctx context.Context, arg db.UpsertCalendarFeedParams) (db.CoachingCalendarFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCalendarFeed", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingCalendarFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCalendarFeed indicates an expected call of UpsertCalendarFeed.
+func (mr *MockQuerierMockRecorder) UpsertCalendarFeed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCalendarFeed", reflect.TypeOf((*MockQuerier)(nil).UpsertCalendarFeed), ctx, arg)
+}
+
 // UpsertDevice mocks base method.
 func (m *MockQuerier) UpsertDevice(ctx context.Context, arg db.UpsertDeviceParams) (db.UserDevice, error) {
 	m.ctrl.T.Helper()
@@ -2432,3 +4569,33 @@ func (mr *MockQuerierMockRecorder) UpsertInboundEmail(ctx, arg any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertInboundEmail", reflect.TypeOf((*MockQuerier)(nil).UpsertInboundEmail), ctx, arg)
 }
+
+// UpsertSessionNotes mocks base method.
+func (m *MockQuerier) UpsertSessionNotes(ctx context.Context, arg db.UpsertSessionNotesParams) (db.CoachingSessionNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertSessionNotes", ctx, arg)
+	ret0, _ := ret[0].(db.CoachingSessionNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertSessionNotes indicates an expected call of UpsertSessionNotes.
+func (mr *MockQuerierMockRecorder) UpsertSessionNotes(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSessionNotes", reflect.TypeOf((*MockQuerier)(nil).UpsertSessionNotes), ctx, arg)
+}
+
+// WithdrawPendingBookingReschedule mocks base method.
+func (m *MockQuerier) WithdrawPendingBookingReschedule(ctx context.Context, arg db.WithdrawPendingBookingRescheduleParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawPendingBookingReschedule", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawPendingBookingReschedule indicates an expected call of WithdrawPendingBookingReschedule.
+func (mr *MockQuerierMockRecorder) WithdrawPendingBookingReschedule(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawPendingBookingReschedule", reflect.TypeOf((*MockQuerier)(nil).WithdrawPendingBookingReschedule), ctx, arg)
+}
@@ -96,6 +96,50 @@ func (ns NullAssetStatus) Value() (driver.Value, error) {
 	return string(ns.AssetStatus), nil
 }
 
+type CoachingAttendanceOutcome string
+
+const (
+	CoachingAttendanceOutcomeAttended      CoachingAttendanceOutcome = "attended"
+	CoachingAttendanceOutcomeStudentNoShow CoachingAttendanceOutcome = "student_no_show"
+	CoachingAttendanceOutcomeExpertNoShow  CoachingAttendanceOutcome = "expert_no_show"
+	CoachingAttendanceOutcomePartial       CoachingAttendanceOutcome = "partial"
+)
+
+func (e *CoachingAttendanceOutcome) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CoachingAttendanceOutcome(s)
+	case string:
+		*e = CoachingAttendanceOutcome(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CoachingAttendanceOutcome: %T", src)
+	}
+	return nil
+}
+
+type NullCoachingAttendanceOutcome struct {
+	CoachingAttendanceOutcome CoachingAttendanceOutcome `json:"coaching_attendance_outcome"`
+	Valid                     bool                      `json:"valid"` // Valid is true if CoachingAttendanceOutcome is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCoachingAttendanceOutcome) Scan(value interface{}) error {
+	if value == nil {
+		ns.CoachingAttendanceOutcome, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CoachingAttendanceOutcome.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCoachingAttendanceOutcome) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CoachingAttendanceOutcome), nil
+}
+
 type CoachingRecordingImportStatus string
 
 const (
@@ -141,6 +185,94 @@ func (ns NullCoachingRecordingImportStatus) Value() (driver.Value, error) {
 	return string(ns.CoachingRecordingImportStatus), nil
 }
 
+type CoachingPaymentStatus string
+
+const (
+	CoachingPaymentStatusPending  CoachingPaymentStatus = "pending"
+	CoachingPaymentStatusPaid     CoachingPaymentStatus = "paid"
+	CoachingPaymentStatusExpired  CoachingPaymentStatus = "expired"
+	CoachingPaymentStatusRefunded CoachingPaymentStatus = "refunded"
+)
+
+func (e *CoachingPaymentStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CoachingPaymentStatus(s)
+	case string:
+		*e = CoachingPaymentStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CoachingPaymentStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCoachingPaymentStatus struct {
+	CoachingPaymentStatus CoachingPaymentStatus `json:"coaching_payment_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if CoachingPaymentStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCoachingPaymentStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CoachingPaymentStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CoachingPaymentStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCoachingPaymentStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CoachingPaymentStatus), nil
+}
+
+type CoachingCreditEntryKind string
+
+const (
+	CoachingCreditEntryKindGrant   CoachingCreditEntryKind = "grant"
+	CoachingCreditEntryKindConsume CoachingCreditEntryKind = "consume"
+	CoachingCreditEntryKindRestore CoachingCreditEntryKind = "restore"
+	CoachingCreditEntryKindExpire  CoachingCreditEntryKind = "expire"
+)
+
+func (e *CoachingCreditEntryKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CoachingCreditEntryKind(s)
+	case string:
+		*e = CoachingCreditEntryKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CoachingCreditEntryKind: %T", src)
+	}
+	return nil
+}
+
+type NullCoachingCreditEntryKind struct {
+	CoachingCreditEntryKind CoachingCreditEntryKind `json:"coaching_credit_entry_kind"`
+	Valid                   bool                    `json:"valid"` // Valid is true if CoachingCreditEntryKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCoachingCreditEntryKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.CoachingCreditEntryKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CoachingCreditEntryKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCoachingCreditEntryKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CoachingCreditEntryKind), nil
+}
+
 type CoachingRecordingStatus string
 
 const (
@@ -186,6 +318,94 @@ func (ns NullCoachingRecordingStatus) Value() (driver.Value, error) {
 	return string(ns.CoachingRecordingStatus), nil
 }
 
+type CoachingRescheduleStatus string
+
+const (
+	CoachingRescheduleStatusPending   CoachingRescheduleStatus = "pending"
+	CoachingRescheduleStatusAccepted  CoachingRescheduleStatus = "accepted"
+	CoachingRescheduleStatusDeclined  CoachingRescheduleStatus = "declined"
+	CoachingRescheduleStatusWithdrawn CoachingRescheduleStatus = "withdrawn"
+)
+
+func (e *CoachingRescheduleStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CoachingRescheduleStatus(s)
+	case string:
+		*e = CoachingRescheduleStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CoachingRescheduleStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCoachingRescheduleStatus struct {
+	CoachingRescheduleStatus CoachingRescheduleStatus `json:"coaching_reschedule_status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if CoachingRescheduleStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCoachingRescheduleStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CoachingRescheduleStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CoachingRescheduleStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCoachingRescheduleStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CoachingRescheduleStatus), nil
+}
+
+type CoachingWaitlistHoldStatus string
+
+const (
+	CoachingWaitlistHoldStatusOffered   CoachingWaitlistHoldStatus = "offered"
+	CoachingWaitlistHoldStatusConfirmed CoachingWaitlistHoldStatus = "confirmed"
+	CoachingWaitlistHoldStatusDeclined  CoachingWaitlistHoldStatus = "declined"
+	CoachingWaitlistHoldStatusExpired   CoachingWaitlistHoldStatus = "expired"
+)
+
+func (e *CoachingWaitlistHoldStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CoachingWaitlistHoldStatus(s)
+	case string:
+		*e = CoachingWaitlistHoldStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CoachingWaitlistHoldStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCoachingWaitlistHoldStatus struct {
+	CoachingWaitlistHoldStatus CoachingWaitlistHoldStatus `json:"coaching_waitlist_hold_status"`
+	Valid                      bool                       `json:"valid"` // Valid is true if CoachingWaitlistHoldStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCoachingWaitlistHoldStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CoachingWaitlistHoldStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CoachingWaitlistHoldStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCoachingWaitlistHoldStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CoachingWaitlistHoldStatus), nil
+}
+
 type InvitationStatus string
 
 const (
@@ -276,12 +496,21 @@ func (ns NullLanguageCode) Value() (driver.Value, error) {
 type NotificationType string
 
 const (
-	NotificationTypeGroupInvitationReceived  NotificationType = "group_invitation_received"
-	NotificationTypeGroupMemberJoined        NotificationType = "group_member_joined"
-	NotificationTypeVideoReviewed            NotificationType = "video_reviewed"
-	NotificationTypeVideoUploaded            NotificationType = "video_uploaded"
-	NotificationTypeCoachingBookingCreated   NotificationType = "coaching_booking_created"
-	NotificationTypeCoachingBookingCancelled NotificationType = "coaching_booking_cancelled"
+	NotificationTypeGroupInvitationReceived           NotificationType = "group_invitation_received"
+	NotificationTypeGroupMemberJoined                 NotificationType = "group_member_joined"
+	NotificationTypeVideoReviewed                     NotificationType = "video_reviewed"
+	NotificationTypeVideoUploaded                     NotificationType = "video_uploaded"
+	NotificationTypeCoachingBookingCreated            NotificationType = "coaching_booking_created"
+	NotificationTypeCoachingBookingCancelled          NotificationType = "coaching_booking_cancelled"
+	NotificationTypeReviewThreadUpdated               NotificationType = "review_thread_updated"
+	NotificationTypeReviewReactionAdded               NotificationType = "review_reaction_added"
+	NotificationTypeCoachingBookingRescheduleProposed NotificationType = "coaching_booking_reschedule_proposed"
+	NotificationTypeCoachingBookingRescheduleDeclined NotificationType = "coaching_booking_reschedule_declined"
+	NotificationTypeCoachingBookingRescheduled        NotificationType = "coaching_booking_rescheduled"
+	NotificationTypeCoachingWaitlistSlotOffered       NotificationType = "coaching_waitlist_slot_offered"
+	NotificationTypeCoachingSessionNotesShared        NotificationType = "coaching_session_notes_shared"
+	NotificationTypeCoachingHomeworkAssigned          NotificationType = "coaching_homework_assigned"
+	NotificationTypeCoachingBookingNoShow             NotificationType = "coaching_booking_no_show"
 )
 
 func (e *NotificationType) Scan(src interface{}) error {
@@ -319,6 +548,49 @@ func (ns NullNotificationType) Value() (driver.Value, error) {
 	return string(ns.NotificationType), nil
 }
 
+type ReviewThreadState string
+
+const (
+	ReviewThreadStateOpen         ReviewThreadState = "open"
+	ReviewThreadStateAcknowledged ReviewThreadState = "acknowledged"
+	ReviewThreadStateResolved     ReviewThreadState = "resolved"
+)
+
+func (e *ReviewThreadState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReviewThreadState(s)
+	case string:
+		*e = ReviewThreadState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReviewThreadState: %T", src)
+	}
+	return nil
+}
+
+type NullReviewThreadState struct {
+	ReviewThreadState ReviewThreadState `json:"review_thread_state"`
+	Valid             bool              `json:"valid"` // Valid is true if ReviewThreadState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReviewThreadState) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReviewThreadState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReviewThreadState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReviewThreadState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReviewThreadState), nil
+}
+
 type SignupCodeStatus string
 
 const (
@@ -405,14 +677,38 @@ func (ns NullVideoStatus) Value() (driver.Value, error) {
 }
 
 type Asset struct {
-	ID          pgtype.UUID        `json:"id"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	Status      AssetStatus        `json:"status"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	GroupID     pgtype.UUID        `json:"group_id"`
-	OwnerID     string             `json:"owner_id"`
+	ID                pgtype.UUID        `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Status            AssetStatus        `json:"status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	GroupID           pgtype.UUID        `json:"group_id"`
+	OwnerID           string             `json:"owner_id"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy         pgtype.Text        `json:"deleted_by"`
+	LastReviewAt      pgtype.Timestamptz `json:"last_review_at"`
+	ReviewThreads     int32              `json:"review_threads"`
+	OpenReviewThreads int32              `json:"open_review_threads"`
+}
+
+type AssetViewer struct {
+	AssetID   pgtype.UUID        `json:"asset_id"`
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type AuditChainHead struct {
+	PartitionName string             `json:"partition_name"`
+	GenesisHash   []byte             `json:"genesis_hash"`
+	ChainedSince  pgtype.Timestamptz `json:"chained_since"`
+	HeadSeq       int64              `json:"head_seq"`
+	HeadHash      []byte             `json:"head_hash"`
+	SealedSeq     pgtype.Int8        `json:"sealed_seq"`
+	SealedHash    []byte             `json:"sealed_hash"`
+	DroppedAt     pgtype.Timestamptz `json:"dropped_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Shard         int32              `json:"shard"`
 }
 
 type AuditEvent struct {
@@ -428,6 +724,19 @@ type AuditEvent struct {
 	OldValues    []byte             `json:"old_values"`
 	NewValues    []byte             `json:"new_values"`
 	Metadata     []byte             `json:"metadata"`
+	ChainSeq     pgtype.Int8        `json:"chain_seq"`
+	PrevHash     []byte             `json:"prev_hash"`
+	Hash         []byte             `json:"hash"`
+	ChainShard   int32              `json:"chain_shard"`
+}
+
+type CoachingAbsence struct {
+	ID        pgtype.UUID        `json:"id"`
+	ExpertID  string             `json:"expert_id"`
+	StartsOn  pgtype.Date        `json:"starts_on"`
+	EndsOn    pgtype.Date        `json:"ends_on"`
+	Reason    pgtype.Text        `json:"reason"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type CoachingAvailability struct {
@@ -442,6 +751,16 @@ type CoachingAvailability struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type CoachingAvailabilityOverride struct {
+	ID           pgtype.UUID        `json:"id"`
+	ExpertID     string             `json:"expert_id"`
+	GroupID      pgtype.UUID        `json:"group_id"`
+	OverrideDate pgtype.Date        `json:"override_date"`
+	StartTime    pgtype.Time        `json:"start_time"`
+	EndTime      pgtype.Time        `json:"end_time"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
 type CoachingBlockedSlot struct {
 	ID          pgtype.UUID        `json:"id"`
 	ExpertID    string             `json:"expert_id"`
@@ -453,21 +772,52 @@ type CoachingBlockedSlot struct {
 }
 
 type CoachingBooking struct {
-	ID                      pgtype.UUID        `json:"id"`
-	ExpertID                string             `json:"expert_id"`
-	StudentID               string             `json:"student_id"`
-	GroupID                 pgtype.UUID        `json:"group_id"`
-	SessionTypeID           pgtype.UUID        `json:"session_type_id"`
-	ScheduledAt             pgtype.Timestamptz `json:"scheduled_at"`
-	DurationMinutes         int32              `json:"duration_minutes"`
-	IsCancelled             bool               `json:"is_cancelled"`
-	CancellationReason      pgtype.Text        `json:"cancellation_reason"`
-	CancelledBy             pgtype.Text        `json:"cancelled_by"`
-	Notes                   pgtype.Text        `json:"notes"`
-	CreatedAt               pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
-	RecordingAssetID        pgtype.UUID        `json:"recording_asset_id"`
-	NextRecordingPartNumber int32              `json:"next_recording_part_number"`
+	ID                      pgtype.UUID                   `json:"id"`
+	ExpertID                string                        `json:"expert_id"`
+	StudentID               string                        `json:"student_id"`
+	GroupID                 pgtype.UUID                   `json:"group_id"`
+	SessionTypeID           pgtype.UUID                   `json:"session_type_id"`
+	ScheduledAt             pgtype.Timestamptz            `json:"scheduled_at"`
+	DurationMinutes         int32                         `json:"duration_minutes"`
+	IsCancelled             bool                          `json:"is_cancelled"`
+	CancellationReason      pgtype.Text                   `json:"cancellation_reason"`
+	CancelledBy             pgtype.Text                   `json:"cancelled_by"`
+	Notes                   pgtype.Text                   `json:"notes"`
+	CreatedAt               pgtype.Timestamptz            `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz            `json:"updated_at"`
+	RecordingAssetID        pgtype.UUID                   `json:"recording_asset_id"`
+	NextRecordingPartNumber int32                         `json:"next_recording_part_number"`
+	SeriesID                pgtype.UUID                   `json:"series_id"`
+	BufferBeforeMinutes     int32                         `json:"buffer_before_minutes"`
+	BufferAfterMinutes      int32                         `json:"buffer_after_minutes"`
+	PriceCents              int32                         `json:"price_cents"`
+	Currency                pgtype.Text                   `json:"currency"`
+	PaymentStatus           NullCoachingPaymentStatus     `json:"payment_status"`
+	PaymentExpiresAt        pgtype.Timestamptz            `json:"payment_expires_at"`
+	CreditGrantID           pgtype.UUID                   `json:"credit_grant_id"`
+	ClassID                 pgtype.UUID                   `json:"class_id"`
+	ClassSeat               pgtype.Int4                   `json:"class_seat"`
+	AttendanceOutcome       NullCoachingAttendanceOutcome `json:"attendance_outcome"`
+	AttendanceRecordedAt    pgtype.Timestamptz            `json:"attendance_recorded_at"`
+}
+
+type CoachingBookingAttendance struct {
+	BookingID       pgtype.UUID        `json:"booking_id"`
+	ParticipantID   string             `json:"participant_id"`
+	ParticipantRole string             `json:"participant_role"`
+	AttendedSeconds int32              `json:"attended_seconds"`
+	FirstSeenAt     pgtype.Timestamptz `json:"first_seen_at"`
+	LastSeenAt      pgtype.Timestamptz `json:"last_seen_at"`
+}
+
+type CoachingBookingMessage struct {
+	ID         pgtype.UUID        `json:"id"`
+	BookingID  pgtype.UUID        `json:"booking_id"`
+	AuthorID   string             `json:"author_id"`
+	AuthorRole string             `json:"author_role"`
+	Body       string             `json:"body"`
+	Links      []string           `json:"links"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type CoachingBookingPresence struct {
@@ -475,6 +825,7 @@ type CoachingBookingPresence struct {
 	ParticipantRole string             `json:"participant_role"`
 	ConnectionID    pgtype.UUID        `json:"connection_id"`
 	LastSeenAt      pgtype.Timestamptz `json:"last_seen_at"`
+	ParticipantID   string             `json:"participant_id"`
 }
 
 type CoachingBookingRecording struct {
@@ -498,11 +849,130 @@ type CoachingBookingRecording struct {
 }
 
 type CoachingBookingReminder struct {
+	ID         pgtype.UUID        `json:"id"`
+	BookingID  pgtype.UUID        `json:"booking_id"`
+	RemindAt   pgtype.Timestamptz `json:"remind_at"`
+	SentAt     pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	HomeworkID pgtype.UUID        `json:"homework_id"`
+}
+
+type CoachingBookingReschedule struct {
+	ID                  pgtype.UUID              `json:"id"`
+	BookingID           pgtype.UUID              `json:"booking_id"`
+	ProposedBy          string                   `json:"proposed_by"`
+	ScheduledAt         pgtype.Timestamptz       `json:"scheduled_at"`
+	PreviousScheduledAt pgtype.Timestamptz       `json:"previous_scheduled_at"`
+	Note                pgtype.Text              `json:"note"`
+	Status              CoachingRescheduleStatus `json:"status"`
+	RespondedBy         pgtype.Text              `json:"responded_by"`
+	RespondedAt         pgtype.Timestamptz       `json:"responded_at"`
+	CreatedAt           pgtype.Timestamptz       `json:"created_at"`
+}
+
+type CoachingBookingSeries struct {
+	ID              pgtype.UUID        `json:"id"`
+	ExpertID        string             `json:"expert_id"`
+	StudentID       string             `json:"student_id"`
+	GroupID         pgtype.UUID        `json:"group_id"`
+	SessionTypeID   pgtype.UUID        `json:"session_type_id"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	Timezone        string             `json:"timezone"`
+	IntervalWeeks   int32              `json:"interval_weeks"`
+	OccurrenceCount pgtype.Int4        `json:"occurrence_count"`
+	UntilDate       pgtype.Date        `json:"until_date"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Notes           pgtype.Text        `json:"notes"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type CoachingCalendarFeed struct {
+	UserID         string             `json:"user_id"`
+	TokenHash      []byte             `json:"token_hash"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	LastAccessedAt pgtype.Timestamptz `json:"last_accessed_at"`
+}
+
+type CoachingClass struct {
+	ID              pgtype.UUID        `json:"id"`
+	ExpertID        string             `json:"expert_id"`
+	GroupID         pgtype.UUID        `json:"group_id"`
+	SessionTypeID   pgtype.UUID        `json:"session_type_id"`
+	ScheduledAt     pgtype.Timestamptz `json:"scheduled_at"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Capacity        int32              `json:"capacity"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type CoachingCreditGrant struct {
 	ID        pgtype.UUID        `json:"id"`
-	BookingID pgtype.UUID        `json:"booking_id"`
-	RemindAt  pgtype.Timestamptz `json:"remind_at"`
-	SentAt    pgtype.Timestamptz `json:"sent_at"`
+	StudentID string             `json:"student_id"`
+	ExpertID  string             `json:"expert_id"`
+	GroupID   pgtype.UUID        `json:"group_id"`
+	Quantity  int32              `json:"quantity"`
+	Remaining int32              `json:"remaining"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Note      pgtype.Text        `json:"note"`
+	GrantedBy string             `json:"granted_by"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CoachingCreditLedger struct {
+	ID        pgtype.UUID             `json:"id"`
+	GrantID   pgtype.UUID             `json:"grant_id"`
+	StudentID string                  `json:"student_id"`
+	ExpertID  string                  `json:"expert_id"`
+	GroupID   pgtype.UUID             `json:"group_id"`
+	BookingID pgtype.UUID             `json:"booking_id"`
+	Kind      CoachingCreditEntryKind `json:"kind"`
+	Delta     int32                   `json:"delta"`
+	ActorID   pgtype.Text             `json:"actor_id"`
+	CreatedAt pgtype.Timestamptz      `json:"created_at"`
+}
+
+type CoachingExternalBusyBlock struct {
+	CalendarID pgtype.UUID        `json:"calendar_id"`
+	StartsAt   pgtype.Timestamptz `json:"starts_at"`
+	EndsAt     pgtype.Timestamptz `json:"ends_at"`
+}
+
+type CoachingExternalCalendar struct {
+	ID              pgtype.UUID        `json:"id"`
+	ExpertID        string             `json:"expert_id"`
+	Label           string             `json:"label"`
+	SourceUrl       pgtype.Text        `json:"source_url"`
+	LastAttemptedAt pgtype.Timestamptz `json:"last_attempted_at"`
+	LastSyncedAt    pgtype.Timestamptz `json:"last_synced_at"`
+	LastError       pgtype.Text        `json:"last_error"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type CoachingHomework struct {
+	ID          pgtype.UUID        `json:"id"`
+	BookingID   pgtype.UUID        `json:"booking_id"`
+	StudentID   string             `json:"student_id"`
+	ExpertID    string             `json:"expert_id"`
+	GroupID     pgtype.UUID        `json:"group_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	DueAt       pgtype.Timestamptz `json:"due_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type CoachingPayment struct {
+	BookingID         pgtype.UUID        `json:"booking_id"`
+	Provider          string             `json:"provider"`
+	CheckoutSessionID string             `json:"checkout_session_id"`
+	AmountCents       int32              `json:"amount_cents"`
+	Currency          string             `json:"currency"`
+	ProviderPaymentID pgtype.Text        `json:"provider_payment_id"`
+	PaidAt            pgtype.Timestamptz `json:"paid_at"`
+	RefundID          pgtype.Text        `json:"refund_id"`
+	RefundedAt        pgtype.Timestamptz `json:"refunded_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 }
 
 type CoachingRecordingImport struct {
@@ -522,16 +992,86 @@ type CoachingRecordingImport struct {
 	FileIndex     int32                         `json:"file_index"`
 }
 
+type CoachingRecordingStitch struct {
+	BookingID     pgtype.UUID                   `json:"booking_id"`
+	Status        CoachingRecordingImportStatus `json:"status"`
+	PartCount     int32                         `json:"part_count"`
+	ObjectName    pgtype.Text                   `json:"object_name"`
+	MuxAssetID    pgtype.Text                   `json:"mux_asset_id"`
+	MuxPlaybackID pgtype.Text                   `json:"mux_playback_id"`
+	VideoID       pgtype.UUID                   `json:"video_id"`
+	Attempts      int32                         `json:"attempts"`
+	LastAttemptAt pgtype.Timestamptz            `json:"last_attempt_at"`
+	StitchedAt    pgtype.Timestamptz            `json:"stitched_at"`
+	Error         pgtype.Text                   `json:"error"`
+	CreatedAt     pgtype.Timestamptz            `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz            `json:"updated_at"`
+}
+
+type CoachingSessionNote struct {
+	BookingID pgtype.UUID        `json:"booking_id"`
+	AuthorID  string             `json:"author_id"`
+	Summary   string             `json:"summary"`
+	WentWell  string             `json:"went_well"`
+	ToImprove string             `json:"to_improve"`
+	NextFocus string             `json:"next_focus"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type CoachingSessionType struct {
-	ID              pgtype.UUID        `json:"id"`
-	ExpertID        string             `json:"expert_id"`
-	GroupID         pgtype.UUID        `json:"group_id"`
-	Name            string             `json:"name"`
-	Description     string             `json:"description"`
-	DurationMinutes int32              `json:"duration_minutes"`
-	IsActive        bool               `json:"is_active"`
-	CreatedAt       pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ID                        pgtype.UUID        `json:"id"`
+	ExpertID                  string             `json:"expert_id"`
+	GroupID                   pgtype.UUID        `json:"group_id"`
+	Name                      string             `json:"name"`
+	Description               string             `json:"description"`
+	DurationMinutes           int32              `json:"duration_minutes"`
+	IsActive                  bool               `json:"is_active"`
+	CreatedAt                 pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                 pgtype.Timestamptz `json:"updated_at"`
+	BufferBeforeMinutes       int32              `json:"buffer_before_minutes"`
+	BufferAfterMinutes        int32              `json:"buffer_after_minutes"`
+	MaxSessionsPerDay         pgtype.Int4        `json:"max_sessions_per_day"`
+	BookingHorizonDays        pgtype.Int4        `json:"booking_horizon_days"`
+	MinBookingNoticeMinutes   pgtype.Int4        `json:"min_booking_notice_minutes"`
+	CancellationNoticeMinutes pgtype.Int4        `json:"cancellation_notice_minutes"`
+	PriceCents                int32              `json:"price_cents"`
+	Currency                  pgtype.Text        `json:"currency"`
+	RequiresCredit            bool               `json:"requires_credit"`
+	Capacity                  int32              `json:"capacity"`
+	NoShowCreditPolicy        string             `json:"no_show_credit_policy"`
+	NotifyOwnerOnNoShow       bool               `json:"notify_owner_on_no_show"`
+}
+
+type CoachingWaitlistEntry struct {
+	ID                pgtype.UUID        `json:"id"`
+	StudentID         string             `json:"student_id"`
+	ExpertID          string             `json:"expert_id"`
+	GroupID           pgtype.UUID        `json:"group_id"`
+	SessionTypeID     pgtype.UUID        `json:"session_type_id"`
+	PreferredWeekdays []int16            `json:"preferred_weekdays"`
+	BookedAt          pgtype.Timestamptz `json:"booked_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type CoachingWaitlistHold struct {
+	ID              pgtype.UUID                `json:"id"`
+	EntryID         pgtype.UUID                `json:"entry_id"`
+	ExpertID        string                     `json:"expert_id"`
+	ScheduledAt     pgtype.Timestamptz         `json:"scheduled_at"`
+	DurationMinutes int32                      `json:"duration_minutes"`
+	ExpiresAt       pgtype.Timestamptz         `json:"expires_at"`
+	Status          CoachingWaitlistHoldStatus `json:"status"`
+	BookingID       pgtype.UUID                `json:"booking_id"`
+	RespondedAt     pgtype.Timestamptz         `json:"responded_at"`
+	CreatedAt       pgtype.Timestamptz         `json:"created_at"`
+}
+
+type CoachingWaitlistWindow struct {
+	ID        pgtype.UUID `json:"id"`
+	EntryID   pgtype.UUID `json:"entry_id"`
+	StartTime pgtype.Time `json:"start_time"`
+	EndTime   pgtype.Time `json:"end_time"`
 }
 
 type FeedbackSubmission struct {
@@ -676,6 +1216,26 @@ type Notification struct {
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
 
+type ReviewReaction struct {
+	ReviewID  pgtype.UUID        `json:"review_id"`
+	UserID    string             `json:"user_id"`
+	Reaction  string             `json:"reaction"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ReviewSnippet struct {
+	ID         pgtype.UUID        `json:"id"`
+	OwnerID    string             `json:"owner_id"`
+	GroupID    pgtype.UUID        `json:"group_id"`
+	Title      string             `json:"title"`
+	Content    string             `json:"content"`
+	Tags       []string           `json:"tags"`
+	UsageCount int32              `json:"usage_count"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
 type SignupCode struct {
 	ID               pgtype.UUID        `json:"id"`
 	Code             string             `json:"code"`
@@ -745,15 +1305,36 @@ type Video struct {
 	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 	DurationSeconds pgtype.Float8      `json:"duration_seconds"`
 	SortOrder       pgtype.Int4        `json:"sort_order"`
+	MuxError        pgtype.Text        `json:"mux_error"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy       pgtype.Text        `json:"deleted_by"`
+}
+
+type VideoChapter struct {
+	ID           pgtype.UUID        `json:"id"`
+	VideoID      pgtype.UUID        `json:"video_id"`
+	Title        string             `json:"title"`
+	StartSeconds int32              `json:"start_seconds"`
+	EndSeconds   pgtype.Int4        `json:"end_seconds"`
+	CreatedBy    string             `json:"created_by"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
 
 type VideoReview struct {
-	ID               pgtype.UUID        `json:"id"`
-	VideoID          pgtype.UUID        `json:"video_id"`
-	Content          string             `json:"content"`
-	TimestampSeconds pgtype.Int4        `json:"timestamp_seconds"`
-	CreatedAt        pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
-	ParentID         pgtype.UUID        `json:"parent_id"`
-	AuthorID         pgtype.Text        `json:"author_id"`
+	ID                   pgtype.UUID        `json:"id"`
+	VideoID              pgtype.UUID        `json:"video_id"`
+	Content              string             `json:"content"`
+	TimestampSeconds     pgtype.Int4        `json:"timestamp_seconds"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	ParentID             pgtype.UUID        `json:"parent_id"`
+	AuthorID             pgtype.Text        `json:"author_id"`
+	Annotation           []byte             `json:"annotation"`
+	AnnotationVersion    int32              `json:"annotation_version"`
+	EndSeconds           pgtype.Int4        `json:"end_seconds"`
+	SnippetID            pgtype.UUID        `json:"snippet_id"`
+	ThreadState          ReviewThreadState  `json:"thread_state"`
+	ThreadStateChangedBy pgtype.Text        `json:"thread_state_changed_by"`
+	ThreadStateChangedAt pgtype.Timestamptz `json:"thread_state_changed_at"`
 }
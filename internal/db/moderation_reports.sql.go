@@ -11,6 +11,30 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const clearAssetModerationTargets = `-- name: ClearAssetModerationTargets :exec
+UPDATE moderation_reports
+SET target_review_content = '', updated_at = NOW()
+WHERE target_video_id IN (SELECT id FROM videos WHERE asset_id = $1)
+`
+
+// Reported review text goes with the purged reviews; the report itself stays
+// as moderation history.
+func (q *Queries) ClearAssetModerationTargets(ctx context.Context, assetID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, clearAssetModerationTargets, assetID)
+	return err
+}
+
+const clearVideoModerationTargets = `-- name: ClearVideoModerationTargets :exec
+UPDATE moderation_reports
+SET target_review_content = '', updated_at = NOW()
+WHERE target_video_id = $1
+`
+
+func (q *Queries) ClearVideoModerationTargets(ctx context.Context, targetVideoID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, clearVideoModerationTargets, targetVideoID)
+	return err
+}
+
 const createModerationReport = `-- name: CreateModerationReport :one
 INSERT INTO moderation_reports (
   reporter_user_id,
@@ -49,6 +49,30 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 	return i, err
 }
 
+const deleteAssetNotifications = `-- name: DeleteAssetNotifications :exec
+DELETE FROM notifications
+WHERE type IN ('video_reviewed', 'video_uploaded', 'review_thread_updated', 'review_reaction_added')
+  AND payload->>'asset_id' = $1::text
+`
+
+// Drops notifications that deep-link to a purged asset.
+func (q *Queries) DeleteAssetNotifications(ctx context.Context, assetID string) error {
+	_, err := q.db.Exec(ctx, deleteAssetNotifications, assetID)
+	return err
+}
+
+const deleteVideoNotifications = `-- name: DeleteVideoNotifications :exec
+DELETE FROM notifications
+WHERE type IN ('review_thread_updated', 'review_reaction_added')
+  AND payload->>'video_id' = $1::text
+`
+
+// Drops notifications that deep-link to a purged video of a surviving asset.
+func (q *Queries) DeleteVideoNotifications(ctx context.Context, videoID string) error {
+	_, err := q.db.Exec(ctx, deleteVideoNotifications, videoID)
+	return err
+}
+
 const getNotification = `-- name: GetNotification :one
 SELECT id, recipient_id, type, payload, read_at, created_at FROM notifications
 WHERE id = $1 LIMIT 1
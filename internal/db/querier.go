@@ -12,36 +12,90 @@ import (
 
 type Querier interface {
 	ActivateUserAccess(ctx context.Context, arg ActivateUserAccessParams) (UserAccess, error)
+	// Adding a reaction twice is a no-op; zero rows means it was already there.
+	AddReviewReaction(ctx context.Context, arg AddReviewReactionParams) (int64, error)
 	AddUserToGroup(ctx context.Context, arg AddUserToGroupParams) error
+	AdvanceAuditChainHead(ctx context.Context, arg AdvanceAuditChainHeadParams) error
 	AssignBookingRecordingAsset(ctx context.Context, arg AssignBookingRecordingAssetParams) (CoachingBooking, error)
+	// Gives every active attendee of the class the recording asset on their own
+	// booking and lets them watch it.
+	AssignClassRecordingAsset(ctx context.Context, arg AssignClassRecordingAssetParams) error
+	// Records the asset Mux created for a direct upload. A later or repeated
+	// delivery never overwrites an asset id that is already set.
+	AttachVideoMuxAsset(ctx context.Context, arg AttachVideoMuxAssetParams) (int64, error)
+	// No row when the booking is already cancelled, so a repeated cancellation
+	// cannot return its credit twice.
 	CancelBooking(ctx context.Context, arg CancelBookingParams) (CoachingBooking, error)
 	CheckUserGroup(ctx context.Context, arg CheckUserGroupParams) (bool, error)
 	CheckVideoVisibleToUser(ctx context.Context, arg CheckVideoVisibleToUserParams) (bool, error)
+	// Subscribed calendars not attempted within the refresh interval. Claiming
+	// stamps last_attempted_at so overlapping runs skip them.
+	ClaimDueExternalCalendars(ctx context.Context, arg ClaimDueExternalCalendarsParams) ([]CoachingExternalCalendar, error)
 	ClaimInboundEmailByResendID(ctx context.Context, resendEmailID string) (InboundEmail, error)
 	// === Simple recording parts ===
 	ClaimNextRecordingPart(ctx context.Context, arg ClaimNextRecordingPartParams) (CoachingBookingRecording, error)
 	ClaimPendingInboundEmails(ctx context.Context, limit int32) ([]InboundEmail, error)
 	ClaimPendingRecordingPartImports(ctx context.Context, limit int32) ([]ClaimPendingRecordingPartImportsRow, error)
+	// Claims stitches to work on. Polling Mux does not use up an attempt; a stitch
+	// that stopped halfway is claimed again once it has gone quiet for 30 minutes,
+	// even past its last attempt, so it can be given up on.
+	ClaimPendingRecordingStitches(ctx context.Context, limit int32) ([]ClaimPendingRecordingStitchesRow, error)
+	// Reported review text goes with the purged reviews; the report itself stays
+	// as moderation history.
+	ClearAssetModerationTargets(ctx context.Context, assetID pgtype.UUID) error
 	ClearRecordingPartEmptySince(ctx context.Context, bookingID pgtype.UUID) error
+	ClearVideoModerationTargets(ctx context.Context, targetVideoID pgtype.UUID) error
+	// Takes one credit from the student's grant that expires first. No row when
+	// the student has no usable credit with this expert.
+	ConsumeCredit(ctx context.Context, arg ConsumeCreditParams) (CoachingCreditGrant, error)
 	ConsumeSignupCode(ctx context.Context, arg ConsumeSignupCodeParams) (SignupCode, error)
 	CountAdminInboundEmails(ctx context.Context, arg CountAdminInboundEmailsParams) (int64, error)
+	CountBookingHomework(ctx context.Context, bookingID pgtype.UUID) (int64, error)
+	CountBookingMessages(ctx context.Context, bookingID pgtype.UUID) (int64, error)
+	// Active sessions of the expert starting in [from_at, to_at), for daily caps.
+	// The bookings of a class count as one session.
+	CountBookingsStartingInRange(ctx context.Context, arg CountBookingsStartingInRangeParams) (int64, error)
+	// Active bookings closer to the interval than the larger of the two buffers
+	// between them. @exclude_id excludes the booking being rescheduled.
+	CountBookingsWithinBuffers(ctx context.Context, arg CountBookingsWithinBuffersParams) (int64, error)
+	// $4 excludes the booking being rescheduled; pass NULL when creating a booking.
 	CountConflictingBookings(ctx context.Context, arg CountConflictingBookingsParams) (int64, error)
+	// Unexpired holds of the expert overlapping [starts_at, ends_at).
+	// @exclude_id excludes the hold being confirmed.
+	CountConflictingWaitlistHolds(ctx context.Context, arg CountConflictingWaitlistHoldsParams) (int64, error)
 	CountFreshBookingParticipants(ctx context.Context, arg CountFreshBookingParticipantsParams) (int64, error)
 	CountSignupCodesByOwner(ctx context.Context, ownerUserID string) (int64, error)
+	// Rows without a chain position written after the partition's chain began can
+	// only have been inserted around the recorder.
+	CountUnchainedAuditEvents(ctx context.Context, arg CountUnchainedAuditEventsParams) (int64, error)
 	CountUnreadNotifications(ctx context.Context, recipientID string) (int64, error)
 	CountVideosWithoutReviews(ctx context.Context, assetID pgtype.UUID) (int64, error)
+	// === Absences ===
+	CreateAbsence(ctx context.Context, arg CreateAbsenceParams) (CoachingAbsence, error)
 	CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset, error)
 	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
 	// === Availability ===
 	CreateAvailability(ctx context.Context, arg CreateAvailabilityParams) (CoachingAvailability, error)
+	// === Availability Overrides ===
+	CreateAvailabilityOverride(ctx context.Context, arg CreateAvailabilityOverrideParams) (CoachingAvailabilityOverride, error)
 	// === Blocked Slots ===
 	CreateBlockedSlot(ctx context.Context, arg CreateBlockedSlotParams) (CoachingBlockedSlot, error)
 	CreateBooking(ctx context.Context, arg CreateBookingParams) (CoachingBooking, error)
+	CreateBookingMessage(ctx context.Context, arg CreateBookingMessageParams) (CoachingBookingMessage, error)
 	// === Booking Reminders ===
 	CreateBookingReminder(ctx context.Context, arg CreateBookingReminderParams) error
+	CreateBookingReschedule(ctx context.Context, arg CreateBookingRescheduleParams) (CoachingBookingReschedule, error)
+	CreateBookingSeries(ctx context.Context, arg CreateBookingSeriesParams) (CoachingBookingSeries, error)
+	CreateClass(ctx context.Context, arg CreateClassParams) (CoachingClass, error)
+	CreateCoachingPayment(ctx context.Context, arg CreateCoachingPaymentParams) (CoachingPayment, error)
+	CreateCreditGrant(ctx context.Context, arg CreateCreditGrantParams) (CoachingCreditGrant, error)
+	CreateCreditLedgerEntry(ctx context.Context, arg CreateCreditLedgerEntryParams) (CoachingCreditLedger, error)
+	CreateExternalCalendar(ctx context.Context, arg CreateExternalCalendarParams) (CoachingExternalCalendar, error)
 	CreateFeedbackSubmission(ctx context.Context, arg CreateFeedbackSubmissionParams) (FeedbackSubmission, error)
 	CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error)
 	CreateGroupInvitation(ctx context.Context, arg CreateGroupInvitationParams) (GroupInvitation, error)
+	CreateHomework(ctx context.Context, arg CreateHomeworkParams) (CoachingHomework, error)
+	CreateHomeworkReminder(ctx context.Context, arg CreateHomeworkReminderParams) error
 	CreateInboundEmailReply(ctx context.Context, arg CreateInboundEmailReplyParams) (InboundEmailReply, error)
 	CreateLandingContactSubmission(ctx context.Context, arg CreateLandingContactSubmissionParams) (LandingContactSubmission, error)
 	CreateModerationReport(ctx context.Context, arg CreateModerationReportParams) (ModerationReport, error)
@@ -50,19 +104,57 @@ type Querier interface {
 	// === Session Types ===
 	CreateSessionType(ctx context.Context, arg CreateSessionTypeParams) (CoachingSessionType, error)
 	CreateSignupCodeWithinLimit(ctx context.Context, arg CreateSignupCodeWithinLimitParams) (SignupCode, error)
+	CreateSnippet(ctx context.Context, arg CreateSnippetParams) (ReviewSnippet, error)
 	CreateVideo(ctx context.Context, arg CreateVideoParams) (Video, error)
+	CreateVideoChapter(ctx context.Context, arg CreateVideoChapterParams) (VideoChapter, error)
 	CreateVideoFromMuxAsset(ctx context.Context, arg CreateVideoFromMuxAssetParams) (Video, error)
 	CreateVideoReview(ctx context.Context, arg CreateVideoReviewParams) (VideoReview, error)
+	CreateWaitlistEntry(ctx context.Context, arg CreateWaitlistEntryParams) (CoachingWaitlistEntry, error)
+	CreateWaitlistHold(ctx context.Context, arg CreateWaitlistHoldParams) (CoachingWaitlistHold, error)
 	DeactivateSessionType(ctx context.Context, arg DeactivateSessionTypeParams) (int64, error)
+	DeleteAbsence(ctx context.Context, arg DeleteAbsenceParams) (int64, error)
+	// Drops notifications that deep-link to a purged asset.
+	DeleteAssetNotifications(ctx context.Context, assetID string) error
 	DeleteAvailability(ctx context.Context, arg DeleteAvailabilityParams) (int64, error)
+	DeleteAvailabilityOverride(ctx context.Context, arg DeleteAvailabilityOverrideParams) (int64, error)
 	DeleteBlockedSlot(ctx context.Context, arg DeleteBlockedSlotParams) (int64, error)
+	DeleteCalendarFeed(ctx context.Context, userID string) (int64, error)
 	DeleteDevice(ctx context.Context, arg DeleteDeviceParams) error
 	DeleteDeviceByToken(ctx context.Context, expoPushToken string) error
+	DeleteExternalBusyBlocks(ctx context.Context, calendarID pgtype.UUID) error
+	DeleteExternalCalendar(ctx context.Context, arg DeleteExternalCalendarParams) (int64, error)
 	DeleteGroup(ctx context.Context, arg DeleteGroupParams) error
+	DeleteHomework(ctx context.Context, id pgtype.UUID) error
+	DeleteSnippet(ctx context.Context, arg DeleteSnippetParams) (int64, error)
+	// Homework reminders are kept: they follow the due date, not the session.
+	DeleteUnsentBookingReminders(ctx context.Context, bookingID pgtype.UUID) error
+	DeleteUnsentHomeworkReminders(ctx context.Context, homeworkID pgtype.UUID) error
+	DeleteVideoChapter(ctx context.Context, arg DeleteVideoChapterParams) (int64, error)
+	// Drops notifications that deep-link to a purged video of a surviving asset.
+	DeleteVideoNotifications(ctx context.Context, videoID string) error
 	DeleteVideoReview(ctx context.Context, arg DeleteVideoReviewParams) error
+	DeleteWaitlistEntry(ctx context.Context, arg DeleteWaitlistEntryParams) (int64, error)
+	// Queues a stitch for every recent booking whose recording has more than one
+	// imported part video once nothing is in flight: no part recording, no import
+	// running or due a retry, no part stopped in the last 15 minutes without files,
+	// and a known duration for every part video.
+	EnqueueRecordingStitches(ctx context.Context, endGraceSeconds int32) (int64, error)
+	// Creates the shard's head for the partition NOW() falls into on its first
+	// event; a concurrent first event waits on the insert instead of failing.
+	EnsureAuditChainHead(ctx context.Context, shard int32) error
 	EnsureRecordingPartImport(ctx context.Context, arg EnsureRecordingPartImportParams) (CoachingRecordingImport, error)
 	EnsureUserAccess(ctx context.Context, userID string) (UserAccess, error)
 	ExchangeRecordingRendererCapability(ctx context.Context, rendererTokenHash []byte) (ExchangeRecordingRendererCapabilityRow, error)
+	// Cancels an active booking still waiting for its payment.
+	ExpireBookingPayment(ctx context.Context, id pgtype.UUID) (CoachingBooking, error)
+	ExpireCreditGrant(ctx context.Context, id pgtype.UUID) (CoachingCreditGrant, error)
+	// Closes offered holds past expires_at so their slots can go to the next
+	// student in line.
+	ExpireWaitlistHolds(ctx context.Context) ([]CoachingWaitlistHold, error)
+	// Chronological keyset page for compliance exports. subject_id matches events
+	// the user performed as well as events about them: their profile, their
+	// memberships, bookings they are party to and reviews they authored.
+	ExportAuditEvents(ctx context.Context, arg ExportAuditEventsParams) ([]AuditEvent, error)
 	GetActiveRecordingPart(ctx context.Context, bookingID pgtype.UUID) (CoachingBookingRecording, error)
 	GetAdminInboundEmail(ctx context.Context, id pgtype.UUID) (InboundEmail, error)
 	GetAsset(ctx context.Context, id pgtype.UUID) (GetAssetRow, error)
@@ -70,14 +162,32 @@ type Querier interface {
 	GetAssetStatusByVideoID(ctx context.Context, id pgtype.UUID) (AssetStatus, error)
 	GetAssetVideos(ctx context.Context, assetID pgtype.UUID) ([]GetAssetVideosRow, error)
 	GetBooking(ctx context.Context, arg GetBookingParams) (CoachingBooking, error)
+	// Locks the call's host booking so posts to its chat are counted one at a time.
+	GetBookingForMessageUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error)
+	GetBookingForPaymentUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error)
 	GetBookingForRecordingAssetUpdate(ctx context.Context, id pgtype.UUID) (CoachingBooking, error)
+	GetBookingMessage(ctx context.Context, id pgtype.UUID) (CoachingBookingMessage, error)
+	GetCalendarFeed(ctx context.Context, userID string) (CoachingCalendarFeed, error)
+	GetClassForSlot(ctx context.Context, arg GetClassForSlotParams) (CoachingClass, error)
+	GetClassHostBooking(ctx context.Context, classID pgtype.UUID) (CoachingBooking, error)
+	// Seats taken by active bookings, whether the student holds one of them, and
+	// the last seat number ever handed out in the class.
+	GetClassSeats(ctx context.Context, arg GetClassSeatsParams) (GetClassSeatsRow, error)
+	GetCoachingPayment(ctx context.Context, bookingID pgtype.UUID) (CoachingPayment, error)
+	GetCoachingPaymentByCheckout(ctx context.Context, arg GetCoachingPaymentByCheckoutParams) (CoachingPayment, error)
+	GetExternalCalendar(ctx context.Context, arg GetExternalCalendarParams) (CoachingExternalCalendar, error)
 	GetGroup(ctx context.Context, id pgtype.UUID) (Group, error)
 	GetGroupInvitationByCode(ctx context.Context, code string) (GroupInvitation, error)
 	GetGroupInvitationByID(ctx context.Context, arg GetGroupInvitationByIDParams) (GroupInvitation, error)
 	GetGroupInvitationsByCodes(ctx context.Context, dollar_1 []string) ([]GroupInvitation, error)
+	GetHomework(ctx context.Context, arg GetHomeworkParams) (CoachingHomework, error)
 	GetModerationReport(ctx context.Context, id pgtype.UUID) (ModerationReport, error)
 	GetNotification(ctx context.Context, id pgtype.UUID) (Notification, error)
+	GetOfferedWaitlistHold(ctx context.Context, entryID pgtype.UUID) (CoachingWaitlistHold, error)
+	GetPendingBookingReschedule(ctx context.Context, bookingID pgtype.UUID) (CoachingBookingReschedule, error)
+	GetRecordingStitch(ctx context.Context, bookingID pgtype.UUID) (CoachingRecordingStitch, error)
 	GetReviewModerationTarget(ctx context.Context, id pgtype.UUID) (GetReviewModerationTargetRow, error)
+	GetSessionNotes(ctx context.Context, bookingID pgtype.UUID) (CoachingSessionNote, error)
 	GetSessionType(ctx context.Context, arg GetSessionTypeParams) (CoachingSessionType, error)
 	GetUserAccess(ctx context.Context, userID string) (UserAccess, error)
 	GetUserEmailPreferences(ctx context.Context, userID string) (GetUserEmailPreferencesRow, error)
@@ -85,42 +195,139 @@ type Querier interface {
 	GetUserPushPreferences(ctx context.Context, userID string) (GetUserPushPreferencesRow, error)
 	// === Timezone ===
 	GetUserTimezone(ctx context.Context, userID string) (string, error)
-	GetVideoReview(ctx context.Context, id pgtype.UUID) (GetVideoReviewRow, error)
+	GetVideoChapter(ctx context.Context, arg GetVideoChapterParams) (VideoChapter, error)
+	GetVideoDuration(ctx context.Context, id pgtype.UUID) (pgtype.Float8, error)
+	GetVideoReview(ctx context.Context, id pgtype.UUID) (VideoReview, error)
 	GetVisibleAsset(ctx context.Context, arg GetVisibleAssetParams) (GetVisibleAssetRow, error)
+	GetVisibleSnippet(ctx context.Context, arg GetVisibleSnippetParams) (ReviewSnippet, error)
+	GetWaitlistEntry(ctx context.Context, arg GetWaitlistEntryParams) (CoachingWaitlistEntry, error)
 	HasVideosWithoutReviews(ctx context.Context, assetID pgtype.UUID) (bool, error)
+	InsertExternalBusyBlocks(ctx context.Context, arg InsertExternalBusyBlocksParams) error
+	InsertWaitlistWindows(ctx context.Context, arg InsertWaitlistWindowsParams) error
 	IsRecordingAssetStillOpen(ctx context.Context, recordingAssetID pgtype.UUID) (bool, error)
 	LeaveGroupIfNotLastMember(ctx context.Context, arg LeaveGroupIfNotLastMemberParams) (int64, error)
+	// Absences overlapping the inclusive date range.
+	ListAbsences(ctx context.Context, arg ListAbsencesParams) ([]CoachingAbsence, error)
 	ListActiveExpertsInGroup(ctx context.Context, groupID pgtype.UUID) ([]string, error)
+	ListActiveSeriesBookingsFrom(ctx context.Context, arg ListActiveSeriesBookingsFromParams) ([]CoachingBooking, error)
 	ListAdminInboundEmails(ctx context.Context, arg ListAdminInboundEmailsParams) ([]InboundEmail, error)
 	ListAllMyBookings(ctx context.Context, expertID string) ([]ListAllMyBookingsRow, error)
+	// Every video of the asset, deleted or not, so no Mux asset is left behind.
+	ListAssetMuxIdentifiers(ctx context.Context, assetID pgtype.UUID) ([]ListAssetMuxIdentifiersRow, error)
+	ListAssetsDueForPurge(ctx context.Context, arg ListAssetsDueForPurgeParams) ([]pgtype.UUID, error)
+	// One shard's chain of a partition in chain_seq order, keyset-paginated on
+	// chain_seq.
+	ListAuditChainEvents(ctx context.Context, arg ListAuditChainEventsParams) ([]AuditEvent, error)
+	ListAuditChainHeads(ctx context.Context) ([]AuditChainHead, error)
+	// Keyset-paginated newest first. The occurred_at window is always bounded so
+	// the planner prunes monthly partitions outside it.
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
 	ListAvailabilityByExpertGroup(ctx context.Context, arg ListAvailabilityByExpertGroupParams) ([]CoachingAvailability, error)
 	ListAvailabilityByExpertGroupDay(ctx context.Context, arg ListAvailabilityByExpertGroupDayParams) ([]CoachingAvailability, error)
 	ListAvailabilityByGroup(ctx context.Context, groupID pgtype.UUID) ([]CoachingAvailability, error)
+	ListAvailabilityOverrides(ctx context.Context, arg ListAvailabilityOverridesParams) ([]CoachingAvailabilityOverride, error)
 	ListBlockedSlots(ctx context.Context, arg ListBlockedSlotsParams) ([]CoachingBlockedSlot, error)
+	ListBookingAttendance(ctx context.Context, bookingID pgtype.UUID) ([]CoachingBookingAttendance, error)
+	ListBookingHomework(ctx context.Context, bookingID pgtype.UUID) ([]CoachingHomework, error)
+	ListBookingMessages(ctx context.Context, bookingID pgtype.UUID) ([]CoachingBookingMessage, error)
+	// Every part of a booking's recording with the import of each of its files;
+	// a part without files yet has a single row with no import.
+	ListBookingRecordingImports(ctx context.Context, bookingID pgtype.UUID) ([]ListBookingRecordingImportsRow, error)
+	// Sessions that ended more than end_grace_seconds ago without an attendance
+	// outcome. Bookings still awaiting payment never took place.
+	ListBookingsAwaitingAttendance(ctx context.Context, arg ListBookingsAwaitingAttendanceParams) ([]CoachingBooking, error)
 	// === Bookings ===
 	ListBookingsByExpertInRange(ctx context.Context, arg ListBookingsByExpertInRangeParams) ([]CoachingBooking, error)
+	// Cancelled bookings stay in the window so subscribed calendars drop them.
+	// The expert sees a class once, through its host booking: it is cancelled only
+	// when every seat is, and a change to any seat updates it.
+	ListCalendarFeedBookings(ctx context.Context, arg ListCalendarFeedBookingsParams) ([]ListCalendarFeedBookingsRow, error)
+	ListClassAttendees(ctx context.Context, classID pgtype.UUID) ([]CoachingBooking, error)
+	// Grants the user holds as student or issued as expert, optionally narrowed
+	// to one student.
+	ListCreditGrants(ctx context.Context, arg ListCreditGrantsParams) ([]CoachingCreditGrant, error)
+	// Ledger movements visible to the user as student or expert, newest first.
+	ListCreditLedger(ctx context.Context, arg ListCreditLedgerParams) ([]CoachingCreditLedger, error)
 	ListDevicesForUser(ctx context.Context, userID string) ([]UserDevice, error)
+	// Grants past their expiry that still hold credits, locked for expiry.
+	ListExpiredCreditGrants(ctx context.Context, limit int32) ([]CoachingCreditGrant, error)
+	ListExternalBusyBlocks(ctx context.Context, arg ListExternalBusyBlocksParams) ([]ListExternalBusyBlocksRow, error)
+	ListExternalCalendarsByExpert(ctx context.Context, expertID string) ([]CoachingExternalCalendar, error)
 	ListGroupBookings(ctx context.Context, groupID pgtype.UUID) ([]ListGroupBookingsRow, error)
 	ListGroupInvitations(ctx context.Context, groupID pgtype.UUID) ([]GroupInvitation, error)
 	ListGroupMembers(ctx context.Context, groupID pgtype.UUID) ([]string, error)
+	// Homework the user assigned or was assigned in the group, open items first
+	// by due date, with the session it came from and that session's recording.
+	ListHomework(ctx context.Context, arg ListHomeworkParams) ([]ListHomeworkRow, error)
 	ListInboundEmailReplies(ctx context.Context, inboundEmailID pgtype.UUID) ([]InboundEmailReply, error)
 	ListModerationReports(ctx context.Context, arg ListModerationReportsParams) ([]ModerationReport, error)
 	ListMyBookings(ctx context.Context, arg ListMyBookingsParams) ([]ListMyBookingsRow, error)
+	// The student's waiting entries in the group with the slot currently held
+	// for them, if any.
+	ListMyWaitlistEntries(ctx context.Context, arg ListMyWaitlistEntriesParams) ([]ListMyWaitlistEntriesRow, error)
 	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	// Classes of the session type starting in the range that hold at least one
+	// active booking and still have a seat left, with the seats taken.
+	ListOpenClasses(ctx context.Context, arg ListOpenClassesParams) ([]ListOpenClassesRow, error)
+	// Payments of cancelled bookings that are still paid, oldest first.
+	ListPaymentsAwaitingRefund(ctx context.Context, limit int32) ([]CoachingPayment, error)
+	// Session reminders, and homework due-date reminders where homework_id is set.
 	ListPendingReminders(ctx context.Context) ([]ListPendingRemindersRow, error)
+	// Chat of the call the asset recorded. Messages live on the call's host
+	// booking, which for a class is its first seat.
+	ListRecordingAssetMessages(ctx context.Context, recordingAssetID pgtype.UUID) ([]CoachingBookingMessage, error)
 	ListRecordingPartsReadyToStop(ctx context.Context, arg ListRecordingPartsReadyToStopParams) ([]CoachingBookingRecording, error)
+	// The part videos a stitch joins, in recording order.
+	ListRecordingStitchInputs(ctx context.Context, bookingID pgtype.UUID) ([]ListRecordingStitchInputsRow, error)
 	ListSessionTypesByExpertGroup(ctx context.Context, arg ListSessionTypesByExpertGroupParams) ([]CoachingSessionType, error)
 	ListSessionTypesByGroup(ctx context.Context, groupID pgtype.UUID) ([]CoachingSessionType, error)
 	ListSignupCodesByOwner(ctx context.Context, ownerUserID string) ([]SignupCode, error)
 	ListStoppedRecordingPartsForDiscovery(ctx context.Context, limit int32) ([]CoachingBookingRecording, error)
+	// Active bookings whose payment hold ran out, locked for expiry.
+	ListUnpaidExpiredBookings(ctx context.Context, limit int32) ([]CoachingBooking, error)
 	ListUserGroups(ctx context.Context, userID string) ([]ListUserGroupsRow, error)
+	ListVideoChapters(ctx context.Context, videoID pgtype.UUID) ([]VideoChapter, error)
+	// Reaction counts per review of a video, flagging the ones user_id added.
+	ListVideoReviewReactions(ctx context.Context, arg ListVideoReviewReactionsParams) ([]ListVideoReviewReactionsRow, error)
 	ListVideoReviews(ctx context.Context, videoID pgtype.UUID) ([]ListVideoReviewsRow, error)
+	// Videos deleted on their own. Videos of a deleted asset go with the asset.
+	ListVideosDueForPurge(ctx context.Context, arg ListVideosDueForPurgeParams) ([]ListVideosDueForPurgeRow, error)
 	// Ready videos without a captured duration. Either identifier may be empty:
 	// direct uploads carry mux_upload_id, coaching imports carry mux_asset_id.
 	ListVideosMissingDuration(ctx context.Context, limit int32) ([]ListVideosMissingDurationRow, error)
+	// Newest upload first. Without a status filter, assets still uploading are
+	// hidden. A NULL page_limit returns every match.
 	ListVisibleAssets(ctx context.Context, arg ListVisibleAssetsParams) ([]ListVisibleAssetsRow, error)
+	// Same filters as ListVisibleAssets, most recent review activity first.
+	// Assets nobody has reviewed yet rank by their upload time.
+	ListVisibleAssetsByReviewActivity(ctx context.Context, arg ListVisibleAssetsByReviewActivityParams) ([]ListVisibleAssetsByReviewActivityRow, error)
+	// Same filters as ListVisibleAssets, most recently changed first.
+	ListVisibleAssetsByUpdated(ctx context.Context, arg ListVisibleAssetsByUpdatedParams) ([]ListVisibleAssetsByUpdatedRow, error)
+	// Snippets the user owns plus those shared with any of their groups, most
+	// used first. tag and search narrow the list when set; search matches
+	// literally, ignoring case, so % and _ are not wildcards.
+	ListVisibleSnippets(ctx context.Context, arg ListVisibleSnippetsParams) ([]ReviewSnippet, error)
+	// Entries waiting for the expert without an unexpired hold, oldest first,
+	// for session types that can still be booked.
+	ListWaitingEntriesByExpert(ctx context.Context, arg ListWaitingEntriesByExpertParams) ([]CoachingWaitlistEntry, error)
+	// Unexpired holds of the expert overlapping [from_at, to_at).
+	ListWaitlistHoldsByExpertInRange(ctx context.Context, arg ListWaitlistHoldsByExpertInRangeParams) ([]CoachingWaitlistHold, error)
+	ListWaitlistWindows(ctx context.Context, entryIds []pgtype.UUID) ([]CoachingWaitlistWindow, error)
+	// Locks one shard's chain head of the partition NOW() falls into. NOW() is the
+	// transaction start, the same value occurred_at defaults to, so the event and
+	// the head always agree on the partition.
+	LockAuditChainHead(ctx context.Context, shard int32) (LockAuditChainHeadRow, error)
 	MarkAllNotificationsRead(ctx context.Context, recipientID string) error
+	// Pending and expired bookings become paid; a cancelled one is then owed a
+	// refund. No row when the payment was already applied.
+	MarkBookingPaid(ctx context.Context, id pgtype.UUID) (CoachingBooking, error)
+	MarkBookingRefunded(ctx context.Context, id pgtype.UUID) (CoachingBooking, error)
+	MarkCoachingPaymentPaid(ctx context.Context, arg MarkCoachingPaymentPaidParams) error
+	MarkCoachingPaymentRefunded(ctx context.Context, arg MarkCoachingPaymentRefundedParams) error
 	MarkEmptyRecordingPartsWithoutFreshHumans(ctx context.Context, freshSeconds int32) (int64, error)
+	// Keeps the busy blocks from the last successful fetch.
+	MarkExternalCalendarFailed(ctx context.Context, arg MarkExternalCalendarFailedParams) error
+	MarkExternalCalendarSynced(ctx context.Context, id pgtype.UUID) error
 	MarkFeedbackDiscordFailed(ctx context.Context, arg MarkFeedbackDiscordFailedParams) error
 	MarkFeedbackDiscordPosted(ctx context.Context, arg MarkFeedbackDiscordPostedParams) error
 	MarkFeedbackDiscordSkipped(ctx context.Context, arg MarkFeedbackDiscordSkippedParams) error
@@ -147,15 +354,55 @@ type Querier interface {
 	MarkRecordingPartStopped(ctx context.Context, id pgtype.UUID) (CoachingBookingRecording, error)
 	MarkRecordingPartStopping(ctx context.Context, id pgtype.UUID) (CoachingBookingRecording, error)
 	MarkRecordingRendererReady(ctx context.Context, rendererTokenHash []byte) (pgtype.UUID, error)
+	// Keeps the stitched file, if there is one, so a retry only imports it again.
+	// mux_asset_id is set when the stitch's Mux asset could not be deleted, so a
+	// retry checks on it again instead of losing track of it.
+	MarkRecordingStitchFailed(ctx context.Context, arg MarkRecordingStitchFailedParams) error
+	MarkRecordingStitchMuxCreated(ctx context.Context, arg MarkRecordingStitchMuxCreatedParams) (CoachingRecordingStitch, error)
+	MarkRecordingStitchReady(ctx context.Context, arg MarkRecordingStitchReadyParams) (CoachingRecordingStitch, error)
 	MarkReminderSent(ctx context.Context, id pgtype.UUID) error
+	// A ready video stays ready: a stale errored delivery must not hide a
+	// playable video, and deleted videos are left alone.
+	MarkVideoFailedFromMux(ctx context.Context, arg MarkVideoFailedFromMuxParams) ([]pgtype.UUID, error)
+	// Matches by asset id, or by upload id when video.asset.ready overtakes
+	// video.upload.asset_created. An existing duration or playback id is kept.
+	// Deleted videos are left alone so a late delivery cannot revive them.
+	MarkVideoReadyFromMux(ctx context.Context, arg MarkVideoReadyFromMuxParams) ([]pgtype.UUID, error)
+	MarkWaitlistEntryBooked(ctx context.Context, id pgtype.UUID) error
+	// Moves the chapters of one video to another, like MoveVideoReviews.
+	MoveVideoChapters(ctx context.Context, arg MoveVideoChaptersParams) (int64, error)
+	// Moves the reviews of one video to another that plays the same footage
+	// offset_seconds later, as when recording parts are stitched into one video.
+	// Annotation ranges move along, and their version changes so an editor still
+	// holding the old times cannot write them back.
+	MoveVideoReviews(ctx context.Context, arg MoveVideoReviewsParams) (int64, error)
+	// Moves an asset out of waiting_upload once no video is still waiting on Mux
+	// and at least one is playable, so uploads finish even if the client never
+	// calls complete.
+	PromoteUploadedAsset(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Reviews cascade with the videos.
+	PurgeAsset(ctx context.Context, id pgtype.UUID) (int64, error)
+	PurgeVideo(ctx context.Context, id pgtype.UUID) (int64, error)
+	// Adds the time since the participant's previous heartbeat to their attendance,
+	// capped at max_gap_seconds so a dropped connection is not counted as time in
+	// the call.
+	RecordBookingAttendance(ctx context.Context, arg RecordBookingAttendanceParams) error
+	RecordSnippetUse(ctx context.Context, id pgtype.UUID) error
 	RefreshBookingPresence(ctx context.Context, arg RefreshBookingPresenceParams) (CoachingBookingPresence, error)
 	ReleaseInboundEmailClaim(ctx context.Context, id pgtype.UUID) error
 	ReleaseSignupCode(ctx context.Context, id pgtype.UUID) error
 	RemoveBookingPresence(ctx context.Context, arg RemoveBookingPresenceParams) (int64, error)
+	RemoveReviewReaction(ctx context.Context, arg RemoveReviewReactionParams) (int64, error)
 	RemoveUserFromGroup(ctx context.Context, arg RemoveUserFromGroupParams) error
-	// Past, non-cancelled sessions the expert ran. Title is the session type name.
+	// Puts the entry a confirmed hold booked back on the waitlist, in its old
+	// place, when that booking's checkout could not be started. An entry the
+	// student has since replaced stays booked.
+	ReopenWaitlistEntryForBooking(ctx context.Context, bookingID pgtype.UUID) error
+	// Past, non-cancelled sessions the expert ran. Title is the session type name;
+	// revenue_cents is the price of paid sessions.
 	ReportSessionEventsForExpert(ctx context.Context, expertID string) ([]ReportSessionEventsForExpertRow, error)
-	// Past, non-cancelled sessions the student attended. Title is the session type name.
+	// Past, non-cancelled sessions the student attended. Title is the session type
+	// name; revenue_cents is the price of paid sessions.
 	ReportSessionEventsForStudent(ctx context.Context, studentID string) ([]ReportSessionEventsForStudentRow, error)
 	// Reports list a user's own video uploads and live coaching sessions as
 	// individual events. The frontend ports buildReport(): it nests events by
@@ -169,31 +416,62 @@ type Querier interface {
 	ReportUploadEventsForExpert(ctx context.Context, expertID string) ([]ReportUploadEventsForExpertRow, error)
 	// One row per asset the student uploaded. The reviewing expert is the group owner.
 	ReportUploadEventsForStudent(ctx context.Context, studentID string) ([]ReportUploadEventsForStudentRow, error)
+	RescheduleBooking(ctx context.Context, arg RescheduleBookingParams) (CoachingBooking, error)
+	RespondToBookingReschedule(ctx context.Context, arg RespondToBookingRescheduleParams) (CoachingBookingReschedule, error)
+	RespondToWaitlistHold(ctx context.Context, arg RespondToWaitlistHoldParams) (CoachingWaitlistHold, error)
+	// No row when the grant was never short of this credit or the booking's
+	// credit was already restored.
+	RestoreCredit(ctx context.Context, arg RestoreCreditParams) (CoachingCreditGrant, error)
 	RevokeGroupInvitation(ctx context.Context, arg RevokeGroupInvitationParams) (GroupInvitation, error)
+	// Freezes the head a following partition chains to. Late events may still
+	// extend the sealed partition; the seal pins the link, not the tail.
+	SealAuditChainHead(ctx context.Context, arg SealAuditChainHeadParams) ([]byte, error)
 	SeedUserPreferences(ctx context.Context, arg SeedUserPreferencesParams) (UserPreference, error)
 	SeedUserPreferencesWithAvatar(ctx context.Context, arg SeedUserPreferencesWithAvatarParams) (UserPreference, error)
+	// Leaves updated_at alone: the outcome is not a change to the session itself,
+	// and updated_at drives calendar feed sequence numbers.
+	SetBookingAttendance(ctx context.Context, arg SetBookingAttendanceParams) (CoachingBooking, error)
+	// Marks the homework done, or open again when completed is false.
+	SetHomeworkCompleted(ctx context.Context, arg SetHomeworkCompletedParams) (CoachingHomework, error)
 	SetRecordingPartProviderStarted(ctx context.Context, arg SetRecordingPartProviderStartedParams) (CoachingBookingRecording, error)
+	// Only top-level reviews carry a thread state; a reply updates nothing.
+	SetReviewThreadState(ctx context.Context, arg SetReviewThreadStateParams) (VideoReview, error)
 	SetVideoDurationByID(ctx context.Context, arg SetVideoDurationByIDParams) error
 	SetVideoDurationByUploadID(ctx context.Context, arg SetVideoDurationByUploadIDParams) error
+	// Hides the asset and all its videos at once. The purge job removes it for
+	// good once the grace window has passed.
+	SoftDeleteAsset(ctx context.Context, arg SoftDeleteAssetParams) (Asset, error)
+	SoftDeleteVideo(ctx context.Context, arg SoftDeleteVideoParams) (Video, error)
+	TouchCalendarFeedByTokenHash(ctx context.Context, tokenHash []byte) (CoachingCalendarFeed, error)
 	UpdateAssetStatus(ctx context.Context, arg UpdateAssetStatusParams) error
 	UpdateAvailability(ctx context.Context, arg UpdateAvailabilityParams) (CoachingAvailability, error)
 	UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error)
 	UpdateGroupInvitationStatus(ctx context.Context, arg UpdateGroupInvitationStatusParams) error
+	UpdateHomework(ctx context.Context, arg UpdateHomeworkParams) (CoachingHomework, error)
 	UpdateInboundEmailContent(ctx context.Context, arg UpdateInboundEmailContentParams) error
 	UpdateInboundEmailHandlingStatus(ctx context.Context, arg UpdateInboundEmailHandlingStatusParams) (InboundEmail, error)
 	UpdateModerationReportStatus(ctx context.Context, arg UpdateModerationReportStatusParams) (ModerationReport, error)
 	UpdateSessionType(ctx context.Context, arg UpdateSessionTypeParams) (CoachingSessionType, error)
+	UpdateSnippet(ctx context.Context, arg UpdateSnippetParams) (ReviewSnippet, error)
 	UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) (UserPreference, error)
 	UpdateUserEmailPreferences(ctx context.Context, arg UpdateUserEmailPreferencesParams) (UserPreference, error)
 	UpdateUserProfilePreferences(ctx context.Context, arg UpdateUserProfilePreferencesParams) (UserPreference, error)
 	UpdateUserPushPreferences(ctx context.Context, arg UpdateUserPushPreferencesParams) (UserPreference, error)
+	UpdateVideoChapter(ctx context.Context, arg UpdateVideoChapterParams) (VideoChapter, error)
 	UpdateVideoMuxAssetID(ctx context.Context, arg UpdateVideoMuxAssetIDParams) error
+	// set_annotation replaces the annotation (NULL removes it) and bumps its
+	// version. A non-NULL expected_annotation_version that no longer matches
+	// updates nothing, so a concurrent edit surfaces as no rows.
 	UpdateVideoReview(ctx context.Context, arg UpdateVideoReviewParams) (VideoReview, error)
 	UpdateVideoStatus(ctx context.Context, arg UpdateVideoStatusParams) error
 	UpdateVideoStatusByUploadID(ctx context.Context, arg UpdateVideoStatusByUploadIDParams) error
 	UpsertBookingPresence(ctx context.Context, arg UpsertBookingPresenceParams) (CoachingBookingPresence, error)
+	// Rotating replaces the token hash, so the previous feed URL stops working.
+	UpsertCalendarFeed(ctx context.Context, arg UpsertCalendarFeedParams) (CoachingCalendarFeed, error)
 	UpsertDevice(ctx context.Context, arg UpsertDeviceParams) (UserDevice, error)
 	UpsertInboundEmail(ctx context.Context, arg UpsertInboundEmailParams) (InboundEmail, error)
+	UpsertSessionNotes(ctx context.Context, arg UpsertSessionNotesParams) (CoachingSessionNote, error)
+	WithdrawPendingBookingReschedule(ctx context.Context, arg WithdrawPendingBookingRescheduleParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -20,28 +20,36 @@ SELECT
     g.name AS group_name,
     cb.student_id,
     cb.expert_id,
-    cb.duration_minutes
+    cb.duration_minutes,
+    CASE WHEN cb.payment_status = 'paid' THEN cb.price_cents ELSE 0 END AS revenue_cents,
+    COALESCE(cb.currency, '') AS currency,
+    cb.attendance_outcome
 FROM coaching_bookings cb
 JOIN groups g ON g.id = cb.group_id
 LEFT JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 WHERE cb.expert_id = $1
   AND cb.is_cancelled = false
+  AND cb.payment_status IS DISTINCT FROM 'pending'
   AND cb.scheduled_at < NOW()
 ORDER BY cb.scheduled_at DESC
 `
 
 type ReportSessionEventsForExpertRow struct {
-	BookingID       pgtype.UUID        `json:"booking_id"`
-	Title           string             `json:"title"`
-	At              pgtype.Timestamptz `json:"at"`
-	GroupID         pgtype.UUID        `json:"group_id"`
-	GroupName       string             `json:"group_name"`
-	StudentID       string             `json:"student_id"`
-	ExpertID        string             `json:"expert_id"`
-	DurationMinutes int32              `json:"duration_minutes"`
+	BookingID         pgtype.UUID                   `json:"booking_id"`
+	Title             string                        `json:"title"`
+	At                pgtype.Timestamptz            `json:"at"`
+	GroupID           pgtype.UUID                   `json:"group_id"`
+	GroupName         string                        `json:"group_name"`
+	StudentID         string                        `json:"student_id"`
+	ExpertID          string                        `json:"expert_id"`
+	DurationMinutes   int32                         `json:"duration_minutes"`
+	RevenueCents      int32                         `json:"revenue_cents"`
+	Currency          string                        `json:"currency"`
+	AttendanceOutcome NullCoachingAttendanceOutcome `json:"attendance_outcome"`
 }
 
-// Past, non-cancelled sessions the expert ran. Title is the session type name.
+// Past, non-cancelled sessions the expert ran. Title is the session type name;
+// revenue_cents is the price of paid sessions.
 func (q *Queries) ReportSessionEventsForExpert(ctx context.Context, expertID string) ([]ReportSessionEventsForExpertRow, error) {
 	rows, err := q.db.Query(ctx, reportSessionEventsForExpert, expertID)
 	if err != nil {
@@ -60,6 +68,9 @@ func (q *Queries) ReportSessionEventsForExpert(ctx context.Context, expertID str
 			&i.StudentID,
 			&i.ExpertID,
 			&i.DurationMinutes,
+			&i.RevenueCents,
+			&i.Currency,
+			&i.AttendanceOutcome,
 		); err != nil {
 			return nil, err
 		}
@@ -80,28 +91,36 @@ SELECT
     g.name AS group_name,
     cb.student_id,
     cb.expert_id,
-    cb.duration_minutes
+    cb.duration_minutes,
+    CASE WHEN cb.payment_status = 'paid' THEN cb.price_cents ELSE 0 END AS revenue_cents,
+    COALESCE(cb.currency, '') AS currency,
+    cb.attendance_outcome
 FROM coaching_bookings cb
 JOIN groups g ON g.id = cb.group_id
 LEFT JOIN coaching_session_types cst ON cst.id = cb.session_type_id
 WHERE cb.student_id = $1
   AND cb.is_cancelled = false
+  AND cb.payment_status IS DISTINCT FROM 'pending'
   AND cb.scheduled_at < NOW()
 ORDER BY cb.scheduled_at DESC
 `
 
 type ReportSessionEventsForStudentRow struct {
-	BookingID       pgtype.UUID        `json:"booking_id"`
-	Title           string             `json:"title"`
-	At              pgtype.Timestamptz `json:"at"`
-	GroupID         pgtype.UUID        `json:"group_id"`
-	GroupName       string             `json:"group_name"`
-	StudentID       string             `json:"student_id"`
-	ExpertID        string             `json:"expert_id"`
-	DurationMinutes int32              `json:"duration_minutes"`
+	BookingID         pgtype.UUID                   `json:"booking_id"`
+	Title             string                        `json:"title"`
+	At                pgtype.Timestamptz            `json:"at"`
+	GroupID           pgtype.UUID                   `json:"group_id"`
+	GroupName         string                        `json:"group_name"`
+	StudentID         string                        `json:"student_id"`
+	ExpertID          string                        `json:"expert_id"`
+	DurationMinutes   int32                         `json:"duration_minutes"`
+	RevenueCents      int32                         `json:"revenue_cents"`
+	Currency          string                        `json:"currency"`
+	AttendanceOutcome NullCoachingAttendanceOutcome `json:"attendance_outcome"`
 }
 
-// Past, non-cancelled sessions the student attended. Title is the session type name.
+// Past, non-cancelled sessions the student attended. Title is the session type
+// name; revenue_cents is the price of paid sessions.
 func (q *Queries) ReportSessionEventsForStudent(ctx context.Context, studentID string) ([]ReportSessionEventsForStudentRow, error) {
 	rows, err := q.db.Query(ctx, reportSessionEventsForStudent, studentID)
 	if err != nil {
@@ -120,6 +139,9 @@ func (q *Queries) ReportSessionEventsForStudent(ctx context.Context, studentID s
 			&i.StudentID,
 			&i.ExpertID,
 			&i.DurationMinutes,
+			&i.RevenueCents,
+			&i.Currency,
+			&i.AttendanceOutcome,
 		); err != nil {
 			return nil, err
 		}
@@ -144,9 +166,10 @@ SELECT
     COALESCE(SUM(v.duration_seconds), 0)::double precision AS duration_seconds
 FROM assets a
 JOIN groups g ON g.id = a.group_id
-LEFT JOIN videos v ON v.asset_id = a.id
+LEFT JOIN videos v ON v.asset_id = a.id AND v.deleted_at IS NULL
 WHERE g.owner_id = $1
   AND a.status != 'waiting_upload'
+  AND a.deleted_at IS NULL
 GROUP BY a.id, a.name, a.created_at, a.group_id, g.name, a.owner_id, g.owner_id
 ORDER BY a.created_at DESC
 `
@@ -212,9 +235,10 @@ SELECT
     COALESCE(SUM(v.duration_seconds), 0)::double precision AS duration_seconds
 FROM assets a
 JOIN groups g ON g.id = a.group_id
-LEFT JOIN videos v ON v.asset_id = a.id
+LEFT JOIN videos v ON v.asset_id = a.id AND v.deleted_at IS NULL
 WHERE a.owner_id = $1
   AND a.status != 'waiting_upload'
+  AND a.deleted_at IS NULL
 GROUP BY a.id, a.name, a.created_at, a.group_id, g.name, a.owner_id, g.owner_id
 ORDER BY a.created_at DESC
 `
@@ -17,8 +17,13 @@ SELECT EXISTS (
     FROM videos v
     INNER JOIN assets a ON a.id = v.asset_id
     WHERE v.id = $1
+      AND v.deleted_at IS NULL
+      AND a.deleted_at IS NULL
       AND (
-        ($2::boolean AND a.owner_id = $3)
+        ($2::boolean AND (
+          a.owner_id = $3
+          OR EXISTS (SELECT 1 FROM asset_viewers av WHERE av.asset_id = a.id AND av.user_id = $3)
+        ))
         OR (
           NOT $2::boolean
           AND EXISTS (
@@ -49,7 +54,7 @@ const countVideosWithoutReviews = `-- name: CountVideosWithoutReviews :one
 SELECT COUNT(*) as count
 FROM videos v
 LEFT JOIN video_reviews r ON v.id = r.video_id
-WHERE v.asset_id = $1
+WHERE v.asset_id = $1 AND v.deleted_at IS NULL
 GROUP BY v.asset_id
 HAVING COUNT(r.id) = 0
    OR COUNT(DISTINCT v.id) > COUNT(DISTINCT r.video_id)
@@ -68,10 +73,13 @@ INSERT INTO video_reviews (
     content,
     timestamp_seconds,
     parent_id,
-    author_id
+    author_id,
+    annotation,
+    end_seconds,
+    snippet_id
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, video_id, content, timestamp_seconds, created_at, updated_at, parent_id, author_id
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, video_id, content, timestamp_seconds, created_at, updated_at, parent_id, author_id, annotation, annotation_version, end_seconds, snippet_id, thread_state, thread_state_changed_by, thread_state_changed_at
 `
 
 type CreateVideoReviewParams struct {
@@ -80,6 +88,9 @@ type CreateVideoReviewParams struct {
 	TimestampSeconds pgtype.Int4 `json:"timestamp_seconds"`
 	ParentID         pgtype.UUID `json:"parent_id"`
 	AuthorID         pgtype.Text `json:"author_id"`
+	Annotation       []byte      `json:"annotation"`
+	EndSeconds       pgtype.Int4 `json:"end_seconds"`
+	SnippetID        pgtype.UUID `json:"snippet_id"`
 }
 
 func (q *Queries) CreateVideoReview(ctx context.Context, arg CreateVideoReviewParams) (VideoReview, error) {
@@ -89,6 +100,9 @@ func (q *Queries) CreateVideoReview(ctx context.Context, arg CreateVideoReviewPa
 		arg.TimestampSeconds,
 		arg.ParentID,
 		arg.AuthorID,
+		arg.Annotation,
+		arg.EndSeconds,
+		arg.SnippetID,
 	)
 	var i VideoReview
 	err := row.Scan(
@@ -100,6 +114,13 @@ func (q *Queries) CreateVideoReview(ctx context.Context, arg CreateVideoReviewPa
 		&i.UpdatedAt,
 		&i.ParentID,
 		&i.AuthorID,
+		&i.Annotation,
+		&i.AnnotationVersion,
+		&i.EndSeconds,
+		&i.SnippetID,
+		&i.ThreadState,
+		&i.ThreadStateChangedBy,
+		&i.ThreadStateChangedAt,
 	)
 	return i, err
 }
@@ -162,27 +183,44 @@ func (q *Queries) GetAssetStatusByVideoID(ctx context.Context, id pgtype.UUID) (
 	return status, err
 }
 
+const getVideoDuration = `-- name: GetVideoDuration :one
+SELECT duration_seconds
+FROM videos
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetVideoDuration(ctx context.Context, id pgtype.UUID) (pgtype.Float8, error) {
+	row := q.db.QueryRow(ctx, getVideoDuration, id)
+	var duration_seconds pgtype.Float8
+	err := row.Scan(&duration_seconds)
+	return duration_seconds, err
+}
+
 const getVideoReview = `-- name: GetVideoReview :one
-SELECT id, video_id, parent_id, author_id
+SELECT id, video_id, content, timestamp_seconds, created_at, updated_at, parent_id, author_id, annotation, annotation_version, end_seconds, snippet_id, thread_state, thread_state_changed_by, thread_state_changed_at
 FROM video_reviews
 WHERE id = $1
 `
 
-type GetVideoReviewRow struct {
-	ID       pgtype.UUID `json:"id"`
-	VideoID  pgtype.UUID `json:"video_id"`
-	ParentID pgtype.UUID `json:"parent_id"`
-	AuthorID pgtype.Text `json:"author_id"`
-}
-
-func (q *Queries) GetVideoReview(ctx context.Context, id pgtype.UUID) (GetVideoReviewRow, error) {
+func (q *Queries) GetVideoReview(ctx context.Context, id pgtype.UUID) (VideoReview, error) {
 	row := q.db.QueryRow(ctx, getVideoReview, id)
-	var i GetVideoReviewRow
+	var i VideoReview
 	err := row.Scan(
 		&i.ID,
 		&i.VideoID,
+		&i.Content,
+		&i.TimestampSeconds,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 		&i.ParentID,
 		&i.AuthorID,
+		&i.Annotation,
+		&i.AnnotationVersion,
+		&i.EndSeconds,
+		&i.SnippetID,
+		&i.ThreadState,
+		&i.ThreadStateChangedBy,
+		&i.ThreadStateChangedAt,
 	)
 	return i, err
 }
@@ -192,7 +230,7 @@ SELECT EXISTS (
     SELECT 1
     FROM videos v
     LEFT JOIN video_reviews r ON v.id = r.video_id
-    WHERE v.asset_id = $1
+    WHERE v.asset_id = $1 AND v.deleted_at IS NULL
     GROUP BY v.id
     HAVING COUNT(r.id) = 0
 ) as has_unreviewed
@@ -211,8 +249,12 @@ SELECT
     r.video_id,
     r.content,
     r.timestamp_seconds,
+    r.end_seconds,
     r.parent_id,
     r.author_id,
+    r.annotation,
+    r.annotation_version,
+    r.thread_state,
     r.created_at,
     r.updated_at,
     up.first_name  AS author_first_name,
@@ -225,17 +267,21 @@ ORDER BY COALESCE(r.parent_id, r.id), r.parent_id NULLS FIRST, r.created_at
 `
 
 type ListVideoReviewsRow struct {
-	ID               pgtype.UUID        `json:"id"`
-	VideoID          pgtype.UUID        `json:"video_id"`
-	Content          string             `json:"content"`
-	TimestampSeconds pgtype.Int4        `json:"timestamp_seconds"`
-	ParentID         pgtype.UUID        `json:"parent_id"`
-	AuthorID         pgtype.Text        `json:"author_id"`
-	CreatedAt        pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
-	AuthorFirstName  pgtype.Text        `json:"author_first_name"`
-	AuthorLastName   pgtype.Text        `json:"author_last_name"`
-	AuthorAvatar     pgtype.Text        `json:"author_avatar"`
+	ID                pgtype.UUID        `json:"id"`
+	VideoID           pgtype.UUID        `json:"video_id"`
+	Content           string             `json:"content"`
+	TimestampSeconds  pgtype.Int4        `json:"timestamp_seconds"`
+	EndSeconds        pgtype.Int4        `json:"end_seconds"`
+	ParentID          pgtype.UUID        `json:"parent_id"`
+	AuthorID          pgtype.Text        `json:"author_id"`
+	Annotation        []byte             `json:"annotation"`
+	AnnotationVersion int32              `json:"annotation_version"`
+	ThreadState       ReviewThreadState  `json:"thread_state"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	AuthorFirstName   pgtype.Text        `json:"author_first_name"`
+	AuthorLastName    pgtype.Text        `json:"author_last_name"`
+	AuthorAvatar      pgtype.Text        `json:"author_avatar"`
 }
 
 func (q *Queries) ListVideoReviews(ctx context.Context, videoID pgtype.UUID) ([]ListVideoReviewsRow, error) {
@@ -252,8 +298,12 @@ func (q *Queries) ListVideoReviews(ctx context.Context, videoID pgtype.UUID) ([]
 			&i.VideoID,
 			&i.Content,
 			&i.TimestampSeconds,
+			&i.EndSeconds,
 			&i.ParentID,
 			&i.AuthorID,
+			&i.Annotation,
+			&i.AnnotationVersion,
+			&i.ThreadState,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.AuthorFirstName,
@@ -270,21 +320,116 @@ func (q *Queries) ListVideoReviews(ctx context.Context, videoID pgtype.UUID) ([]
 	return items, nil
 }
 
+const moveVideoReviews = `-- name: MoveVideoReviews :execrows
+UPDATE video_reviews
+SET video_id = $1,
+    timestamp_seconds = timestamp_seconds + $2::int,
+    end_seconds = end_seconds + $2::int,
+    annotation = CASE WHEN annotation IS NULL THEN NULL ELSE jsonb_set(
+        jsonb_set(annotation, '{start_seconds}',
+            to_jsonb((annotation->>'start_seconds')::numeric + $2::int)),
+        '{end_seconds}',
+        to_jsonb((annotation->>'end_seconds')::numeric + $2::int)) END,
+    annotation_version = annotation_version + CASE WHEN annotation IS NULL THEN 0 ELSE 1 END,
+    updated_at = NOW()
+WHERE video_id = $3
+`
+
+type MoveVideoReviewsParams struct {
+	ToVideoID     pgtype.UUID `json:"to_video_id"`
+	OffsetSeconds int32       `json:"offset_seconds"`
+	FromVideoID   pgtype.UUID `json:"from_video_id"`
+}
+
+// Moves the reviews of one video to another that plays the same footage
+// offset_seconds later, as when recording parts are stitched into one video.
+// Annotation ranges move along, and their version changes so an editor still
+// holding the old times cannot write them back.
+func (q *Queries) MoveVideoReviews(ctx context.Context, arg MoveVideoReviewsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveVideoReviews, arg.ToVideoID, arg.OffsetSeconds, arg.FromVideoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setReviewThreadState = `-- name: SetReviewThreadState :one
+UPDATE video_reviews
+SET thread_state = $1,
+    thread_state_changed_by = $2,
+    thread_state_changed_at = NOW()
+WHERE id = $3 AND video_id = $4 AND parent_id IS NULL
+RETURNING id, video_id, content, timestamp_seconds, created_at, updated_at, parent_id, author_id, annotation, annotation_version, end_seconds, snippet_id, thread_state, thread_state_changed_by, thread_state_changed_at
+`
+
+type SetReviewThreadStateParams struct {
+	ThreadState ReviewThreadState `json:"thread_state"`
+	ChangedBy   pgtype.Text       `json:"changed_by"`
+	ID          pgtype.UUID       `json:"id"`
+	VideoID     pgtype.UUID       `json:"video_id"`
+}
+
+// Only top-level reviews carry a thread state; a reply updates nothing.
+func (q *Queries) SetReviewThreadState(ctx context.Context, arg SetReviewThreadStateParams) (VideoReview, error) {
+	row := q.db.QueryRow(ctx, setReviewThreadState,
+		arg.ThreadState,
+		arg.ChangedBy,
+		arg.ID,
+		arg.VideoID,
+	)
+	var i VideoReview
+	err := row.Scan(
+		&i.ID,
+		&i.VideoID,
+		&i.Content,
+		&i.TimestampSeconds,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.AuthorID,
+		&i.Annotation,
+		&i.AnnotationVersion,
+		&i.EndSeconds,
+		&i.SnippetID,
+		&i.ThreadState,
+		&i.ThreadStateChangedBy,
+		&i.ThreadStateChangedAt,
+	)
+	return i, err
+}
+
 const updateVideoReview = `-- name: UpdateVideoReview :one
 UPDATE video_reviews
-SET content = $2, updated_at = NOW()
-WHERE id = $1 AND video_id = $3
-RETURNING id, video_id, content, timestamp_seconds, created_at, updated_at, parent_id, author_id
+SET content = $1,
+    annotation = CASE WHEN $2::boolean THEN $3::jsonb ELSE annotation END,
+    annotation_version = CASE WHEN $2::boolean THEN annotation_version + 1 ELSE annotation_version END,
+    updated_at = NOW()
+WHERE id = $4 AND video_id = $5
+  AND ($6::int IS NULL OR annotation_version = $6)
+RETURNING id, video_id, content, timestamp_seconds, created_at, updated_at, parent_id, author_id, annotation, annotation_version, end_seconds, snippet_id, thread_state, thread_state_changed_by, thread_state_changed_at
 `
 
 type UpdateVideoReviewParams struct {
-	ID      pgtype.UUID `json:"id"`
-	Content string      `json:"content"`
-	VideoID pgtype.UUID `json:"video_id"`
+	Content                   string      `json:"content"`
+	SetAnnotation             bool        `json:"set_annotation"`
+	Annotation                []byte      `json:"annotation"`
+	ID                        pgtype.UUID `json:"id"`
+	VideoID                   pgtype.UUID `json:"video_id"`
+	ExpectedAnnotationVersion pgtype.Int4 `json:"expected_annotation_version"`
 }
 
+// set_annotation replaces the annotation (NULL removes it) and bumps its
+// version. A non-NULL expected_annotation_version that no longer matches
+// updates nothing, so a concurrent edit surfaces as no rows.
 func (q *Queries) UpdateVideoReview(ctx context.Context, arg UpdateVideoReviewParams) (VideoReview, error) {
-	row := q.db.QueryRow(ctx, updateVideoReview, arg.ID, arg.Content, arg.VideoID)
+	row := q.db.QueryRow(ctx, updateVideoReview,
+		arg.Content,
+		arg.SetAnnotation,
+		arg.Annotation,
+		arg.ID,
+		arg.VideoID,
+		arg.ExpectedAnnotationVersion,
+	)
 	var i VideoReview
 	err := row.Scan(
 		&i.ID,
@@ -295,6 +440,13 @@ func (q *Queries) UpdateVideoReview(ctx context.Context, arg UpdateVideoReviewPa
 		&i.UpdatedAt,
 		&i.ParentID,
 		&i.AuthorID,
+		&i.Annotation,
+		&i.AnnotationVersion,
+		&i.EndSeconds,
+		&i.SnippetID,
+		&i.ThreadState,
+		&i.ThreadStateChangedBy,
+		&i.ThreadStateChangedAt,
 	)
 	return i, err
 }
@@ -38,10 +38,19 @@ type Action struct {
 	URL string
 }
 
-// Message carries translated copy and an optional CTA action.
+// Attachment is a file sent with the email, e.g. a calendar invitation.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Message carries translated copy, an optional CTA action and optional
+// attachments. Attachments are not part of the rendered body.
 type Message struct {
-	Copy   Copy
-	Action *Action
+	Copy        Copy
+	Action      *Action
+	Attachments []Attachment
 }
 
 type RenderedEmail struct {
@@ -34,7 +34,7 @@ func NewService(logger *slog.Logger) *Service {
 }
 
 func (s *Service) Send(to []string, subject string, text string) error {
-	return s.send(to, subject, text, "")
+	return s.send(to, subject, text, "", nil)
 }
 
 func (s *Service) SendTemplate(to []string, subject string, templateName TemplateName, message Message) error {
@@ -42,15 +42,16 @@ func (s *Service) SendTemplate(to []string, subject string, templateName Templat
 	if err != nil {
 		return fmt.Errorf("render email template %q: %w", templateName, err)
 	}
-	return s.send(to, subject, rendered.Text, rendered.HTML)
+	return s.send(to, subject, rendered.Text, rendered.HTML, message.Attachments)
 }
 
-func (s *Service) send(to []string, subject string, text string, html string) error {
+func (s *Service) send(to []string, subject string, text string, html string, attachments []Attachment) error {
 	s.logger.Info("email_send_initiated",
 		slog.String("component", "email_service"),
 		slog.Int("recipient_count", len(to)),
 		slog.String("subject", subject),
 		slog.Bool("has_html", html != ""),
+		slog.Int("attachment_count", len(attachments)),
 	)
 
 	params := &resend.SendEmailRequest{
@@ -60,6 +61,13 @@ func (s *Service) send(to []string, subject string, text string, html string) er
 		Text:    text,
 		Html:    html,
 	}
+	for _, a := range attachments {
+		params.Attachments = append(params.Attachments, &resend.Attachment{
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Content:     a.Content,
+		})
+	}
 
 	resp, err := s.client.Emails.Send(params)
 	if err != nil {
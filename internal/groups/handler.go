@@ -2,16 +2,19 @@ package groups
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/logger"
 	"github.com/OZIOisgood/zeta/internal/permissions"
 	"github.com/OZIOisgood/zeta/internal/pgutil"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -44,12 +47,14 @@ func toGroupResponse(g db.Group) groupResponse {
 
 type Handler struct {
 	q      db.Querier
+	tx     audit.Runner
 	logger *slog.Logger
 }
 
-func NewHandler(q db.Querier, logger *slog.Logger) *Handler {
+func NewHandler(q db.Querier, tx audit.Runner, logger *slog.Logger) *Handler {
 	return &Handler{
 		q:      q,
+		tx:     tx,
 		logger: logger,
 	}
 }
@@ -143,11 +148,43 @@ func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group, err := h.q.CreateGroup(ctx, db.CreateGroupParams{
-		Name:        req.Name,
-		OwnerID:     user.ID,
-		Avatar:      req.Avatar,
-		Description: req.Description,
+	// The group, the owner's membership and both audit events commit together;
+	// a group without its owner as member is unreachable.
+	var group db.Group
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		group, err = tx.CreateGroup(ctx, db.CreateGroupParams{
+			Name:        req.Name,
+			OwnerID:     user.ID,
+			Avatar:      req.Avatar,
+			Description: req.Description,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.AddUserToGroup(ctx, db.AddUserToGroupParams{
+			UserID:  user.ID,
+			GroupID: group.ID,
+		}); err != nil {
+			return err
+		}
+		groupID := pgutil.UUIDToString(group.ID)
+		if err := tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupCreated,
+			ResourceType: audit.ResourceGroup,
+			ResourceID:   groupID,
+			GroupID:      groupID,
+			NewValues:    audit.GroupSnapshotOf(group),
+		}); err != nil {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupMembershipAdded,
+			ResourceType: audit.ResourceGroupMembership,
+			ResourceID:   user.ID,
+			GroupID:      groupID,
+			NewValues:    audit.GroupMembershipSnapshotOf(user.ID, group.ID, "owner"),
+		})
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "group_create_failed",
@@ -160,21 +197,6 @@ func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.q.AddUserToGroup(ctx, db.AddUserToGroupParams{
-		UserID:  user.ID,
-		GroupID: group.ID,
-	})
-	if err != nil {
-		log.ErrorContext(ctx, "group_user_add_failed",
-			slog.String("component", "groups"),
-			slog.String("user_id", user.ID),
-			slog.String("group_id", group.ID.String()),
-			slog.Any("err", err),
-		)
-		http.Error(w, "Failed to link user to group", http.StatusInternalServerError)
-		return
-	}
-
 	log.InfoContext(ctx, "group_created",
 		slog.String("component", "groups"),
 		slog.String("user_id", user.ID),
@@ -293,9 +315,23 @@ func (h *Handler) LeaveGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	removed, err := h.q.LeaveGroupIfNotLastMember(ctx, db.LeaveGroupIfNotLastMemberParams{
-		UserID:  user.ID,
-		GroupID: groupID,
+	var removed int64
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		removed, err = tx.LeaveGroupIfNotLastMember(ctx, db.LeaveGroupIfNotLastMemberParams{
+			UserID:  user.ID,
+			GroupID: groupID,
+		})
+		if err != nil || removed == 0 {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupMembershipLeft,
+			ResourceType: audit.ResourceGroupMembership,
+			ResourceID:   user.ID,
+			GroupID:      groupIDStr,
+			OldValues:    audit.GroupMembershipSnapshotOf(user.ID, groupID, ""),
+		})
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "group_membership_leave_failed",
@@ -384,11 +420,26 @@ func (h *Handler) UpdateGroupPreferences(w http.ResponseWriter, r *http.Request)
 		avatarData = req.Avatar
 	}
 
-	group, err := h.q.UpdateGroup(ctx, db.UpdateGroupParams{
-		ID:          groupID,
-		Name:        req.Name,
-		Description: req.Description,
-		Avatar:      avatarData,
+	var group db.Group
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		group, err = tx.UpdateGroup(ctx, db.UpdateGroupParams{
+			ID:          groupID,
+			Name:        req.Name,
+			Description: req.Description,
+			Avatar:      avatarData,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupUpdated,
+			ResourceType: audit.ResourceGroup,
+			ResourceID:   groupIDStr,
+			GroupID:      groupIDStr,
+			OldValues:    audit.GroupSnapshotOf(existing),
+			NewValues:    audit.GroupSnapshotOf(group),
+		})
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "group_update_failed",
@@ -436,9 +487,32 @@ func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.q.DeleteGroup(ctx, db.DeleteGroupParams{
-		ID:      groupID,
-		OwnerID: user.ID,
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		// Only the owner may delete; anyone else gets the same silent no-op the
+		// owner-scoped DELETE always produced, and nothing is recorded.
+		existing, err := tx.GetGroup(ctx, groupID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if existing.OwnerID != user.ID {
+			return nil
+		}
+		if err := tx.DeleteGroup(ctx, db.DeleteGroupParams{
+			ID:      groupID,
+			OwnerID: user.ID,
+		}); err != nil {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupDeleted,
+			ResourceType: audit.ResourceGroup,
+			ResourceID:   groupIDStr,
+			GroupID:      groupIDStr,
+			OldValues:    audit.GroupSnapshotOf(existing),
+		})
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "group_delete_failed",
@@ -11,6 +11,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
@@ -57,10 +59,18 @@ func mustGroupUUID(t *testing.T) pgtype.UUID {
 	return groupID
 }
 
+func assertActions(t *testing.T, runner *audittest.Runner, want ...string) {
+	t.Helper()
+	got := runner.Actions()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("audit actions = %v, want %v", got, want)
+	}
+}
+
 func TestListGroups_Unauthorized(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default())
 
 	req := httptest.NewRequest(http.MethodGet, "/groups", nil)
 	rec := httptest.NewRecorder()
@@ -75,7 +85,7 @@ func TestListGroups_Unauthorized(t *testing.T) {
 func TestListGroups_Forbidden(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default())
 
 	user := &auth.UserContext{ID: "user-1", Role: "viewer", Permissions: []string{}}
 	req := httptest.NewRequest(http.MethodGet, "/groups", nil)
@@ -92,7 +102,7 @@ func TestListGroups_Forbidden(t *testing.T) {
 func TestListGroups_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default())
 
 	now := time.Now()
 	uuid := pgtype.UUID{Valid: true}
@@ -135,7 +145,7 @@ func TestListGroups_Success(t *testing.T) {
 func TestListGroups_DBError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default())
 
 	q.EXPECT().ListUserGroups(gomock.Any(), "user-1").Return(nil, errors.New("db down"))
 
@@ -154,7 +164,8 @@ func TestListGroups_DBError(t *testing.T) {
 func TestCreateGroup_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default())
 
 	now := time.Now()
 	uuid := pgtype.UUID{Valid: true}
@@ -199,12 +210,36 @@ func TestCreateGroup_Success(t *testing.T) {
 	if resp.Name != "New Group" {
 		t.Errorf("got name %q, want %q", resp.Name, "New Group")
 	}
+	assertActions(t, runner, audit.ActionGroupCreated, audit.ActionGroupMembershipAdded)
+}
+
+func TestCreateGroup_AuditFailureAborts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	runner.RecordErr = errors.New("audit down")
+	h := NewHandler(q, runner, slog.Default())
+
+	q.EXPECT().CreateGroup(gomock.Any(), gomock.Any()).Return(db.Group{ID: mustGroupUUID(t), Name: "New Group", OwnerID: "user-1"}, nil)
+	q.EXPECT().AddUserToGroup(gomock.Any(), gomock.Any()).Return(nil)
+
+	body := `{"name":"New Group","description":"A new group","avatar":"base64data"}`
+	req := httptest.NewRequest(http.MethodPost, "/groups", strings.NewReader(body))
+	req = req.WithContext(testUserCtx(req.Context(), adminUser()))
+	rec := httptest.NewRecorder()
+
+	h.CreateGroup(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	assertActions(t, runner)
 }
 
 func TestCreateGroup_MissingName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default())
 
 	body := `{"name":"","description":"desc","avatar":"base64data"}`
 	user := adminUser()
@@ -222,7 +257,7 @@ func TestCreateGroup_MissingName(t *testing.T) {
 func TestCreateGroup_MissingAvatar(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default())
 
 	body := `{"name":"Group","description":"desc","avatar":""}`
 	user := adminUser()
@@ -240,7 +275,8 @@ func TestCreateGroup_MissingAvatar(t *testing.T) {
 func TestLeaveGroup_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default())
 	groupID := mustGroupUUID(t)
 
 	q.EXPECT().CheckUserGroup(gomock.Any(), db.CheckUserGroupParams{
@@ -268,12 +304,14 @@ func TestLeaveGroup_Success(t *testing.T) {
 	if rec.Code != http.StatusNoContent {
 		t.Fatalf("got %d, want %d; body: %s", rec.Code, http.StatusNoContent, rec.Body.String())
 	}
+	assertActions(t, runner, audit.ActionGroupMembershipLeft)
 }
 
 func TestLeaveGroup_ForbidsLastMember(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, slog.Default())
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default())
 	groupID := mustGroupUUID(t)
 
 	q.EXPECT().CheckUserGroup(gomock.Any(), db.CheckUserGroupParams{
@@ -301,4 +339,5 @@ func TestLeaveGroup_ForbidsLastMember(t *testing.T) {
 	if rec.Code != http.StatusConflict {
 		t.Fatalf("got %d, want %d; body: %s", rec.Code, http.StatusConflict, rec.Body.String())
 	}
+	assertActions(t, runner)
 }
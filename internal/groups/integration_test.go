@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/groups"
@@ -29,8 +30,11 @@ func adminCtx(ctx context.Context) context.Context {
 
 func TestIntegration_CreateAndListGroups(t *testing.T) {
 	pool := testdb.New(t)
+	if err := audit.EnsurePartitions(context.Background(), pool); err != nil {
+		t.Fatalf("EnsurePartitions: %v", err)
+	}
 	q := db.New(pool)
-	h := groups.NewHandler(q, slog.Default())
+	h := groups.NewHandler(q, audit.NewRunner(pool, audit.NewRecorder()), slog.Default())
 
 	// Create group
 	body := `{"name":"Integration Group","description":"test","avatar":"base64img"}`
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/email"
@@ -31,15 +32,17 @@ import (
 
 type Handler struct {
 	q                db.Querier
+	tx               audit.Runner
 	email            email.Sender
 	workos           auth.UserManagement
 	logger           *slog.Logger
 	webInviteBaseURL string
 }
 
-func NewHandler(q db.Querier, email email.Sender, workos auth.UserManagement, logger *slog.Logger, webInviteBaseURL string) *Handler {
+func NewHandler(q db.Querier, tx audit.Runner, email email.Sender, workos auth.UserManagement, logger *slog.Logger, webInviteBaseURL string) *Handler {
 	return &Handler{
 		q:                q,
+		tx:               tx,
 		email:            email,
 		workos:           workos,
 		logger:           logger,
@@ -136,11 +139,19 @@ func (h *Handler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	invitation, err := h.q.CreateGroupInvitation(ctx, db.CreateGroupInvitationParams{
-		GroupID:   pgGroupID,
-		InviterID: user.ID,
-		Email:     invitationEmail,
-		Code:      code,
+	var invitation db.GroupInvitation
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		invitation, err = tx.CreateGroupInvitation(ctx, db.CreateGroupInvitationParams{
+			GroupID:   pgGroupID,
+			InviterID: user.ID,
+			Email:     invitationEmail,
+			Code:      code,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Record(ctx, inviteEvent(audit.ActionGroupInviteCreated, invitation, nil))
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "invitation_create_failed",
@@ -303,8 +314,17 @@ func (h *Handler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid invitation ID", http.StatusBadRequest)
 		return
 	}
-	_, err = h.q.RevokeGroupInvitation(ctx, db.RevokeGroupInvitationParams{
-		ID: pgtype.UUID{Bytes: invitationUUID, Valid: true}, GroupID: groupID,
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		revoked, err := tx.RevokeGroupInvitation(ctx, db.RevokeGroupInvitationParams{
+			ID: pgtype.UUID{Bytes: invitationUUID, Valid: true}, GroupID: groupID,
+		})
+		if err != nil {
+			return err
+		}
+		// Only pending invitations match the revoke, so that is the prior state.
+		pending := revoked
+		pending.Status = db.InvitationStatusPending
+		return tx.Record(ctx, inviteEvent(audit.ActionGroupInviteRevoked, revoked, &pending))
 	})
 	if err == pgx.ErrNoRows {
 		http.Error(w, "Invitation cannot be revoked", http.StatusConflict)
@@ -468,9 +488,37 @@ func (h *Handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.q.AddUserToGroup(ctx, db.AddUserToGroupParams{
-		UserID:  user.ID,
-		GroupID: invitation.GroupID,
+	// Email-specific invitations are single-use, so their status flips in the
+	// same transaction as the membership. Generic link/QR invitations remain
+	// pending so multiple users can join from the same shared link.
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		if err := tx.AddUserToGroup(ctx, db.AddUserToGroupParams{
+			UserID:  user.ID,
+			GroupID: invitation.GroupID,
+		}); err != nil {
+			return err
+		}
+		if err := tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupMembershipAdded,
+			ResourceType: audit.ResourceGroupMembership,
+			ResourceID:   user.ID,
+			GroupID:      groupIDStr,
+			NewValues:    audit.GroupMembershipSnapshotOf(user.ID, invitation.GroupID, "invitation"),
+		}); err != nil {
+			return err
+		}
+		if !invitationHasEmail(invitation) {
+			return nil
+		}
+		if err := tx.UpdateGroupInvitationStatus(ctx, db.UpdateGroupInvitationStatusParams{
+			ID:     invitation.ID,
+			Status: db.InvitationStatusAccepted,
+		}); err != nil {
+			return err
+		}
+		accepted := invitation
+		accepted.Status = db.InvitationStatusAccepted
+		return tx.Record(ctx, inviteEvent(audit.ActionGroupInviteAccepted, accepted, &invitation))
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "invitation_add_user_failed",
@@ -506,21 +554,6 @@ func (h *Handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
 			})
 	}()
 
-	if invitationHasEmail(invitation) {
-		// Email-specific invitations are single-use. Generic link/QR invitations
-		// remain pending so multiple users can join from the same shared link.
-		err = h.q.UpdateGroupInvitationStatus(ctx, db.UpdateGroupInvitationStatusParams{
-			ID:     invitation.ID,
-			Status: db.InvitationStatusAccepted,
-		})
-		if err != nil {
-			log.ErrorContext(ctx, "invitation_status_update_failed",
-				slog.String("component", "invitations"),
-				slog.Any("err", err),
-			)
-		}
-	}
-
 	if invitationHasEmail(invitation) {
 		// Notify inviter for direct invitations. Generic link/QR invitations may be
 		// used many times, so they do not send an email on every acceptance.
@@ -661,9 +694,16 @@ func (h *Handler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if invitation.Status == db.InvitationStatusPending {
-			if err := h.q.UpdateGroupInvitationStatus(ctx, db.UpdateGroupInvitationStatusParams{
-				ID:     invitation.ID,
-				Status: db.InvitationStatusDeclined,
+			if err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+				if err := tx.UpdateGroupInvitationStatus(ctx, db.UpdateGroupInvitationStatusParams{
+					ID:     invitation.ID,
+					Status: db.InvitationStatusDeclined,
+				}); err != nil {
+					return err
+				}
+				declined := invitation
+				declined.Status = db.InvitationStatusDeclined
+				return tx.Record(ctx, inviteEvent(audit.ActionGroupInviteDeclined, declined, &invitation))
 			}); err != nil {
 				log.ErrorContext(ctx, "invitation_decline_status_update_failed",
 					slog.String("component", "invitations"),
@@ -845,6 +885,22 @@ func (h *Handler) requireGroupMembership(w http.ResponseWriter, r *http.Request,
 	return groupID, true
 }
 
+// inviteEvent builds a group_invite event; old is nil when there is no prior
+// state.
+func inviteEvent(action string, invitation db.GroupInvitation, old *db.GroupInvitation) audit.Event {
+	e := audit.Event{
+		Action:       action,
+		ResourceType: audit.ResourceGroupInvite,
+		ResourceID:   pgutil.UUIDToString(invitation.ID),
+		GroupID:      pgutil.UUIDToString(invitation.GroupID),
+		NewValues:    audit.GroupInviteSnapshotOf(invitation),
+	}
+	if old != nil {
+		e.OldValues = audit.GroupInviteSnapshotOf(*old)
+	}
+	return e
+}
+
 func timestamptzPtr(value pgtype.Timestamptz) *time.Time {
 	if !value.Valid {
 		return nil
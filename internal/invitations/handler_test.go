@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
@@ -49,7 +50,7 @@ func TestCreateInvitationAllowsMissingEmail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	sender := emailmocks.NewMockSender(ctrl)
-	h := NewHandler(q, sender, nil, slog.Default(), "http://localhost:4200")
+	h := NewHandler(q, audittest.NewRunner(q), sender, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	pgGroupID := invitationTestUUID(t, groupID)
@@ -103,7 +104,7 @@ func TestCreateInvitationAllowsMissingEmail(t *testing.T) {
 func TestListInvitationsReturnsDeliveryAndStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, slog.Default(), "http://localhost:4200")
+	h := NewHandler(q, audittest.NewRunner(q), nil, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	pgGroupID := invitationTestUUID(t, groupID)
@@ -149,7 +150,8 @@ func TestListInvitationsReturnsDeliveryAndStatus(t *testing.T) {
 func TestRevokeInvitationRevokesPendingInvitation(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, slog.Default(), "http://localhost:4200")
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, nil, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	invitationID := "22222222-2222-2222-2222-222222222222"
@@ -175,6 +177,9 @@ func TestRevokeInvitationRevokesPendingInvitation(t *testing.T) {
 	if rec.Code != http.StatusNoContent {
 		t.Fatalf("got status %d, want %d; body: %s", rec.Code, http.StatusNoContent, rec.Body.String())
 	}
+	if got := strings.Join(runner.Actions(), ","); got != "group_invite.revoked" {
+		t.Errorf("audit actions = %q, want %q", got, "group_invite.revoked")
+	}
 }
 
 type createGenericInvitationMatcher struct {
@@ -199,7 +204,8 @@ func TestAcceptInvitationKeepsGenericInvitationPending(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	sender := emailmocks.NewMockSender(ctrl)
-	h := NewHandler(q, sender, nil, slog.Default(), "http://localhost:4200")
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, sender, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
@@ -249,12 +255,16 @@ func TestAcceptInvitationKeepsGenericInvitationPending(t *testing.T) {
 	if body.GroupID != "11111111-1111-1111-1111-111111111111" {
 		t.Fatalf("got group_id %q, want group uuid", body.GroupID)
 	}
+	if got := strings.Join(runner.Actions(), ","); got != "group_membership.added" {
+		t.Errorf("audit actions = %q, want %q", got, "group_membership.added")
+	}
 }
 
 func TestDeclineInvitationMarksEmailInvitationDeclined(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, slog.Default(), "http://localhost:4200")
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, nil, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
@@ -285,12 +295,16 @@ func TestDeclineInvitationMarksEmailInvitationDeclined(t *testing.T) {
 	if rec.Code != http.StatusNoContent {
 		t.Fatalf("got status %d, want %d", rec.Code, http.StatusNoContent)
 	}
+	if got := strings.Join(runner.Actions(), ","); got != "group_invite.declined" {
+		t.Errorf("audit actions = %q, want %q", got, "group_invite.declined")
+	}
 }
 
 func TestDeclineInvitationRejectsNonRecipient(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, slog.Default(), "http://localhost:4200")
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, nil, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
@@ -317,12 +331,15 @@ func TestDeclineInvitationRejectsNonRecipient(t *testing.T) {
 	if rec.Code != http.StatusNotFound {
 		t.Fatalf("got status %d, want %d", rec.Code, http.StatusNotFound)
 	}
+	if got := strings.Join(runner.Actions(), ","); got != "" {
+		t.Errorf("audit actions = %q, want %q", got, "")
+	}
 }
 
 func TestAcceptInvitationNotifiesGroupOwner(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, slog.Default(), "http://localhost:4200")
+	h := NewHandler(q, audittest.NewRunner(q), nil, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
@@ -375,7 +392,7 @@ func TestAcceptInvitationNotifiesGroupOwner(t *testing.T) {
 func TestDeclineInvitationKeepsGenericInvitationPending(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
-	h := NewHandler(q, nil, nil, slog.Default(), "http://localhost:4200")
+	h := NewHandler(q, audittest.NewRunner(q), nil, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
@@ -410,7 +427,7 @@ func TestGetInvitationInfoMarksExistingMember(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	sender := emailmocks.NewMockSender(ctrl)
-	h := NewHandler(q, sender, nil, slog.Default(), "http://localhost:4200")
+	h := NewHandler(q, audittest.NewRunner(q), sender, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
@@ -470,7 +487,7 @@ func TestAcceptInvitationReturnsExistingMemberGroup(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	sender := emailmocks.NewMockSender(ctrl)
-	h := NewHandler(q, sender, nil, slog.Default(), "http://localhost:4200")
+	h := NewHandler(q, audittest.NewRunner(q), sender, nil, slog.Default(), "http://localhost:4200")
 
 	groupID := invitationTestUUID(t, "11111111-1111-1111-1111-111111111111")
 	invitationID := invitationTestUUID(t, "22222222-2222-2222-2222-222222222222")
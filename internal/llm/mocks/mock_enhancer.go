@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	llm "github.com/OZIOisgood/zeta/internal/llm"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,3 +55,18 @@ func (mr *MockEnhancerMockRecorder) EnhanceReviewText(ctx, originalText any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnhanceReviewText", reflect.TypeOf((*MockEnhancer)(nil).EnhanceReviewText), ctx, originalText)
 }
+
+// SuggestSnippets mocks base method.
+func (m *MockEnhancer) SuggestSnippets(ctx context.Context, draft string, snippets []llm.Snippet) (llm.SnippetSuggestions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestSnippets", ctx, draft, snippets)
+	ret0, _ := ret[0].(llm.SnippetSuggestions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestSnippets indicates an expected call of SuggestSnippets.
+func (mr *MockEnhancerMockRecorder) SuggestSnippets(ctx, draft, snippets any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestSnippets", reflect.TypeOf((*MockEnhancer)(nil).SuggestSnippets), ctx, draft, snippets)
+}
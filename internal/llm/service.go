@@ -18,8 +18,32 @@ import (
 // Enhancer is the interface for LLM text enhancement.
 type Enhancer interface {
 	EnhanceReviewText(ctx context.Context, originalText string) (string, error)
+	SuggestSnippets(ctx context.Context, draft string, snippets []Snippet) (SnippetSuggestions, error)
 }
 
+// Snippet is a saved comment the model may pick from.
+type Snippet struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// SnippetSuggestion is a snippet that fits the draft, with its text rendered
+// in the draft's language.
+type SnippetSuggestion struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// SnippetSuggestions is the result of SuggestSnippets.
+type SnippetSuggestions struct {
+	Language    string
+	Suggestions []SnippetSuggestion
+}
+
+// maxSnippetSuggestions caps how many snippets are proposed for one draft.
+const maxSnippetSuggestions = 3
+
 type Service struct {
 	apiKey string
 	client *http.Client
@@ -104,6 +128,62 @@ func (s *Service) EnhanceReviewText(ctx context.Context, originalText string) (s
 	return enhanced, nil
 }
 
+// SuggestSnippets picks the saved snippets that best match a draft comment and
+// renders them in the language the author is writing in. Suggestions that do
+// not reference one of the given snippets are dropped.
+func (s *Service) SuggestSnippets(ctx context.Context, draft string, snippets []Snippet) (SnippetSuggestions, error) {
+	if s.apiKey == "" {
+		return SnippetSuggestions{}, fmt.Errorf("OpenRouter API key not configured")
+	}
+
+	if strings.TrimSpace(draft) == "" {
+		return SnippetSuggestions{}, fmt.Errorf("text cannot be empty")
+	}
+
+	s.logger.InfoContext(ctx, "llm_suggest_snippets_request",
+		slog.String("component", "llm"),
+		slog.Int("text_length", len(draft)),
+		slog.Int("snippet_count", len(snippets)),
+	)
+
+	// Step 1: same language detection as EnhanceReviewText. The language is
+	// reported even when there is nothing to suggest.
+	lang := s.detectLanguage(ctx, draft)
+	if len(snippets) == 0 {
+		return SnippetSuggestions{Language: lang}, nil
+	}
+
+	// Step 2: let the model choose and translate; Go validates the choice.
+	library, err := json.Marshal(snippets)
+	if err != nil {
+		return SnippetSuggestions{}, fmt.Errorf("failed to marshal snippets: %w", err)
+	}
+	raw, err := s.callAPI(ctx, []Message{
+		{Role: "system", Content: s.buildSnippetSystemPrompt(lang)},
+		{Role: "user", Content: s.buildSnippetPrompt(draft, string(library))},
+	})
+	if err != nil {
+		return SnippetSuggestions{}, err
+	}
+
+	suggestions, err := parseSnippetSuggestions(raw, snippets)
+	if err != nil {
+		s.logger.WarnContext(ctx, "llm_suggest_snippets_parse_failed",
+			slog.String("component", "llm"),
+			slog.String("raw", raw),
+			slog.Any("err", err),
+		)
+		return SnippetSuggestions{}, err
+	}
+
+	s.logger.InfoContext(ctx, "llm_suggest_snippets_success",
+		slog.String("component", "llm"),
+		slog.Int("suggestion_count", len(suggestions)),
+	)
+
+	return SnippetSuggestions{Language: lang, Suggestions: suggestions}, nil
+}
+
 // detectLanguage asks the model to return a JSON map of language→word-percentage,
 // then picks the language with the highest share in Go — not the model.
 // This correctly handles mixed-language input (e.g. mostly English + one forgotten foreign word).
@@ -254,6 +334,54 @@ func (s *Service) buildEnhancementPrompt(text string) string {
 	return fmt.Sprintf("Rewrite the coach feedback below. Return only the enhanced feedback text. Start immediately with the feedback itself; do not introduce it or label it.\n\n%s", text)
 }
 
+func (s *Service) buildSnippetSystemPrompt(lang string) string {
+	return fmt.Sprintf(`You help coaches reuse their saved review comments. You receive a draft comment and a JSON library of saved snippets (id, title, content).
+
+Pick at most %d snippets whose advice matches what the draft is about. If none fit, pick none.
+
+OUTPUT LANGUAGE: %s — write every "text" in %s, translating the snippet content if it is saved in another language. Keep the snippet's meaning; do not merge it with the draft.
+
+Return ONLY a JSON array, best match first — no markdown, no explanation.
+
+Example output: [{"id": "<snippet id>", "text": "Keep your elbow high through contact."}]`, maxSnippetSuggestions, lang, lang)
+}
+
+func (s *Service) buildSnippetPrompt(draft, library string) string {
+	return fmt.Sprintf("Snippet library:\n%s\n\nDraft comment:\n%s", library, draft)
+}
+
+// parseSnippetSuggestions decodes the model's JSON answer, keeping only known
+// snippet IDs, each once, up to maxSnippetSuggestions.
+func parseSnippetSuggestions(raw string, snippets []Snippet) ([]SnippetSuggestion, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+
+	var parsed []SnippetSuggestion
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse suggestions: %w", err)
+	}
+
+	known := make(map[string]bool, len(snippets))
+	for _, sn := range snippets {
+		known[sn.ID] = true
+	}
+	suggestions := make([]SnippetSuggestion, 0, maxSnippetSuggestions)
+	for _, p := range parsed {
+		p.Text = strings.TrimSpace(p.Text)
+		if !known[p.ID] || p.Text == "" {
+			continue
+		}
+		known[p.ID] = false
+		suggestions = append(suggestions, p)
+		if len(suggestions) == maxSnippetSuggestions {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
 func normalizeEnhancedText(text string) string {
 	const preamble = "Here is the enhanced feedback:"
 
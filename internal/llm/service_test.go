@@ -120,3 +120,99 @@ func TestEnhanceReviewTextRemovesProviderPreamble(t *testing.T) {
 		t.Fatalf("OpenRouter calls = %d, want %d", call, len(responses))
 	}
 }
+
+func TestParseSnippetSuggestions(t *testing.T) {
+	snippets := []Snippet{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+
+	got, err := parseSnippetSuggestions("```json\n"+`[
+		{"id":"b","text":" Keep the elbow high. "},
+		{"id":"zzz","text":"invented"},
+		{"id":"b","text":"duplicate"},
+		{"id":"a","text":""},
+		{"id":"c","text":"Bend your knees."},
+		{"id":"d","text":"Follow through."},
+		{"id":"a","text":"One too many."}
+	]`+"\n```", snippets)
+	if err != nil {
+		t.Fatalf("parseSnippetSuggestions() error = %v", err)
+	}
+	want := []SnippetSuggestion{{"b", "Keep the elbow high."}, {"c", "Bend your knees."}, {"d", "Follow through."}}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("suggestion %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := parseSnippetSuggestions("I suggest snippet b", snippets); err == nil {
+		t.Error("accepted a non-JSON answer")
+	}
+}
+
+func TestSuggestSnippetsUsesDetectedLanguage(t *testing.T) {
+	responses := []string{
+		`{"choices":[{"message":{"role":"assistant","content":"{\"German\":90,\"English\":10}"}}]}`,
+		`{"choices":[{"message":{"role":"assistant","content":"[{\"id\":\"s1\",\"text\":\"Ellbogen hoch halten.\"}]"}}]}`,
+	}
+	var prompts []string
+	call := 0
+	service := &Service{
+		apiKey: "test-key",
+		logger: slog.Default(),
+		client: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if call >= len(responses) {
+					t.Fatalf("unexpected OpenRouter call %d", call+1)
+				}
+				body, _ := io.ReadAll(req.Body)
+				prompts = append(prompts, string(body))
+				resp := responses[call]
+				call++
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(resp)),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		},
+	}
+
+	got, err := service.SuggestSnippets(context.Background(), "Ellbogen bleibt zu tief", []Snippet{
+		{ID: "s1", Title: "Elbow", Content: "Keep your elbow high."},
+	})
+	if err != nil {
+		t.Fatalf("SuggestSnippets() error = %v", err)
+	}
+	if got.Language != "German" || len(got.Suggestions) != 1 || got.Suggestions[0].Text != "Ellbogen hoch halten." {
+		t.Fatalf("SuggestSnippets() = %+v", got)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "OUTPUT LANGUAGE: German") {
+		t.Errorf("suggestion prompt does not carry the detected language")
+	}
+}
+
+func TestSuggestSnippetsWithoutLibraryOnlyDetectsLanguage(t *testing.T) {
+	call := 0
+	service := &Service{
+		apiKey: "test-key",
+		logger: slog.Default(),
+		client: &http.Client{
+			Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				if call++; call > 1 {
+					t.Fatal("asked for suggestions from an empty library")
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"choices":[{"message":{"role":"assistant","content":"{\"German\":100}"}}]}`)),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		},
+	}
+	got, err := service.SuggestSnippets(context.Background(), "Ellbogen", nil)
+	if err != nil || len(got.Suggestions) != 0 || got.Language != "German" {
+		t.Fatalf("SuggestSnippets() = %+v, %v", got, err)
+	}
+}
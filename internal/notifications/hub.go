@@ -4,7 +4,8 @@ import "sync"
 
 // Hub is an in-process fan-out of notification events to connected SSE clients,
 // keyed by recipient (WorkOS user id). One Hub lives per API instance; the
-// Listener publishes into it, the SSE handler subscribes/unsubscribes.
+// Listener publishes into it, the SSE handler subscribes/unsubscribes. The
+// coaching booking chat runs a Hub of its own, keyed by booking.
 type Hub struct {
 	mu   sync.RWMutex
 	subs map[string]map[chan []byte]struct{}
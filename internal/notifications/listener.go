@@ -7,26 +7,33 @@ import (
 	"time"
 
 	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const pgNotifyChannel = "notifications"
 
-// Listener holds a dedicated Postgres connection that LISTENs for notification
-// inserts (emitted by the DB trigger) and fans them out through the Hub to
-// locally connected SSE clients. Run it once per API instance. Using
-// LISTEN/NOTIFY keeps delivery correct across multiple instances without any
-// extra infrastructure.
+// Listener holds a dedicated Postgres connection that LISTENs on one channel
+// (fed by a DB trigger) and hands every payload to its dispatch func. Run it
+// once per API instance. Using LISTEN/NOTIFY keeps delivery correct across
+// multiple instances without any extra infrastructure.
 type Listener struct {
-	pool   *pgxpool.Pool
-	q      db.Querier
-	hub    *Hub
-	logger *slog.Logger
+	pool     *pgxpool.Pool
+	channel  string
+	dispatch func(ctx context.Context, payload string)
+	logger   *slog.Logger
+}
+
+func NewListener(pool *pgxpool.Pool, channel string, dispatch func(ctx context.Context, payload string), logger *slog.Logger) *Listener {
+	return &Listener{pool: pool, channel: channel, dispatch: dispatch, logger: logger}
 }
 
-func NewListener(pool *pgxpool.Pool, q db.Querier, hub *Hub, logger *slog.Logger) *Listener {
-	return &Listener{pool: pool, q: q, hub: hub, logger: logger}
+// NewNotificationListener fans notification inserts out through the Hub to
+// locally connected SSE clients.
+func NewNotificationListener(pool *pgxpool.Pool, q db.Querier, hub *Hub, logger *slog.Logger) *Listener {
+	d := &notificationDispatcher{q: q, hub: hub, logger: logger}
+	return NewListener(pool, pgNotifyChannel, d.dispatch, logger)
 }
 
 type notifyPayload struct {
@@ -38,8 +45,9 @@ type notifyPayload struct {
 func (l *Listener) Run(ctx context.Context) {
 	for ctx.Err() == nil {
 		if err := l.listen(ctx); err != nil && ctx.Err() == nil {
-			l.logger.Error("notification_listener_error",
+			l.logger.Error("pg_listener_error",
 				slog.String("component", "notifications"),
+				slog.String("channel", l.channel),
 				slog.Any("err", err),
 			)
 			select {
@@ -57,11 +65,12 @@ func (l *Listener) listen(ctx context.Context) error {
 	}
 	defer conn.Release()
 
-	if _, err := conn.Exec(ctx, "LISTEN "+pgNotifyChannel); err != nil {
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
 		return err
 	}
-	l.logger.Info("notification_listener_started",
+	l.logger.Info("pg_listener_started",
 		slog.String("component", "notifications"),
+		slog.String("channel", l.channel),
 	)
 
 	for {
@@ -73,10 +82,16 @@ func (l *Listener) listen(ctx context.Context) error {
 	}
 }
 
-func (l *Listener) dispatch(ctx context.Context, raw string) {
+type notificationDispatcher struct {
+	q      db.Querier
+	hub    *Hub
+	logger *slog.Logger
+}
+
+func (d *notificationDispatcher) dispatch(ctx context.Context, raw string) {
 	var p notifyPayload
 	if err := json.Unmarshal([]byte(raw), &p); err != nil {
-		l.logger.WarnContext(ctx, "notification_notify_decode_failed",
+		d.logger.WarnContext(ctx, "notification_notify_decode_failed",
 			slog.String("component", "notifications"),
 			slog.Any("err", err),
 		)
@@ -88,9 +103,9 @@ func (l *Listener) dispatch(ctx context.Context, raw string) {
 		return
 	}
 
-	row, err := l.q.GetNotification(ctx, id)
+	row, err := d.q.GetNotification(ctx, id)
 	if err != nil {
-		l.logger.WarnContext(ctx, "notification_notify_fetch_failed",
+		d.logger.WarnContext(ctx, "notification_notify_fetch_failed",
 			slog.String("component", "notifications"),
 			slog.String("notification_id", p.ID),
 			slog.Any("err", err),
@@ -102,5 +117,5 @@ func (l *Listener) dispatch(ctx context.Context, raw string) {
 	if err != nil {
 		return
 	}
-	l.hub.Publish(p.RecipientID, msg)
+	d.hub.Publish(p.RecipientID, msg)
 }
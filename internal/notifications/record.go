@@ -33,7 +33,7 @@ func pushCategory(t Type) (preferences.EmailCategory, bool) {
 	switch t {
 	case TypeVideoUploaded:
 		return preferences.EmailCategoryAssetUploads, true
-	case TypeVideoReviewed:
+	case TypeVideoReviewed, TypeReviewThreadUpdated, TypeReviewReactionAdded:
 		return preferences.EmailCategoryAssetReviews, true
 	case TypeGroupInvitationReceived:
 		return preferences.EmailCategoryInvitationUpdates, true
@@ -41,7 +41,10 @@ func pushCategory(t Type) (preferences.EmailCategory, bool) {
 		return preferences.EmailCategoryGroupMembershipUpdates, true
 	case TypeCoachingBookingCreated:
 		return preferences.EmailCategoryCoachingBookingUpdates, true
-	case TypeCoachingBookingCancelled:
+	case TypeCoachingBookingCancelled, TypeCoachingBookingRescheduleProposed,
+		TypeCoachingBookingRescheduleDeclined, TypeCoachingBookingRescheduled,
+		TypeCoachingWaitlistSlotOffered, TypeCoachingSessionNotesShared,
+		TypeCoachingHomeworkAssigned, TypeCoachingBookingNoShow:
 		return preferences.EmailCategoryCoachingBookingUpdates, true
 	default:
 		return "", false
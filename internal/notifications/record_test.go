@@ -209,6 +209,15 @@ func TestRecord_AllTypes_PushDelivered(t *testing.T) {
 		{TypeGroupInvitationReceived, GroupInvitationReceivedPayload{GroupName: "G"}},
 		{TypeGroupMemberJoined, GroupMemberJoinedPayload{GroupID: "g", GroupName: "G", MemberName: "M"}},
 		{TypeCoachingBookingCreated, CoachingBookingCreatedPayload{BookingID: "b", StudentName: "S"}},
+		{TypeReviewThreadUpdated, ReviewThreadUpdatedPayload{AssetID: "a", ReviewID: "r", ActorName: "A", State: "resolved"}},
+		{TypeReviewReactionAdded, ReviewReactionAddedPayload{AssetID: "a", ReviewID: "r", ActorName: "A", Reaction: "heart"}},
+		{TypeCoachingBookingRescheduleProposed, CoachingBookingRescheduleProposedPayload{BookingID: "b", ActorName: "A"}},
+		{TypeCoachingBookingRescheduleDeclined, CoachingBookingRescheduleDeclinedPayload{BookingID: "b", ActorName: "A"}},
+		{TypeCoachingBookingRescheduled, CoachingBookingRescheduledPayload{BookingID: "b", ActorName: "A"}},
+		{TypeCoachingWaitlistSlotOffered, CoachingWaitlistSlotOfferedPayload{EntryID: "e", ExpertName: "E"}},
+		{TypeCoachingSessionNotesShared, CoachingSessionNotesSharedPayload{BookingID: "b", ExpertName: "E"}},
+		{TypeCoachingHomeworkAssigned, CoachingHomeworkAssignedPayload{HomeworkID: "h", BookingID: "b", ExpertName: "E", Title: "T"}},
+		{TypeCoachingBookingNoShow, CoachingBookingNoShowPayload{BookingID: "b", Outcome: "student_no_show", StudentName: "S", ExpertName: "E"}},
 	}
 
 	for _, tc := range cases {
@@ -242,6 +251,15 @@ func TestPushCategory(t *testing.T) {
 		{TypeGroupMemberJoined, "group_membership_updates", true},
 		{TypeCoachingBookingCreated, "coaching_booking_updates", true},
 		{TypeCoachingBookingCancelled, "coaching_booking_updates", true},
+		{TypeReviewThreadUpdated, "asset_reviews", true},
+		{TypeReviewReactionAdded, "asset_reviews", true},
+		{TypeCoachingBookingRescheduleProposed, "coaching_booking_updates", true},
+		{TypeCoachingBookingRescheduleDeclined, "coaching_booking_updates", true},
+		{TypeCoachingBookingRescheduled, "coaching_booking_updates", true},
+		{TypeCoachingWaitlistSlotOffered, "coaching_booking_updates", true},
+		{TypeCoachingSessionNotesShared, "coaching_booking_updates", true},
+		{TypeCoachingHomeworkAssigned, "coaching_booking_updates", true},
+		{TypeCoachingBookingNoShow, "coaching_booking_updates", true},
 		{"unknown_type", "", false},
 	}
 	for _, tc := range tt {
@@ -15,6 +15,16 @@ const (
 	TypeVideoUploaded            Type = "video_uploaded"
 	TypeCoachingBookingCreated   Type = "coaching_booking_created"
 	TypeCoachingBookingCancelled Type = "coaching_booking_cancelled"
+	TypeReviewThreadUpdated      Type = "review_thread_updated"
+	TypeReviewReactionAdded      Type = "review_reaction_added"
+
+	TypeCoachingBookingRescheduleProposed Type = "coaching_booking_reschedule_proposed"
+	TypeCoachingBookingRescheduleDeclined Type = "coaching_booking_reschedule_declined"
+	TypeCoachingBookingRescheduled        Type = "coaching_booking_rescheduled"
+	TypeCoachingWaitlistSlotOffered       Type = "coaching_waitlist_slot_offered"
+	TypeCoachingSessionNotesShared        Type = "coaching_session_notes_shared"
+	TypeCoachingHomeworkAssigned          Type = "coaching_homework_assigned"
+	TypeCoachingBookingNoShow             Type = "coaching_booking_no_show"
 )
 
 // Payloads are denormalized so the client can render text and build a deep-link
@@ -59,6 +69,10 @@ type CoachingBookingCreatedPayload struct {
 	// DurationMinutes lets clients derive the session end time from
 	// ScheduledAt, which is what decides the sessions tab they deep-link into.
 	DurationMinutes int `json:"duration_minutes"`
+	// SeriesID and Occurrences are set when a booking series was created;
+	// BookingID and ScheduledAt then describe its first session.
+	SeriesID    string `json:"series_id,omitempty"`
+	Occurrences int    `json:"occurrences,omitempty"`
 }
 
 // ActorName is whoever cancelled — either party can, so it is not student_name.
@@ -72,4 +86,110 @@ type CoachingBookingCancelledPayload struct {
 	// DurationMinutes lets clients derive the session end time from
 	// ScheduledAt, which is what decides the sessions tab they deep-link into.
 	DurationMinutes int `json:"duration_minutes"`
+	// SeriesID and Occurrences are set when a series was cancelled from
+	// ScheduledAt onwards; Occurrences counts the cancelled sessions.
+	SeriesID    string `json:"series_id,omitempty"`
+	Occurrences int    `json:"occurrences,omitempty"`
+}
+
+// ActorName proposed the new time. ScheduledAt is the current start and
+// ProposedScheduledAt the requested one.
+type CoachingBookingRescheduleProposedPayload struct {
+	BookingID           string `json:"booking_id"`
+	GroupID             string `json:"group_id,omitempty"`
+	GroupName           string `json:"group_name,omitempty"`
+	ActorName           string `json:"actor_name"`
+	SessionName         string `json:"session_name,omitempty"`
+	ScheduledAt         string `json:"scheduled_at,omitempty"`          // RFC3339
+	ProposedScheduledAt string `json:"proposed_scheduled_at,omitempty"` // RFC3339
+	DurationMinutes     int    `json:"duration_minutes"`
+}
+
+// ActorName declined the proposal; the booking keeps ScheduledAt.
+type CoachingBookingRescheduleDeclinedPayload struct {
+	BookingID           string `json:"booking_id"`
+	GroupID             string `json:"group_id,omitempty"`
+	GroupName           string `json:"group_name,omitempty"`
+	ActorName           string `json:"actor_name"`
+	SessionName         string `json:"session_name,omitempty"`
+	ScheduledAt         string `json:"scheduled_at,omitempty"`          // RFC3339
+	ProposedScheduledAt string `json:"proposed_scheduled_at,omitempty"` // RFC3339
+	DurationMinutes     int    `json:"duration_minutes"`
+}
+
+// ActorName accepted the proposal. Both participants receive this.
+type CoachingBookingRescheduledPayload struct {
+	BookingID           string `json:"booking_id"`
+	GroupID             string `json:"group_id,omitempty"`
+	GroupName           string `json:"group_name,omitempty"`
+	ActorName           string `json:"actor_name"`
+	SessionName         string `json:"session_name,omitempty"`
+	ScheduledAt         string `json:"scheduled_at,omitempty"`          // RFC3339
+	PreviousScheduledAt string `json:"previous_scheduled_at,omitempty"` // RFC3339
+	DurationMinutes     int    `json:"duration_minutes"`
+}
+
+// ExpertName holds the slot at ScheduledAt for the waitlisted student until
+// ExpiresAt; EntryID is the waitlist entry to confirm or decline.
+type CoachingWaitlistSlotOfferedPayload struct {
+	EntryID         string `json:"entry_id"`
+	GroupID         string `json:"group_id,omitempty"`
+	GroupName       string `json:"group_name,omitempty"`
+	ExpertName      string `json:"expert_name"`
+	SessionName     string `json:"session_name,omitempty"`
+	ScheduledAt     string `json:"scheduled_at,omitempty"` // RFC3339
+	ExpiresAt       string `json:"expires_at,omitempty"`   // RFC3339
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+// ExpertName shared notes for the session at ScheduledAt; the summary is
+// fetched by BookingID.
+type CoachingSessionNotesSharedPayload struct {
+	BookingID   string `json:"booking_id"`
+	GroupID     string `json:"group_id,omitempty"`
+	GroupName   string `json:"group_name,omitempty"`
+	ExpertName  string `json:"expert_name"`
+	ScheduledAt string `json:"scheduled_at,omitempty"` // RFC3339
+}
+
+// DueAt is empty when the homework has no due date.
+type CoachingHomeworkAssignedPayload struct {
+	HomeworkID string `json:"homework_id"`
+	BookingID  string `json:"booking_id"`
+	GroupID    string `json:"group_id,omitempty"`
+	GroupName  string `json:"group_name,omitempty"`
+	ExpertName string `json:"expert_name"`
+	Title      string `json:"title"`
+	DueAt      string `json:"due_at,omitempty"` // RFC3339
+}
+
+// Outcome is student_no_show or expert_no_show; sent to the group owner when
+// the session type asks for it.
+type CoachingBookingNoShowPayload struct {
+	BookingID   string `json:"booking_id"`
+	GroupID     string `json:"group_id,omitempty"`
+	GroupName   string `json:"group_name,omitempty"`
+	Outcome     string `json:"outcome"`
+	StudentName string `json:"student_name"`
+	ExpertName  string `json:"expert_name"`
+	ScheduledAt string `json:"scheduled_at,omitempty"` // RFC3339
+}
+
+// State is the new thread state: open, acknowledged or resolved.
+type ReviewThreadUpdatedPayload struct {
+	AssetID    string `json:"asset_id"`
+	VideoID    string `json:"video_id"`
+	ReviewID   string `json:"review_id"`
+	VideoTitle string `json:"video_title"`
+	ActorName  string `json:"actor_name"`
+	State      string `json:"state"`
+}
+
+type ReviewReactionAddedPayload struct {
+	AssetID    string `json:"asset_id"`
+	VideoID    string `json:"video_id"`
+	ReviewID   string `json:"review_id"`
+	VideoTitle string `json:"video_title"`
+	ActorName  string `json:"actor_name"`
+	Reaction   string `json:"reaction"`
 }
@@ -39,6 +39,9 @@ const (
 
 	InboundEmailRead  = "inbound-email:read"
 	InboundEmailReply = "inbound-email:reply"
+
+	AuditEventsRead   = "audit:events:read"
+	AuditEventsExport = "audit:events:export"
 )
 
 // Roles
@@ -19,6 +19,16 @@ const (
 	typeVideoUploaded            = "video_uploaded"
 	typeCoachingBookingCreated   = "coaching_booking_created"
 	typeCoachingBookingCancelled = "coaching_booking_cancelled"
+	typeReviewThreadUpdated      = "review_thread_updated"
+	typeReviewReactionAdded      = "review_reaction_added"
+
+	typeCoachingBookingRescheduleProposed = "coaching_booking_reschedule_proposed"
+	typeCoachingBookingRescheduleDeclined = "coaching_booking_reschedule_declined"
+	typeCoachingBookingRescheduled        = "coaching_booking_rescheduled"
+	typeCoachingWaitlistSlotOffered       = "coaching_waitlist_slot_offered"
+	typeCoachingSessionNotesShared        = "coaching_session_notes_shared"
+	typeCoachingHomeworkAssigned          = "coaching_homework_assigned"
+	typeCoachingBookingNoShow             = "coaching_booking_no_show"
 )
 
 // Local payload shapes mirror the structs in internal/notifications/types.go.
@@ -61,6 +71,8 @@ type coachingBookingCreatedPayload struct {
 	SessionName     string `json:"session_name,omitempty"`
 	ScheduledAt     string `json:"scheduled_at,omitempty"` // RFC3339
 	DurationMinutes int    `json:"duration_minutes"`
+	SeriesID        string `json:"series_id,omitempty"`
+	Occurrences     int    `json:"occurrences,omitempty"`
 }
 
 type coachingBookingCancelledPayload struct {
@@ -71,6 +83,98 @@ type coachingBookingCancelledPayload struct {
 	SessionName     string `json:"session_name,omitempty"`
 	ScheduledAt     string `json:"scheduled_at,omitempty"` // RFC3339
 	DurationMinutes int    `json:"duration_minutes"`
+	SeriesID        string `json:"series_id,omitempty"`
+	Occurrences     int    `json:"occurrences,omitempty"`
+}
+
+type coachingBookingRescheduleProposedPayload struct {
+	BookingID           string `json:"booking_id"`
+	GroupID             string `json:"group_id,omitempty"`
+	GroupName           string `json:"group_name,omitempty"`
+	ActorName           string `json:"actor_name"`
+	SessionName         string `json:"session_name,omitempty"`
+	ScheduledAt         string `json:"scheduled_at,omitempty"`          // RFC3339
+	ProposedScheduledAt string `json:"proposed_scheduled_at,omitempty"` // RFC3339
+	DurationMinutes     int    `json:"duration_minutes"`
+}
+
+type coachingBookingRescheduleDeclinedPayload struct {
+	BookingID           string `json:"booking_id"`
+	GroupID             string `json:"group_id,omitempty"`
+	GroupName           string `json:"group_name,omitempty"`
+	ActorName           string `json:"actor_name"`
+	SessionName         string `json:"session_name,omitempty"`
+	ScheduledAt         string `json:"scheduled_at,omitempty"`          // RFC3339
+	ProposedScheduledAt string `json:"proposed_scheduled_at,omitempty"` // RFC3339
+	DurationMinutes     int    `json:"duration_minutes"`
+}
+
+type coachingBookingRescheduledPayload struct {
+	BookingID           string `json:"booking_id"`
+	GroupID             string `json:"group_id,omitempty"`
+	GroupName           string `json:"group_name,omitempty"`
+	ActorName           string `json:"actor_name"`
+	SessionName         string `json:"session_name,omitempty"`
+	ScheduledAt         string `json:"scheduled_at,omitempty"`          // RFC3339
+	PreviousScheduledAt string `json:"previous_scheduled_at,omitempty"` // RFC3339
+	DurationMinutes     int    `json:"duration_minutes"`
+}
+
+type coachingWaitlistSlotOfferedPayload struct {
+	EntryID         string `json:"entry_id"`
+	GroupID         string `json:"group_id,omitempty"`
+	GroupName       string `json:"group_name,omitempty"`
+	ExpertName      string `json:"expert_name"`
+	SessionName     string `json:"session_name,omitempty"`
+	ScheduledAt     string `json:"scheduled_at,omitempty"` // RFC3339
+	ExpiresAt       string `json:"expires_at,omitempty"`   // RFC3339
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+type coachingSessionNotesSharedPayload struct {
+	BookingID   string `json:"booking_id"`
+	GroupID     string `json:"group_id,omitempty"`
+	GroupName   string `json:"group_name,omitempty"`
+	ExpertName  string `json:"expert_name"`
+	ScheduledAt string `json:"scheduled_at,omitempty"` // RFC3339
+}
+
+type coachingHomeworkAssignedPayload struct {
+	HomeworkID string `json:"homework_id"`
+	BookingID  string `json:"booking_id"`
+	GroupID    string `json:"group_id,omitempty"`
+	GroupName  string `json:"group_name,omitempty"`
+	ExpertName string `json:"expert_name"`
+	Title      string `json:"title"`
+	DueAt      string `json:"due_at,omitempty"` // RFC3339
+}
+
+type coachingBookingNoShowPayload struct {
+	BookingID   string `json:"booking_id"`
+	GroupID     string `json:"group_id,omitempty"`
+	GroupName   string `json:"group_name,omitempty"`
+	Outcome     string `json:"outcome"`
+	StudentName string `json:"student_name"`
+	ExpertName  string `json:"expert_name"`
+	ScheduledAt string `json:"scheduled_at,omitempty"` // RFC3339
+}
+
+type reviewThreadUpdatedPayload struct {
+	AssetID    string `json:"asset_id"`
+	VideoID    string `json:"video_id"`
+	ReviewID   string `json:"review_id"`
+	VideoTitle string `json:"video_title"`
+	ActorName  string `json:"actor_name"`
+	State      string `json:"state"`
+}
+
+type reviewReactionAddedPayload struct {
+	AssetID    string `json:"asset_id"`
+	VideoID    string `json:"video_id"`
+	ReviewID   string `json:"review_id"`
+	VideoTitle string `json:"video_title"`
+	ActorName  string `json:"actor_name"`
+	Reaction   string `json:"reaction"`
 }
 
 // BuildMessage translates a notification type and its JSON payload into the
@@ -148,7 +252,14 @@ func BuildMessage(notificationType string, payload []byte) (title, body string,
 			return "", "", nil, false
 		}
 		title = "New coaching session booked"
-		if p.SessionName != "" {
+		if p.Occurrences > 1 {
+			title = "Recurring coaching sessions booked"
+			if p.SessionName != "" {
+				body = fmt.Sprintf("%s booked %d \"%s\" sessions", p.StudentName, p.Occurrences, p.SessionName)
+			} else {
+				body = fmt.Sprintf("%s booked %d coaching sessions", p.StudentName, p.Occurrences)
+			}
+		} else if p.SessionName != "" {
 			body = fmt.Sprintf("%s booked \"%s\"", p.StudentName, p.SessionName)
 		} else {
 			body = fmt.Sprintf("%s booked a coaching session", p.StudentName)
@@ -164,7 +275,14 @@ func BuildMessage(notificationType string, payload []byte) (title, body string,
 			return "", "", nil, false
 		}
 		title = "Coaching session cancelled"
-		if p.SessionName != "" {
+		if p.Occurrences > 1 {
+			title = "Coaching sessions cancelled"
+			if p.SessionName != "" {
+				body = fmt.Sprintf("%s cancelled %d \"%s\" sessions", p.ActorName, p.Occurrences, p.SessionName)
+			} else {
+				body = fmt.Sprintf("%s cancelled %d coaching sessions", p.ActorName, p.Occurrences)
+			}
+		} else if p.SessionName != "" {
 			body = fmt.Sprintf("%s cancelled \"%s\"", p.ActorName, p.SessionName)
 		} else {
 			body = fmt.Sprintf("%s cancelled the coaching session", p.ActorName)
@@ -174,6 +292,140 @@ func BuildMessage(notificationType string, payload []byte) (title, body string,
 			data["group_id"] = p.GroupID
 		}
 
+	case typeCoachingBookingRescheduleProposed:
+		var p coachingBookingRescheduleProposedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "New time proposed"
+		if p.SessionName != "" {
+			body = fmt.Sprintf("%s wants to move \"%s\"", p.ActorName, p.SessionName)
+		} else {
+			body = fmt.Sprintf("%s wants to move the coaching session", p.ActorName)
+		}
+		data["booking_id"] = p.BookingID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeCoachingBookingRescheduleDeclined:
+		var p coachingBookingRescheduleDeclinedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "New time declined"
+		if p.SessionName != "" {
+			body = fmt.Sprintf("%s kept \"%s\" at its current time", p.ActorName, p.SessionName)
+		} else {
+			body = fmt.Sprintf("%s kept the coaching session at its current time", p.ActorName)
+		}
+		data["booking_id"] = p.BookingID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeCoachingBookingRescheduled:
+		var p coachingBookingRescheduledPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "Coaching session rescheduled"
+		if p.SessionName != "" {
+			body = fmt.Sprintf("\"%s\" has moved to a new time", p.SessionName)
+		} else {
+			body = "Your coaching session has moved to a new time"
+		}
+		data["booking_id"] = p.BookingID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeCoachingWaitlistSlotOffered:
+		var p coachingWaitlistSlotOfferedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "A coaching slot opened up"
+		if p.SessionName != "" {
+			body = fmt.Sprintf("A \"%s\" session with %s is held for you", p.SessionName, p.ExpertName)
+		} else {
+			body = fmt.Sprintf("A coaching session with %s is held for you", p.ExpertName)
+		}
+		data["entry_id"] = p.EntryID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeCoachingSessionNotesShared:
+		var p coachingSessionNotesSharedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "Session notes shared"
+		body = fmt.Sprintf("%s wrote up your coaching session", p.ExpertName)
+		data["booking_id"] = p.BookingID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeCoachingHomeworkAssigned:
+		var p coachingHomeworkAssignedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "New homework"
+		body = fmt.Sprintf("%s assigned \"%s\"", p.ExpertName, p.Title)
+		data["homework_id"] = p.HomeworkID
+		data["booking_id"] = p.BookingID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeCoachingBookingNoShow:
+		var p coachingBookingNoShowPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "Coaching session missed"
+		if p.Outcome == "expert_no_show" {
+			body = fmt.Sprintf("%s did not join the session with %s", p.ExpertName, p.StudentName)
+		} else {
+			body = fmt.Sprintf("%s did not join the session with %s", p.StudentName, p.ExpertName)
+		}
+		data["booking_id"] = p.BookingID
+		if p.GroupID != "" {
+			data["group_id"] = p.GroupID
+		}
+
+	case typeReviewThreadUpdated:
+		var p reviewThreadUpdatedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		switch p.State {
+		case "acknowledged":
+			title = "Feedback acknowledged"
+			body = fmt.Sprintf("%s acknowledged your feedback on \"%s\"", p.ActorName, p.VideoTitle)
+		case "resolved":
+			title = "Feedback resolved"
+			body = fmt.Sprintf("%s marked feedback on \"%s\" as resolved", p.ActorName, p.VideoTitle)
+		default:
+			title = "Feedback reopened"
+			body = fmt.Sprintf("%s reopened feedback on \"%s\"", p.ActorName, p.VideoTitle)
+		}
+		data["asset_id"] = p.AssetID
+		data["review_id"] = p.ReviewID
+
+	case typeReviewReactionAdded:
+		var p reviewReactionAddedPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", "", nil, false
+		}
+		title = "New reaction"
+		body = fmt.Sprintf("%s reacted to your comment on \"%s\"", p.ActorName, p.VideoTitle)
+		data["asset_id"] = p.AssetID
+		data["review_id"] = p.ReviewID
+
 	default:
 		return "", "", nil, false
 	}
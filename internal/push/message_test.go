@@ -227,6 +227,220 @@ func TestBuildMessage(t *testing.T) {
 				assert.False(t, hasGroupID, "group_id should be absent when empty")
 			},
 		},
+		{
+			name:             "coaching_booking_created for a series",
+			notificationType: typeCoachingBookingCreated,
+			payload: mustMarshal(coachingBookingCreatedPayload{
+				BookingID:   "book-8",
+				GroupID:     "grp-8",
+				StudentName: "Dave",
+				SessionName: "60-min Technique",
+				SeriesID:    "series-1",
+				Occurrences: 8,
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, "book-8", data["booking_id"])
+				assert.Equal(t, "grp-8", data["group_id"])
+			},
+		},
+		{
+			name:             "coaching_booking_cancelled for a series",
+			notificationType: typeCoachingBookingCancelled,
+			payload: mustMarshal(coachingBookingCancelledPayload{
+				BookingID:   "book-9",
+				ActorName:   "Eve",
+				SeriesID:    "series-1",
+				Occurrences: 3,
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, "book-9", data["booking_id"])
+			},
+		},
+		{
+			name:             "review_thread_updated resolved",
+			notificationType: typeReviewThreadUpdated,
+			payload: mustMarshal(reviewThreadUpdatedPayload{
+				AssetID:    "asset-5",
+				ReviewID:   "review-1",
+				VideoTitle: "Sprint Drill",
+				ActorName:  "Coach Jane",
+				State:      "resolved",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, typeReviewThreadUpdated, data["type"])
+				assert.Equal(t, "asset-5", data["asset_id"])
+				assert.Equal(t, "review-1", data["review_id"])
+			},
+		},
+		{
+			name:             "review_reaction_added",
+			notificationType: typeReviewReactionAdded,
+			payload: mustMarshal(reviewReactionAddedPayload{
+				AssetID:    "asset-6",
+				ReviewID:   "review-2",
+				VideoTitle: "Flip Turn Practice",
+				ActorName:  "Bob",
+				Reaction:   "thumbs_up",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, "asset-6", data["asset_id"])
+				assert.Equal(t, "review-2", data["review_id"])
+			},
+		},
+		{
+			name:             "coaching_booking_reschedule_proposed",
+			notificationType: typeCoachingBookingRescheduleProposed,
+			payload: mustMarshal(coachingBookingRescheduleProposedPayload{
+				BookingID:           "book-5",
+				GroupID:             "grp-6",
+				ActorName:           "Frank",
+				SessionName:         "Private Lesson",
+				ScheduledAt:         "2026-06-14T10:00:00Z",
+				ProposedScheduledAt: "2026-06-15T10:00:00Z",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, "book-5", data["booking_id"])
+				assert.Equal(t, "grp-6", data["group_id"])
+			},
+		},
+		{
+			name:             "coaching_booking_reschedule_declined",
+			notificationType: typeCoachingBookingRescheduleDeclined,
+			payload: mustMarshal(coachingBookingRescheduleDeclinedPayload{
+				BookingID: "book-6",
+				ActorName: "Gina",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, "book-6", data["booking_id"])
+				_, hasGroupID := data["group_id"]
+				assert.False(t, hasGroupID, "group_id should be absent when empty")
+			},
+		},
+		{
+			name:             "coaching_booking_rescheduled",
+			notificationType: typeCoachingBookingRescheduled,
+			payload: mustMarshal(coachingBookingRescheduledPayload{
+				BookingID:           "book-7",
+				GroupID:             "grp-7",
+				ActorName:           "Hana",
+				SessionName:         "Private Lesson",
+				ScheduledAt:         "2026-06-15T10:00:00Z",
+				PreviousScheduledAt: "2026-06-14T10:00:00Z",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, typeCoachingBookingRescheduled, data["type"])
+				assert.Equal(t, "book-7", data["booking_id"])
+				assert.Equal(t, "grp-7", data["group_id"])
+			},
+		},
+		{
+			name:             "coaching_waitlist_slot_offered",
+			notificationType: typeCoachingWaitlistSlotOffered,
+			payload: mustMarshal(coachingWaitlistSlotOfferedPayload{
+				EntryID:     "entry-1",
+				GroupID:     "grp-8",
+				ExpertName:  "Ivo",
+				SessionName: "Private Lesson",
+				ScheduledAt: "2026-06-15T10:00:00Z",
+				ExpiresAt:   "2026-06-14T12:00:00Z",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, typeCoachingWaitlistSlotOffered, data["type"])
+				assert.Equal(t, "entry-1", data["entry_id"])
+				assert.Equal(t, "grp-8", data["group_id"])
+			},
+		},
+		{
+			name:             "coaching_session_notes_shared",
+			notificationType: typeCoachingSessionNotesShared,
+			payload: mustMarshal(coachingSessionNotesSharedPayload{
+				BookingID:   "booking-9",
+				GroupID:     "grp-9",
+				ExpertName:  "Ivo",
+				ScheduledAt: "2026-06-15T10:00:00Z",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, typeCoachingSessionNotesShared, data["type"])
+				assert.Equal(t, "booking-9", data["booking_id"])
+				assert.Equal(t, "grp-9", data["group_id"])
+			},
+		},
+		{
+			name:             "coaching_homework_assigned",
+			notificationType: typeCoachingHomeworkAssigned,
+			payload: mustMarshal(coachingHomeworkAssignedPayload{
+				HomeworkID: "hw-1",
+				BookingID:  "booking-9",
+				GroupID:    "grp-9",
+				ExpertName: "Ivo",
+				Title:      "Shadow footwork drill",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, typeCoachingHomeworkAssigned, data["type"])
+				assert.Equal(t, "hw-1", data["homework_id"])
+				assert.Equal(t, "booking-9", data["booking_id"])
+			},
+		},
+		{
+			name:             "coaching_booking_no_show",
+			notificationType: typeCoachingBookingNoShow,
+			payload: mustMarshal(coachingBookingNoShowPayload{
+				BookingID:   "booking-9",
+				GroupID:     "grp-9",
+				Outcome:     "student_no_show",
+				StudentName: "Mia",
+				ExpertName:  "Ivo",
+			}),
+			wantOk:            true,
+			wantTitleNonEmpty: true,
+			wantBodyNonEmpty:  true,
+			checkData: func(t *testing.T, data map[string]string) {
+				t.Helper()
+				assert.Equal(t, typeCoachingBookingNoShow, data["type"])
+				assert.Equal(t, "booking-9", data["booking_id"])
+				assert.Equal(t, "grp-9", data["group_id"])
+			},
+		},
 		{
 			name:             "unknown type returns ok=false",
 			notificationType: "not_a_real_type",
@@ -262,3 +476,17 @@ func TestBuildMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildMessage_SeriesCountsOccurrences(t *testing.T) {
+	_, body, _, ok := BuildMessage(typeCoachingBookingCreated, mustMarshal(coachingBookingCreatedPayload{
+		BookingID: "book-1", StudentName: "Dave", SessionName: "Technique", Occurrences: 8,
+	}))
+	require.True(t, ok)
+	assert.Equal(t, `Dave booked 8 "Technique" sessions`, body)
+
+	_, body, _, ok = BuildMessage(typeCoachingBookingCancelled, mustMarshal(coachingBookingCancelledPayload{
+		BookingID: "book-1", ActorName: "Eve", Occurrences: 3,
+	}))
+	require.True(t, ok)
+	assert.Equal(t, "Eve cancelled 3 coaching sessions", body)
+}
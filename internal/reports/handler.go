@@ -52,6 +52,13 @@ type event struct {
 	Title           string    `json:"title"`
 	At              time.Time `json:"at"`
 	DurationSeconds float64   `json:"duration_seconds"`
+	// RevenueCents is what a paid live session brought in; free and
+	// refunded sessions omit it.
+	RevenueCents int32  `json:"revenue_cents,omitempty"`
+	Currency     string `json:"currency,omitempty"`
+	// Attendance is the outcome of a live session once it has been decided:
+	// attended, partial, student_no_show or expert_no_show.
+	Attendance string `json:"attendance,omitempty"`
 }
 
 type eventsResponse struct {
@@ -108,7 +115,7 @@ func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for _, s := range sessions {
-			resp.Events = append(resp.Events, sessionEvent(ctx, names, s.GroupID, s.GroupName, s.StudentID, s.ExpertID, s.Title, s.At, s.DurationMinutes))
+			resp.Events = append(resp.Events, sessionEvent(ctx, names, s.GroupID, s.GroupName, s.StudentID, s.ExpertID, s.Title, s.At, s.DurationMinutes, s.RevenueCents, s.Currency, string(s.AttendanceOutcome.CoachingAttendanceOutcome)))
 		}
 	} else {
 		uploads, err := h.q.ReportUploadEventsForExpert(ctx, user.ID)
@@ -134,7 +141,7 @@ func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for _, s := range sessions {
-			resp.Events = append(resp.Events, sessionEvent(ctx, names, s.GroupID, s.GroupName, s.StudentID, s.ExpertID, s.Title, s.At, s.DurationMinutes))
+			resp.Events = append(resp.Events, sessionEvent(ctx, names, s.GroupID, s.GroupName, s.StudentID, s.ExpertID, s.Title, s.At, s.DurationMinutes, s.RevenueCents, s.Currency, string(s.AttendanceOutcome.CoachingAttendanceOutcome)))
 		}
 	}
 
@@ -151,8 +158,11 @@ func sessionEvent(
 	groupName, studentID, expertID, title string,
 	at pgtype.Timestamptz,
 	durationMinutes int32,
+	revenueCents int32,
+	currency string,
+	attendance string,
 ) event {
-	return event{
+	e := event{
 		Kind:            "live",
 		Group:           ref{ID: pgutil.UUIDToString(groupID), Name: groupName},
 		Student:         names.ref(ctx, studentID),
@@ -160,7 +170,13 @@ func sessionEvent(
 		Title:           title,
 		At:              at.Time,
 		DurationSeconds: float64(durationMinutes) * 60,
+		Attendance:      attendance,
 	}
+	if revenueCents > 0 {
+		e.RevenueCents = revenueCents
+		e.Currency = currency
+	}
+	return e
 }
 
 func (h *Handler) fail(ctx context.Context, log *slog.Logger, w http.ResponseWriter, event string, err error) {
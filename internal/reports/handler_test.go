@@ -59,6 +59,7 @@ func TestEvents_StudentUsesStudentScopedQueries(t *testing.T) {
 	q.EXPECT().ReportSessionEventsForStudent(gomock.Any(), user.ID).Return([]db.ReportSessionEventsForStudentRow{{
 		Title: "Schwunganalyse", At: mustTS(t, "2026-06-04T10:00:00Z"),
 		GroupName: "Heinrichs Gruppe", StudentID: user.ID, ExpertID: "expert-1", DurationMinutes: 45,
+		AttendanceOutcome: db.NullCoachingAttendanceOutcome{CoachingAttendanceOutcome: db.CoachingAttendanceOutcomePartial, Valid: true},
 	}}, nil)
 	// Expert-scoped queries must never run for a student.
 	q.EXPECT().ReportUploadEventsForExpert(gomock.Any(), gomock.Any()).Times(0)
@@ -86,6 +87,9 @@ func TestEvents_StudentUsesStudentScopedQueries(t *testing.T) {
 	if resp.Events[1].Kind != "live" || resp.Events[1].DurationSeconds != 45*60 {
 		t.Fatalf("unexpected live event (minutes should convert to seconds): %+v", resp.Events[1])
 	}
+	if resp.Events[1].Attendance != "partial" || resp.Events[0].Attendance != "" {
+		t.Fatalf("attendance = %q/%q, want only the live session's outcome", resp.Events[0].Attendance, resp.Events[1].Attendance)
+	}
 }
 
 func TestEvents_ExpertUsesExpertScopedQueries(t *testing.T) {
@@ -0,0 +1,177 @@
+package reviews
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// Shape types an annotation layer may contain.
+const (
+	ShapePath   = "path"
+	ShapeArrow  = "arrow"
+	ShapeCircle = "circle"
+	ShapeAngle  = "angle"
+)
+
+const (
+	maxAnnotationShapes  = 50
+	maxPathPoints        = 1000
+	maxAnnotationPoints  = 5000
+	maxStrokeWidth       = 0.05
+	maxAnnotationSeconds = 24 * 60 * 60
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// fixedShapePoints is the exact point count of every shape except path.
+var fixedShapePoints = map[string]int{ShapeArrow: 2, ShapeCircle: 1, ShapeAngle: 3}
+
+// Point is a position in normalized frame coordinates: (0,0) is the top-left
+// corner of the video frame and (1,1) the bottom-right, independent of the
+// resolution the client renders at.
+type Point [2]float64
+
+// Shape is one element of an annotation layer. Which fields apply depends on
+// Type:
+//
+//	path   — Points is the freehand stroke, at least two points
+//	arrow  — Points is [tail, head]
+//	circle — Points is [center]; Radius is a fraction of the frame width
+//	angle  — Points is [arm end, vertex, arm end]; Degrees is set by the server
+type Shape struct {
+	Type        string   `json:"type"`
+	Points      []Point  `json:"points"`
+	Radius      float64  `json:"radius,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	StrokeWidth float64  `json:"stroke_width,omitempty"`
+	Degrees     *float64 `json:"degrees,omitempty"`
+}
+
+// Annotation is the vector layer drawn over the frame while playback is
+// between StartSeconds and EndSeconds.
+type Annotation struct {
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+	Shapes       []Shape `json:"shapes"`
+}
+
+// parseAnnotation decodes and validates a client-supplied layer for a review
+// pinned at timestampSeconds. Angle measurements are recomputed so every
+// client shows the same value.
+func parseAnnotation(raw json.RawMessage, timestampSeconds *int32) (*Annotation, error) {
+	var a Annotation
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&a); err != nil {
+		return nil, errors.New("annotation is not valid JSON")
+	}
+	if timestampSeconds == nil {
+		return nil, errors.New("annotations require timestamp_seconds")
+	}
+	if err := a.validate(float64(*timestampSeconds)); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (a *Annotation) validate(timestamp float64) error {
+	if a.StartSeconds < 0 || a.EndSeconds > maxAnnotationSeconds {
+		return errors.New("annotation range is out of bounds")
+	}
+	if a.EndSeconds <= a.StartSeconds {
+		return errors.New("annotation end_seconds must be after start_seconds")
+	}
+	if timestamp < a.StartSeconds || timestamp > a.EndSeconds {
+		return errors.New("annotation range must contain the review timestamp")
+	}
+	if len(a.Shapes) == 0 || len(a.Shapes) > maxAnnotationShapes {
+		return fmt.Errorf("annotation must have between 1 and %d shapes", maxAnnotationShapes)
+	}
+
+	total := 0
+	for i := range a.Shapes {
+		s := &a.Shapes[i]
+		total += len(s.Points)
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("shape %d: %w", i, err)
+		}
+	}
+	if total > maxAnnotationPoints {
+		return fmt.Errorf("annotation has more than %d points", maxAnnotationPoints)
+	}
+	return nil
+}
+
+func (s *Shape) validate() error {
+	switch s.Type {
+	case ShapePath:
+		if len(s.Points) < 2 || len(s.Points) > maxPathPoints {
+			return fmt.Errorf("path must have between 2 and %d points", maxPathPoints)
+		}
+	case ShapeArrow, ShapeCircle, ShapeAngle:
+		if len(s.Points) != fixedShapePoints[s.Type] {
+			return fmt.Errorf("%s must have exactly %d points", s.Type, fixedShapePoints[s.Type])
+		}
+	default:
+		return fmt.Errorf("unknown shape type %q", s.Type)
+	}
+	for _, p := range s.Points {
+		if !inFrame(p[0]) || !inFrame(p[1]) {
+			return errors.New("points must be normalized to [0, 1]")
+		}
+	}
+
+	if s.Type == ShapeCircle {
+		if s.Radius <= 0 || s.Radius > 1 {
+			return errors.New("circle radius must be in (0, 1]")
+		}
+	} else if s.Radius != 0 {
+		return errors.New("radius only applies to circles")
+	}
+	if s.Color != "" && !colorPattern.MatchString(s.Color) {
+		return errors.New("color must be #rrggbb")
+	}
+	if s.StrokeWidth < 0 || s.StrokeWidth > maxStrokeWidth {
+		return fmt.Errorf("stroke_width must be in [0, %g]", maxStrokeWidth)
+	}
+
+	s.Degrees = nil
+	if s.Type == ShapeAngle {
+		d := angleDegrees(s.Points[0], s.Points[1], s.Points[2])
+		s.Degrees = &d
+	}
+	return nil
+}
+
+// angleDegrees returns the angle at vertex between the arms to a and b,
+// rounded to a tenth of a degree. Coordinates are normalized, so the value
+// assumes square pixels scaled to the frame; clients with the real aspect
+// ratio may refine it for display.
+func angleDegrees(a, vertex, b Point) float64 {
+	a1 := math.Atan2(a[1]-vertex[1], a[0]-vertex[0])
+	a2 := math.Atan2(b[1]-vertex[1], b[0]-vertex[0])
+	d := math.Abs(a1-a2) * 180 / math.Pi
+	if d > 180 {
+		d = 360 - d
+	}
+	return math.Round(d*10) / 10
+}
+
+func inFrame(v float64) bool { return v >= 0 && v <= 1 }
+
+// hasAnnotation reports whether a request field carries a layer rather than
+// being absent or null.
+func hasAnnotation(raw json.RawMessage) bool {
+	return len(raw) > 0 && !bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+func annotationJSON(stored []byte) json.RawMessage {
+	if len(stored) == 0 {
+		return nil
+	}
+	return json.RawMessage(stored)
+}
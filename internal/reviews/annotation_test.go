@@ -0,0 +1,79 @@
+package reviews
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseAnnotation_Valid(t *testing.T) {
+	ts := int32(12)
+	raw := `{"start_seconds":10,"end_seconds":14.5,"shapes":[
+		{"type":"path","points":[[0.1,0.1],[0.2,0.25],[0.3,0.2]],"color":"#ff0000","stroke_width":0.01},
+		{"type":"arrow","points":[[0.5,0.5],[0.6,0.4]]},
+		{"type":"circle","points":[[0.5,0.5]],"radius":0.1},
+		{"type":"angle","points":[[1,0.5],[0.5,0.5],[0.5,0]],"degrees":12}
+	]}`
+	a, err := parseAnnotation(json.RawMessage(raw), &ts)
+	if err != nil {
+		t.Fatalf("parseAnnotation: %v", err)
+	}
+	if len(a.Shapes) != 4 {
+		t.Fatalf("shapes = %d, want 4", len(a.Shapes))
+	}
+	// The client's degrees are replaced by the server's measurement.
+	if d := a.Shapes[3].Degrees; d == nil || *d != 90 {
+		t.Errorf("angle degrees = %v, want 90", d)
+	}
+	if a.Shapes[0].Degrees != nil {
+		t.Error("path carries degrees")
+	}
+}
+
+func TestParseAnnotation_Rejects(t *testing.T) {
+	ts := int32(5)
+	cases := map[string]string{
+		"unknown field":      `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]]}],"extra":1}`,
+		"empty range":        `{"start_seconds":5,"end_seconds":5,"shapes":[{"type":"arrow","points":[[0,0],[1,1]]}]}`,
+		"negative start":     `{"start_seconds":-1,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]]}]}`,
+		"excludes timestamp": `{"start_seconds":6,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]]}]}`,
+		"no shapes":          `{"start_seconds":0,"end_seconds":10,"shapes":[]}`,
+		"unknown type":       `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"text","points":[[0,0]]}]}`,
+		"outside frame":      `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1.2,1]]}]}`,
+		"arrow point count":  `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0]]}]}`,
+		"single point path":  `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"path","points":[[0,0]]}]}`,
+		"circle no radius":   `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"circle","points":[[0.5,0.5]]}]}`,
+		"radius on arrow":    `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]],"radius":0.2}]}`,
+		"bad color":          `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]],"color":"red"}]}`,
+		"stroke too wide":    `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]],"stroke_width":0.5}]}`,
+		"not an object":      `[1,2,3]`,
+	}
+	for name, raw := range cases {
+		if _, err := parseAnnotation(json.RawMessage(raw), &ts); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	valid := `{"start_seconds":0,"end_seconds":10,"shapes":[{"type":"arrow","points":[[0,0],[1,1]]}]}`
+	if _, err := parseAnnotation(json.RawMessage(valid), nil); err == nil {
+		t.Error("accepted an annotation without a review timestamp")
+	}
+}
+
+func TestAngleDegrees(t *testing.T) {
+	cases := []struct {
+		a, v, b Point
+		want    float64
+	}{
+		{Point{1, 0}, Point{0, 0}, Point{0, 1}, 90},
+		{Point{1, 0}, Point{0, 0}, Point{1, 1}, 45},
+		{Point{1, 0.5}, Point{0.5, 0.5}, Point{0, 0.5}, 180},
+		// Arms either side of the negative x axis: the raw difference exceeds
+		// 180 and wraps to the inner angle.
+		{Point{0, 0.45}, Point{0.5, 0.5}, Point{0, 0.55}, 11.4},
+	}
+	for _, c := range cases {
+		if got := angleDegrees(c.a, c.v, c.b); got != c.want {
+			t.Errorf("angleDegrees(%v, %v, %v) = %v, want %v", c.a, c.v, c.b, got, c.want)
+		}
+	}
+}
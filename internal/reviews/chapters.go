@@ -0,0 +1,325 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/go-chi/chi/v5"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxChapterTitleLength = 120
+
+// ChapterRequest creates or replaces a chapter. StartSeconds is required;
+// leave EndSeconds out for a chapter that runs until the next one.
+type ChapterRequest struct {
+	Title        string `json:"title"`
+	StartSeconds *int32 `json:"start_seconds"`
+	EndSeconds   *int32 `json:"end_seconds,omitempty"`
+}
+
+func (req *ChapterRequest) validate() error {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return errors.New("Title is required")
+	}
+	if utf8.RuneCountInString(req.Title) > maxChapterTitleLength {
+		return errors.New("Title is too long")
+	}
+	if req.StartSeconds == nil {
+		return errors.New("start_seconds is required")
+	}
+	if err := checkRange(*req.StartSeconds, req.EndSeconds); err != nil {
+		return errors.New("Invalid range: " + err.Error())
+	}
+	return nil
+}
+
+func (h *Handler) ListChapters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	userInfo := auth.GetUser(ctx)
+	if userInfo == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !permissions.HasPermission(userInfo.Permissions, permissions.ReviewsRead) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	var videoID pgtype.UUID
+	if err := videoID.Scan(idStr); err != nil {
+		http.Error(w, "Invalid video ID", http.StatusBadRequest)
+		return
+	}
+
+	if !h.ensureVideoVisible(w, r, log, userInfo, videoID, idStr) {
+		return
+	}
+
+	chapters, err := h.q.ListVideoChapters(ctx, videoID)
+	if err != nil {
+		log.ErrorContext(ctx, "list_chapters_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", idStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list chapters", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]ChapterResponse, len(chapters))
+	for i, c := range chapters {
+		response[i] = chapterResponse(c)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) CreateChapter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	userInfo, videoID, idStr, ok := h.chapterWriteTarget(w, r, log)
+	if !ok {
+		return
+	}
+
+	var req ChapterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.ensureRangeFitsVideo(w, r, log, videoID, idStr, *req.StartSeconds, req.EndSeconds) {
+		return
+	}
+
+	var chapter db.VideoChapter
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		chapter, err = tx.CreateVideoChapter(ctx, db.CreateVideoChapterParams{
+			VideoID:      videoID,
+			Title:        req.Title,
+			StartSeconds: *req.StartSeconds,
+			EndSeconds:   optionalInt4(req.EndSeconds),
+			CreatedBy:    userInfo.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordChapter(ctx, tx, audit.ActionChapterCreated, chapter, nil, audit.ChapterSnapshotOf(chapter))
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "create_chapter_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", idStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create chapter", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(chapterResponse(chapter))
+}
+
+func (h *Handler) UpdateChapter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	_, videoID, idStr, ok := h.chapterWriteTarget(w, r, log)
+	if !ok {
+		return
+	}
+	existing, ok := h.loadChapter(w, r, log, videoID)
+	if !ok {
+		return
+	}
+
+	var req ChapterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.ensureRangeFitsVideo(w, r, log, videoID, idStr, *req.StartSeconds, req.EndSeconds) {
+		return
+	}
+
+	var chapter db.VideoChapter
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		chapter, err = tx.UpdateVideoChapter(ctx, db.UpdateVideoChapterParams{
+			ID:           existing.ID,
+			VideoID:      videoID,
+			Title:        req.Title,
+			StartSeconds: *req.StartSeconds,
+			EndSeconds:   optionalInt4(req.EndSeconds),
+		})
+		if err != nil {
+			return err
+		}
+		return recordChapter(ctx, tx, audit.ActionChapterUpdated, chapter, audit.ChapterSnapshotOf(existing), audit.ChapterSnapshotOf(chapter))
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Chapter not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "update_chapter_failed",
+			slog.String("component", "reviews"),
+			slog.String("chapter_id", pgutil.UUIDToString(existing.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to update chapter", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chapterResponse(chapter))
+}
+
+func (h *Handler) DeleteChapter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	_, videoID, _, ok := h.chapterWriteTarget(w, r, log)
+	if !ok {
+		return
+	}
+	existing, ok := h.loadChapter(w, r, log, videoID)
+	if !ok {
+		return
+	}
+
+	err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+		n, err := tx.DeleteVideoChapter(ctx, db.DeleteVideoChapterParams{ID: existing.ID, VideoID: videoID})
+		if err != nil || n == 0 {
+			// Already gone: nothing to audit.
+			return err
+		}
+		return recordChapter(ctx, tx, audit.ActionChapterDeleted, existing, audit.ChapterSnapshotOf(existing), nil)
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "delete_chapter_failed",
+			slog.String("component", "reviews"),
+			slog.String("chapter_id", pgutil.UUIDToString(existing.ID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete chapter", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// chapterWriteTarget authorizes a chapter change on the video in the URL.
+// Anyone who may post top-level reviews may segment the video, as long as
+// the asset is not completed yet.
+func (h *Handler) chapterWriteTarget(w http.ResponseWriter, r *http.Request, log *slog.Logger) (*auth.UserContext, pgtype.UUID, string, bool) {
+	ctx := r.Context()
+
+	userInfo := auth.GetUser(ctx)
+	if userInfo == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, pgtype.UUID{}, "", false
+	}
+
+	if !permissions.HasPermission(userInfo.Permissions, permissions.ReviewsCreate) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return nil, pgtype.UUID{}, "", false
+	}
+
+	idStr := chi.URLParam(r, "id")
+	var videoID pgtype.UUID
+	if err := videoID.Scan(idStr); err != nil {
+		http.Error(w, "Invalid video ID", http.StatusBadRequest)
+		return nil, pgtype.UUID{}, "", false
+	}
+
+	if !h.ensureVideoVisible(w, r, log, userInfo, videoID, idStr) {
+		return nil, pgtype.UUID{}, "", false
+	}
+
+	assetStatus, err := h.q.GetAssetStatusByVideoID(ctx, videoID)
+	if err != nil {
+		log.ErrorContext(ctx, "get_asset_status_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", idStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to check video status", http.StatusInternalServerError)
+		return nil, pgtype.UUID{}, "", false
+	}
+	if assetStatus == db.AssetStatusCompleted {
+		http.Error(w, "Cannot change chapters on a completed video", http.StatusForbidden)
+		return nil, pgtype.UUID{}, "", false
+	}
+	return userInfo, videoID, idStr, true
+}
+
+func (h *Handler) loadChapter(w http.ResponseWriter, r *http.Request, log *slog.Logger, videoID pgtype.UUID) (db.VideoChapter, bool) {
+	ctx := r.Context()
+
+	chapterIDStr := chi.URLParam(r, "chapterId")
+	var chapterID pgtype.UUID
+	if err := chapterID.Scan(chapterIDStr); err != nil {
+		http.Error(w, "Invalid chapter ID", http.StatusBadRequest)
+		return db.VideoChapter{}, false
+	}
+
+	chapter, err := h.q.GetVideoChapter(ctx, db.GetVideoChapterParams{ID: chapterID, VideoID: videoID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Chapter not found", http.StatusNotFound)
+		return db.VideoChapter{}, false
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "get_chapter_failed",
+			slog.String("component", "reviews"),
+			slog.String("chapter_id", chapterIDStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch chapter", http.StatusInternalServerError)
+		return db.VideoChapter{}, false
+	}
+	return chapter, true
+}
+
+func recordChapter(ctx context.Context, tx audit.Tx, action string, chapter db.VideoChapter, oldValues, newValues any) error {
+	asset, err := tx.GetAssetOwnerByVideoID(ctx, chapter.VideoID)
+	if err != nil {
+		return err
+	}
+	return tx.Record(ctx, audit.Event{
+		Action:       action,
+		ResourceType: audit.ResourceChapter,
+		ResourceID:   pgutil.UUIDToString(chapter.ID),
+		GroupID:      pgutil.UUIDToString(asset.GroupID),
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	})
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package reviews_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_ChaptersAndReviewRanges(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Match", GroupID: group.ID, OwnerID: "student-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	video, err := q.CreateVideo(ctx, db.CreateVideoParams{AssetID: asset.ID, Status: db.VideoStatusReady})
+	if err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+
+	for _, c := range []db.CreateVideoChapterParams{
+		{VideoID: video.ID, Title: "Rally", StartSeconds: 60, CreatedBy: "expert-1"},
+		{VideoID: video.ID, Title: "Serves", StartSeconds: 0, EndSeconds: pgtype.Int4{Int32: 60, Valid: true}, CreatedBy: "expert-1"},
+	} {
+		if _, err := q.CreateVideoChapter(ctx, c); err != nil {
+			t.Fatalf("CreateVideoChapter: %v", err)
+		}
+	}
+	if _, err := q.CreateVideoChapter(ctx, db.CreateVideoChapterParams{
+		VideoID: video.ID, Title: "Backwards", StartSeconds: 10, EndSeconds: pgtype.Int4{Int32: 5, Valid: true}, CreatedBy: "expert-1",
+	}); err == nil {
+		t.Error("created a chapter ending before it starts")
+	}
+
+	chapters, err := q.ListVideoChapters(ctx, video.ID)
+	if err != nil || len(chapters) != 2 || chapters[0].Title != "Serves" {
+		t.Fatalf("ListVideoChapters = %+v, %v", chapters, err)
+	}
+	if n, err := q.DeleteVideoChapter(ctx, db.DeleteVideoChapterParams{ID: chapters[1].ID, VideoID: asset.ID}); err != nil || n != 0 {
+		t.Errorf("deleted a chapter through the wrong video: %d, %v", n, err)
+	}
+
+	review, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{
+		VideoID:          video.ID,
+		Content:          "Whole rep",
+		TimestampSeconds: pgtype.Int4{Int32: 42, Valid: true},
+		EndSeconds:       pgtype.Int4{Int32: 55, Valid: true},
+	})
+	if err != nil || review.EndSeconds.Int32 != 55 {
+		t.Fatalf("CreateVideoReview = %+v, %v", review, err)
+	}
+	if _, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{
+		VideoID:    video.ID,
+		Content:    "Range without a start",
+		EndSeconds: pgtype.Int4{Int32: 55, Valid: true},
+	}); err == nil {
+		t.Error("created a range review without timestamp_seconds")
+	}
+}
@@ -0,0 +1,210 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	llmmocks "github.com/OZIOisgood/zeta/internal/llm/mocks"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const chapterTestVideoID = "01020304-0506-0708-090a-0b0c0d0e0f10"
+
+func chapterRequest(method, body string, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	req := httptest.NewRequest(method, "/videos/"+chapterTestVideoID+"/chapters", strings.NewReader(body))
+	return req.WithContext(testUserCtx(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), reviewUser()))
+}
+
+func TestChapterRequest_Validate(t *testing.T) {
+	start, end, before := int32(42), int32(55), int32(41)
+	long := strings.Repeat("x", maxChapterTitleLength+1)
+	cases := map[string]struct {
+		req  ChapterRequest
+		want bool
+	}{
+		"valid":         {ChapterRequest{Title: " Warm-up ", StartSeconds: &start, EndSeconds: &end}, true},
+		"open":          {ChapterRequest{Title: "Drills", StartSeconds: &start}, true},
+		"blank title":   {ChapterRequest{Title: "  ", StartSeconds: &start}, false},
+		"long title":    {ChapterRequest{Title: long, StartSeconds: &start}, false},
+		"missing start": {ChapterRequest{Title: "Drills"}, false},
+		"end first":     {ChapterRequest{Title: "Drills", StartSeconds: &start, EndSeconds: &before}, false},
+	}
+	for name, c := range cases {
+		err := c.req.validate()
+		if (err == nil) != c.want {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
+
+func TestCreateChapter_RecordsAuditEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	expectVideoVisible(q, videoID, reviewUser())
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetVideoDuration(gomock.Any(), videoID).Return(pgtype.Float8{Float64: 54.2, Valid: true}, nil)
+	q.EXPECT().CreateVideoChapter(gomock.Any(), db.CreateVideoChapterParams{
+		VideoID:      videoID,
+		Title:        "Serve rep",
+		StartSeconds: 42,
+		EndSeconds:   pgtype.Int4{Int32: 55, Valid: true},
+		CreatedBy:    "user-1",
+	}).Return(db.VideoChapter{
+		ID:           testUUID(),
+		VideoID:      videoID,
+		Title:        "Serve rep",
+		StartSeconds: 42,
+		EndSeconds:   pgtype.Int4{Int32: 55, Valid: true},
+		CreatedBy:    "user-1",
+	}, nil)
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), videoID).Return(db.GetAssetOwnerByVideoIDRow{}, nil)
+
+	rec := httptest.NewRecorder()
+	h.CreateChapter(rec, chapterRequest(http.MethodPost, `{"title":" Serve rep ","start_seconds":42,"end_seconds":55}`,
+		map[string]string{"id": chapterTestVideoID}))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp ChapterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Title != "Serve rep" || resp.EndSeconds == nil || *resp.EndSeconds != 55 {
+		t.Errorf("response = %+v", resp)
+	}
+	if got := runner.Actions(); len(got) != 1 || got[0] != audit.ActionChapterCreated {
+		t.Errorf("audit actions = %v", got)
+	}
+}
+
+func TestCreateChapter_PastEndOfVideo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	expectVideoVisible(q, videoID, reviewUser())
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetVideoDuration(gomock.Any(), videoID).Return(pgtype.Float8{Float64: 50, Valid: true}, nil)
+
+	rec := httptest.NewRecorder()
+	h.CreateChapter(rec, chapterRequest(http.MethodPost, `{"title":"Cool-down","start_seconds":42,"end_seconds":55}`,
+		map[string]string{"id": chapterTestVideoID}))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateChapter_DurationNotKnownYet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	expectVideoVisible(q, videoID, reviewUser())
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetVideoDuration(gomock.Any(), videoID).Return(pgtype.Float8{}, nil)
+
+	rec := httptest.NewRecorder()
+	h.CreateChapter(rec, chapterRequest(http.MethodPost, `{"title":"Warm-up","start_seconds":0,"end_seconds":30}`,
+		map[string]string{"id": chapterTestVideoID}))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got %d, want 409; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateChapter_RequiresReviewsCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	req := chapterRequest(http.MethodPost, `{"title":"x","start_seconds":1}`, map[string]string{"id": chapterTestVideoID})
+	student := reviewUser()
+	student.Role = permissions.RoleStudent
+	student.Permissions = []string{permissions.ReviewsRead, permissions.ReviewsReply}
+	req = req.WithContext(testUserCtx(req.Context(), student))
+	rec := httptest.NewRecorder()
+	h.CreateChapter(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got %d, want 403", rec.Code)
+	}
+}
+
+func TestDeleteChapter_CompletedAsset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	expectVideoVisible(q, videoID, reviewUser())
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusCompleted, nil)
+
+	rec := httptest.NewRecorder()
+	h.DeleteChapter(rec, chapterRequest(http.MethodDelete, "",
+		map[string]string{"id": chapterTestVideoID, "chapterId": chapterTestVideoID}))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got %d, want 403", rec.Code)
+	}
+}
+
+func TestListReviews_IncludeChaptersReturnsTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	start := int32(42)
+	row := makeListRow(testUUID(), videoID, "Whole rep", &start, nil, "Review", "User")
+	row.AuthorID = pgtype.Text{String: "user-1", Valid: true}
+	row.EndSeconds = pgtype.Int4{Int32: 55, Valid: true}
+	expectVideoVisible(q, videoID, reviewUser())
+	q.EXPECT().ListVideoReviews(gomock.Any(), videoID).Return([]db.ListVideoReviewsRow{row}, nil)
+	q.EXPECT().ListVideoReviewReactions(gomock.Any(), gomock.Any()).Return(nil, nil)
+	q.EXPECT().ListVideoChapters(gomock.Any(), videoID).Return([]db.VideoChapter{
+		{ID: testUUID(), VideoID: videoID, Title: "Serves", StartSeconds: 0},
+	}, nil)
+
+	req := chapterRequest(http.MethodGet, "", map[string]string{"id": chapterTestVideoID})
+	req.URL.RawQuery = "include=chapters"
+	rec := httptest.NewRecorder()
+	h.ListReviews(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp TimelineResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Reviews) != 1 || resp.Reviews[0].EndSeconds == nil || *resp.Reviews[0].EndSeconds != 55 {
+		t.Errorf("reviews = %+v", resp.Reviews)
+	}
+	if len(resp.Chapters) != 1 || resp.Chapters[0].Title != "Serves" || resp.Chapters[0].EndSeconds != nil {
+		t.Errorf("chapters = %+v", resp.Chapters)
+	}
+}
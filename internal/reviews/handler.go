@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/llm"
@@ -23,13 +24,15 @@ import (
 
 type Handler struct {
 	q          db.Querier
+	tx         audit.Runner
 	logger     *slog.Logger
 	llmService llm.Enhancer
 }
 
-func NewHandler(q db.Querier, logger *slog.Logger, llmService llm.Enhancer) *Handler {
+func NewHandler(q db.Querier, tx audit.Runner, logger *slog.Logger, llmService llm.Enhancer) *Handler {
 	return &Handler{
 		q:          q,
+		tx:         tx,
 		logger:     logger,
 		llmService: llmService,
 	}
@@ -40,6 +43,13 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Post("/{id}/reviews", h.CreateReview)
 	r.Put("/{id}/reviews/{reviewId}", h.UpdateReview)
 	r.Delete("/{id}/reviews/{reviewId}", h.DeleteReview)
+	r.Put("/{id}/reviews/{reviewId}/state", h.SetThreadState)
+	r.Put("/{id}/reviews/{reviewId}/reactions/{reaction}", h.AddReaction)
+	r.Delete("/{id}/reviews/{reviewId}/reactions/{reaction}", h.RemoveReaction)
+	r.Get("/{id}/chapters", h.ListChapters)
+	r.Post("/{id}/chapters", h.CreateChapter)
+	r.Put("/{id}/chapters/{chapterId}", h.UpdateChapter)
+	r.Delete("/{id}/chapters/{chapterId}", h.DeleteChapter)
 }
 
 type ReviewAuthor struct {
@@ -49,22 +59,41 @@ type ReviewAuthor struct {
 }
 
 type ReviewResponse struct {
-	ID               string        `json:"id"`
-	Content          string        `json:"content"`
-	TimestampSeconds *int32        `json:"timestamp_seconds,omitempty"`
-	ParentID         *string       `json:"parent_id,omitempty"`
-	Author           *ReviewAuthor `json:"author,omitempty"`
-	CreatedAt        time.Time     `json:"created_at"`
+	ID                string          `json:"id"`
+	Content           string          `json:"content"`
+	TimestampSeconds  *int32          `json:"timestamp_seconds,omitempty"`
+	EndSeconds        *int32          `json:"end_seconds,omitempty"`
+	ParentID          *string         `json:"parent_id,omitempty"`
+	Annotation        json.RawMessage `json:"annotation,omitempty"`
+	AnnotationVersion int32           `json:"annotation_version"`
+	// ThreadState is only set on top-level reviews.
+	ThreadState string            `json:"thread_state,omitempty"`
+	Reactions   []ReactionSummary `json:"reactions,omitempty"`
+	Author      *ReviewAuthor     `json:"author,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
 }
 
+// CreateReviewRequest creates a top-level review or a reply. A review with
+// EndSeconds covers the range from TimestampSeconds to EndSeconds. SnippetID
+// records the library snippet the comment came from; Content may be left
+// empty to use the snippet's text as is.
 type CreateReviewRequest struct {
-	Content          string  `json:"content"`
-	TimestampSeconds *int32  `json:"timestamp_seconds,omitempty"`
-	ParentID         *string `json:"parent_id,omitempty"`
+	Content          string          `json:"content"`
+	TimestampSeconds *int32          `json:"timestamp_seconds,omitempty"`
+	EndSeconds       *int32          `json:"end_seconds,omitempty"`
+	ParentID         *string         `json:"parent_id,omitempty"`
+	Annotation       json.RawMessage `json:"annotation,omitempty"`
+	SnippetID        *string         `json:"snippet_id,omitempty"`
 }
 
+// UpdateReviewRequest replaces the content. The annotation is only touched
+// when the field is present: an object replaces it, null removes it. Passing
+// the annotation_version the client last saw turns a concurrent edit into a
+// 409 instead of a silent overwrite.
 type UpdateReviewRequest struct {
-	Content string `json:"content"`
+	Content           string          `json:"content"`
+	Annotation        json.RawMessage `json:"annotation,omitempty"`
+	AnnotationVersion *int32          `json:"annotation_version,omitempty"`
 }
 
 func (h *Handler) ListReviews(w http.ResponseWriter, r *http.Request) {
@@ -89,6 +118,12 @@ func (h *Handler) ListReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	include := r.URL.Query().Get("include")
+	if include != "" && include != "chapters" {
+		http.Error(w, "Invalid include", http.StatusBadRequest)
+		return
+	}
+
 	if !h.ensureVideoVisible(w, r, log, userInfo, videoID, idStr) {
 		return
 	}
@@ -104,6 +139,21 @@ func (h *Handler) ListReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	reactionRows, err := h.q.ListVideoReviewReactions(ctx, db.ListVideoReviewReactionsParams{
+		UserID:  userInfo.ID,
+		VideoID: videoID,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_reactions_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", idStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list reviews", http.StatusInternalServerError)
+		return
+	}
+	reactions := reactionSummaries(reactionRows)
+
 	response := make([]ReviewResponse, len(reviews))
 	for i, row := range reviews {
 		var createdAt time.Time
@@ -111,15 +161,20 @@ func (h *Handler) ListReviews(w http.ResponseWriter, r *http.Request) {
 			createdAt = row.CreatedAt.Time
 		}
 
-		var tsSeconds *int32
+		var tsSeconds, endSeconds *int32
 		if row.TimestampSeconds.Valid {
 			tsSeconds = &row.TimestampSeconds.Int32
 		}
+		if row.EndSeconds.Valid {
+			endSeconds = &row.EndSeconds.Int32
+		}
 
 		var parentID *string
+		threadState := string(row.ThreadState)
 		if row.ParentID.Valid {
 			s := pgutil.UUIDToString(row.ParentID)
 			parentID = &s
+			threadState = ""
 		}
 
 		var author *ReviewAuthor
@@ -145,17 +200,42 @@ func (h *Handler) ListReviews(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		response[i] = ReviewResponse{
-			ID:               pgutil.UUIDToString(row.ID),
-			Content:          row.Content,
-			TimestampSeconds: tsSeconds,
-			ParentID:         parentID,
-			Author:           author,
-			CreatedAt:        createdAt,
+			ID:                pgutil.UUIDToString(row.ID),
+			Content:           row.Content,
+			TimestampSeconds:  tsSeconds,
+			EndSeconds:        endSeconds,
+			ParentID:          parentID,
+			Annotation:        annotationJSON(row.Annotation),
+			AnnotationVersion: row.AnnotationVersion,
+			ThreadState:       threadState,
+			Reactions:         reactions[row.ID],
+			Author:            author,
+			CreatedAt:         createdAt,
 		}
 	}
 
+	if include != "chapters" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	chapters, err := h.q.ListVideoChapters(ctx, videoID)
+	if err != nil {
+		log.ErrorContext(ctx, "list_chapters_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", idStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list chapters", http.StatusInternalServerError)
+		return
+	}
+	timeline := TimelineResponse{Reviews: response, Chapters: make([]ChapterResponse, len(chapters))}
+	for i, c := range chapters {
+		timeline.Chapters[i] = chapterResponse(c)
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(timeline)
 }
 
 func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
@@ -191,6 +271,31 @@ func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var snippetID pgtype.UUID
+	if req.SnippetID != nil {
+		if err := snippetID.Scan(*req.SnippetID); err != nil {
+			http.Error(w, "Invalid snippet_id", http.StatusBadRequest)
+			return
+		}
+		snippet, err := h.q.GetVisibleSnippet(ctx, db.GetVisibleSnippetParams{ID: snippetID, UserID: userInfo.ID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Snippet not found", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.ErrorContext(ctx, "get_snippet_failed",
+				slog.String("component", "reviews"),
+				slog.String("snippet_id", *req.SnippetID),
+				slog.Any("err", err),
+			)
+			http.Error(w, "Failed to load snippet", http.StatusInternalServerError)
+			return
+		}
+		if req.Content == "" {
+			req.Content = snippet.Content
+		}
+	}
+
 	if req.Content == "" {
 		http.Error(w, "Content is required", http.StatusBadRequest)
 		return
@@ -241,6 +346,34 @@ func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
 		}
 		// Replies never carry a video timestamp.
 		req.TimestampSeconds = nil
+		req.EndSeconds = nil
+	}
+
+	if req.EndSeconds != nil {
+		if req.TimestampSeconds == nil {
+			http.Error(w, "end_seconds requires timestamp_seconds", http.StatusBadRequest)
+			return
+		}
+		if err := checkRange(*req.TimestampSeconds, req.EndSeconds); err != nil {
+			http.Error(w, "Invalid range: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !h.ensureRangeFitsVideo(w, r, log, videoID, idStr, *req.TimestampSeconds, req.EndSeconds) {
+			return
+		}
+	}
+
+	var annotation []byte
+	if hasAnnotation(req.Annotation) {
+		parsed, err := parseAnnotation(req.Annotation, req.TimestampSeconds)
+		if err != nil {
+			http.Error(w, "Invalid annotation: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if annotation, err = json.Marshal(parsed); err != nil {
+			http.Error(w, "Invalid annotation", http.StatusBadRequest)
+			return
+		}
 	}
 
 	assetStatus, err := h.q.GetAssetStatusByVideoID(ctx, videoID)
@@ -290,12 +423,28 @@ func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
 		timestampSeconds = pgtype.Int4{Int32: *req.TimestampSeconds, Valid: true}
 	}
 
-	review, err := h.q.CreateVideoReview(ctx, db.CreateVideoReviewParams{
-		VideoID:          videoID,
-		Content:          req.Content,
-		TimestampSeconds: timestampSeconds,
-		ParentID:         parentID,
-		AuthorID:         authorID,
+	var review db.VideoReview
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		review, err = tx.CreateVideoReview(ctx, db.CreateVideoReviewParams{
+			VideoID:          videoID,
+			Content:          req.Content,
+			TimestampSeconds: timestampSeconds,
+			ParentID:         parentID,
+			AuthorID:         authorID,
+			Annotation:       annotation,
+			EndSeconds:       optionalInt4(req.EndSeconds),
+			SnippetID:        snippetID,
+		})
+		if err != nil {
+			return err
+		}
+		if snippetID.Valid {
+			if err := tx.RecordSnippetUse(ctx, snippetID); err != nil {
+				return err
+			}
+		}
+		return recordReview(ctx, tx, audit.ActionReviewCreated, review, nil, audit.ReviewSnapshotOf(review))
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "create_review_failed",
@@ -323,9 +472,18 @@ func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
 	if review.TimestampSeconds.Valid {
 		responseData["timestamp_seconds"] = review.TimestampSeconds.Int32
 	}
+	if review.EndSeconds.Valid {
+		responseData["end_seconds"] = review.EndSeconds.Int32
+	}
 	if review.ParentID.Valid {
 		responseData["parent_id"] = pgutil.UUIDToString(review.ParentID)
+	} else {
+		responseData["thread_state"] = review.ThreadState
 	}
+	if len(review.Annotation) > 0 {
+		responseData["annotation"] = json.RawMessage(review.Annotation)
+	}
+	responseData["annotation_version"] = review.AnnotationVersion
 	if authorName != "" {
 		author := map[string]interface{}{"id": userInfo.ID, "name": authorName}
 		if authorPrefs.Avatar != "" {
@@ -453,11 +611,46 @@ func (h *Handler) UpdateReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	review, err := h.q.UpdateVideoReview(ctx, db.UpdateVideoReviewParams{
+	params := db.UpdateVideoReviewParams{
 		ID:      reviewID,
 		Content: req.Content,
 		VideoID: videoID,
+	}
+	if len(req.Annotation) > 0 {
+		params.SetAnnotation = true
+		if hasAnnotation(req.Annotation) {
+			var tsSeconds *int32
+			if existing.TimestampSeconds.Valid {
+				tsSeconds = &existing.TimestampSeconds.Int32
+			}
+			parsed, err := parseAnnotation(req.Annotation, tsSeconds)
+			if err != nil {
+				http.Error(w, "Invalid annotation: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if params.Annotation, err = json.Marshal(parsed); err != nil {
+				http.Error(w, "Invalid annotation", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if req.AnnotationVersion != nil {
+		params.ExpectedAnnotationVersion = pgtype.Int4{Int32: *req.AnnotationVersion, Valid: true}
+	}
+
+	var review db.VideoReview
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		var err error
+		review, err = tx.UpdateVideoReview(ctx, params)
+		if err != nil {
+			return err
+		}
+		return recordReview(ctx, tx, audit.ActionReviewUpdated, review, audit.ReviewSnapshotOf(existing), audit.ReviewSnapshotOf(review))
 	})
+	if errors.Is(err, pgx.ErrNoRows) && params.ExpectedAnnotationVersion.Valid {
+		http.Error(w, "Annotation was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		log.ErrorContext(ctx, "update_review_failed",
 			slog.String("component", "reviews"),
@@ -469,12 +662,17 @@ func (h *Handler) UpdateReview(w http.ResponseWriter, r *http.Request) {
 	}
 
 	createdAt := review.CreatedAt.Time
+	responseData := map[string]interface{}{
+		"id":                 pgutil.UUIDToString(review.ID),
+		"content":            review.Content,
+		"annotation_version": review.AnnotationVersion,
+		"created_at":         createdAt,
+	}
+	if len(review.Annotation) > 0 {
+		responseData["annotation"] = json.RawMessage(review.Annotation)
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":         pgutil.UUIDToString(review.ID),
-		"content":    review.Content,
-		"created_at": createdAt,
-	})
+	json.NewEncoder(w).Encode(responseData)
 }
 
 func (h *Handler) DeleteReview(w http.ResponseWriter, r *http.Request) {
@@ -546,9 +744,14 @@ func (h *Handler) DeleteReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.q.DeleteVideoReview(ctx, db.DeleteVideoReviewParams{
-		ID:      reviewID,
-		VideoID: videoID,
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		if err := tx.DeleteVideoReview(ctx, db.DeleteVideoReviewParams{
+			ID:      reviewID,
+			VideoID: videoID,
+		}); err != nil {
+			return err
+		}
+		return recordReview(ctx, tx, audit.ActionReviewDeleted, existing, audit.ReviewSnapshotOf(existing), nil)
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "delete_review_failed",
@@ -563,6 +766,22 @@ func (h *Handler) DeleteReview(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// recordReview appends a review event scoped to the group owning the video.
+func recordReview(ctx context.Context, tx audit.Tx, action string, review db.VideoReview, oldValues, newValues any) error {
+	asset, err := tx.GetAssetOwnerByVideoID(ctx, review.VideoID)
+	if err != nil {
+		return err
+	}
+	return tx.Record(ctx, audit.Event{
+		Action:       action,
+		ResourceType: audit.ResourceReview,
+		ResourceID:   pgutil.UUIDToString(review.ID),
+		GroupID:      pgutil.UUIDToString(asset.GroupID),
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	})
+}
+
 func (h *Handler) ensureVideoVisible(w http.ResponseWriter, r *http.Request, log *slog.Logger, user *auth.UserContext, videoID pgtype.UUID, videoIDStr string) bool {
 	ctx := r.Context()
 	visible, err := h.q.CheckVideoVisibleToUser(ctx, db.CheckVideoVisibleToUserParams{
@@ -593,14 +812,40 @@ func (h *Handler) ensureVideoVisible(w http.ResponseWriter, r *http.Request, log
 	return true
 }
 
+// Modes of POST /reviews/enhance.
+const (
+	EnhanceModeRewrite  = "rewrite"
+	EnhanceModeSnippets = "snippets"
+)
+
+// maxSnippetCandidates bounds the library sent to the model; the most used
+// snippets come first.
+const maxSnippetCandidates = 100
+
+// EnhanceTextRequest asks for a polished rewrite of Text (the default mode)
+// or, with Mode "snippets", for library snippets matching the draft.
 type EnhanceTextRequest struct {
 	Text string `json:"text"`
+	Mode string `json:"mode,omitempty"`
 }
 
 type EnhanceTextResponse struct {
 	EnhancedText string `json:"enhanced_text"`
 }
 
+type SnippetSuggestion struct {
+	SnippetID string `json:"snippet_id"`
+	Title     string `json:"title"`
+	Text      string `json:"text"`
+}
+
+// SnippetSuggestionsResponse lists matching snippets, best first, with their
+// text in the draft's detected language.
+type SnippetSuggestionsResponse struct {
+	Language    string              `json:"language"`
+	Suggestions []SnippetSuggestion `json:"suggestions"`
+}
+
 func (h *Handler) EnhanceText(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := logger.From(ctx, h.logger)
@@ -627,6 +872,16 @@ func (h *Handler) EnhanceText(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	switch req.Mode {
+	case "", EnhanceModeRewrite:
+	case EnhanceModeSnippets:
+		h.suggestSnippets(w, r, log, userInfo, req.Text)
+		return
+	default:
+		http.Error(w, "Invalid mode", http.StatusBadRequest)
+		return
+	}
+
 	enhancedText, err := h.llmService.EnhanceReviewText(ctx, req.Text)
 	if err != nil {
 		log.ErrorContext(ctx, "enhance_text_failed",
@@ -651,3 +906,51 @@ func (h *Handler) EnhanceText(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *Handler) suggestSnippets(w http.ResponseWriter, r *http.Request, log *slog.Logger, userInfo *auth.UserContext, draft string) {
+	ctx := r.Context()
+
+	library, err := h.q.ListVisibleSnippets(ctx, db.ListVisibleSnippetsParams{
+		UserID:   userInfo.ID,
+		RowLimit: maxSnippetCandidates,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "list_snippets_for_suggestion_failed",
+			slog.String("component", "reviews"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to load snippets", http.StatusInternalServerError)
+		return
+	}
+
+	candidates := make([]llm.Snippet, len(library))
+	titles := make(map[string]string, len(library))
+	for i, s := range library {
+		id := pgutil.UUIDToString(s.ID)
+		candidates[i] = llm.Snippet{ID: id, Title: s.Title, Content: s.Content}
+		titles[id] = s.Title
+	}
+
+	result, err := h.llmService.SuggestSnippets(ctx, draft, candidates)
+	if err != nil {
+		log.ErrorContext(ctx, "suggest_snippets_failed",
+			slog.String("component", "reviews"),
+			slog.Int("text_length", len(draft)),
+			slog.Int("snippet_count", len(candidates)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to suggest snippets", http.StatusInternalServerError)
+		return
+	}
+
+	response := SnippetSuggestionsResponse{
+		Language:    result.Language,
+		Suggestions: make([]SnippetSuggestion, len(result.Suggestions)),
+	}
+	for i, s := range result.Suggestions {
+		response.Suggestions[i] = SnippetSuggestion{SnippetID: s.ID, Title: titles[s.ID], Text: s.Text}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -11,13 +11,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/llm"
 	llmmocks "github.com/OZIOisgood/zeta/internal/llm/mocks"
 	"github.com/OZIOisgood/zeta/internal/notifications"
 	"github.com/OZIOisgood/zeta/internal/permissions"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/mock/gomock"
 )
@@ -98,7 +102,7 @@ func TestListReviews_Unauthorized(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos/abc/reviews", nil)
 	req = withChiURLParam(req, "id", "01020304-0506-0708-090a-0b0c0d0e0f10")
@@ -115,7 +119,7 @@ func TestListReviews_Forbidden(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	user := &auth.UserContext{ID: "user-1", Permissions: []string{}}
 	req := httptest.NewRequest(http.MethodGet, "/videos/abc/reviews", nil)
@@ -134,7 +138,7 @@ func TestListReviews_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	now := time.Now()
@@ -152,6 +156,7 @@ func TestListReviews_Success(t *testing.T) {
 			CreatedAt:        pgtype.Timestamptz{Time: now, Valid: true},
 		},
 	}, nil)
+	q.EXPECT().ListVideoReviewReactions(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
 	req := httptest.NewRequest(http.MethodGet, "/videos/"+videoIDStr+"/reviews", nil)
@@ -181,7 +186,7 @@ func TestListReviews_NotVisible(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	user := reviewUser()
@@ -208,7 +213,7 @@ func TestListReviews_InvalidVideoID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	user := reviewUser()
 	req := httptest.NewRequest(http.MethodGet, "/videos/not-a-uuid/reviews", nil)
@@ -227,7 +232,8 @@ func TestCreateReview_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default(), llmMock)
 
 	videoID := testUUID()
 	reviewID := pgtype.UUID{Valid: true}
@@ -272,13 +278,20 @@ func TestCreateReview_Success(t *testing.T) {
 	if resp["content"] != "Nice technique" {
 		t.Errorf("got content %q, want %q", resp["content"], "Nice technique")
 	}
+	events := runner.Events()
+	if len(events) != 1 || events[0].Action != audit.ActionReviewCreated {
+		t.Fatalf("audit events = %v, want one %s", runner.Actions(), audit.ActionReviewCreated)
+	}
+	if events[0].OldValues != nil {
+		t.Errorf("created event carries old values: %#v", events[0].OldValues)
+	}
 }
 
 func TestCreateReview_NotifiesVideoOwner(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	assetID := pgtype.UUID{Valid: true}
@@ -344,7 +357,7 @@ func TestCreateReview_CompletedAsset(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
@@ -370,7 +383,7 @@ func TestCreateReview_Reply_CompletedAssetAllowed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	parentUUID := pgtype.UUID{Valid: true}
@@ -386,7 +399,7 @@ func TestCreateReview_Reply_CompletedAssetAllowed(t *testing.T) {
 		Permissions: []string{permissions.ReviewsReply},
 	}
 	expectVideoVisible(q, videoID, user)
-	q.EXPECT().GetVideoReview(gomock.Any(), parentUUID).Return(db.GetVideoReviewRow{
+	q.EXPECT().GetVideoReview(gomock.Any(), parentUUID).Return(db.VideoReview{
 		ID:      parentUUID,
 		VideoID: videoID,
 	}, nil)
@@ -428,7 +441,7 @@ func TestCreateReview_Reply_PendingAssetRequiresPermission(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	parentUUID := pgtype.UUID{Valid: true}
@@ -440,7 +453,7 @@ func TestCreateReview_Reply_PendingAssetRequiresPermission(t *testing.T) {
 		Permissions: []string{permissions.ReviewsReply},
 	}
 	expectVideoVisible(q, videoID, user)
-	q.EXPECT().GetVideoReview(gomock.Any(), parentUUID).Return(db.GetVideoReviewRow{
+	q.EXPECT().GetVideoReview(gomock.Any(), parentUUID).Return(db.VideoReview{
 		ID:      parentUUID,
 		VideoID: videoID,
 	}, nil)
@@ -464,7 +477,7 @@ func TestCreateReview_EmptyContent(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
@@ -489,7 +502,7 @@ func TestCreateReview_AuthorPreferencesMissing(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
@@ -516,7 +529,7 @@ func TestCreateReview_DBError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	videoID := testUUID()
 	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
@@ -547,7 +560,7 @@ func TestListReviews_ReturnsAuthorAndParentID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	user := reviewUser()
 	videoID := testUUID()
@@ -560,8 +573,15 @@ func TestListReviews_ReturnsAuthorAndParentID(t *testing.T) {
 		makeListRow(replyUUID, videoID, "Reply text", nil, &videoID, "Ben", "Smith"),
 	}
 
+	rows[0].ThreadState = db.ReviewThreadStateAcknowledged
+	rows[1].ThreadState = db.ReviewThreadStateOpen
+
 	expectVideoVisible(q, videoID, user)
 	q.EXPECT().ListVideoReviews(gomock.Any(), videoID).Return(rows, nil)
+	q.EXPECT().ListVideoReviewReactions(gomock.Any(), db.ListVideoReviewReactionsParams{UserID: user.ID, VideoID: videoID}).
+		Return([]db.ListVideoReviewReactionsRow{
+			{ReviewID: videoID, Reaction: "heart", Count: 2, Reacted: true},
+		}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	req = withChiURLParam(req, "id", "01020304-0506-0708-090a-0b0c0d0e0f10")
@@ -591,13 +611,22 @@ func TestListReviews_ReturnsAuthorAndParentID(t *testing.T) {
 	if got[1].TimestampSeconds != nil {
 		t.Error("reply should not have timestamp_seconds")
 	}
+	if got[0].ThreadState != "acknowledged" || got[1].ThreadState != "" {
+		t.Errorf("thread states = %q, %q; want acknowledged on the root only", got[0].ThreadState, got[1].ThreadState)
+	}
+	if len(got[0].Reactions) != 1 || got[0].Reactions[0] != (ReactionSummary{Reaction: "heart", Count: 2, Reacted: true}) {
+		t.Errorf("root reactions = %+v", got[0].Reactions)
+	}
+	if len(got[1].Reactions) != 0 {
+		t.Errorf("reply reactions = %+v, want none", got[1].Reactions)
+	}
 }
 
 func TestListReviews_AuthorProfileMissingIsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	user := reviewUser()
 	videoID := testUUID()
@@ -614,6 +643,7 @@ func TestListReviews_AuthorProfileMissingIsError(t *testing.T) {
 
 	expectVideoVisible(q, videoID, user)
 	q.EXPECT().ListVideoReviews(gomock.Any(), videoID).Return(rows, nil)
+	q.EXPECT().ListVideoReviewReactions(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	req = withChiURLParam(req, "id", "01020304-0506-0708-090a-0b0c0d0e0f10")
@@ -631,7 +661,7 @@ func TestCreateReview_Reply_ReRootsToTopLevel(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	user := &auth.UserContext{
 		ID:        "user-1",
@@ -656,7 +686,7 @@ func TestCreateReview_Reply_ReRootsToTopLevel(t *testing.T) {
 	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
 
 	// Parent is itself a reply (has parent_id = rootUUID)
-	q.EXPECT().GetVideoReview(gomock.Any(), replyUUID).Return(db.GetVideoReviewRow{
+	q.EXPECT().GetVideoReview(gomock.Any(), replyUUID).Return(db.VideoReview{
 		ID:       replyUUID,
 		VideoID:  videoID,
 		ParentID: rootUUID, // already a reply
@@ -697,7 +727,7 @@ func TestCreateReview_Reply_CrossVideoRejected(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	llmMock := llmmocks.NewMockEnhancer(ctrl)
-	h := NewHandler(q, slog.Default(), llmMock)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
 
 	user := &auth.UserContext{
 		ID:          "user-1",
@@ -711,7 +741,7 @@ func TestCreateReview_Reply_CrossVideoRejected(t *testing.T) {
 	copy(parentUUID.Bytes[:], []byte{2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
 
 	expectVideoVisible(q, videoID, user)
-	q.EXPECT().GetVideoReview(gomock.Any(), parentUUID).Return(db.GetVideoReviewRow{
+	q.EXPECT().GetVideoReview(gomock.Any(), parentUUID).Return(db.VideoReview{
 		ID:      parentUUID,
 		VideoID: otherVideoID, // different video!
 	}, nil)
@@ -729,3 +759,440 @@ func TestCreateReview_Reply_CrossVideoRejected(t *testing.T) {
 		t.Errorf("got %d, want 400", rec.Code)
 	}
 }
+
+func TestDeleteReview_RecordsOldSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	llmMock := llmmocks.NewMockEnhancer(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default(), llmMock)
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	reviewID := pgtype.UUID{Valid: true}
+	copy(reviewID.Bytes[:], []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	reviewIDStr := "100f0e0d-0c0b-0a09-0807-060504030201"
+	groupID := pgtype.UUID{Valid: true}
+	copy(groupID.Bytes[:], []byte{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9})
+
+	user := reviewUser()
+	user.Permissions = append(user.Permissions, permissions.ReviewsDelete)
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetVideoReview(gomock.Any(), reviewID).Return(db.VideoReview{
+		ID:       reviewID,
+		VideoID:  videoID,
+		Content:  "Keep your elbow up",
+		AuthorID: pgtype.Text{String: "user-1", Valid: true},
+	}, nil)
+	q.EXPECT().DeleteVideoReview(gomock.Any(), db.DeleteVideoReviewParams{ID: reviewID, VideoID: videoID}).Return(nil)
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), videoID).Return(db.GetAssetOwnerByVideoIDRow{GroupID: groupID}, nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", videoIDStr)
+	rctx.URLParams.Add("reviewId", reviewIDStr)
+	req := httptest.NewRequest(http.MethodDelete, "/videos/"+videoIDStr+"/reviews/"+reviewIDStr, nil)
+	req = req.WithContext(testUserCtx(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), user))
+	rec := httptest.NewRecorder()
+
+	h.DeleteReview(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d, want %d; body: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	events := runner.Events()
+	if len(events) != 1 {
+		t.Fatalf("audit events = %v, want one", runner.Actions())
+	}
+	e := events[0]
+	if e.Action != audit.ActionReviewDeleted || e.ResourceID != reviewIDStr || e.GroupID != "09090909-0909-0909-0909-090909090909" {
+		t.Errorf("event = %+v", e)
+	}
+	old, ok := e.OldValues.(audit.ReviewSnapshot)
+	if !ok || old.Content != "Keep your elbow up" || old.V != 1 {
+		t.Errorf("old values = %#v", e.OldValues)
+	}
+	if e.NewValues != nil {
+		t.Errorf("deleted event carries new values: %#v", e.NewValues)
+	}
+}
+
+func TestCreateReview_StoresValidatedAnnotation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	llmMock := llmmocks.NewMockEnhancer(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	user := reviewUser()
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "user-1").Return(db.UserPreference{FirstName: "Review", LastName: "User"}, nil)
+	q.EXPECT().CreateVideoReview(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateVideoReviewParams) (db.VideoReview, error) {
+			var stored Annotation
+			if err := json.Unmarshal(arg.Annotation, &stored); err != nil {
+				t.Fatalf("stored annotation: %v", err)
+			}
+			if len(stored.Shapes) != 1 || stored.Shapes[0].Degrees == nil || *stored.Shapes[0].Degrees != 90 {
+				t.Errorf("stored annotation = %s", arg.Annotation)
+			}
+			return db.VideoReview{
+				ID:               testUUID(),
+				VideoID:          videoID,
+				Content:          arg.Content,
+				TimestampSeconds: arg.TimestampSeconds,
+				Annotation:       arg.Annotation,
+			}, nil
+		})
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), gomock.Any()).Return(db.GetAssetOwnerByVideoIDRow{}, nil).AnyTimes()
+
+	body := `{"content":"Knee angle","timestamp_seconds":4,"annotation":{"start_seconds":3,"end_seconds":6,
+		"shapes":[{"type":"angle","points":[[1,0],[0,0],[0,1]],"degrees":45}]}}`
+	req := httptest.NewRequest(http.MethodPost, "/videos/"+videoIDStr+"/reviews", strings.NewReader(body))
+	req = withChiURLParam(req, "id", videoIDStr)
+	req = req.WithContext(testUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	h.CreateReview(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp ReviewResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Annotation) == 0 {
+		t.Error("response dropped the annotation")
+	}
+}
+
+func TestCreateReview_InvalidAnnotation(t *testing.T) {
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	user := reviewUser()
+
+	for name, body := range map[string]string{
+		"no timestamp": `{"content":"x","annotation":{"start_seconds":0,"end_seconds":5,"shapes":[{"type":"arrow","points":[[0,0],[1,1]]}]}}`,
+		"out of frame": `{"content":"x","timestamp_seconds":1,"annotation":{"start_seconds":0,"end_seconds":5,"shapes":[{"type":"arrow","points":[[0,0],[2,1]]}]}}`,
+	} {
+		ctrl := gomock.NewController(t)
+		q := dbmocks.NewMockQuerier(ctrl)
+		h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+		expectVideoVisible(q, videoID, user)
+
+		req := httptest.NewRequest(http.MethodPost, "/videos/"+videoIDStr+"/reviews", strings.NewReader(body))
+		req = withChiURLParam(req, "id", videoIDStr)
+		req = req.WithContext(testUserCtx(req.Context(), user))
+		rec := httptest.NewRecorder()
+
+		h.CreateReview(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400; body: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestUpdateReview_AnnotationVersionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	llmMock := llmmocks.NewMockEnhancer(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default(), llmMock)
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	reviewID := pgtype.UUID{Valid: true}
+	copy(reviewID.Bytes[:], []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	reviewIDStr := "100f0e0d-0c0b-0a09-0807-060504030201"
+
+	user := reviewUser()
+	user.Permissions = append(user.Permissions, permissions.ReviewsEdit)
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetVideoReview(gomock.Any(), reviewID).Return(db.VideoReview{
+		ID:                reviewID,
+		VideoID:           videoID,
+		Content:           "Elbow",
+		TimestampSeconds:  pgtype.Int4{Int32: 8, Valid: true},
+		AuthorID:          pgtype.Text{String: "user-1", Valid: true},
+		AnnotationVersion: 3,
+	}, nil)
+	q.EXPECT().UpdateVideoReview(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.UpdateVideoReviewParams) (db.VideoReview, error) {
+			if !arg.SetAnnotation || len(arg.Annotation) == 0 || arg.ExpectedAnnotationVersion.Int32 != 2 {
+				t.Errorf("update params = %+v", arg)
+			}
+			return db.VideoReview{}, pgx.ErrNoRows
+		})
+
+	body := `{"content":"Elbow","annotation_version":2,"annotation":{"start_seconds":7,"end_seconds":9,
+		"shapes":[{"type":"circle","points":[[0.4,0.4]],"radius":0.05}]}}`
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", videoIDStr)
+	rctx.URLParams.Add("reviewId", reviewIDStr)
+	req := httptest.NewRequest(http.MethodPut, "/videos/"+videoIDStr+"/reviews/"+reviewIDStr, strings.NewReader(body))
+	req = req.WithContext(testUserCtx(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), user))
+	rec := httptest.NewRecorder()
+
+	h.UpdateReview(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("got %d, want %d; body: %s", rec.Code, http.StatusConflict, rec.Body.String())
+	}
+	if len(runner.Events()) != 0 {
+		t.Errorf("conflicting update was audited: %v", runner.Actions())
+	}
+}
+
+func TestUpdateReview_NullRemovesAnnotation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	llmMock := llmmocks.NewMockEnhancer(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	reviewID := pgtype.UUID{Valid: true}
+	copy(reviewID.Bytes[:], []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	reviewIDStr := "100f0e0d-0c0b-0a09-0807-060504030201"
+
+	user := reviewUser()
+	user.Permissions = append(user.Permissions, permissions.ReviewsEdit)
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	existing := db.VideoReview{
+		ID:                reviewID,
+		VideoID:           videoID,
+		Content:           "Elbow",
+		TimestampSeconds:  pgtype.Int4{Int32: 8, Valid: true},
+		AuthorID:          pgtype.Text{String: "user-1", Valid: true},
+		Annotation:        []byte(`{"start_seconds":7,"end_seconds":9,"shapes":[]}`),
+		AnnotationVersion: 1,
+	}
+	q.EXPECT().GetVideoReview(gomock.Any(), reviewID).Return(existing, nil)
+	updated := existing
+	updated.Annotation = nil
+	updated.AnnotationVersion = 2
+	q.EXPECT().UpdateVideoReview(gomock.Any(), db.UpdateVideoReviewParams{
+		Content:       "Elbow",
+		SetAnnotation: true,
+		ID:            reviewID,
+		VideoID:       videoID,
+	}).Return(updated, nil)
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), videoID).Return(db.GetAssetOwnerByVideoIDRow{}, nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", videoIDStr)
+	rctx.URLParams.Add("reviewId", reviewIDStr)
+	req := httptest.NewRequest(http.MethodPut, "/videos/"+videoIDStr+"/reviews/"+reviewIDStr,
+		strings.NewReader(`{"content":"Elbow","annotation":null}`))
+	req = req.WithContext(testUserCtx(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), user))
+	rec := httptest.NewRecorder()
+
+	h.UpdateReview(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp ReviewResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Annotation != nil || resp.AnnotationVersion != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestCreateReview_RangeValidation(t *testing.T) {
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	user := reviewUser()
+
+	for name, tc := range map[string]struct {
+		body       string
+		durationOK bool
+	}{
+		"end without start": {body: `{"content":"x","end_seconds":10}`},
+		"end before start":  {body: `{"content":"x","timestamp_seconds":10,"end_seconds":10}`},
+		"past the end":      {body: `{"content":"x","timestamp_seconds":42,"end_seconds":62}`, durationOK: true},
+	} {
+		ctrl := gomock.NewController(t)
+		q := dbmocks.NewMockQuerier(ctrl)
+		h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+		expectVideoVisible(q, videoID, user)
+		if tc.durationOK {
+			q.EXPECT().GetVideoDuration(gomock.Any(), videoID).Return(pgtype.Float8{Float64: 60.4, Valid: true}, nil)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/videos/"+videoIDStr+"/reviews", strings.NewReader(tc.body))
+		req = withChiURLParam(req, "id", videoIDStr)
+		req = req.WithContext(testUserCtx(req.Context(), user))
+		rec := httptest.NewRecorder()
+
+		h.CreateReview(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400; body: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestCreateReview_StoresRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	user := reviewUser()
+	expectVideoVisible(q, videoID, user)
+	// A duration of 54.2s still admits a range ending at second 55.
+	q.EXPECT().GetVideoDuration(gomock.Any(), videoID).Return(pgtype.Float8{Float64: 54.2, Valid: true}, nil)
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "user-1").Return(db.UserPreference{FirstName: "Review", LastName: "User"}, nil)
+	q.EXPECT().CreateVideoReview(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateVideoReviewParams) (db.VideoReview, error) {
+			if arg.TimestampSeconds.Int32 != 42 || arg.EndSeconds != (pgtype.Int4{Int32: 55, Valid: true}) {
+				t.Errorf("range = %+v – %+v", arg.TimestampSeconds, arg.EndSeconds)
+			}
+			return db.VideoReview{ID: testUUID(), VideoID: videoID, Content: arg.Content,
+				TimestampSeconds: arg.TimestampSeconds, EndSeconds: arg.EndSeconds}, nil
+		})
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), gomock.Any()).Return(db.GetAssetOwnerByVideoIDRow{}, nil).AnyTimes()
+
+	body := `{"content":"Whole rep","timestamp_seconds":42,"end_seconds":55}`
+	req := httptest.NewRequest(http.MethodPost, "/videos/"+videoIDStr+"/reviews", strings.NewReader(body))
+	req = withChiURLParam(req, "id", videoIDStr)
+	req = req.WithContext(testUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	h.CreateReview(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp ReviewResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.EndSeconds == nil || *resp.EndSeconds != 55 {
+		t.Errorf("end_seconds = %v", resp.EndSeconds)
+	}
+}
+
+func TestCreateReview_FromSnippetRecordsUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	snippetID := pgtype.UUID{Bytes: [16]byte{7}, Valid: true}
+	user := reviewUser()
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetVisibleSnippet(gomock.Any(), db.GetVisibleSnippetParams{ID: snippetID, UserID: "user-1"}).
+		Return(db.ReviewSnippet{ID: snippetID, Content: "Keep your elbow high."}, nil)
+	q.EXPECT().GetAssetStatusByVideoID(gomock.Any(), videoID).Return(db.AssetStatusPending, nil)
+	q.EXPECT().GetUserPreferences(gomock.Any(), "user-1").Return(db.UserPreference{FirstName: "Review", LastName: "User"}, nil)
+	q.EXPECT().CreateVideoReview(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateVideoReviewParams) (db.VideoReview, error) {
+			if arg.Content != "Keep your elbow high." || arg.SnippetID != snippetID {
+				t.Errorf("create params = %+v", arg)
+			}
+			return db.VideoReview{ID: testUUID(), VideoID: videoID, Content: arg.Content, SnippetID: arg.SnippetID}, nil
+		})
+	q.EXPECT().RecordSnippetUse(gomock.Any(), snippetID).Return(nil)
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), gomock.Any()).Return(db.GetAssetOwnerByVideoIDRow{}, nil).AnyTimes()
+
+	body := `{"snippet_id":"07000000-0000-0000-0000-000000000000"}`
+	req := httptest.NewRequest(http.MethodPost, "/videos/"+videoIDStr+"/reviews", strings.NewReader(body))
+	req = withChiURLParam(req, "id", videoIDStr)
+	req = req.WithContext(testUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	h.CreateReview(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateReview_InvisibleSnippetRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	videoID := testUUID()
+	videoIDStr := "01020304-0506-0708-090a-0b0c0d0e0f10"
+	user := reviewUser()
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetVisibleSnippet(gomock.Any(), gomock.Any()).Return(db.ReviewSnippet{}, pgx.ErrNoRows)
+
+	body := `{"content":"x","snippet_id":"07000000-0000-0000-0000-000000000000"}`
+	req := httptest.NewRequest(http.MethodPost, "/videos/"+videoIDStr+"/reviews", strings.NewReader(body))
+	req = withChiURLParam(req, "id", videoIDStr)
+	req = req.WithContext(testUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	h.CreateReview(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestEnhanceText_SnippetsMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	llmMock := llmmocks.NewMockEnhancer(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmMock)
+
+	snippetID := pgtype.UUID{Bytes: [16]byte{7}, Valid: true}
+	snippetIDStr := "07000000-0000-0000-0000-000000000000"
+	q.EXPECT().ListVisibleSnippets(gomock.Any(), db.ListVisibleSnippetsParams{UserID: "user-1", RowLimit: maxSnippetCandidates}).
+		Return([]db.ReviewSnippet{{ID: snippetID, Title: "Elbow", Content: "Keep your elbow high."}}, nil)
+	llmMock.EXPECT().SuggestSnippets(gomock.Any(), "Ellbogen zu tief", []llm.Snippet{{ID: snippetIDStr, Title: "Elbow", Content: "Keep your elbow high."}}).
+		Return(llm.SnippetSuggestions{Language: "German", Suggestions: []llm.SnippetSuggestion{{ID: snippetIDStr, Text: "Ellbogen hoch halten."}}}, nil)
+
+	user := reviewUser()
+	user.Permissions = append(user.Permissions, permissions.ReviewsEdit)
+	req := httptest.NewRequest(http.MethodPost, "/reviews/enhance", strings.NewReader(`{"text":"Ellbogen zu tief","mode":"snippets"}`))
+	req = req.WithContext(testUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	h.EnhanceText(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	var resp SnippetSuggestionsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Language != "German" || len(resp.Suggestions) != 1 ||
+		resp.Suggestions[0] != (SnippetSuggestion{SnippetID: snippetIDStr, Title: "Elbow", Text: "Ellbogen hoch halten."}) {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestEnhanceText_UnknownMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	user := reviewUser()
+	user.Permissions = append(user.Permissions, permissions.ReviewsEdit)
+	req := httptest.NewRequest(http.MethodPost, "/reviews/enhance", strings.NewReader(`{"text":"x","mode":"poem"}`))
+	req = req.WithContext(testUserCtx(req.Context(), user))
+	rec := httptest.NewRecorder()
+
+	h.EnhanceText(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,359 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/OZIOisgood/zeta/internal/preferences"
+	"github.com/go-chi/chi/v5"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// reviewReactions are the reactions a review comment accepts. Names rather
+// than emoji keep URLs and stored values plain; clients map them to glyphs.
+var reviewReactions = map[string]bool{
+	"thumbs_up": true,
+	"heart":     true,
+	"clap":      true,
+	"fire":      true,
+	"eyes":      true,
+	"question":  true,
+}
+
+// ReactionSummary counts one reaction on a review. Reacted is set when the
+// caller is one of the reactors.
+type ReactionSummary struct {
+	Reaction string `json:"reaction"`
+	Count    int32  `json:"count"`
+	Reacted  bool   `json:"reacted"`
+}
+
+// ThreadStateRequest moves a top-level review to State.
+type ThreadStateRequest struct {
+	State string `json:"state"`
+}
+
+type ThreadStateResponse struct {
+	ID          string    `json:"id"`
+	ThreadState string    `json:"thread_state"`
+	ChangedAt   time.Time `json:"thread_state_changed_at"`
+}
+
+// canSetThreadState reports whether user may move a thread on an asset owned
+// by ownerID to state. The student acknowledges feedback, the expert resolves
+// it, and either side may reopen it.
+func canSetThreadState(user *auth.UserContext, ownerID string, state db.ReviewThreadState) bool {
+	isOwner := user.ID == ownerID
+	isReviewer := !isOwner && permissions.HasPermission(user.Permissions, permissions.ReviewsCreate)
+	switch state {
+	case db.ReviewThreadStateAcknowledged:
+		return isOwner
+	case db.ReviewThreadStateResolved:
+		return isReviewer
+	case db.ReviewThreadStateOpen:
+		return isOwner || isReviewer
+	default:
+		return false
+	}
+}
+
+func parseThreadState(s string) (db.ReviewThreadState, bool) {
+	switch state := db.ReviewThreadState(s); state {
+	case db.ReviewThreadStateOpen, db.ReviewThreadStateAcknowledged, db.ReviewThreadStateResolved:
+		return state, true
+	default:
+		return "", false
+	}
+}
+
+// reactionSummaries groups the reaction rows of a video by review ID.
+func reactionSummaries(rows []db.ListVideoReviewReactionsRow) map[pgtype.UUID][]ReactionSummary {
+	out := make(map[pgtype.UUID][]ReactionSummary)
+	for _, row := range rows {
+		out[row.ReviewID] = append(out[row.ReviewID], ReactionSummary{
+			Reaction: row.Reaction,
+			Count:    row.Count,
+			Reacted:  row.Reacted,
+		})
+	}
+	return out
+}
+
+// SetThreadState handles PUT /assets/videos/{id}/reviews/{reviewId}/state.
+// Setting the state a thread already has is a no-op.
+func (h *Handler) SetThreadState(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	userInfo := auth.GetUser(ctx)
+	if userInfo == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !permissions.HasPermission(userInfo.Permissions, permissions.ReviewsRead) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+
+	existing, asset, ok := h.loadReviewForActivity(w, r, log, userInfo)
+	if !ok {
+		return
+	}
+
+	var req ThreadStateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	state, ok := parseThreadState(req.State)
+	if !ok {
+		http.Error(w, "Invalid state", http.StatusBadRequest)
+		return
+	}
+
+	if existing.ParentID.Valid {
+		http.Error(w, "Only top-level reviews have a thread state", http.StatusBadRequest)
+		return
+	}
+	if !canSetThreadState(userInfo, asset.OwnerID, state) {
+		http.Error(w, "Not allowed to set this state", http.StatusForbidden)
+		return
+	}
+
+	review := existing
+	if existing.ThreadState != state {
+		err := h.tx.InTx(ctx, func(tx audit.Tx) error {
+			var err error
+			review, err = tx.SetReviewThreadState(ctx, db.SetReviewThreadStateParams{
+				ThreadState: state,
+				ChangedBy:   pgtype.Text{String: userInfo.ID, Valid: true},
+				ID:          existing.ID,
+				VideoID:     existing.VideoID,
+			})
+			if err != nil {
+				return err
+			}
+			return recordReview(ctx, tx, audit.ActionReviewThreadStateChanged, review, audit.ReviewSnapshotOf(existing), audit.ReviewSnapshotOf(review))
+		})
+		if err != nil {
+			log.ErrorContext(ctx, "set_thread_state_failed",
+				slog.String("component", "reviews"),
+				slog.String("review_id", pgutil.UUIDToString(existing.ID)),
+				slog.Any("err", err),
+			)
+			http.Error(w, "Failed to update thread state", http.StatusInternalServerError)
+			return
+		}
+
+		// The student hears from the expert and the other way round.
+		recipientID := asset.OwnerID
+		if userInfo.ID == asset.OwnerID {
+			recipientID = existing.AuthorID.String
+		}
+		h.notifyReviewActivity(asset, review, recipientID, userInfo.ID, notifications.TypeReviewThreadUpdated,
+			func(a reviewActivity) any {
+				return notifications.ReviewThreadUpdatedPayload{
+					AssetID:    a.assetID,
+					VideoID:    a.videoID,
+					ReviewID:   a.reviewID,
+					VideoTitle: a.videoTitle,
+					ActorName:  a.actorName,
+					State:      string(state),
+				}
+			})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ThreadStateResponse{
+		ID:          pgutil.UUIDToString(review.ID),
+		ThreadState: string(review.ThreadState),
+		ChangedAt:   review.ThreadStateChangedAt.Time,
+	})
+}
+
+// AddReaction handles PUT /assets/videos/{id}/reviews/{reviewId}/reactions/{reaction}.
+// Reacting twice is a no-op and notifies nobody.
+func (h *Handler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, true)
+}
+
+// RemoveReaction handles DELETE /assets/videos/{id}/reviews/{reviewId}/reactions/{reaction}.
+func (h *Handler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, false)
+}
+
+func (h *Handler) changeReaction(w http.ResponseWriter, r *http.Request, add bool) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	userInfo := auth.GetUser(ctx)
+	if userInfo == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !permissions.HasPermission(userInfo.Permissions, permissions.ReviewsRead) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return
+	}
+
+	reaction := chi.URLParam(r, "reaction")
+	if !reviewReactions[reaction] {
+		http.Error(w, "Invalid reaction", http.StatusBadRequest)
+		return
+	}
+
+	review, asset, ok := h.loadReviewForActivity(w, r, log, userInfo)
+	if !ok {
+		return
+	}
+
+	var (
+		n   int64
+		err error
+	)
+	if add {
+		n, err = h.q.AddReviewReaction(ctx, db.AddReviewReactionParams{
+			ReviewID: review.ID,
+			UserID:   userInfo.ID,
+			Reaction: reaction,
+		})
+	} else {
+		_, err = h.q.RemoveReviewReaction(ctx, db.RemoveReviewReactionParams{
+			ReviewID: review.ID,
+			UserID:   userInfo.ID,
+			Reaction: reaction,
+		})
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "change_reaction_failed",
+			slog.String("component", "reviews"),
+			slog.String("review_id", pgutil.UUIDToString(review.ID)),
+			slog.Bool("add", add),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to update reaction", http.StatusInternalServerError)
+		return
+	}
+
+	if n > 0 {
+		h.notifyReviewActivity(asset, review, review.AuthorID.String, userInfo.ID, notifications.TypeReviewReactionAdded,
+			func(a reviewActivity) any {
+				return notifications.ReviewReactionAddedPayload{
+					AssetID:    a.assetID,
+					VideoID:    a.videoID,
+					ReviewID:   a.reviewID,
+					VideoTitle: a.videoTitle,
+					ActorName:  a.actorName,
+					Reaction:   reaction,
+				}
+			})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadReviewForActivity resolves the {id} video and {reviewId} review of a
+// state change or reaction, writing the error response when it fails. Unlike
+// edits, both stay possible after the video is marked reviewed.
+func (h *Handler) loadReviewForActivity(w http.ResponseWriter, r *http.Request, log *slog.Logger, user *auth.UserContext) (db.VideoReview, db.GetAssetOwnerByVideoIDRow, bool) {
+	ctx := r.Context()
+
+	idStr := chi.URLParam(r, "id")
+	var videoID pgtype.UUID
+	if err := videoID.Scan(idStr); err != nil {
+		http.Error(w, "Invalid video ID", http.StatusBadRequest)
+		return db.VideoReview{}, db.GetAssetOwnerByVideoIDRow{}, false
+	}
+
+	reviewIDStr := chi.URLParam(r, "reviewId")
+	var reviewID pgtype.UUID
+	if err := reviewID.Scan(reviewIDStr); err != nil {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return db.VideoReview{}, db.GetAssetOwnerByVideoIDRow{}, false
+	}
+
+	if !h.ensureVideoVisible(w, r, log, user, videoID, idStr) {
+		return db.VideoReview{}, db.GetAssetOwnerByVideoIDRow{}, false
+	}
+
+	review, err := h.q.GetVideoReview(ctx, reviewID)
+	if err == nil && review.VideoID != videoID {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Review not found", http.StatusNotFound)
+			return db.VideoReview{}, db.GetAssetOwnerByVideoIDRow{}, false
+		}
+		log.ErrorContext(ctx, "get_review_failed",
+			slog.String("component", "reviews"),
+			slog.String("review_id", reviewIDStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch review", http.StatusInternalServerError)
+		return db.VideoReview{}, db.GetAssetOwnerByVideoIDRow{}, false
+	}
+
+	asset, err := h.q.GetAssetOwnerByVideoID(ctx, videoID)
+	if err != nil {
+		log.ErrorContext(ctx, "get_asset_owner_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", idStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to fetch video", http.StatusInternalServerError)
+		return db.VideoReview{}, db.GetAssetOwnerByVideoIDRow{}, false
+	}
+	return review, asset, true
+}
+
+// reviewActivity holds the notification fields shared by every kind of
+// activity on a review.
+type reviewActivity struct {
+	assetID, videoID, reviewID, videoTitle, actorName string
+}
+
+// notifyReviewActivity records a notification of type t for recipientID
+// about activity by actorID on review. build fills the type's payload from
+// the shared fields. Skipped when the recipient is the actor; runs detached
+// from the request.
+func (h *Handler) notifyReviewActivity(asset db.GetAssetOwnerByVideoIDRow, review db.VideoReview, recipientID, actorID string, t notifications.Type, build func(reviewActivity) any) {
+	if recipientID == "" || recipientID == actorID {
+		return
+	}
+	go func() {
+		bgCtx := context.Background()
+		actorName := preferences.DefaultDisplayName("", "")
+		prefs, err := h.q.GetUserPreferences(bgCtx, actorID)
+		if err != nil {
+			h.logger.WarnContext(bgCtx, "review_activity_actor_fetch_failed",
+				slog.String("component", "reviews"),
+				slog.String("user_id", actorID),
+				slog.Any("err", err),
+			)
+		} else {
+			actorName = preferences.PublicDisplayName(prefs)
+		}
+		notifications.Record(bgCtx, h.q, h.logger, recipientID, t, build(reviewActivity{
+			assetID:    pgutil.UUIDToString(asset.AssetID),
+			videoID:    pgutil.UUIDToString(review.VideoID),
+			reviewID:   pgutil.UUIDToString(review.ID),
+			videoTitle: asset.Name,
+			actorName:  actorName,
+		}))
+	}()
+}
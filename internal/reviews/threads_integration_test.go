@@ -0,0 +1,104 @@
+//go:build integration
+
+package reviews_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_ThreadStateFeedsAssetProgress(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if err := q.AddUserToGroup(ctx, db.AddUserToGroupParams{UserID: "expert-1", GroupID: group.ID}); err != nil {
+		t.Fatalf("AddUserToGroup: %v", err)
+	}
+	asset, err := q.CreateAsset(ctx, db.CreateAssetParams{Name: "Match", GroupID: group.ID, OwnerID: "student-1"})
+	if err != nil {
+		t.Fatalf("CreateAsset: %v", err)
+	}
+	video, err := q.CreateVideo(ctx, db.CreateVideoParams{AssetID: asset.ID, Status: db.VideoStatusReady})
+	if err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+
+	author := pgtype.Text{String: "expert-1", Valid: true}
+	first, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{VideoID: video.ID, Content: "Elbow up", AuthorID: author})
+	if err != nil {
+		t.Fatalf("CreateVideoReview: %v", err)
+	}
+	second, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{VideoID: video.ID, Content: "Follow through", AuthorID: author})
+	if err != nil {
+		t.Fatalf("CreateVideoReview: %v", err)
+	}
+	// Replies are not threads of their own.
+	reply, err := q.CreateVideoReview(ctx, db.CreateVideoReviewParams{VideoID: video.ID, Content: "Thanks", ParentID: first.ID})
+	if err != nil {
+		t.Fatalf("CreateVideoReview reply: %v", err)
+	}
+	if err := q.UpdateAssetStatus(ctx, db.UpdateAssetStatusParams{ID: asset.ID, Status: db.AssetStatusCompleted}); err != nil {
+		t.Fatalf("UpdateAssetStatus: %v", err)
+	}
+
+	stateOf := func(reviewState string) []db.ListVisibleAssetsRow {
+		t.Helper()
+		rows, err := q.ListVisibleAssets(ctx, db.ListVisibleAssetsParams{UserID: "expert-1", ReviewState: reviewState})
+		if err != nil {
+			t.Fatalf("ListVisibleAssets: %v", err)
+		}
+		return rows
+	}
+
+	rows := stateOf("in_review")
+	if len(rows) != 1 || rows[0].ReviewThreads != 2 || rows[0].OpenReviewThreads != 2 {
+		t.Fatalf("completed asset with open threads: in_review = %+v", rows)
+	}
+	if rows := stateOf("reviewed"); len(rows) != 0 {
+		t.Errorf("reviewed = %+v, want none while threads are open", rows)
+	}
+
+	if _, err := q.SetReviewThreadState(ctx, db.SetReviewThreadStateParams{
+		ThreadState: db.ReviewThreadStateResolved, ID: reply.ID, VideoID: video.ID,
+	}); err == nil {
+		t.Error("set a thread state on a reply")
+	}
+	for _, r := range []db.VideoReview{first, second} {
+		updated, err := q.SetReviewThreadState(ctx, db.SetReviewThreadStateParams{
+			ThreadState: db.ReviewThreadStateAcknowledged,
+			ChangedBy:   pgtype.Text{String: "student-1", Valid: true},
+			ID:          r.ID,
+			VideoID:     video.ID,
+		})
+		if err != nil || updated.ThreadState != db.ReviewThreadStateAcknowledged || !updated.ThreadStateChangedAt.Valid {
+			t.Fatalf("SetReviewThreadState = %+v, %v", updated, err)
+		}
+	}
+
+	rows = stateOf("reviewed")
+	if len(rows) != 1 || rows[0].OpenReviewThreads != 0 {
+		t.Fatalf("reviewed = %+v, want the asset once every thread is addressed", rows)
+	}
+
+	for _, user := range []string{"student-1", "expert-2"} {
+		if n, err := q.AddReviewReaction(ctx, db.AddReviewReactionParams{ReviewID: first.ID, UserID: user, Reaction: "heart"}); err != nil || n != 1 {
+			t.Fatalf("AddReviewReaction(%s) = %d, %v", user, n, err)
+		}
+	}
+	if n, err := q.AddReviewReaction(ctx, db.AddReviewReactionParams{ReviewID: first.ID, UserID: "student-1", Reaction: "heart"}); err != nil || n != 0 {
+		t.Errorf("repeated reaction = %d, %v; want a no-op", n, err)
+	}
+	reactions, err := q.ListVideoReviewReactions(ctx, db.ListVideoReviewReactionsParams{UserID: "student-1", VideoID: video.ID})
+	if err != nil || len(reactions) != 1 || reactions[0].Count != 2 || !reactions[0].Reacted {
+		t.Errorf("ListVideoReviewReactions = %+v, %v", reactions, err)
+	}
+}
@@ -0,0 +1,278 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	llmmocks "github.com/OZIOisgood/zeta/internal/llm/mocks"
+	"github.com/OZIOisgood/zeta/internal/notifications"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const threadTestVideoID = "01020304-0506-0708-090a-0b0c0d0e0f10"
+
+func threadStudent() *auth.UserContext {
+	return &auth.UserContext{
+		ID:          "student-1",
+		Role:        permissions.RoleStudent,
+		Permissions: []string{permissions.ReviewsRead, permissions.ReviewsReply},
+	}
+}
+
+func threadRequest(method, body string, user *auth.UserContext, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", threadTestVideoID)
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	req := httptest.NewRequest(method, "/videos/"+threadTestVideoID+"/reviews", strings.NewReader(body))
+	return req.WithContext(testUserCtx(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), user))
+}
+
+// threadFixture is an asset owned by student-1 with one top-level review by
+// expert user-1.
+func threadFixture(q *dbmocks.MockQuerier, user *auth.UserContext) (db.VideoReview, string) {
+	videoID := testUUID()
+	reviewID := pgtype.UUID{Valid: true}
+	copy(reviewID.Bytes[:], []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	review := db.VideoReview{
+		ID:          reviewID,
+		VideoID:     videoID,
+		Content:     "Keep the elbow high",
+		AuthorID:    pgtype.Text{String: "user-1", Valid: true},
+		ThreadState: db.ReviewThreadStateOpen,
+	}
+	expectVideoVisible(q, videoID, user)
+	q.EXPECT().GetVideoReview(gomock.Any(), reviewID).Return(review, nil)
+	q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), videoID).Return(db.GetAssetOwnerByVideoIDRow{
+		AssetID: testUUID(),
+		OwnerID: "student-1",
+		Name:    "Backhand drill",
+	}, nil).AnyTimes()
+	return review, "100f0e0d-0c0b-0a09-0807-060504030201"
+}
+
+func expectNotification(q *dbmocks.MockQuerier) chan db.CreateNotificationParams {
+	recorded := make(chan db.CreateNotificationParams, 1)
+	q.EXPECT().GetUserPreferences(gomock.Any(), gomock.Any()).Return(db.UserPreference{FirstName: "Ann", LastName: "Lee"}, nil)
+	q.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CreateNotificationParams) (db.Notification, error) {
+			recorded <- arg
+			return db.Notification{}, nil
+		}).Times(1)
+	return recorded
+}
+
+func waitNotification(t *testing.T, recorded chan db.CreateNotificationParams) db.CreateNotificationParams {
+	t.Helper()
+	select {
+	case arg := <-recorded:
+		return arg
+	case <-time.After(2 * time.Second):
+		t.Fatal("CreateNotification was not called")
+		return db.CreateNotificationParams{}
+	}
+}
+
+func TestCanSetThreadState(t *testing.T) {
+	expert := reviewUser()
+	student := threadStudent()
+	cases := []struct {
+		user  *auth.UserContext
+		state db.ReviewThreadState
+		want  bool
+	}{
+		{student, db.ReviewThreadStateAcknowledged, true},
+		{student, db.ReviewThreadStateResolved, false},
+		{student, db.ReviewThreadStateOpen, true},
+		{expert, db.ReviewThreadStateAcknowledged, false},
+		{expert, db.ReviewThreadStateResolved, true},
+		{expert, db.ReviewThreadStateOpen, true},
+		{&auth.UserContext{ID: "viewer", Permissions: []string{permissions.ReviewsRead}}, db.ReviewThreadStateOpen, false},
+	}
+	for _, c := range cases {
+		if got := canSetThreadState(c.user, "student-1", c.state); got != c.want {
+			t.Errorf("canSetThreadState(%s, %s) = %v, want %v", c.user.ID, c.state, got, c.want)
+		}
+	}
+}
+
+func TestSetThreadState_StudentAcknowledgesAndNotifiesReviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	user := threadStudent()
+	review, reviewIDStr := threadFixture(q, user)
+	updated := review
+	updated.ThreadState = db.ReviewThreadStateAcknowledged
+	updated.ThreadStateChangedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	q.EXPECT().SetReviewThreadState(gomock.Any(), db.SetReviewThreadStateParams{
+		ThreadState: db.ReviewThreadStateAcknowledged,
+		ChangedBy:   pgtype.Text{String: "student-1", Valid: true},
+		ID:          review.ID,
+		VideoID:     review.VideoID,
+	}).Return(updated, nil)
+	recorded := expectNotification(q)
+
+	rec := httptest.NewRecorder()
+	h.SetThreadState(rec, threadRequest(http.MethodPut, `{"state":"acknowledged"}`, user, map[string]string{"reviewId": reviewIDStr}))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var resp ThreadStateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ThreadState != "acknowledged" {
+		t.Errorf("thread_state = %q, want acknowledged", resp.ThreadState)
+	}
+	if got := runner.Actions(); len(got) != 1 || got[0] != audit.ActionReviewThreadStateChanged {
+		t.Fatalf("audit actions = %v, want one %s", got, audit.ActionReviewThreadStateChanged)
+	}
+
+	arg := waitNotification(t, recorded)
+	if arg.RecipientID != "user-1" || arg.Type != db.NotificationType(notifications.TypeReviewThreadUpdated) {
+		t.Fatalf("notification = %s to %q, want %s to user-1", arg.Type, arg.RecipientID, notifications.TypeReviewThreadUpdated)
+	}
+	var payload notifications.ReviewThreadUpdatedPayload
+	if err := json.Unmarshal(arg.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.State != "acknowledged" || payload.ActorName != "Ann L." || payload.VideoTitle != "Backhand drill" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestSetThreadState_Rejections(t *testing.T) {
+	cases := map[string]struct {
+		user   *auth.UserContext
+		body   string
+		reply  bool
+		status int
+	}{
+		"student cannot resolve":    {threadStudent(), `{"state":"resolved"}`, false, http.StatusForbidden},
+		"expert cannot acknowledge": {reviewUser(), `{"state":"acknowledged"}`, false, http.StatusForbidden},
+		"unknown state":             {reviewUser(), `{"state":"done"}`, false, http.StatusBadRequest},
+		"reply has no state":        {reviewUser(), `{"state":"resolved"}`, true, http.StatusBadRequest},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			runner := audittest.NewRunner(q)
+			h := NewHandler(q, runner, slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+			videoID := testUUID()
+			review := db.VideoReview{ID: videoID, VideoID: videoID, ThreadState: db.ReviewThreadStateOpen}
+			if c.reply {
+				review.ParentID = videoID
+			}
+			expectVideoVisible(q, videoID, c.user)
+			q.EXPECT().GetVideoReview(gomock.Any(), videoID).Return(review, nil)
+			q.EXPECT().GetAssetOwnerByVideoID(gomock.Any(), videoID).Return(db.GetAssetOwnerByVideoIDRow{OwnerID: "student-1"}, nil)
+
+			rec := httptest.NewRecorder()
+			h.SetThreadState(rec, threadRequest(http.MethodPut, c.body, c.user, map[string]string{"reviewId": threadTestVideoID}))
+
+			if rec.Code != c.status {
+				t.Fatalf("got %d, want %d: %s", rec.Code, c.status, rec.Body.String())
+			}
+			if len(runner.Events()) != 0 {
+				t.Errorf("audited %v on a rejected change", runner.Actions())
+			}
+		})
+	}
+}
+
+func TestSetThreadState_SameStateIsNoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(q, runner, slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	user := reviewUser()
+	_, reviewIDStr := threadFixture(q, user)
+
+	rec := httptest.NewRecorder()
+	h.SetThreadState(rec, threadRequest(http.MethodPut, `{"state":"open"}`, user, map[string]string{"reviewId": reviewIDStr}))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if len(runner.Events()) != 0 {
+		t.Errorf("audited %v for an unchanged state", runner.Actions())
+	}
+}
+
+func TestAddReaction_NotifiesAuthorOnFirstReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	user := threadStudent()
+	review, reviewIDStr := threadFixture(q, user)
+	q.EXPECT().AddReviewReaction(gomock.Any(), db.AddReviewReactionParams{
+		ReviewID: review.ID,
+		UserID:   "student-1",
+		Reaction: "thumbs_up",
+	}).Return(int64(1), nil)
+	recorded := expectNotification(q)
+
+	rec := httptest.NewRecorder()
+	h.AddReaction(rec, threadRequest(http.MethodPut, "", user, map[string]string{"reviewId": reviewIDStr, "reaction": "thumbs_up"}))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d, want 204: %s", rec.Code, rec.Body.String())
+	}
+	arg := waitNotification(t, recorded)
+	if arg.RecipientID != "user-1" || arg.Type != db.NotificationType(notifications.TypeReviewReactionAdded) {
+		t.Fatalf("notification = %s to %q, want %s to user-1", arg.Type, arg.RecipientID, notifications.TypeReviewReactionAdded)
+	}
+}
+
+func TestAddReaction_RepeatIsSilent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	user := threadStudent()
+	_, reviewIDStr := threadFixture(q, user)
+	q.EXPECT().AddReviewReaction(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+	rec := httptest.NewRecorder()
+	h.AddReaction(rec, threadRequest(http.MethodPut, "", user, map[string]string{"reviewId": reviewIDStr, "reaction": "heart"}))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d, want 204: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAddReaction_UnknownReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, audittest.NewRunner(q), slog.Default(), llmmocks.NewMockEnhancer(ctrl))
+
+	rec := httptest.NewRecorder()
+	h.AddReaction(rec, threadRequest(http.MethodPut, "", reviewUser(), map[string]string{"reviewId": threadTestVideoID, "reaction": "poop"}))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+}
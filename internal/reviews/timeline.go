@@ -0,0 +1,102 @@
+package reviews
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ChapterResponse is a named section of a video. EndSeconds is omitted for an
+// open chapter, which runs until the next one starts.
+type ChapterResponse struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	StartSeconds int32     `json:"start_seconds"`
+	EndSeconds   *int32    `json:"end_seconds,omitempty"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TimelineResponse is returned by ListReviews with ?include=chapters: the
+// review threads plus the chapters ordered by start, enough for a client to
+// lay out a timeline in one request.
+type TimelineResponse struct {
+	Reviews  []ReviewResponse  `json:"reviews"`
+	Chapters []ChapterResponse `json:"chapters"`
+}
+
+func chapterResponse(c db.VideoChapter) ChapterResponse {
+	resp := ChapterResponse{
+		ID:           pgutil.UUIDToString(c.ID),
+		Title:        c.Title,
+		StartSeconds: c.StartSeconds,
+		CreatedBy:    c.CreatedBy,
+		CreatedAt:    c.CreatedAt.Time,
+		UpdatedAt:    c.UpdatedAt.Time,
+	}
+	if c.EndSeconds.Valid {
+		end := c.EndSeconds.Int32
+		resp.EndSeconds = &end
+	}
+	return resp
+}
+
+// checkRange validates a range in whole seconds; end is optional.
+func checkRange(start int32, end *int32) error {
+	if start < 0 {
+		return errors.New("start must not be negative")
+	}
+	if end != nil && *end <= start {
+		return errors.New("end_seconds must be after the start")
+	}
+	return nil
+}
+
+func optionalInt4(v *int32) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *v, Valid: true}
+}
+
+// ensureRangeFitsVideo rejects a range that runs past the end of the video.
+// The duration is only known once Mux has processed the upload; until then
+// ranges are rejected, since nothing would recheck them once it arrives.
+func (h *Handler) ensureRangeFitsVideo(w http.ResponseWriter, r *http.Request, log *slog.Logger, videoID pgtype.UUID, videoIDStr string, start int32, end *int32) bool {
+	ctx := r.Context()
+	duration, err := h.q.GetVideoDuration(ctx, videoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Video not found", http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "get_video_duration_failed",
+			slog.String("component", "reviews"),
+			slog.String("video_id", videoIDStr),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to check video duration", http.StatusInternalServerError)
+		return false
+	}
+	if !duration.Valid {
+		http.Error(w, "Video duration is not known yet", http.StatusConflict)
+		return false
+	}
+	last := start
+	if end != nil {
+		last = *end
+	}
+	if float64(last) > math.Ceil(duration.Float64) {
+		http.Error(w, "Range is past the end of the video", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
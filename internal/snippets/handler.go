@@ -0,0 +1,365 @@
+// Package snippets manages the reusable review comments experts insert
+// instead of retyping the same corrections.
+package snippets
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/logger"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/OZIOisgood/zeta/internal/pgutil"
+	"github.com/go-chi/chi/v5"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	maxTitleLength   = 120
+	maxContentLength = 4000
+	maxTags          = 10
+	maxTagLength     = 32
+	defaultListLimit = 100
+	maxListLimit     = 200
+)
+
+// Handler serves the snippet library. Snippets are private to their owner
+// unless shared with a group; only the owner may change or delete one.
+type Handler struct {
+	q      db.Querier
+	logger *slog.Logger
+}
+
+func NewHandler(q db.Querier, logger *slog.Logger) *Handler {
+	return &Handler{q: q, logger: logger}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.ListSnippets)
+	r.Post("/", h.CreateSnippet)
+	r.Put("/{snippetID}", h.UpdateSnippet)
+	r.Delete("/{snippetID}", h.DeleteSnippet)
+}
+
+type SnippetResponse struct {
+	ID         string     `json:"id"`
+	OwnerID    string     `json:"owner_id"`
+	GroupID    *string    `json:"group_id,omitempty"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Tags       []string   `json:"tags"`
+	UsageCount int32      `json:"usage_count"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// SnippetRequest creates or replaces a snippet. Set GroupID to share it with
+// a group the caller belongs to.
+type SnippetRequest struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+	GroupID *string  `json:"group_id,omitempty"`
+}
+
+func snippetResponse(s db.ReviewSnippet) SnippetResponse {
+	resp := SnippetResponse{
+		ID:         pgutil.UUIDToString(s.ID),
+		OwnerID:    s.OwnerID,
+		Title:      s.Title,
+		Content:    s.Content,
+		Tags:       s.Tags,
+		UsageCount: s.UsageCount,
+		CreatedAt:  s.CreatedAt.Time,
+		UpdatedAt:  s.UpdatedAt.Time,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if s.GroupID.Valid {
+		groupID := pgutil.UUIDToString(s.GroupID)
+		resp.GroupID = &groupID
+	}
+	if s.LastUsedAt.Valid {
+		resp.LastUsedAt = &s.LastUsedAt.Time
+	}
+	return resp
+}
+
+// normalizeTags lowercases, trims and de-duplicates tags, keeping their order.
+func normalizeTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		if utf8.RuneCountInString(t) > maxTagLength {
+			return nil, errors.New("Tag is too long")
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	if len(tags) > maxTags {
+		return nil, errors.New("Too many tags")
+	}
+	return tags, nil
+}
+
+// parse validates req and resolves the group it is shared with.
+func (req *SnippetRequest) parse() (tags []string, groupID pgtype.UUID, err error) {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Title == "" {
+		return nil, groupID, errors.New("Title is required")
+	}
+	if req.Content == "" {
+		return nil, groupID, errors.New("Content is required")
+	}
+	if utf8.RuneCountInString(req.Title) > maxTitleLength {
+		return nil, groupID, errors.New("Title is too long")
+	}
+	if utf8.RuneCountInString(req.Content) > maxContentLength {
+		return nil, groupID, errors.New("Content is too long")
+	}
+	if tags, err = normalizeTags(req.Tags); err != nil {
+		return nil, groupID, err
+	}
+	if req.GroupID != nil && *req.GroupID != "" {
+		if err := groupID.Scan(*req.GroupID); err != nil {
+			return nil, groupID, errors.New("Invalid group_id")
+		}
+	}
+	return tags, groupID, nil
+}
+
+// authorize returns the caller if they may use the snippet library.
+func authorize(w http.ResponseWriter, r *http.Request) *auth.UserContext {
+	user := auth.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+	if !permissions.HasPermission(user.Permissions, permissions.ReviewsCreate) {
+		http.Error(w, "Permission denied", http.StatusForbidden)
+		return nil
+	}
+	return user
+}
+
+// ListSnippets handles GET /reviews/snippets?tag=&q=&group_id=&limit=.
+func (h *Handler) ListSnippets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	user := authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	values := r.URL.Query()
+	params := db.ListVisibleSnippetsParams{UserID: user.ID, RowLimit: defaultListLimit}
+	if v := strings.TrimSpace(values.Get("group_id")); v != "" {
+		if err := params.GroupID.Scan(v); err != nil {
+			http.Error(w, "Invalid group_id", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := strings.ToLower(strings.TrimSpace(values.Get("tag"))); v != "" {
+		params.Tag = pgtype.Text{String: v, Valid: true}
+	}
+	if v := strings.TrimSpace(values.Get("q")); v != "" {
+		params.Search = pgtype.Text{String: v, Valid: true}
+	}
+	if v, err := strconv.Atoi(values.Get("limit")); err == nil && v > 0 {
+		params.RowLimit = int32(min(v, maxListLimit))
+	}
+
+	rows, err := h.q.ListVisibleSnippets(ctx, params)
+	if err != nil {
+		log.ErrorContext(ctx, "list_snippets_failed",
+			slog.String("component", "snippets"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to list snippets", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]SnippetResponse, len(rows))
+	for i, row := range rows {
+		response[i] = snippetResponse(row)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateSnippet handles POST /reviews/snippets.
+func (h *Handler) CreateSnippet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	user := authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	var req SnippetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	tags, groupID, err := req.parse()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.ensureGroupMember(w, r, log, user, groupID) {
+		return
+	}
+
+	snippet, err := h.q.CreateSnippet(ctx, db.CreateSnippetParams{
+		OwnerID: user.ID,
+		GroupID: groupID,
+		Title:   req.Title,
+		Content: req.Content,
+		Tags:    tags,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "create_snippet_failed",
+			slog.String("component", "snippets"),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to create snippet", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(snippetResponse(snippet))
+}
+
+// UpdateSnippet handles PUT /reviews/snippets/{snippetID}. The usage count is
+// kept.
+func (h *Handler) UpdateSnippet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	user := authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	var snippetID pgtype.UUID
+	if err := snippetID.Scan(chi.URLParam(r, "snippetID")); err != nil {
+		http.Error(w, "Invalid snippet ID", http.StatusBadRequest)
+		return
+	}
+
+	var req SnippetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	tags, groupID, err := req.parse()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.ensureGroupMember(w, r, log, user, groupID) {
+		return
+	}
+
+	snippet, err := h.q.UpdateSnippet(ctx, db.UpdateSnippetParams{
+		ID:      snippetID,
+		OwnerID: user.ID,
+		GroupID: groupID,
+		Title:   req.Title,
+		Content: req.Content,
+		Tags:    tags,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Snippet not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "update_snippet_failed",
+			slog.String("component", "snippets"),
+			slog.String("snippet_id", pgutil.UUIDToString(snippetID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to update snippet", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snippetResponse(snippet))
+}
+
+// DeleteSnippet handles DELETE /reviews/snippets/{snippetID}. Reviews written
+// from the snippet keep their text.
+func (h *Handler) DeleteSnippet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := logger.From(ctx, h.logger)
+
+	user := authorize(w, r)
+	if user == nil {
+		return
+	}
+
+	var snippetID pgtype.UUID
+	if err := snippetID.Scan(chi.URLParam(r, "snippetID")); err != nil {
+		http.Error(w, "Invalid snippet ID", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.q.DeleteSnippet(ctx, db.DeleteSnippetParams{ID: snippetID, OwnerID: user.ID})
+	if err != nil {
+		log.ErrorContext(ctx, "delete_snippet_failed",
+			slog.String("component", "snippets"),
+			slog.String("snippet_id", pgutil.UUIDToString(snippetID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to delete snippet", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Snippet not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ensureGroupMember rejects sharing a snippet with a group the caller is not
+// in. A zero groupID keeps the snippet private and always passes.
+func (h *Handler) ensureGroupMember(w http.ResponseWriter, r *http.Request, log *slog.Logger, user *auth.UserContext, groupID pgtype.UUID) bool {
+	if !groupID.Valid {
+		return true
+	}
+	ctx := r.Context()
+	member, err := h.q.CheckUserGroup(ctx, db.CheckUserGroupParams{UserID: user.ID, GroupID: groupID})
+	if err != nil {
+		log.ErrorContext(ctx, "check_snippet_group_failed",
+			slog.String("component", "snippets"),
+			slog.String("group_id", pgutil.UUIDToString(groupID)),
+			slog.Any("err", err),
+		)
+		http.Error(w, "Failed to check group membership", http.StatusInternalServerError)
+		return false
+	}
+	if !member {
+		http.Error(w, "Not a member of this group", http.StatusForbidden)
+		return false
+	}
+	return true
+}
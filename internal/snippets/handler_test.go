@@ -0,0 +1,207 @@
+package snippets
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/auth"
+	"github.com/OZIOisgood/zeta/internal/db"
+	dbmocks "github.com/OZIOisgood/zeta/internal/db/mocks"
+	"github.com/OZIOisgood/zeta/internal/permissions"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testSnippetID = "01020304-0506-0708-090a-0b0c0d0e0f10"
+	testGroupID   = "09090909-0909-0909-0909-090909090909"
+)
+
+func expertUser() *auth.UserContext {
+	return &auth.UserContext{ID: "expert-1", Role: permissions.RoleExpert, Permissions: []string{permissions.ReviewsCreate}}
+}
+
+func newRequest(method, target, body string, user *auth.UserContext, snippetID string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), auth.UserKey, user)
+	if snippetID != "" {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("snippetID", snippetID)
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	}
+	return req.WithContext(ctx)
+}
+
+func uuid(s string) pgtype.UUID {
+	var u pgtype.UUID
+	_ = u.Scan(s)
+	return u
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := normalizeTags([]string{" Serve ", "serve", "", "FOOTWORK"})
+	if err != nil || strings.Join(got, ",") != "serve,footwork" {
+		t.Errorf("normalizeTags = %v, %v", got, err)
+	}
+	if _, err := normalizeTags([]string{strings.Repeat("x", maxTagLength+1)}); err == nil {
+		t.Error("accepted an overlong tag")
+	}
+	many := make([]string, maxTags+1)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+	if _, err := normalizeTags(many); err == nil {
+		t.Error("accepted too many tags")
+	}
+}
+
+func TestListSnippets_PassesFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, slog.Default())
+
+	q.EXPECT().ListVisibleSnippets(gomock.Any(), db.ListVisibleSnippetsParams{
+		UserID:   "expert-1",
+		GroupID:  uuid(testGroupID),
+		Tag:      pgtype.Text{String: "serve", Valid: true},
+		Search:   pgtype.Text{String: "elbow", Valid: true},
+		RowLimit: maxListLimit,
+	}).Return([]db.ReviewSnippet{{ID: uuid(testSnippetID), OwnerID: "expert-1", Title: "Elbow", Content: "Keep it high"}}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ListSnippets(rec, newRequest(http.MethodGet, "/reviews/snippets?group_id="+testGroupID+"&tag=Serve&q=elbow&limit=5000", "", expertUser(), ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp []SnippetResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp) != 1 || resp[0].Tags == nil || resp[0].GroupID != nil {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestListSnippets_StudentsForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	h := NewHandler(dbmocks.NewMockQuerier(ctrl), slog.Default())
+
+	student := &auth.UserContext{ID: "student-1", Role: permissions.RoleStudent, Permissions: []string{permissions.ReviewsReply}}
+	rec := httptest.NewRecorder()
+	h.ListSnippets(rec, newRequest(http.MethodGet, "/reviews/snippets", "", student, ""))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got %d, want 403", rec.Code)
+	}
+}
+
+func TestCreateSnippet(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(q *dbmocks.MockQuerier)
+		wantStatus int
+	}{
+		{
+			name: "private snippet",
+			body: `{"title":" Elbow ","content":"Keep your elbow high.","tags":["Serve","serve"]}`,
+			setupMock: func(q *dbmocks.MockQuerier) {
+				q.EXPECT().CreateSnippet(gomock.Any(), db.CreateSnippetParams{
+					OwnerID: "expert-1",
+					Title:   "Elbow",
+					Content: "Keep your elbow high.",
+					Tags:    []string{"serve"},
+				}).Return(db.ReviewSnippet{ID: uuid(testSnippetID), OwnerID: "expert-1"}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "shared with own group",
+			body: `{"title":"Elbow","content":"Keep it high","group_id":"` + testGroupID + `"}`,
+			setupMock: func(q *dbmocks.MockQuerier) {
+				q.EXPECT().CheckUserGroup(gomock.Any(), db.CheckUserGroupParams{UserID: "expert-1", GroupID: uuid(testGroupID)}).Return(true, nil)
+				q.EXPECT().CreateSnippet(gomock.Any(), gomock.Any()).Return(db.ReviewSnippet{ID: uuid(testSnippetID)}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "shared with foreign group",
+			body: `{"title":"Elbow","content":"Keep it high","group_id":"` + testGroupID + `"}`,
+			setupMock: func(q *dbmocks.MockQuerier) {
+				q.EXPECT().CheckUserGroup(gomock.Any(), gomock.Any()).Return(false, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing content",
+			body:       `{"title":"Elbow","content":"  "}`,
+			setupMock:  func(q *dbmocks.MockQuerier) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			q := dbmocks.NewMockQuerier(ctrl)
+			tt.setupMock(q)
+			h := NewHandler(q, slog.Default())
+
+			rec := httptest.NewRecorder()
+			h.CreateSnippet(rec, newRequest(http.MethodPost, "/reviews/snippets", tt.body, expertUser(), ""))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdateSnippet_OtherOwnersSnippetNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, slog.Default())
+
+	q.EXPECT().UpdateSnippet(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.UpdateSnippetParams) (db.ReviewSnippet, error) {
+			if arg.OwnerID != "expert-1" {
+				t.Errorf("update not scoped to the caller: %+v", arg)
+			}
+			return db.ReviewSnippet{}, pgx.ErrNoRows
+		})
+
+	rec := httptest.NewRecorder()
+	h.UpdateSnippet(rec, newRequest(http.MethodPut, "/reviews/snippets/"+testSnippetID, `{"title":"x","content":"y"}`, expertUser(), testSnippetID))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("got %d, want 404", rec.Code)
+	}
+}
+
+func TestDeleteSnippet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	h := NewHandler(q, slog.Default())
+
+	q.EXPECT().DeleteSnippet(gomock.Any(), db.DeleteSnippetParams{ID: uuid(testSnippetID), OwnerID: "expert-1"}).Return(int64(1), nil)
+	rec := httptest.NewRecorder()
+	h.DeleteSnippet(rec, newRequest(http.MethodDelete, "/reviews/snippets/"+testSnippetID, "", expertUser(), testSnippetID))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got %d, want 204", rec.Code)
+	}
+
+	q.EXPECT().DeleteSnippet(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	rec = httptest.NewRecorder()
+	h.DeleteSnippet(rec, newRequest(http.MethodDelete, "/reviews/snippets/"+testSnippetID, "", expertUser(), testSnippetID))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("got %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package snippets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OZIOisgood/zeta/internal/db"
+	"github.com/OZIOisgood/zeta/internal/testdb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIntegration_SnippetVisibilityAndUsage(t *testing.T) {
+	ctx := context.Background()
+	pool := testdb.New(t)
+	q := db.New(pool)
+
+	group, err := q.CreateGroup(ctx, db.CreateGroupParams{Name: "Academy", OwnerID: "expert-1"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	for _, user := range []string{"expert-1", "expert-2"} {
+		if err := q.AddUserToGroup(ctx, db.AddUserToGroupParams{UserID: user, GroupID: group.ID}); err != nil {
+			t.Fatalf("AddUserToGroup: %v", err)
+		}
+	}
+
+	private, err := q.CreateSnippet(ctx, db.CreateSnippetParams{OwnerID: "expert-1", Title: "Elbow", Content: "Keep your elbow high.", Tags: []string{"serve"}})
+	if err != nil {
+		t.Fatalf("CreateSnippet: %v", err)
+	}
+	shared, err := q.CreateSnippet(ctx, db.CreateSnippetParams{OwnerID: "expert-1", GroupID: group.ID, Title: "Knees", Content: "Bend your knees.", Tags: []string{"footwork"}})
+	if err != nil {
+		t.Fatalf("CreateSnippet: %v", err)
+	}
+
+	list := func(p db.ListVisibleSnippetsParams) []db.ReviewSnippet {
+		t.Helper()
+		p.RowLimit = 10
+		rows, err := q.ListVisibleSnippets(ctx, p)
+		if err != nil {
+			t.Fatalf("ListVisibleSnippets: %v", err)
+		}
+		return rows
+	}
+	if rows := list(db.ListVisibleSnippetsParams{UserID: "expert-1"}); len(rows) != 2 {
+		t.Errorf("owner sees %d snippets, want 2", len(rows))
+	}
+	if rows := list(db.ListVisibleSnippetsParams{UserID: "expert-2"}); len(rows) != 1 || rows[0].ID != shared.ID {
+		t.Errorf("group member sees %+v, want only the shared snippet", rows)
+	}
+	if rows := list(db.ListVisibleSnippetsParams{UserID: "outsider"}); len(rows) != 0 {
+		t.Errorf("outsider sees %d snippets", len(rows))
+	}
+	if rows := list(db.ListVisibleSnippetsParams{UserID: "expert-1", Tag: pgtype.Text{String: "serve", Valid: true}}); len(rows) != 1 || rows[0].ID != private.ID {
+		t.Errorf("tag filter = %+v", rows)
+	}
+	if rows := list(db.ListVisibleSnippetsParams{UserID: "expert-1", Search: pgtype.Text{String: "KNEES", Valid: true}}); len(rows) != 1 {
+		t.Errorf("search = %+v", rows)
+	}
+	for _, wildcard := range []string{"%", "_"} {
+		if rows := list(db.ListVisibleSnippetsParams{UserID: "expert-1", Search: pgtype.Text{String: wildcard, Valid: true}}); len(rows) != 0 {
+			t.Errorf("search %q matched %d snippets as a wildcard", wildcard, len(rows))
+		}
+	}
+
+	if _, err := q.GetVisibleSnippet(ctx, db.GetVisibleSnippetParams{ID: private.ID, UserID: "expert-2"}); err == nil {
+		t.Error("private snippet visible to another expert")
+	}
+	if n, err := q.DeleteSnippet(ctx, db.DeleteSnippetParams{ID: shared.ID, OwnerID: "expert-2"}); err != nil || n != 0 {
+		t.Errorf("non-owner deleted a shared snippet: %d, %v", n, err)
+	}
+
+	if err := q.RecordSnippetUse(ctx, shared.ID); err != nil {
+		t.Fatalf("RecordSnippetUse: %v", err)
+	}
+	if rows := list(db.ListVisibleSnippetsParams{UserID: "expert-1"}); rows[0].ID != shared.ID || rows[0].UsageCount != 1 || !rows[0].LastUsedAt.Valid {
+		t.Errorf("most used snippet not first: %+v", rows)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	"github.com/OZIOisgood/zeta/internal/db"
 	"github.com/OZIOisgood/zeta/internal/email"
@@ -36,14 +37,16 @@ type groupUser struct {
 type Handler struct {
 	logger *slog.Logger
 	q      db.Querier
+	tx     audit.Runner
 	email  email.Sender
 	workos auth.UserManagement
 }
 
-func NewHandler(logger *slog.Logger, q db.Querier, emailService email.Sender, workos auth.UserManagement) *Handler {
+func NewHandler(logger *slog.Logger, q db.Querier, tx audit.Runner, emailService email.Sender, workos auth.UserManagement) *Handler {
 	return &Handler{
 		logger: logger,
 		q:      q,
+		tx:     tx,
 		email:  emailService,
 		workos: workos,
 	}
@@ -257,9 +260,20 @@ func (h *Handler) RemoveGroupUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.q.RemoveUserFromGroup(ctx, db.RemoveUserFromGroupParams{
-		UserID:  targetUserID,
-		GroupID: pgGroupID,
+	err = h.tx.InTx(ctx, func(tx audit.Tx) error {
+		if err := tx.RemoveUserFromGroup(ctx, db.RemoveUserFromGroupParams{
+			UserID:  targetUserID,
+			GroupID: pgGroupID,
+		}); err != nil {
+			return err
+		}
+		return tx.Record(ctx, audit.Event{
+			Action:       audit.ActionGroupMembershipRemoved,
+			ResourceType: audit.ResourceGroupMembership,
+			ResourceID:   targetUserID,
+			GroupID:      groupID.String(),
+			OldValues:    audit.GroupMembershipSnapshotOf(targetUserID, pgGroupID, ""),
+		})
 	})
 	if err != nil {
 		log.ErrorContext(ctx, "users_remove_from_group_failed",
@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/OZIOisgood/zeta/internal/audit"
+	"github.com/OZIOisgood/zeta/internal/audit/audittest"
 	"github.com/OZIOisgood/zeta/internal/auth"
 	authmocks "github.com/OZIOisgood/zeta/internal/auth/mocks"
 	"github.com/OZIOisgood/zeta/internal/db"
@@ -31,7 +33,7 @@ func TestListGroupUsersReturnsDisplayNameAndFullNameWithoutEmail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	var pgGroupID pgtype.UUID
@@ -113,7 +115,7 @@ func TestListGroupUsersMissingPreferencesIsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	var pgGroupID pgtype.UUID
@@ -154,7 +156,7 @@ func TestListGroupUsersMemberWithBlankNameDegradesInsteadOf500(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	var pgGroupID pgtype.UUID
@@ -219,7 +221,7 @@ func TestListGroupUsersFiltersStudents(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	var pgGroupID pgtype.UUID
@@ -290,7 +292,7 @@ func TestListGroupUsersStudentRoleDoesNotReceiveFullName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	var pgGroupID pgtype.UUID
@@ -356,7 +358,7 @@ func TestListGroupExpertsFiltersExpertsAndAdminsWithoutFullNamesOrEmails(t *test
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	groupID := "11111111-1111-1111-1111-111111111111"
 	var pgGroupID pgtype.UUID
@@ -457,7 +459,7 @@ func TestListGroupExpertsRequiresExpertListPermission(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	router := chi.NewRouter()
 	router.Get("/groups/{groupID}/experts", h.ListGroupExperts)
@@ -479,7 +481,7 @@ func TestListGroupUsersRequiresUserListPermission(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	q := dbmocks.NewMockQuerier(ctrl)
 	workos := authmocks.NewMockUserManagement(ctrl)
-	h := NewHandler(slog.Default(), q, nil, workos)
+	h := NewHandler(slog.Default(), q, audittest.NewRunner(q), nil, workos)
 
 	router := chi.NewRouter()
 	router.Get("/groups/{groupID}/users", h.ListGroupUsers)
@@ -496,3 +498,38 @@ func TestListGroupUsersRequiresUserListPermission(t *testing.T) {
 		t.Fatalf("got status %d, want %d", rec.Code, http.StatusForbidden)
 	}
 }
+
+func TestRemoveGroupUserRecordsMembershipRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	q := dbmocks.NewMockQuerier(ctrl)
+	workos := authmocks.NewMockUserManagement(ctrl)
+	runner := audittest.NewRunner(q)
+	h := NewHandler(slog.Default(), q, runner, nil, workos)
+
+	groupID := pgtype.UUID{Valid: true}
+	copy(groupID.Bytes[:], []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+	q.EXPECT().GetGroup(gomock.Any(), groupID).Return(db.Group{ID: groupID, OwnerID: "owner-1", Name: "Academy"}, nil)
+	q.EXPECT().RemoveUserFromGroup(gomock.Any(), db.RemoveUserFromGroupParams{UserID: "student-1", GroupID: groupID}).Return(nil)
+	// Background removal email: missing preferences short-circuit before WorkOS.
+	q.EXPECT().GetUserEmailPreferences(gomock.Any(), "student-1").Return(db.GetUserEmailPreferencesRow{}, pgx.ErrNoRows).AnyTimes()
+
+	router := chi.NewRouter()
+	router.Delete("/groups/{groupID}/users/{userID}", h.RemoveGroupUser)
+	req := httptest.NewRequest(http.MethodDelete, "/groups/01010101-0101-0101-0101-010101010101/users/student-1", nil)
+	req = req.WithContext(withTestUser(req.Context(), &auth.UserContext{
+		ID:          "expert-1",
+		Role:        permissions.RoleExpert,
+		Permissions: []string{permissions.GroupsUserListDelete},
+	}))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	events := runner.Events()
+	if len(events) != 1 || events[0].Action != audit.ActionGroupMembershipRemoved || events[0].ResourceID != "student-1" {
+		t.Fatalf("audit events = %+v, want one %s for student-1", events, audit.ActionGroupMembershipRemoved)
+	}
+}
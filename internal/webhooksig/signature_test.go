@@ -0,0 +1,23 @@
+package webhooksig
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignIsVerified(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":"evt_1"}`)
+	value := Sign("secret", now, body)
+
+	if err := Verify("Test-Signature", value, body, "secret", time.Minute, now.Add(30*time.Second)); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := Verify("Test-Signature", value, body, "secret", time.Minute, now.Add(2*time.Minute)); err == nil {
+		t.Fatal("Verify() accepted a signature older than the tolerance")
+	}
+	if err := Verify("Test-Signature", "", body, "secret", time.Minute, now); err == nil || !strings.Contains(err.Error(), "Test-Signature") {
+		t.Fatalf("Verify() error = %v, want it to name the header", err)
+	}
+}